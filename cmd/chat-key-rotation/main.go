@@ -0,0 +1,81 @@
+// Command chat-key-rotation re-wraps every conversation's chat key with the
+// active master key. Deploy the new key in CHAT_MASTER_KEYS alongside the old
+// ones, run this, then drop the old versions once it reports nothing left.
+//
+// It first moves chats stored before envelope encryption onto their
+// conversation's key. Those are decrypted with AES_16_SECRET_KEY, or with
+// -legacy-key for deployments that ran on the old built-in default.
+package main
+
+import (
+	"flag"
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/connection"
+	chatRepoPackage "kedai/backend/be-kedai/internal/domain/chat/repository"
+	"kedai/backend/be-kedai/internal/utils/encrypt"
+	"log"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 500, "number of keys re-wrapped per query")
+	legacyKey := flag.String("legacy-key", string(config.AES16SecretKey), "key chats stored before envelope encryption were encrypted with")
+	flag.Parse()
+
+	err := connection.ConnectDB()
+	if err != nil {
+		log.Fatal("couldn't connect to DB:", err.Error())
+	}
+
+	keyring, err := encrypt.NewKeyring(config.ChatMasterKeys, config.ChatMasterKeyVersion)
+	if err != nil {
+		log.Fatal("couldn't load chat master keys:", err)
+	}
+
+	chatKeyRepo := chatRepoPackage.NewChatKeyRepository(&chatRepoPackage.ChatKeyRConfig{
+		DB:      connection.GetDB(),
+		Keyring: keyring,
+	})
+
+	chatRepo := chatRepoPackage.NewChatRepository(&chatRepoPackage.ChatRConfig{
+		DB:          connection.GetDB(),
+		ChatKeyRepo: chatKeyRepo,
+	})
+
+	legacy, err := chatRepo.CountLegacy()
+	if err != nil {
+		log.Fatal("couldn't count legacy chats:", err)
+	}
+	if legacy > 0 && *legacyKey == "" {
+		log.Fatalf("%d chats were stored before envelope encryption; set AES_16_SECRET_KEY or -legacy-key to migrate them", legacy)
+	}
+
+	migrated := 0
+	for legacy > 0 {
+		count, err := chatRepo.MigrateLegacy([]byte(*legacyKey), *batchSize)
+		if err != nil {
+			log.Fatalf("migration stopped after %d chats: %v", migrated, err)
+		}
+		if count == 0 {
+			break
+		}
+
+		migrated += count
+		log.Printf("migrated %d legacy chats", migrated)
+	}
+
+	total := 0
+	for {
+		count, err := chatKeyRepo.Rewrap(*batchSize)
+		if err != nil {
+			log.Fatalf("rotation stopped after %d keys: %v", total, err)
+		}
+		if count == 0 {
+			break
+		}
+
+		total += count
+		log.Printf("re-wrapped %d chat keys", total)
+	}
+
+	log.Printf("done: %d chat keys now use master key version %d", total, keyring.ActiveVersion())
+}
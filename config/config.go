@@ -2,7 +2,7 @@ package config
 
 var (
 	AppName       = "Kedai"
-	SecretKey     = GetEnv("SECRET_KEY", "secret_key")
+	SecretKey     = GetEnv("SECRET_KEY", "")
 	HashKey       = GetEnv("HASH_KEY", "secret_key")
 	MerchantCode  = GetEnv("MERCHANT_CODE", "code")
 	PlatformFee   = GetEnv("PLATFORM_FEE", "0")
@@ -28,7 +28,53 @@ var (
 		API_BASE_URL:    GetEnv("MAILGUN_API_BASE_URL", ""),
 		SENDER:          GetEnv("MAILGUN_SENDER", ""),
 	}
+	Mail = MailConfig{
+		Driver:         GetEnv("MAIL_DRIVER", "mailgun"),
+		Sender:         GetEnv("MAILGUN_SENDER", "Support@kedai.com"),
+		Locale:         GetEnv("MAIL_LOCALE", "id"),
+		SMTPAddr:       GetEnv("SMTP_ADDR", "localhost:1025"),
+		SMTPUsername:   GetEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   GetEnv("SMTP_PASSWORD", ""),
+		FileDir:        GetEnv("MAIL_FILE_DIR", "tmp/mail"),
+		UnsubscribeURL: GetEnv("UNSUBSCRIBE_URL", "http://localhost:8080/v1/notifications/unsubscribe?token="),
+	}
+	Push = PushConfig{
+		Driver:             GetEnv("PUSH_DRIVER", "fake"),
+		VAPIDPublicKey:     GetEnv("VAPID_PUBLIC_KEY", ""),
+		VAPIDPrivateKey:    GetEnv("VAPID_PRIVATE_KEY", ""),
+		VAPIDSubject:       GetEnv("VAPID_SUBJECT", "mailto:Support@kedai.com"),
+		FCMCredentialsFile: GetEnv("FCM_CREDENTIALS_FILE", ""),
+	}
 	Origin                = GetArrayENV("ORIGIN", []string{"http://localhost:3000"})
 	DefaultProfilePicture = GetEnv("DEFAULT_PROFILE_PICTURE", "")
-	AES16SecretKey        = []byte(GetEnv("AES_16_SECRET_KEY", "wzbtr8xSIacDrhVO"))
+	// AES16SecretKey only decrypts chats stored before envelope encryption.
+	AES16SecretKey = []byte(GetEnv("AES_16_SECRET_KEY", ""))
+	// ChatMasterKeys are "version:base64key" entries that wrap per-conversation
+	// chat keys; ChatMasterKeyVersion picks the one used for new wraps.
+	ChatMasterKeys       = GetArrayENV("CHAT_MASTER_KEYS", []string{})
+	ChatMasterKeyVersion = GetEnv("CHAT_MASTER_KEY_VERSION", "")
+	// TwoFactorMasterKeys wrap users' TOTP secrets, in the same format as
+	// ChatMasterKeys.
+	TwoFactorMasterKeys       = GetArrayENV("TWO_FACTOR_MASTER_KEYS", []string{})
+	TwoFactorMasterKeyVersion = GetEnv("TWO_FACTOR_MASTER_KEY_VERSION", "")
+	// JWTSigningKeys are "kid:base64key" entries holding PEM encoded RSA or
+	// Ed25519 private keys; JWTActiveKeyID picks the one that signs new
+	// tokens. JWTVerificationKeys hold public keys of retired signing keys
+	// that must still verify tokens issued before a rotation. SecretKey only
+	// verifies HS256 tokens issued before asymmetric signing.
+	JWTSigningKeys      = GetArrayENV("JWT_SIGNING_KEYS", []string{})
+	JWTVerificationKeys = GetArrayENV("JWT_VERIFICATION_KEYS", []string{})
+	JWTActiveKeyID      = GetEnv("JWT_ACTIVE_KEY_ID", "")
+	BannedKeywords      = GetArrayENV("BANNED_KEYWORDS", []string{})
+	// Sign in with a provider is offered for every provider configured here.
+	// OIDCProviders are "name|issuer|clientId" entries for providers found
+	// through OIDC discovery.
+	GoogleClientID    = GetEnv("GOOGLE_CLIENT_ID", "")
+	AppleClientID     = GetEnv("APPLE_CLIENT_ID", "")
+	FacebookAppID     = GetEnv("FACEBOOK_APP_ID", "")
+	FacebookAppSecret = GetEnv("FACEBOOK_APP_SECRET", "")
+	OIDCProviders     = GetArrayENV("OIDC_PROVIDERS", []string{})
+	// TokenSigningKey signs the tokens of emailed links. Unlike HashKey it
+	// has no default, and the server refuses to start without it.
+	TokenSigningKey = GetEnv("TOKEN_SIGNING_KEY", "")
 )
@@ -0,0 +1,16 @@
+package config
+
+type MailConfig struct {
+	// Driver picks where emails go: mailgun in production, smtp for a local
+	// catcher such as MailHog, or file to write .eml files for inspection.
+	Driver       string
+	Sender       string
+	Locale       string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	FileDir      string
+	// UnsubscribeURL is the public unsubscribe endpoint; the signed token is
+	// appended to it.
+	UnsubscribeURL string
+}
@@ -0,0 +1,15 @@
+package config
+
+type PushConfig struct {
+	// Driver is live to deliver through Web Push and FCM, or fake to only log
+	// pushes during development.
+	Driver string
+	// VAPIDPublicKey and VAPIDPrivateKey are the base64url encoded P-256 key
+	// pair identifying this server to browser push services.
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	VAPIDSubject    string
+	// FCMCredentialsFile is a Firebase service account JSON file. Android
+	// and iOS devices register FCM tokens; FCM relays to APNs for iOS.
+	FCMCredentialsFile string
+}
@@ -0,0 +1,113 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const socketIOBroadcastChannel = "socket.io#broadcast"
+
+type SocketBroadcaster interface {
+	BroadcastToRoom(namespace string, room, event string, args ...interface{}) bool
+}
+
+// SocketIORedisAdapter fans room broadcasts out to every replica through Redis
+// pub/sub. Rooms only live in the memory of the instance a client is connected
+// to, so every instance re-broadcasts what it receives to its own rooms.
+type SocketIORedisAdapter struct {
+	local   SocketBroadcaster
+	rdc     *redis.Client
+	channel string
+}
+
+type socketBroadcastMessage struct {
+	Namespace string            `json:"namespace"`
+	Room      string            `json:"room"`
+	Event     string            `json:"event"`
+	Args      []json.RawMessage `json:"args"`
+}
+
+func NewSocketIORedisAdapter(local SocketBroadcaster, rdc *redis.Client) *SocketIORedisAdapter {
+	return &SocketIORedisAdapter{
+		local:   local,
+		rdc:     rdc,
+		channel: socketIOBroadcastChannel,
+	}
+}
+
+func (a *SocketIORedisAdapter) BroadcastToRoom(namespace string, room, event string, args ...interface{}) bool {
+	message := socketBroadcastMessage{
+		Namespace: namespace,
+		Room:      room,
+		Event:     event,
+	}
+	for _, arg := range args {
+		raw, err := json.Marshal(arg)
+		if err != nil {
+			log.Println("SocketIO redis adapter marshal error:", err)
+			return false
+		}
+		message.Args = append(message.Args, raw)
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		log.Println("SocketIO redis adapter marshal error:", err)
+		return false
+	}
+
+	err = a.rdc.Publish(context.Background(), a.channel, payload).Err()
+	if err != nil {
+		log.Println("SocketIO redis adapter publish error:", err)
+		return a.local.BroadcastToRoom(namespace, room, event, args...)
+	}
+
+	return true
+}
+
+// Start subscribes to the broadcast channel and returns once the subscription
+// is confirmed, relaying messages to the local server until ctx is cancelled.
+func (a *SocketIORedisAdapter) Start(ctx context.Context) error {
+	sub := a.rdc.Subscribe(ctx, a.channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return err
+	}
+
+	go func() {
+		defer sub.Close()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				a.relay(msg.Payload)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (a *SocketIORedisAdapter) relay(payload string) {
+	var message socketBroadcastMessage
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		log.Println("SocketIO redis adapter unmarshal error:", err)
+		return
+	}
+
+	args := make([]interface{}, len(message.Args))
+	for i, arg := range message.Args {
+		args[i] = arg
+	}
+
+	a.local.BroadcastToRoom(message.Namespace, message.Room, message.Event, args...)
+}
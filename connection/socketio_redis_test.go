@@ -0,0 +1,115 @@
+package connection_test
+
+import (
+	"context"
+	"kedai/backend/be-kedai/connection"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	socketio "github.com/googollee/go-socket.io"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type testReplica struct {
+	adapter *connection.SocketIORedisAdapter
+	url     string
+}
+
+func newTestReplica(t *testing.T, ctx context.Context, redisAddr string) *testReplica {
+	server := socketio.NewServer(nil)
+	server.OnConnect("/", func(s socketio.Conn) error {
+		return nil
+	})
+	server.OnEvent("/", "join-room", func(s socketio.Conn, room string) string {
+		s.Join(room)
+		return room
+	})
+	go server.Serve()
+	t.Cleanup(func() { server.Close() })
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	rdc := redis.NewClient(&redis.Options{Addr: redisAddr})
+	t.Cleanup(func() { rdc.Close() })
+
+	adapter := connection.NewSocketIORedisAdapter(server, rdc)
+	if err := adapter.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testReplica{
+		adapter: adapter,
+		url:     "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/socket.io/?EIO=3&transport=websocket",
+	}
+}
+
+func dialTestReplica(t *testing.T, replica *testReplica) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(replica.url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// readUntil skips engine.io handshake and ping frames until a packet with the
+// given prefix arrives.
+func readUntil(t *testing.T, conn *websocket.Conn, prefix string) string {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("waiting for %q: %v", prefix, err)
+		}
+		packet := strings.TrimSpace(string(data))
+		if strings.HasPrefix(packet, prefix) {
+			return packet
+		}
+	}
+}
+
+func TestSocketIORedisAdapterBroadcastsAcrossReplicas(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replicaA := newTestReplica(t, ctx, redisServer.Addr())
+	replicaB := newTestReplica(t, ctx, redisServer.Addr())
+
+	clientA := dialTestReplica(t, replicaA)
+	assert.NoError(t, clientA.WriteMessage(websocket.TextMessage, []byte(`421["join-room","chat:1:3"]`)))
+	assert.Equal(t, `431["chat:1:3"]`, readUntil(t, clientA, "431"))
+
+	clientB := dialTestReplica(t, replicaB)
+	assert.NoError(t, clientB.WriteMessage(websocket.TextMessage, []byte(`421["join-room","chat:1:3"]`)))
+	assert.Equal(t, `431["chat:1:3"]`, readUntil(t, clientB, "431"))
+
+	assert.True(t, replicaB.adapter.BroadcastToRoom("/", "chat:1:3", "receive-message", map[string]string{"message": "hai"}))
+
+	assert.Equal(t, `42["receive-message",{"message":"hai"}]`, readUntil(t, clientA, "42"))
+	assert.Equal(t, `42["receive-message",{"message":"hai"}]`, readUntil(t, clientB, "42"))
+}
+
+func TestSocketIORedisAdapterFallsBackToLocalBroadcast(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replica := newTestReplica(t, ctx, redisServer.Addr())
+
+	client := dialTestReplica(t, replica)
+	assert.NoError(t, client.WriteMessage(websocket.TextMessage, []byte(`421["join-room","chat:1:3"]`)))
+	assert.Equal(t, `431["chat:1:3"]`, readUntil(t, client, "431"))
+
+	redisServer.Close()
+
+	assert.True(t, replica.adapter.BroadcastToRoom("/", "chat:1:3", "receive-message", "hai"))
+	assert.Equal(t, `42["receive-message","hai"]`, readUntil(t, client, "42"))
+}
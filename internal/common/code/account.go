@@ -0,0 +1,10 @@
+package code
+
+const (
+	DATA_EXPORT_NOT_FOUND      = "DATA_EXPORT_NOT_FOUND"
+	DATA_EXPORT_NOT_READY      = "DATA_EXPORT_NOT_READY"
+	ACCOUNT_HAS_OPEN_ORDERS    = "ACCOUNT_HAS_OPEN_ORDERS"
+	ACCOUNT_HAS_OPEN_REFUNDS   = "ACCOUNT_HAS_OPEN_REFUNDS"
+	ACCOUNT_HAS_WALLET_BALANCE = "ACCOUNT_HAS_WALLET_BALANCE"
+	ACCOUNT_HAS_SHOP           = "ACCOUNT_HAS_SHOP"
+)
@@ -0,0 +1,9 @@
+package code
+
+const (
+	ADMIN_NOT_FOUND              = "ADMIN_NOT_FOUND"
+	ADMIN_ALREADY_EXISTS         = "ADMIN_ALREADY_EXISTS"
+	ADMIN_ROLE_NOT_FOUND         = "ADMIN_ROLE_NOT_FOUND"
+	INVALID_ADMIN_INVITATION     = "INVALID_ADMIN_INVITATION"
+	CANNOT_MODIFY_OWN_ADMIN_ROLE = "CANNOT_MODIFY_OWN_ADMIN_ROLE"
+)
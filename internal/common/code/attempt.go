@@ -0,0 +1,3 @@
+package code
+
+const TOO_MANY_ATTEMPTS = "TOO_MANY_ATTEMPTS"
@@ -0,0 +1,5 @@
+package code
+
+const (
+	DUPLICATE_QUICK_REPLY = "DUPLICATE_QUICK_REPLY"
+)
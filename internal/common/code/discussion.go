@@ -0,0 +1,9 @@
+package code
+
+var (
+	DISCUSSION_NOT_FOUND        = "DISCUSSION_NOT_FOUND"
+	DISCUSSION_ALREADY_VOTED    = "DISCUSSION_ALREADY_VOTED"
+	DISCUSSION_NOT_VOTED        = "DISCUSSION_NOT_VOTED"
+	DISCUSSION_ALREADY_REPORTED = "DISCUSSION_ALREADY_REPORTED"
+	INVALID_OFFICIAL_ANSWER     = "INVALID_OFFICIAL_ANSWER"
+)
@@ -0,0 +1,8 @@
+package code
+
+const (
+	INVALID_EMAIL_TOKEN    = "INVALID_EMAIL_TOKEN"
+	EMAIL_TOKEN_EXPIRED    = "EMAIL_TOKEN_EXPIRED"
+	EMAIL_ALREADY_VERIFIED = "EMAIL_ALREADY_VERIFIED"
+	EMAIL_NOT_VERIFIED     = "EMAIL_NOT_VERIFIED"
+)
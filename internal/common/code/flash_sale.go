@@ -0,0 +1,12 @@
+package code
+
+var (
+	FLASH_SALE_CAMPAIGN_NOT_FOUND    = "FLASH_SALE_CAMPAIGN_NOT_FOUND"
+	FLASH_SALE_SLOT_NOT_FOUND        = "FLASH_SALE_SLOT_NOT_FOUND"
+	FLASH_SALE_SLOT_CLOSED           = "FLASH_SALE_SLOT_CLOSED"
+	INVALID_FLASH_SALE_SLOT          = "INVALID_FLASH_SALE_SLOT"
+	INVALID_FLASH_SALE_DISCOUNT      = "INVALID_FLASH_SALE_DISCOUNT"
+	FLASH_SALE_QUOTA_EXCEEDS_STOCK   = "FLASH_SALE_QUOTA_EXCEEDS_STOCK"
+	FLASH_SALE_SKU_ALREADY_SUBMITTED = "FLASH_SALE_SKU_ALREADY_SUBMITTED"
+	FLASH_SALE_SUBMISSION_NOT_FOUND  = "FLASH_SALE_SUBMISSION_NOT_FOUND"
+)
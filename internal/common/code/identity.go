@@ -0,0 +1,9 @@
+package code
+
+const (
+	UNKNOWN_IDENTITY_PROVIDER = "UNKNOWN_IDENTITY_PROVIDER"
+	IDENTITY_EMAIL_MISSING    = "IDENTITY_EMAIL_MISSING"
+	IDENTITY_LINK_PENDING     = "IDENTITY_LINK_PENDING"
+	IDENTITY_ALREADY_LINKED   = "IDENTITY_ALREADY_LINKED"
+	IDENTITY_NOT_FOUND        = "IDENTITY_NOT_FOUND"
+)
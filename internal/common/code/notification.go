@@ -0,0 +1,11 @@
+package code
+
+const (
+	INVALID_UNSUBSCRIBE_TOKEN      = "INVALID_UNSUBSCRIBE_TOKEN"
+	SECURITY_NOTIFICATION_REQUIRED = "SECURITY_NOTIFICATION_REQUIRED"
+)
+
+const (
+	PUSH_DEVICE_NOT_FOUND     = "PUSH_DEVICE_NOT_FOUND"
+	INVALID_PUSH_SUBSCRIPTION = "INVALID_PUSH_SUBSCRIPTION"
+)
@@ -6,4 +6,5 @@ var (
 	INVALID_PRODUCT_NAME    = "INVALID_PRODUCT_NAME"
 	DUPLICATE_VARIANT_GROUP = "DUPLICATE_VARIANT_GROUP"
 	DUPLICATE_VARIANT       = "DUPLICATE_VARIANT"
+	PRODUCT_BANNED          = "PRODUCT_BANNED"
 )
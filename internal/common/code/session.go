@@ -0,0 +1,6 @@
+package code
+
+const (
+	SESSION_NOT_FOUND    = "SESSION_NOT_FOUND"
+	REFRESH_TOKEN_REUSED = "REFRESH_TOKEN_REUSED"
+)
@@ -6,3 +6,11 @@ var (
 	INVALID_SHOP_NAME   = "INVALID_SHOP_NAME"
 	HAVE_SHOP           = "HAVE_SHOP"
 )
+
+const (
+	SHOP_MEMBER_NOT_FOUND          = "SHOP_MEMBER_NOT_FOUND"
+	SHOP_MEMBER_ALREADY_EXISTS     = "SHOP_MEMBER_ALREADY_EXISTS"
+	INVALID_SHOP_INVITATION        = "INVALID_SHOP_INVITATION"
+	CANNOT_MODIFY_OWN_SHOP_MEMBER  = "CANNOT_MODIFY_OWN_SHOP_MEMBER"
+	SHOP_INVITATION_EMAIL_MISMATCH = "SHOP_INVITATION_EMAIL_MISMATCH"
+)
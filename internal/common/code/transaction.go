@@ -4,4 +4,10 @@ var (
 	TRANSACTION_REVIEW_ALREADY_EXIST = "TRANSACTION_REVIEW_ALREADY_EXIST"
 	TRANSACTION_NOT_FOUND            = "TRANSACTION_NOT_FOUND"
 	TRANSACTION_REVIEW_NOT_FOUND     = "TRANSACTION_REVIEW_NOT_FOUND"
+	REVIEW_EDIT_WINDOW_CLOSED        = "REVIEW_EDIT_WINDOW_CLOSED"
+	REVIEW_ALREADY_VOTED             = "REVIEW_ALREADY_VOTED"
+	REVIEW_NOT_VOTED                 = "REVIEW_NOT_VOTED"
+	REVIEW_ALREADY_REPORTED          = "REVIEW_ALREADY_REPORTED"
+	CANNOT_REACT_TO_OWN_REVIEW       = "CANNOT_REACT_TO_OWN_REVIEW"
+	INVALID_REVIEW_MEDIA             = "INVALID_REVIEW_MEDIA"
 )
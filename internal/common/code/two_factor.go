@@ -0,0 +1,10 @@
+package code
+
+const (
+	TWO_FACTOR_NOT_ENROLLED      = "TWO_FACTOR_NOT_ENROLLED"
+	TWO_FACTOR_ALREADY_ENABLED   = "TWO_FACTOR_ALREADY_ENABLED"
+	TWO_FACTOR_NOT_ENABLED       = "TWO_FACTOR_NOT_ENABLED"
+	INVALID_TWO_FACTOR_CODE      = "INVALID_TWO_FACTOR_CODE"
+	INVALID_TWO_FACTOR_CHALLENGE = "INVALID_TWO_FACTOR_CHALLENGE"
+	TWO_FACTOR_REQUIRED          = "TWO_FACTOR_REQUIRED"
+)
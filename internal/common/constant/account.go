@@ -0,0 +1,21 @@
+package constant
+
+import "time"
+
+const (
+	DataExportStatusPending = "pending"
+	DataExportStatusReady   = "ready"
+	DataExportStatusFailed  = "failed"
+
+	DataExportBatchSize = 5
+	// DataExportClaimLease hides claimed exports from other workers long
+	// enough to build one batch.
+	DataExportClaimLease = 10 * time.Minute
+	// DataExportAge is how long a built archive can be downloaded before it
+	// is purged.
+	DataExportAge = 7 * 24 * time.Hour
+
+	// DeletedUserEmailDomain is where the address of a deleted account points
+	// to, so it can never receive mail or be signed in to again.
+	DeletedUserEmailDomain = "deleted.kedai.invalid"
+)
@@ -1,5 +1,40 @@
 package constant
 
-var (
-	AdminUserId = 0
+import "time"
+
+// AdminTokenScope marks access tokens issued to admins so they can't be used
+// on buyer and seller routes, and buyer tokens can't be used under /admins.
+const AdminTokenScope = "admin"
+
+const (
+	AdminStatusInvited = "invited"
+	AdminStatusActive  = "active"
+	AdminStatusRevoked = "revoked"
+
+	// AdminInvitationAge is how long an invitation link can be accepted.
+	AdminInvitationAge = 72 * time.Hour
+)
+
+const (
+	AdminRoleSuperAdmin = "super_admin"
+	AdminRoleFinance    = "finance"
+	AdminRoleModerator  = "moderator"
+	AdminRoleSupport    = "support"
+)
+
+// Admin permissions guard the route groups under /admins. Which role holds
+// which permission lives in the admin_role_permissions table.
+const (
+	AdminPermissionManageCategories  = "categories.manage"
+	AdminPermissionModerateProducts  = "products.moderate"
+	AdminPermissionManageOrders      = "orders.manage"
+	AdminPermissionManageRefunds     = "refunds.manage"
+	AdminPermissionManageMarketplace = "marketplace.manage"
+	AdminPermissionManageAdmins      = "admins.manage"
+	AdminPermissionReadAuditLogs     = "audit_logs.read"
+)
+
+const (
+	DefaultAdminAuditLogLimit = 20
+	MaxAdminAuditLogLimit     = 100
 )
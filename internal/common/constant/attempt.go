@@ -0,0 +1,29 @@
+package constant
+
+import "time"
+
+// Scopes keep the failure counters of each credential check apart.
+const (
+	AttemptScopeUserLogin      = "user_login"
+	AttemptScopeAdminLogin     = "admin_login"
+	AttemptScopePasswordChange = "password_change"
+	AttemptScopePasswordReset  = "password_reset"
+	AttemptScopeTwoFactor      = "two_factor"
+	AttemptScopeAccountDelete  = "account_delete"
+	AttemptScopePinChange      = "pin_change"
+	AttemptScopePinReset       = "pin_reset"
+)
+
+const (
+	// AttemptFreeFailures failures are allowed back to back; after that each
+	// failure makes the next attempt wait AttemptBaseDelay, doubling up to
+	// AttemptMaxDelay.
+	AttemptFreeFailures = 3
+	AttemptBaseDelay    = time.Second
+	AttemptMaxDelay     = 30 * time.Second
+	// AttemptMaxFailures failures within AttemptWindow lock the key out for
+	// AttemptLockout.
+	AttemptMaxFailures = 10
+	AttemptWindow      = 15 * time.Minute
+	AttemptLockout     = 15 * time.Minute
+)
@@ -0,0 +1,27 @@
+package constant
+
+import "time"
+
+const (
+	ChatPresenceTTL = time.Minute
+)
+
+const (
+	ChatTypeText      = "text"
+	ChatTypeImage     = "image"
+	ChatTypeProduct   = "product"
+	ChatTypeInvoice   = "invoice"
+	ChatTypeComplaint = "complaint"
+	ChatTypeVoucher   = "voucher"
+)
+
+const (
+	MaxChatQuickReplies = 20
+
+	// ChatResponseWindow is how soon a seller must reply to a buyer for the
+	// message to count as answered in the shop's response rate.
+	ChatResponseWindow = 24 * time.Hour
+	// ChatPerformancePeriodDays is how far back response rate and average
+	// response time look.
+	ChatPerformancePeriodDays = 30
+)
@@ -0,0 +1,7 @@
+package constant
+
+const (
+	DiscussionReportHideThreshold = 3
+	DiscussionAnswerSLAHours      = 24
+	DiscussionSLAWindowDays       = 30
+)
@@ -0,0 +1,19 @@
+package constant
+
+import "time"
+
+const (
+	EmailTokenPurposeVerify = "verify"
+	EmailTokenPurposeChange = "change"
+	EmailTokenPurposeRevert = "revert"
+	EmailTokenPurposeLink   = "link"
+
+	// EmailVerificationAge is how long the link sent to confirm an address,
+	// on sign up, on an email change or to link a provider account, stays
+	// valid.
+	EmailVerificationAge = 24 * time.Hour
+
+	// EmailRevertAge is how long the previous address can undo an email
+	// change.
+	EmailRevertAge = 7 * 24 * time.Hour
+)
@@ -0,0 +1,7 @@
+package constant
+
+const (
+	IdentityProviderGoogle   = "google"
+	IdentityProviderApple    = "apple"
+	IdentityProviderFacebook = "facebook"
+)
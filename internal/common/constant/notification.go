@@ -0,0 +1,68 @@
+package constant
+
+import "time"
+
+const (
+	NotificationTypeOrderStatus     = "order_status"
+	NotificationTypeRefundDecision  = "refund_decision"
+	NotificationTypeDiscussionReply = "discussion_reply"
+	NotificationTypeChatMessage     = "chat_message"
+	NotificationTypeVoucherExpiry   = "voucher_expiry"
+)
+
+const (
+	// VoucherExpiryNoticeHours is how long before a claimed voucher expires
+	// its owner is reminded to use it.
+	VoucherExpiryNoticeHours = 24
+)
+
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+
+	EmailOutboxBatchSize = 50
+	// EmailMaxAttempts caps retries; with EmailRetryBaseDelay doubling each
+	// time the last attempt happens about four hours after the first.
+	EmailMaxAttempts    = 8
+	EmailRetryBaseDelay = time.Minute
+	// EmailClaimLease hides claimed emails from other dispatchers long enough
+	// for one batch to be sent.
+	EmailClaimLease = 5 * time.Minute
+)
+
+const (
+	NotificationCategoryOrders     = "orders"
+	NotificationCategoryPromotions = "promotions"
+	NotificationCategoryChat       = "chat"
+	// NotificationCategorySecurity covers password, PIN and login messages,
+	// which are always delivered whatever the user's preferences.
+	NotificationCategorySecurity = "security"
+
+	NotificationChannelEmail = "email"
+	NotificationChannelInApp = "in_app"
+	NotificationChannelPush  = "push"
+)
+
+var (
+	NotificationCategories = []string{
+		NotificationCategoryOrders,
+		NotificationCategoryPromotions,
+		NotificationCategoryChat,
+		NotificationCategorySecurity,
+	}
+	NotificationChannels = []string{
+		NotificationChannelEmail,
+		NotificationChannelInApp,
+		NotificationChannelPush,
+	}
+	// NotificationTypeCategories decides which preference an in-app
+	// notification type is governed by.
+	NotificationTypeCategories = map[string]string{
+		NotificationTypeOrderStatus:     NotificationCategoryOrders,
+		NotificationTypeRefundDecision:  NotificationCategoryOrders,
+		NotificationTypeDiscussionReply: NotificationCategoryChat,
+		NotificationTypeChatMessage:     NotificationCategoryChat,
+		NotificationTypeVoucherExpiry:   NotificationCategoryPromotions,
+	}
+)
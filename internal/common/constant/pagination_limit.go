@@ -40,4 +40,18 @@ const (
 	MaxDiscussionLimit             = 10
 	DefaultRecommendedProductLimit = 18
 	MaxRecommendedProductLimit     = 100
+
+	DefaultStockHistoryLimit = 10
+	MaxStockHistoryLimit     = 50
+
+	DefaultModerationQueueLimit = 20
+	MaxModerationQueueLimit     = 100
+
+	DefaultFlashSaleSubmissionLimit = 10
+	MaxFlashSaleSubmissionLimit     = 50
+
+	DefaultFlashSaleSlotLimit = 5
+
+	DefaultNotificationLimit = 10
+	MaxNotificationLimit     = 50
 )
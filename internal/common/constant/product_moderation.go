@@ -0,0 +1,14 @@
+package constant
+
+const (
+	ModerationStatusPendingReview = "pending_review"
+	ModerationStatusApproved      = "approved"
+	ModerationStatusRejected      = "rejected"
+	ModerationStatusBanned        = "banned"
+
+	ModerationFlagBannedKeyword = "banned_keyword"
+	ModerationFlagHazardous     = "hazardous"
+	ModerationFlagPriceOutlier  = "price_outlier"
+
+	PriceOutlierRatio = 10
+)
@@ -9,4 +9,5 @@ const (
 	SortByStockLow    = "stock_low"
 	SortByPriceLow    = "price_low"
 	SortByPriceHigh   = "price_high"
+	SortByMostHelpful = "most_helpful"
 )
@@ -0,0 +1,6 @@
+package constant
+
+const (
+	ReviewEditWindowDays      = 30
+	ReviewReportHideThreshold = 3
+)
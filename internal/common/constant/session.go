@@ -0,0 +1,12 @@
+package constant
+
+import "time"
+
+const (
+	// DeviceIDHeader lets clients name the device a session belongs to;
+	// without it the user agent and network identify the device.
+	DeviceIDHeader = "X-Device-Id"
+	// SessionTouchInterval limits how often a session's last-seen time is
+	// written while it is in use.
+	SessionTouchInterval = time.Minute
+)
@@ -0,0 +1,66 @@
+package constant
+
+import "time"
+
+// Shop roles. The owner is whoever registered the shop and has no
+// shop_members row; everyone else joins by invitation.
+const (
+	ShopRoleOwner     = "owner"
+	ShopRoleAdmin     = "admin"
+	ShopRoleOrderOps  = "order_ops"
+	ShopRoleChatAgent = "chat_agent"
+	ShopRoleFinance   = "finance"
+)
+
+const (
+	ShopMemberStatusInvited = "invited"
+	ShopMemberStatusActive  = "active"
+	ShopMemberStatusRevoked = "revoked"
+
+	// ShopInvitationAge is how long a staff invitation can be accepted.
+	ShopInvitationAge = 72 * time.Hour
+)
+
+// Shop permissions guard the route groups under /sellers.
+const (
+	ShopPermissionViewInsights     = "insights.view"
+	ShopPermissionManageShop       = "shop.manage"
+	ShopPermissionManageProducts   = "products.manage"
+	ShopPermissionManageOrders     = "orders.manage"
+	ShopPermissionManageChats      = "chats.manage"
+	ShopPermissionManageFinance    = "finance.manage"
+	ShopPermissionManagePromotions = "promotions.manage"
+	ShopPermissionManageStaff      = "staff.manage"
+)
+
+var ShopRolePermissions = map[string][]string{
+	ShopRoleOwner: {
+		ShopPermissionViewInsights,
+		ShopPermissionManageShop,
+		ShopPermissionManageProducts,
+		ShopPermissionManageOrders,
+		ShopPermissionManageChats,
+		ShopPermissionManageFinance,
+		ShopPermissionManagePromotions,
+		ShopPermissionManageStaff,
+	},
+	ShopRoleAdmin: {
+		ShopPermissionViewInsights,
+		ShopPermissionManageShop,
+		ShopPermissionManageProducts,
+		ShopPermissionManageOrders,
+		ShopPermissionManageChats,
+		ShopPermissionManagePromotions,
+		ShopPermissionManageStaff,
+	},
+	ShopRoleOrderOps: {
+		ShopPermissionManageOrders,
+	},
+	ShopRoleChatAgent: {
+		ShopPermissionManageChats,
+	},
+	ShopRoleFinance: {
+		ShopPermissionViewInsights,
+		ShopPermissionManageFinance,
+	},
+}
@@ -0,0 +1,17 @@
+package constant
+
+import "time"
+
+const (
+	TwoFactorIssuer = "Kedai"
+
+	// TwoFactorChallengeAge is how long a sign-in waits for the second factor
+	// after the password or Google credential was accepted.
+	TwoFactorChallengeAge = 5 * time.Minute
+
+	// TwoFactorFreshAge is how recently a token must have passed 2FA to be
+	// accepted on sensitive actions.
+	TwoFactorFreshAge = 10 * time.Minute
+
+	TwoFactorRecoveryCodeCount = 10
+)
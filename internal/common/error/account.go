@@ -0,0 +1,12 @@
+package error
+
+import "errors"
+
+var (
+	ErrDataExportNotFound      = errors.New("data export not found")
+	ErrDataExportNotReady      = errors.New("data export is still being prepared")
+	ErrAccountHasOpenOrders    = errors.New("finish or cancel your open orders before deleting your account")
+	ErrAccountHasOpenRefunds   = errors.New("wait for your open refunds to settle before deleting your account")
+	ErrAccountHasWalletBalance = errors.New("withdraw your wallet balance before deleting your account")
+	ErrAccountHasShop          = errors.New("accounts that own a shop cannot be deleted")
+)
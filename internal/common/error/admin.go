@@ -0,0 +1,12 @@
+package error
+
+import "errors"
+
+var (
+	ErrAdminNotFound            = errors.New("admin not found")
+	ErrAdminAlreadyExists       = errors.New("an active admin already uses this email")
+	ErrAdminRoleNotFound        = errors.New("admin role not found")
+	ErrInvalidAdminInvitation   = errors.New("invitation is invalid or has expired")
+	ErrAdminPermissionDenied    = errors.New("you don't have permission to do this")
+	ErrCannotModifyOwnAdminRole = errors.New("you can't change or revoke your own access")
+)
@@ -0,0 +1,5 @@
+package error
+
+import "errors"
+
+var ErrTooManyAttempts = errors.New("too many failed attempts, try again later")
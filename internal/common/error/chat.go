@@ -3,5 +3,18 @@ package error
 import "errors"
 
 var (
-	ErrSelfMessaging = errors.New("could not send messages to self")
+	ErrSelfMessaging      = errors.New("could not send messages to self")
+	ErrInvalidChatRoom    = errors.New("chat room must target either a shop or a user")
+	ErrSocketUnauthorized = errors.New("socket connection is not authenticated")
+	ErrInvalidChatType    = errors.New("chat type must be one of text, image, product, invoice, complaint or voucher")
+	ErrInvalidChatImage   = errors.New("chat image must be a jpg, jpeg, png, webp or gif url")
+	ErrChatVoucherSender  = errors.New("only sellers can share vouchers")
+
+	ErrInvalidLegacyChatKey = errors.New("legacy chat key does not decrypt stored chats")
+
+	ErrChatAutoReplyMessageRequired = errors.New("message is required when auto reply or away mode is enabled")
+	ErrInvalidAwaySchedule          = errors.New("away schedule must end after it starts")
+	ErrQuickReplyNotFound           = errors.New("quick reply not found")
+	ErrQuickReplyAlreadyExist       = errors.New("quick reply shortcut already exists")
+	ErrQuickReplyLimitReached       = errors.New("quick reply limit reached")
 )
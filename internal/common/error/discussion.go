@@ -5,5 +5,9 @@ import (
 )
 
 var (
-	ErrDiscussionNotFound = errors.New("discussion not found")
+	ErrDiscussionNotFound        = errors.New("discussion not found")
+	ErrDiscussionAlreadyVoted    = errors.New("discussion already voted as helpful")
+	ErrDiscussionNotVoted        = errors.New("discussion has not been voted as helpful")
+	ErrDiscussionAlreadyReported = errors.New("discussion already reported")
+	ErrInvalidOfficialAnswer     = errors.New("only your shop's replies can be marked as official answer")
 )
@@ -0,0 +1,10 @@
+package error
+
+import "errors"
+
+var (
+	ErrInvalidEmailToken    = errors.New("invalid email link")
+	ErrEmailTokenExpired    = errors.New("email link has expired")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrEmailNotVerified     = errors.New("verify your email address to continue")
+)
@@ -0,0 +1,14 @@
+package error
+
+import "errors"
+
+var (
+	ErrFlashSaleCampaignNotFound    = errors.New("flash sale campaign not found")
+	ErrFlashSaleSlotNotFound        = errors.New("flash sale slot not found")
+	ErrFlashSaleSlotClosed          = errors.New("flash sale slot is no longer open for submission")
+	ErrInvalidFlashSaleSlot         = errors.New("invalid flash sale slot period")
+	ErrInvalidFlashSaleDiscount     = errors.New("invalid flash sale discount")
+	ErrFlashSaleQuotaExceedsStock   = errors.New("flash sale quota exceeds sku stock")
+	ErrFlashSaleSkuAlreadySubmitted = errors.New("sku already submitted to flash sale slot")
+	ErrFlashSaleSubmissionNotFound  = errors.New("flash sale submission not found")
+)
@@ -0,0 +1,11 @@
+package error
+
+import "errors"
+
+var (
+	ErrUnknownIdentityProvider = errors.New("unknown identity provider")
+	ErrIdentityEmailMissing    = errors.New("the identity provider did not share an email address")
+	ErrIdentityLinkPending     = errors.New("an account with this email already exists, check your email to link it")
+	ErrIdentityAlreadyLinked   = errors.New("this provider account is already linked")
+	ErrIdentityNotFound        = errors.New("linked account not found")
+)
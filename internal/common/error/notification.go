@@ -0,0 +1,15 @@
+package error
+
+import "errors"
+
+var (
+	ErrNotificationNotFound         = errors.New("notification not found")
+	ErrUnknownNotificationType      = errors.New("unknown notification type")
+	ErrInvalidUnsubscribeToken      = errors.New("invalid unsubscribe link")
+	ErrSecurityNotificationRequired = errors.New("security notifications cannot be turned off")
+)
+
+var (
+	ErrPushDeviceNotFound      = errors.New("push device not found")
+	ErrInvalidPushSubscription = errors.New("web devices need an endpoint and keys, mobile devices need a token")
+)
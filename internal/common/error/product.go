@@ -9,4 +9,5 @@ var (
 	ErrInvalidProductNamePattern = errors.New("invalid product name pattern")
 	ErrDuplicateVariantGroup     = errors.New("duplicate variant group")
 	ErrDuplicateVariant          = errors.New("duplicate variant")
+	ErrProductBanned             = errors.New("product has been banned")
 )
@@ -9,3 +9,12 @@ var (
 	ErrShopRegistered  = errors.New("shop already registered")
 	ErrUserHasShop     = errors.New("user already has shop")
 )
+
+var (
+	ErrShopMemberNotFound          = errors.New("shop member not found")
+	ErrShopMemberAlreadyExists     = errors.New("this user already works for the shop")
+	ErrInvalidShopInvitation       = errors.New("invitation is invalid or has expired")
+	ErrShopPermissionDenied        = errors.New("your role in this shop doesn't allow this")
+	ErrCannotModifyOwnShopMember   = errors.New("you can't change or revoke your own membership")
+	ErrShopInvitationEmailMismatch = errors.New("this invitation was sent to a different email")
+)
@@ -6,4 +6,10 @@ var (
 	ErrInvalidTransactionID          = errors.New("invalid transaction id")
 	ErrTransactionNotFound           = errors.New("transaction not found")
 	ErrTransactionReviewAlreadyExist = errors.New("transaction review already exist")
+	ErrReviewEditWindowClosed        = errors.New("review can no longer be edited")
+	ErrReviewAlreadyVoted            = errors.New("review already voted as helpful")
+	ErrReviewNotVoted                = errors.New("review has not been voted as helpful")
+	ErrReviewAlreadyReported         = errors.New("review already reported")
+	ErrCannotReactToOwnReview        = errors.New("you can't vote on or report your own review")
+	ErrInvalidReviewMedia            = errors.New("review media must be a jpg, jpeg, png, webp or mp4 url")
 )
@@ -0,0 +1,12 @@
+package error
+
+import "errors"
+
+var (
+	ErrTwoFactorNotEnrolled       = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled        = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode       = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge  = errors.New("sign in again to continue")
+	ErrTwoFactorVerificationStale = errors.New("verify your two-factor code again to continue")
+)
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"kedai/backend/be-kedai/config"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type AdminCache interface {
+	StoreToken(adminId int, accessToken string) error
+	FindToken(adminId int, accessToken string) error
+	DeleteAllByID(adminId int) error
+}
+
+type adminCacheImpl struct {
+	rdc *redis.Client
+}
+
+type AdminCConfig struct {
+	RDC *redis.Client
+}
+
+func NewAdminCache(cfg *AdminCConfig) AdminCache {
+	return &adminCacheImpl{
+		rdc: cfg.RDC,
+	}
+}
+
+func (r *adminCacheImpl) StoreToken(adminId int, accessToken string) error {
+	key := fmt.Sprintf("admin_%d:%s", adminId, accessToken)
+	accessTime := jwttoken.ParseTokenAgeFromENV(config.GetEnv("ACCESS_TOKEN_AGE", ""), "access")
+
+	return r.rdc.Set(context.Background(), key, 0, accessTime).Err()
+}
+
+func (r *adminCacheImpl) FindToken(adminId int, accessToken string) error {
+	key := fmt.Sprintf("admin_%d:%s", adminId, accessToken)
+
+	return r.rdc.Get(context.Background(), key).Err()
+}
+
+// DeleteAllByID ends every session of the admin, so a revoked admin is
+// locked out immediately rather than when their token expires.
+func (r *adminCacheImpl) DeleteAllByID(adminId int) error {
+	ctx := context.Background()
+
+	iter := r.rdc.Scan(ctx, 0, fmt.Sprintf("admin_%d:*", adminId), 0).Iterator()
+	for iter.Next(ctx) {
+		if err := r.rdc.Unlink(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+)
+
+type AdminLoginRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	Password  string `json:"password" binding:"required"`
+	IPAddress string `json:"-"`
+}
+
+type AdminToken struct {
+	AccessToken string `json:"accessToken"`
+}
+
+type InviteAdminRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	Name   string `json:"name" binding:"required,max=64"`
+	RoleID int    `json:"roleId" binding:"required,min=1"`
+}
+
+type AcceptAdminInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,max=16"`
+}
+
+type UpdateAdminRoleRequest struct {
+	RoleID int `json:"roleId" binding:"required,min=1"`
+}
+
+type AdminProfile struct {
+	*model.Admin
+	Permissions []string `json:"permissions"`
+}
+
+func NewAdminProfile(admin *model.Admin) *AdminProfile {
+	return &AdminProfile{
+		Admin:       admin,
+		Permissions: admin.Permissions(),
+	}
+}
+
+type GetAdminAuditLogsRequest struct {
+	Page    int `form:"page"`
+	Limit   int `form:"limit"`
+	AdminID int `form:"adminId"`
+}
+
+func (req *GetAdminAuditLogsRequest) Validate() {
+	if req.Limit < 1 {
+		req.Limit = constant.DefaultAdminAuditLogLimit
+	}
+
+	if req.Limit > constant.MaxAdminAuditLogLimit {
+		req.Limit = constant.MaxAdminAuditLogLimit
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+}
+
+func (req *GetAdminAuditLogsRequest) Offset() int {
+	return (req.Page - 1) * req.Limit
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/admin/dto"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditAction records every change made under /admins against the admin who
+// made it. Reads aren't recorded.
+func (h *Handler) AuditAction(c *gin.Context) {
+	if c.Request.Method == http.MethodGet {
+		return
+	}
+
+	c.Next()
+
+	err := h.adminAuditService.RecordAction(&model.AdminAuditLog{
+		AdminID:    c.GetInt("adminId"),
+		Action:     c.Request.Method + " " + c.FullPath(),
+		Path:       c.Request.URL.Path,
+		StatusCode: c.Writer.Status(),
+		IPAddress:  c.ClientIP(),
+	})
+	if err != nil {
+		log.Println("failed to record admin action", c.Request.Method, c.Request.URL.Path, ":", err)
+	}
+}
+
+func (h *Handler) GetAuditLogs(c *gin.Context) {
+	var request dto.GetAdminAuditLogsRequest
+	_ = c.ShouldBindQuery(&request)
+	request.Validate()
+
+	res, err := h.adminAuditService.GetAuditLogs(&request)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", res)
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/admin/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SignIn(c *gin.Context) {
+	var request dto.AdminLoginRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	request.IPAddress = c.ClientIP()
+
+	res, err := h.adminService.SignIn(&request)
+	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidCredential) {
+			response.Error(c, http.StatusBadRequest, code.WRONG_PASSWORD, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", res)
+}
+
+// GetSession runs after AdminJWTAuthorization and stores the admin's
+// permissions for RequireAdminPermission.
+func (h *Handler) GetSession(c *gin.Context) {
+	adminId := c.GetInt("adminId")
+	token := strings.Replace(c.GetHeader("authorization"), "Bearer ", "", -1)
+
+	admin, err := h.adminService.GetSession(adminId, token)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{
+			Code:    code.UNAUTHORIZED,
+			Message: errs.ErrUnauthorized.Error(),
+		})
+		return
+	}
+
+	c.Set("adminPermissions", admin.Permissions())
+}
+
+func (h *Handler) GetProfile(c *gin.Context) {
+	adminId := c.GetInt("adminId")
+
+	res, err := h.adminService.GetProfile(adminId)
+	if err != nil {
+		if errors.Is(err, errs.ErrAdminNotFound) {
+			response.Error(c, http.StatusNotFound, code.ADMIN_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", res)
+}
+
+func (h *Handler) GetAdmins(c *gin.Context) {
+	res, err := h.adminService.GetAdmins()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", res)
+}
+
+func (h *Handler) GetRoles(c *gin.Context) {
+	res, err := h.adminService.GetRoles()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", res)
+}
+
+func (h *Handler) InviteAdmin(c *gin.Context) {
+	adminId := c.GetInt("adminId")
+
+	var request dto.InviteAdminRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.adminService.InviteAdmin(adminId, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrAdminRoleNotFound) {
+			response.Error(c, http.StatusNotFound, code.ADMIN_ROLE_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAdminAlreadyExists) {
+			response.Error(c, http.StatusConflict, code.ADMIN_ALREADY_EXISTS, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "invitation sent", res)
+}
+
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	var request dto.AcceptAdminInvitationRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.adminService.AcceptInvitation(&request)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidAdminInvitation) {
+			response.Error(c, http.StatusBadRequest, code.INVALID_ADMIN_INVITATION, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "invitation accepted", nil)
+}
+
+func (h *Handler) UpdateAdminRole(c *gin.Context) {
+	actorId := c.GetInt("adminId")
+
+	adminId, err := strconv.Atoi(c.Param("adminId"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, code.ADMIN_NOT_FOUND, errs.ErrAdminNotFound.Error())
+		return
+	}
+
+	var request dto.UpdateAdminRoleRequest
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.adminService.UpdateRole(actorId, adminId, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrCannotModifyOwnAdminRole) {
+			response.Error(c, http.StatusBadRequest, code.CANNOT_MODIFY_OWN_ADMIN_ROLE, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAdminNotFound) {
+			response.Error(c, http.StatusNotFound, code.ADMIN_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAdminRoleNotFound) {
+			response.Error(c, http.StatusNotFound, code.ADMIN_ROLE_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "updated", res)
+}
+
+func (h *Handler) RevokeAdmin(c *gin.Context) {
+	actorId := c.GetInt("adminId")
+
+	adminId, err := strconv.Atoi(c.Param("adminId"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, code.ADMIN_NOT_FOUND, errs.ErrAdminNotFound.Error())
+		return
+	}
+
+	err = h.adminService.RevokeAdmin(actorId, adminId)
+	if err != nil {
+		if errors.Is(err, errs.ErrCannotModifyOwnAdminRole) {
+			response.Error(c, http.StatusBadRequest, code.CANNOT_MODIFY_OWN_ADMIN_ROLE, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAdminNotFound) {
+			response.Error(c, http.StatusNotFound, code.ADMIN_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "revoked", nil)
+}
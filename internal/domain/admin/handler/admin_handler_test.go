@@ -0,0 +1,366 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/admin/dto"
+	"kedai/backend/be-kedai/internal/domain/admin/handler"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSignIn(t *testing.T) {
+	var (
+		req   = &dto.AdminLoginRequest{Email: "finance@kedai.com", Password: "Kedai_admin1"}
+		token = &dto.AdminToken{AccessToken: "access"}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		beforeTests func(as *mocks.AdminService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when credential is invalid",
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("SignIn", &dto.AdminLoginRequest{Email: req.Email, Password: req.Password, IPAddress: "192.0.2.1"}).Return(nil, errs.ErrInvalidCredential)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.WRONG_PASSWORD,
+					Message: errs.ErrInvalidCredential.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 429 when sign in attempts are throttled",
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("SignIn", &dto.AdminLoginRequest{Email: req.Email, Password: req.Password, IPAddress: "192.0.2.1"}).Return(nil, errs.ErrTooManyAttempts)
+			},
+			expected: expected{
+				statusCode: http.StatusTooManyRequests,
+				response: response.Response{
+					Code:    code.TOO_MANY_ATTEMPTS,
+					Message: errs.ErrTooManyAttempts.Error(),
+				},
+			},
+		},
+		{
+			description: "should return token with code 200 when success",
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("SignIn", &dto.AdminLoginRequest{Email: req.Email, Password: req.Password, IPAddress: "192.0.2.1"}).Return(token, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+					Data:    token,
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockAdminService := mocks.NewAdminService(t)
+			tc.beforeTests(mockAdminService)
+			handler := handler.New(&handler.Config{
+				AdminService: mockAdminService,
+			})
+			c.Request = httptest.NewRequest("POST", "/admins/login", test.MakeRequestBody(req))
+
+			handler.SignIn(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestGetSession(t *testing.T) {
+	t.Run("should abort with code 401 when session is not live", func(t *testing.T) {
+		expectedBody, _ := json.Marshal(response.Response{
+			Code:    code.UNAUTHORIZED,
+			Message: errs.ErrUnauthorized.Error(),
+		})
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		mockAdminService := mocks.NewAdminService(t)
+		mockAdminService.On("GetSession", 2, "token").Return(nil, errs.ErrUnauthorized)
+		handler := handler.New(&handler.Config{
+			AdminService: mockAdminService,
+		})
+		c.Set("adminId", 2)
+		c.Request, _ = http.NewRequest("GET", "/admins/me", nil)
+		c.Request.Header.Set("Authorization", "Bearer token")
+
+		handler.GetSession(c)
+
+		assert.True(t, c.IsAborted())
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, string(expectedBody), rec.Body.String())
+	})
+
+	t.Run("should store permissions of the admin's role", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		mockAdminService := mocks.NewAdminService(t)
+		mockAdminService.On("GetSession", 2, "token").Return(&model.Admin{
+			ID: 2,
+			Role: &model.AdminRole{Permissions: []*model.AdminPermission{
+				{Code: constant.AdminPermissionManageRefunds},
+				{Code: constant.AdminPermissionManageMarketplace},
+			}},
+		}, nil)
+		handler := handler.New(&handler.Config{
+			AdminService: mockAdminService,
+		})
+		c.Set("adminId", 2)
+		c.Request, _ = http.NewRequest("GET", "/admins/me", nil)
+		c.Request.Header.Set("Authorization", "Bearer token")
+
+		handler.GetSession(c)
+
+		assert.False(t, c.IsAborted())
+		assert.Equal(t, []string{constant.AdminPermissionManageRefunds, constant.AdminPermissionManageMarketplace}, c.GetStringSlice("adminPermissions"))
+	})
+}
+
+func TestInviteAdmin(t *testing.T) {
+	var (
+		adminId = 1
+		req     = &dto.InviteAdminRequest{Email: "finance@kedai.com", Name: "Fina", RoleID: 2}
+		result  = &model.Admin{ID: 3, Email: "finance@kedai.com", Name: "Fina", Status: constant.AdminStatusInvited, RoleID: 2}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		body        interface{}
+		beforeTests func(as *mocks.AdminService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when email is invalid",
+			body:        &dto.InviteAdminRequest{Email: "finance", Name: "Fina", RoleID: 2},
+			beforeTests: func(as *mocks.AdminService) {},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Email must be an email format",
+				},
+			},
+		},
+		{
+			description: "should return error with code 404 when role does not exist",
+			body:        req,
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("InviteAdmin", adminId, req).Return(nil, errs.ErrAdminRoleNotFound)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.ADMIN_ROLE_NOT_FOUND,
+					Message: errs.ErrAdminRoleNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 409 when email belongs to an active admin",
+			body:        req,
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("InviteAdmin", adminId, req).Return(nil, errs.ErrAdminAlreadyExists)
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.ADMIN_ALREADY_EXISTS,
+					Message: errs.ErrAdminAlreadyExists.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			body:        req,
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("InviteAdmin", adminId, req).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return invited admin with code 201 when success",
+			body:        req,
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("InviteAdmin", adminId, req).Return(result, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "invitation sent",
+					Data:    result,
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockAdminService := mocks.NewAdminService(t)
+			tc.beforeTests(mockAdminService)
+			handler := handler.New(&handler.Config{
+				AdminService: mockAdminService,
+			})
+			c.Set("adminId", adminId)
+			c.Request, _ = http.NewRequest("POST", "/admins/staff", test.MakeRequestBody(tc.body))
+
+			handler.InviteAdmin(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestRevokeAdmin(t *testing.T) {
+	var adminId = 1
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		param       string
+		beforeTests func(as *mocks.AdminService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when revoking yourself",
+			param:       "1",
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("RevokeAdmin", adminId, 1).Return(errs.ErrCannotModifyOwnAdminRole)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.CANNOT_MODIFY_OWN_ADMIN_ROLE,
+					Message: errs.ErrCannotModifyOwnAdminRole.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 404 when admin does not exist",
+			param:       "3",
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("RevokeAdmin", adminId, 3).Return(errs.ErrAdminNotFound)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.ADMIN_NOT_FOUND,
+					Message: errs.ErrAdminNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return code 200 when revoked",
+			param:       "3",
+			beforeTests: func(as *mocks.AdminService) {
+				as.On("RevokeAdmin", adminId, 3).Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "revoked",
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockAdminService := mocks.NewAdminService(t)
+			tc.beforeTests(mockAdminService)
+			handler := handler.New(&handler.Config{
+				AdminService: mockAdminService,
+			})
+			c.Set("adminId", adminId)
+			c.AddParam("adminId", tc.param)
+			c.Request, _ = http.NewRequest("DELETE", "/admins/staff/"+tc.param, nil)
+
+			handler.RevokeAdmin(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestAuditAction(t *testing.T) {
+	t.Run("should not record reads", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		handler := handler.New(&handler.Config{
+			AdminAuditService: mocks.NewAdminAuditService(t),
+		})
+		c.Request, _ = http.NewRequest("GET", "/admins/staff", nil)
+
+		handler.AuditAction(c)
+	})
+
+	t.Run("should attribute changes to the admin who made them", func(t *testing.T) {
+		mockAuditService := mocks.NewAdminAuditService(t)
+		mockAuditService.On("RecordAction", mock.MatchedBy(func(log *model.AdminAuditLog) bool {
+			return log.AdminID == 2 && log.Action == "POST /v1/admins/orders/refund/:refundId" &&
+				log.Path == "/v1/admins/orders/refund/5" && log.StatusCode == http.StatusOK
+		})).Return(nil)
+		handler := handler.New(&handler.Config{
+			AdminAuditService: mockAuditService,
+		})
+		r := gin.New()
+		r.POST("/v1/admins/orders/refund/:refundId", func(c *gin.Context) {
+			c.Set("adminId", 2)
+		}, handler.AuditAction, func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		req, _ := http.NewRequest("POST", "/v1/admins/orders/refund/5", nil)
+
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	})
+}
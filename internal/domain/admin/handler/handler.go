@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/domain/admin/service"
+)
+
+type Handler struct {
+	adminService      service.AdminService
+	adminAuditService service.AdminAuditService
+}
+
+type Config struct {
+	AdminService      service.AdminService
+	AdminAuditService service.AdminAuditService
+}
+
+func New(cfg *Config) *Handler {
+	return &Handler{
+		adminService:      cfg.AdminService,
+		adminAuditService: cfg.AdminAuditService,
+	}
+}
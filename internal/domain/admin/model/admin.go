@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Admin struct {
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Password        *string    `json:"-"`
+	Status          string     `json:"status"`
+	RoleID          int        `json:"roleId"`
+	Role            *AdminRole `json:"role,omitempty"`
+	InvitedByID     *int       `json:"invitedById"`
+	InviteToken     *string    `json:"-"`
+	InviteExpiresAt *time.Time `json:"-"`
+	LastLoginAt     *time.Time `json:"lastLoginAt"`
+	RevokedAt       *time.Time `json:"revokedAt"`
+	RevokedByID     *int       `json:"revokedById"`
+
+	gorm.Model `json:"-"`
+}
+
+// Permissions lists the permission codes granted through the admin's role.
+func (a *Admin) Permissions() []string {
+	if a.Role == nil {
+		return nil
+	}
+
+	permissions := make([]string, 0, len(a.Role.Permissions))
+	for _, permission := range a.Role.Permissions {
+		permissions = append(permissions, permission.Code)
+	}
+
+	return permissions
+}
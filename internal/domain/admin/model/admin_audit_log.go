@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// AdminAuditLog attributes one change made under /admins to the admin who
+// made it.
+type AdminAuditLog struct {
+	ID         int       `json:"id"`
+	AdminID    int       `json:"adminId"`
+	Admin      *Admin    `json:"admin,omitempty"`
+	Action     string    `json:"action"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"statusCode"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
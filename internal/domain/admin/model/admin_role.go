@@ -0,0 +1,14 @@
+package model
+
+type AdminRole struct {
+	ID          int                `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Permissions []*AdminPermission `json:"permissions,omitempty" gorm:"many2many:admin_role_permissions;joinForeignKey:RoleID;joinReferences:PermissionID"`
+}
+
+type AdminPermission struct {
+	ID          int    `json:"id"`
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
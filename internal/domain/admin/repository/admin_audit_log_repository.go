@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"kedai/backend/be-kedai/internal/domain/admin/dto"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+type AdminAuditLogRepository interface {
+	Create(log *model.AdminAuditLog) error
+	GetAll(req *dto.GetAdminAuditLogsRequest) ([]*model.AdminAuditLog, int64, int, error)
+}
+
+type adminAuditLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type AdminAuditLogRConfig struct {
+	DB *gorm.DB
+}
+
+func NewAdminAuditLogRepository(cfg *AdminAuditLogRConfig) AdminAuditLogRepository {
+	return &adminAuditLogRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *adminAuditLogRepositoryImpl) Create(log *model.AdminAuditLog) error {
+	return r.db.Omit("Admin").Create(log).Error
+}
+
+func (r *adminAuditLogRepositoryImpl) GetAll(req *dto.GetAdminAuditLogsRequest) ([]*model.AdminAuditLog, int64, int, error) {
+	var (
+		logs      []*model.AdminAuditLog
+		totalRows int64
+	)
+
+	query := r.db.Model(&model.AdminAuditLog{})
+	if req.AdminID != 0 {
+		query = query.Where("admin_id = ?", req.AdminID)
+	}
+
+	err := query.Session(&gorm.Session{}).Count(&totalRows).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = query.Preload("Admin").Order("created_at desc").Limit(req.Limit).Offset(req.Offset()).Find(&logs).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalRows) / float64(req.Limit)))
+
+	return logs, totalRows, totalPages, nil
+}
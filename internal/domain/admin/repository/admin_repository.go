@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AdminRepository interface {
+	GetByID(id int) (*model.Admin, error)
+	GetByEmail(email string) (*model.Admin, error)
+	GetByInviteToken(token string) (*model.Admin, error)
+	GetAll() ([]*model.Admin, error)
+	Count() (int64, error)
+	Create(admin *model.Admin) error
+	Save(admin *model.Admin) error
+}
+
+type adminRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type AdminRConfig struct {
+	DB *gorm.DB
+}
+
+func NewAdminRepository(cfg *AdminRConfig) AdminRepository {
+	return &adminRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *adminRepositoryImpl) GetByID(id int) (*model.Admin, error) {
+	var admin model.Admin
+
+	err := r.db.Preload("Role.Permissions").Where("id = ?", id).First(&admin).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrAdminNotFound
+		}
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+func (r *adminRepositoryImpl) GetByEmail(email string) (*model.Admin, error) {
+	var admin model.Admin
+
+	err := r.db.Preload("Role.Permissions").Where("lower(email) = lower(?)", email).First(&admin).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrAdminNotFound
+		}
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+func (r *adminRepositoryImpl) GetByInviteToken(token string) (*model.Admin, error) {
+	var admin model.Admin
+
+	err := r.db.Where("invite_token = ?", token).First(&admin).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrInvalidAdminInvitation
+		}
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+func (r *adminRepositoryImpl) GetAll() ([]*model.Admin, error) {
+	var admins []*model.Admin
+
+	err := r.db.Preload("Role").Order("created_at").Find(&admins).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return admins, nil
+}
+
+func (r *adminRepositoryImpl) Count() (int64, error) {
+	var count int64
+
+	err := r.db.Model(&model.Admin{}).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *adminRepositoryImpl) Create(admin *model.Admin) error {
+	return r.db.Omit(clause.Associations).Create(admin).Error
+}
+
+func (r *adminRepositoryImpl) Save(admin *model.Admin) error {
+	return r.db.Omit(clause.Associations).Save(admin).Error
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+
+	"gorm.io/gorm"
+)
+
+type AdminRoleRepository interface {
+	GetAll() ([]*model.AdminRole, error)
+	GetByID(id int) (*model.AdminRole, error)
+	GetByName(name string) (*model.AdminRole, error)
+}
+
+type adminRoleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type AdminRoleRConfig struct {
+	DB *gorm.DB
+}
+
+func NewAdminRoleRepository(cfg *AdminRoleRConfig) AdminRoleRepository {
+	return &adminRoleRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *adminRoleRepositoryImpl) GetAll() ([]*model.AdminRole, error) {
+	var roles []*model.AdminRole
+
+	err := r.db.Preload("Permissions").Order("id").Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *adminRoleRepositoryImpl) GetByID(id int) (*model.AdminRole, error) {
+	var role model.AdminRole
+
+	err := r.db.Preload("Permissions").Where("id = ?", id).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrAdminRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *adminRoleRepositoryImpl) GetByName(name string) (*model.AdminRole, error) {
+	var role model.AdminRole
+
+	err := r.db.Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrAdminRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	"kedai/backend/be-kedai/internal/domain/admin/dto"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+	"kedai/backend/be-kedai/internal/domain/admin/repository"
+)
+
+type AdminAuditService interface {
+	RecordAction(log *model.AdminAuditLog) error
+	GetAuditLogs(req *dto.GetAdminAuditLogsRequest) (*commonDto.PaginationResponse, error)
+}
+
+type adminAuditServiceImpl struct {
+	auditLogRepo repository.AdminAuditLogRepository
+}
+
+type AdminAuditSConfig struct {
+	AuditLogRepo repository.AdminAuditLogRepository
+}
+
+func NewAdminAuditService(cfg *AdminAuditSConfig) AdminAuditService {
+	return &adminAuditServiceImpl{
+		auditLogRepo: cfg.AuditLogRepo,
+	}
+}
+
+func (s *adminAuditServiceImpl) RecordAction(log *model.AdminAuditLog) error {
+	return s.auditLogRepo.Create(log)
+}
+
+func (s *adminAuditServiceImpl) GetAuditLogs(req *dto.GetAdminAuditLogsRequest) (*commonDto.PaginationResponse, error) {
+	logs, totalRows, totalPages, err := s.auditLogRepo.GetAll(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commonDto.PaginationResponse{
+		Data:       logs,
+		Limit:      req.Limit,
+		Page:       req.Page,
+		TotalRows:  totalRows,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/admin/cache"
+	"kedai/backend/be-kedai/internal/domain/admin/dto"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+	"kedai/backend/be-kedai/internal/domain/admin/repository"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/utils/attempt"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/internal/utils/random"
+	"log"
+	"strings"
+	"time"
+)
+
+type AdminService interface {
+	SignIn(req *dto.AdminLoginRequest) (*dto.AdminToken, error)
+	GetSession(adminId int, accessToken string) (*model.Admin, error)
+	GetProfile(adminId int) (*dto.AdminProfile, error)
+	GetAdmins() ([]*model.Admin, error)
+	GetRoles() ([]*model.AdminRole, error)
+	InviteAdmin(inviterId int, req *dto.InviteAdminRequest) (*model.Admin, error)
+	AcceptInvitation(req *dto.AcceptAdminInvitationRequest) error
+	UpdateRole(actorId int, adminId int, req *dto.UpdateAdminRoleRequest) (*model.Admin, error)
+	RevokeAdmin(actorId int, adminId int) error
+	Bootstrap(email string, hashedPassword string) error
+}
+
+type adminServiceImpl struct {
+	adminRepo      repository.AdminRepository
+	adminRoleRepo  repository.AdminRoleRepository
+	cache          cache.AdminCache
+	randomUtils    random.RandomUtils
+	emailPublisher notificationService.EmailPublisher
+	limiter        attempt.Limiter
+}
+
+type AdminSConfig struct {
+	AdminRepo      repository.AdminRepository
+	AdminRoleRepo  repository.AdminRoleRepository
+	Cache          cache.AdminCache
+	RandomUtils    random.RandomUtils
+	EmailPublisher notificationService.EmailPublisher
+	Limiter        attempt.Limiter
+}
+
+func NewAdminService(cfg *AdminSConfig) AdminService {
+	return &adminServiceImpl{
+		adminRepo:      cfg.AdminRepo,
+		adminRoleRepo:  cfg.AdminRoleRepo,
+		cache:          cfg.Cache,
+		randomUtils:    cfg.RandomUtils,
+		emailPublisher: cfg.EmailPublisher,
+		limiter:        cfg.Limiter,
+	}
+}
+
+func (s *adminServiceImpl) SignIn(req *dto.AdminLoginRequest) (*dto.AdminToken, error) {
+	email := strings.ToLower(req.Email)
+	accountKey := attempt.AccountKey(constant.AttemptScopeAdminLogin, email)
+	ipKey := attempt.IPKey(constant.AttemptScopeAdminLogin, req.IPAddress)
+	if s.limiter != nil {
+		if err := s.limiter.Check(accountKey, ipKey); err != nil {
+			return nil, err
+		}
+	}
+
+	admin, err := s.adminRepo.GetByEmail(email)
+	if err != nil {
+		if err == errs.ErrAdminNotFound {
+			s.recordFailedSignIn(nil, req.IPAddress, accountKey, ipKey)
+			return nil, errs.ErrInvalidCredential
+		}
+		return nil, err
+	}
+
+	if admin.Status != constant.AdminStatusActive || admin.Password == nil || !hash.ComparePassword(*admin.Password, req.Password) {
+		s.recordFailedSignIn(admin, req.IPAddress, accountKey, ipKey)
+		return nil, errs.ErrInvalidCredential
+	}
+
+	if s.limiter != nil {
+		if err := s.limiter.Reset(accountKey); err != nil {
+			log.Println("failed to reset failed sign ins of admin", admin.ID, ":", err)
+		}
+		if err := s.limiter.Release(ipKey); err != nil {
+			log.Println("failed to release sign in attempt for", ipKey, ":", err)
+		}
+	}
+
+	accessToken, err := jwttoken.GenerateAdminAccessToken(admin.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.cache.StoreToken(admin.ID, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	admin.LastLoginAt = &now
+	if err := s.adminRepo.Save(admin); err != nil {
+		log.Println("failed to record login of admin", admin.ID, ":", err)
+	}
+
+	return &dto.AdminToken{AccessToken: accessToken}, nil
+}
+
+// recordFailedSignIn counts a failed sign in against the account and the
+// client IP, and emails the admin once their account gets locked out.
+func (s *adminServiceImpl) recordFailedSignIn(admin *model.Admin, ipAddress string, accountKey string, ipKey string) {
+	if s.limiter == nil {
+		return
+	}
+
+	locked, err := s.limiter.Fail(accountKey)
+	if err != nil {
+		log.Println("failed to record failed sign in for", accountKey, ":", err)
+	}
+	if _, err := s.limiter.Fail(ipKey); err != nil {
+		log.Println("failed to record failed sign in for", ipKey, ":", err)
+	}
+
+	if !locked || admin == nil || s.emailPublisher == nil {
+		return
+	}
+
+	err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+		To:       admin.Email,
+		Category: constant.NotificationCategorySecurity,
+		Template: mail.TemplateAccountLocked,
+		Data: &mail.AccountLockedEmailData{
+			Name:        admin.Name,
+			IPAddress:   ipAddress,
+			LockedUntil: time.Now().Add(constant.AttemptLockout).Format("2 January 2006 15:04 MST"),
+		},
+	})
+	if err != nil {
+		log.Println("failed to enqueue lockout alert to", admin.Email, ":", err)
+	}
+}
+
+// GetSession loads the admin behind a live access token. The admin is read
+// from the database on every request so role changes and revocations apply
+// straight away.
+func (s *adminServiceImpl) GetSession(adminId int, accessToken string) (*model.Admin, error) {
+	err := s.cache.FindToken(adminId, accessToken)
+	if err != nil {
+		return nil, errs.ErrInvalidToken
+	}
+
+	admin, err := s.adminRepo.GetByID(adminId)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin.Status != constant.AdminStatusActive {
+		return nil, errs.ErrUnauthorized
+	}
+
+	return admin, nil
+}
+
+func (s *adminServiceImpl) GetProfile(adminId int) (*dto.AdminProfile, error) {
+	admin, err := s.adminRepo.GetByID(adminId)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewAdminProfile(admin), nil
+}
+
+func (s *adminServiceImpl) GetAdmins() ([]*model.Admin, error) {
+	return s.adminRepo.GetAll()
+}
+
+func (s *adminServiceImpl) GetRoles() ([]*model.AdminRole, error) {
+	return s.adminRoleRepo.GetAll()
+}
+
+// InviteAdmin creates an invited admin, or re-invites one that was revoked
+// or never accepted, and emails them a link to set their password.
+func (s *adminServiceImpl) InviteAdmin(inviterId int, req *dto.InviteAdminRequest) (*model.Admin, error) {
+	inviter, err := s.adminRepo.GetByID(inviterId)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.adminRoleRepo.GetByID(req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	admin, err := s.adminRepo.GetByEmail(req.Email)
+	if err != nil && err != errs.ErrAdminNotFound {
+		return nil, err
+	}
+	if admin != nil && admin.Status == constant.AdminStatusActive {
+		return nil, errs.ErrAdminAlreadyExists
+	}
+	if admin == nil {
+		admin = &model.Admin{Email: req.Email}
+	}
+
+	token := s.randomUtils.GenerateSecureUniqueToken()
+	hashedToken := hash.HashSHA256(token)
+	expiresAt := time.Now().Add(constant.AdminInvitationAge)
+
+	admin.Name = req.Name
+	admin.Status = constant.AdminStatusInvited
+	admin.RoleID = role.ID
+	admin.Role = nil
+	admin.Password = nil
+	admin.InvitedByID = &inviter.ID
+	admin.InviteToken = &hashedToken
+	admin.InviteExpiresAt = &expiresAt
+	admin.RevokedAt = nil
+	admin.RevokedByID = nil
+
+	if admin.ID == 0 {
+		err = s.adminRepo.Create(admin)
+	} else {
+		err = s.adminRepo.Save(admin)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.emailPublisher != nil {
+		err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+			To:       admin.Email,
+			Category: constant.NotificationCategorySecurity,
+			Template: mail.TemplateAdminInvitation,
+			Data: &mail.AdminInvitationEmailData{
+				Name:          admin.Name,
+				InviterName:   inviter.Name,
+				RoleName:      role.Description,
+				InvitationURL: config.GetEnv("FRONTEND_URL", "http://localhost:3000") + config.GetEnv("ADMIN_INVITATION_URL", "/admin/invitations?token=") + token,
+				ExpiresAt:     expiresAt.Format("2 January 2006 15:04 MST"),
+			},
+		})
+		if err != nil {
+			log.Println("failed to enqueue invitation email to", admin.Email, ":", err)
+		}
+	}
+
+	admin.Role = role
+
+	return admin, nil
+}
+
+func (s *adminServiceImpl) AcceptInvitation(req *dto.AcceptAdminInvitationRequest) error {
+	admin, err := s.adminRepo.GetByInviteToken(hash.HashSHA256(req.Token))
+	if err != nil {
+		return err
+	}
+
+	if admin.Status != constant.AdminStatusInvited || admin.InviteExpiresAt == nil || time.Now().After(*admin.InviteExpiresAt) {
+		return errs.ErrInvalidAdminInvitation
+	}
+
+	password, err := hash.HashAndSalt(req.Password)
+	if err != nil {
+		return err
+	}
+
+	admin.Password = &password
+	admin.Status = constant.AdminStatusActive
+	admin.InviteToken = nil
+	admin.InviteExpiresAt = nil
+
+	return s.adminRepo.Save(admin)
+}
+
+func (s *adminServiceImpl) UpdateRole(actorId int, adminId int, req *dto.UpdateAdminRoleRequest) (*model.Admin, error) {
+	if actorId == adminId {
+		return nil, errs.ErrCannotModifyOwnAdminRole
+	}
+
+	admin, err := s.adminRepo.GetByID(adminId)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.adminRoleRepo.GetByID(req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	admin.RoleID = role.ID
+	admin.Role = nil
+	err = s.adminRepo.Save(admin)
+	if err != nil {
+		return nil, err
+	}
+
+	admin.Role = role
+
+	return admin, nil
+}
+
+// RevokeAdmin disables the admin and ends their sessions. The row is kept so
+// their audit trail still resolves to a person.
+func (s *adminServiceImpl) RevokeAdmin(actorId int, adminId int) error {
+	if actorId == adminId {
+		return errs.ErrCannotModifyOwnAdminRole
+	}
+
+	admin, err := s.adminRepo.GetByID(adminId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	admin.Status = constant.AdminStatusRevoked
+	admin.RevokedAt = &now
+	admin.RevokedByID = &actorId
+	admin.InviteToken = nil
+	admin.InviteExpiresAt = nil
+	admin.Role = nil
+
+	err = s.adminRepo.Save(admin)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.DeleteAllByID(adminId)
+}
+
+// Bootstrap creates the first super admin from ADMIN_EMAIL and the bcrypt
+// hash in ADMIN_PASSWORD. It does nothing once any admin exists, so the env
+// vars stop granting access as soon as real accounts are set up.
+func (s *adminServiceImpl) Bootstrap(email string, hashedPassword string) error {
+	if email == "" || hashedPassword == "" {
+		return nil
+	}
+
+	count, err := s.adminRepo.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	role, err := s.adminRoleRepo.GetByName(constant.AdminRoleSuperAdmin)
+	if err != nil {
+		return err
+	}
+
+	return s.adminRepo.Create(&model.Admin{
+		Email:    email,
+		Name:     "Super Admin",
+		Password: &hashedPassword,
+		Status:   constant.AdminStatusActive,
+		RoleID:   role.ID,
+	})
+}
@@ -0,0 +1,412 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/admin/dto"
+	"kedai/backend/be-kedai/internal/domain/admin/model"
+	"kedai/backend/be-kedai/internal/domain/admin/service"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSignIn(t *testing.T) {
+	test.UseJWTKeySet(t)
+
+	var (
+		password, _ = hash.HashAndSalt("Kedai_admin1")
+		req         = &dto.AdminLoginRequest{Email: "finance@kedai.com", Password: "Kedai_admin1"}
+	)
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.AdminRepository, *mocks.AdminCache)
+		err         error
+	}{
+		{
+			description: "should return invalid credential when admin does not exist",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ar.On("GetByEmail", req.Email).Return(nil, errs.ErrAdminNotFound)
+			},
+			err: errs.ErrInvalidCredential,
+		},
+		{
+			description: "should return invalid credential when admin was revoked",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ar.On("GetByEmail", req.Email).Return(&model.Admin{ID: 2, Password: &password, Status: constant.AdminStatusRevoked}, nil)
+			},
+			err: errs.ErrInvalidCredential,
+		},
+		{
+			description: "should return invalid credential when password is wrong",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				wrong, _ := hash.HashAndSalt("Another_pass1")
+				ar.On("GetByEmail", req.Email).Return(&model.Admin{ID: 2, Password: &wrong, Status: constant.AdminStatusActive}, nil)
+			},
+			err: errs.ErrInvalidCredential,
+		},
+		{
+			description: "should return error when failed to store session",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ar.On("GetByEmail", req.Email).Return(&model.Admin{ID: 2, Password: &password, Status: constant.AdminStatusActive}, nil)
+				ac.On("StoreToken", 2, mock.Anything).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return token and record login when credentials match",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ar.On("GetByEmail", req.Email).Return(&model.Admin{ID: 2, Password: &password, Status: constant.AdminStatusActive}, nil)
+				ac.On("StoreToken", 2, mock.Anything).Return(nil)
+				ar.On("Save", mock.MatchedBy(func(admin *model.Admin) bool {
+					return admin.LastLoginAt != nil
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockAdminRepo := mocks.NewAdminRepository(t)
+			mockAdminCache := mocks.NewAdminCache(t)
+			tc.beforeTest(mockAdminRepo, mockAdminCache)
+			adminService := service.NewAdminService(&service.AdminSConfig{
+				AdminRepo: mockAdminRepo,
+				Cache:     mockAdminCache,
+			})
+
+			result, err := adminService.SignIn(req)
+
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.NotEmpty(t, result.AccessToken)
+			}
+		})
+	}
+
+	t.Run("should return error when sign in attempts are throttled", func(t *testing.T) {
+		mockLimiter := mocks.NewLimiter(t)
+		mockLimiter.On("Check", "admin_login:account:finance@kedai.com", "admin_login:ip:127.0.0.1").Return(errs.ErrTooManyAttempts)
+		adminService := service.NewAdminService(&service.AdminSConfig{
+			Limiter: mockLimiter,
+		})
+
+		result, err := adminService.SignIn(&dto.AdminLoginRequest{Email: "Finance@kedai.com", Password: "Kedai_admin1", IPAddress: "127.0.0.1"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, errs.ErrTooManyAttempts)
+	})
+
+	t.Run("should look up and lock out the lowercased email when wrong password locks the account", func(t *testing.T) {
+		wrong, _ := hash.HashAndSalt("Another_pass1")
+		mockAdminRepo := mocks.NewAdminRepository(t)
+		mockLimiter := mocks.NewLimiter(t)
+		mockEmailPublisher := mocks.NewEmailPublisher(t)
+		mockLimiter.On("Check", "admin_login:account:finance@kedai.com", "admin_login:ip:127.0.0.1").Return(nil)
+		mockAdminRepo.On("GetByEmail", req.Email).Return(&model.Admin{ID: 2, Name: "Finance", Email: req.Email, Password: &wrong, Status: constant.AdminStatusActive}, nil)
+		mockLimiter.On("Fail", "admin_login:account:finance@kedai.com").Return(true, nil)
+		mockLimiter.On("Fail", "admin_login:ip:127.0.0.1").Return(false, nil)
+		mockEmailPublisher.On("Enqueue", mock.MatchedBy(func(email *notificationDto.EmailRequest) bool {
+			data, ok := email.Data.(*mail.AccountLockedEmailData)
+			return email.To == req.Email && email.Template == mail.TemplateAccountLocked && ok && data.IPAddress == "127.0.0.1"
+		})).Return(nil)
+		adminService := service.NewAdminService(&service.AdminSConfig{
+			AdminRepo:      mockAdminRepo,
+			EmailPublisher: mockEmailPublisher,
+			Limiter:        mockLimiter,
+		})
+
+		result, err := adminService.SignIn(&dto.AdminLoginRequest{Email: "Finance@Kedai.com", Password: req.Password, IPAddress: "127.0.0.1"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, errs.ErrInvalidCredential)
+	})
+}
+
+func TestGetSession(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.AdminRepository, *mocks.AdminCache)
+		expected    *model.Admin
+		err         error
+	}{
+		{
+			description: "should return error when session does not exist",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ac.On("FindToken", 2, "token").Return(errors.New("redis: nil"))
+			},
+			err: errs.ErrInvalidToken,
+		},
+		{
+			description: "should return error when admin was revoked after signing in",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ac.On("FindToken", 2, "token").Return(nil)
+				ar.On("GetByID", 2).Return(&model.Admin{ID: 2, Status: constant.AdminStatusRevoked}, nil)
+			},
+			err: errs.ErrUnauthorized,
+		},
+		{
+			description: "should return admin when session is live",
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ac.On("FindToken", 2, "token").Return(nil)
+				ar.On("GetByID", 2).Return(&model.Admin{ID: 2, Status: constant.AdminStatusActive}, nil)
+			},
+			expected: &model.Admin{ID: 2, Status: constant.AdminStatusActive},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockAdminRepo := mocks.NewAdminRepository(t)
+			mockAdminCache := mocks.NewAdminCache(t)
+			tc.beforeTest(mockAdminRepo, mockAdminCache)
+			adminService := service.NewAdminService(&service.AdminSConfig{
+				AdminRepo: mockAdminRepo,
+				Cache:     mockAdminCache,
+			})
+
+			result, err := adminService.GetSession(2, "token")
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestInviteAdmin(t *testing.T) {
+	var (
+		inviter = &model.Admin{ID: 1, Name: "Owner", Status: constant.AdminStatusActive}
+		role    = &model.AdminRole{ID: 2, Name: constant.AdminRoleFinance, Description: "Finance"}
+		req     = &dto.InviteAdminRequest{Email: "finance@kedai.com", Name: "Fina", RoleID: 2}
+	)
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.AdminRepository, *mocks.AdminRoleRepository, *mocks.RandomUtils, *mocks.EmailPublisher)
+		err         error
+	}{
+		{
+			description: "should return error when role does not exist",
+			beforeTest: func(ar *mocks.AdminRepository, arr *mocks.AdminRoleRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+				ar.On("GetByID", 1).Return(inviter, nil)
+				arr.On("GetByID", 2).Return(nil, errs.ErrAdminRoleNotFound)
+			},
+			err: errs.ErrAdminRoleNotFound,
+		},
+		{
+			description: "should return error when email belongs to an active admin",
+			beforeTest: func(ar *mocks.AdminRepository, arr *mocks.AdminRoleRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+				ar.On("GetByID", 1).Return(inviter, nil)
+				arr.On("GetByID", 2).Return(role, nil)
+				ar.On("GetByEmail", req.Email).Return(&model.Admin{ID: 3, Status: constant.AdminStatusActive}, nil)
+			},
+			err: errs.ErrAdminAlreadyExists,
+		},
+		{
+			description: "should re-invite a revoked admin",
+			beforeTest: func(ar *mocks.AdminRepository, arr *mocks.AdminRoleRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+				revokedAt := time.Now()
+				ar.On("GetByID", 1).Return(inviter, nil)
+				arr.On("GetByID", 2).Return(role, nil)
+				ar.On("GetByEmail", req.Email).Return(&model.Admin{ID: 3, Email: req.Email, Status: constant.AdminStatusRevoked, RevokedAt: &revokedAt}, nil)
+				ru.On("GenerateSecureUniqueToken").Return("token")
+				ar.On("Save", mock.MatchedBy(func(admin *model.Admin) bool {
+					return admin.ID == 3 && admin.Status == constant.AdminStatusInvited && admin.RevokedAt == nil &&
+						*admin.InviteToken == hash.HashSHA256("token") && admin.RoleID == 2
+				})).Return(nil)
+				ep.On("Enqueue", mock.Anything).Return(nil)
+			},
+		},
+		{
+			description: "should create invited admin and email the invitation",
+			beforeTest: func(ar *mocks.AdminRepository, arr *mocks.AdminRoleRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+				ar.On("GetByID", 1).Return(inviter, nil)
+				arr.On("GetByID", 2).Return(role, nil)
+				ar.On("GetByEmail", req.Email).Return(nil, errs.ErrAdminNotFound)
+				ru.On("GenerateSecureUniqueToken").Return("token")
+				ar.On("Create", mock.MatchedBy(func(admin *model.Admin) bool {
+					return admin.Email == req.Email && admin.Status == constant.AdminStatusInvited &&
+						*admin.InvitedByID == 1 && admin.Password == nil
+				})).Return(nil)
+				ep.On("Enqueue", mock.MatchedBy(func(req *notificationDto.EmailRequest) bool {
+					data := req.Data.(*mail.AdminInvitationEmailData)
+					return req.Template == mail.TemplateAdminInvitation && req.Category == constant.NotificationCategorySecurity &&
+						data.InviterName == "Owner" && data.RoleName == "Finance"
+				})).Return(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockAdminRepo := mocks.NewAdminRepository(t)
+			mockAdminRoleRepo := mocks.NewAdminRoleRepository(t)
+			mockRandomUtils := mocks.NewRandomUtils(t)
+			mockEmailPublisher := mocks.NewEmailPublisher(t)
+			tc.beforeTest(mockAdminRepo, mockAdminRoleRepo, mockRandomUtils, mockEmailPublisher)
+			adminService := service.NewAdminService(&service.AdminSConfig{
+				AdminRepo:      mockAdminRepo,
+				AdminRoleRepo:  mockAdminRoleRepo,
+				RandomUtils:    mockRandomUtils,
+				EmailPublisher: mockEmailPublisher,
+			})
+
+			result, err := adminService.InviteAdmin(1, req)
+
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, role, result.Role)
+			}
+		})
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	var (
+		req     = &dto.AcceptAdminInvitationRequest{Token: "token", Password: "Kedai_admin1"}
+		expired = time.Now().Add(-time.Minute)
+		valid   = time.Now().Add(time.Hour)
+	)
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.AdminRepository)
+		err         error
+	}{
+		{
+			description: "should return error when token is unknown",
+			beforeTest: func(ar *mocks.AdminRepository) {
+				ar.On("GetByInviteToken", hash.HashSHA256("token")).Return(nil, errs.ErrInvalidAdminInvitation)
+			},
+			err: errs.ErrInvalidAdminInvitation,
+		},
+		{
+			description: "should return error when invitation expired",
+			beforeTest: func(ar *mocks.AdminRepository) {
+				ar.On("GetByInviteToken", hash.HashSHA256("token")).Return(&model.Admin{ID: 3, Status: constant.AdminStatusInvited, InviteExpiresAt: &expired}, nil)
+			},
+			err: errs.ErrInvalidAdminInvitation,
+		},
+		{
+			description: "should activate admin with the chosen password",
+			beforeTest: func(ar *mocks.AdminRepository) {
+				ar.On("GetByInviteToken", hash.HashSHA256("token")).Return(&model.Admin{ID: 3, Status: constant.AdminStatusInvited, InviteExpiresAt: &valid}, nil)
+				ar.On("Save", mock.MatchedBy(func(admin *model.Admin) bool {
+					return admin.Status == constant.AdminStatusActive && admin.InviteToken == nil &&
+						hash.ComparePassword(*admin.Password, req.Password)
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockAdminRepo := mocks.NewAdminRepository(t)
+			tc.beforeTest(mockAdminRepo)
+			adminService := service.NewAdminService(&service.AdminSConfig{
+				AdminRepo: mockAdminRepo,
+			})
+
+			err := adminService.AcceptInvitation(req)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestRevokeAdmin(t *testing.T) {
+	tests := []struct {
+		description string
+		adminId     int
+		beforeTest  func(*mocks.AdminRepository, *mocks.AdminCache)
+		err         error
+	}{
+		{
+			description: "should not let an admin revoke themselves",
+			adminId:     1,
+			beforeTest:  func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {},
+			err:         errs.ErrCannotModifyOwnAdminRole,
+		},
+		{
+			description: "should return error when admin does not exist",
+			adminId:     3,
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ar.On("GetByID", 3).Return(nil, errs.ErrAdminNotFound)
+			},
+			err: errs.ErrAdminNotFound,
+		},
+		{
+			description: "should revoke admin and end their sessions",
+			adminId:     3,
+			beforeTest: func(ar *mocks.AdminRepository, ac *mocks.AdminCache) {
+				ar.On("GetByID", 3).Return(&model.Admin{ID: 3, Status: constant.AdminStatusActive}, nil)
+				ar.On("Save", mock.MatchedBy(func(admin *model.Admin) bool {
+					return admin.Status == constant.AdminStatusRevoked && *admin.RevokedByID == 1 && admin.RevokedAt != nil
+				})).Return(nil)
+				ac.On("DeleteAllByID", 3).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockAdminRepo := mocks.NewAdminRepository(t)
+			mockAdminCache := mocks.NewAdminCache(t)
+			tc.beforeTest(mockAdminRepo, mockAdminCache)
+			adminService := service.NewAdminService(&service.AdminSConfig{
+				AdminRepo: mockAdminRepo,
+				Cache:     mockAdminCache,
+			})
+
+			err := adminService.RevokeAdmin(1, tc.adminId)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Run("should not create an admin when admins already exist", func(t *testing.T) {
+		mockAdminRepo := mocks.NewAdminRepository(t)
+		mockAdminRepo.On("Count").Return(int64(1), nil)
+		adminService := service.NewAdminService(&service.AdminSConfig{
+			AdminRepo: mockAdminRepo,
+		})
+
+		err := adminService.Bootstrap("owner@kedai.com", "hashed")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should create super admin from env credentials on first start", func(t *testing.T) {
+		hashed := "hashed"
+		mockAdminRepo := mocks.NewAdminRepository(t)
+		mockAdminRoleRepo := mocks.NewAdminRoleRepository(t)
+		mockAdminRepo.On("Count").Return(int64(0), nil)
+		mockAdminRoleRepo.On("GetByName", constant.AdminRoleSuperAdmin).Return(&model.AdminRole{ID: 1}, nil)
+		mockAdminRepo.On("Create", &model.Admin{
+			Email:    "owner@kedai.com",
+			Name:     "Super Admin",
+			Password: &hashed,
+			Status:   constant.AdminStatusActive,
+			RoleID:   1,
+		}).Return(nil)
+		adminService := service.NewAdminService(&service.AdminSConfig{
+			AdminRepo:     mockAdminRepo,
+			AdminRoleRepo: mockAdminRoleRepo,
+		})
+
+		err := adminService.Bootstrap("owner@kedai.com", hashed)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type PresenceCache interface {
+	MarkOnline(userId int, connId string) error
+	MarkOffline(userId int, connId string) error
+	GetPresences(userIds []int) (map[int]*dto.Presence, error)
+	IsOnline(userId int) (bool, error)
+}
+
+type presenceCacheImpl struct {
+	rdc *redis.Client
+}
+
+type PresenceCConfig struct {
+	RDC *redis.Client
+}
+
+func NewPresenceCache(cfg *PresenceCConfig) PresenceCache {
+	return &presenceCacheImpl{
+		rdc: cfg.RDC,
+	}
+}
+
+// Every socket connection is a member of the user's presence set scored by its
+// last heartbeat, so connections held by a crashed replica age out on their own.
+func presenceKey(userId int) string {
+	return fmt.Sprintf("user_%d-presence", userId)
+}
+
+func lastSeenKey(userId int) string {
+	return fmt.Sprintf("user_%d-lastSeen", userId)
+}
+
+func (r *presenceCacheImpl) MarkOnline(userId int, connId string) error {
+	ctx := context.Background()
+	key := presenceKey(userId)
+	now := time.Now()
+
+	pipe := r.rdc.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-constant.ChatPresenceTTL).Unix(), 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.Unix()), Member: connId})
+	pipe.Expire(ctx, key, 2*constant.ChatPresenceTTL)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+func (r *presenceCacheImpl) MarkOffline(userId int, connId string) error {
+	ctx := context.Background()
+
+	pipe := r.rdc.TxPipeline()
+	pipe.ZRem(ctx, presenceKey(userId), connId)
+	pipe.Set(ctx, lastSeenKey(userId), time.Now().Unix(), 0)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+func (r *presenceCacheImpl) GetPresences(userIds []int) (map[int]*dto.Presence, error) {
+	ctx := context.Background()
+	presences := make(map[int]*dto.Presence, len(userIds))
+	if len(userIds) == 0 {
+		return presences, nil
+	}
+
+	pipe := r.rdc.Pipeline()
+	latestCmds := make([]*redis.ZSliceCmd, len(userIds))
+	lastSeenCmds := make([]*redis.StringCmd, len(userIds))
+	for i, userId := range userIds {
+		latestCmds[i] = pipe.ZRevRangeWithScores(ctx, presenceKey(userId), 0, 0)
+		lastSeenCmds[i] = pipe.Get(ctx, lastSeenKey(userId))
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	onlineSince := time.Now().Add(-constant.ChatPresenceTTL).Unix()
+	for i, userId := range userIds {
+		presence := &dto.Presence{}
+
+		var lastSeen int64
+		if latest, err := latestCmds[i].Result(); err == nil && len(latest) > 0 {
+			lastSeen = int64(latest[0].Score)
+			presence.IsOnline = lastSeen >= onlineSince
+		}
+		if stored, err := lastSeenCmds[i].Int64(); err == nil && stored > lastSeen {
+			lastSeen = stored
+		}
+
+		if !presence.IsOnline && lastSeen > 0 {
+			lastSeenAt := time.Unix(lastSeen, 0)
+			presence.LastSeenAt = &lastSeenAt
+		}
+
+		presences[userId] = presence
+	}
+
+	return presences, nil
+}
+
+func (r *presenceCacheImpl) IsOnline(userId int) (bool, error) {
+	presences, err := r.GetPresences([]int{userId})
+	if err != nil {
+		return false, err
+	}
+
+	return presences[userId].IsOnline, nil
+}
@@ -0,0 +1,77 @@
+package cache_test
+
+import (
+	"context"
+	"kedai/backend/be-kedai/internal/domain/chat/cache"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newReplicaPresenceCache(t *testing.T, addr string) (cache.PresenceCache, *redis.Client) {
+	rdc := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { rdc.Close() })
+
+	return cache.NewPresenceCache(&cache.PresenceCConfig{RDC: rdc}), rdc
+}
+
+func TestPresenceAcrossReplicas(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	replicaA, _ := newReplicaPresenceCache(t, redisServer.Addr())
+	replicaB, _ := newReplicaPresenceCache(t, redisServer.Addr())
+
+	assert.NoError(t, replicaA.MarkOnline(1, "conn-a"))
+	assert.NoError(t, replicaB.MarkOnline(1, "conn-b"))
+
+	presences, err := replicaB.GetPresences([]int{1, 2})
+	assert.NoError(t, err)
+	assert.True(t, presences[1].IsOnline)
+	assert.Nil(t, presences[1].LastSeenAt)
+	assert.False(t, presences[2].IsOnline)
+	assert.Nil(t, presences[2].LastSeenAt)
+
+	assert.NoError(t, replicaA.MarkOffline(1, "conn-a"))
+
+	presences, err = replicaB.GetPresences([]int{1})
+	assert.NoError(t, err)
+	assert.True(t, presences[1].IsOnline)
+
+	assert.NoError(t, replicaB.MarkOffline(1, "conn-b"))
+
+	presences, err = replicaA.GetPresences([]int{1})
+	assert.NoError(t, err)
+	assert.False(t, presences[1].IsOnline)
+	assert.NotNil(t, presences[1].LastSeenAt)
+}
+
+func TestPresenceExpiresConnectionsOfCrashedReplica(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	replica, rdc := newReplicaPresenceCache(t, redisServer.Addr())
+	lastHeartbeat := time.Now().Add(-2 * time.Minute).Truncate(time.Second)
+
+	err := rdc.ZAdd(context.Background(), "user_1-presence", redis.Z{Score: float64(lastHeartbeat.Unix()), Member: "conn-crashed"}).Err()
+	assert.NoError(t, err)
+
+	presences, err := replica.GetPresences([]int{1})
+	assert.NoError(t, err)
+	assert.False(t, presences[1].IsOnline)
+	assert.Equal(t, lastHeartbeat, *presences[1].LastSeenAt)
+}
+
+func TestIsOnline(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	replica, _ := newReplicaPresenceCache(t, redisServer.Addr())
+
+	assert.NoError(t, replica.MarkOnline(1, "conn-a"))
+
+	online, err := replica.IsOnline(1)
+	assert.NoError(t, err)
+	assert.True(t, online)
+
+	online, err = replica.IsOnline(2)
+	assert.NoError(t, err)
+	assert.False(t, online)
+}
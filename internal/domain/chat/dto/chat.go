@@ -1,12 +1,23 @@
 package dto
 
 import (
-	"kedai/backend/be-kedai/config"
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
 	chatModel "kedai/backend/be-kedai/internal/domain/chat/model"
-	"kedai/backend/be-kedai/internal/utils/encrypt"
+	"net/url"
+	"path"
+	"strings"
 	"time"
 )
 
+var allowedChatImageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+	".gif":  true,
+}
+
 type ListOfChatsParamRequest struct {
 	Search string `form:"search"`
 	Status string `form:"status"`
@@ -32,20 +43,117 @@ func (cpr *ChatParamRequest) Offset() int {
 
 type SendChatBodyRequest struct {
 	Message string `json:"message" binding:"required"`
+	Type    string `json:"type" binding:"omitempty,oneof=text image product invoice complaint voucher"`
+
+	// Payload is resolved by the service from Message according to Type.
+	Payload     *chatModel.ChatPayload `json:"-"`
+	IsAutoReply bool                   `json:"-"`
+	SentByID    *int                   `json:"-"`
+}
+
+// Validate defaults Type to text and reports whether it is a known chat type.
+func (req *SendChatBodyRequest) Validate() bool {
+	switch req.Type {
+	case "":
+		req.Type = constant.ChatTypeText
+		return true
+	case constant.ChatTypeText, constant.ChatTypeImage, constant.ChatTypeProduct,
+		constant.ChatTypeInvoice, constant.ChatTypeComplaint, constant.ChatTypeVoucher:
+		return true
+	default:
+		return false
+	}
+}
+
+func (req *SendChatBodyRequest) IsValidImage() bool {
+	parsed, err := url.Parse(req.Message)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return false
+	}
+
+	return allowedChatImageExtensions[strings.ToLower(path.Ext(parsed.Path))]
+}
+
+// SocketRoomRequest names the conversation by the shop for buyers and by the
+// buyer's username for sellers. ShopID picks the shop a seller acts for,
+// like the X-Shop-Id header on seller routes.
+type SocketRoomRequest struct {
+	ShopSlug string `json:"shopSlug"`
+	Username string `json:"username"`
+	ShopID   int    `json:"shopId"`
+}
+
+type SocketSendChatRequest struct {
+	SocketRoomRequest
+	Message string `json:"message"`
 	Type    string `json:"type"`
 }
 
+func (req *SocketSendChatRequest) ToSendChatBodyRequest() *SendChatBodyRequest {
+	return &SendChatBodyRequest{
+		Message: req.Message,
+		Type:    req.Type,
+	}
+}
+
+type SocketTypingRequest struct {
+	SocketRoomRequest
+	IsTyping bool `json:"isTyping"`
+}
+
+type ChatReceiptEvent struct {
+	Room   string    `json:"room"`
+	Reader string    `json:"reader"`
+	At     time.Time `json:"at"`
+}
+
+type ChatTypingEvent struct {
+	Room     string `json:"room"`
+	Issuer   string `json:"issuer"`
+	IsTyping bool   `json:"isTyping"`
+}
+
+type UnreadCountResponse struct {
+	Buyer  int `json:"buyer"`
+	Seller int `json:"seller"`
+	Total  int `json:"total"`
+}
+
+type ChatEvent struct {
+	Room   string        `json:"room"`
+	Issuer string        `json:"issuer"`
+	Chat   *ChatResponse `json:"chat"`
+}
+
+func ChatRoomID(userId int, shopId int) string {
+	return fmt.Sprintf("chat:%d:%d", userId, shopId)
+}
+
+type SocketSession struct {
+	UserID      int
+	ConnID      string
+	AccessToken string
+}
+
+type Presence struct {
+	IsOnline   bool       `json:"isOnline"`
+	LastSeenAt *time.Time `json:"lastSeenAt"`
+}
+
 type UserChatProfile struct {
-	ID       int     `json:"-"`
-	Username string  `json:"username"`
-	ImageUrl *string `json:"imageUrl"`
+	ID       int       `json:"-"`
+	Username string    `json:"username"`
+	ImageUrl *string   `json:"imageUrl"`
+	Presence *Presence `json:"presence,omitempty"`
 }
 
 type ShopChatProfile struct {
-	ID       int     `json:"-"`
-	Name     string  `json:"name"`
-	ImageUrl *string `json:"imageUrl"`
-	ShopSlug string  `json:"shopSlug"`
+	ID       int       `json:"-"`
+	UserID   int       `json:"-"`
+	Name     string    `json:"name"`
+	ImageUrl *string   `json:"imageUrl"`
+	ShopSlug string    `json:"shopSlug"`
+	Presence *Presence `json:"presence,omitempty"`
 }
 
 type UserListOfChatResponse struct {
@@ -63,25 +171,33 @@ type SellerListOfChatResponse struct {
 }
 
 type ChatResponse struct {
-	ID                  int       `json:"id"`
-	Message             string    `json:"message"`
-	Time                time.Time `json:"time"`
-	Type                string    `json:"type"`
-	IsIncoming          bool      `json:"isIncoming"`
-	IsFirstMessageOfDay *bool     `json:"isFirstMessageOfDay,omitempty"`
+	ID                  int                    `json:"id"`
+	Message             string                 `json:"message"`
+	Time                time.Time              `json:"time"`
+	Type                string                 `json:"type"`
+	IsIncoming          bool                   `json:"isIncoming"`
+	IsRead              bool                   `json:"isRead"`
+	DeliveredAt         *time.Time             `json:"deliveredAt"`
+	ReadAt              *time.Time             `json:"readAt"`
+	Payload             *chatModel.ChatPayload `json:"payload,omitempty"`
+	IsAutoReply         bool                   `json:"isAutoReply"`
+	SentByID            *int                   `json:"sentById,omitempty"`
+	IsFirstMessageOfDay *bool                  `json:"isFirstMessageOfDay,omitempty"`
 }
 
+// ChatExport is one conversation in a user's data export.
+type ChatExport struct {
+	Shop     string          `json:"shop"`
+	Messages []*ChatResponse `json:"messages"`
+}
+
+// ConvertChatToOutput expects c.Message to be decrypted already.
 func ConvertChatToOutput(c *chatModel.Chat, role string) *ChatResponse {
 	// Role: user | seller
 	if c == nil {
 		return nil
 	}
 
-	decryptedMsg, err := encrypt.DecryptMessage(c.Message, config.AES16SecretKey)
-	if err == nil {
-		c.Message = decryptedMsg
-	}
-
 	return &ChatResponse{
 		ID:      c.ID,
 		Message: c.Message,
@@ -94,8 +210,14 @@ func ConvertChatToOutput(c *chatModel.Chat, role string) *ChatResponse {
 			c.CreatedAt.Second(),
 			c.CreatedAt.Nanosecond(),
 			time.FixedZone("WIB", 7*60*60)).Add(7 * time.Hour),
-		Type:       c.Type,
-		IsIncoming: incomingSelector(c.Issuer, role),
+		Type:        c.Type,
+		IsIncoming:  incomingSelector(c.Issuer, role),
+		IsRead:      c.IsReadByOpponent,
+		DeliveredAt: c.DeliveredAt,
+		ReadAt:      c.ReadAt,
+		Payload:     c.Payload,
+		IsAutoReply: c.IsAutoReply,
+		SentByID:    c.SentByID,
 	}
 }
 
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/model"
+	"strings"
+	"time"
+)
+
+type ChatSettingRequest struct {
+	IsAutoReplyEnabled bool       `json:"isAutoReplyEnabled"`
+	AutoReplyMessage   string     `json:"autoReplyMessage" binding:"max=500"`
+	IsAwayEnabled      bool       `json:"isAwayEnabled"`
+	AwayMessage        string     `json:"awayMessage" binding:"max=500"`
+	AwayFrom           *time.Time `json:"awayFrom"`
+	AwayUntil          *time.Time `json:"awayUntil"`
+}
+
+func (req *ChatSettingRequest) Validate() error {
+	req.AutoReplyMessage = strings.TrimSpace(req.AutoReplyMessage)
+	req.AwayMessage = strings.TrimSpace(req.AwayMessage)
+
+	if req.IsAutoReplyEnabled && req.AutoReplyMessage == "" {
+		return errs.ErrChatAutoReplyMessageRequired
+	}
+	if req.IsAwayEnabled && req.AwayMessage == "" {
+		return errs.ErrChatAutoReplyMessageRequired
+	}
+	if req.AwayFrom != nil && req.AwayUntil != nil && !req.AwayUntil.After(*req.AwayFrom) {
+		return errs.ErrInvalidAwaySchedule
+	}
+
+	return nil
+}
+
+func (req *ChatSettingRequest) ToModel(shopId int) *model.ChatSetting {
+	return &model.ChatSetting{
+		ShopID:             shopId,
+		IsAutoReplyEnabled: req.IsAutoReplyEnabled,
+		AutoReplyMessage:   req.AutoReplyMessage,
+		IsAwayEnabled:      req.IsAwayEnabled,
+		AwayMessage:        req.AwayMessage,
+		AwayFrom:           req.AwayFrom,
+		AwayUntil:          req.AwayUntil,
+	}
+}
+
+type QuickReplyRequest struct {
+	Shortcut string `json:"shortcut" binding:"required,max=30"`
+	Message  string `json:"message" binding:"required,max=500"`
+}
+
+func (req *QuickReplyRequest) ToModel(shopId int) *model.ChatQuickReply {
+	return &model.ChatQuickReply{
+		ShopID:   shopId,
+		Shortcut: strings.TrimSpace(req.Shortcut),
+		Message:  req.Message,
+	}
+}
+
+// ChatPerformance summarises how a shop answers buyers over the last
+// constant.ChatPerformancePeriodDays days.
+type ChatPerformance struct {
+	ResponseRate           float64 `json:"responseRate"`
+	AverageResponseSeconds int     `json:"averageResponseSeconds"`
+}
@@ -26,7 +26,7 @@ func (h *Handler) UserGetListOfChats(c *gin.Context) {
 }
 
 func (h *Handler) SellerGetListOfChats(c *gin.Context) {
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	var param dto.ListOfChatsParamRequest
 	_ = c.ShouldBindQuery(&param)
@@ -68,7 +68,7 @@ func (h *Handler) UserGetChat(c *gin.Context) {
 }
 
 func (h *Handler) SellerGetChat(c *gin.Context) {
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	username := c.Param("username")
 
@@ -102,11 +102,11 @@ func (h *Handler) UserAddChat(c *gin.Context) {
 
 	chat, err := h.chatService.UserAddChat(body, userID, shopSlug)
 	if err != nil {
-		if err == spErr.ErrShopNotFound || err == spErr.ErrUserDoesNotExist || err == spErr.ErrProductDoesNotExist || err == spErr.ErrInvoiceNotFound {
+		if err == spErr.ErrShopNotFound || err == spErr.ErrUserDoesNotExist || err == spErr.ErrProductDoesNotExist || err == spErr.ErrInvoiceNotFound || err == spErr.ErrVoucherNotFound {
 			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
 			return
 		}
-		if err == spErr.ErrSelfMessaging {
+		if err == spErr.ErrSelfMessaging || err == spErr.ErrInvalidChatType || err == spErr.ErrInvalidChatImage || err == spErr.ErrChatVoucherSender || err == spErr.ErrInvalidVoucher {
 			response.Error(c, http.StatusBadRequest, code.BAD_REQUEST, err.Error())
 			return
 		}
@@ -118,7 +118,8 @@ func (h *Handler) UserAddChat(c *gin.Context) {
 }
 
 func (h *Handler) SellerAddChat(c *gin.Context) {
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
+	memberID := c.GetInt("userId")
 
 	username := c.Param("username")
 
@@ -128,13 +129,13 @@ func (h *Handler) SellerAddChat(c *gin.Context) {
 		return
 	}
 
-	chat, err := h.chatService.SellerAddChat(body, userID, username)
+	chat, err := h.chatService.SellerAddChat(body, userID, memberID, username)
 	if err != nil {
-		if err == spErr.ErrShopNotFound || err == spErr.ErrUserDoesNotExist || err == spErr.ErrProductDoesNotExist || err == spErr.ErrInvoiceNotFound {
+		if err == spErr.ErrShopNotFound || err == spErr.ErrUserDoesNotExist || err == spErr.ErrProductDoesNotExist || err == spErr.ErrInvoiceNotFound || err == spErr.ErrVoucherNotFound {
 			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
 			return
 		}
-		if err == spErr.ErrSelfMessaging {
+		if err == spErr.ErrSelfMessaging || err == spErr.ErrInvalidChatType || err == spErr.ErrInvalidChatImage || err == spErr.ErrChatVoucherSender || err == spErr.ErrInvalidVoucher {
 			response.Error(c, http.StatusBadRequest, code.BAD_REQUEST, err.Error())
 			return
 		}
@@ -144,3 +145,59 @@ func (h *Handler) SellerAddChat(c *gin.Context) {
 
 	response.Success(c, http.StatusCreated, code.CREATED, "success", chat)
 }
+
+func (h *Handler) UserReadChat(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	shopSlug := c.Param("shopSlug")
+
+	err := h.chatService.UserReadChat(userID, shopSlug)
+	if err != nil {
+		if err == spErr.ErrShopNotFound {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+		if err == spErr.ErrSelfMessaging {
+			response.Error(c, http.StatusBadRequest, code.BAD_REQUEST, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", nil)
+}
+
+func (h *Handler) SellerReadChat(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	username := c.Param("username")
+
+	err := h.chatService.SellerReadChat(userID, username)
+	if err != nil {
+		if err == spErr.ErrShopNotFound || err == spErr.ErrUserDoesNotExist {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+		if err == spErr.ErrSelfMessaging {
+			response.Error(c, http.StatusBadRequest, code.BAD_REQUEST, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", nil)
+}
+
+func (h *Handler) GetUnreadChatCount(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	unreadCount, err := h.chatService.GetUnreadCount(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", unreadCount)
+}
@@ -205,7 +205,7 @@ func TestSellerGetListOfChats(t *testing.T) {
 			handler := handler.New(&handler.Config{
 				ChatService: mockChatService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 
 			c.Request, _ = http.NewRequest("GET", "/users/chats", nil)
 
@@ -463,7 +463,7 @@ func TestSellerGetChat(t *testing.T) {
 			handler := handler.New(&handler.Config{
 				ChatService: mockChatService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			c.Params = gin.Params{
 				{
 					Key:   "username",
@@ -588,6 +588,26 @@ func TestUserAddChat(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "should return error with code 400 when image is invalid",
+			input: input{
+				userId:   userId,
+				shopSlug: shopSlug,
+				body:     body,
+				result:   nil,
+				err:      errs.ErrInvalidChatImage,
+			},
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("UserAddChat", body, userId, shopSlug).Return(nil, errs.ErrInvalidChatImage)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: errs.ErrInvalidChatImage.Error(),
+				},
+			},
+		},
 		{
 			description: "should return error with code 400 when bad params",
 			input: input{
@@ -657,6 +677,7 @@ func TestUserAddChat(t *testing.T) {
 func TestSellerAddChat(t *testing.T) {
 	var (
 		userId   = 1
+		memberId = 2
 		username = "usernameA"
 		body     = &dto.SendChatBodyRequest{
 			Message: "Hai sayang",
@@ -702,7 +723,7 @@ func TestSellerAddChat(t *testing.T) {
 				err:      nil,
 			},
 			beforeTests: func(cs *mocks.ChatService) {
-				cs.On("SellerAddChat", body, userId, username).Return(chat, nil)
+				cs.On("SellerAddChat", body, userId, memberId, username).Return(chat, nil)
 			},
 			expected: expected{
 				statusCode: http.StatusCreated,
@@ -723,7 +744,7 @@ func TestSellerAddChat(t *testing.T) {
 				err:      errs.ErrShopNotFound,
 			},
 			beforeTests: func(cs *mocks.ChatService) {
-				cs.On("SellerAddChat", body, userId, username).Return(nil, errs.ErrShopNotFound)
+				cs.On("SellerAddChat", body, userId, memberId, username).Return(nil, errs.ErrShopNotFound)
 			},
 			expected: expected{
 				statusCode: http.StatusNotFound,
@@ -743,7 +764,7 @@ func TestSellerAddChat(t *testing.T) {
 				err:      errs.ErrSelfMessaging,
 			},
 			beforeTests: func(cs *mocks.ChatService) {
-				cs.On("SellerAddChat", body, userId, username).Return(nil, errs.ErrSelfMessaging)
+				cs.On("SellerAddChat", body, userId, memberId, username).Return(nil, errs.ErrSelfMessaging)
 			},
 			expected: expected{
 				statusCode: http.StatusBadRequest,
@@ -781,7 +802,7 @@ func TestSellerAddChat(t *testing.T) {
 				err:      errors.New("error"),
 			},
 			beforeTests: func(cs *mocks.ChatService) {
-				cs.On("SellerAddChat", body, userId, username).Return(nil, errors.New("error"))
+				cs.On("SellerAddChat", body, userId, memberId, username).Return(nil, errors.New("error"))
 			},
 			expected: expected{
 				statusCode: http.StatusInternalServerError,
@@ -801,7 +822,8 @@ func TestSellerAddChat(t *testing.T) {
 			handler := handler.New(&handler.Config{
 				ChatService: mockChatService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
+			c.Set("userId", memberId)
 			c.Params = gin.Params{
 				{
 					Key:   "username",
@@ -818,3 +840,217 @@ func TestSellerAddChat(t *testing.T) {
 		})
 	}
 }
+
+func TestUserReadChat(t *testing.T) {
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	type cases struct {
+		description string
+		beforeTests func(cs *mocks.ChatService)
+		expected
+	}
+
+	for _, tc := range []cases{
+		{
+			description: "should return code 200 when messages marked as read",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("UserReadChat", 1, "shop-A").Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+				},
+			},
+		},
+		{
+			description: "should return error with code 404 when shop not found",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("UserReadChat", 1, "shop-A").Return(errs.ErrShopNotFound)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrShopNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 400 when reading own shop conversation",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("UserReadChat", 1, "shop-A").Return(errs.ErrSelfMessaging)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: errs.ErrSelfMessaging.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("UserReadChat", 1, "shop-A").Return(errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTests(mockChatService)
+			handler := handler.New(&handler.Config{
+				ChatService: mockChatService,
+			})
+			c.Set("userId", 1)
+			c.AddParam("shopSlug", "shop-A")
+
+			c.Request, _ = http.NewRequest("PUT", "/users/chats/shop-A/read", nil)
+
+			handler.UserReadChat(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestSellerReadChat(t *testing.T) {
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	type cases struct {
+		description string
+		beforeTests func(cs *mocks.ChatService)
+		expected
+	}
+
+	for _, tc := range []cases{
+		{
+			description: "should return code 200 when messages marked as read",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("SellerReadChat", 1, "user1").Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+				},
+			},
+		},
+		{
+			description: "should return error with code 404 when user not found",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("SellerReadChat", 1, "user1").Return(errs.ErrUserDoesNotExist)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrUserDoesNotExist.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTests(mockChatService)
+			handler := handler.New(&handler.Config{
+				ChatService: mockChatService,
+			})
+			c.Set("shopOwnerId", 1)
+			c.AddParam("username", "user1")
+
+			c.Request, _ = http.NewRequest("PUT", "/sellers/chats/user1/read", nil)
+
+			handler.SellerReadChat(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestGetUnreadChatCount(t *testing.T) {
+	unreadCount := &dto.UnreadCountResponse{Buyer: 1, Seller: 2, Total: 3}
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	type cases struct {
+		description string
+		beforeTests func(cs *mocks.ChatService)
+		expected
+	}
+
+	for _, tc := range []cases{
+		{
+			description: "should return unread count with code 200 when success",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("GetUnreadCount", 1).Return(unreadCount, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    unreadCount,
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			beforeTests: func(cs *mocks.ChatService) {
+				cs.On("GetUnreadCount", 1).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTests(mockChatService)
+			handler := handler.New(&handler.Config{
+				ChatService: mockChatService,
+			})
+			c.Set("userId", 1)
+
+			c.Request, _ = http.NewRequest("GET", "/users/chats/unread-count", nil)
+
+			handler.GetUnreadChatCount(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/common/code"
+	spErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetChatSetting(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	setting, err := h.chatSettingService.GetSetting(userID)
+	if err != nil {
+		if err == spErr.ErrShopNotFound {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", setting)
+}
+
+func (h *Handler) UpdateChatSetting(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	var body dto.ChatSettingRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	setting, err := h.chatSettingService.UpdateSetting(userID, &body)
+	if err != nil {
+		if err == spErr.ErrShopNotFound {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+		if err == spErr.ErrChatAutoReplyMessageRequired || err == spErr.ErrInvalidAwaySchedule {
+			response.Error(c, http.StatusBadRequest, code.BAD_REQUEST, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", setting)
+}
+
+func (h *Handler) GetQuickReplies(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	quickReplies, err := h.chatSettingService.GetQuickReplies(userID)
+	if err != nil {
+		if err == spErr.ErrShopNotFound {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", quickReplies)
+}
+
+func (h *Handler) CreateQuickReply(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	var body dto.QuickReplyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	quickReply, err := h.chatSettingService.CreateQuickReply(userID, &body)
+	if err != nil {
+		quickReplyError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "success", quickReply)
+}
+
+func (h *Handler) UpdateQuickReply(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	quickReplyID, err := strconv.Atoi(c.Param("quickReplyId"))
+	if err != nil || quickReplyID < 1 {
+		response.Error(c, http.StatusNotFound, code.NOT_FOUND, spErr.ErrQuickReplyNotFound.Error())
+		return
+	}
+
+	var body dto.QuickReplyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	quickReply, err := h.chatSettingService.UpdateQuickReply(userID, quickReplyID, &body)
+	if err != nil {
+		quickReplyError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", quickReply)
+}
+
+func (h *Handler) DeleteQuickReply(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	quickReplyID, err := strconv.Atoi(c.Param("quickReplyId"))
+	if err != nil || quickReplyID < 1 {
+		response.Error(c, http.StatusNotFound, code.NOT_FOUND, spErr.ErrQuickReplyNotFound.Error())
+		return
+	}
+
+	err = h.chatSettingService.DeleteQuickReply(userID, quickReplyID)
+	if err != nil {
+		quickReplyError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", nil)
+}
+
+func quickReplyError(c *gin.Context, err error) {
+	if err == spErr.ErrShopNotFound || err == spErr.ErrQuickReplyNotFound {
+		response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+		return
+	}
+	if err == spErr.ErrQuickReplyAlreadyExist {
+		response.Error(c, http.StatusConflict, code.DUPLICATE_QUICK_REPLY, err.Error())
+		return
+	}
+	if err == spErr.ErrQuickReplyLimitReached {
+		response.Error(c, http.StatusBadRequest, code.BAD_REQUEST, err.Error())
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+}
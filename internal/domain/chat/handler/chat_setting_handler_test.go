@@ -0,0 +1,282 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	"kedai/backend/be-kedai/internal/domain/chat/handler"
+	"kedai/backend/be-kedai/internal/domain/chat/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testutil "kedai/backend/be-kedai/internal/utils/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateChatSetting(t *testing.T) {
+	var (
+		userId  = 1
+		body    = &dto.ChatSettingRequest{IsAutoReplyEnabled: true, AutoReplyMessage: "Halo!"}
+		setting = &model.ChatSetting{ShopID: 3, IsAutoReplyEnabled: true, AutoReplyMessage: "Halo!"}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		beforeTests func(css *mocks.ChatSettingService)
+		expected
+	}{
+		{
+			description: "should return chat setting with code 200 when success",
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("UpdateSetting", userId, body).Return(setting, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    setting,
+				},
+			},
+		},
+		{
+			description: "should return error with code 400 when message is missing",
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("UpdateSetting", userId, body).Return(nil, errs.ErrChatAutoReplyMessageRequired)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: errs.ErrChatAutoReplyMessageRequired.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 404 when shop not found",
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("UpdateSetting", userId, body).Return(nil, errs.ErrShopNotFound)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrShopNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("UpdateSetting", userId, body).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockChatSettingService := mocks.NewChatSettingService(t)
+			tc.beforeTests(mockChatSettingService)
+			handler := handler.New(&handler.Config{
+				ChatSettingService: mockChatSettingService,
+			})
+			c.Set("shopOwnerId", userId)
+			c.Request, _ = http.NewRequest("PUT", "/sellers/chats/settings", testutil.MakeRequestBody(body))
+
+			handler.UpdateChatSetting(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestCreateQuickReply(t *testing.T) {
+	var (
+		userId     = 1
+		body       = &dto.QuickReplyRequest{Shortcut: "ongkir", Message: "Ongkir gratis se-Jawa"}
+		quickReply = &model.ChatQuickReply{ShopID: 3, Shortcut: "ongkir", Message: "Ongkir gratis se-Jawa"}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		body        *dto.QuickReplyRequest
+		beforeTests func(css *mocks.ChatSettingService)
+		expected
+	}{
+		{
+			description: "should return quick reply with code 201 when success",
+			body:        body,
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("CreateQuickReply", userId, body).Return(quickReply, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "success",
+					Data:    quickReply,
+				},
+			},
+		},
+		{
+			description: "should return error with code 400 when bad request",
+			body:        &dto.QuickReplyRequest{Shortcut: "ongkir"},
+			beforeTests: func(css *mocks.ChatSettingService) {},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Message is required",
+				},
+			},
+		},
+		{
+			description: "should return error with code 409 when shortcut already exists",
+			body:        body,
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("CreateQuickReply", userId, body).Return(nil, errs.ErrQuickReplyAlreadyExist)
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.DUPLICATE_QUICK_REPLY,
+					Message: errs.ErrQuickReplyAlreadyExist.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 400 when limit reached",
+			body:        body,
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("CreateQuickReply", userId, body).Return(nil, errs.ErrQuickReplyLimitReached)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: errs.ErrQuickReplyLimitReached.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockChatSettingService := mocks.NewChatSettingService(t)
+			tc.beforeTests(mockChatSettingService)
+			handler := handler.New(&handler.Config{
+				ChatSettingService: mockChatSettingService,
+			})
+			c.Set("shopOwnerId", userId)
+			c.Request, _ = http.NewRequest("POST", "/sellers/chats/quick-replies", testutil.MakeRequestBody(tc.body))
+
+			handler.CreateQuickReply(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestDeleteQuickReply(t *testing.T) {
+	var userId = 1
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description  string
+		quickReplyId string
+		beforeTests  func(css *mocks.ChatSettingService)
+		expected
+	}{
+		{
+			description:  "should return code 200 when success",
+			quickReplyId: "5",
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("DeleteQuickReply", userId, 5).Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+				},
+			},
+		},
+		{
+			description:  "should return error with code 404 when id is invalid",
+			quickReplyId: "abc",
+			beforeTests:  func(css *mocks.ChatSettingService) {},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrQuickReplyNotFound.Error(),
+				},
+			},
+		},
+		{
+			description:  "should return error with code 404 when quick reply not found",
+			quickReplyId: "5",
+			beforeTests: func(css *mocks.ChatSettingService) {
+				css.On("DeleteQuickReply", userId, 5).Return(errs.ErrQuickReplyNotFound)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrQuickReplyNotFound.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockChatSettingService := mocks.NewChatSettingService(t)
+			tc.beforeTests(mockChatSettingService)
+			handler := handler.New(&handler.Config{
+				ChatSettingService: mockChatSettingService,
+			})
+			c.Set("shopOwnerId", userId)
+			c.AddParam("quickReplyId", tc.quickReplyId)
+			c.Request, _ = http.NewRequest("DELETE", "/sellers/chats/quick-replies/"+tc.quickReplyId, nil)
+
+			handler.DeleteQuickReply(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
@@ -2,18 +2,29 @@ package handler
 
 import (
 	"kedai/backend/be-kedai/internal/domain/chat/service"
+	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
+	userService "kedai/backend/be-kedai/internal/domain/user/service"
 )
 
 type Handler struct {
-	chatService service.ChatService
+	chatService        service.ChatService
+	chatSettingService service.ChatSettingService
+	userService        userService.UserService
+	shopMemberService  shopService.ShopMemberService
 }
 
 type Config struct {
-	ChatService service.ChatService
+	ChatService        service.ChatService
+	ChatSettingService service.ChatSettingService
+	UserService        userService.UserService
+	ShopMemberService  shopService.ShopMemberService
 }
 
 func New(cfg *Config) *Handler {
 	return &Handler{
-		chatService: cfg.ChatService,
+		chatService:        cfg.ChatService,
+		chatSettingService: cfg.ChatSettingService,
+		userService:        cfg.UserService,
+		shopMemberService:  cfg.ShopMemberService,
 	}
 }
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	spErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"log"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	socketio "github.com/googollee/go-socket.io"
+)
+
+const socketNamespace = "/"
+
+func (h *Handler) RegisterSocketEvents(server *socketio.Server) {
+	server.OnConnect(socketNamespace, h.SocketConnect)
+	server.OnDisconnect(socketNamespace, h.SocketDisconnect)
+	server.OnEvent(socketNamespace, "heartbeat", h.SocketHeartbeat)
+	server.OnEvent(socketNamespace, "join-room", h.SocketJoinRoom)
+	server.OnEvent(socketNamespace, "leave-room", h.SocketLeaveRoom)
+	server.OnEvent(socketNamespace, "send-message", h.SocketSendMessage)
+	server.OnEvent(socketNamespace, "read-messages", h.SocketReadMessages)
+	server.OnEvent(socketNamespace, "delivered", h.SocketDeliverMessages)
+	server.OnEvent(socketNamespace, "typing", h.SocketTyping)
+}
+
+// SocketConnect accepts the same access token as middleware.JWTAuthorization,
+// either from the Authorization header or the "token" query parameter since
+// browsers cannot set headers on a websocket handshake. Like the REST API it
+// also requires the session of the token to still be signed in.
+func (h *Handler) SocketConnect(s socketio.Conn) error {
+	auth := s.RemoteHeader().Get("authorization")
+	if auth == "" {
+		handshakeURL := s.URL()
+		auth = handshakeURL.Query().Get("token")
+	}
+
+	if auth == "" {
+		return spErr.ErrInvalidToken
+	}
+
+	auth = strings.Replace(auth, "Bearer ", "", -1)
+
+	parsedToken, err := jwttoken.ValidateToken(auth)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return spErr.ErrExpiredToken
+		}
+		return err
+	}
+
+	if h.userService != nil {
+		if _, err := h.userService.GetSession(parsedToken.UserId, auth); err != nil {
+			return spErr.ErrSocketUnauthorized
+		}
+	}
+
+	session := &dto.SocketSession{
+		UserID:      parsedToken.UserId,
+		ConnID:      uuid.New().String(),
+		AccessToken: auth,
+	}
+	s.SetContext(session)
+	s.Join(notificationDto.UserRoomID(session.UserID))
+	log.Println("SocketIO client connected:", s.ID(), "user", session.UserID)
+
+	if err := h.chatService.MarkOnline(session.UserID, session.ConnID); err != nil {
+		log.Println("SocketIO failed to mark user online:", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) SocketDisconnect(s socketio.Conn, msg string) {
+	log.Println("SocketIO Client disconnected:", s.ID(), "-", msg)
+
+	session, ok := s.Context().(*dto.SocketSession)
+	if !ok {
+		return
+	}
+
+	if err := h.chatService.MarkOffline(session.UserID, session.ConnID); err != nil {
+		log.Println("SocketIO failed to mark user offline:", err)
+	}
+}
+
+// SocketHeartbeat keeps the connection counted as online; clients should emit
+// it more often than constant.ChatPresenceTTL.
+func (h *Handler) SocketHeartbeat(s socketio.Conn) response.Response {
+	session, err := h.socketSession(s)
+	if err != nil {
+		return socketError(err)
+	}
+
+	if err := h.chatService.MarkOnline(session.UserID, session.ConnID); err != nil {
+		return socketError(err)
+	}
+
+	return response.Response{Code: code.OK, Message: "success"}
+}
+
+func (h *Handler) SocketJoinRoom(s socketio.Conn, req dto.SocketRoomRequest) response.Response {
+	session, err := h.socketSession(s)
+	if err != nil {
+		return socketError(err)
+	}
+
+	var room string
+	switch {
+	case req.ShopSlug != "":
+		room, err = h.chatService.UserJoinRoom(session.UserID, req.ShopSlug)
+	case req.Username != "":
+		var ownerID int
+		if ownerID, err = h.socketShopOwner(session.UserID, req); err == nil {
+			room, err = h.chatService.SellerJoinRoom(ownerID, req.Username)
+		}
+	default:
+		err = spErr.ErrInvalidChatRoom
+	}
+	if err != nil {
+		return socketError(err)
+	}
+
+	s.Join(room)
+
+	// Anything already sent in this conversation reaches the client once it
+	// joins, so acknowledge delivery on its behalf.
+	if err := h.deliver(session.UserID, req); err != nil {
+		log.Println("SocketIO failed to mark chats delivered:", err)
+	}
+
+	return response.Response{Code: code.OK, Message: "success", Data: room}
+}
+
+func (h *Handler) SocketReadMessages(s socketio.Conn, req dto.SocketRoomRequest) response.Response {
+	session, err := h.socketSession(s)
+	if err != nil {
+		return socketError(err)
+	}
+
+	switch {
+	case req.ShopSlug != "":
+		err = h.chatService.UserReadChat(session.UserID, req.ShopSlug)
+	case req.Username != "":
+		var ownerID int
+		if ownerID, err = h.socketShopOwner(session.UserID, req); err == nil {
+			err = h.chatService.SellerReadChat(ownerID, req.Username)
+		}
+	default:
+		err = spErr.ErrInvalidChatRoom
+	}
+	if err != nil {
+		return socketError(err)
+	}
+
+	return response.Response{Code: code.OK, Message: "success"}
+}
+
+func (h *Handler) SocketDeliverMessages(s socketio.Conn, req dto.SocketRoomRequest) response.Response {
+	session, err := h.socketSession(s)
+	if err != nil {
+		return socketError(err)
+	}
+
+	if err := h.deliver(session.UserID, req); err != nil {
+		return socketError(err)
+	}
+
+	return response.Response{Code: code.OK, Message: "success"}
+}
+
+func (h *Handler) SocketTyping(s socketio.Conn, req dto.SocketTypingRequest) response.Response {
+	session, err := h.socketSession(s)
+	if err != nil {
+		return socketError(err)
+	}
+
+	switch {
+	case req.ShopSlug != "":
+		err = h.chatService.UserTyping(session.UserID, req.ShopSlug, req.IsTyping)
+	case req.Username != "":
+		var ownerID int
+		if ownerID, err = h.socketShopOwner(session.UserID, req.SocketRoomRequest); err == nil {
+			err = h.chatService.SellerTyping(ownerID, req.Username, req.IsTyping)
+		}
+	default:
+		err = spErr.ErrInvalidChatRoom
+	}
+	if err != nil {
+		return socketError(err)
+	}
+
+	return response.Response{Code: code.OK, Message: "success"}
+}
+
+func (h *Handler) deliver(userID int, req dto.SocketRoomRequest) error {
+	switch {
+	case req.ShopSlug != "":
+		return h.chatService.UserDeliverChat(userID, req.ShopSlug)
+	case req.Username != "":
+		ownerID, err := h.socketShopOwner(userID, req)
+		if err != nil {
+			return err
+		}
+		return h.chatService.SellerDeliverChat(ownerID, req.Username)
+	default:
+		return spErr.ErrInvalidChatRoom
+	}
+}
+
+func (h *Handler) SocketLeaveRoom(s socketio.Conn, room string) response.Response {
+	if _, err := h.socketSession(s); err != nil {
+		return socketError(err)
+	}
+
+	s.Leave(room)
+
+	return response.Response{Code: code.OK, Message: "success", Data: room}
+}
+
+func (h *Handler) SocketSendMessage(s socketio.Conn, req dto.SocketSendChatRequest) response.Response {
+	session, err := h.socketSession(s)
+	if err != nil {
+		return socketError(err)
+	}
+	userID := session.UserID
+
+	if req.Message == "" {
+		return response.Response{Code: code.BAD_REQUEST, Message: "Message is required"}
+	}
+
+	var chat *dto.ChatResponse
+	switch {
+	case req.ShopSlug != "":
+		chat, err = h.chatService.UserAddChat(req.ToSendChatBodyRequest(), userID, req.ShopSlug)
+	case req.Username != "":
+		var ownerID int
+		if ownerID, err = h.socketShopOwner(userID, req.SocketRoomRequest); err == nil {
+			chat, err = h.chatService.SellerAddChat(req.ToSendChatBodyRequest(), ownerID, userID, req.Username)
+		}
+	default:
+		err = spErr.ErrInvalidChatRoom
+	}
+	if err != nil {
+		return socketError(err)
+	}
+
+	return response.Response{Code: code.CREATED, Message: "success", Data: chat}
+}
+
+// socketSession returns the session the connection was opened with. Every
+// event checks it is still signed in, so a connection is closed once its
+// session is revoked or its access token expires; clients reconnect with the
+// renewed token.
+func (h *Handler) socketSession(s socketio.Conn) (*dto.SocketSession, error) {
+	session, ok := s.Context().(*dto.SocketSession)
+	if !ok {
+		return nil, spErr.ErrSocketUnauthorized
+	}
+
+	if h.userService != nil {
+		if _, err := h.userService.GetSession(session.UserID, session.AccessToken); err != nil {
+			log.Println("SocketIO closing connection of revoked session:", s.ID(), "user", session.UserID)
+			s.Close()
+			return nil, spErr.ErrSocketUnauthorized
+		}
+	}
+
+	return session, nil
+}
+
+// socketShopOwner resolves the shop a seller event acts for the same way
+// GetShopMember does for seller routes, with req.ShopID in place of the
+// X-Shop-Id header, and returns its owner for the chat service to look the
+// shop up by.
+func (h *Handler) socketShopOwner(userID int, req dto.SocketRoomRequest) (int, error) {
+	if h.shopMemberService == nil {
+		return userID, nil
+	}
+
+	membership, err := h.shopMemberService.GetMembership(userID, req.ShopID)
+	if err != nil {
+		return 0, err
+	}
+	if !membership.Can(constant.ShopPermissionManageChats) {
+		return 0, spErr.ErrShopPermissionDenied
+	}
+
+	return membership.OwnerID, nil
+}
+
+func socketError(err error) response.Response {
+	if err == spErr.ErrSocketUnauthorized {
+		return response.Response{Code: code.UNAUTHORIZED, Message: err.Error()}
+	}
+	if err == spErr.ErrShopNotFound || err == spErr.ErrUserDoesNotExist || err == spErr.ErrProductDoesNotExist || err == spErr.ErrInvoiceNotFound || err == spErr.ErrVoucherNotFound {
+		return response.Response{Code: code.NOT_FOUND, Message: err.Error()}
+	}
+	if err == spErr.ErrShopPermissionDenied {
+		return response.Response{Code: code.FORBIDDEN, Message: err.Error()}
+	}
+	if err == spErr.ErrSelfMessaging || err == spErr.ErrInvalidChatRoom || err == spErr.ErrInvalidChatType || err == spErr.ErrInvalidChatImage || err == spErr.ErrChatVoucherSender || err == spErr.ErrInvalidVoucher {
+		return response.Response{Code: code.BAD_REQUEST, Message: err.Error()}
+	}
+	return response.Response{Code: code.INTERNAL_SERVER_ERROR, Message: spErr.ErrInternalServerError.Error()}
+}
@@ -0,0 +1,541 @@
+package handler_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	"kedai/backend/be-kedai/internal/domain/chat/handler"
+	shopDto "kedai/backend/be-kedai/internal/domain/shop/dto"
+	userModel "kedai/backend/be-kedai/internal/domain/user/model"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeSocketConn struct {
+	context interface{}
+	header  http.Header
+	url     url.URL
+	rooms   []string
+	closed  bool
+}
+
+func (f *fakeSocketConn) Close() error                            { f.closed = true; return nil }
+func (f *fakeSocketConn) Context() interface{}                    { return f.context }
+func (f *fakeSocketConn) SetContext(ctx interface{})              { f.context = ctx }
+func (f *fakeSocketConn) Namespace() string                       { return "/" }
+func (f *fakeSocketConn) Emit(eventName string, v ...interface{}) {}
+func (f *fakeSocketConn) Join(room string)                        { f.rooms = append(f.rooms, room) }
+func (f *fakeSocketConn) Leave(room string) {
+	for i, r := range f.rooms {
+		if r == room {
+			f.rooms = append(f.rooms[:i], f.rooms[i+1:]...)
+			return
+		}
+	}
+}
+func (f *fakeSocketConn) LeaveAll()                 { f.rooms = nil }
+func (f *fakeSocketConn) Rooms() []string           { return f.rooms }
+func (f *fakeSocketConn) ID() string                { return "1" }
+func (f *fakeSocketConn) URL() url.URL              { return f.url }
+func (f *fakeSocketConn) LocalAddr() net.Addr       { return nil }
+func (f *fakeSocketConn) RemoteAddr() net.Addr      { return nil }
+func (f *fakeSocketConn) RemoteHeader() http.Header { return f.header }
+
+func signSocketToken(tokenType string, expiresAt time.Time) string {
+	claims := &userModel.Claim{
+		UserId:    1,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, _ := jwttoken.Sign(claims)
+	return token
+}
+
+func TestSocketConnect(t *testing.T) {
+	test.UseJWTKeySet(t)
+
+	tests := []struct {
+		description string
+		conn        *fakeSocketConn
+		userID      int
+		err         error
+	}{
+		{
+			description: "should reject connection without token",
+			conn:        &fakeSocketConn{header: http.Header{}},
+			err:         errs.ErrInvalidToken,
+		},
+		{
+			description: "should reject connection with expired token",
+			conn: &fakeSocketConn{header: http.Header{
+				"Authorization": []string{"Bearer " + signSocketToken("access", time.Now().Add(-time.Minute))},
+			}},
+			err: errs.ErrExpiredToken,
+		},
+		{
+			description: "should reject connection with refresh token",
+			conn: &fakeSocketConn{header: http.Header{
+				"Authorization": []string{"Bearer " + signSocketToken("refresh", time.Now().Add(time.Hour))},
+			}},
+			err: jwt.ErrTokenInvalidClaims,
+		},
+		{
+			description: "should accept connection with access token from header",
+			conn: &fakeSocketConn{header: http.Header{
+				"Authorization": []string{"Bearer " + signSocketToken("access", time.Now().Add(time.Hour))},
+			}},
+			userID: 1,
+		},
+		{
+			description: "should accept connection with access token from query",
+			conn: &fakeSocketConn{
+				header: http.Header{},
+				url:    url.URL{RawQuery: "token=" + signSocketToken("access", time.Now().Add(time.Hour))},
+			},
+			userID: 1,
+		},
+	}
+
+	t.Run("should reject connection when session was signed out", func(t *testing.T) {
+		token := signSocketToken("access", time.Now().Add(time.Hour))
+		mockUserService := mocks.NewUserService(t)
+		mockUserService.On("GetSession", 1, token).Return("", errors.New("redis: nil"))
+		h := handler.New(&handler.Config{ChatService: mocks.NewChatService(t), UserService: mockUserService})
+		conn := &fakeSocketConn{header: http.Header{"Authorization": []string{"Bearer " + token}}}
+
+		err := h.SocketConnect(conn)
+
+		assert.Equal(t, errs.ErrSocketUnauthorized, err)
+		assert.Nil(t, conn.context)
+	})
+
+	t.Run("should keep the access token of a signed in session", func(t *testing.T) {
+		token := signSocketToken("access", time.Now().Add(time.Hour))
+		mockUserService := mocks.NewUserService(t)
+		mockUserService.On("GetSession", 1, token).Return("session", nil)
+		mockChatService := mocks.NewChatService(t)
+		mockChatService.On("MarkOnline", 1, mock.AnythingOfType("string")).Return(nil)
+		h := handler.New(&handler.Config{ChatService: mockChatService, UserService: mockUserService})
+		conn := &fakeSocketConn{header: http.Header{"Authorization": []string{"Bearer " + token}}}
+
+		err := h.SocketConnect(conn)
+
+		assert.Nil(t, err)
+		assert.Equal(t, token, conn.context.(*dto.SocketSession).AccessToken)
+	})
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockChatService := mocks.NewChatService(t)
+			if tc.userID != 0 {
+				mockChatService.On("MarkOnline", tc.userID, mock.AnythingOfType("string")).Return(nil)
+			}
+			h := handler.New(&handler.Config{ChatService: mockChatService})
+
+			err := h.SocketConnect(tc.conn)
+
+			assert.Equal(t, tc.err, err)
+			if tc.userID == 0 {
+				assert.Nil(t, tc.conn.context)
+				return
+			}
+			session := tc.conn.context.(*dto.SocketSession)
+			assert.Equal(t, tc.userID, session.UserID)
+			assert.NotEmpty(t, session.ConnID)
+			assert.Contains(t, tc.conn.rooms, "user:1")
+		})
+	}
+}
+
+func TestSocketJoinRoom(t *testing.T) {
+	tests := []struct {
+		description string
+		context     interface{}
+		req         dto.SocketRoomRequest
+		beforeTest  func(*mocks.ChatService)
+		expected    response.Response
+		rooms       []string
+	}{
+		{
+			description: "should reject unauthenticated connection",
+			req:         dto.SocketRoomRequest{ShopSlug: "shop-A"},
+			beforeTest:  func(cs *mocks.ChatService) {},
+			expected:    response.Response{Code: code.UNAUTHORIZED, Message: errs.ErrSocketUnauthorized.Error()},
+		},
+		{
+			description: "should reject request without target",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			beforeTest:  func(cs *mocks.ChatService) {},
+			expected:    response.Response{Code: code.BAD_REQUEST, Message: errs.ErrInvalidChatRoom.Error()},
+		},
+		{
+			description: "should reject room of unknown shop",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			req:         dto.SocketRoomRequest{ShopSlug: "shop-A"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("UserJoinRoom", 1, "shop-A").Return("", errs.ErrShopNotFound)
+			},
+			expected: response.Response{Code: code.NOT_FOUND, Message: errs.ErrShopNotFound.Error()},
+		},
+		{
+			description: "should join user room",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			req:         dto.SocketRoomRequest{ShopSlug: "shop-A"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("UserJoinRoom", 1, "shop-A").Return("chat:1:3", nil)
+				cs.On("UserDeliverChat", 1, "shop-A").Return(nil)
+			},
+			expected: response.Response{Code: code.OK, Message: "success", Data: "chat:1:3"},
+			rooms:    []string{"chat:1:3"},
+		},
+		{
+			description: "should join seller room",
+			context:     &dto.SocketSession{UserID: 2, ConnID: "conn-2"},
+			req:         dto.SocketRoomRequest{Username: "user1"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("SellerJoinRoom", 2, "user1").Return("chat:1:3", nil)
+				cs.On("SellerDeliverChat", 2, "user1").Return(errors.New("db down"))
+			},
+			expected: response.Response{Code: code.OK, Message: "success", Data: "chat:1:3"},
+			rooms:    []string{"chat:1:3"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTest(mockChatService)
+			h := handler.New(&handler.Config{ChatService: mockChatService})
+			conn := &fakeSocketConn{context: tc.context}
+
+			res := h.SocketJoinRoom(conn, tc.req)
+
+			assert.Equal(t, tc.expected, res)
+			assert.Equal(t, tc.rooms, conn.rooms)
+		})
+	}
+}
+
+func TestSocketSendMessage(t *testing.T) {
+	chat := &dto.ChatResponse{ID: 10, Message: "hai"}
+	tests := []struct {
+		description string
+		context     interface{}
+		req         dto.SocketSendChatRequest
+		beforeTest  func(*mocks.ChatService)
+		expected    response.Response
+	}{
+		{
+			description: "should reject unauthenticated connection",
+			req:         dto.SocketSendChatRequest{SocketRoomRequest: dto.SocketRoomRequest{ShopSlug: "shop-A"}, Message: "hai"},
+			beforeTest:  func(cs *mocks.ChatService) {},
+			expected:    response.Response{Code: code.UNAUTHORIZED, Message: errs.ErrSocketUnauthorized.Error()},
+		},
+		{
+			description: "should reject empty message",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			req:         dto.SocketSendChatRequest{SocketRoomRequest: dto.SocketRoomRequest{ShopSlug: "shop-A"}},
+			beforeTest:  func(cs *mocks.ChatService) {},
+			expected:    response.Response{Code: code.BAD_REQUEST, Message: "Message is required"},
+		},
+		{
+			description: "should reject self messaging",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			req:         dto.SocketSendChatRequest{SocketRoomRequest: dto.SocketRoomRequest{ShopSlug: "shop-A"}, Message: "hai"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("UserAddChat", &dto.SendChatBodyRequest{Message: "hai"}, 1, "shop-A").Return(nil, errs.ErrSelfMessaging)
+			},
+			expected: response.Response{Code: code.BAD_REQUEST, Message: errs.ErrSelfMessaging.Error()},
+		},
+		{
+			description: "should persist user message through chat service",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			req:         dto.SocketSendChatRequest{SocketRoomRequest: dto.SocketRoomRequest{ShopSlug: "shop-A"}, Message: "hai", Type: "text"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("UserAddChat", &dto.SendChatBodyRequest{Message: "hai", Type: "text"}, 1, "shop-A").Return(chat, nil)
+			},
+			expected: response.Response{Code: code.CREATED, Message: "success", Data: chat},
+		},
+		{
+			description: "should persist seller message through chat service",
+			context:     &dto.SocketSession{UserID: 2, ConnID: "conn-2"},
+			req:         dto.SocketSendChatRequest{SocketRoomRequest: dto.SocketRoomRequest{Username: "user1"}, Message: "hai", Type: "text"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("SellerAddChat", &dto.SendChatBodyRequest{Message: "hai", Type: "text"}, 2, 2, "user1").Return(chat, nil)
+			},
+			expected: response.Response{Code: code.CREATED, Message: "success", Data: chat},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTest(mockChatService)
+			h := handler.New(&handler.Config{ChatService: mockChatService})
+
+			res := h.SocketSendMessage(&fakeSocketConn{context: tc.context}, tc.req)
+
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestSocketSellerMembership(t *testing.T) {
+	chat := &dto.ChatResponse{ID: 10, Message: "hai"}
+
+	t.Run("should send as the shop the member acts for", func(t *testing.T) {
+		mockChatService := mocks.NewChatService(t)
+		mockShopMemberService := mocks.NewShopMemberService(t)
+		mockShopMemberService.On("GetMembership", 2, 3).Return(shopDto.NewShopMembership(3, 1, 2, constant.ShopRoleChatAgent), nil)
+		mockChatService.On("SellerAddChat", &dto.SendChatBodyRequest{Message: "hai"}, 1, 2, "user1").Return(chat, nil)
+		h := handler.New(&handler.Config{ChatService: mockChatService, ShopMemberService: mockShopMemberService})
+		req := dto.SocketSendChatRequest{SocketRoomRequest: dto.SocketRoomRequest{Username: "user1", ShopID: 3}, Message: "hai"}
+
+		res := h.SocketSendMessage(&fakeSocketConn{context: &dto.SocketSession{UserID: 2, ConnID: "conn-2"}}, req)
+
+		assert.Equal(t, response.Response{Code: code.CREATED, Message: "success", Data: chat}, res)
+	})
+
+	t.Run("should join the room of the shop the member acts for", func(t *testing.T) {
+		mockChatService := mocks.NewChatService(t)
+		mockShopMemberService := mocks.NewShopMemberService(t)
+		mockShopMemberService.On("GetMembership", 2, 3).Return(shopDto.NewShopMembership(3, 1, 2, constant.ShopRoleChatAgent), nil)
+		mockChatService.On("SellerJoinRoom", 1, "user1").Return("chat:4:3", nil)
+		mockChatService.On("SellerDeliverChat", 1, "user1").Return(nil)
+		h := handler.New(&handler.Config{ChatService: mockChatService, ShopMemberService: mockShopMemberService})
+		conn := &fakeSocketConn{context: &dto.SocketSession{UserID: 2, ConnID: "conn-2"}}
+
+		res := h.SocketJoinRoom(conn, dto.SocketRoomRequest{Username: "user1", ShopID: 3})
+
+		assert.Equal(t, response.Response{Code: code.OK, Message: "success", Data: "chat:4:3"}, res)
+		assert.Equal(t, []string{"chat:4:3"}, conn.rooms)
+	})
+
+	t.Run("should reject a member whose role cannot manage chats", func(t *testing.T) {
+		mockShopMemberService := mocks.NewShopMemberService(t)
+		mockShopMemberService.On("GetMembership", 2, 3).Return(shopDto.NewShopMembership(3, 1, 2, constant.ShopRoleOrderOps), nil)
+		h := handler.New(&handler.Config{ChatService: mocks.NewChatService(t), ShopMemberService: mockShopMemberService})
+		conn := &fakeSocketConn{context: &dto.SocketSession{UserID: 2, ConnID: "conn-2"}}
+
+		res := h.SocketJoinRoom(conn, dto.SocketRoomRequest{Username: "user1", ShopID: 3})
+
+		assert.Equal(t, response.Response{Code: code.FORBIDDEN, Message: errs.ErrShopPermissionDenied.Error()}, res)
+		assert.Empty(t, conn.rooms)
+	})
+
+	t.Run("should reject a user who is not a member of the shop", func(t *testing.T) {
+		mockShopMemberService := mocks.NewShopMemberService(t)
+		mockShopMemberService.On("GetMembership", 2, 3).Return(nil, errs.ErrShopNotFound)
+		h := handler.New(&handler.Config{ChatService: mocks.NewChatService(t), ShopMemberService: mockShopMemberService})
+		req := dto.SocketSendChatRequest{SocketRoomRequest: dto.SocketRoomRequest{Username: "user1", ShopID: 3}, Message: "hai"}
+
+		res := h.SocketSendMessage(&fakeSocketConn{context: &dto.SocketSession{UserID: 2, ConnID: "conn-2"}}, req)
+
+		assert.Equal(t, response.Response{Code: code.NOT_FOUND, Message: errs.ErrShopNotFound.Error()}, res)
+	})
+}
+
+func TestSocketHeartbeat(t *testing.T) {
+	tests := []struct {
+		description string
+		context     interface{}
+		beforeTest  func(*mocks.ChatService)
+		expected    response.Response
+	}{
+		{
+			description: "should reject unauthenticated connection",
+			beforeTest:  func(cs *mocks.ChatService) {},
+			expected:    response.Response{Code: code.UNAUTHORIZED, Message: errs.ErrSocketUnauthorized.Error()},
+		},
+		{
+			description: "should return error when presence could not be refreshed",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("MarkOnline", 1, "conn-1").Return(errors.New("redis down"))
+			},
+			expected: response.Response{Code: code.INTERNAL_SERVER_ERROR, Message: errs.ErrInternalServerError.Error()},
+		},
+		{
+			description: "should refresh presence",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("MarkOnline", 1, "conn-1").Return(nil)
+			},
+			expected: response.Response{Code: code.OK, Message: "success"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTest(mockChatService)
+			h := handler.New(&handler.Config{ChatService: mockChatService})
+
+			res := h.SocketHeartbeat(&fakeSocketConn{context: tc.context})
+
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestSocketRevokedSession(t *testing.T) {
+	t.Run("should close connection once its session is revoked", func(t *testing.T) {
+		mockUserService := mocks.NewUserService(t)
+		mockUserService.On("GetSession", 1, "access").Return("", errors.New("redis: nil"))
+		h := handler.New(&handler.Config{ChatService: mocks.NewChatService(t), UserService: mockUserService})
+		conn := &fakeSocketConn{context: &dto.SocketSession{UserID: 1, ConnID: "conn-1", AccessToken: "access"}}
+
+		res := h.SocketHeartbeat(conn)
+
+		assert.Equal(t, response.Response{Code: code.UNAUTHORIZED, Message: errs.ErrSocketUnauthorized.Error()}, res)
+		assert.True(t, conn.closed)
+	})
+
+	t.Run("should keep connection of a signed in session", func(t *testing.T) {
+		mockUserService := mocks.NewUserService(t)
+		mockUserService.On("GetSession", 1, "access").Return("session", nil)
+		mockChatService := mocks.NewChatService(t)
+		mockChatService.On("MarkOnline", 1, "conn-1").Return(nil)
+		h := handler.New(&handler.Config{ChatService: mockChatService, UserService: mockUserService})
+		conn := &fakeSocketConn{context: &dto.SocketSession{UserID: 1, ConnID: "conn-1", AccessToken: "access"}}
+
+		res := h.SocketHeartbeat(conn)
+
+		assert.Equal(t, response.Response{Code: code.OK, Message: "success"}, res)
+		assert.False(t, conn.closed)
+	})
+}
+
+func TestSocketDisconnect(t *testing.T) {
+	t.Run("should mark connection offline", func(t *testing.T) {
+		mockChatService := mocks.NewChatService(t)
+		mockChatService.On("MarkOffline", 1, "conn-1").Return(nil)
+		h := handler.New(&handler.Config{ChatService: mockChatService})
+
+		h.SocketDisconnect(&fakeSocketConn{context: &dto.SocketSession{UserID: 1, ConnID: "conn-1"}}, "client namespace disconnect")
+	})
+
+	t.Run("should ignore unauthenticated connection", func(t *testing.T) {
+		mockChatService := mocks.NewChatService(t)
+		h := handler.New(&handler.Config{ChatService: mockChatService})
+
+		h.SocketDisconnect(&fakeSocketConn{}, "client namespace disconnect")
+	})
+}
+
+func TestSocketReadMessages(t *testing.T) {
+	tests := []struct {
+		description string
+		context     interface{}
+		req         dto.SocketRoomRequest
+		beforeTest  func(*mocks.ChatService)
+		expected    response.Response
+	}{
+		{
+			description: "should reject unauthenticated connection",
+			req:         dto.SocketRoomRequest{ShopSlug: "shop-A"},
+			beforeTest:  func(cs *mocks.ChatService) {},
+			expected:    response.Response{Code: code.UNAUTHORIZED, Message: errs.ErrSocketUnauthorized.Error()},
+		},
+		{
+			description: "should mark user conversation read",
+			context:     &dto.SocketSession{UserID: 1, ConnID: "conn-1"},
+			req:         dto.SocketRoomRequest{ShopSlug: "shop-A"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("UserReadChat", 1, "shop-A").Return(nil)
+			},
+			expected: response.Response{Code: code.OK, Message: "success"},
+		},
+		{
+			description: "should mark seller conversation read",
+			context:     &dto.SocketSession{UserID: 2, ConnID: "conn-2"},
+			req:         dto.SocketRoomRequest{Username: "user1"},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("SellerReadChat", 2, "user1").Return(errs.ErrUserDoesNotExist)
+			},
+			expected: response.Response{Code: code.NOT_FOUND, Message: errs.ErrUserDoesNotExist.Error()},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTest(mockChatService)
+			h := handler.New(&handler.Config{ChatService: mockChatService})
+
+			res := h.SocketReadMessages(&fakeSocketConn{context: tc.context}, tc.req)
+
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestSocketDeliverMessages(t *testing.T) {
+	mockChatService := mocks.NewChatService(t)
+	mockChatService.On("SellerDeliverChat", 2, "user1").Return(nil)
+	h := handler.New(&handler.Config{ChatService: mockChatService})
+
+	res := h.SocketDeliverMessages(&fakeSocketConn{context: &dto.SocketSession{UserID: 2, ConnID: "conn-2"}}, dto.SocketRoomRequest{Username: "user1"})
+
+	assert.Equal(t, response.Response{Code: code.OK, Message: "success"}, res)
+}
+
+func TestSocketTyping(t *testing.T) {
+	tests := []struct {
+		description string
+		req         dto.SocketTypingRequest
+		beforeTest  func(*mocks.ChatService)
+		expected    response.Response
+	}{
+		{
+			description: "should reject request without target",
+			req:         dto.SocketTypingRequest{IsTyping: true},
+			beforeTest:  func(cs *mocks.ChatService) {},
+			expected:    response.Response{Code: code.BAD_REQUEST, Message: errs.ErrInvalidChatRoom.Error()},
+		},
+		{
+			description: "should push user typing indicator",
+			req:         dto.SocketTypingRequest{SocketRoomRequest: dto.SocketRoomRequest{ShopSlug: "shop-A"}, IsTyping: true},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("UserTyping", 1, "shop-A", true).Return(nil)
+			},
+			expected: response.Response{Code: code.OK, Message: "success"},
+		},
+		{
+			description: "should push seller typing indicator",
+			req:         dto.SocketTypingRequest{SocketRoomRequest: dto.SocketRoomRequest{Username: "user1"}},
+			beforeTest: func(cs *mocks.ChatService) {
+				cs.On("SellerTyping", 1, "user1", false).Return(nil)
+			},
+			expected: response.Response{Code: code.OK, Message: "success"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockChatService := mocks.NewChatService(t)
+			tc.beforeTest(mockChatService)
+			h := handler.New(&handler.Config{ChatService: mockChatService})
+
+			res := h.SocketTyping(&fakeSocketConn{context: &dto.SocketSession{UserID: 1, ConnID: "conn-1"}}, tc.req)
+
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
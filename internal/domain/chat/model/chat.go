@@ -19,5 +19,50 @@ type Chat struct {
 	User             *userModel.User `json:"user" gorm:"foreignKey:UserId"`
 	Issuer           string          `json:"issuer"`
 	IsReadByOpponent bool            `json:"isReadByOpponent"`
+	DeliveredAt      *time.Time      `json:"deliveredAt"`
+	ReadAt           *time.Time      `json:"readAt"`
+	Payload          *ChatPayload    `json:"payload,omitempty" gorm:"serializer:json"`
+	IsAutoReply      bool            `json:"isAutoReply"`
+	SentByID         *int            `json:"sentById,omitempty"`
+	KeyID            *int            `json:"-"`
 	CreatedAt        time.Time       `json:"createdAt"`
 }
+
+// ChatPayload is a snapshot of whatever a non-text chat refers to, taken when
+// the chat is sent so history does not change with the product, order or
+// voucher it points at.
+type ChatPayload struct {
+	Product *ChatProductPayload `json:"product,omitempty"`
+	Invoice *ChatInvoicePayload `json:"invoice,omitempty"`
+	Image   *ChatImagePayload   `json:"image,omitempty"`
+	Voucher *ChatVoucherPayload `json:"voucher,omitempty"`
+}
+
+type ChatProductPayload struct {
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	MinPrice float64 `json:"minPrice"`
+	MaxPrice float64 `json:"maxPrice"`
+	ImageURL string  `json:"imageUrl"`
+}
+
+type ChatInvoicePayload struct {
+	Code      string  `json:"code"`
+	Status    string  `json:"status"`
+	Total     float64 `json:"total"`
+	ItemCount int     `json:"itemCount"`
+	ImageURL  string  `json:"imageUrl"`
+}
+
+type ChatImagePayload struct {
+	Url string `json:"url"`
+}
+
+type ChatVoucherPayload struct {
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Amount       float64   `json:"amount"`
+	Type         string    `json:"type"`
+	MinimumSpend float64   `json:"minimumSpend"`
+	ExpiredAt    time.Time `json:"expiredAt"`
+}
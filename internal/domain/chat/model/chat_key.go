@@ -0,0 +1,15 @@
+package model
+
+import "gorm.io/gorm"
+
+// ChatKey is a conversation's data key, stored wrapped by the master key
+// version it was last wrapped with.
+type ChatKey struct {
+	ID               int    `json:"id"`
+	UserID           int    `json:"userId"`
+	ShopID           int    `json:"shopId"`
+	WrappedKey       string `json:"-"`
+	MasterKeyVersion int    `json:"masterKeyVersion"`
+
+	gorm.Model `json:"-"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ChatSetting struct {
+	ID                 int        `json:"id"`
+	ShopID             int        `json:"shopId"`
+	IsAutoReplyEnabled bool       `json:"isAutoReplyEnabled"`
+	AutoReplyMessage   string     `json:"autoReplyMessage"`
+	IsAwayEnabled      bool       `json:"isAwayEnabled"`
+	AwayMessage        string     `json:"awayMessage"`
+	AwayFrom           *time.Time `json:"awayFrom"`
+	AwayUntil          *time.Time `json:"awayUntil"`
+
+	gorm.Model `json:"-"`
+}
+
+// IsAway reports whether the shop is in away mode at now. A missing AwayFrom
+// means away starts immediately and a missing AwayUntil means it never ends.
+func (s *ChatSetting) IsAway(now time.Time) bool {
+	if !s.IsAwayEnabled {
+		return false
+	}
+	if s.AwayFrom != nil && now.Before(*s.AwayFrom) {
+		return false
+	}
+	if s.AwayUntil != nil && !now.Before(*s.AwayUntil) {
+		return false
+	}
+
+	return true
+}
+
+// AwaySince is when the current away period started.
+func (s *ChatSetting) AwaySince() time.Time {
+	if s.AwayFrom != nil {
+		return *s.AwayFrom
+	}
+
+	return s.UpdatedAt
+}
+
+type ChatQuickReply struct {
+	ID       int    `json:"id"`
+	ShopID   int    `json:"shopId"`
+	Shortcut string `json:"shortcut"`
+	Message  string `json:"message"`
+
+	gorm.Model `json:"-"`
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"kedai/backend/be-kedai/internal/domain/chat/model"
+	"kedai/backend/be-kedai/internal/utils/encrypt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatKeyRepository interface {
+	GetOrCreate(userId int, shopId int) (int, []byte, error)
+	GetDataKey(keyId int) ([]byte, error)
+	Rewrap(batchSize int) (int, error)
+}
+
+type chatKeyRepositoryImpl struct {
+	db      *gorm.DB
+	keyring *encrypt.Keyring
+
+	// dataKeys caches unwrapped keys by id. Rewrapping never changes a data
+	// key, so entries stay valid across master key rotations.
+	dataKeys sync.Map
+}
+
+type ChatKeyRConfig struct {
+	DB      *gorm.DB
+	Keyring *encrypt.Keyring
+}
+
+func NewChatKeyRepository(cfg *ChatKeyRConfig) ChatKeyRepository {
+	return &chatKeyRepositoryImpl{
+		db:      cfg.DB,
+		keyring: cfg.Keyring,
+	}
+}
+
+// GetOrCreate returns the id and data key of the conversation, generating
+// one on its first message.
+func (r *chatKeyRepositoryImpl) GetOrCreate(userId int, shopId int) (int, []byte, error) {
+	var keys []*model.ChatKey
+	err := r.db.Where("user_id = ? AND shop_id = ?", userId, shopId).Limit(1).Find(&keys).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(keys) == 0 {
+		dataKey, err := encrypt.GenerateDataKey()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		wrapped, version, err := r.keyring.Wrap(dataKey)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		// A concurrent first message may have created the key already, so
+		// read back whichever row won.
+		err = r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ChatKey{
+			UserID:           userId,
+			ShopID:           shopId,
+			WrappedKey:       wrapped,
+			MasterKeyVersion: version,
+		}).Error
+		if err != nil {
+			return 0, nil, err
+		}
+
+		err = r.db.Where("user_id = ? AND shop_id = ?", userId, shopId).Limit(1).Find(&keys).Error
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	dataKey, err := r.unwrap(keys[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return keys[0].ID, dataKey, nil
+}
+
+func (r *chatKeyRepositoryImpl) GetDataKey(keyId int) ([]byte, error) {
+	if dataKey, ok := r.dataKeys.Load(keyId); ok {
+		return dataKey.([]byte), nil
+	}
+
+	var key model.ChatKey
+	if err := r.db.First(&key, keyId).Error; err != nil {
+		return nil, err
+	}
+
+	return r.unwrap(&key)
+}
+
+// Rewrap re-wraps up to batchSize keys that are not on the active master key
+// version and returns how many it found. Each key is updated on its own, so
+// readers keep working with either wrapping while a rotation runs.
+func (r *chatKeyRepositoryImpl) Rewrap(batchSize int) (int, error) {
+	var keys []*model.ChatKey
+	err := r.db.Where("master_key_version <> ?", r.keyring.ActiveVersion()).
+		Order("id ASC").
+		Limit(batchSize).
+		Find(&keys).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		dataKey, err := r.keyring.Unwrap(key.WrappedKey, key.MasterKeyVersion)
+		if err != nil {
+			return 0, err
+		}
+
+		wrapped, version, err := r.keyring.Wrap(dataKey)
+		if err != nil {
+			return 0, err
+		}
+
+		err = r.db.Model(&model.ChatKey{}).
+			Where("id = ? AND master_key_version = ?", key.ID, key.MasterKeyVersion).
+			Updates(map[string]interface{}{
+				"wrapped_key":        wrapped,
+				"master_key_version": version,
+			}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(keys), nil
+}
+
+func (r *chatKeyRepositoryImpl) unwrap(key *model.ChatKey) ([]byte, error) {
+	if dataKey, ok := r.dataKeys.Load(key.ID); ok {
+		return dataKey.([]byte), nil
+	}
+
+	dataKey, err := r.keyring.Unwrap(key.WrappedKey, key.MasterKeyVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	r.dataKeys.Store(key.ID, dataKey)
+
+	return dataKey, nil
+}
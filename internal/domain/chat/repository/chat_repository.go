@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"fmt"
 	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/chat/dto"
@@ -14,6 +16,7 @@ import (
 	"math"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 )
@@ -25,19 +28,31 @@ type ChatRepository interface {
 	SellerGetChat(param *dto.ChatParamRequest, shop *shopModel.Shop, user *userModel.User) (*commonDto.PaginationResponse, error)
 	UserAddChat(body *dto.SendChatBodyRequest, userId int, shop *shopModel.Shop) (*dto.ChatResponse, error)
 	SellerAddChat(body *dto.SendChatBodyRequest, shop *shopModel.Shop, user *userModel.User) (*dto.ChatResponse, error)
+	MarkRead(userId int, shopId int, issuer string) (int64, error)
+	MarkDelivered(userId int, shopId int, issuer string) (int64, error)
+	CountUnread(userId int) (*dto.UnreadCountResponse, error)
+	FirstChat(shop *shopModel.Shop, user *userModel.User) (*model.Chat, error)
+	LastAutoReply(userId int, shopId int) (*model.Chat, error)
+	GetPerformance(shopId int, since time.Time) (*dto.ChatPerformance, error)
+	GetAllByUserID(userId int) ([]*model.Chat, error)
+	CountLegacy() (int64, error)
+	MigrateLegacy(legacyKey []byte, batchSize int) (int, error)
 }
 
 type chatRepositoryImpl struct {
-	db *gorm.DB
+	db          *gorm.DB
+	chatKeyRepo ChatKeyRepository
 }
 
 type ChatRConfig struct {
-	DB *gorm.DB
+	DB          *gorm.DB
+	ChatKeyRepo ChatKeyRepository
 }
 
 func NewChatRepository(cfg *ChatRConfig) ChatRepository {
 	return &chatRepositoryImpl{
-		db: cfg.DB,
+		db:          cfg.DB,
+		chatKeyRepo: cfg.ChatKeyRepo,
 	}
 }
 
@@ -61,6 +76,24 @@ func (r *chatRepositoryImpl) FindWithParam(param *dto.ChatParamRequest, shop *sh
 	return chats, result.Error
 }
 
+// GetAllByUserID returns every chat of the user's conversations, decrypted and
+// ordered by shop then time.
+func (r *chatRepositoryImpl) GetAllByUserID(userId int) ([]*model.Chat, error) {
+	var chats []*model.Chat
+	err := r.db.Preload("Shop").Where("user_id = ?", userId).Order("shop_id, created_at").Find(&chats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chat := range chats {
+		if err := r.decrypt(chat); err != nil {
+			return nil, err
+		}
+	}
+
+	return chats, nil
+}
+
 func (r *chatRepositoryImpl) FirstChat(shop *shopModel.Shop, user *userModel.User) (*model.Chat, error) {
 	var chat *model.Chat
 	result := r.db.Where("user_id = ?", user.ID).Where("shop_id = ?", shop.ID).Order("created_at ASC").First(&chat)
@@ -77,14 +110,14 @@ func (r *chatRepositoryImpl) UserGetListOfChats(param *dto.ListOfChatsParamReque
 		Find(&chats)
 	for _, chat := range chats {
 		if !slice.Contains(distinctShopIds, chat.Shop.ID) && strings.Contains(strings.ToLower(chat.Shop.Name), strings.ToLower(param.Search)) {
-			decryptedMsg, err := encrypt.DecryptMessage(chat.Message, config.AES16SecretKey)
-			if err == nil {
-				chat.Message = decryptedMsg
+			if err := r.decrypt(chat); err != nil {
+				return nil, err
 			}
 
 			listOfChatResponses = append(listOfChatResponses, &dto.UserListOfChatResponse{
 				Shop: &dto.ShopChatProfile{
 					ID:       chat.Shop.ID,
+					UserID:   chat.Shop.UserID,
 					Name:     chat.Shop.Name,
 					ImageUrl: chat.Shop.PhotoUrl,
 					ShopSlug: chat.Shop.Slug,
@@ -138,9 +171,8 @@ func (r *chatRepositoryImpl) SellerGetListOfChats(param *dto.ListOfChatsParamReq
 	r.db.Preload("User.Profile").Where("shop_id = ?", shop.ID).Order("created_at DESC").Find(&chats)
 	for _, chat := range chats {
 		if !slice.Contains(distinctUserIds, chat.User.ID) && strings.Contains(strings.ToLower(chat.User.Username), strings.ToLower(param.Search)) {
-			decryptedMsg, err := encrypt.DecryptMessage(chat.Message, config.AES16SecretKey)
-			if err == nil {
-				chat.Message = decryptedMsg
+			if err := r.decrypt(chat); err != nil {
+				return nil, err
 			}
 
 			listOfChatResponses = append(listOfChatResponses, &dto.SellerListOfChatResponse{
@@ -197,6 +229,9 @@ func (r *chatRepositoryImpl) UserGetChat(param *dto.ChatParamRequest, userId int
 
 	chatsResponse := []*dto.ChatResponse{}
 	for _, chat := range chats {
+		if err := r.decrypt(chat); err != nil {
+			return nil, err
+		}
 		chatsResponse = append(chatsResponse, dto.ConvertChatToOutput(chat, "user"))
 	}
 
@@ -210,13 +245,6 @@ func (r *chatRepositoryImpl) UserGetChat(param *dto.ChatParamRequest, userId int
 		TotalPages: int(math.Ceil(float64(calculatedTotalRows) / float64(param.LimitByDay))),
 	}
 
-	// Step 2: Read all messages (change status from unread to read)
-	r.db.Model(&model.Chat{}).
-		Where("user_id = ? AND shop_id = ?", userId, shop.ID).
-		Where("issuer = ?", "seller").
-		Where("is_read_by_opponent = ?", false).
-		Update("is_read_by_opponent", true)
-
 	return paginatedChats, nil
 }
 
@@ -238,6 +266,9 @@ func (r *chatRepositoryImpl) SellerGetChat(param *dto.ChatParamRequest, shop *sh
 
 	chatsResponse := []*dto.ChatResponse{}
 	for _, chat := range chats {
+		if err := r.decrypt(chat); err != nil {
+			return nil, err
+		}
 		chatsResponse = append(chatsResponse, dto.ConvertChatToOutput(chat, "seller"))
 	}
 
@@ -251,62 +282,276 @@ func (r *chatRepositoryImpl) SellerGetChat(param *dto.ChatParamRequest, shop *sh
 		TotalPages: int(math.Ceil(float64(calculatedTotalRows) / float64(param.LimitByDay))),
 	}
 
-	// Step 2: Read all messages (change status from unread to read)
-	r.db.Model(&model.Chat{}).
-		Where("user_id = ? AND shop_id = ?", user.ID, shop.ID).
-		Where("issuer = ?", "user").
-		Where("is_read_by_opponent = ?", false).
-		Update("is_read_by_opponent", true)
-
 	return paginatedChats, nil
 }
 
 func (r *chatRepositoryImpl) UserAddChat(body *dto.SendChatBodyRequest, userId int, shop *shopModel.Shop) (*dto.ChatResponse, error) {
 	if body.Type == "" {
-		body.Type = "text"
+		body.Type = constant.ChatTypeText
 	}
 
-	encryptedMsg, err := encrypt.EncryptMessage(body.Message, config.AES16SecretKey)
-	if err == nil {
-		body.Message = encryptedMsg
+	encryptedMsg, keyId, err := r.encrypt(body.Message, userId, shop.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	chat := &model.Chat{
-		Message: body.Message,
+		Message: encryptedMsg,
 		Type:    body.Type,
 		ShopId:  shop.ID,
 		UserId:  userId,
 		Issuer:  "user",
+		Payload: body.Payload,
+		KeyID:   &keyId,
 	}
 	result := r.db.Create(&chat)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	chat, _ = r.Last(chat)
+	chat.Message = body.Message
 	return dto.ConvertChatToOutput(chat, "user"), nil
 }
 
 func (r *chatRepositoryImpl) SellerAddChat(body *dto.SendChatBodyRequest, shop *shopModel.Shop, user *userModel.User) (*dto.ChatResponse, error) {
 	if body.Type == "" {
-		body.Type = "text"
+		body.Type = constant.ChatTypeText
 	}
 
-	encryptedMsg, err := encrypt.EncryptMessage(body.Message, config.AES16SecretKey)
-	if err == nil {
-		body.Message = encryptedMsg
+	encryptedMsg, keyId, err := r.encrypt(body.Message, user.ID, shop.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	chat := &model.Chat{
-		Message: body.Message,
-		Type:    body.Type,
-		ShopId:  shop.ID,
-		UserId:  user.ID,
-		Issuer:  "seller",
+		Message:     encryptedMsg,
+		Type:        body.Type,
+		ShopId:      shop.ID,
+		UserId:      user.ID,
+		Issuer:      "seller",
+		Payload:     body.Payload,
+		IsAutoReply: body.IsAutoReply,
+		SentByID:    body.SentByID,
+		KeyID:       &keyId,
 	}
 	result := r.db.Create(&chat)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	chat, _ = r.Last(chat)
+	chat.Message = body.Message
 	return dto.ConvertChatToOutput(chat, "seller"), nil
 }
+
+// MarkRead marks every unread message sent by issuer in the conversation as
+// read, which also implies delivered.
+func (r *chatRepositoryImpl) MarkRead(userId int, shopId int, issuer string) (int64, error) {
+	now := time.Now()
+	result := r.db.Model(&model.Chat{}).
+		Where("user_id = ? AND shop_id = ?", userId, shopId).
+		Where("issuer = ?", issuer).
+		Where("is_read_by_opponent = ?", false).
+		Updates(map[string]interface{}{
+			"is_read_by_opponent": true,
+			"read_at":             now,
+			"delivered_at":        gorm.Expr("COALESCE(delivered_at, ?)", now),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *chatRepositoryImpl) MarkDelivered(userId int, shopId int, issuer string) (int64, error) {
+	result := r.db.Model(&model.Chat{}).
+		Where("user_id = ? AND shop_id = ?", userId, shopId).
+		Where("issuer = ?", issuer).
+		Where("delivered_at IS NULL").
+		Update("delivered_at", time.Now())
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *chatRepositoryImpl) CountUnread(userId int) (*dto.UnreadCountResponse, error) {
+	var buyer, seller int64
+
+	err := r.db.Model(&model.Chat{}).
+		Where("user_id = ?", userId).
+		Where("issuer = ?", "seller").
+		Where("is_read_by_opponent = ?", false).
+		Count(&buyer).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Model(&model.Chat{}).
+		Where("shop_id IN (?)", r.db.Model(&shopModel.Shop{}).Select("id").Where("user_id = ?", userId)).
+		Where("issuer = ?", "user").
+		Where("is_read_by_opponent = ?", false).
+		Count(&seller).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UnreadCountResponse{
+		Buyer:  int(buyer),
+		Seller: int(seller),
+		Total:  int(buyer + seller),
+	}, nil
+}
+
+// LastAutoReply returns the shop's latest automatic reply in the conversation,
+// or nil when it has never sent one.
+func (r *chatRepositoryImpl) LastAutoReply(userId int, shopId int) (*model.Chat, error) {
+	var chats []*model.Chat
+	err := r.db.Where("user_id = ? AND shop_id = ?", userId, shopId).
+		Where("is_auto_reply = ?", true).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&chats).Error
+	if err != nil || len(chats) == 0 {
+		return nil, err
+	}
+
+	return chats[0], nil
+}
+
+// GetPerformance measures how the shop answers buyers since the given time.
+// Each run of consecutive buyer messages is one question; it is answered when
+// a seller message that is not an automatic reply follows within
+// constant.ChatResponseWindow.
+func (r *chatRepositoryImpl) GetPerformance(shopId int, since time.Time) (*dto.ChatPerformance, error) {
+	var stats struct {
+		Questions       int64
+		Answered        int64
+		AverageResponse float64
+	}
+
+	err := r.db.Raw(`
+		WITH ordered AS (
+			SELECT user_id, issuer, created_at,
+				LAG(issuer) OVER (PARTITION BY user_id ORDER BY created_at) AS previous_issuer
+			FROM chats
+			WHERE shop_id = ? AND is_auto_reply = false AND deleted_at IS NULL AND created_at >= ?
+		), questions AS (
+			SELECT user_id, created_at FROM ordered
+			WHERE issuer = 'user' AND (previous_issuer IS NULL OR previous_issuer <> 'user')
+		), replies AS (
+			SELECT q.created_at AS asked_at, (
+				SELECT MIN(c.created_at) FROM chats c
+				WHERE c.shop_id = ? AND c.user_id = q.user_id AND c.issuer = 'seller'
+					AND c.is_auto_reply = false AND c.deleted_at IS NULL AND c.created_at > q.created_at
+			) AS replied_at
+			FROM questions q
+		)
+		SELECT COUNT(*) AS questions,
+			COUNT(*) FILTER (WHERE replied_at <= asked_at + ? * interval '1 second') AS answered,
+			COALESCE(AVG(EXTRACT(EPOCH FROM replied_at - asked_at)) FILTER (WHERE replied_at <= asked_at + ? * interval '1 second'), 0) AS average_response
+		FROM replies`,
+		shopId, since, shopId, constant.ChatResponseWindow.Seconds(), constant.ChatResponseWindow.Seconds(),
+	).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	performance := &dto.ChatPerformance{}
+	if stats.Questions > 0 {
+		performance.ResponseRate = math.Round(float64(stats.Answered)/float64(stats.Questions)*10000) / 100
+		performance.AverageResponseSeconds = int(math.Round(stats.AverageResponse))
+	}
+
+	return performance, nil
+}
+
+// CountLegacy counts chats stored before envelope encryption, which can only
+// be read with the legacy global key.
+func (r *chatRepositoryImpl) CountLegacy() (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.Chat{}).Where("key_id IS NULL").Count(&count).Error
+
+	return count, err
+}
+
+// MigrateLegacy re-encrypts up to batchSize legacy chats with their
+// conversation's data key and returns how many it found. A message that does
+// not decrypt to text means legacyKey is wrong, so it stops before anything
+// is overwritten with garbage.
+func (r *chatRepositoryImpl) MigrateLegacy(legacyKey []byte, batchSize int) (int, error) {
+	var chats []*model.Chat
+	err := r.db.Unscoped().
+		Where("key_id IS NULL").
+		Order("id ASC").
+		Limit(batchSize).
+		Find(&chats).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, chat := range chats {
+		plaintext, err := encrypt.DecryptMessage(chat.Message, legacyKey)
+		if err != nil {
+			return 0, fmt.Errorf("chat %d: %w", chat.ID, err)
+		}
+		if !utf8.ValidString(plaintext) {
+			return 0, fmt.Errorf("chat %d: %w", chat.ID, errs.ErrInvalidLegacyChatKey)
+		}
+
+		sealed, keyId, err := r.encrypt(plaintext, chat.UserId, chat.ShopId)
+		if err != nil {
+			return 0, err
+		}
+
+		err = r.db.Unscoped().Model(&model.Chat{}).
+			Where("id = ? AND key_id IS NULL", chat.ID).
+			Updates(map[string]interface{}{
+				"message": sealed,
+				"key_id":  keyId,
+			}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(chats), nil
+}
+
+// encrypt seals message with the conversation's data key. It fails rather than
+// storing plaintext.
+func (r *chatRepositoryImpl) encrypt(message string, userId int, shopId int) (string, int, error) {
+	keyId, dataKey, err := r.chatKeyRepo.GetOrCreate(userId, shopId)
+	if err != nil {
+		return "", 0, err
+	}
+
+	sealed, err := encrypt.Seal([]byte(message), dataKey)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return sealed, keyId, nil
+}
+
+// decrypt replaces chat.Message with its plaintext. Chats without a key were
+// stored before envelope encryption and use the legacy global key.
+func (r *chatRepositoryImpl) decrypt(chat *model.Chat) error {
+	if chat.KeyID == nil {
+		plaintext, err := encrypt.DecryptMessage(chat.Message, config.AES16SecretKey)
+		if err != nil {
+			return err
+		}
+
+		chat.Message = plaintext
+		return nil
+	}
+
+	dataKey, err := r.chatKeyRepo.GetDataKey(*chat.KeyID)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := encrypt.Open(chat.Message, dataKey)
+	if err != nil {
+		return err
+	}
+
+	chat.Message = string(plaintext)
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatSettingRepository interface {
+	GetByShopID(shopId int) (*model.ChatSetting, error)
+	Upsert(setting *model.ChatSetting) error
+	GetQuickReplies(shopId int) ([]*model.ChatQuickReply, error)
+	CreateQuickReply(quickReply *model.ChatQuickReply) error
+	UpdateQuickReply(quickReply *model.ChatQuickReply) error
+	DeleteQuickReply(id, shopId int) error
+}
+
+type chatSettingRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type ChatSettingRConfig struct {
+	DB *gorm.DB
+}
+
+func NewChatSettingRepository(cfg *ChatSettingRConfig) ChatSettingRepository {
+	return &chatSettingRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+// GetByShopID returns the shop's settings, or disabled defaults when the shop
+// has never saved any.
+func (r *chatSettingRepositoryImpl) GetByShopID(shopId int) (*model.ChatSetting, error) {
+	var setting model.ChatSetting
+
+	err := r.db.Where(model.ChatSetting{ShopID: shopId}).FirstOrInit(&setting).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+func (r *chatSettingRepositoryImpl) Upsert(setting *model.ChatSetting) error {
+	return r.db.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "shop_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"is_auto_reply_enabled",
+				"auto_reply_message",
+				"is_away_enabled",
+				"away_message",
+				"away_from",
+				"away_until",
+				"updated_at",
+			}),
+		}).
+		Create(setting).Error
+}
+
+func (r *chatSettingRepositoryImpl) GetQuickReplies(shopId int) ([]*model.ChatQuickReply, error) {
+	var quickReplies []*model.ChatQuickReply
+
+	err := r.db.Where("shop_id = ?", shopId).Order("shortcut ASC").Find(&quickReplies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return quickReplies, nil
+}
+
+func (r *chatSettingRepositoryImpl) CreateQuickReply(quickReply *model.ChatQuickReply) error {
+	err := r.db.Create(quickReply).Error
+	if err != nil {
+		if errs.IsDuplicateKeyError(err) {
+			return errs.ErrQuickReplyAlreadyExist
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *chatSettingRepositoryImpl) UpdateQuickReply(quickReply *model.ChatQuickReply) error {
+	res := r.db.Model(&model.ChatQuickReply{}).
+		Where("id = ?", quickReply.ID).
+		Where("shop_id = ?", quickReply.ShopID).
+		Updates(map[string]interface{}{
+			"shortcut": quickReply.Shortcut,
+			"message":  quickReply.Message,
+		})
+	if res.Error != nil {
+		if errs.IsDuplicateKeyError(res.Error) {
+			return errs.ErrQuickReplyAlreadyExist
+		}
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return errs.ErrQuickReplyNotFound
+	}
+
+	return nil
+}
+
+func (r *chatSettingRepositoryImpl) DeleteQuickReply(id, shopId int) error {
+	res := r.db.Where("id = ?", id).Where("shop_id = ?", shopId).Delete(&model.ChatQuickReply{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return errs.ErrQuickReplyNotFound
+	}
+
+	return nil
+}
@@ -1,14 +1,23 @@
 package service
 
 import (
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/cache"
 	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	chatModel "kedai/backend/be-kedai/internal/domain/chat/model"
 	"kedai/backend/be-kedai/internal/domain/chat/repository"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
 	invoiceService "kedai/backend/be-kedai/internal/domain/order/service"
 	productService "kedai/backend/be-kedai/internal/domain/product/service"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
 	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
+	userModel "kedai/backend/be-kedai/internal/domain/user/model"
 	userService "kedai/backend/be-kedai/internal/domain/user/service"
+	"log"
+	"time"
 )
 
 type ChatService interface {
@@ -17,41 +26,134 @@ type ChatService interface {
 	UserGetChat(param *dto.ChatParamRequest, userId int, shopSlug string) (*commonDto.PaginationResponse, error)
 	SellerGetChat(param *dto.ChatParamRequest, userId int, username string) (*commonDto.PaginationResponse, error)
 	UserAddChat(body *dto.SendChatBodyRequest, userId int, shopSlug string) (*dto.ChatResponse, error)
-	SellerAddChat(body *dto.SendChatBodyRequest, userId int, username string) (*dto.ChatResponse, error)
+	SellerAddChat(body *dto.SendChatBodyRequest, userId int, actorId int, username string) (*dto.ChatResponse, error)
+	UserJoinRoom(userId int, shopSlug string) (string, error)
+	SellerJoinRoom(userId int, username string) (string, error)
+	MarkOnline(userId int, connId string) error
+	MarkOffline(userId int, connId string) error
+	UserReadChat(userId int, shopSlug string) error
+	SellerReadChat(userId int, username string) error
+	UserDeliverChat(userId int, shopSlug string) error
+	SellerDeliverChat(userId int, username string) error
+	UserTyping(userId int, shopSlug string, isTyping bool) error
+	SellerTyping(userId int, username string, isTyping bool) error
+	GetUnreadCount(userId int) (*dto.UnreadCountResponse, error)
+	GetShopPerformance(shopId int) (*dto.ChatPerformance, error)
+	ExportAccountData(userId int) (map[string]interface{}, error)
+}
+
+// ChatBroadcaster pushes persisted chats to the conversation's socket room.
+// *socketio.Server satisfies it.
+type ChatBroadcaster interface {
+	BroadcastToRoom(namespace string, room, event string, args ...interface{}) bool
 }
 
 type chatServiceImpl struct {
-	chatRepo       repository.ChatRepository
-	shopService    shopService.ShopService
-	userService    userService.UserService
-	productService productService.ProductService
-	invoiceService invoiceService.InvoicePerShopService
+	chatRepo              repository.ChatRepository
+	shopService           shopService.ShopService
+	userService           userService.UserService
+	productService        productService.ProductService
+	invoiceService        invoiceService.InvoicePerShopService
+	voucherService        shopService.ShopVoucherService
+	settingService        ChatSettingService
+	broadcaster           ChatBroadcaster
+	presenceCache         cache.PresenceCache
+	notificationPublisher notificationService.NotificationPublisher
 }
 
 type ChatConfig struct {
-	ChatRepo       repository.ChatRepository
-	ShopService    shopService.ShopService
-	UserService    userService.UserService
-	ProductService productService.ProductService
-	InvoiceService invoiceService.InvoicePerShopService
+	ChatRepo              repository.ChatRepository
+	ShopService           shopService.ShopService
+	UserService           userService.UserService
+	ProductService        productService.ProductService
+	InvoiceService        invoiceService.InvoicePerShopService
+	VoucherService        shopService.ShopVoucherService
+	SettingService        ChatSettingService
+	Broadcaster           ChatBroadcaster
+	PresenceCache         cache.PresenceCache
+	NotificationPublisher notificationService.NotificationPublisher
 }
 
 func NewChatService(config *ChatConfig) ChatService {
 	return &chatServiceImpl{
-		chatRepo:       config.ChatRepo,
-		shopService:    config.ShopService,
-		userService:    config.UserService,
-		productService: config.ProductService,
-		invoiceService: config.InvoiceService,
+		chatRepo:              config.ChatRepo,
+		shopService:           config.ShopService,
+		userService:           config.UserService,
+		productService:        config.ProductService,
+		invoiceService:        config.InvoiceService,
+		voucherService:        config.VoucherService,
+		settingService:        config.SettingService,
+		broadcaster:           config.Broadcaster,
+		presenceCache:         config.PresenceCache,
+		notificationPublisher: config.NotificationPublisher,
 	}
 }
 
 func (s *chatServiceImpl) UserGetListOfChats(param *dto.ListOfChatsParamRequest, userId int) ([]*dto.UserListOfChatResponse, error) {
-	return s.chatRepo.UserGetListOfChats(param, userId)
+	chats, err := s.chatRepo.UserGetListOfChats(param, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var ownerIds []int
+	for _, chat := range chats {
+		ownerIds = append(ownerIds, chat.Shop.UserID)
+	}
+	presences := s.getPresences(ownerIds)
+	for _, chat := range chats {
+		chat.Shop.Presence = presences[chat.Shop.UserID]
+	}
+
+	return chats, nil
 }
 
 func (s *chatServiceImpl) SellerGetListOfChats(param *dto.ListOfChatsParamRequest, userId int) ([]*dto.SellerListOfChatResponse, error) {
-	return s.chatRepo.SellerGetListOfChats(param, userId)
+	chats, err := s.chatRepo.SellerGetListOfChats(param, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIds []int
+	for _, chat := range chats {
+		userIds = append(userIds, chat.User.ID)
+	}
+	presences := s.getPresences(userIds)
+	for _, chat := range chats {
+		chat.User.Presence = presences[chat.User.ID]
+	}
+
+	return chats, nil
+}
+
+func (s *chatServiceImpl) MarkOnline(userId int, connId string) error {
+	if s.presenceCache == nil {
+		return nil
+	}
+
+	return s.presenceCache.MarkOnline(userId, connId)
+}
+
+func (s *chatServiceImpl) MarkOffline(userId int, connId string) error {
+	if s.presenceCache == nil {
+		return nil
+	}
+
+	return s.presenceCache.MarkOffline(userId, connId)
+}
+
+// getPresences never fails the chat list; presence is decoration only.
+func (s *chatServiceImpl) getPresences(userIds []int) map[int]*dto.Presence {
+	if s.presenceCache == nil || len(userIds) == 0 {
+		return map[int]*dto.Presence{}
+	}
+
+	presences, err := s.presenceCache.GetPresences(userIds)
+	if err != nil {
+		log.Println("failed to get chat presences:", err)
+		return map[int]*dto.Presence{}
+	}
+
+	return presences
 }
 
 func (s *chatServiceImpl) UserGetChat(param *dto.ChatParamRequest, userId int, shopSlug string) (*commonDto.PaginationResponse, error) {
@@ -59,7 +161,16 @@ func (s *chatServiceImpl) UserGetChat(param *dto.ChatParamRequest, userId int, s
 	if err != nil {
 		return nil, err
 	}
-	return s.chatRepo.UserGetChat(param, userId, shop)
+	chats, err := s.chatRepo.UserGetChat(param, userId, shop)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markRead(userId, shop.ID, "user"); err != nil {
+		log.Println("failed to mark chats as read:", err)
+	}
+
+	return chats, nil
 }
 
 func (s *chatServiceImpl) SellerGetChat(param *dto.ChatParamRequest, userId int, username string) (*commonDto.PaginationResponse, error) {
@@ -71,7 +182,16 @@ func (s *chatServiceImpl) SellerGetChat(param *dto.ChatParamRequest, userId int,
 	if err != nil {
 		return nil, err
 	}
-	return s.chatRepo.SellerGetChat(param, shop, user)
+	chats, err := s.chatRepo.SellerGetChat(param, shop, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markRead(user.ID, shop.ID, "seller"); err != nil {
+		log.Println("failed to mark chats as read:", err)
+	}
+
+	return chats, nil
 }
 
 func (s *chatServiceImpl) UserAddChat(body *dto.SendChatBodyRequest, userId int, shopSlug string) (*dto.ChatResponse, error) {
@@ -85,54 +205,424 @@ func (s *chatServiceImpl) UserAddChat(body *dto.SendChatBodyRequest, userId int,
 		return nil, errs.ErrSelfMessaging
 	}
 
-	if body.Type == "product" {
-		// Check Message as ProductCode: ErrProductNotFound
-		_, err := s.productService.GetByCode(body.Message)
+	if err := s.resolvePayload(body, userId, shop, "user"); err != nil {
+		return nil, err
+	}
+
+	chat, err := s.chatRepo.UserAddChat(body, userId, shop)
+	if err != nil {
+		return nil, err
+	}
+
+	s.broadcast(dto.ChatRoomID(userId, shop.ID), "user", chat)
+	s.notifyShop(userId, shop, chat)
+	s.autoReply(userId, shop, chat)
+
+	return chat, nil
+}
+
+// autoReply answers the buyer on the shop's behalf: the welcome message on the
+// first chat of a conversation and the away message once per away period.
+// Failures are only logged so the buyer's own message still goes through.
+func (s *chatServiceImpl) autoReply(userId int, shop *shopModel.Shop, chat *dto.ChatResponse) {
+	if s.settingService == nil {
+		return
+	}
+
+	setting, err := s.settingService.GetByShopID(shop.ID)
+	if err != nil {
+		log.Println("failed to get chat setting:", err)
+		return
+	}
+
+	user := &userModel.User{ID: userId}
+	var messages []string
+
+	if setting.IsAutoReplyEnabled {
+		first, err := s.chatRepo.FirstChat(shop, user)
 		if err != nil {
-			return nil, err
+			log.Println("failed to get first chat:", err)
+		} else if first.ID == chat.ID {
+			messages = append(messages, setting.AutoReplyMessage)
 		}
 	}
 
-	if body.Type == "invoice" || body.Type == "complaint" {
-		// Check Message as InvoiceCode: ErrInvoiceNotFound
-		_, err := s.invoiceService.GetInvoicesByUserIDAndCode(userId, body.Message)
+	if setting.IsAway(time.Now()) {
+		last, err := s.chatRepo.LastAutoReply(userId, shop.ID)
 		if err != nil {
-			return nil, err
+			log.Println("failed to get last auto reply:", err)
+		} else if last == nil || last.CreatedAt.Before(setting.AwaySince()) {
+			messages = append(messages, setting.AwayMessage)
 		}
 	}
 
-	return s.chatRepo.UserAddChat(body, userId, shop)
+	for _, message := range messages {
+		reply, err := s.chatRepo.SellerAddChat(&dto.SendChatBodyRequest{
+			Message:     message,
+			Type:        constant.ChatTypeText,
+			IsAutoReply: true,
+		}, shop, user)
+		if err != nil {
+			log.Println("failed to send auto reply:", err)
+			return
+		}
+
+		s.broadcast(dto.ChatRoomID(userId, shop.ID), "seller", reply)
+	}
 }
 
-func (s *chatServiceImpl) SellerAddChat(body *dto.SendChatBodyRequest, userId int, username string) (*dto.ChatResponse, error) {
-	shop, err := s.shopService.FindShopByUserId(userId)
+// SellerAddChat sends as the shop userId acts for and records actorId, the
+// shop member who actually wrote the message.
+func (s *chatServiceImpl) SellerAddChat(body *dto.SendChatBodyRequest, userId int, actorId int, username string) (*dto.ChatResponse, error) {
+	shop, user, err := s.sellerConversation(userId, username)
 	if err != nil {
 		return nil, err
 	}
-	user, err := s.userService.GetByUsername(username)
+
+	if actorId == user.ID {
+		return nil, errs.ErrSelfMessaging
+	}
+	body.SentByID = &actorId
+
+	if err := s.resolvePayload(body, user.ID, shop, "seller"); err != nil {
+		return nil, err
+	}
+
+	chat, err := s.chatRepo.SellerAddChat(body, shop, user)
 	if err != nil {
 		return nil, err
 	}
 
-	if shop.UserID == user.ID {
-		return nil, errs.ErrSelfMessaging
+	s.broadcast(dto.ChatRoomID(user.ID, shop.ID), "seller", chat)
+	s.notifyRecipient(user.ID, shop.Name, dto.ChatRoomID(user.ID, shop.ID), "seller", chat)
+
+	return chat, nil
+}
+
+// resolvePayload validates body against its type and snapshots what the chat
+// refers to into body.Payload. Invoices are looked up under buyerId and must
+// belong to shop, so an order card can only be shared inside its own
+// conversation.
+func (s *chatServiceImpl) resolvePayload(body *dto.SendChatBodyRequest, buyerId int, shop *shopModel.Shop, issuer string) error {
+	if !body.Validate() {
+		return errs.ErrInvalidChatType
 	}
 
-	if body.Type == "product" {
-		// Check Message as ProductCode: ErrProductNotFound
-		_, err := s.productService.GetByCode(body.Message)
+	switch body.Type {
+	case constant.ChatTypeImage:
+		if !body.IsValidImage() {
+			return errs.ErrInvalidChatImage
+		}
+
+		body.Payload = &chatModel.ChatPayload{
+			Image: &chatModel.ChatImagePayload{Url: body.Message},
+		}
+	case constant.ChatTypeProduct:
+		product, err := s.productService.GetByCode(body.Message)
 		if err != nil {
-			return nil, err
+			return err
 		}
-	}
 
-	if body.Type == "invoice" || body.Type == "complaint" {
-		// Check Message as InvoiceCode: ErrInvoiceNotFound
-		_, err := s.invoiceService.GetInvoicesByUserIDAndCode(user.ID, body.Message)
+		imageURL := product.ImageURL
+		if imageURL == "" && len(product.Media) > 0 {
+			imageURL = product.Media[0].Url
+		}
+
+		body.Payload = &chatModel.ChatPayload{
+			Product: &chatModel.ChatProductPayload{
+				Code:     product.Code,
+				Name:     product.Name,
+				MinPrice: product.MinPrice,
+				MaxPrice: product.MaxPrice,
+				ImageURL: imageURL,
+			},
+		}
+	case constant.ChatTypeInvoice, constant.ChatTypeComplaint:
+		invoice, err := s.invoiceService.GetInvoicesByUserIDAndCode(buyerId, body.Message)
+		if err != nil {
+			return err
+		}
+		if invoice.ShopID != shop.ID {
+			return errs.ErrInvoiceNotFound
+		}
+
+		var imageURL string
+		if len(invoice.TransactionItems) > 0 {
+			imageURL = invoice.TransactionItems[0].ImageUrl
+		}
+
+		body.Payload = &chatModel.ChatPayload{
+			Invoice: &chatModel.ChatInvoicePayload{
+				Code:      invoice.Code,
+				Status:    invoice.Status,
+				Total:     invoice.Total,
+				ItemCount: len(invoice.TransactionItems),
+				ImageURL:  imageURL,
+			},
+		}
+	case constant.ChatTypeVoucher:
+		if issuer != "seller" {
+			return errs.ErrChatVoucherSender
+		}
+
+		voucher, err := s.voucherService.GetVoucherByCodeAndShopId(body.Message, shop.UserID)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if voucher.Status == constant.VoucherPromotionStatusExpired {
+			return errs.ErrInvalidVoucher
 		}
+
+		body.Payload = &chatModel.ChatPayload{
+			Voucher: &chatModel.ChatVoucherPayload{
+				Code:         voucher.Code,
+				Name:         voucher.Name,
+				Amount:       voucher.Amount,
+				Type:         voucher.Type,
+				MinimumSpend: voucher.MinimumSpend,
+				ExpiredAt:    voucher.ExpiredAt,
+			},
+		}
+	}
+
+	return nil
+}
+
+func (s *chatServiceImpl) UserJoinRoom(userId int, shopSlug string) (string, error) {
+	shop, err := s.userConversation(userId, shopSlug)
+	if err != nil {
+		return "", err
+	}
+
+	return dto.ChatRoomID(userId, shop.ID), nil
+}
+
+func (s *chatServiceImpl) SellerJoinRoom(userId int, username string) (string, error) {
+	shop, user, err := s.sellerConversation(userId, username)
+	if err != nil {
+		return "", err
+	}
+
+	return dto.ChatRoomID(user.ID, shop.ID), nil
+}
+
+func (s *chatServiceImpl) UserReadChat(userId int, shopSlug string) error {
+	shop, err := s.userConversation(userId, shopSlug)
+	if err != nil {
+		return err
+	}
+
+	return s.markRead(userId, shop.ID, "user")
+}
+
+func (s *chatServiceImpl) SellerReadChat(userId int, username string) error {
+	shop, user, err := s.sellerConversation(userId, username)
+	if err != nil {
+		return err
+	}
+
+	return s.markRead(user.ID, shop.ID, "seller")
+}
+
+func (s *chatServiceImpl) UserDeliverChat(userId int, shopSlug string) error {
+	shop, err := s.userConversation(userId, shopSlug)
+	if err != nil {
+		return err
+	}
+
+	return s.markDelivered(userId, shop.ID, "user")
+}
+
+func (s *chatServiceImpl) SellerDeliverChat(userId int, username string) error {
+	shop, user, err := s.sellerConversation(userId, username)
+	if err != nil {
+		return err
+	}
+
+	return s.markDelivered(user.ID, shop.ID, "seller")
+}
+
+func (s *chatServiceImpl) UserTyping(userId int, shopSlug string, isTyping bool) error {
+	shop, err := s.userConversation(userId, shopSlug)
+	if err != nil {
+		return err
+	}
+
+	room := dto.ChatRoomID(userId, shop.ID)
+	s.emit(room, "typing", &dto.ChatTypingEvent{Room: room, Issuer: "user", IsTyping: isTyping})
+
+	return nil
+}
+
+func (s *chatServiceImpl) SellerTyping(userId int, username string, isTyping bool) error {
+	shop, user, err := s.sellerConversation(userId, username)
+	if err != nil {
+		return err
+	}
+
+	room := dto.ChatRoomID(user.ID, shop.ID)
+	s.emit(room, "typing", &dto.ChatTypingEvent{Room: room, Issuer: "seller", IsTyping: isTyping})
+
+	return nil
+}
+
+func (s *chatServiceImpl) GetUnreadCount(userId int) (*dto.UnreadCountResponse, error) {
+	return s.chatRepo.CountUnread(userId)
+}
+
+func (s *chatServiceImpl) userConversation(userId int, shopSlug string) (*shopModel.Shop, error) {
+	shop, err := s.shopService.FindShopBySlug(shopSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	if shop.UserID == userId {
+		return nil, errs.ErrSelfMessaging
+	}
+
+	return shop, nil
+}
+
+func (s *chatServiceImpl) sellerConversation(userId int, username string) (*shopModel.Shop, *userModel.User, error) {
+	shop, err := s.shopService.FindShopForMember(userId, constant.ShopPermissionManageChats)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.userService.GetByUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if shop.UserID == user.ID {
+		return nil, nil, errs.ErrSelfMessaging
+	}
+
+	return shop, user, nil
+}
+
+// markRead marks the opponent's messages read on behalf of reader ("user" or
+// "seller") and pushes a receipt to the room when anything changed.
+func (s *chatServiceImpl) markRead(userId int, shopId int, reader string) error {
+	count, err := s.chatRepo.MarkRead(userId, shopId, opponentOf(reader))
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		room := dto.ChatRoomID(userId, shopId)
+		s.emit(room, "messages-read", &dto.ChatReceiptEvent{Room: room, Reader: reader, At: time.Now()})
+	}
+
+	return nil
+}
+
+func (s *chatServiceImpl) markDelivered(userId int, shopId int, reader string) error {
+	count, err := s.chatRepo.MarkDelivered(userId, shopId, opponentOf(reader))
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		room := dto.ChatRoomID(userId, shopId)
+		s.emit(room, "messages-delivered", &dto.ChatReceiptEvent{Room: room, Reader: reader, At: time.Now()})
+	}
+
+	return nil
+}
+
+func opponentOf(role string) string {
+	if role == "user" {
+		return "seller"
+	}
+	return "user"
+}
+
+func (s *chatServiceImpl) GetShopPerformance(shopId int) (*dto.ChatPerformance, error) {
+	since := time.Now().AddDate(0, 0, -constant.ChatPerformancePeriodDays)
+
+	return s.chatRepo.GetPerformance(shopId, since)
+}
+
+func (s *chatServiceImpl) notifyShop(userId int, shop *shopModel.Shop, chat *dto.ChatResponse) {
+	if s.notificationPublisher == nil {
+		return
+	}
+
+	user, err := s.userService.GetByID(userId)
+	if err != nil {
+		log.Println("failed to get chat sender:", err)
+		return
+	}
+
+	s.notifyRecipient(shop.UserID, user.Username, dto.ChatRoomID(userId, shop.ID), "user", chat)
+}
+
+// notifyRecipient leaves a notification for a recipient who is not connected;
+// online recipients already get the chat through the socket room.
+func (s *chatServiceImpl) notifyRecipient(recipientId int, sender string, room string, issuer string, chat *dto.ChatResponse) {
+	if s.notificationPublisher == nil {
+		return
+	}
+
+	if presence := s.getPresences([]int{recipientId})[recipientId]; presence != nil && presence.IsOnline {
+		return
+	}
+
+	message := chat.Message
+	if chat.Type != constant.ChatTypeText {
+		message = "Sent a " + chat.Type
+	}
+
+	err := s.notificationPublisher.Publish(&notificationDto.NotificationEvent{
+		UserID: recipientId,
+		Type:   constant.NotificationTypeChatMessage,
+		Data: map[string]interface{}{
+			"chatId":  chat.ID,
+			"room":    room,
+			"issuer":  issuer,
+			"sender":  sender,
+			"message": message,
+		},
+	})
+	if err != nil {
+		log.Println("failed to send chat notification:", err)
+	}
+}
+
+func (s *chatServiceImpl) broadcast(room string, issuer string, chat *dto.ChatResponse) {
+	s.emit(room, "receive-message", &dto.ChatEvent{
+		Room:   room,
+		Issuer: issuer,
+		Chat:   chat,
+	})
+}
+
+func (s *chatServiceImpl) emit(room string, event string, payload interface{}) {
+	if s.broadcaster == nil {
+		return
+	}
+
+	s.broadcaster.BroadcastToRoom("/", room, event, payload)
+}
+
+// ExportAccountData adds the user's conversations with shops, decrypted, to
+// their data export.
+func (s *chatServiceImpl) ExportAccountData(userId int) (map[string]interface{}, error) {
+	chats, err := s.chatRepo.GetAllByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	conversations := []*dto.ChatExport{}
+	var current *dto.ChatExport
+	for i, chat := range chats {
+		if i == 0 || chat.ShopId != chats[i-1].ShopId {
+			current = &dto.ChatExport{Shop: chat.Shop.Name, Messages: []*dto.ChatResponse{}}
+			conversations = append(conversations, current)
+		}
+
+		current.Messages = append(current.Messages, dto.ConvertChatToOutput(chat, "user"))
 	}
 
-	return s.chatRepo.SellerAddChat(body, shop, user)
+	return map[string]interface{}{"chats": conversations}, nil
 }
@@ -2,16 +2,26 @@ package service_test
 
 import (
 	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	chatModel "kedai/backend/be-kedai/internal/domain/chat/model"
 	"kedai/backend/be-kedai/internal/domain/chat/service"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	orderDto "kedai/backend/be-kedai/internal/domain/order/dto"
+	orderModel "kedai/backend/be-kedai/internal/domain/order/model"
+	productDto "kedai/backend/be-kedai/internal/domain/product/dto"
+	productModel "kedai/backend/be-kedai/internal/domain/product/model"
+	shopDto "kedai/backend/be-kedai/internal/domain/shop/dto"
 	"kedai/backend/be-kedai/internal/domain/shop/model"
 	userModel "kedai/backend/be-kedai/internal/domain/user/model"
 	"kedai/backend/be-kedai/mocks"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestUserGetListOfChat(t *testing.T) {
@@ -206,6 +216,7 @@ func TestUserGetChat(t *testing.T) {
 			beforeTest: func(cr *mocks.ChatRepository, ss *mocks.ShopService) {
 				ss.On("FindShopBySlug", shopSlug).Return(shop, nil)
 				cr.On("UserGetChat", param, userId, shop).Return(&commonDto.PaginationResponse{Data: &dto.ChatResponse{}}, nil)
+				cr.On("MarkRead", userId, shop.ID, "seller").Return(int64(0), nil)
 			},
 			expected: expected{
 				data: &commonDto.PaginationResponse{Data: &dto.ChatResponse{}},
@@ -311,6 +322,7 @@ func TestSellerGetChat(t *testing.T) {
 				ss.On("FindShopByUserId", userId).Return(shop, nil)
 				us.On("GetByUsername", username).Return(user, nil)
 				cr.On("SellerGetChat", param, shop, user).Return(&commonDto.PaginationResponse{Data: &dto.ChatResponse{}}, nil)
+				cr.On("MarkRead", user.ID, shop.ID, "user").Return(int64(0), nil)
 			},
 			expected: expected{
 				data: &commonDto.PaginationResponse{Data: &dto.ChatResponse{}},
@@ -528,7 +540,7 @@ func TestSellerAddChat(t *testing.T) {
 				mockErr:  errors.New("failed to find shop"),
 			},
 			beforeTest: func(cr *mocks.ChatRepository, ss *mocks.ShopService, us *mocks.UserService, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
-				ss.On("FindShopByUserId", userId).Return(shop, errors.New("failed to find shop"))
+				ss.On("FindShopForMember", userId, constant.ShopPermissionManageChats).Return(shop, errors.New("failed to find shop"))
 			},
 			expected: expected{
 				shop: shop,
@@ -546,7 +558,7 @@ func TestSellerAddChat(t *testing.T) {
 				mockErr:  errors.New("failed to find user"),
 			},
 			beforeTest: func(cr *mocks.ChatRepository, ss *mocks.ShopService, us *mocks.UserService, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
-				ss.On("FindShopByUserId", userId).Return(shop, nil)
+				ss.On("FindShopForMember", userId, constant.ShopPermissionManageChats).Return(shop, nil)
 				us.On("GetByUsername", username).Return(user, errors.New("failed to find user"))
 			},
 			expected: expected{
@@ -565,7 +577,7 @@ func TestSellerAddChat(t *testing.T) {
 				mockErr:  errs.ErrSelfMessaging,
 			},
 			beforeTest: func(cr *mocks.ChatRepository, ss *mocks.ShopService, us *mocks.UserService, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
-				ss.On("FindShopByUserId", userId).Return(shop, nil)
+				ss.On("FindShopForMember", userId, constant.ShopPermissionManageChats).Return(shop, nil)
 				us.On("GetByUsername", username).Return(&userModel.User{ID: 1}, nil)
 			},
 			expected: expected{
@@ -587,7 +599,7 @@ func TestSellerAddChat(t *testing.T) {
 				mockErr:  errors.New("product not found"),
 			},
 			beforeTest: func(cr *mocks.ChatRepository, ss *mocks.ShopService, us *mocks.UserService, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
-				ss.On("FindShopByUserId", userId).Return(shop, nil)
+				ss.On("FindShopForMember", userId, constant.ShopPermissionManageChats).Return(shop, nil)
 				us.On("GetByUsername", username).Return(user, nil)
 				ps.On("GetByCode", "ITEM-001").Return(nil, errors.New("product not found"))
 			},
@@ -610,7 +622,7 @@ func TestSellerAddChat(t *testing.T) {
 				mockErr:  errors.New("invoice not found"),
 			},
 			beforeTest: func(cr *mocks.ChatRepository, ss *mocks.ShopService, us *mocks.UserService, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
-				ss.On("FindShopByUserId", userId).Return(shop, nil)
+				ss.On("FindShopForMember", userId, constant.ShopPermissionManageChats).Return(shop, nil)
 				us.On("GetByUsername", username).Return(user, nil)
 				is.On("GetInvoicesByUserIDAndCode", user.ID, "INV-A").Return(nil, errors.New("invoice not found"))
 			},
@@ -630,7 +642,7 @@ func TestSellerAddChat(t *testing.T) {
 				mockErr:  nil,
 			},
 			beforeTest: func(cr *mocks.ChatRepository, ss *mocks.ShopService, us *mocks.UserService, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
-				ss.On("FindShopByUserId", userId).Return(shop, nil)
+				ss.On("FindShopForMember", userId, constant.ShopPermissionManageChats).Return(shop, nil)
 				us.On("GetByUsername", username).Return(user, nil)
 				cr.On("SellerAddChat", body, shop, user).Return(&dto.ChatResponse{}, nil)
 			},
@@ -656,9 +668,786 @@ func TestSellerAddChat(t *testing.T) {
 			InvoiceService: mockInvoiceService,
 		})
 
-		data, err := chatService.SellerAddChat(tc.input.body, tc.input.userId, tc.input.username)
+		data, err := chatService.SellerAddChat(tc.input.body, tc.input.userId, tc.input.userId, tc.input.username)
 
 		assert.Equal(t, tc.expected.data, data)
 		assert.Equal(t, tc.expected.err, err)
 	}
 }
+
+func TestUserAddChatPayload(t *testing.T) {
+	var (
+		userId   = 1
+		shopSlug = "shop-A"
+		shop     = &model.Shop{ID: 3, UserID: 2}
+	)
+
+	tests := []struct {
+		description string
+		body        *dto.SendChatBodyRequest
+		beforeTest  func(*mocks.ChatRepository, *mocks.ProductService, *mocks.InvoicePerShopService)
+		expected    *chatModel.ChatPayload
+		err         error
+	}{
+		{
+			description: "should return error when chat type is unknown",
+			body:        &dto.SendChatBodyRequest{Message: "hai", Type: "sticker"},
+			beforeTest:  func(cr *mocks.ChatRepository, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {},
+			err:         errs.ErrInvalidChatType,
+		},
+		{
+			description: "should return error when image is not an image url",
+			body:        &dto.SendChatBodyRequest{Message: "https://cdn.kedai.com/file.exe", Type: constant.ChatTypeImage},
+			beforeTest:  func(cr *mocks.ChatRepository, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {},
+			err:         errs.ErrInvalidChatImage,
+		},
+		{
+			description: "should snapshot image url",
+			body:        &dto.SendChatBodyRequest{Message: "https://cdn.kedai.com/a.png", Type: constant.ChatTypeImage},
+			beforeTest: func(cr *mocks.ChatRepository, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
+				cr.On("UserAddChat", mock.Anything, userId, shop).Return(&dto.ChatResponse{}, nil)
+			},
+			expected: &chatModel.ChatPayload{
+				Image: &chatModel.ChatImagePayload{Url: "https://cdn.kedai.com/a.png"},
+			},
+		},
+		{
+			description: "should snapshot product name, price and first media",
+			body:        &dto.SendChatBodyRequest{Message: "ITEM-001", Type: constant.ChatTypeProduct},
+			beforeTest: func(cr *mocks.ChatRepository, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
+				ps.On("GetByCode", "ITEM-001").Return(&productDto.ProductDetail{
+					Product: productModel.Product{
+						Code:  "ITEM-001",
+						Name:  "Kaos",
+						Media: []*productModel.ProductMedia{{Url: "https://cdn.kedai.com/kaos.png"}},
+					},
+					MinPrice: 10000,
+					MaxPrice: 20000,
+				}, nil)
+				cr.On("UserAddChat", mock.Anything, userId, shop).Return(&dto.ChatResponse{}, nil)
+			},
+			expected: &chatModel.ChatPayload{
+				Product: &chatModel.ChatProductPayload{
+					Code:     "ITEM-001",
+					Name:     "Kaos",
+					MinPrice: 10000,
+					MaxPrice: 20000,
+					ImageURL: "https://cdn.kedai.com/kaos.png",
+				},
+			},
+		},
+		{
+			description: "should return error when invoice belongs to another shop",
+			body:        &dto.SendChatBodyRequest{Message: "INV-A", Type: constant.ChatTypeInvoice},
+			beforeTest: func(cr *mocks.ChatRepository, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
+				is.On("GetInvoicesByUserIDAndCode", userId, "INV-A").Return(&orderDto.InvoicePerShopDetail{
+					InvoicePerShop: orderModel.InvoicePerShop{Code: "INV/A", ShopID: 4},
+				}, nil)
+			},
+			err: errs.ErrInvoiceNotFound,
+		},
+		{
+			description: "should snapshot invoice status and total",
+			body:        &dto.SendChatBodyRequest{Message: "INV-A", Type: constant.ChatTypeInvoice},
+			beforeTest: func(cr *mocks.ChatRepository, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {
+				is.On("GetInvoicesByUserIDAndCode", userId, "INV-A").Return(&orderDto.InvoicePerShopDetail{
+					InvoicePerShop:   orderModel.InvoicePerShop{Code: "INV/A", ShopID: 3, Status: "SENT", Total: 50000},
+					TransactionItems: []*orderDto.TransactionItem{{ImageUrl: "https://cdn.kedai.com/kaos.png"}, {}},
+				}, nil)
+				cr.On("UserAddChat", mock.Anything, userId, shop).Return(&dto.ChatResponse{}, nil)
+			},
+			expected: &chatModel.ChatPayload{
+				Invoice: &chatModel.ChatInvoicePayload{
+					Code:      "INV/A",
+					Status:    "SENT",
+					Total:     50000,
+					ItemCount: 2,
+					ImageURL:  "https://cdn.kedai.com/kaos.png",
+				},
+			},
+		},
+		{
+			description: "should return error when buyer shares a voucher",
+			body:        &dto.SendChatBodyRequest{Message: "DISC10", Type: constant.ChatTypeVoucher},
+			beforeTest:  func(cr *mocks.ChatRepository, ps *mocks.ProductService, is *mocks.InvoicePerShopService) {},
+			err:         errs.ErrChatVoucherSender,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopService := mocks.NewShopService(t)
+			mockProductService := mocks.NewProductService(t)
+			mockInvoiceService := mocks.NewInvoicePerShopService(t)
+			mockChatRepo := mocks.NewChatRepository(t)
+			mockShopService.On("FindShopBySlug", shopSlug).Return(shop, nil)
+			tc.beforeTest(mockChatRepo, mockProductService, mockInvoiceService)
+			chatService := service.NewChatService(&service.ChatConfig{
+				ChatRepo:       mockChatRepo,
+				ShopService:    mockShopService,
+				ProductService: mockProductService,
+				InvoiceService: mockInvoiceService,
+			})
+
+			_, err := chatService.UserAddChat(tc.body, userId, shopSlug)
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, tc.body.Payload)
+		})
+	}
+}
+
+func TestSellerAddChatVoucher(t *testing.T) {
+	var (
+		userId   = 2
+		username = "usernameA"
+		shop     = &model.Shop{ID: 3, UserID: 2}
+		user     = &userModel.User{ID: 1}
+		expired  = time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		description string
+		voucher     *shopDto.SellerVoucher
+		voucherErr  error
+		expected    *chatModel.ChatPayload
+		err         error
+	}{
+		{
+			description: "should return error when voucher not found",
+			voucherErr:  errs.ErrVoucherNotFound,
+			err:         errs.ErrVoucherNotFound,
+		},
+		{
+			description: "should return error when voucher has expired",
+			voucher: &shopDto.SellerVoucher{
+				ShopVoucher: model.ShopVoucher{Code: "DISC10"},
+				Status:      constant.VoucherPromotionStatusExpired,
+			},
+			err: errs.ErrInvalidVoucher,
+		},
+		{
+			description: "should snapshot voucher terms",
+			voucher: &shopDto.SellerVoucher{
+				ShopVoucher: model.ShopVoucher{
+					Code:         "DISC10",
+					Name:         "Diskon",
+					Amount:       10,
+					Type:         "percent",
+					MinimumSpend: 50000,
+					ExpiredAt:    expired,
+				},
+				Status: constant.VoucherPromotionStatusOngoing,
+			},
+			expected: &chatModel.ChatPayload{
+				Voucher: &chatModel.ChatVoucherPayload{
+					Code:         "DISC10",
+					Name:         "Diskon",
+					Amount:       10,
+					Type:         "percent",
+					MinimumSpend: 50000,
+					ExpiredAt:    expired,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			body := &dto.SendChatBodyRequest{Message: "DISC10", Type: constant.ChatTypeVoucher}
+			mockShopService := mocks.NewShopService(t)
+			mockUserService := mocks.NewUserService(t)
+			mockVoucherService := mocks.NewShopVoucherService(t)
+			mockChatRepo := mocks.NewChatRepository(t)
+			mockShopService.On("FindShopForMember", userId, constant.ShopPermissionManageChats).Return(shop, nil)
+			mockUserService.On("GetByUsername", username).Return(user, nil)
+			mockVoucherService.On("GetVoucherByCodeAndShopId", "DISC10", shop.UserID).Return(tc.voucher, tc.voucherErr)
+			if tc.err == nil {
+				mockChatRepo.On("SellerAddChat", body, shop, user).Return(&dto.ChatResponse{}, nil)
+			}
+			chatService := service.NewChatService(&service.ChatConfig{
+				ChatRepo:       mockChatRepo,
+				ShopService:    mockShopService,
+				UserService:    mockUserService,
+				VoucherService: mockVoucherService,
+			})
+
+			_, err := chatService.SellerAddChat(body, userId, userId, username)
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, body.Payload)
+		})
+	}
+}
+
+func TestUserAddChatAutoReply(t *testing.T) {
+	var (
+		userId    = 1
+		shopSlug  = "shop-A"
+		shop      = &model.Shop{ID: 3, UserID: 2}
+		user      = &userModel.User{ID: userId}
+		chat      = &dto.ChatResponse{ID: 10}
+		awayFrom  = time.Now().Add(-time.Hour)
+		awayUntil = time.Now().Add(time.Hour)
+		welcome   = &dto.SendChatBodyRequest{Message: "Selamat datang", Type: constant.ChatTypeText, IsAutoReply: true}
+		away      = &dto.SendChatBodyRequest{Message: "Toko sedang libur", Type: constant.ChatTypeText, IsAutoReply: true}
+	)
+
+	tests := []struct {
+		description string
+		setting     *chatModel.ChatSetting
+		beforeTest  func(*mocks.ChatRepository)
+	}{
+		{
+			description: "should not reply when auto reply and away mode are disabled",
+			setting:     &chatModel.ChatSetting{ShopID: shop.ID},
+			beforeTest:  func(cr *mocks.ChatRepository) {},
+		},
+		{
+			description: "should send welcome message on the first chat of a conversation",
+			setting:     &chatModel.ChatSetting{ShopID: shop.ID, IsAutoReplyEnabled: true, AutoReplyMessage: "Selamat datang"},
+			beforeTest: func(cr *mocks.ChatRepository) {
+				cr.On("FirstChat", shop, user).Return(&chatModel.Chat{ID: chat.ID}, nil)
+				cr.On("SellerAddChat", welcome, shop, user).Return(&dto.ChatResponse{ID: 11}, nil)
+			},
+		},
+		{
+			description: "should not send welcome message when conversation already exists",
+			setting:     &chatModel.ChatSetting{ShopID: shop.ID, IsAutoReplyEnabled: true, AutoReplyMessage: "Selamat datang"},
+			beforeTest: func(cr *mocks.ChatRepository) {
+				cr.On("FirstChat", shop, user).Return(&chatModel.Chat{ID: 1}, nil)
+			},
+		},
+		{
+			description: "should send away message once the away period has started",
+			setting:     &chatModel.ChatSetting{ShopID: shop.ID, IsAwayEnabled: true, AwayMessage: "Toko sedang libur", AwayFrom: &awayFrom, AwayUntil: &awayUntil},
+			beforeTest: func(cr *mocks.ChatRepository) {
+				cr.On("LastAutoReply", userId, shop.ID).Return(&chatModel.Chat{CreatedAt: awayFrom.Add(-time.Minute)}, nil)
+				cr.On("SellerAddChat", away, shop, user).Return(&dto.ChatResponse{ID: 11}, nil)
+			},
+		},
+		{
+			description: "should not repeat away message within the same away period",
+			setting:     &chatModel.ChatSetting{ShopID: shop.ID, IsAwayEnabled: true, AwayMessage: "Toko sedang libur", AwayFrom: &awayFrom, AwayUntil: &awayUntil},
+			beforeTest: func(cr *mocks.ChatRepository) {
+				cr.On("LastAutoReply", userId, shop.ID).Return(&chatModel.Chat{CreatedAt: awayFrom.Add(time.Minute)}, nil)
+			},
+		},
+		{
+			description: "should not send away message after the away period",
+			setting:     &chatModel.ChatSetting{ShopID: shop.ID, IsAwayEnabled: true, AwayMessage: "Toko sedang libur", AwayUntil: &awayFrom},
+			beforeTest:  func(cr *mocks.ChatRepository) {},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			body := &dto.SendChatBodyRequest{Message: "halo", Type: constant.ChatTypeText}
+			mockShopService := mocks.NewShopService(t)
+			mockSettingService := mocks.NewChatSettingService(t)
+			mockChatRepo := mocks.NewChatRepository(t)
+			mockShopService.On("FindShopBySlug", shopSlug).Return(shop, nil)
+			mockChatRepo.On("UserAddChat", body, userId, shop).Return(chat, nil)
+			mockSettingService.On("GetByShopID", shop.ID).Return(tc.setting, nil)
+			tc.beforeTest(mockChatRepo)
+			chatService := service.NewChatService(&service.ChatConfig{
+				ChatRepo:       mockChatRepo,
+				ShopService:    mockShopService,
+				SettingService: mockSettingService,
+			})
+
+			result, err := chatService.UserAddChat(body, userId, shopSlug)
+
+			assert.NoError(t, err)
+			assert.Equal(t, chat, result)
+		})
+	}
+}
+
+func TestGetShopPerformance(t *testing.T) {
+	var (
+		shopId      = 3
+		performance = &dto.ChatPerformance{ResponseRate: 90, AverageResponseSeconds: 120}
+	)
+
+	mockChatRepo := mocks.NewChatRepository(t)
+	mockChatRepo.On("GetPerformance", shopId, mock.MatchedBy(func(since time.Time) bool {
+		expected := time.Now().AddDate(0, 0, -constant.ChatPerformancePeriodDays)
+		return since.After(expected.Add(-time.Minute)) && since.Before(expected.Add(time.Minute))
+	})).Return(performance, nil)
+	chatService := service.NewChatService(&service.ChatConfig{
+		ChatRepo: mockChatRepo,
+	})
+
+	result, err := chatService.GetShopPerformance(shopId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, performance, result)
+}
+
+func TestAddChatBroadcast(t *testing.T) {
+	var (
+		body = &dto.SendChatBodyRequest{
+			Message: "hai",
+			Type:    "text",
+		}
+		shop = &model.Shop{ID: 3, UserID: 2}
+		user = &userModel.User{ID: 1}
+		chat = &dto.ChatResponse{ID: 10, Message: "hai"}
+	)
+
+	t.Run("should broadcast user chat to conversation room", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockBroadcaster := mocks.NewChatBroadcaster(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(shop, nil)
+		mockChatRepo.On("UserAddChat", body, 1, shop).Return(chat, nil)
+		mockBroadcaster.On("BroadcastToRoom", "/", "chat:1:3", "receive-message", &dto.ChatEvent{
+			Room:   "chat:1:3",
+			Issuer: "user",
+			Chat:   chat,
+		}).Return(true)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:    mockChatRepo,
+			ShopService: mockShopService,
+			Broadcaster: mockBroadcaster,
+		})
+
+		data, err := chatService.UserAddChat(body, 1, "shop-A")
+
+		assert.Equal(t, chat, data)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should broadcast seller chat to conversation room", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockUserService := mocks.NewUserService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockBroadcaster := mocks.NewChatBroadcaster(t)
+		mockShopService.On("FindShopForMember", 2, constant.ShopPermissionManageChats).Return(shop, nil)
+		mockUserService.On("GetByUsername", "user1").Return(user, nil)
+		mockChatRepo.On("SellerAddChat", body, shop, user).Return(chat, nil)
+		mockBroadcaster.On("BroadcastToRoom", "/", "chat:1:3", "receive-message", &dto.ChatEvent{
+			Room:   "chat:1:3",
+			Issuer: "seller",
+			Chat:   chat,
+		}).Return(true)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:    mockChatRepo,
+			ShopService: mockShopService,
+			UserService: mockUserService,
+			Broadcaster: mockBroadcaster,
+		})
+
+		data, err := chatService.SellerAddChat(body, 2, 2, "user1")
+
+		assert.Equal(t, chat, data)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should not broadcast when chat is not persisted", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockBroadcaster := mocks.NewChatBroadcaster(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(shop, nil)
+		mockChatRepo.On("UserAddChat", body, 1, shop).Return(nil, errors.New("failed to create chat"))
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:    mockChatRepo,
+			ShopService: mockShopService,
+			Broadcaster: mockBroadcaster,
+		})
+
+		data, err := chatService.UserAddChat(body, 1, "shop-A")
+
+		assert.Nil(t, data)
+		assert.Equal(t, errors.New("failed to create chat"), err)
+	})
+}
+
+func TestAddChatNotification(t *testing.T) {
+	var (
+		body = &dto.SendChatBodyRequest{
+			Message: "hai",
+			Type:    "text",
+		}
+		shop = &model.Shop{ID: 3, UserID: 2, Name: "Shop A"}
+		user = &userModel.User{ID: 1, Username: "user1"}
+		chat = &dto.ChatResponse{ID: 10, Message: "hai", Type: "text"}
+	)
+
+	t.Run("should notify shop owner who is offline", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockUserService := mocks.NewUserService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockPresenceCache := mocks.NewPresenceCache(t)
+		mockPublisher := mocks.NewNotificationPublisher(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(shop, nil)
+		mockChatRepo.On("UserAddChat", body, 1, shop).Return(chat, nil)
+		mockUserService.On("GetByID", 1).Return(user, nil)
+		mockPresenceCache.On("GetPresences", []int{2}).Return(map[int]*dto.Presence{2: {IsOnline: false}}, nil)
+		mockPublisher.On("Publish", &notificationDto.NotificationEvent{
+			UserID: shop.UserID,
+			Type:   constant.NotificationTypeChatMessage,
+			Data: map[string]interface{}{
+				"chatId":  chat.ID,
+				"room":    "chat:1:3",
+				"issuer":  "user",
+				"sender":  user.Username,
+				"message": "hai",
+			},
+		}).Return(nil)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:              mockChatRepo,
+			ShopService:           mockShopService,
+			UserService:           mockUserService,
+			PresenceCache:         mockPresenceCache,
+			NotificationPublisher: mockPublisher,
+		})
+
+		data, err := chatService.UserAddChat(body, 1, "shop-A")
+
+		assert.Equal(t, chat, data)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should not notify user who is online", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockUserService := mocks.NewUserService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockPresenceCache := mocks.NewPresenceCache(t)
+		mockPublisher := mocks.NewNotificationPublisher(t)
+		mockShopService.On("FindShopForMember", 2, constant.ShopPermissionManageChats).Return(shop, nil)
+		mockUserService.On("GetByUsername", "user1").Return(user, nil)
+		mockChatRepo.On("SellerAddChat", body, shop, user).Return(chat, nil)
+		mockPresenceCache.On("GetPresences", []int{1}).Return(map[int]*dto.Presence{1: {IsOnline: true}}, nil)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:              mockChatRepo,
+			ShopService:           mockShopService,
+			UserService:           mockUserService,
+			PresenceCache:         mockPresenceCache,
+			NotificationPublisher: mockPublisher,
+		})
+
+		data, err := chatService.SellerAddChat(body, 2, 2, "user1")
+
+		assert.Equal(t, chat, data)
+		assert.Nil(t, err)
+	})
+}
+
+func TestUserJoinRoom(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.ShopService)
+		room        string
+		err         error
+	}{
+		{
+			description: "should return error when shop not found",
+			beforeTest: func(ss *mocks.ShopService) {
+				ss.On("FindShopBySlug", "shop-A").Return(nil, errs.ErrShopNotFound)
+			},
+			err: errs.ErrShopNotFound,
+		},
+		{
+			description: "should return error when joining own shop room",
+			beforeTest: func(ss *mocks.ShopService) {
+				ss.On("FindShopBySlug", "shop-A").Return(&model.Shop{ID: 3, UserID: 1}, nil)
+			},
+			err: errs.ErrSelfMessaging,
+		},
+		{
+			description: "should return room when succeed",
+			beforeTest: func(ss *mocks.ShopService) {
+				ss.On("FindShopBySlug", "shop-A").Return(&model.Shop{ID: 3, UserID: 2}, nil)
+			},
+			room: "chat:1:3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopService := mocks.NewShopService(t)
+			tc.beforeTest(mockShopService)
+			chatService := service.NewChatService(&service.ChatConfig{
+				ShopService: mockShopService,
+			})
+
+			room, err := chatService.UserJoinRoom(1, "shop-A")
+
+			assert.Equal(t, tc.room, room)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestSellerJoinRoom(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.ShopService, *mocks.UserService)
+		room        string
+		err         error
+	}{
+		{
+			description: "should return error when seller has no shop",
+			beforeTest: func(ss *mocks.ShopService, us *mocks.UserService) {
+				ss.On("FindShopForMember", 2, constant.ShopPermissionManageChats).Return(nil, errs.ErrShopNotFound)
+			},
+			err: errs.ErrShopNotFound,
+		},
+		{
+			description: "should return error when user not found",
+			beforeTest: func(ss *mocks.ShopService, us *mocks.UserService) {
+				ss.On("FindShopForMember", 2, constant.ShopPermissionManageChats).Return(&model.Shop{ID: 3, UserID: 2}, nil)
+				us.On("GetByUsername", "user1").Return(nil, errs.ErrUserDoesNotExist)
+			},
+			err: errs.ErrUserDoesNotExist,
+		},
+		{
+			description: "should return room when succeed",
+			beforeTest: func(ss *mocks.ShopService, us *mocks.UserService) {
+				ss.On("FindShopForMember", 2, constant.ShopPermissionManageChats).Return(&model.Shop{ID: 3, UserID: 2}, nil)
+				us.On("GetByUsername", "user1").Return(&userModel.User{ID: 1}, nil)
+			},
+			room: "chat:1:3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopService := mocks.NewShopService(t)
+			mockUserService := mocks.NewUserService(t)
+			tc.beforeTest(mockShopService, mockUserService)
+			chatService := service.NewChatService(&service.ChatConfig{
+				ShopService: mockShopService,
+				UserService: mockUserService,
+			})
+
+			room, err := chatService.SellerJoinRoom(2, "user1")
+
+			assert.Equal(t, tc.room, room)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestGetListOfChatsPresence(t *testing.T) {
+	lastSeen := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should attach shop owner presence to user chat list", func(t *testing.T) {
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockPresenceCache := mocks.NewPresenceCache(t)
+		chats := []*dto.UserListOfChatResponse{
+			{Shop: &dto.ShopChatProfile{ID: 3, UserID: 2}},
+		}
+		mockChatRepo.On("UserGetListOfChats", &dto.ListOfChatsParamRequest{}, 1).Return(chats, nil)
+		mockPresenceCache.On("GetPresences", []int{2}).Return(map[int]*dto.Presence{2: {IsOnline: true}}, nil)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:      mockChatRepo,
+			PresenceCache: mockPresenceCache,
+		})
+
+		data, err := chatService.UserGetListOfChats(&dto.ListOfChatsParamRequest{}, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &dto.Presence{IsOnline: true}, data[0].Shop.Presence)
+	})
+
+	t.Run("should attach user presence to seller chat list", func(t *testing.T) {
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockPresenceCache := mocks.NewPresenceCache(t)
+		chats := []*dto.SellerListOfChatResponse{
+			{User: &dto.UserChatProfile{ID: 1}},
+		}
+		mockChatRepo.On("SellerGetListOfChats", &dto.ListOfChatsParamRequest{}, 2).Return(chats, nil)
+		mockPresenceCache.On("GetPresences", []int{1}).Return(map[int]*dto.Presence{1: {LastSeenAt: &lastSeen}}, nil)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:      mockChatRepo,
+			PresenceCache: mockPresenceCache,
+		})
+
+		data, err := chatService.SellerGetListOfChats(&dto.ListOfChatsParamRequest{}, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &dto.Presence{LastSeenAt: &lastSeen}, data[0].User.Presence)
+	})
+
+	t.Run("should still return chat list when presence is unavailable", func(t *testing.T) {
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockPresenceCache := mocks.NewPresenceCache(t)
+		chats := []*dto.SellerListOfChatResponse{
+			{User: &dto.UserChatProfile{ID: 1}},
+		}
+		mockChatRepo.On("SellerGetListOfChats", &dto.ListOfChatsParamRequest{}, 2).Return(chats, nil)
+		mockPresenceCache.On("GetPresences", []int{1}).Return(nil, errors.New("redis down"))
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:      mockChatRepo,
+			PresenceCache: mockPresenceCache,
+		})
+
+		data, err := chatService.SellerGetListOfChats(&dto.ListOfChatsParamRequest{}, 2)
+
+		assert.Nil(t, err)
+		assert.Nil(t, data[0].User.Presence)
+	})
+}
+
+func TestMarkPresence(t *testing.T) {
+	t.Run("should mark connection online", func(t *testing.T) {
+		mockPresenceCache := mocks.NewPresenceCache(t)
+		mockPresenceCache.On("MarkOnline", 1, "conn-1").Return(nil)
+		chatService := service.NewChatService(&service.ChatConfig{PresenceCache: mockPresenceCache})
+
+		assert.Nil(t, chatService.MarkOnline(1, "conn-1"))
+	})
+
+	t.Run("should mark connection offline", func(t *testing.T) {
+		mockPresenceCache := mocks.NewPresenceCache(t)
+		mockPresenceCache.On("MarkOffline", 1, "conn-1").Return(nil)
+		chatService := service.NewChatService(&service.ChatConfig{PresenceCache: mockPresenceCache})
+
+		assert.Nil(t, chatService.MarkOffline(1, "conn-1"))
+	})
+}
+
+func TestReadChat(t *testing.T) {
+	shop := &model.Shop{ID: 3, UserID: 2}
+	user := &userModel.User{ID: 1}
+
+	t.Run("should return error when user reads own shop conversation", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(&model.Shop{ID: 3, UserID: 1}, nil)
+		chatService := service.NewChatService(&service.ChatConfig{ShopService: mockShopService})
+
+		err := chatService.UserReadChat(1, "shop-A")
+
+		assert.Equal(t, errs.ErrSelfMessaging, err)
+	})
+
+	t.Run("should push read receipt to seller when user reads unread messages", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockBroadcaster := mocks.NewChatBroadcaster(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(shop, nil)
+		mockChatRepo.On("MarkRead", 1, 3, "seller").Return(int64(2), nil)
+		mockBroadcaster.On("BroadcastToRoom", "/", "chat:1:3", "messages-read", mock.MatchedBy(func(event *dto.ChatReceiptEvent) bool {
+			return event.Room == "chat:1:3" && event.Reader == "user" && !event.At.IsZero()
+		})).Return(true)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:    mockChatRepo,
+			ShopService: mockShopService,
+			Broadcaster: mockBroadcaster,
+		})
+
+		err := chatService.UserReadChat(1, "shop-A")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should not push read receipt when nothing was unread", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockUserService := mocks.NewUserService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockBroadcaster := mocks.NewChatBroadcaster(t)
+		mockShopService.On("FindShopForMember", 2, constant.ShopPermissionManageChats).Return(shop, nil)
+		mockUserService.On("GetByUsername", "user1").Return(user, nil)
+		mockChatRepo.On("MarkRead", 1, 3, "user").Return(int64(0), nil)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:    mockChatRepo,
+			ShopService: mockShopService,
+			UserService: mockUserService,
+			Broadcaster: mockBroadcaster,
+		})
+
+		err := chatService.SellerReadChat(2, "user1")
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestDeliverChat(t *testing.T) {
+	shop := &model.Shop{ID: 3, UserID: 2}
+	user := &userModel.User{ID: 1}
+
+	t.Run("should return error when failed to mark delivered", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(shop, nil)
+		mockChatRepo.On("MarkDelivered", 1, 3, "seller").Return(int64(0), errors.New("db down"))
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:    mockChatRepo,
+			ShopService: mockShopService,
+		})
+
+		err := chatService.UserDeliverChat(1, "shop-A")
+
+		assert.Equal(t, errors.New("db down"), err)
+	})
+
+	t.Run("should push delivered receipt to user when seller receives messages", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockUserService := mocks.NewUserService(t)
+		mockChatRepo := mocks.NewChatRepository(t)
+		mockBroadcaster := mocks.NewChatBroadcaster(t)
+		mockShopService.On("FindShopForMember", 2, constant.ShopPermissionManageChats).Return(shop, nil)
+		mockUserService.On("GetByUsername", "user1").Return(user, nil)
+		mockChatRepo.On("MarkDelivered", 1, 3, "user").Return(int64(1), nil)
+		mockBroadcaster.On("BroadcastToRoom", "/", "chat:1:3", "messages-delivered", mock.MatchedBy(func(event *dto.ChatReceiptEvent) bool {
+			return event.Room == "chat:1:3" && event.Reader == "seller"
+		})).Return(true)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ChatRepo:    mockChatRepo,
+			ShopService: mockShopService,
+			UserService: mockUserService,
+			Broadcaster: mockBroadcaster,
+		})
+
+		err := chatService.SellerDeliverChat(2, "user1")
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestTyping(t *testing.T) {
+	shop := &model.Shop{ID: 3, UserID: 2}
+
+	t.Run("should return error when shop not found", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(nil, errs.ErrShopNotFound)
+		chatService := service.NewChatService(&service.ChatConfig{ShopService: mockShopService})
+
+		err := chatService.UserTyping(1, "shop-A", true)
+
+		assert.Equal(t, errs.ErrShopNotFound, err)
+	})
+
+	t.Run("should push typing indicator to conversation room", func(t *testing.T) {
+		mockShopService := mocks.NewShopService(t)
+		mockBroadcaster := mocks.NewChatBroadcaster(t)
+		mockShopService.On("FindShopBySlug", "shop-A").Return(shop, nil)
+		mockBroadcaster.On("BroadcastToRoom", "/", "chat:1:3", "typing", &dto.ChatTypingEvent{
+			Room:     "chat:1:3",
+			Issuer:   "user",
+			IsTyping: true,
+		}).Return(true)
+		chatService := service.NewChatService(&service.ChatConfig{
+			ShopService: mockShopService,
+			Broadcaster: mockBroadcaster,
+		})
+
+		err := chatService.UserTyping(1, "shop-A", true)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestGetUnreadCount(t *testing.T) {
+	mockChatRepo := mocks.NewChatRepository(t)
+	mockChatRepo.On("CountUnread", 1).Return(&dto.UnreadCountResponse{Buyer: 2, Seller: 3, Total: 5}, nil)
+	chatService := service.NewChatService(&service.ChatConfig{ChatRepo: mockChatRepo})
+
+	data, err := chatService.GetUnreadCount(1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &dto.UnreadCountResponse{Buyer: 2, Seller: 3, Total: 5}, data)
+}
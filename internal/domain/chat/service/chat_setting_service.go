@@ -0,0 +1,123 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	"kedai/backend/be-kedai/internal/domain/chat/model"
+	"kedai/backend/be-kedai/internal/domain/chat/repository"
+	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
+)
+
+type ChatSettingService interface {
+	GetSetting(userId int) (*model.ChatSetting, error)
+	GetByShopID(shopId int) (*model.ChatSetting, error)
+	UpdateSetting(userId int, req *dto.ChatSettingRequest) (*model.ChatSetting, error)
+	GetQuickReplies(userId int) ([]*model.ChatQuickReply, error)
+	CreateQuickReply(userId int, req *dto.QuickReplyRequest) (*model.ChatQuickReply, error)
+	UpdateQuickReply(userId int, quickReplyId int, req *dto.QuickReplyRequest) (*model.ChatQuickReply, error)
+	DeleteQuickReply(userId int, quickReplyId int) error
+}
+
+type chatSettingServiceImpl struct {
+	chatSettingRepo repository.ChatSettingRepository
+	shopService     shopService.ShopService
+}
+
+type ChatSettingSConfig struct {
+	ChatSettingRepo repository.ChatSettingRepository
+	ShopService     shopService.ShopService
+}
+
+func NewChatSettingService(cfg *ChatSettingSConfig) ChatSettingService {
+	return &chatSettingServiceImpl{
+		chatSettingRepo: cfg.ChatSettingRepo,
+		shopService:     cfg.ShopService,
+	}
+}
+
+func (s *chatSettingServiceImpl) GetSetting(userId int) (*model.ChatSetting, error) {
+	shop, err := s.shopService.FindShopByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.chatSettingRepo.GetByShopID(shop.ID)
+}
+
+func (s *chatSettingServiceImpl) GetByShopID(shopId int) (*model.ChatSetting, error) {
+	return s.chatSettingRepo.GetByShopID(shopId)
+}
+
+func (s *chatSettingServiceImpl) UpdateSetting(userId int, req *dto.ChatSettingRequest) (*model.ChatSetting, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	shop, err := s.shopService.FindShopByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	setting := req.ToModel(shop.ID)
+	if err := s.chatSettingRepo.Upsert(setting); err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+func (s *chatSettingServiceImpl) GetQuickReplies(userId int) ([]*model.ChatQuickReply, error) {
+	shop, err := s.shopService.FindShopByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.chatSettingRepo.GetQuickReplies(shop.ID)
+}
+
+func (s *chatSettingServiceImpl) CreateQuickReply(userId int, req *dto.QuickReplyRequest) (*model.ChatQuickReply, error) {
+	shop, err := s.shopService.FindShopByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	quickReplies, err := s.chatSettingRepo.GetQuickReplies(shop.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(quickReplies) >= constant.MaxChatQuickReplies {
+		return nil, errs.ErrQuickReplyLimitReached
+	}
+
+	quickReply := req.ToModel(shop.ID)
+	if err := s.chatSettingRepo.CreateQuickReply(quickReply); err != nil {
+		return nil, err
+	}
+
+	return quickReply, nil
+}
+
+func (s *chatSettingServiceImpl) UpdateQuickReply(userId int, quickReplyId int, req *dto.QuickReplyRequest) (*model.ChatQuickReply, error) {
+	shop, err := s.shopService.FindShopByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	quickReply := req.ToModel(shop.ID)
+	quickReply.ID = quickReplyId
+	if err := s.chatSettingRepo.UpdateQuickReply(quickReply); err != nil {
+		return nil, err
+	}
+
+	return quickReply, nil
+}
+
+func (s *chatSettingServiceImpl) DeleteQuickReply(userId int, quickReplyId int) error {
+	shop, err := s.shopService.FindShopByUserId(userId)
+	if err != nil {
+		return err
+	}
+
+	return s.chatSettingRepo.DeleteQuickReply(quickReplyId, shop.ID)
+}
@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/chat/dto"
+	chatModel "kedai/backend/be-kedai/internal/domain/chat/model"
+	"kedai/backend/be-kedai/internal/domain/chat/service"
+	"kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateChatSetting(t *testing.T) {
+	var (
+		userId    = 1
+		shop      = &model.Shop{ID: 3, UserID: 1}
+		awayFrom  = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		awayUntil = awayFrom.AddDate(0, 0, 7)
+	)
+
+	tests := []struct {
+		description string
+		req         *dto.ChatSettingRequest
+		beforeTest  func(*mocks.ChatSettingRepository, *mocks.ShopService)
+		expected    *chatModel.ChatSetting
+		err         error
+	}{
+		{
+			description: "should return error when auto reply is enabled without a message",
+			req:         &dto.ChatSettingRequest{IsAutoReplyEnabled: true, AutoReplyMessage: "  "},
+			beforeTest:  func(csr *mocks.ChatSettingRepository, ss *mocks.ShopService) {},
+			err:         errs.ErrChatAutoReplyMessageRequired,
+		},
+		{
+			description: "should return error when away schedule ends before it starts",
+			req:         &dto.ChatSettingRequest{IsAwayEnabled: true, AwayMessage: "libur", AwayFrom: &awayUntil, AwayUntil: &awayFrom},
+			beforeTest:  func(csr *mocks.ChatSettingRepository, ss *mocks.ShopService) {},
+			err:         errs.ErrInvalidAwaySchedule,
+		},
+		{
+			description: "should return error when shop not found",
+			req:         &dto.ChatSettingRequest{},
+			beforeTest: func(csr *mocks.ChatSettingRepository, ss *mocks.ShopService) {
+				ss.On("FindShopByUserId", userId).Return(nil, errs.ErrShopNotFound)
+			},
+			err: errs.ErrShopNotFound,
+		},
+		{
+			description: "should return error when failed to save setting",
+			req:         &dto.ChatSettingRequest{},
+			beforeTest: func(csr *mocks.ChatSettingRepository, ss *mocks.ShopService) {
+				ss.On("FindShopByUserId", userId).Return(shop, nil)
+				csr.On("Upsert", mock.Anything).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should save setting for the seller's shop",
+			req: &dto.ChatSettingRequest{
+				IsAutoReplyEnabled: true,
+				AutoReplyMessage:   " Halo! ",
+				IsAwayEnabled:      true,
+				AwayMessage:        "Toko libur",
+				AwayFrom:           &awayFrom,
+				AwayUntil:          &awayUntil,
+			},
+			beforeTest: func(csr *mocks.ChatSettingRepository, ss *mocks.ShopService) {
+				ss.On("FindShopByUserId", userId).Return(shop, nil)
+				csr.On("Upsert", mock.Anything).Return(nil)
+			},
+			expected: &chatModel.ChatSetting{
+				ShopID:             shop.ID,
+				IsAutoReplyEnabled: true,
+				AutoReplyMessage:   "Halo!",
+				IsAwayEnabled:      true,
+				AwayMessage:        "Toko libur",
+				AwayFrom:           &awayFrom,
+				AwayUntil:          &awayUntil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockRepo := mocks.NewChatSettingRepository(t)
+			mockShopService := mocks.NewShopService(t)
+			tc.beforeTest(mockRepo, mockShopService)
+			chatSettingService := service.NewChatSettingService(&service.ChatSettingSConfig{
+				ChatSettingRepo: mockRepo,
+				ShopService:     mockShopService,
+			})
+
+			result, err := chatSettingService.UpdateSetting(userId, tc.req)
+
+			assert.Equal(t, tc.expected, result)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestCreateQuickReply(t *testing.T) {
+	var (
+		userId = 1
+		shop   = &model.Shop{ID: 3, UserID: 1}
+		req    = &dto.QuickReplyRequest{Shortcut: "ongkir", Message: "Ongkir gratis se-Jawa"}
+		full   = make([]*chatModel.ChatQuickReply, constant.MaxChatQuickReplies)
+	)
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.ChatSettingRepository)
+		expected    *chatModel.ChatQuickReply
+		err         error
+	}{
+		{
+			description: "should return error when quick reply limit is reached",
+			beforeTest: func(csr *mocks.ChatSettingRepository) {
+				csr.On("GetQuickReplies", shop.ID).Return(full, nil)
+			},
+			err: errs.ErrQuickReplyLimitReached,
+		},
+		{
+			description: "should return error when shortcut already exists",
+			beforeTest: func(csr *mocks.ChatSettingRepository) {
+				csr.On("GetQuickReplies", shop.ID).Return([]*chatModel.ChatQuickReply{}, nil)
+				csr.On("CreateQuickReply", req.ToModel(shop.ID)).Return(errs.ErrQuickReplyAlreadyExist)
+			},
+			err: errs.ErrQuickReplyAlreadyExist,
+		},
+		{
+			description: "should create quick reply",
+			beforeTest: func(csr *mocks.ChatSettingRepository) {
+				csr.On("GetQuickReplies", shop.ID).Return([]*chatModel.ChatQuickReply{}, nil)
+				csr.On("CreateQuickReply", req.ToModel(shop.ID)).Return(nil)
+			},
+			expected: req.ToModel(shop.ID),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockRepo := mocks.NewChatSettingRepository(t)
+			mockShopService := mocks.NewShopService(t)
+			mockShopService.On("FindShopByUserId", userId).Return(shop, nil)
+			tc.beforeTest(mockRepo)
+			chatSettingService := service.NewChatSettingService(&service.ChatSettingSConfig{
+				ChatSettingRepo: mockRepo,
+				ShopService:     mockShopService,
+			})
+
+			result, err := chatSettingService.CreateQuickReply(userId, req)
+
+			assert.Equal(t, tc.expected, result)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestDeleteQuickReply(t *testing.T) {
+	var (
+		userId       = 1
+		quickReplyId = 5
+		shop         = &model.Shop{ID: 3, UserID: 1}
+	)
+
+	mockRepo := mocks.NewChatSettingRepository(t)
+	mockShopService := mocks.NewShopService(t)
+	mockShopService.On("FindShopByUserId", userId).Return(shop, nil)
+	mockRepo.On("DeleteQuickReply", quickReplyId, shop.ID).Return(errs.ErrQuickReplyNotFound)
+	chatSettingService := service.NewChatSettingService(&service.ChatSettingSConfig{
+		ChatSettingRepo: mockRepo,
+		ShopService:     mockShopService,
+	})
+
+	err := chatSettingService.DeleteQuickReply(userId, quickReplyId)
+
+	assert.Equal(t, errs.ErrQuickReplyNotFound, err)
+}
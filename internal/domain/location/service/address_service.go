@@ -20,6 +20,7 @@ type AddressService interface {
 	GetUserAddressByIdAndUserId(addressId int, userId int) (*model.UserAddress, error)
 	SearchAddress(req *dto.SearchAddressRequest) ([]*dto.SearchAddressResponse, error)
 	GetSearchAddressDetail(placeId string) (*dto.SearchAddressDetailResponse, error)
+	ExportAccountData(userId int) (map[string]interface{}, error)
 }
 
 type addressServiceImpl struct {
@@ -91,6 +92,16 @@ func (s *addressServiceImpl) GetAllUserAddress(userId int) ([]*model.UserAddress
 	return dto.ToAddressList(addresses, profile.DefaultAddressID, &shop.AddressID), nil
 }
 
+// ExportAccountData adds the user's addresses to their data export.
+func (s *addressServiceImpl) ExportAccountData(userId int) (map[string]interface{}, error) {
+	addresses, err := s.GetAllUserAddress(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"addresses": addresses}, nil
+}
+
 func (s *addressServiceImpl) PreCheckAddress(newAddress *dto.AddressRequest) (*model.UserAddress, error) {
 	var address *model.UserAddress
 
@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/marketplace/model"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
+	"time"
+)
+
+type CreateFlashSaleCampaignRequest struct {
+	Name        string                  `json:"name" binding:"required,min=5,max=100"`
+	Description string                  `json:"description" binding:"omitempty,max=1000"`
+	Slots       []*FlashSaleSlotRequest `json:"slots" binding:"required,min=1,dive"`
+}
+
+type FlashSaleSlotRequest struct {
+	StartTime time.Time `json:"startTime" binding:"required"`
+	EndTime   time.Time `json:"endTime" binding:"required"`
+}
+
+func (r *CreateFlashSaleCampaignRequest) Validate() error {
+	now := time.Now()
+	for _, slot := range r.Slots {
+		if slot.StartTime.Before(now) || !slot.EndTime.After(slot.StartTime) {
+			return commonErr.ErrInvalidFlashSaleSlot
+		}
+	}
+
+	return nil
+}
+
+func (r *CreateFlashSaleCampaignRequest) ToCampaign() *model.FlashSaleCampaign {
+	campaign := &model.FlashSaleCampaign{
+		Name:        r.Name,
+		Description: r.Description,
+	}
+
+	for _, slot := range r.Slots {
+		campaign.Slots = append(campaign.Slots, &model.FlashSaleSlot{
+			StartTime: slot.StartTime,
+			EndTime:   slot.EndTime,
+		})
+	}
+
+	return campaign
+}
+
+type SubmitFlashSaleRequest struct {
+	Skus []*FlashSaleSkuRequest `json:"skus" binding:"required,min=1,dive"`
+}
+
+type FlashSaleSkuRequest struct {
+	SkuID         int     `json:"skuId" binding:"required,gte=1"`
+	Type          string  `json:"type" binding:"required,oneof=percent nominal"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	Quota         int     `json:"quota" binding:"required,gte=1"`
+	PurchaseLimit int     `json:"purchaseLimit" binding:"required,gte=1"`
+}
+
+func (r *FlashSaleSkuRequest) Validate(price float64) error {
+	if r.Type == shopModel.PromotionTypePercent && r.Amount >= 1 {
+		return commonErr.ErrInvalidFlashSaleDiscount
+	}
+
+	if r.Type == shopModel.PromotionTypeNominal && r.Amount >= price {
+		return commonErr.ErrInvalidFlashSaleDiscount
+	}
+
+	return nil
+}
+
+func (r *FlashSaleSkuRequest) ToFlashSaleProduct(slotID, shopID int) *model.FlashSaleProduct {
+	return &model.FlashSaleProduct{
+		Type:          r.Type,
+		Amount:        r.Amount,
+		Quota:         r.Quota,
+		Stock:         r.Quota,
+		PurchaseLimit: r.PurchaseLimit,
+		Status:        model.FlashSaleProductStatusPending,
+		SlotID:        slotID,
+		SkuID:         r.SkuID,
+		ShopID:        shopID,
+	}
+}
+
+type FlashSaleSubmissionFilterRequest struct {
+	Limit      int    `form:"limit"`
+	Page       int    `form:"page"`
+	Status     string `form:"status"`
+	CampaignID int    `form:"-"`
+	ShopID     int    `form:"-"`
+}
+
+func (r *FlashSaleSubmissionFilterRequest) Validate() {
+	if r.Limit < 1 {
+		r.Limit = constant.DefaultFlashSaleSubmissionLimit
+	}
+
+	if r.Limit > constant.MaxFlashSaleSubmissionLimit {
+		r.Limit = constant.MaxFlashSaleSubmissionLimit
+	}
+
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Status != model.FlashSaleProductStatusPending &&
+		r.Status != model.FlashSaleProductStatusApproved &&
+		r.Status != model.FlashSaleProductStatusRejected {
+		r.Status = ""
+	}
+}
+
+func (r *FlashSaleSubmissionFilterRequest) Offset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+type UpdateFlashSaleSubmissionRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+}
+
+type FlashSaleSlotResponse struct {
+	ID           int                         `json:"id"`
+	CampaignID   int                         `json:"campaignId"`
+	CampaignName string                      `json:"campaignName"`
+	StartTime    time.Time                   `json:"startTime"`
+	EndTime      time.Time                   `json:"endTime"`
+	IsActive     bool                        `json:"isActive"`
+	Products     []*FlashSaleProductResponse `json:"products"`
+}
+
+type FlashSaleProductResponse struct {
+	ID             int     `json:"id"`
+	SlotID         int     `json:"-"`
+	SkuID          int     `json:"skuId"`
+	ProductCode    string  `json:"productCode"`
+	ProductName    string  `json:"productName"`
+	ImageURL       string  `json:"imageUrl"`
+	OriginalPrice  float64 `json:"originalPrice"`
+	FlashSalePrice float64 `json:"flashSalePrice"`
+	Quota          int     `json:"quota"`
+	Stock          int     `json:"stock"`
+	PurchaseLimit  int     `json:"purchaseLimit"`
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	commonErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/marketplace/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetFlashSales(c *gin.Context) {
+	result, err := h.flashSaleService.GetFlashSales()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+func (h *Handler) CreateFlashSaleCampaign(c *gin.Context) {
+	var req dto.CreateFlashSaleCampaignRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.flashSaleService.CreateCampaign(&req)
+	if err != nil {
+		if errors.Is(err, commonErr.ErrInvalidFlashSaleSlot) {
+			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_FLASH_SALE_SLOT, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "created", result)
+}
+
+func (h *Handler) GetFlashSaleSubmissions(c *gin.Context) {
+	campaignID, err := strconv.Atoi(c.Param("campaignId"))
+	if campaignID < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.FLASH_SALE_CAMPAIGN_NOT_FOUND, commonErr.ErrFlashSaleCampaignNotFound.Error())
+		return
+	}
+
+	var req dto.FlashSaleSubmissionFilterRequest
+	_ = c.ShouldBindQuery(&req)
+	req.Validate()
+	req.CampaignID = campaignID
+
+	result, err := h.flashSaleService.GetSubmissions(&req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+func (h *Handler) UpdateFlashSaleSubmissionStatus(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("submissionId"))
+	if submissionID < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.FLASH_SALE_SUBMISSION_NOT_FOUND, commonErr.ErrFlashSaleSubmissionNotFound.Error())
+		return
+	}
+
+	var req dto.UpdateFlashSaleSubmissionRequest
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.flashSaleService.UpdateSubmissionStatus(submissionID, &req)
+	if err != nil {
+		if errors.Is(err, commonErr.ErrFlashSaleSubmissionNotFound) {
+			response.Error(c, http.StatusNotFound, code.FLASH_SALE_SUBMISSION_NOT_FOUND, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrFlashSaleSlotClosed) {
+			response.Error(c, http.StatusUnprocessableEntity, code.FLASH_SALE_SLOT_CLOSED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "update successful", nil)
+}
+
+func (h *Handler) GetOpenFlashSaleSlots(c *gin.Context) {
+	result, err := h.flashSaleService.GetOpenSlots()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+func (h *Handler) SubmitFlashSaleSkus(c *gin.Context) {
+	slotID, err := strconv.Atoi(c.Param("slotId"))
+	if slotID < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.FLASH_SALE_SLOT_NOT_FOUND, commonErr.ErrFlashSaleSlotNotFound.Error())
+		return
+	}
+
+	var req dto.SubmitFlashSaleRequest
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID := c.GetInt("shopOwnerId")
+
+	result, err := h.flashSaleService.SubmitSkus(userID, slotID, &req)
+	if err != nil {
+		if errors.Is(err, commonErr.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrFlashSaleSlotNotFound) {
+			response.Error(c, http.StatusNotFound, code.FLASH_SALE_SLOT_NOT_FOUND, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrSKUDoesNotExist) {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrFlashSaleSlotClosed) {
+			response.Error(c, http.StatusUnprocessableEntity, code.FLASH_SALE_SLOT_CLOSED, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrInvalidFlashSaleDiscount) {
+			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_FLASH_SALE_DISCOUNT, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrFlashSaleQuotaExceedsStock) {
+			response.Error(c, http.StatusUnprocessableEntity, code.FLASH_SALE_QUOTA_EXCEEDS_STOCK, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrFlashSaleSkuAlreadySubmitted) {
+			response.Error(c, http.StatusConflict, code.FLASH_SALE_SKU_ALREADY_SUBMITTED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "created", result)
+}
+
+func (h *Handler) GetSellerFlashSaleSubmissions(c *gin.Context) {
+	var req dto.FlashSaleSubmissionFilterRequest
+	_ = c.ShouldBindQuery(&req)
+	req.Validate()
+
+	userID := c.GetInt("shopOwnerId")
+
+	result, err := h.flashSaleService.GetSellerSubmissions(userID, &req)
+	if err != nil {
+		if errors.Is(err, commonErr.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
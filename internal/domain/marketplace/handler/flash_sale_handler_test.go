@@ -0,0 +1,550 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/marketplace/dto"
+	"kedai/backend/be-kedai/internal/domain/marketplace/handler"
+	"kedai/backend/be-kedai/internal/domain/marketplace/model"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	testutil "kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFlashSales(t *testing.T) {
+	flashSales := []*dto.FlashSaleSlotResponse{{ID: 1, CampaignID: 1, Products: []*dto.FlashSaleProductResponse{}}}
+
+	type input struct {
+		mockData []*dto.FlashSaleSlotResponse
+		mockErr  error
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 500 when failed to get flash sales",
+			input: input{
+				mockData: nil,
+				mockErr:  errs.ErrInternalServerError,
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return flash sales with status code 200 when succeed",
+			input: input{
+				mockData: flashSales,
+				mockErr:  nil,
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    flashSales,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			flashSaleService := mocks.NewFlashSaleService(t)
+			flashSaleService.On("GetFlashSales").Return(tc.input.mockData, tc.input.mockErr)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h := handler.New(&handler.HandlerConfig{
+				FlashSaleService: flashSaleService,
+			})
+			c.Request = httptest.NewRequest("GET", "/v1/marketplaces/flash-sales", nil)
+
+			h.GetFlashSales(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestCreateFlashSaleCampaign(t *testing.T) {
+	var (
+		startTime = time.Date(2099, time.January, 1, 12, 0, 0, 0, time.UTC)
+		request   = &dto.CreateFlashSaleCampaignRequest{
+			Name:  "Payday Sale",
+			Slots: []*dto.FlashSaleSlotRequest{{StartTime: startTime, EndTime: startTime.Add(time.Hour)}},
+		}
+		campaign = &model.FlashSaleCampaign{ID: 1, Name: request.Name}
+	)
+
+	type input struct {
+		request    *dto.CreateFlashSaleCampaignRequest
+		beforeTest func(*mocks.FlashSaleService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 400 when slots are missing",
+			input: input{
+				request:    &dto.CreateFlashSaleCampaignRequest{Name: request.Name},
+				beforeTest: func(fss *mocks.FlashSaleService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Slots is required",
+				},
+			},
+		},
+		{
+			description: "should return error with status code 422 when slot is invalid",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("CreateCampaign", request).Return(nil, errs.ErrInvalidFlashSaleSlot)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusUnprocessableEntity,
+				response: response.Response{
+					Code:    code.INVALID_FLASH_SALE_SLOT,
+					Message: errs.ErrInvalidFlashSaleSlot.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to create campaign",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("CreateCampaign", request).Return(nil, errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return created campaign with status code 201 when succeed",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("CreateCampaign", request).Return(campaign, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "created",
+					Data:    campaign,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			flashSaleService := mocks.NewFlashSaleService(t)
+			tc.beforeTest(flashSaleService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h := handler.New(&handler.HandlerConfig{
+				FlashSaleService: flashSaleService,
+			})
+			c.Request = httptest.NewRequest("POST", "/v1/admins/marketplaces/flash-sales", testutil.MakeRequestBody(tc.input.request))
+
+			h.CreateFlashSaleCampaign(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestGetFlashSaleSubmissions(t *testing.T) {
+	var (
+		request = &dto.FlashSaleSubmissionFilterRequest{
+			Limit:      constant.DefaultFlashSaleSubmissionLimit,
+			Page:       1,
+			CampaignID: 1,
+		}
+		submissions = &commonDto.PaginationResponse{
+			TotalRows:  1,
+			TotalPages: 1,
+			Page:       1,
+			Limit:      constant.DefaultFlashSaleSubmissionLimit,
+			Data:       []*model.FlashSaleProduct{{ID: 1}},
+		}
+	)
+
+	type input struct {
+		campaignID string
+		beforeTest func(*mocks.FlashSaleService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when campaign id is invalid",
+			input: input{
+				campaignID: "abc",
+				beforeTest: func(fss *mocks.FlashSaleService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.FLASH_SALE_CAMPAIGN_NOT_FOUND,
+					Message: errs.ErrFlashSaleCampaignNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to get submissions",
+			input: input{
+				campaignID: "1",
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("GetSubmissions", request).Return(nil, errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return submissions with status code 200 when succeed",
+			input: input{
+				campaignID: "1",
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("GetSubmissions", request).Return(submissions, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    submissions,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			flashSaleService := mocks.NewFlashSaleService(t)
+			tc.beforeTest(flashSaleService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.AddParam("campaignId", tc.input.campaignID)
+			h := handler.New(&handler.HandlerConfig{
+				FlashSaleService: flashSaleService,
+			})
+			c.Request = httptest.NewRequest("GET", "/v1/admins/marketplaces/flash-sales/"+tc.input.campaignID+"/submissions", nil)
+
+			h.GetFlashSaleSubmissions(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestUpdateFlashSaleSubmissionStatus(t *testing.T) {
+	request := &dto.UpdateFlashSaleSubmissionRequest{Status: model.FlashSaleProductStatusApproved}
+
+	type input struct {
+		submissionID string
+		request      *dto.UpdateFlashSaleSubmissionRequest
+		beforeTest   func(*mocks.FlashSaleService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 400 when status is invalid",
+			input: input{
+				submissionID: "1",
+				request:      &dto.UpdateFlashSaleSubmissionRequest{Status: "unknown"},
+				beforeTest:   func(fss *mocks.FlashSaleService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Status must be either approved, or rejected",
+				},
+			},
+		},
+		{
+			description: "should return error with status code 404 when submission is not pending",
+			input: input{
+				submissionID: "1",
+				request:      request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("UpdateSubmissionStatus", 1, request).Return(errs.ErrFlashSaleSubmissionNotFound)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.FLASH_SALE_SUBMISSION_NOT_FOUND,
+					Message: errs.ErrFlashSaleSubmissionNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 422 when slot already started",
+			input: input{
+				submissionID: "1",
+				request:      request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("UpdateSubmissionStatus", 1, request).Return(errs.ErrFlashSaleSlotClosed)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusUnprocessableEntity,
+				response: response.Response{
+					Code:    code.FLASH_SALE_SLOT_CLOSED,
+					Message: errs.ErrFlashSaleSlotClosed.Error(),
+				},
+			},
+		},
+		{
+			description: "should return status code 200 when submission status updated",
+			input: input{
+				submissionID: "1",
+				request:      request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("UpdateSubmissionStatus", 1, request).Return(nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.UPDATED,
+					Message: "update successful",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			flashSaleService := mocks.NewFlashSaleService(t)
+			tc.beforeTest(flashSaleService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.AddParam("submissionId", tc.input.submissionID)
+			h := handler.New(&handler.HandlerConfig{
+				FlashSaleService: flashSaleService,
+			})
+			c.Request = httptest.NewRequest("PUT", "/v1/admins/marketplaces/flash-sales/submissions/"+tc.input.submissionID, testutil.MakeRequestBody(tc.input.request))
+
+			h.UpdateFlashSaleSubmissionStatus(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestSubmitFlashSaleSkus(t *testing.T) {
+	var (
+		userID  = 1
+		slotID  = 1
+		request = &dto.SubmitFlashSaleRequest{
+			Skus: []*dto.FlashSaleSkuRequest{{SkuID: 1, Type: shopModel.PromotionTypePercent, Amount: 0.2, Quota: 5, PurchaseLimit: 1}},
+		}
+		submissions = []*model.FlashSaleProduct{request.Skus[0].ToFlashSaleProduct(slotID, 1)}
+	)
+
+	type input struct {
+		request    *dto.SubmitFlashSaleRequest
+		beforeTest func(*mocks.FlashSaleService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 400 when skus are missing",
+			input: input{
+				request:    &dto.SubmitFlashSaleRequest{},
+				beforeTest: func(fss *mocks.FlashSaleService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Skus is required",
+				},
+			},
+		},
+		{
+			description: "should return error with status code 404 when slot not found",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("SubmitSkus", userID, slotID, request).Return(nil, errs.ErrFlashSaleSlotNotFound)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.FLASH_SALE_SLOT_NOT_FOUND,
+					Message: errs.ErrFlashSaleSlotNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 422 when slot is closed",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("SubmitSkus", userID, slotID, request).Return(nil, errs.ErrFlashSaleSlotClosed)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusUnprocessableEntity,
+				response: response.Response{
+					Code:    code.FLASH_SALE_SLOT_CLOSED,
+					Message: errs.ErrFlashSaleSlotClosed.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 409 when sku already submitted",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("SubmitSkus", userID, slotID, request).Return(nil, errs.ErrFlashSaleSkuAlreadySubmitted)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.FLASH_SALE_SKU_ALREADY_SUBMITTED,
+					Message: errs.ErrFlashSaleSkuAlreadySubmitted.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to submit skus",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("SubmitSkus", userID, slotID, request).Return(nil, errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return submissions with status code 201 when succeed",
+			input: input{
+				request: request,
+				beforeTest: func(fss *mocks.FlashSaleService) {
+					fss.On("SubmitSkus", userID, slotID, request).Return(submissions, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "created",
+					Data:    submissions,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			flashSaleService := mocks.NewFlashSaleService(t)
+			tc.beforeTest(flashSaleService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("shopOwnerId", userID)
+			c.AddParam("slotId", "1")
+			h := handler.New(&handler.HandlerConfig{
+				FlashSaleService: flashSaleService,
+			})
+			c.Request = httptest.NewRequest("POST", "/v1/sellers/flash-sales/slots/1/submissions", testutil.MakeRequestBody(tc.input.request))
+
+			h.SubmitFlashSaleSkus(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
@@ -5,16 +5,19 @@ import "kedai/backend/be-kedai/internal/domain/marketplace/service"
 type Handler struct {
 	marketplaceVoucherService service.MarketplaceVoucherService
 	marketplaceBannerService  service.MarketplaceBannerService
+	flashSaleService          service.FlashSaleService
 }
 
 type HandlerConfig struct {
 	MarketplaceVoucherService service.MarketplaceVoucherService
 	MarketplaceBannerService  service.MarketplaceBannerService
+	FlashSaleService          service.FlashSaleService
 }
 
 func New(cfg *HandlerConfig) *Handler {
 	return &Handler{
 		marketplaceVoucherService: cfg.MarketplaceVoucherService,
 		marketplaceBannerService:  cfg.MarketplaceBannerService,
+		flashSaleService:          cfg.FlashSaleService,
 	}
 }
@@ -0,0 +1,65 @@
+package model
+
+import (
+	productModel "kedai/backend/be-kedai/internal/domain/product/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FlashSaleCampaign struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	Slots []*FlashSaleSlot `json:"slots,omitempty" gorm:"foreignKey:CampaignID"`
+
+	gorm.Model `json:"-"`
+}
+
+type FlashSaleSlot struct {
+	ID         int       `json:"id"`
+	StartTime  time.Time `json:"startTime"`
+	EndTime    time.Time `json:"endTime"`
+	CampaignID int       `json:"campaignId"`
+
+	Campaign *FlashSaleCampaign  `json:"campaign,omitempty" gorm:"foreignKey:CampaignID"`
+	Products []*FlashSaleProduct `json:"products,omitempty" gorm:"foreignKey:SlotID"`
+
+	gorm.Model `json:"-"`
+}
+
+type FlashSaleProduct struct {
+	ID            int     `json:"id"`
+	Type          string  `json:"type"`
+	Amount        float64 `json:"amount"`
+	Quota         int     `json:"quota"`
+	Stock         int     `json:"stock"`
+	PurchaseLimit int     `json:"purchaseLimit"`
+	Status        string  `json:"status"`
+	SlotID        int     `json:"slotId"`
+	SkuID         int     `json:"skuId"`
+	ShopID        int     `json:"shopId"`
+
+	Slot *FlashSaleSlot    `json:"slot,omitempty" gorm:"foreignKey:SlotID"`
+	Sku  *productModel.Sku `json:"sku,omitempty" gorm:"foreignKey:SkuID"`
+
+	gorm.Model `json:"-"`
+}
+
+const (
+	FlashSaleProductStatusPending  = "pending"
+	FlashSaleProductStatusApproved = "approved"
+	FlashSaleProductStatusRejected = "rejected"
+)
+
+func (p *FlashSaleProduct) ToProductPromotion() *productModel.ProductPromotion {
+	return &productModel.ProductPromotion{
+		Type:          p.Type,
+		Amount:        p.Amount,
+		Stock:         p.Stock,
+		IsActive:      true,
+		PurchaseLimit: p.PurchaseLimit,
+		SkuId:         p.SkuID,
+	}
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/marketplace/dto"
+	"kedai/backend/be-kedai/internal/domain/marketplace/model"
+	productModel "kedai/backend/be-kedai/internal/domain/product/model"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FlashSaleRepository interface {
+	CreateCampaign(campaign *model.FlashSaleCampaign) error
+	GetSlotByID(slotID int) (*model.FlashSaleSlot, error)
+	GetOpenSlots() ([]*model.FlashSaleSlot, error)
+	GetUpcomingSlots(limit int) ([]*model.FlashSaleSlot, error)
+	GetApprovedProductsBySlotIDs(slotIDs []int) ([]*dto.FlashSaleProductResponse, error)
+	GetShopSku(shopID int, skuID int) (*productModel.Sku, error)
+	CreateProducts(products []*model.FlashSaleProduct) error
+	GetProducts(req *dto.FlashSaleSubmissionFilterRequest) ([]*model.FlashSaleProduct, int64, int, error)
+	UpdateProductStatus(productID int, status string) error
+	GetActiveProductBySkuID(skuID int) (*model.FlashSaleProduct, error)
+}
+
+type flashSaleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type FlashSaleRConfig struct {
+	DB *gorm.DB
+}
+
+func NewFlashSaleRepository(cfg *FlashSaleRConfig) FlashSaleRepository {
+	return &flashSaleRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *flashSaleRepositoryImpl) CreateCampaign(campaign *model.FlashSaleCampaign) error {
+	return r.db.Create(campaign).Error
+}
+
+func (r *flashSaleRepositoryImpl) GetSlotByID(slotID int) (*model.FlashSaleSlot, error) {
+	var slot model.FlashSaleSlot
+
+	err := r.db.Preload("Campaign").First(&slot, slotID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commonErr.ErrFlashSaleSlotNotFound
+		}
+
+		return nil, err
+	}
+
+	return &slot, nil
+}
+
+func (r *flashSaleRepositoryImpl) GetOpenSlots() ([]*model.FlashSaleSlot, error) {
+	var slots []*model.FlashSaleSlot
+
+	err := r.db.
+		Preload("Campaign").
+		Where("start_time > ?", time.Now()).
+		Order("start_time ASC").
+		Find(&slots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+func (r *flashSaleRepositoryImpl) GetUpcomingSlots(limit int) ([]*model.FlashSaleSlot, error) {
+	var slots []*model.FlashSaleSlot
+
+	err := r.db.
+		Preload("Campaign").
+		Where("end_time > ?", time.Now()).
+		Order("start_time ASC").
+		Limit(limit).
+		Find(&slots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+func (r *flashSaleRepositoryImpl) GetApprovedProductsBySlotIDs(slotIDs []int) ([]*dto.FlashSaleProductResponse, error) {
+	var products []*dto.FlashSaleProductResponse
+
+	err := r.db.Table("flash_sale_products fsp").
+		Select(`fsp.id, fsp.slot_id, fsp.sku_id, fsp.quota, fsp.stock, fsp.purchase_limit,
+		p.code AS product_code, p.name AS product_name, s.price AS original_price,
+		CASE WHEN fsp.type = 'percent' THEN s.price - (s.price * fsp.amount) ELSE s.price - fsp.amount END AS flash_sale_price,
+		(SELECT url FROM product_medias pm WHERE pm.product_id = p.id LIMIT 1) AS image_url`).
+		Joins("JOIN skus s ON s.id = fsp.sku_id AND s.deleted_at IS NULL").
+		Joins("JOIN products p ON p.id = s.product_id AND p.deleted_at IS NULL").
+		Where("fsp.slot_id IN ?", slotIDs).
+		Where("fsp.status = ?", model.FlashSaleProductStatusApproved).
+		Where("fsp.deleted_at IS NULL").
+		Where("p.is_active").
+		Where("p.moderation_status = ?", constant.ModerationStatusApproved).
+		Order("fsp.stock DESC").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *flashSaleRepositoryImpl) GetShopSku(shopID int, skuID int) (*productModel.Sku, error) {
+	var sku productModel.Sku
+
+	err := r.db.
+		Joins("JOIN products ON products.id = skus.product_id").
+		Where("products.shop_id = ?", shopID).
+		First(&sku, skuID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commonErr.ErrSKUDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	return &sku, nil
+}
+
+func (r *flashSaleRepositoryImpl) CreateProducts(products []*model.FlashSaleProduct) error {
+	tx := r.db.Begin()
+	defer tx.Commit()
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&products)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if int(res.RowsAffected) != len(products) {
+		tx.Rollback()
+		return commonErr.ErrFlashSaleSkuAlreadySubmitted
+	}
+
+	return nil
+}
+
+func (r *flashSaleRepositoryImpl) GetProducts(req *dto.FlashSaleSubmissionFilterRequest) ([]*model.FlashSaleProduct, int64, int, error) {
+	var (
+		products   []*model.FlashSaleProduct
+		totalRows  int64
+		totalPages int
+	)
+
+	query := r.db.Model(&model.FlashSaleProduct{})
+	if req.CampaignID != 0 {
+		query = query.Joins("JOIN flash_sale_slots ON flash_sale_slots.id = flash_sale_products.slot_id").
+			Where("flash_sale_slots.campaign_id = ?", req.CampaignID)
+	}
+	if req.ShopID != 0 {
+		query = query.Where("flash_sale_products.shop_id = ?", req.ShopID)
+	}
+	if req.Status != "" {
+		query = query.Where("flash_sale_products.status = ?", req.Status)
+	}
+
+	err := query.Count(&totalRows).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	totalPages = int(math.Ceil(float64(totalRows) / float64(req.Limit)))
+
+	err = query.
+		Preload("Slot").
+		Preload("Sku.Product").
+		Order("flash_sale_products.created_at DESC").
+		Limit(req.Limit).
+		Offset(req.Offset()).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return products, totalRows, totalPages, nil
+}
+
+// UpdateProductStatus reviews a pending submission. A submission can only be
+// approved while its slot has not started yet.
+func (r *flashSaleRepositoryImpl) UpdateProductStatus(productID int, status string) error {
+	query := r.db.Model(&model.FlashSaleProduct{}).
+		Where("id = ?", productID).
+		Where("status = ?", model.FlashSaleProductStatusPending)
+	if status == model.FlashSaleProductStatusApproved {
+		query = query.Where("slot_id IN (?)", r.db.Model(&model.FlashSaleSlot{}).Select("id").Where("start_time > ?", time.Now()))
+	}
+
+	res := query.Update("status", status)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		var pending int64
+		err := r.db.Model(&model.FlashSaleProduct{}).
+			Where("id = ?", productID).
+			Where("status = ?", model.FlashSaleProductStatusPending).
+			Count(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		if pending > 0 {
+			return commonErr.ErrFlashSaleSlotClosed
+		}
+
+		return commonErr.ErrFlashSaleSubmissionNotFound
+	}
+
+	return nil
+}
+
+func (r *flashSaleRepositoryImpl) GetActiveProductBySkuID(skuID int) (*model.FlashSaleProduct, error) {
+	var products []*model.FlashSaleProduct
+
+	now := time.Now()
+	err := r.db.
+		Joins("JOIN flash_sale_slots ON flash_sale_slots.id = flash_sale_products.slot_id").
+		Where("flash_sale_products.sku_id = ?", skuID).
+		Where("flash_sale_products.status = ?", model.FlashSaleProductStatusApproved).
+		Where("flash_sale_products.stock > 0").
+		Where("? BETWEEN flash_sale_slots.start_time AND flash_sale_slots.end_time", now).
+		Order("flash_sale_slots.end_time ASC").
+		Limit(1).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(products) == 0 {
+		return nil, nil
+	}
+
+	return products[0], nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	commonErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/marketplace/dto"
+	"kedai/backend/be-kedai/internal/domain/marketplace/model"
+	"kedai/backend/be-kedai/internal/domain/marketplace/repository"
+	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
+	"time"
+)
+
+type FlashSaleService interface {
+	CreateCampaign(req *dto.CreateFlashSaleCampaignRequest) (*model.FlashSaleCampaign, error)
+	GetSubmissions(req *dto.FlashSaleSubmissionFilterRequest) (*commonDto.PaginationResponse, error)
+	UpdateSubmissionStatus(submissionID int, req *dto.UpdateFlashSaleSubmissionRequest) error
+	GetOpenSlots() ([]*model.FlashSaleSlot, error)
+	SubmitSkus(userID int, slotID int, req *dto.SubmitFlashSaleRequest) ([]*model.FlashSaleProduct, error)
+	GetSellerSubmissions(userID int, req *dto.FlashSaleSubmissionFilterRequest) (*commonDto.PaginationResponse, error)
+	GetFlashSales() ([]*dto.FlashSaleSlotResponse, error)
+	GetActiveBySkuID(skuID int) (*model.FlashSaleProduct, error)
+}
+
+type flashSaleServiceImpl struct {
+	flashSaleRepository repository.FlashSaleRepository
+	shopService         shopService.ShopService
+}
+
+type FlashSaleSConfig struct {
+	FlashSaleRepository repository.FlashSaleRepository
+	ShopService         shopService.ShopService
+}
+
+func NewFlashSaleService(cfg *FlashSaleSConfig) FlashSaleService {
+	return &flashSaleServiceImpl{
+		flashSaleRepository: cfg.FlashSaleRepository,
+		shopService:         cfg.ShopService,
+	}
+}
+
+func (s *flashSaleServiceImpl) CreateCampaign(req *dto.CreateFlashSaleCampaignRequest) (*model.FlashSaleCampaign, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	campaign := req.ToCampaign()
+
+	err := s.flashSaleRepository.CreateCampaign(campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+func (s *flashSaleServiceImpl) GetSubmissions(req *dto.FlashSaleSubmissionFilterRequest) (*commonDto.PaginationResponse, error) {
+	submissions, totalRows, totalPages, err := s.flashSaleRepository.GetProducts(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commonDto.PaginationResponse{
+		TotalRows:  totalRows,
+		TotalPages: totalPages,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		Data:       submissions,
+	}, nil
+}
+
+func (s *flashSaleServiceImpl) UpdateSubmissionStatus(submissionID int, req *dto.UpdateFlashSaleSubmissionRequest) error {
+	return s.flashSaleRepository.UpdateProductStatus(submissionID, req.Status)
+}
+
+func (s *flashSaleServiceImpl) GetOpenSlots() ([]*model.FlashSaleSlot, error) {
+	return s.flashSaleRepository.GetOpenSlots()
+}
+
+func (s *flashSaleServiceImpl) SubmitSkus(userID int, slotID int, req *dto.SubmitFlashSaleRequest) ([]*model.FlashSaleProduct, error) {
+	shop, err := s.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := s.flashSaleRepository.GetSlotByID(slotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slot.StartTime.After(time.Now()) {
+		return nil, commonErr.ErrFlashSaleSlotClosed
+	}
+
+	var products []*model.FlashSaleProduct
+	for _, skuReq := range req.Skus {
+		sku, err := s.flashSaleRepository.GetShopSku(shop.ID, skuReq.SkuID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := skuReq.Validate(sku.Price); err != nil {
+			return nil, err
+		}
+
+		if skuReq.Quota > sku.Stock {
+			return nil, commonErr.ErrFlashSaleQuotaExceedsStock
+		}
+
+		products = append(products, skuReq.ToFlashSaleProduct(slot.ID, shop.ID))
+	}
+
+	err = s.flashSaleRepository.CreateProducts(products)
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (s *flashSaleServiceImpl) GetSellerSubmissions(userID int, req *dto.FlashSaleSubmissionFilterRequest) (*commonDto.PaginationResponse, error) {
+	shop, err := s.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ShopID = shop.ID
+
+	return s.GetSubmissions(req)
+}
+
+func (s *flashSaleServiceImpl) GetFlashSales() ([]*dto.FlashSaleSlotResponse, error) {
+	slots, err := s.flashSaleRepository.GetUpcomingSlots(constant.DefaultFlashSaleSlotLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []*dto.FlashSaleSlotResponse{}
+	if len(slots) == 0 {
+		return responses, nil
+	}
+
+	var (
+		slotIDs        []int
+		responseBySlot = map[int]*dto.FlashSaleSlotResponse{}
+		now            = time.Now()
+	)
+	for _, slot := range slots {
+		response := &dto.FlashSaleSlotResponse{
+			ID:         slot.ID,
+			CampaignID: slot.CampaignID,
+			StartTime:  slot.StartTime,
+			EndTime:    slot.EndTime,
+			IsActive:   !now.Before(slot.StartTime) && now.Before(slot.EndTime),
+			Products:   []*dto.FlashSaleProductResponse{},
+		}
+		if slot.Campaign != nil {
+			response.CampaignName = slot.Campaign.Name
+		}
+
+		slotIDs = append(slotIDs, slot.ID)
+		responseBySlot[slot.ID] = response
+		responses = append(responses, response)
+	}
+
+	products, err := s.flashSaleRepository.GetApprovedProductsBySlotIDs(slotIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		if response, ok := responseBySlot[product.SlotID]; ok {
+			response.Products = append(response.Products, product)
+		}
+	}
+
+	return responses, nil
+}
+
+func (s *flashSaleServiceImpl) GetActiveBySkuID(skuID int) (*model.FlashSaleProduct, error) {
+	return s.flashSaleRepository.GetActiveProductBySkuID(skuID)
+}
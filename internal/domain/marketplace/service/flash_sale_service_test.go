@@ -0,0 +1,448 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/marketplace/dto"
+	"kedai/backend/be-kedai/internal/domain/marketplace/model"
+	"kedai/backend/be-kedai/internal/domain/marketplace/service"
+	productModel "kedai/backend/be-kedai/internal/domain/product/model"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateCampaign(t *testing.T) {
+	var (
+		startTime = time.Now().Add(time.Hour)
+		endTime   = startTime.Add(2 * time.Hour)
+		request   = &dto.CreateFlashSaleCampaignRequest{
+			Name:  "Payday Sale",
+			Slots: []*dto.FlashSaleSlotRequest{{StartTime: startTime, EndTime: endTime}},
+		}
+		campaign = request.ToCampaign()
+	)
+
+	type input struct {
+		request    *dto.CreateFlashSaleCampaignRequest
+		beforeTest func(*mocks.FlashSaleRepository)
+	}
+	type expected struct {
+		result *model.FlashSaleCampaign
+		err    error
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when slot ends before it starts",
+			input: input{
+				request: &dto.CreateFlashSaleCampaignRequest{
+					Name:  "Payday Sale",
+					Slots: []*dto.FlashSaleSlotRequest{{StartTime: endTime, EndTime: startTime}},
+				},
+				beforeTest: func(fsr *mocks.FlashSaleRepository) {},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrInvalidFlashSaleSlot,
+			},
+		},
+		{
+			description: "should return error when failed to create campaign",
+			input: input{
+				request: request,
+				beforeTest: func(fsr *mocks.FlashSaleRepository) {
+					fsr.On("CreateCampaign", campaign).Return(errors.New("failed to create campaign"))
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    errors.New("failed to create campaign"),
+			},
+		},
+		{
+			description: "should return created campaign when succeed",
+			input: input{
+				request: request,
+				beforeTest: func(fsr *mocks.FlashSaleRepository) {
+					fsr.On("CreateCampaign", campaign).Return(nil)
+				},
+			},
+			expected: expected{
+				result: campaign,
+				err:    nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			flashSaleRepo := mocks.NewFlashSaleRepository(t)
+			tc.beforeTest(flashSaleRepo)
+			s := service.NewFlashSaleService(&service.FlashSaleSConfig{
+				FlashSaleRepository: flashSaleRepo,
+			})
+
+			result, err := s.CreateCampaign(tc.input.request)
+
+			assert.Equal(t, tc.expected.err, err)
+			assert.Equal(t, tc.expected.result, result)
+		})
+	}
+}
+
+func TestUpdateSubmissionStatus(t *testing.T) {
+	var (
+		submissionID = 1
+		request      = &dto.UpdateFlashSaleSubmissionRequest{Status: model.FlashSaleProductStatusApproved}
+	)
+
+	cases := []struct {
+		description string
+		mockErr     error
+		expected    error
+	}{
+		{
+			description: "should return error when submission is not pending",
+			mockErr:     commonErr.ErrFlashSaleSubmissionNotFound,
+			expected:    commonErr.ErrFlashSaleSubmissionNotFound,
+		},
+		{
+			description: "should return nil when submission status updated",
+			mockErr:     nil,
+			expected:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			flashSaleRepo := mocks.NewFlashSaleRepository(t)
+			flashSaleRepo.On("UpdateProductStatus", submissionID, request.Status).Return(tc.mockErr)
+			s := service.NewFlashSaleService(&service.FlashSaleSConfig{
+				FlashSaleRepository: flashSaleRepo,
+			})
+
+			err := s.UpdateSubmissionStatus(submissionID, request)
+
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestSubmitSkus(t *testing.T) {
+	var (
+		userID   = 1
+		slotID   = 1
+		shop     = &shopModel.Shop{ID: 1}
+		openSlot = &model.FlashSaleSlot{ID: slotID, StartTime: time.Now().Add(time.Hour), EndTime: time.Now().Add(2 * time.Hour)}
+		sku      = &productModel.Sku{ID: 1, Price: 10000, Stock: 10}
+		skuReq   = &dto.FlashSaleSkuRequest{SkuID: 1, Type: shopModel.PromotionTypePercent, Amount: 0.2, Quota: 5, PurchaseLimit: 1}
+		request  = &dto.SubmitFlashSaleRequest{Skus: []*dto.FlashSaleSkuRequest{skuReq}}
+		products = []*model.FlashSaleProduct{skuReq.ToFlashSaleProduct(slotID, shop.ID)}
+	)
+
+	type input struct {
+		request    *dto.SubmitFlashSaleRequest
+		beforeTest func(*mocks.ShopService, *mocks.FlashSaleRepository)
+	}
+	type expected struct {
+		result []*model.FlashSaleProduct
+		err    error
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when shop not found",
+			input: input{
+				request: request,
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(nil, commonErr.ErrShopNotFound)
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrShopNotFound,
+			},
+		},
+		{
+			description: "should return error when slot not found",
+			input: input{
+				request: request,
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					fsr.On("GetSlotByID", slotID).Return(nil, commonErr.ErrFlashSaleSlotNotFound)
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrFlashSaleSlotNotFound,
+			},
+		},
+		{
+			description: "should return error when slot has already started",
+			input: input{
+				request: request,
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					fsr.On("GetSlotByID", slotID).Return(&model.FlashSaleSlot{ID: slotID, StartTime: time.Now().Add(-time.Hour)}, nil)
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrFlashSaleSlotClosed,
+			},
+		},
+		{
+			description: "should return error when sku does not belong to shop",
+			input: input{
+				request: request,
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					fsr.On("GetSlotByID", slotID).Return(openSlot, nil)
+					fsr.On("GetShopSku", shop.ID, skuReq.SkuID).Return(nil, commonErr.ErrSKUDoesNotExist)
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrSKUDoesNotExist,
+			},
+		},
+		{
+			description: "should return error when nominal discount is not lower than sku price",
+			input: input{
+				request: &dto.SubmitFlashSaleRequest{Skus: []*dto.FlashSaleSkuRequest{{SkuID: 1, Type: shopModel.PromotionTypeNominal, Amount: 10000, Quota: 5, PurchaseLimit: 1}}},
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					fsr.On("GetSlotByID", slotID).Return(openSlot, nil)
+					fsr.On("GetShopSku", shop.ID, skuReq.SkuID).Return(sku, nil)
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrInvalidFlashSaleDiscount,
+			},
+		},
+		{
+			description: "should return error when quota exceeds sku stock",
+			input: input{
+				request: &dto.SubmitFlashSaleRequest{Skus: []*dto.FlashSaleSkuRequest{{SkuID: 1, Type: shopModel.PromotionTypePercent, Amount: 0.2, Quota: 11, PurchaseLimit: 1}}},
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					fsr.On("GetSlotByID", slotID).Return(openSlot, nil)
+					fsr.On("GetShopSku", shop.ID, skuReq.SkuID).Return(sku, nil)
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrFlashSaleQuotaExceedsStock,
+			},
+		},
+		{
+			description: "should return error when sku already submitted to slot",
+			input: input{
+				request: request,
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					fsr.On("GetSlotByID", slotID).Return(openSlot, nil)
+					fsr.On("GetShopSku", shop.ID, skuReq.SkuID).Return(sku, nil)
+					fsr.On("CreateProducts", products).Return(commonErr.ErrFlashSaleSkuAlreadySubmitted)
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    commonErr.ErrFlashSaleSkuAlreadySubmitted,
+			},
+		},
+		{
+			description: "should return pending submissions when succeed",
+			input: input{
+				request: request,
+				beforeTest: func(ss *mocks.ShopService, fsr *mocks.FlashSaleRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					fsr.On("GetSlotByID", slotID).Return(openSlot, nil)
+					fsr.On("GetShopSku", shop.ID, skuReq.SkuID).Return(sku, nil)
+					fsr.On("CreateProducts", products).Return(nil)
+				},
+			},
+			expected: expected{
+				result: products,
+				err:    nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			shopService := mocks.NewShopService(t)
+			flashSaleRepo := mocks.NewFlashSaleRepository(t)
+			tc.beforeTest(shopService, flashSaleRepo)
+			s := service.NewFlashSaleService(&service.FlashSaleSConfig{
+				FlashSaleRepository: flashSaleRepo,
+				ShopService:         shopService,
+			})
+
+			result, err := s.SubmitSkus(userID, slotID, tc.input.request)
+
+			assert.Equal(t, tc.expected.err, err)
+			assert.Equal(t, tc.expected.result, result)
+		})
+	}
+}
+
+func TestGetFlashSales(t *testing.T) {
+	var (
+		now      = time.Now()
+		campaign = &model.FlashSaleCampaign{ID: 1, Name: "Payday Sale"}
+		active   = &model.FlashSaleSlot{ID: 1, StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour), CampaignID: 1, Campaign: campaign}
+		upcoming = &model.FlashSaleSlot{ID: 2, StartTime: now.Add(2 * time.Hour), EndTime: now.Add(3 * time.Hour), CampaignID: 1, Campaign: campaign}
+		product  = &dto.FlashSaleProductResponse{ID: 1, SlotID: 1, SkuID: 1, OriginalPrice: 10000, FlashSalePrice: 8000, Quota: 5, Stock: 3}
+	)
+
+	type input struct {
+		beforeTest func(*mocks.FlashSaleRepository)
+	}
+	type expected struct {
+		result []*dto.FlashSaleSlotResponse
+		err    error
+	}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when failed to get upcoming slots",
+			input: input{
+				beforeTest: func(fsr *mocks.FlashSaleRepository) {
+					fsr.On("GetUpcomingSlots", constant.DefaultFlashSaleSlotLimit).Return(nil, errors.New("failed to get slots"))
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    errors.New("failed to get slots"),
+			},
+		},
+		{
+			description: "should return empty list when there is no upcoming slot",
+			input: input{
+				beforeTest: func(fsr *mocks.FlashSaleRepository) {
+					fsr.On("GetUpcomingSlots", constant.DefaultFlashSaleSlotLimit).Return([]*model.FlashSaleSlot{}, nil)
+				},
+			},
+			expected: expected{
+				result: []*dto.FlashSaleSlotResponse{},
+				err:    nil,
+			},
+		},
+		{
+			description: "should return error when failed to get slot products",
+			input: input{
+				beforeTest: func(fsr *mocks.FlashSaleRepository) {
+					fsr.On("GetUpcomingSlots", constant.DefaultFlashSaleSlotLimit).Return([]*model.FlashSaleSlot{active, upcoming}, nil)
+					fsr.On("GetApprovedProductsBySlotIDs", []int{active.ID, upcoming.ID}).Return(nil, errors.New("failed to get products"))
+				},
+			},
+			expected: expected{
+				result: nil,
+				err:    errors.New("failed to get products"),
+			},
+		},
+		{
+			description: "should return active and upcoming slots with their products when succeed",
+			input: input{
+				beforeTest: func(fsr *mocks.FlashSaleRepository) {
+					fsr.On("GetUpcomingSlots", constant.DefaultFlashSaleSlotLimit).Return([]*model.FlashSaleSlot{active, upcoming}, nil)
+					fsr.On("GetApprovedProductsBySlotIDs", []int{active.ID, upcoming.ID}).Return([]*dto.FlashSaleProductResponse{product}, nil)
+				},
+			},
+			expected: expected{
+				result: []*dto.FlashSaleSlotResponse{
+					{
+						ID:           active.ID,
+						CampaignID:   campaign.ID,
+						CampaignName: campaign.Name,
+						StartTime:    active.StartTime,
+						EndTime:      active.EndTime,
+						IsActive:     true,
+						Products:     []*dto.FlashSaleProductResponse{product},
+					},
+					{
+						ID:           upcoming.ID,
+						CampaignID:   campaign.ID,
+						CampaignName: campaign.Name,
+						StartTime:    upcoming.StartTime,
+						EndTime:      upcoming.EndTime,
+						IsActive:     false,
+						Products:     []*dto.FlashSaleProductResponse{},
+					},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			flashSaleRepo := mocks.NewFlashSaleRepository(t)
+			tc.beforeTest(flashSaleRepo)
+			s := service.NewFlashSaleService(&service.FlashSaleSConfig{
+				FlashSaleRepository: flashSaleRepo,
+			})
+
+			result, err := s.GetFlashSales()
+
+			assert.Equal(t, tc.expected.err, err)
+			assert.Equal(t, tc.expected.result, result)
+		})
+	}
+}
+
+func TestGetActiveBySkuID(t *testing.T) {
+	flashSaleProduct := &model.FlashSaleProduct{ID: 1, SkuID: 1, Status: model.FlashSaleProductStatusApproved}
+
+	cases := []struct {
+		description string
+		mockData    *model.FlashSaleProduct
+		mockErr     error
+	}{
+		{
+			description: "should return nil when sku has no active flash sale",
+			mockData:    nil,
+			mockErr:     nil,
+		},
+		{
+			description: "should return active flash sale product when exists",
+			mockData:    flashSaleProduct,
+			mockErr:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			flashSaleRepo := mocks.NewFlashSaleRepository(t)
+			flashSaleRepo.On("GetActiveProductBySkuID", mock.Anything).Return(tc.mockData, tc.mockErr)
+			s := service.NewFlashSaleService(&service.FlashSaleSConfig{
+				FlashSaleRepository: flashSaleRepo,
+			})
+
+			result, err := s.GetActiveBySkuID(1)
+
+			assert.Equal(t, tc.mockErr, err)
+			assert.Equal(t, tc.mockData, result)
+		})
+	}
+}
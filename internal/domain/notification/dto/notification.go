@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
+	"time"
+)
+
+// NotificationEvent is what other domains publish. Data fills the template
+// of Type and is stored with the notification so clients can deep link.
+type NotificationEvent struct {
+	UserID int
+	Type   string
+	Data   map[string]interface{}
+}
+
+type GetNotificationsRequest struct {
+	Page       int  `form:"page"`
+	Limit      int  `form:"limit"`
+	UnreadOnly bool `form:"unreadOnly"`
+}
+
+func (req *GetNotificationsRequest) Validate() {
+	if req.Limit < 1 {
+		req.Limit = constant.DefaultNotificationLimit
+	}
+
+	if req.Limit > constant.MaxNotificationLimit {
+		req.Limit = constant.MaxNotificationLimit
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+}
+
+func (req *GetNotificationsRequest) Offset() int {
+	return (req.Page - 1) * req.Limit
+}
+
+type NotificationListResponse struct {
+	Data        interface{} `json:"data"`
+	Limit       int         `json:"limit"`
+	Page        int         `json:"page"`
+	TotalRows   int64       `json:"totalRows"`
+	TotalPages  int         `json:"totalPages"`
+	UnreadCount int64       `json:"unreadCount"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unreadCount"`
+}
+
+type ExpiringVoucher struct {
+	UserID        int
+	UserVoucherID int
+	Code          string
+	Name          string
+	ExpiredAt     time.Time
+}
+
+// UserRoomID is the socket room every connection of userId joins, used to
+// push notifications in real time.
+func UserRoomID(userId int) string {
+	return fmt.Sprintf("user:%d", userId)
+}
+
+// EmailRequest asks for Template to be rendered with Data and sent to To.
+// An empty Locale uses the configured default. When UserID is set the email
+// honours that user's preferences for Category and carries an unsubscribe
+// link.
+type EmailRequest struct {
+	To       string
+	UserID   int
+	Category string
+	Template string
+	Locale   string
+	Data     interface{}
+}
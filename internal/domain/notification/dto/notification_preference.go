@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"encoding/base64"
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"strconv"
+	"strings"
+)
+
+type NotificationPreference struct {
+	Category string `json:"category"`
+	Email    bool   `json:"email"`
+	InApp    bool   `json:"inApp"`
+	Push     bool   `json:"push"`
+}
+
+type NotificationPreferencesResponse struct {
+	Preferences []*NotificationPreference `json:"preferences"`
+}
+
+// NewNotificationPreferencesResponse lists every category with the stored
+// choices applied over the enabled default.
+func NewNotificationPreferencesResponse(stored []*model.NotificationPreference) *NotificationPreferencesResponse {
+	enabled := map[string]bool{}
+	for _, pref := range stored {
+		enabled[pref.Category+":"+pref.Channel] = pref.Enabled
+	}
+
+	isEnabled := func(category string, channel string) bool {
+		if category == constant.NotificationCategorySecurity {
+			return true
+		}
+		value, ok := enabled[category+":"+channel]
+		return !ok || value
+	}
+
+	res := &NotificationPreferencesResponse{}
+	for _, category := range constant.NotificationCategories {
+		res.Preferences = append(res.Preferences, &NotificationPreference{
+			Category: category,
+			Email:    isEnabled(category, constant.NotificationChannelEmail),
+			InApp:    isEnabled(category, constant.NotificationChannelInApp),
+			Push:     isEnabled(category, constant.NotificationChannelPush),
+		})
+	}
+
+	return res
+}
+
+// NotificationPreferenceRequest leaves channels that are omitted unchanged.
+type NotificationPreferenceRequest struct {
+	Category string `json:"category" binding:"required,oneof=orders promotions chat security"`
+	Email    *bool  `json:"email"`
+	InApp    *bool  `json:"inApp"`
+	Push     *bool  `json:"push"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []*NotificationPreferenceRequest `json:"preferences" binding:"required,min=1,dive"`
+}
+
+func (req *UpdateNotificationPreferencesRequest) ToModels(userId int) ([]*model.NotificationPreference, error) {
+	var prefs []*model.NotificationPreference
+	for _, pref := range req.Preferences {
+		channels := map[string]*bool{
+			constant.NotificationChannelEmail: pref.Email,
+			constant.NotificationChannelInApp: pref.InApp,
+			constant.NotificationChannelPush:  pref.Push,
+		}
+
+		for _, channel := range constant.NotificationChannels {
+			enabled := channels[channel]
+			if enabled == nil {
+				continue
+			}
+			if pref.Category == constant.NotificationCategorySecurity && !*enabled {
+				return nil, errs.ErrSecurityNotificationRequired
+			}
+
+			prefs = append(prefs, &model.NotificationPreference{
+				UserID:   userId,
+				Category: pref.Category,
+				Channel:  channel,
+				Enabled:  *enabled,
+			})
+		}
+	}
+
+	return prefs, nil
+}
+
+// UnsubscribeToken identifies what a one-click unsubscribe link turns off.
+// It is signed with the token signing key so links can't be forged for other
+// users, and does not expire since emails are kept for a long time.
+type UnsubscribeToken struct {
+	UserID   int
+	Category string
+	Channel  string
+}
+
+func (t *UnsubscribeToken) payload() string {
+	return fmt.Sprintf("%d:%s:%s", t.UserID, t.Category, t.Channel)
+}
+
+func (t *UnsubscribeToken) Sign() string {
+	payload := t.payload()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + hash.SignToken(payload)
+}
+
+func ParseUnsubscribeToken(token string) (*UnsubscribeToken, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errs.ErrInvalidUnsubscribeToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errs.ErrInvalidUnsubscribeToken
+	}
+
+	if !hash.VerifyToken(string(payload), signature) {
+		return nil, errs.ErrInvalidUnsubscribeToken
+	}
+
+	parts := strings.Split(string(payload), ":")
+	if len(parts) != 3 || parts[1] == constant.NotificationCategorySecurity {
+		return nil, errs.ErrInvalidUnsubscribeToken
+	}
+
+	userId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, errs.ErrInvalidUnsubscribeToken
+	}
+
+	return &UnsubscribeToken{
+		UserID:   userId,
+		Category: parts[1],
+		Channel:  parts[2],
+	}, nil
+}
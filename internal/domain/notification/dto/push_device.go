@@ -0,0 +1,51 @@
+package dto
+
+import (
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/utils/push"
+)
+
+type WebPushKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// RegisterDeviceRequest takes a browser PushSubscription (endpoint and keys)
+// for web, or an FCM registration token for android and ios.
+type RegisterDeviceRequest struct {
+	Platform string       `json:"platform" binding:"required,oneof=web android ios"`
+	Token    string       `json:"token"`
+	Endpoint string       `json:"endpoint"`
+	Keys     *WebPushKeys `json:"keys"`
+}
+
+func (req *RegisterDeviceRequest) ToModel(userId int, userAgent string) (*model.PushDevice, error) {
+	device := &model.PushDevice{
+		UserID:    userId,
+		Platform:  req.Platform,
+		UserAgent: userAgent,
+	}
+
+	if req.Platform == push.PlatformWeb {
+		if req.Endpoint == "" || req.Keys == nil || req.Keys.P256dh == "" || req.Keys.Auth == "" {
+			return nil, errs.ErrInvalidPushSubscription
+		}
+		device.Token = req.Endpoint
+		device.P256dh = req.Keys.P256dh
+		device.Auth = req.Keys.Auth
+
+		return device, nil
+	}
+
+	if req.Token == "" {
+		return nil, errs.ErrInvalidPushSubscription
+	}
+	device.Token = req.Token
+
+	return device, nil
+}
+
+type VAPIDKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
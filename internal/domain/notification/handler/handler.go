@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/domain/notification/service"
+)
+
+type Handler struct {
+	notificationService           service.NotificationService
+	emailService                  service.EmailService
+	notificationPreferenceService service.NotificationPreferenceService
+	pushService                   service.PushService
+}
+
+type Config struct {
+	NotificationService           service.NotificationService
+	EmailService                  service.EmailService
+	NotificationPreferenceService service.NotificationPreferenceService
+	PushService                   service.PushService
+}
+
+func New(cfg *Config) *Handler {
+	return &Handler{
+		notificationService:           cfg.NotificationService,
+		emailService:                  cfg.EmailService,
+		notificationPreferenceService: cfg.NotificationPreferenceService,
+		pushService:                   cfg.PushService,
+	}
+}
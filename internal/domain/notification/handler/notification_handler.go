@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/common/code"
+	spErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetNotifications(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	var req dto.GetNotificationsRequest
+	_ = c.ShouldBindQuery(&req)
+	req.Validate()
+
+	result, err := h.notificationService.GetNotifications(userID, &req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+func (h *Handler) GetUnreadNotificationCount(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	result, err := h.notificationService.GetUnreadCount(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	notificationID, err := strconv.Atoi(c.Param("notificationId"))
+	if err != nil || notificationID < 1 {
+		response.Error(c, http.StatusNotFound, code.NOT_FOUND, spErr.ErrNotificationNotFound.Error())
+		return
+	}
+
+	err = h.notificationService.MarkRead(userID, notificationID)
+	if err != nil {
+		if err == spErr.ErrNotificationNotFound {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", nil)
+}
+
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	err := h.notificationService.MarkAllRead(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", nil)
+}
+
+func (h *Handler) VoucherExpiryCronJob(c *gin.Context) {
+	_ = h.notificationService.VoucherExpiryCRONJob()
+	log.Println("VOUCHER EXPIRY CRON JOB")
+}
+
+func (h *Handler) EmailOutboxCronJob(c *gin.Context) {
+	_ = h.emailService.DispatchOutbox()
+	log.Println("EMAIL OUTBOX CRON JOB")
+}
@@ -0,0 +1,181 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/handler"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotifications(t *testing.T) {
+	var (
+		userId = 1
+		req    = &dto.GetNotificationsRequest{Page: 1, Limit: 10, UnreadOnly: true}
+		result = &dto.NotificationListResponse{
+			Data:        []*model.Notification{{ID: 1, UserID: userId, Title: "Order INV/1 updated"}},
+			Limit:       10,
+			Page:        1,
+			TotalRows:   1,
+			TotalPages:  1,
+			UnreadCount: 1,
+		}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		beforeTests func(ns *mocks.NotificationService)
+		expected
+	}{
+		{
+			description: "should return notifications with code 200 when success",
+			beforeTests: func(ns *mocks.NotificationService) {
+				ns.On("GetNotifications", userId, req).Return(result, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    result,
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			beforeTests: func(ns *mocks.NotificationService) {
+				ns.On("GetNotifications", userId, req).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockNotificationService := mocks.NewNotificationService(t)
+			tc.beforeTests(mockNotificationService)
+			handler := handler.New(&handler.Config{
+				NotificationService: mockNotificationService,
+			})
+			c.Set("userId", userId)
+			c.Request, _ = http.NewRequest("GET", "/users/notifications?unreadOnly=true", nil)
+
+			handler.GetNotifications(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	var userId = 1
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description    string
+		notificationId string
+		beforeTests    func(ns *mocks.NotificationService)
+		expected
+	}{
+		{
+			description:    "should return code 200 when success",
+			notificationId: "5",
+			beforeTests: func(ns *mocks.NotificationService) {
+				ns.On("MarkRead", userId, 5).Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+				},
+			},
+		},
+		{
+			description:    "should return error with code 404 when id is invalid",
+			notificationId: "abc",
+			beforeTests:    func(ns *mocks.NotificationService) {},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrNotificationNotFound.Error(),
+				},
+			},
+		},
+		{
+			description:    "should return error with code 404 when notification not found",
+			notificationId: "5",
+			beforeTests: func(ns *mocks.NotificationService) {
+				ns.On("MarkRead", userId, 5).Return(errs.ErrNotificationNotFound)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrNotificationNotFound.Error(),
+				},
+			},
+		},
+		{
+			description:    "should return error with code 500 when error",
+			notificationId: "5",
+			beforeTests: func(ns *mocks.NotificationService) {
+				ns.On("MarkRead", userId, 5).Return(errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockNotificationService := mocks.NewNotificationService(t)
+			tc.beforeTests(mockNotificationService)
+			handler := handler.New(&handler.Config{
+				NotificationService: mockNotificationService,
+			})
+			c.Set("userId", userId)
+			c.AddParam("notificationId", tc.notificationId)
+			c.Request, _ = http.NewRequest("PUT", "/users/notifications/"+tc.notificationId+"/read", nil)
+
+			handler.MarkNotificationRead(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
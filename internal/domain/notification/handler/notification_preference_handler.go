@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/common/code"
+	spErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetNotificationPreferences(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	result, err := h.notificationPreferenceService.GetPreferences(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+func (h *Handler) UpdateNotificationPreferences(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	var req dto.UpdateNotificationPreferencesRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.notificationPreferenceService.UpdatePreferences(userID, &req)
+	if err != nil {
+		if err == spErr.ErrSecurityNotificationRequired {
+			response.Error(c, http.StatusBadRequest, code.SECURITY_NOTIFICATION_REQUIRED, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+// Unsubscribe serves the link in emails as well as the one-click POST mail
+// clients send for List-Unsubscribe-Post, so it needs no login.
+func (h *Handler) Unsubscribe(c *gin.Context) {
+	err := h.notificationPreferenceService.Unsubscribe(c.Query("token"))
+	if err != nil {
+		if err == spErr.ErrInvalidUnsubscribeToken {
+			response.Error(c, http.StatusBadRequest, code.INVALID_UNSUBSCRIBE_TOKEN, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "unsubscribed", nil)
+}
@@ -0,0 +1,178 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/handler"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	var (
+		userId   = 1
+		disabled = false
+		req      = &dto.UpdateNotificationPreferencesRequest{Preferences: []*dto.NotificationPreferenceRequest{
+			{Category: constant.NotificationCategoryPromotions, Email: &disabled},
+		}}
+		result = &dto.NotificationPreferencesResponse{Preferences: []*dto.NotificationPreference{
+			{Category: constant.NotificationCategoryPromotions, InApp: true, Push: true},
+		}}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		body        interface{}
+		beforeTests func(nps *mocks.NotificationPreferenceService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when category is invalid",
+			body: &dto.UpdateNotificationPreferencesRequest{Preferences: []*dto.NotificationPreferenceRequest{
+				{Category: "spam", Email: &disabled},
+			}},
+			beforeTests: func(nps *mocks.NotificationPreferenceService) {},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Category must be either orders, promotions, chat, or security",
+				},
+			},
+		},
+		{
+			description: "should return error with code 400 when turning off security notifications",
+			body:        req,
+			beforeTests: func(nps *mocks.NotificationPreferenceService) {
+				nps.On("UpdatePreferences", userId, req).Return(nil, errs.ErrSecurityNotificationRequired)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.SECURITY_NOTIFICATION_REQUIRED,
+					Message: errs.ErrSecurityNotificationRequired.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			body:        req,
+			beforeTests: func(nps *mocks.NotificationPreferenceService) {
+				nps.On("UpdatePreferences", userId, req).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return preferences with code 200 when success",
+			body:        req,
+			beforeTests: func(nps *mocks.NotificationPreferenceService) {
+				nps.On("UpdatePreferences", userId, req).Return(result, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    result,
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockPreferenceService := mocks.NewNotificationPreferenceService(t)
+			tc.beforeTests(mockPreferenceService)
+			handler := handler.New(&handler.Config{
+				NotificationPreferenceService: mockPreferenceService,
+			})
+			c.Set("userId", userId)
+			c.Request, _ = http.NewRequest("PUT", "/users/notification-preferences", test.MakeRequestBody(tc.body))
+
+			handler.UpdateNotificationPreferences(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		beforeTests func(nps *mocks.NotificationPreferenceService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when token is invalid",
+			beforeTests: func(nps *mocks.NotificationPreferenceService) {
+				nps.On("Unsubscribe", "token").Return(errs.ErrInvalidUnsubscribeToken)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.INVALID_UNSUBSCRIBE_TOKEN,
+					Message: errs.ErrInvalidUnsubscribeToken.Error(),
+				},
+			},
+		},
+		{
+			description: "should return code 200 when unsubscribed",
+			beforeTests: func(nps *mocks.NotificationPreferenceService) {
+				nps.On("Unsubscribe", "token").Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "unsubscribed",
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockPreferenceService := mocks.NewNotificationPreferenceService(t)
+			tc.beforeTests(mockPreferenceService)
+			handler := handler.New(&handler.Config{
+				NotificationPreferenceService: mockPreferenceService,
+			})
+			c.Request, _ = http.NewRequest("POST", "/notifications/unsubscribe?token=token", nil)
+
+			handler.Unsubscribe(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
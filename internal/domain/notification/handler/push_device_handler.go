@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/common/code"
+	spErr "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetPushDevices(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	result, err := h.pushService.GetDevices(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", result)
+}
+
+func (h *Handler) RegisterPushDevice(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	var req dto.RegisterDeviceRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.pushService.RegisterDevice(userID, c.Request.UserAgent(), &req)
+	if err != nil {
+		if err == spErr.ErrInvalidPushSubscription {
+			response.Error(c, http.StatusBadRequest, code.INVALID_PUSH_SUBSCRIPTION, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "created", result)
+}
+
+func (h *Handler) DeletePushDevice(c *gin.Context) {
+	userID := c.GetInt("userId")
+
+	deviceID, err := strconv.Atoi(c.Param("deviceId"))
+	if err != nil || deviceID < 1 {
+		response.Error(c, http.StatusNotFound, code.PUSH_DEVICE_NOT_FOUND, spErr.ErrPushDeviceNotFound.Error())
+		return
+	}
+
+	err = h.pushService.DeleteDevice(userID, deviceID)
+	if err != nil {
+		if err == spErr.ErrPushDeviceNotFound {
+			response.Error(c, http.StatusNotFound, code.PUSH_DEVICE_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, spErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", nil)
+}
+
+func (h *Handler) GetVAPIDPublicKey(c *gin.Context) {
+	response.Success(c, http.StatusOK, code.OK, "success", h.pushService.GetVAPIDPublicKey())
+}
@@ -0,0 +1,206 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/handler"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPushDevice(t *testing.T) {
+	var (
+		userId    = 1
+		userAgent = "Mozilla/5.0"
+		req       = &dto.RegisterDeviceRequest{Platform: "android", Token: "fcm-token"}
+		result    = &model.PushDevice{ID: 1, UserID: userId, Platform: "android", Token: "fcm-token", UserAgent: userAgent}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		body        interface{}
+		beforeTests func(ps *mocks.PushService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when platform is invalid",
+			body:        &dto.RegisterDeviceRequest{Platform: "symbian", Token: "token"},
+			beforeTests: func(ps *mocks.PushService) {},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Platform must be either web, android, or ios",
+				},
+			},
+		},
+		{
+			description: "should return error with code 400 when subscription is incomplete",
+			body:        req,
+			beforeTests: func(ps *mocks.PushService) {
+				ps.On("RegisterDevice", userId, userAgent, req).Return(nil, errs.ErrInvalidPushSubscription)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.INVALID_PUSH_SUBSCRIPTION,
+					Message: errs.ErrInvalidPushSubscription.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			body:        req,
+			beforeTests: func(ps *mocks.PushService) {
+				ps.On("RegisterDevice", userId, userAgent, req).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return device with code 201 when registered",
+			body:        req,
+			beforeTests: func(ps *mocks.PushService) {
+				ps.On("RegisterDevice", userId, userAgent, req).Return(result, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "created",
+					Data:    result,
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockPushService := mocks.NewPushService(t)
+			tc.beforeTests(mockPushService)
+			handler := handler.New(&handler.Config{
+				PushService: mockPushService,
+			})
+			c.Set("userId", userId)
+			c.Request, _ = http.NewRequest("POST", "/users/devices", test.MakeRequestBody(tc.body))
+			c.Request.Header.Set("User-Agent", userAgent)
+
+			handler.RegisterPushDevice(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestDeletePushDevice(t *testing.T) {
+	var userId = 1
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		deviceId    string
+		beforeTests func(ps *mocks.PushService)
+		expected
+	}{
+		{
+			description: "should return error with code 404 when device id is invalid",
+			deviceId:    "abc",
+			beforeTests: func(ps *mocks.PushService) {},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.PUSH_DEVICE_NOT_FOUND,
+					Message: errs.ErrPushDeviceNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 404 when device does not belong to user",
+			deviceId:    "2",
+			beforeTests: func(ps *mocks.PushService) {
+				ps.On("DeleteDevice", userId, 2).Return(errs.ErrPushDeviceNotFound)
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.PUSH_DEVICE_NOT_FOUND,
+					Message: errs.ErrPushDeviceNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			deviceId:    "2",
+			beforeTests: func(ps *mocks.PushService) {
+				ps.On("DeleteDevice", userId, 2).Return(errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return code 200 when deleted",
+			deviceId:    "2",
+			beforeTests: func(ps *mocks.PushService) {
+				ps.On("DeleteDevice", userId, 2).Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockPushService := mocks.NewPushService(t)
+			tc.beforeTests(mockPushService)
+			handler := handler.New(&handler.Config{
+				PushService: mockPushService,
+			})
+			c.Set("userId", userId)
+			c.AddParam("deviceId", tc.deviceId)
+			c.Request, _ = http.NewRequest("DELETE", "/users/devices/"+tc.deviceId, nil)
+
+			handler.DeletePushDevice(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
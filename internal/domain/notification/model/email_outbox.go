@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailOutbox is a rendered email waiting to be sent. Requests only insert
+// rows; a dispatcher sends them and retries with backoff, so a provider
+// outage never fails the request that triggered the email.
+type EmailOutbox struct {
+	gorm.Model
+	ID             int
+	Recipient      string
+	Template       string
+	Locale         string
+	Subject        string
+	TextBody       string
+	HTMLBody       string
+	UnsubscribeURL string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      *string
+	SentAt         *time.Time
+}
+
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Notification struct {
+	gorm.Model `json:"-"`
+	ID         int                    `json:"id"`
+	UserID     int                    `json:"userId"`
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Body       string                 `json:"body"`
+	Data       map[string]interface{} `json:"data,omitempty" gorm:"serializer:json"`
+	ReadAt     *time.Time             `json:"readAt"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
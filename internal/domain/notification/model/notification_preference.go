@@ -0,0 +1,14 @@
+package model
+
+import "gorm.io/gorm"
+
+// NotificationPreference records a user's choice for one category and
+// channel. A missing row means the channel is enabled.
+type NotificationPreference struct {
+	gorm.Model `json:"-"`
+	ID         int    `json:"id"`
+	UserID     int    `json:"userId"`
+	Category   string `json:"category"`
+	Channel    string `json:"channel"`
+	Enabled    bool   `json:"enabled"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PushDevice is a browser or app install that receives push notifications.
+// Token holds the push service endpoint for web and the FCM token for
+// mobile, and is unique so a device that switches account moves with it.
+type PushDevice struct {
+	gorm.Model `json:"-"`
+	ID         int       `json:"id"`
+	UserID     int       `json:"userId"`
+	Platform   string    `json:"platform"`
+	Token      string    `json:"token"`
+	P256dh     string    `json:"-"`
+	Auth       string    `json:"-"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EmailOutboxRepository interface {
+	Create(email *model.EmailOutbox) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]*model.EmailOutbox, error)
+	MarkSent(id int, sentAt time.Time) error
+	MarkFailed(email *model.EmailOutbox) error
+}
+
+type emailOutboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type EmailOutboxRConfig struct {
+	DB *gorm.DB
+}
+
+func NewEmailOutboxRepository(cfg *EmailOutboxRConfig) EmailOutboxRepository {
+	return &emailOutboxRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *emailOutboxRepositoryImpl) Create(email *model.EmailOutbox) error {
+	return r.db.Create(email).Error
+}
+
+// ClaimDue pushes next_attempt_at of up to limit due emails past the lease
+// and returns them. SKIP LOCKED lets several instances dispatch at once
+// without sending the same email twice.
+func (r *emailOutboxRepositoryImpl) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*model.EmailOutbox, error) {
+	var emails []*model.EmailOutbox
+
+	err := r.db.Raw(`
+		UPDATE email_outbox SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = ? AND next_attempt_at <= ? AND deleted_at IS NULL
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, constant.EmailStatusPending, now, limit,
+	).Scan(&emails).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+func (r *emailOutboxRepositoryImpl) MarkSent(id int, sentAt time.Time) error {
+	return r.db.Model(&model.EmailOutbox{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":   constant.EmailStatusSent,
+			"attempts": gorm.Expr("attempts + 1"),
+			"sent_at":  sentAt,
+		}).Error
+}
+
+func (r *emailOutboxRepositoryImpl) MarkFailed(email *model.EmailOutbox) error {
+	return r.db.Model(&model.EmailOutbox{}).
+		Where("id = ?", email.ID).
+		Updates(map[string]interface{}{
+			"status":          email.Status,
+			"attempts":        email.Attempts,
+			"next_attempt_at": email.NextAttemptAt,
+			"last_error":      email.LastError,
+		}).Error
+}
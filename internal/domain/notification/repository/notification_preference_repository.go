@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepository interface {
+	GetByUserID(userId int) ([]*model.NotificationPreference, error)
+	IsEnabled(userId int, category string, channel string) (bool, error)
+	Upsert(prefs []*model.NotificationPreference) error
+}
+
+type notificationPreferenceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type NotificationPreferenceRConfig struct {
+	DB *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(cfg *NotificationPreferenceRConfig) NotificationPreferenceRepository {
+	return &notificationPreferenceRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *notificationPreferenceRepositoryImpl) GetByUserID(userId int) ([]*model.NotificationPreference, error) {
+	var prefs []*model.NotificationPreference
+
+	err := r.db.Where("user_id = ?", userId).Find(&prefs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+func (r *notificationPreferenceRepositoryImpl) IsEnabled(userId int, category string, channel string) (bool, error) {
+	var pref model.NotificationPreference
+
+	err := r.db.Where("user_id = ? AND category = ? AND channel = ?", userId, category, channel).First(&pref).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return pref.Enabled, nil
+}
+
+func (r *notificationPreferenceRepositoryImpl) Upsert(prefs []*model.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&prefs).Error
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(notification *model.Notification) error
+	GetByUserID(userId int, req *dto.GetNotificationsRequest) ([]*model.Notification, int64, int, error)
+	CountUnread(userId int) (int64, error)
+	MarkRead(userId int, id int) error
+	MarkAllRead(userId int) error
+	GetExpiringVouchers(from, to time.Time) ([]*dto.ExpiringVoucher, error)
+}
+
+type notificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type NotificationRConfig struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepository(cfg *NotificationRConfig) NotificationRepository {
+	return &notificationRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *notificationRepositoryImpl) Create(notification *model.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *notificationRepositoryImpl) GetByUserID(userId int, req *dto.GetNotificationsRequest) ([]*model.Notification, int64, int, error) {
+	var (
+		notifications []*model.Notification
+		totalRows     int64
+	)
+
+	query := r.db.Model(&model.Notification{}).Where("user_id = ?", userId)
+	if req.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	err := query.Session(&gorm.Session{}).Count(&totalRows).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	err = query.Order("created_at desc").Limit(req.Limit).Offset(req.Offset()).Find(&notifications).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalRows) / float64(req.Limit)))
+
+	return notifications, totalRows, totalPages, nil
+}
+
+func (r *notificationRepositoryImpl) CountUnread(userId int) (int64, error) {
+	var count int64
+
+	err := r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkRead keeps the first read time, so reading an already read
+// notification again succeeds without touching it.
+func (r *notificationRepositoryImpl) MarkRead(userId int, id int) error {
+	var notification model.Notification
+
+	err := r.db.Where("id = ? AND user_id = ?", id, userId).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrNotificationNotFound
+		}
+		return err
+	}
+
+	if notification.ReadAt != nil {
+		return nil
+	}
+
+	return r.db.Model(&notification).Update("read_at", time.Now()).Error
+}
+
+func (r *notificationRepositoryImpl) MarkAllRead(userId int) error {
+	return r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Update("read_at", time.Now()).Error
+}
+
+// GetExpiringVouchers returns unused claimed vouchers expiring in (from, to].
+func (r *notificationRepositoryImpl) GetExpiringVouchers(from, to time.Time) ([]*dto.ExpiringVoucher, error) {
+	var vouchers []*dto.ExpiringVoucher
+
+	err := r.db.Table("user_vouchers uv").
+		Select("uv.user_id, uv.id AS user_voucher_id, COALESCE(sv.code, mv.code) AS code, COALESCE(sv.name, mv.name) AS name, uv.expired_at").
+		Joins("LEFT JOIN shop_vouchers sv ON sv.id = uv.shop_voucher_id").
+		Joins("LEFT JOIN marketplace_vouchers mv ON mv.id = uv.marketplace_voucher_id").
+		Where("uv.is_used = ? AND uv.deleted_at IS NULL", false).
+		Where("uv.expired_at > ? AND uv.expired_at <= ?", from, to).
+		Scan(&vouchers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PushDeviceRepository interface {
+	Upsert(device *model.PushDevice) error
+	GetByUserID(userId int) ([]*model.PushDevice, error)
+	Delete(userId int, id int) error
+	DeleteByID(id int) error
+}
+
+type pushDeviceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type PushDeviceRConfig struct {
+	DB *gorm.DB
+}
+
+func NewPushDeviceRepository(cfg *PushDeviceRConfig) PushDeviceRepository {
+	return &pushDeviceRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+// Upsert registers device, taking the token over from whichever user had it
+// before.
+func (r *pushDeviceRepositoryImpl) Upsert(device *model.PushDevice) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "p256dh", "auth", "user_agent", "updated_at"}),
+	}).Create(device).Error
+}
+
+func (r *pushDeviceRepositoryImpl) GetByUserID(userId int) ([]*model.PushDevice, error) {
+	var devices []*model.PushDevice
+
+	err := r.db.Where("user_id = ?", userId).Order("created_at DESC").Find(&devices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func (r *pushDeviceRepositoryImpl) Delete(userId int, id int) error {
+	res := r.db.Unscoped().Where("user_id = ? AND id = ?", userId, id).Delete(&model.PushDevice{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return errs.ErrPushDeviceNotFound
+	}
+
+	return nil
+}
+
+func (r *pushDeviceRepositoryImpl) DeleteByID(id int) error {
+	return r.db.Unscoped().Delete(&model.PushDevice{}, id).Error
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/repository"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"log"
+	"time"
+)
+
+// EmailPublisher is what other domains depend on to send email. Enqueue only
+// renders and stores the email, so it never waits on the mail provider.
+type EmailPublisher interface {
+	Enqueue(req *dto.EmailRequest) error
+}
+
+type EmailService interface {
+	EmailPublisher
+	DispatchOutbox() error
+}
+
+type emailServiceImpl struct {
+	emailOutboxRepo repository.EmailOutboxRepository
+	sink            mail.Sink
+	preferences     PreferenceChecker
+}
+
+type EmailSConfig struct {
+	EmailOutboxRepo repository.EmailOutboxRepository
+	Sink            mail.Sink
+	Preferences     PreferenceChecker
+}
+
+func NewEmailService(cfg *EmailSConfig) EmailService {
+	return &emailServiceImpl{
+		emailOutboxRepo: cfg.EmailOutboxRepo,
+		sink:            cfg.Sink,
+		preferences:     cfg.Preferences,
+	}
+}
+
+// Enqueue silently drops emails the user opted out of.
+func (s *emailServiceImpl) Enqueue(req *dto.EmailRequest) error {
+	if req.UserID != 0 && s.preferences != nil && !s.preferences.IsEnabled(req.UserID, req.Category, constant.NotificationChannelEmail) {
+		return nil
+	}
+
+	locale := req.Locale
+	if locale == "" {
+		locale = config.Mail.Locale
+	}
+
+	email, err := mail.RenderTemplate(req.Template, locale, req.Data)
+	if err != nil {
+		return err
+	}
+
+	var unsubscribeURL string
+	if req.UserID != 0 && req.Category != "" && req.Category != constant.NotificationCategorySecurity {
+		token := &dto.UnsubscribeToken{
+			UserID:   req.UserID,
+			Category: req.Category,
+			Channel:  constant.NotificationChannelEmail,
+		}
+		unsubscribeURL = config.Mail.UnsubscribeURL + token.Sign()
+
+		if err := mail.AppendUnsubscribeFooter(email, locale, unsubscribeURL); err != nil {
+			return err
+		}
+	}
+
+	return s.emailOutboxRepo.Create(&model.EmailOutbox{
+		Recipient:      req.To,
+		Template:       req.Template,
+		Locale:         locale,
+		Subject:        email.Subject,
+		TextBody:       email.Text,
+		HTMLBody:       email.HTML,
+		UnsubscribeURL: unsubscribeURL,
+		Status:         constant.EmailStatusPending,
+		NextAttemptAt:  time.Now(),
+	})
+}
+
+// DispatchOutbox sends one batch of due emails. A failed send is retried
+// with exponential backoff until EmailMaxAttempts, after which the email is
+// marked failed and left for inspection.
+func (s *emailServiceImpl) DispatchOutbox() error {
+	now := time.Now()
+
+	emails, err := s.emailOutboxRepo.ClaimDue(now, constant.EmailClaimLease, constant.EmailOutboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, email := range emails {
+		err := s.sink.Send(&mail.Message{
+			From:    config.Mail.Sender,
+			To:      email.Recipient,
+			Subject: email.Subject,
+			Text:    email.TextBody,
+			HTML:    email.HTMLBody,
+
+			UnsubscribeURL: email.UnsubscribeURL,
+		})
+		if err == nil {
+			if err := s.emailOutboxRepo.MarkSent(email.ID, time.Now()); err != nil {
+				log.Println("failed to mark email sent", email.ID, ":", err)
+			}
+			continue
+		}
+
+		lastError := err.Error()
+		email.Attempts++
+		email.LastError = &lastError
+		email.NextAttemptAt = now.Add(constant.EmailRetryBaseDelay << (email.Attempts - 1))
+		if email.Attempts >= constant.EmailMaxAttempts {
+			email.Status = constant.EmailStatusFailed
+		}
+
+		if err := s.emailOutboxRepo.MarkFailed(email); err != nil {
+			log.Println("failed to record email failure", email.ID, ":", err)
+		}
+	}
+
+	return nil
+}
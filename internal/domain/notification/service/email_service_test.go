@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEnqueue(t *testing.T) {
+	data := &mail.OrderEmailData{Username: "budi", ShopName: "Toko", Code: "INV/1", TrackingNumber: "123"}
+
+	tests := []struct {
+		description string
+		req         *dto.EmailRequest
+		beforeTest  func(*mocks.EmailOutboxRepository, *mocks.PreferenceChecker)
+		err         error
+	}{
+		{
+			description: "should return error when template does not exist",
+			req:         &dto.EmailRequest{To: "budi@mail.com", Template: "unknown", Data: data},
+			beforeTest:  func(eor *mocks.EmailOutboxRepository, pc *mocks.PreferenceChecker) {},
+			err:         mail.ErrUnknownEmailTemplate,
+		},
+		{
+			description: "should return error when failed to save email",
+			req:         &dto.EmailRequest{To: "budi@mail.com", Template: mail.TemplateOrderShipped, Data: data},
+			beforeTest: func(eor *mocks.EmailOutboxRepository, pc *mocks.PreferenceChecker) {
+				eor.On("Create", mock.Anything).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should store rendered email in requested locale as pending",
+			req:         &dto.EmailRequest{To: "budi@mail.com", Template: mail.TemplateOrderShipped, Locale: "en", Data: data},
+			beforeTest: func(eor *mocks.EmailOutboxRepository, pc *mocks.PreferenceChecker) {
+				eor.On("Create", mock.MatchedBy(func(email *model.EmailOutbox) bool {
+					return email.Recipient == "budi@mail.com" &&
+						email.Locale == "en" &&
+						email.Subject == "Order INV/1 is on its way" &&
+						email.Status == constant.EmailStatusPending &&
+						email.UnsubscribeURL == "" &&
+						!email.NextAttemptAt.After(time.Now())
+				})).Return(nil)
+			},
+		},
+		{
+			description: "should drop email when user opted out of category",
+			req:         &dto.EmailRequest{To: "budi@mail.com", UserID: 1, Category: constant.NotificationCategoryOrders, Template: mail.TemplateOrderShipped, Data: data},
+			beforeTest: func(eor *mocks.EmailOutboxRepository, pc *mocks.PreferenceChecker) {
+				pc.On("IsEnabled", 1, constant.NotificationCategoryOrders, constant.NotificationChannelEmail).Return(false)
+			},
+		},
+		{
+			description: "should add unsubscribe link when email belongs to a user category",
+			req:         &dto.EmailRequest{To: "budi@mail.com", UserID: 1, Category: constant.NotificationCategoryOrders, Template: mail.TemplateOrderShipped, Locale: "en", Data: data},
+			beforeTest: func(eor *mocks.EmailOutboxRepository, pc *mocks.PreferenceChecker) {
+				token := (&dto.UnsubscribeToken{UserID: 1, Category: constant.NotificationCategoryOrders, Channel: constant.NotificationChannelEmail}).Sign()
+				pc.On("IsEnabled", 1, constant.NotificationCategoryOrders, constant.NotificationChannelEmail).Return(true)
+				eor.On("Create", mock.MatchedBy(func(email *model.EmailOutbox) bool {
+					return strings.HasSuffix(email.UnsubscribeURL, "?token="+token) &&
+						strings.HasSuffix(email.TextBody, "Unsubscribe: "+email.UnsubscribeURL)
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockEmailOutboxRepo := mocks.NewEmailOutboxRepository(t)
+			mockPreferences := mocks.NewPreferenceChecker(t)
+			tc.beforeTest(mockEmailOutboxRepo, mockPreferences)
+			emailService := service.NewEmailService(&service.EmailSConfig{
+				EmailOutboxRepo: mockEmailOutboxRepo,
+				Preferences:     mockPreferences,
+			})
+
+			err := emailService.Enqueue(tc.req)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestDispatchOutbox(t *testing.T) {
+	t.Run("should return error when failed to claim due emails", func(t *testing.T) {
+		mockEmailOutboxRepo := mocks.NewEmailOutboxRepository(t)
+		mockEmailOutboxRepo.On("ClaimDue", mock.Anything, constant.EmailClaimLease, constant.EmailOutboxBatchSize).Return(nil, errors.New("error"))
+		emailService := service.NewEmailService(&service.EmailSConfig{
+			EmailOutboxRepo: mockEmailOutboxRepo,
+		})
+
+		err := emailService.DispatchOutbox()
+
+		assert.Equal(t, errors.New("error"), err)
+	})
+
+	t.Run("should mark sent emails and reschedule failed ones with backoff", func(t *testing.T) {
+		mockEmailOutboxRepo := mocks.NewEmailOutboxRepository(t)
+		mockSink := mocks.NewSink(t)
+		mockEmailOutboxRepo.On("ClaimDue", mock.Anything, constant.EmailClaimLease, constant.EmailOutboxBatchSize).Return([]*model.EmailOutbox{
+			{ID: 1, Recipient: "sent@mail.com", Status: constant.EmailStatusPending},
+			{ID: 2, Recipient: "retry@mail.com", Status: constant.EmailStatusPending, Attempts: 2},
+			{ID: 3, Recipient: "dead@mail.com", Status: constant.EmailStatusPending, Attempts: constant.EmailMaxAttempts - 1},
+		}, nil)
+		mockSink.On("Send", mock.MatchedBy(func(msg *mail.Message) bool { return msg.To == "sent@mail.com" })).Return(nil)
+		mockSink.On("Send", mock.Anything).Return(errors.New("mailgun down"))
+		mockEmailOutboxRepo.On("MarkSent", 1, mock.Anything).Return(nil)
+		mockEmailOutboxRepo.On("MarkFailed", mock.MatchedBy(func(email *model.EmailOutbox) bool {
+			return email.ID == 2 &&
+				email.Attempts == 3 &&
+				email.Status == constant.EmailStatusPending &&
+				*email.LastError == "mailgun down" &&
+				time.Until(email.NextAttemptAt) > 3*time.Minute
+		})).Return(nil)
+		mockEmailOutboxRepo.On("MarkFailed", mock.MatchedBy(func(email *model.EmailOutbox) bool {
+			return email.ID == 3 && email.Status == constant.EmailStatusFailed
+		})).Return(errors.New("error"))
+		emailService := service.NewEmailService(&service.EmailSConfig{
+			EmailOutboxRepo: mockEmailOutboxRepo,
+			Sink:            mockSink,
+		})
+
+		err := emailService.DispatchOutbox()
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/repository"
+	"log"
+)
+
+// PreferenceChecker tells publishers whether a user still wants messages of
+// category on channel.
+type PreferenceChecker interface {
+	IsEnabled(userId int, category string, channel string) bool
+}
+
+type NotificationPreferenceService interface {
+	PreferenceChecker
+	GetPreferences(userId int) (*dto.NotificationPreferencesResponse, error)
+	UpdatePreferences(userId int, req *dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error)
+	Unsubscribe(token string) error
+}
+
+type notificationPreferenceServiceImpl struct {
+	preferenceRepo repository.NotificationPreferenceRepository
+}
+
+type NotificationPreferenceSConfig struct {
+	PreferenceRepo repository.NotificationPreferenceRepository
+}
+
+func NewNotificationPreferenceService(cfg *NotificationPreferenceSConfig) NotificationPreferenceService {
+	return &notificationPreferenceServiceImpl{
+		preferenceRepo: cfg.PreferenceRepo,
+	}
+}
+
+// IsEnabled always allows security messages, and fails open when the
+// preference can't be read so order updates aren't silently lost.
+func (s *notificationPreferenceServiceImpl) IsEnabled(userId int, category string, channel string) bool {
+	if category == constant.NotificationCategorySecurity {
+		return true
+	}
+
+	enabled, err := s.preferenceRepo.IsEnabled(userId, category, channel)
+	if err != nil {
+		log.Println("failed to get notification preference of user", userId, ":", err)
+		return true
+	}
+
+	return enabled
+}
+
+func (s *notificationPreferenceServiceImpl) GetPreferences(userId int) (*dto.NotificationPreferencesResponse, error) {
+	prefs, err := s.preferenceRepo.GetByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewNotificationPreferencesResponse(prefs), nil
+}
+
+func (s *notificationPreferenceServiceImpl) UpdatePreferences(userId int, req *dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error) {
+	prefs, err := req.ToModels(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.preferenceRepo.Upsert(prefs); err != nil {
+		return nil, err
+	}
+
+	return s.GetPreferences(userId)
+}
+
+func (s *notificationPreferenceServiceImpl) Unsubscribe(token string) error {
+	parsed, err := dto.ParseUnsubscribeToken(token)
+	if err != nil {
+		return err
+	}
+
+	return s.preferenceRepo.Upsert([]*model.NotificationPreference{{
+		UserID:   parsed.UserID,
+		Category: parsed.Category,
+		Channel:  parsed.Channel,
+		Enabled:  false,
+	}})
+}
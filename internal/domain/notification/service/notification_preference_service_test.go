@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNotificationEnabled(t *testing.T) {
+	tests := []struct {
+		description string
+		category    string
+		beforeTest  func(*mocks.NotificationPreferenceRepository)
+		expected    bool
+	}{
+		{
+			description: "should always allow security messages",
+			category:    constant.NotificationCategorySecurity,
+			beforeTest:  func(npr *mocks.NotificationPreferenceRepository) {},
+			expected:    true,
+		},
+		{
+			description: "should allow when preference can't be read",
+			category:    constant.NotificationCategoryOrders,
+			beforeTest: func(npr *mocks.NotificationPreferenceRepository) {
+				npr.On("IsEnabled", 1, constant.NotificationCategoryOrders, constant.NotificationChannelEmail).Return(false, errors.New("error"))
+			},
+			expected: true,
+		},
+		{
+			description: "should follow stored preference",
+			category:    constant.NotificationCategoryOrders,
+			beforeTest: func(npr *mocks.NotificationPreferenceRepository) {
+				npr.On("IsEnabled", 1, constant.NotificationCategoryOrders, constant.NotificationChannelEmail).Return(false, nil)
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockPreferenceRepo := mocks.NewNotificationPreferenceRepository(t)
+			tc.beforeTest(mockPreferenceRepo)
+			preferenceService := service.NewNotificationPreferenceService(&service.NotificationPreferenceSConfig{
+				PreferenceRepo: mockPreferenceRepo,
+			})
+
+			enabled := preferenceService.IsEnabled(1, tc.category, constant.NotificationChannelEmail)
+
+			assert.Equal(t, tc.expected, enabled)
+		})
+	}
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	var (
+		userId   = 1
+		disabled = false
+		enabled  = true
+	)
+
+	tests := []struct {
+		description string
+		req         *dto.UpdateNotificationPreferencesRequest
+		beforeTest  func(*mocks.NotificationPreferenceRepository)
+		expected    *dto.NotificationPreferencesResponse
+		err         error
+	}{
+		{
+			description: "should return error when turning off security notifications",
+			req: &dto.UpdateNotificationPreferencesRequest{Preferences: []*dto.NotificationPreferenceRequest{
+				{Category: constant.NotificationCategorySecurity, Email: &disabled},
+			}},
+			beforeTest: func(npr *mocks.NotificationPreferenceRepository) {},
+			err:        errs.ErrSecurityNotificationRequired,
+		},
+		{
+			description: "should return error when failed to save preferences",
+			req: &dto.UpdateNotificationPreferencesRequest{Preferences: []*dto.NotificationPreferenceRequest{
+				{Category: constant.NotificationCategoryPromotions, Email: &disabled},
+			}},
+			beforeTest: func(npr *mocks.NotificationPreferenceRepository) {
+				npr.On("Upsert", []*model.NotificationPreference{
+					{UserID: userId, Category: constant.NotificationCategoryPromotions, Channel: constant.NotificationChannelEmail, Enabled: false},
+				}).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should save only given channels and return every category",
+			req: &dto.UpdateNotificationPreferencesRequest{Preferences: []*dto.NotificationPreferenceRequest{
+				{Category: constant.NotificationCategoryPromotions, Email: &disabled, Push: &disabled},
+				{Category: constant.NotificationCategorySecurity, Email: &enabled},
+			}},
+			beforeTest: func(npr *mocks.NotificationPreferenceRepository) {
+				saved := []*model.NotificationPreference{
+					{UserID: userId, Category: constant.NotificationCategoryPromotions, Channel: constant.NotificationChannelEmail, Enabled: false},
+					{UserID: userId, Category: constant.NotificationCategoryPromotions, Channel: constant.NotificationChannelPush, Enabled: false},
+					{UserID: userId, Category: constant.NotificationCategorySecurity, Channel: constant.NotificationChannelEmail, Enabled: true},
+				}
+				npr.On("Upsert", saved).Return(nil)
+				npr.On("GetByUserID", userId).Return(saved, nil)
+			},
+			expected: &dto.NotificationPreferencesResponse{Preferences: []*dto.NotificationPreference{
+				{Category: constant.NotificationCategoryOrders, Email: true, InApp: true, Push: true},
+				{Category: constant.NotificationCategoryPromotions, Email: false, InApp: true, Push: false},
+				{Category: constant.NotificationCategoryChat, Email: true, InApp: true, Push: true},
+				{Category: constant.NotificationCategorySecurity, Email: true, InApp: true, Push: true},
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockPreferenceRepo := mocks.NewNotificationPreferenceRepository(t)
+			tc.beforeTest(mockPreferenceRepo)
+			preferenceService := service.NewNotificationPreferenceService(&service.NotificationPreferenceSConfig{
+				PreferenceRepo: mockPreferenceRepo,
+			})
+
+			result, err := preferenceService.UpdatePreferences(userId, tc.req)
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	test.UseTokenKey(t)
+	valid := (&dto.UnsubscribeToken{UserID: 1, Category: constant.NotificationCategoryPromotions, Channel: constant.NotificationChannelEmail}).Sign()
+	security := (&dto.UnsubscribeToken{UserID: 1, Category: constant.NotificationCategorySecurity, Channel: constant.NotificationChannelEmail}).Sign()
+
+	tests := []struct {
+		description string
+		token       string
+		beforeTest  func(*mocks.NotificationPreferenceRepository)
+		err         error
+	}{
+		{
+			description: "should return error when token is malformed",
+			token:       "abc",
+			beforeTest:  func(npr *mocks.NotificationPreferenceRepository) {},
+			err:         errs.ErrInvalidUnsubscribeToken,
+		},
+		{
+			description: "should return error when signature does not match",
+			token:       valid + "0",
+			beforeTest:  func(npr *mocks.NotificationPreferenceRepository) {},
+			err:         errs.ErrInvalidUnsubscribeToken,
+		},
+		{
+			description: "should return error when token is signed with the server hash key",
+			token:       base64.RawURLEncoding.EncodeToString([]byte("1:promotions:email")) + "." + hash.HashSHA256("1:promotions:email"),
+			beforeTest:  func(npr *mocks.NotificationPreferenceRepository) {},
+			err:         errs.ErrInvalidUnsubscribeToken,
+		},
+		{
+			description: "should return error when token targets security notifications",
+			token:       security,
+			beforeTest:  func(npr *mocks.NotificationPreferenceRepository) {},
+			err:         errs.ErrInvalidUnsubscribeToken,
+		},
+		{
+			description: "should turn off the channel named in the token",
+			token:       valid,
+			beforeTest: func(npr *mocks.NotificationPreferenceRepository) {
+				npr.On("Upsert", []*model.NotificationPreference{
+					{UserID: 1, Category: constant.NotificationCategoryPromotions, Channel: constant.NotificationChannelEmail, Enabled: false},
+				}).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockPreferenceRepo := mocks.NewNotificationPreferenceRepository(t)
+			tc.beforeTest(mockPreferenceRepo)
+			preferenceService := service.NewNotificationPreferenceService(&service.NotificationPreferenceSConfig{
+				PreferenceRepo: mockPreferenceRepo,
+			})
+
+			err := preferenceService.Unsubscribe(tc.token)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
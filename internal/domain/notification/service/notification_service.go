@@ -0,0 +1,219 @@
+package service
+
+import (
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/repository"
+	"kedai/backend/be-kedai/internal/utils/push"
+	"log"
+	"strconv"
+	"time"
+)
+
+// NotificationPublisher is the event interface other domains depend on, so
+// they can notify users without knowing how notifications are stored or
+// delivered.
+type NotificationPublisher interface {
+	Publish(event *dto.NotificationEvent) error
+}
+
+// NotificationBroadcaster pushes new notifications to the user's socket
+// room. *socketio.Server satisfies it.
+type NotificationBroadcaster interface {
+	BroadcastToRoom(namespace string, room, event string, args ...interface{}) bool
+}
+
+// PresenceChecker reports whether the user has a live socket, in which case
+// the socket event already reached them and no push is sent.
+type PresenceChecker interface {
+	IsOnline(userId int) (bool, error)
+}
+
+type NotificationService interface {
+	NotificationPublisher
+	GetNotifications(userId int, req *dto.GetNotificationsRequest) (*dto.NotificationListResponse, error)
+	GetUnreadCount(userId int) (*dto.UnreadCountResponse, error)
+	MarkRead(userId int, notificationId int) error
+	MarkAllRead(userId int) error
+	VoucherExpiryCRONJob() error
+}
+
+type notificationServiceImpl struct {
+	notificationRepo repository.NotificationRepository
+	broadcaster      NotificationBroadcaster
+	preferences      PreferenceChecker
+	pusher           Pusher
+	presence         PresenceChecker
+}
+
+type NotificationSConfig struct {
+	NotificationRepo repository.NotificationRepository
+	Broadcaster      NotificationBroadcaster
+	Preferences      PreferenceChecker
+	Pusher           Pusher
+	Presence         PresenceChecker
+}
+
+func NewNotificationService(cfg *NotificationSConfig) NotificationService {
+	return &notificationServiceImpl{
+		notificationRepo: cfg.NotificationRepo,
+		broadcaster:      cfg.Broadcaster,
+		preferences:      cfg.Preferences,
+		pusher:           cfg.Pusher,
+		presence:         cfg.Presence,
+	}
+}
+
+// Publish stores the notification and sends it to the user's socket room
+// unless they turned in-app notifications off for its category. Whether it
+// is also pushed to their devices depends on the push channel alone.
+func (s *notificationServiceImpl) Publish(event *dto.NotificationEvent) error {
+	title, body, err := renderNotification(event.Type, event.Data)
+	if err != nil {
+		return err
+	}
+
+	category := constant.NotificationTypeCategories[event.Type]
+	notification := &model.Notification{
+		UserID: event.UserID,
+		Type:   event.Type,
+		Title:  title,
+		Body:   body,
+		Data:   event.Data,
+	}
+
+	if s.isEnabled(event.UserID, category, constant.NotificationChannelInApp) {
+		if err := s.notificationRepo.Create(notification); err != nil {
+			return err
+		}
+
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastToRoom("/", dto.UserRoomID(event.UserID), "notification", notification)
+		}
+	}
+
+	s.push(category, notification)
+
+	return nil
+}
+
+func (s *notificationServiceImpl) isEnabled(userId int, category string, channel string) bool {
+	return s.preferences == nil || s.preferences.IsEnabled(userId, category, channel)
+}
+
+// push forwards notification to the user's devices when they have no live
+// socket and haven't turned push off for category. The providers are called
+// in the background so the caller's request never waits on them, and
+// failures are only logged.
+func (s *notificationServiceImpl) push(category string, notification *model.Notification) {
+	if s.pusher == nil {
+		return
+	}
+
+	if s.presence != nil {
+		online, err := s.presence.IsOnline(notification.UserID)
+		if err != nil {
+			log.Println("failed to get presence of user", notification.UserID, ":", err)
+		}
+		if online {
+			return
+		}
+	}
+
+	if !s.isEnabled(notification.UserID, category, constant.NotificationChannelPush) {
+		return
+	}
+
+	data := map[string]string{}
+	for key, value := range notification.Data {
+		data[key] = fmt.Sprint(value)
+	}
+	if notification.ID != 0 {
+		data["notificationId"] = strconv.Itoa(notification.ID)
+	}
+	data["type"] = notification.Type
+
+	message := &push.Message{
+		Title: notification.Title,
+		Body:  notification.Body,
+		Data:  data,
+	}
+
+	go func() {
+		err := s.pusher.Push(notification.UserID, message)
+		if err != nil {
+			log.Println("failed to push", notification.Type, "notification to user", notification.UserID, ":", err)
+		}
+	}()
+}
+
+func (s *notificationServiceImpl) GetNotifications(userId int, req *dto.GetNotificationsRequest) (*dto.NotificationListResponse, error) {
+	notifications, totalRows, totalPages, err := s.notificationRepo.GetByUserID(userId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCount, err := s.notificationRepo.CountUnread(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.NotificationListResponse{
+		Data:        notifications,
+		Limit:       req.Limit,
+		Page:        req.Page,
+		TotalRows:   totalRows,
+		TotalPages:  totalPages,
+		UnreadCount: unreadCount,
+	}, nil
+}
+
+func (s *notificationServiceImpl) GetUnreadCount(userId int) (*dto.UnreadCountResponse, error) {
+	unreadCount, err := s.notificationRepo.CountUnread(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UnreadCountResponse{UnreadCount: unreadCount}, nil
+}
+
+func (s *notificationServiceImpl) MarkRead(userId int, notificationId int) error {
+	return s.notificationRepo.MarkRead(userId, notificationId)
+}
+
+func (s *notificationServiceImpl) MarkAllRead(userId int) error {
+	return s.notificationRepo.MarkAllRead(userId)
+}
+
+// VoucherExpiryCRONJob reminds owners of vouchers expiring in the hour that
+// just entered the notice window. It is meant to run hourly so each voucher
+// is picked up once.
+func (s *notificationServiceImpl) VoucherExpiryCRONJob() error {
+	to := time.Now().Add(constant.VoucherExpiryNoticeHours * time.Hour)
+	from := to.Add(-time.Hour)
+
+	vouchers, err := s.notificationRepo.GetExpiringVouchers(from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, voucher := range vouchers {
+		err := s.Publish(&dto.NotificationEvent{
+			UserID: voucher.UserID,
+			Type:   constant.NotificationTypeVoucherExpiry,
+			Data: map[string]interface{}{
+				"userVoucherId": voucher.UserVoucherID,
+				"code":          voucher.Code,
+				"name":          voucher.Name,
+				"expiredAt":     voucher.ExpiredAt.Format("02 Jan 2006 15:04"),
+			},
+		})
+		if err != nil {
+			log.Println("failed to notify voucher expiry", voucher.UserVoucherID, ":", err)
+		}
+	}
+
+	return nil
+}
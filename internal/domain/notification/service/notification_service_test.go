@@ -0,0 +1,332 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/utils/push"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPublish(t *testing.T) {
+	var (
+		userId = 1
+		data   = map[string]interface{}{
+			"invoiceId": 5,
+			"code":      "INV/2026101/70031/1",
+			"status":    constant.TransactionStatusOnDelivery,
+		}
+	)
+
+	tests := []struct {
+		description string
+		event       *dto.NotificationEvent
+		optedOut    bool
+		beforeTest  func(*mocks.NotificationRepository, *mocks.NotificationBroadcaster)
+		err         error
+	}{
+		{
+			description: "should return error when type has no template",
+			event:       &dto.NotificationEvent{UserID: userId, Type: "unknown", Data: data},
+			beforeTest:  func(nr *mocks.NotificationRepository, nb *mocks.NotificationBroadcaster) {},
+			err:         errs.ErrUnknownNotificationType,
+		},
+		{
+			description: "should return error when data misses a template field",
+			event:       &dto.NotificationEvent{UserID: userId, Type: constant.NotificationTypeOrderStatus, Data: map[string]interface{}{"code": "INV"}},
+			beforeTest:  func(nr *mocks.NotificationRepository, nb *mocks.NotificationBroadcaster) {},
+			err:         errors.New(`template: order_status.body:1:39: executing "order_status.body" at <.status>: map has no entry for key "status"`),
+		},
+		{
+			description: "should not store notification when user turned off in-app notifications",
+			event:       &dto.NotificationEvent{UserID: userId, Type: constant.NotificationTypeOrderStatus, Data: data},
+			optedOut:    true,
+			beforeTest:  func(nr *mocks.NotificationRepository, nb *mocks.NotificationBroadcaster) {},
+		},
+		{
+			description: "should return error when failed to save notification",
+			event:       &dto.NotificationEvent{UserID: userId, Type: constant.NotificationTypeOrderStatus, Data: data},
+			beforeTest: func(nr *mocks.NotificationRepository, nb *mocks.NotificationBroadcaster) {
+				nr.On("Create", mock.Anything).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should save rendered notification and push it to the user's room",
+			event:       &dto.NotificationEvent{UserID: userId, Type: constant.NotificationTypeOrderStatus, Data: data},
+			beforeTest: func(nr *mocks.NotificationRepository, nb *mocks.NotificationBroadcaster) {
+				expected := &model.Notification{
+					UserID: userId,
+					Type:   constant.NotificationTypeOrderStatus,
+					Title:  "Order INV/2026101/70031/1 updated",
+					Body:   "Your order INV/2026101/70031/1 is now on delivery.",
+					Data:   data,
+				}
+				nr.On("Create", expected).Return(nil)
+				nb.On("BroadcastToRoom", "/", "user:1", "notification", expected).Return(true)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockNotificationRepo := mocks.NewNotificationRepository(t)
+			mockBroadcaster := mocks.NewNotificationBroadcaster(t)
+			mockPreferences := new(mocks.PreferenceChecker)
+			mockPreferences.On("IsEnabled", userId, constant.NotificationCategoryOrders, constant.NotificationChannelInApp).Return(!tc.optedOut)
+			tc.beforeTest(mockNotificationRepo, mockBroadcaster)
+			notificationService := service.NewNotificationService(&service.NotificationSConfig{
+				NotificationRepo: mockNotificationRepo,
+				Broadcaster:      mockBroadcaster,
+				Preferences:      mockPreferences,
+			})
+
+			err := notificationService.Publish(tc.event)
+
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	var (
+		userId        = 1
+		req           = &dto.GetNotificationsRequest{Page: 1, Limit: 10}
+		notifications = []*model.Notification{{ID: 1, UserID: userId}}
+	)
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.NotificationRepository)
+		expected    *dto.NotificationListResponse
+		err         error
+	}{
+		{
+			description: "should return error when failed to get notifications",
+			beforeTest: func(nr *mocks.NotificationRepository) {
+				nr.On("GetByUserID", userId, req).Return(nil, int64(0), 0, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return error when failed to count unread notifications",
+			beforeTest: func(nr *mocks.NotificationRepository) {
+				nr.On("GetByUserID", userId, req).Return(notifications, int64(1), 1, nil)
+				nr.On("CountUnread", userId).Return(int64(0), errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return notifications with unread count",
+			beforeTest: func(nr *mocks.NotificationRepository) {
+				nr.On("GetByUserID", userId, req).Return(notifications, int64(1), 1, nil)
+				nr.On("CountUnread", userId).Return(int64(1), nil)
+			},
+			expected: &dto.NotificationListResponse{
+				Data:        notifications,
+				Limit:       10,
+				Page:        1,
+				TotalRows:   1,
+				TotalPages:  1,
+				UnreadCount: 1,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockNotificationRepo := mocks.NewNotificationRepository(t)
+			tc.beforeTest(mockNotificationRepo)
+			notificationService := service.NewNotificationService(&service.NotificationSConfig{
+				NotificationRepo: mockNotificationRepo,
+			})
+
+			result, err := notificationService.GetNotifications(userId, req)
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	t.Run("should return error when notification not found", func(t *testing.T) {
+		mockNotificationRepo := mocks.NewNotificationRepository(t)
+		mockNotificationRepo.On("MarkRead", 1, 5).Return(errs.ErrNotificationNotFound)
+		notificationService := service.NewNotificationService(&service.NotificationSConfig{
+			NotificationRepo: mockNotificationRepo,
+		})
+
+		err := notificationService.MarkRead(1, 5)
+
+		assert.Equal(t, errs.ErrNotificationNotFound, err)
+	})
+
+	t.Run("should mark all notifications as read", func(t *testing.T) {
+		mockNotificationRepo := mocks.NewNotificationRepository(t)
+		mockNotificationRepo.On("MarkAllRead", 1).Return(nil)
+		notificationService := service.NewNotificationService(&service.NotificationSConfig{
+			NotificationRepo: mockNotificationRepo,
+		})
+
+		err := notificationService.MarkAllRead(1)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestVoucherExpiryCRONJob(t *testing.T) {
+	expiredAt := time.Date(2026, 10, 20, 9, 30, 0, 0, time.UTC)
+
+	t.Run("should return error when failed to get expiring vouchers", func(t *testing.T) {
+		mockNotificationRepo := mocks.NewNotificationRepository(t)
+		mockNotificationRepo.On("GetExpiringVouchers", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+		notificationService := service.NewNotificationService(&service.NotificationSConfig{
+			NotificationRepo: mockNotificationRepo,
+		})
+
+		err := notificationService.VoucherExpiryCRONJob()
+
+		assert.Equal(t, errors.New("error"), err)
+	})
+
+	t.Run("should notify owners of vouchers entering the notice window", func(t *testing.T) {
+		mockNotificationRepo := mocks.NewNotificationRepository(t)
+		mockNotificationRepo.On("GetExpiringVouchers", mock.Anything, mock.Anything).Return([]*dto.ExpiringVoucher{
+			{UserID: 1, UserVoucherID: 7, Code: "HEMAT10", Name: "Hemat 10%", ExpiredAt: expiredAt},
+			{UserID: 2, UserVoucherID: 8, Code: "ONGKIR", Name: "Gratis Ongkir", ExpiredAt: expiredAt},
+		}, nil).Run(func(args mock.Arguments) {
+			from, to := args.Get(0).(time.Time), args.Get(1).(time.Time)
+			assert.Equal(t, time.Hour, to.Sub(from))
+			assert.WithinDuration(t, time.Now().Add(constant.VoucherExpiryNoticeHours*time.Hour), to, time.Minute)
+		})
+		mockNotificationRepo.On("Create", mock.MatchedBy(func(n *model.Notification) bool {
+			return n.UserID == 1 && n.Title == "Voucher HEMAT10 expires soon" &&
+				n.Body == "Your voucher Hemat 10% expires on 20 Oct 2026 09:30. Use it before it's gone!"
+		})).Return(nil)
+		mockNotificationRepo.On("Create", mock.MatchedBy(func(n *model.Notification) bool {
+			return n.UserID == 2
+		})).Return(errors.New("error"))
+		notificationService := service.NewNotificationService(&service.NotificationSConfig{
+			NotificationRepo: mockNotificationRepo,
+		})
+
+		err := notificationService.VoucherExpiryCRONJob()
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestPublishPush(t *testing.T) {
+	var (
+		userId = 1
+		event  = &dto.NotificationEvent{
+			UserID: userId,
+			Type:   constant.NotificationTypeChatMessage,
+			Data: map[string]interface{}{
+				"chatId":  3,
+				"room":    "chat:1:2",
+				"issuer":  "user",
+				"sender":  "budi",
+				"message": "Halo",
+			},
+		}
+		message = func(data map[string]string) *push.Message {
+			for key, value := range map[string]string{
+				"type":    constant.NotificationTypeChatMessage,
+				"chatId":  "3",
+				"room":    "chat:1:2",
+				"issuer":  "user",
+				"sender":  "budi",
+				"message": "Halo",
+			} {
+				data[key] = value
+			}
+			return &push.Message{Title: "New message from budi", Body: "Halo", Data: data}
+		}
+	)
+
+	tests := []struct {
+		description  string
+		online       bool
+		inAppEnabled bool
+		pushEnabled  bool
+		expected     *push.Message
+	}{
+		{
+			description:  "should not push when user has a live socket",
+			online:       true,
+			inAppEnabled: true,
+			pushEnabled:  true,
+		},
+		{
+			description:  "should not push when user turned push off",
+			inAppEnabled: true,
+			pushEnabled:  false,
+		},
+		{
+			description:  "should push stored notification to offline user",
+			inAppEnabled: true,
+			pushEnabled:  true,
+			expected:     message(map[string]string{"notificationId": "7"}),
+		},
+		{
+			description:  "should push without storing when user turned in-app notifications off",
+			inAppEnabled: false,
+			pushEnabled:  true,
+			expected:     message(map[string]string{}),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockNotificationRepo := mocks.NewNotificationRepository(t)
+			mockPusher := mocks.NewPusher(t)
+			mockPresence := mocks.NewPresenceChecker(t)
+			mockPreferences := new(mocks.PreferenceChecker)
+			if tc.inAppEnabled {
+				mockNotificationRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+					args.Get(0).(*model.Notification).ID = 7
+				}).Return(nil)
+			}
+			mockPresence.On("IsOnline", userId).Return(tc.online, nil)
+			mockPreferences.On("IsEnabled", userId, constant.NotificationCategoryChat, constant.NotificationChannelInApp).Return(tc.inAppEnabled)
+			mockPreferences.On("IsEnabled", userId, constant.NotificationCategoryChat, constant.NotificationChannelPush).Return(tc.pushEnabled)
+			pushed := make(chan struct{})
+			if tc.expected != nil {
+				mockPusher.On("Push", userId, tc.expected).Run(func(args mock.Arguments) {
+					close(pushed)
+				}).Return(errors.New("error"))
+			}
+			notificationService := service.NewNotificationService(&service.NotificationSConfig{
+				NotificationRepo: mockNotificationRepo,
+				Preferences:      mockPreferences,
+				Pusher:           mockPusher,
+				Presence:         mockPresence,
+			})
+
+			err := notificationService.Publish(event)
+
+			assert.NoError(t, err)
+			if tc.expected != nil {
+				select {
+				case <-pushed:
+				case <-time.After(time.Second):
+					t.Fatal("notification was not pushed")
+				}
+			}
+		})
+	}
+}
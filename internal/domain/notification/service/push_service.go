@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/repository"
+	"kedai/backend/be-kedai/internal/utils/push"
+	"log"
+)
+
+// Pusher delivers msg to every device userId registered.
+type Pusher interface {
+	Push(userId int, msg *push.Message) error
+}
+
+type PushService interface {
+	Pusher
+	RegisterDevice(userId int, userAgent string, req *dto.RegisterDeviceRequest) (*model.PushDevice, error)
+	GetDevices(userId int) ([]*model.PushDevice, error)
+	DeleteDevice(userId int, deviceId int) error
+	GetVAPIDPublicKey() *dto.VAPIDKeyResponse
+}
+
+type pushServiceImpl struct {
+	pushDeviceRepo repository.PushDeviceRepository
+	sender         push.Sender
+}
+
+type PushSConfig struct {
+	PushDeviceRepo repository.PushDeviceRepository
+	Sender         push.Sender
+}
+
+func NewPushService(cfg *PushSConfig) PushService {
+	return &pushServiceImpl{
+		pushDeviceRepo: cfg.PushDeviceRepo,
+		sender:         cfg.Sender,
+	}
+}
+
+func (s *pushServiceImpl) RegisterDevice(userId int, userAgent string, req *dto.RegisterDeviceRequest) (*model.PushDevice, error) {
+	device, err := req.ToModel(userId, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pushDeviceRepo.Upsert(device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+func (s *pushServiceImpl) GetDevices(userId int) ([]*model.PushDevice, error) {
+	return s.pushDeviceRepo.GetByUserID(userId)
+}
+
+func (s *pushServiceImpl) DeleteDevice(userId int, deviceId int) error {
+	return s.pushDeviceRepo.Delete(userId, deviceId)
+}
+
+func (s *pushServiceImpl) GetVAPIDPublicKey() *dto.VAPIDKeyResponse {
+	return &dto.VAPIDKeyResponse{PublicKey: config.Push.VAPIDPublicKey}
+}
+
+// Push sends msg to each device of userId. Devices the push service reports
+// as gone are deleted; other failures are only logged so one broken device
+// doesn't stop the rest.
+func (s *pushServiceImpl) Push(userId int, msg *push.Message) error {
+	devices, err := s.pushDeviceRepo.GetByUserID(userId)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		err := s.sender.Send(&push.Subscription{
+			Platform: device.Platform,
+			Token:    device.Token,
+			P256dh:   device.P256dh,
+			Auth:     device.Auth,
+		}, msg)
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, push.ErrSubscriptionGone) {
+			if err := s.pushDeviceRepo.DeleteByID(device.ID); err != nil {
+				log.Println("failed to prune push device", device.ID, ":", err)
+			}
+			continue
+		}
+
+		log.Println("failed to push to device", device.ID, ":", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/notification/model"
+	"kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/utils/push"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPushDevice(t *testing.T) {
+	var (
+		userId    = 1
+		userAgent = "Mozilla/5.0"
+	)
+
+	tests := []struct {
+		description string
+		req         *dto.RegisterDeviceRequest
+		beforeTest  func(*mocks.PushDeviceRepository)
+		expected    *model.PushDevice
+		err         error
+	}{
+		{
+			description: "should return error when web subscription has no keys",
+			req:         &dto.RegisterDeviceRequest{Platform: push.PlatformWeb, Endpoint: "https://push.example.com/abc"},
+			beforeTest:  func(pdr *mocks.PushDeviceRepository) {},
+			err:         errs.ErrInvalidPushSubscription,
+		},
+		{
+			description: "should return error when mobile device has no token",
+			req:         &dto.RegisterDeviceRequest{Platform: push.PlatformAndroid},
+			beforeTest:  func(pdr *mocks.PushDeviceRepository) {},
+			err:         errs.ErrInvalidPushSubscription,
+		},
+		{
+			description: "should return error when failed to save device",
+			req:         &dto.RegisterDeviceRequest{Platform: push.PlatformIOS, Token: "fcm-token"},
+			beforeTest: func(pdr *mocks.PushDeviceRepository) {
+				pdr.On("Upsert", &model.PushDevice{UserID: userId, Platform: push.PlatformIOS, Token: "fcm-token", UserAgent: userAgent}).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should store web subscription endpoint and keys",
+			req: &dto.RegisterDeviceRequest{
+				Platform: push.PlatformWeb,
+				Endpoint: "https://push.example.com/abc",
+				Keys:     &dto.WebPushKeys{P256dh: "key", Auth: "auth"},
+			},
+			beforeTest: func(pdr *mocks.PushDeviceRepository) {
+				pdr.On("Upsert", &model.PushDevice{
+					UserID:    userId,
+					Platform:  push.PlatformWeb,
+					Token:     "https://push.example.com/abc",
+					P256dh:    "key",
+					Auth:      "auth",
+					UserAgent: userAgent,
+				}).Return(nil)
+			},
+			expected: &model.PushDevice{
+				UserID:    userId,
+				Platform:  push.PlatformWeb,
+				Token:     "https://push.example.com/abc",
+				P256dh:    "key",
+				Auth:      "auth",
+				UserAgent: userAgent,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockPushDeviceRepo := mocks.NewPushDeviceRepository(t)
+			tc.beforeTest(mockPushDeviceRepo)
+			pushService := service.NewPushService(&service.PushSConfig{
+				PushDeviceRepo: mockPushDeviceRepo,
+			})
+
+			result, err := pushService.RegisterDevice(userId, userAgent, tc.req)
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPush(t *testing.T) {
+	msg := &push.Message{Title: "New message from budi", Body: "Halo"}
+
+	t.Run("should return error when failed to get devices", func(t *testing.T) {
+		mockPushDeviceRepo := mocks.NewPushDeviceRepository(t)
+		mockPushDeviceRepo.On("GetByUserID", 1).Return(nil, errors.New("error"))
+		pushService := service.NewPushService(&service.PushSConfig{
+			PushDeviceRepo: mockPushDeviceRepo,
+			Sender:         push.NewFakeSender(),
+		})
+
+		err := pushService.Push(1, msg)
+
+		assert.Equal(t, errors.New("error"), err)
+	})
+
+	t.Run("should push to every device and prune gone ones", func(t *testing.T) {
+		sender := push.NewFakeSender()
+		sender.Gone["dead"] = true
+		mockPushDeviceRepo := mocks.NewPushDeviceRepository(t)
+		mockPushDeviceRepo.On("GetByUserID", 1).Return([]*model.PushDevice{
+			{ID: 1, Platform: push.PlatformAndroid, Token: "alive"},
+			{ID: 2, Platform: push.PlatformIOS, Token: "dead"},
+			{ID: 3, Platform: push.PlatformWeb, Token: "https://push.example.com/abc", P256dh: "key", Auth: "auth"},
+		}, nil)
+		mockPushDeviceRepo.On("DeleteByID", 2).Return(nil)
+		pushService := service.NewPushService(&service.PushSConfig{
+			PushDeviceRepo: mockPushDeviceRepo,
+			Sender:         sender,
+		})
+
+		err := pushService.Push(1, msg)
+
+		assert.NoError(t, err)
+		assert.Len(t, sender.Sent, 2)
+		assert.Equal(t, &push.Subscription{Platform: push.PlatformWeb, Token: "https://push.example.com/abc", P256dh: "key", Auth: "auth"}, sender.Sent[1].Subscription)
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"bytes"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"strings"
+	"text/template"
+)
+
+type notificationTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	// humanize turns status constants such as ON_DELIVERY into "on delivery".
+	"humanize": func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, "_", " "))
+	},
+}
+
+func newNotificationTemplate(name, title, body string) *notificationTemplate {
+	return &notificationTemplate{
+		title: template.Must(template.New(name + ".title").Funcs(templateFuncs).Option("missingkey=error").Parse(title)),
+		body:  template.Must(template.New(name + ".body").Funcs(templateFuncs).Option("missingkey=error").Parse(body)),
+	}
+}
+
+var notificationTemplates = map[string]*notificationTemplate{
+	constant.NotificationTypeOrderStatus: newNotificationTemplate(
+		constant.NotificationTypeOrderStatus,
+		"Order {{.code}} updated",
+		"Your order {{.code}} is now {{humanize .status}}.",
+	),
+	constant.NotificationTypeRefundDecision: newNotificationTemplate(
+		constant.NotificationTypeRefundDecision,
+		"Refund request for {{.code}} {{humanize .status}}",
+		"Your refund request for order {{.code}} has been {{humanize .status}}.",
+	),
+	constant.NotificationTypeDiscussionReply: newNotificationTemplate(
+		constant.NotificationTypeDiscussionReply,
+		"{{.shopName}} answered your question",
+		"{{.shopName}} replied to your question on {{.productName}}: {{.message}}",
+	),
+	constant.NotificationTypeChatMessage: newNotificationTemplate(
+		constant.NotificationTypeChatMessage,
+		"New message from {{.sender}}",
+		"{{.message}}",
+	),
+	constant.NotificationTypeVoucherExpiry: newNotificationTemplate(
+		constant.NotificationTypeVoucherExpiry,
+		"Voucher {{.code}} expires soon",
+		"Your voucher {{.name}} expires on {{.expiredAt}}. Use it before it's gone!",
+	),
+}
+
+func renderNotification(notificationType string, data map[string]interface{}) (string, string, error) {
+	tmpl, ok := notificationTemplates[notificationType]
+	if !ok {
+		return "", "", errs.ErrUnknownNotificationType
+	}
+
+	var title, body bytes.Buffer
+	if err := tmpl.title.Execute(&title, data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	return title.String(), body.String(), nil
+}
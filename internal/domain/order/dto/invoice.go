@@ -53,6 +53,8 @@ type PayInvoiceRequest struct {
 	Signature       string  `json:"signature" binding:"required_unless=PaymentMethodID 1"`
 	Amount          float64 `json:"amount" binding:"required_unless=PaymentMethodID 1"`
 	TxnID           string  `json:"txnId" binding:"required_unless=PaymentMethodID 1"`
+	RefreshToken    string  `json:"refreshToken" binding:"required"`
+	SessionKey      string  `json:"-"`
 	UserID          int
 }
 
@@ -4,6 +4,7 @@ import (
 	"kedai/backend/be-kedai/internal/common/constant"
 	"kedai/backend/be-kedai/internal/domain/order/model"
 	productModel "kedai/backend/be-kedai/internal/domain/product/model"
+	"kedai/backend/be-kedai/internal/utils/mail"
 	"time"
 )
 
@@ -87,3 +88,40 @@ func (d *WithdrawInvoiceRequest) Validate() {
 		d.OrderID = []int{}
 	}
 }
+
+// InvoicePerShopEmailDetail is what order emails need to know about an
+// order, its buyer and its seller.
+type InvoicePerShopEmailDetail struct {
+	ID             int
+	InvoiceID      int
+	Code           string
+	Total          float64
+	TrackingNumber string
+	BuyerID        int
+	BuyerEmail     string
+	BuyerUsername  string
+	ShopName       string
+	SellerID       int
+	SellerEmail    string
+	SellerUsername string
+}
+
+func (d *InvoicePerShopEmailDetail) BuyerEmailData() *mail.OrderEmailData {
+	return &mail.OrderEmailData{
+		Username:       d.BuyerUsername,
+		ShopName:       d.ShopName,
+		Code:           d.Code,
+		TrackingNumber: d.TrackingNumber,
+		Total:          d.Total,
+	}
+}
+
+func (d *InvoicePerShopEmailDetail) SellerEmailData() *mail.OrderEmailData {
+	return &mail.OrderEmailData{
+		Username:       d.SellerUsername,
+		ShopName:       d.ShopName,
+		Code:           d.Code,
+		TrackingNumber: d.TrackingNumber,
+		Total:          d.Total,
+	}
+}
@@ -1,9 +1,31 @@
 package dto
 
-import "kedai/backend/be-kedai/internal/domain/order/model"
+import (
+	"kedai/backend/be-kedai/internal/domain/order/model"
+	"net/url"
+	"path"
+	"strings"
+)
+
+var allowedReviewMediaExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+	".mp4":  true,
+}
 
 type ReviewMediaRequest struct {
-	Url string `json:"url" binding:"required"`
+	Url string `json:"url" binding:"required,url,max=255"`
+}
+
+func (req *ReviewMediaRequest) IsValid() bool {
+	parsed, err := url.Parse(req.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+
+	return allowedReviewMediaExtensions[strings.ToLower(path.Ext(parsed.Path))]
 }
 
 func (req *ReviewMediaRequest) ToModel() *model.ReviewMedia {
@@ -12,11 +34,37 @@ func (req *ReviewMediaRequest) ToModel() *model.ReviewMedia {
 	}
 }
 
-func (req *TransactionReviewRequest) ToReviewMediaModels() []*model.ReviewMedia {
+func toReviewMediaModels(medias []ReviewMediaRequest) []*model.ReviewMedia {
 	var reviewMedias []*model.ReviewMedia
-	for _, reviewMedia := range req.ReviewMedias {
+	for _, reviewMedia := range medias {
 		reviewMedias = append(reviewMedias, reviewMedia.ToModel())
 	}
 
 	return reviewMedias
 }
+
+func validateReviewMedias(medias []ReviewMediaRequest) bool {
+	for _, media := range medias {
+		if !media.IsValid() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (req *TransactionReviewRequest) ToReviewMediaModels() []*model.ReviewMedia {
+	return toReviewMediaModels(req.ReviewMedias)
+}
+
+func (req *TransactionReviewRequest) ValidateMedias() bool {
+	return validateReviewMedias(req.ReviewMedias)
+}
+
+func (req *UpdateTransactionReviewRequest) ToReviewMediaModels() []*model.ReviewMedia {
+	return toReviewMediaModels(req.ReviewMedias)
+}
+
+func (req *UpdateTransactionReviewRequest) ValidateMedias() bool {
+	return validateReviewMedias(req.ReviewMedias)
+}
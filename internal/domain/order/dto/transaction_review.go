@@ -17,11 +17,34 @@ type TransactionReviewRequest struct {
 	UserId int
 }
 
+type UpdateTransactionReviewRequest struct {
+	Description *string `json:"description" binding:"max=500"`
+	Rating      int     `json:"rating" binding:"required,min=1,max=5,numeric"`
+
+	ReviewMedias []ReviewMediaRequest `json:"reviewMedias" binding:"max=5,dive"`
+
+	ReviewId int
+	UserId   int
+}
+
+type ReportReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type ReplyReviewRequest struct {
+	Reply string `json:"reply" binding:"required,max=500"`
+}
+
 type TransactionReviewResponse struct {
-	Description  *string              `json:"description"`
-	Rating       int                  `json:"rating"`
-	ReviewDate   time.Time            `json:"reviewDate"`
-	ReviewMedias []*model.ReviewMedia `json:"reviewMedias"`
+	ID              int                  `json:"id"`
+	Description     *string              `json:"description"`
+	Rating          int                  `json:"rating"`
+	ReviewDate      time.Time            `json:"reviewDate"`
+	EditedAt        *time.Time           `json:"editedAt"`
+	HelpfulCount    int                  `json:"helpfulCount"`
+	SellerReply     *string              `json:"sellerReply"`
+	SellerReplyDate *time.Time           `json:"sellerReplyDate"`
+	ReviewMedias    []*model.ReviewMedia `json:"reviewMedias"`
 }
 
 type ReviewResponse struct {
@@ -38,16 +61,21 @@ type UserReviewResponse struct {
 func (res *UserReviewResponse) ToResponse(user *userModel.User) {
 	res.Username = user.Username
 
-	if user.Profile != nil {
+	if user.Profile != nil && user.Profile.PhotoUrl != nil {
 		res.PhotoUrl = *user.Profile.PhotoUrl
 	}
 
 }
 
 func (res *TransactionReviewResponse) ToResponse(review *model.TransactionReview) {
+	res.ID = review.ID
 	res.Description = review.Description
 	res.Rating = review.Rating
 	res.ReviewDate = review.ReviewDate
+	res.EditedAt = review.EditedAt
+	res.HelpfulCount = review.HelpfulCount
+	res.SellerReply = review.SellerReply
+	res.SellerReplyDate = review.SellerReplyDate
 	res.ReviewMedias = review.ReviewMedias
 }
 
@@ -74,7 +102,7 @@ func ConvertReviewsToResponses(reviews []*model.TransactionReview) (responses []
 }
 
 func (req *TransactionReviewRequest) ToModel() *model.TransactionReview {
-	if *req.Description == "" {
+	if req.Description != nil && *req.Description == "" {
 		req.Description = nil
 	}
 
@@ -85,3 +113,21 @@ func (req *TransactionReviewRequest) ToModel() *model.TransactionReview {
 		ReviewMedias:  req.ToReviewMediaModels(),
 	}
 }
+
+func (req *UpdateTransactionReviewRequest) ApplyTo(review *model.TransactionReview) {
+	if req.Description != nil && *req.Description == "" {
+		req.Description = nil
+	}
+
+	review.Description = req.Description
+	review.Rating = req.Rating
+	review.ReviewMedias = req.ToReviewMediaModels()
+}
+
+func (req *ReportReviewRequest) ToModel(reviewID, userID int) *model.ReviewReport {
+	return &model.ReviewReport{
+		Reason:   req.Reason,
+		ReviewID: reviewID,
+		UserID:   userID,
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"kedai/backend/be-kedai/internal/utils/response"
 	"log"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -82,11 +81,9 @@ func (h *Handler) PayInvoice(c *gin.Context) {
 	}
 
 	req.UserID = c.GetInt("userId")
+	req.SessionKey = c.GetString("sessionKey")
 
-	token := c.GetHeader("authorization")
-	token = strings.Replace(token, "Bearer ", "", -1)
-
-	invoice, err := h.invoiceService.PayInvoice(req, token)
+	invoice, err := h.invoiceService.PayInvoice(req)
 	if err != nil {
 		if errors.Is(err, commonErr.ErrInvoiceNotFound) || errors.Is(err, commonErr.ErrInvoiceAlreadyPaid) {
 			response.Error(c, http.StatusBadRequest, code.BAD_REQUEST, err.Error())
@@ -103,6 +100,16 @@ func (h *Handler) PayInvoice(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, commonErr.ErrExpiredToken) {
+			response.Error(c, http.StatusUnauthorized, code.TOKEN_EXPIRED, err.Error())
+			return
+		}
+
+		if errors.Is(err, commonErr.ErrRefreshTokenReused) {
+			response.Error(c, http.StatusUnauthorized, code.REFRESH_TOKEN_REUSED, err.Error())
+			return
+		}
+
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
 		return
 	}
@@ -210,6 +210,14 @@ func TestPayInvoice(t *testing.T) {
 			InvoiceID:       1,
 			UserID:          1,
 			PaymentMethodID: 1,
+			RefreshToken:    "refresh",
+		}
+		paid = dto.PayInvoiceRequest{
+			InvoiceID:       1,
+			UserID:          1,
+			PaymentMethodID: 1,
+			RefreshToken:    "refresh",
+			SessionKey:      "session",
 		}
 		res = userDto.Token{
 			AccessToken:  token,
@@ -234,7 +242,7 @@ func TestPayInvoice(t *testing.T) {
 			},
 			code: http.StatusOK,
 			beforeTest: func(is *mocks.InvoiceService) {
-				is.On("PayInvoice", req, token).Return(&res, nil)
+				is.On("PayInvoice", paid).Return(&res, nil)
 			},
 		},
 		{
@@ -242,7 +250,7 @@ func TestPayInvoice(t *testing.T) {
 			req:  dto.PayInvoiceRequest{},
 			want: response.Response{
 				Code:    code.BAD_REQUEST,
-				Message: "TxnID is required",
+				Message: "RefreshToken is required",
 			},
 			code:       http.StatusBadRequest,
 			beforeTest: func(is *mocks.InvoiceService) {},
@@ -256,7 +264,7 @@ func TestPayInvoice(t *testing.T) {
 			},
 			code: http.StatusBadRequest,
 			beforeTest: func(is *mocks.InvoiceService) {
-				is.On("PayInvoice", req, token).Return(nil, errs.ErrInvoiceNotFound)
+				is.On("PayInvoice", paid).Return(nil, errs.ErrInvoiceNotFound)
 			},
 		},
 		{
@@ -268,7 +276,19 @@ func TestPayInvoice(t *testing.T) {
 			},
 			code: http.StatusBadRequest,
 			beforeTest: func(is *mocks.InvoiceService) {
-				is.On("PayInvoice", req, token).Return(nil, errs.ErrInsufficientBalance)
+				is.On("PayInvoice", paid).Return(nil, errs.ErrInsufficientBalance)
+			},
+		},
+		{
+			name: "should return 401 when the presented refresh token was already used",
+			req:  req,
+			want: response.Response{
+				Code:    code.REFRESH_TOKEN_REUSED,
+				Message: errs.ErrRefreshTokenReused.Error(),
+			},
+			code: http.StatusUnauthorized,
+			beforeTest: func(is *mocks.InvoiceService) {
+				is.On("PayInvoice", paid).Return(nil, errs.ErrRefreshTokenReused)
 			},
 		},
 		{
@@ -280,7 +300,7 @@ func TestPayInvoice(t *testing.T) {
 			},
 			code: http.StatusInternalServerError,
 			beforeTest: func(is *mocks.InvoiceService) {
-				is.On("PayInvoice", req, token).Return(nil, errs.ErrInternalServerError)
+				is.On("PayInvoice", paid).Return(nil, errs.ErrInternalServerError)
 			},
 		},
 	}
@@ -295,6 +315,7 @@ func TestPayInvoice(t *testing.T) {
 			c, _ := gin.CreateTestContext(rec)
 			c.Set("userId", userId)
 			c.Set("level", 1)
+			c.Set("sessionKey", "session")
 
 			c.Request, _ = http.NewRequest(http.MethodPost, "/v1/orders/invoices", payload)
 			c.Request.Header.Add("authorization", "Bearer "+token)
@@ -45,7 +45,7 @@ func (h *Handler) GetInvoicePerShopsByShopId(c *gin.Context) {
 
 	req.Validate()
 
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	result, err := h.invoicePerShopService.GetInvoicesByShopId(userId, &req)
 	if err != nil {
@@ -87,9 +87,10 @@ func (h *Handler) WithdrawFromInvoice(c *gin.Context) {
 	}
 	req.Validate()
 
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
+	memberId := c.GetInt("userId")
 
-	err = h.invoicePerShopService.WithdrawFromInvoice(req.OrderID, userId)
+	err = h.invoicePerShopService.WithdrawFromInvoice(req.OrderID, userId, memberId)
 	if err != nil {
 		if errors.Is(err, errs.ErrInvoiceNotFound) {
 			response.Error(c, http.StatusNotFound, code.INVOICE_NOT_FOUND, err.Error())
@@ -112,7 +113,7 @@ func (h *Handler) WithdrawFromInvoice(c *gin.Context) {
 }
 
 func (h *Handler) GetInvoiceByShopIdAndOrderId(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	id := c.Param("orderId")
 	idInt, _ := strconv.Atoi(id)
 
@@ -151,7 +152,7 @@ func (h *Handler) GetShopOrder(c *gin.Context) {
 	}
 
 	req.Validate()
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	result, err := h.invoicePerShopService.GetShopOrder(userId, &req)
 	if err != nil {
@@ -186,10 +187,11 @@ func (h *Handler) Refund(c *gin.Context) {
 }
 
 func (h *Handler) UpdateToProcessing(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
+	memberId := c.GetInt("userId")
 	orderId, _ := strconv.Atoi(c.Param("orderId"))
 
-	err := h.invoicePerShopService.UpdateStatusToProcessing(userId, orderId)
+	err := h.invoicePerShopService.UpdateStatusToProcessing(userId, memberId, orderId)
 	if err != nil {
 		if errors.Is(err, errs.ErrShopNotFound) {
 			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
@@ -209,10 +211,11 @@ func (h *Handler) UpdateToProcessing(c *gin.Context) {
 }
 
 func (h *Handler) UpdateToDelivery(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
+	memberId := c.GetInt("userId")
 	orderId, _ := strconv.Atoi(c.Param("orderId"))
 
-	err := h.invoicePerShopService.UpdateStatusToDelivery(userId, orderId)
+	err := h.invoicePerShopService.UpdateStatusToDelivery(userId, memberId, orderId)
 	if err != nil {
 		if errors.Is(err, errs.ErrShopNotFound) {
 			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
@@ -250,10 +253,11 @@ func (h *Handler) UpdateToCanceled(c *gin.Context) {
 }
 
 func (h *Handler) UpdateToRefundPendingSellerCancel(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
+	memberId := c.GetInt("userId")
 	orderId, _ := strconv.Atoi(c.Param("orderId"))
 
-	err := h.invoicePerShopService.UpdateStatusToRefundPendingSellerCancel(userId, orderId)
+	err := h.invoicePerShopService.UpdateStatusToRefundPendingSellerCancel(userId, memberId, orderId)
 	if err != nil {
 		if errors.Is(err, errs.ErrShopNotFound) {
 			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
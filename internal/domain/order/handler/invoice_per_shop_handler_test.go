@@ -309,7 +309,7 @@ func TestGetInvoicePerShopsByShopId(t *testing.T) {
 			tc.beforeTest(invoicePerShopService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", userId)
+			c.Set("shopOwnerId", userId)
 			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/sellers/finances/incomes?startDate=%s&endDate=%s&s=%s&status=%s", tc.input.req.StartDate, tc.input.req.EndDate, tc.input.req.S, tc.input.req.Status), nil)
 			handler := handler.New(&handler.Config{
 				InvoicePerShopService: invoicePerShopService,
@@ -432,7 +432,7 @@ func TestGetInvoiceByShopIdAndOrderId(t *testing.T) {
 			tc.beforeTest(invoicePerShopService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", userId)
+			c.Set("shopOwnerId", userId)
 			c.Params = []gin.Param{
 				{
 					Key:   "orderId",
@@ -455,8 +455,9 @@ func TestGetInvoiceByShopIdAndOrderId(t *testing.T) {
 
 func TestWithdrawFromInvoice(t *testing.T) {
 	var (
-		userId  = 1
-		orderId = []int{1}
+		userId   = 1
+		memberId = 2
+		orderId  = []int{1}
 	)
 	type input struct {
 		req        dto.WithdrawInvoiceRequest
@@ -498,7 +499,7 @@ func TestWithdrawFromInvoice(t *testing.T) {
 				},
 				err: errs.ErrInvoiceNotFound,
 				beforeTest: func(ipss *mocks.InvoicePerShopService) {
-					ipss.On("WithdrawFromInvoice", orderId, userId).Return(errs.ErrInvoiceNotFound)
+					ipss.On("WithdrawFromInvoice", orderId, userId, memberId).Return(errs.ErrInvoiceNotFound)
 				},
 			},
 			expected: expected{
@@ -517,7 +518,7 @@ func TestWithdrawFromInvoice(t *testing.T) {
 				},
 				err: errs.ErrShopNotFound,
 				beforeTest: func(ipss *mocks.InvoicePerShopService) {
-					ipss.On("WithdrawFromInvoice", orderId, userId).Return(errs.ErrShopNotFound)
+					ipss.On("WithdrawFromInvoice", orderId, userId, memberId).Return(errs.ErrShopNotFound)
 				},
 			},
 			expected: expected{
@@ -536,7 +537,7 @@ func TestWithdrawFromInvoice(t *testing.T) {
 				},
 				err: errs.ErrWalletDoesNotExist,
 				beforeTest: func(ipss *mocks.InvoicePerShopService) {
-					ipss.On("WithdrawFromInvoice", orderId, userId).Return(errs.ErrWalletDoesNotExist)
+					ipss.On("WithdrawFromInvoice", orderId, userId, memberId).Return(errs.ErrWalletDoesNotExist)
 				},
 			},
 			expected: expected{
@@ -555,7 +556,7 @@ func TestWithdrawFromInvoice(t *testing.T) {
 				},
 				err: errs.ErrInternalServerError,
 				beforeTest: func(ipss *mocks.InvoicePerShopService) {
-					ipss.On("WithdrawFromInvoice", orderId, userId).Return(errs.ErrInternalServerError)
+					ipss.On("WithdrawFromInvoice", orderId, userId, memberId).Return(errs.ErrInternalServerError)
 				},
 			},
 			expected: expected{
@@ -574,7 +575,7 @@ func TestWithdrawFromInvoice(t *testing.T) {
 				},
 				err: nil,
 				beforeTest: func(ipss *mocks.InvoicePerShopService) {
-					ipss.On("WithdrawFromInvoice", orderId, userId).Return(nil)
+					ipss.On("WithdrawFromInvoice", orderId, userId, memberId).Return(nil)
 				},
 			},
 			expected: expected{
@@ -593,7 +594,8 @@ func TestWithdrawFromInvoice(t *testing.T) {
 			tc.beforeTest(invoicePerShopService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", userId)
+			c.Set("shopOwnerId", userId)
+			c.Set("userId", memberId)
 
 			payload := test.MakeRequestBody(tc.input.req)
 			c.Request, _ = http.NewRequest(http.MethodPost, "/sellers/finances/incomes/withdrawals", payload)
@@ -717,7 +719,7 @@ func TestGetShopOrder(t *testing.T) {
 			tc.beforeTest(invoicePerShopService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", userId)
+			c.Set("shopOwnerId", userId)
 			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/sellers/orders?startDate=%s&endDate=%s&user=%s", tc.input.req.StartDate, tc.input.req.EndDate, tc.input.req.Username), nil)
 			handler := handler.New(&handler.Config{
 				InvoicePerShopService: invoicePerShopService,
@@ -733,8 +735,9 @@ func TestGetShopOrder(t *testing.T) {
 
 func TestUpdateToDelivery(t *testing.T) {
 	var (
-		userId  = 1
-		orderId = 1
+		userId   = 1
+		memberId = 2
+		orderId  = 1
 	)
 	type input struct {
 		userId  int
@@ -816,10 +819,11 @@ func TestUpdateToDelivery(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			expectedJson, _ := json.Marshal(tc.expected.response)
 			invoicePerShopService := mocks.NewInvoicePerShopService(t)
-			invoicePerShopService.On("UpdateStatusToDelivery", tc.input.userId, tc.input.orderId).Return(tc.input.err)
+			invoicePerShopService.On("UpdateStatusToDelivery", tc.input.userId, memberId, tc.input.orderId).Return(tc.input.err)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", userId)
+			c.Set("shopOwnerId", userId)
+			c.Set("userId", memberId)
 			c.AddParam("orderId", "1")
 			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/sellers/orders/{%d}/delivery", tc.orderId), nil)
 			handler := handler.New(&handler.Config{
@@ -836,8 +840,9 @@ func TestUpdateToDelivery(t *testing.T) {
 
 func TestUpdateToProcessing(t *testing.T) {
 	var (
-		userId  = 1
-		orderId = 1
+		userId   = 1
+		memberId = 2
+		orderId  = 1
 	)
 	type input struct {
 		userId  int
@@ -919,10 +924,11 @@ func TestUpdateToProcessing(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			expectedJson, _ := json.Marshal(tc.expected.response)
 			invoicePerShopService := mocks.NewInvoicePerShopService(t)
-			invoicePerShopService.On("UpdateStatusToProcessing", tc.input.userId, tc.input.orderId).Return(tc.input.err)
+			invoicePerShopService.On("UpdateStatusToProcessing", tc.input.userId, memberId, tc.input.orderId).Return(tc.input.err)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", userId)
+			c.Set("shopOwnerId", userId)
+			c.Set("userId", memberId)
 			c.AddParam("orderId", "1")
 			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/sellers/orders/{%d}/process", tc.orderId), nil)
 			handler := handler.New(&handler.Config{
@@ -1110,8 +1116,9 @@ func TestUpdateToReceived(t *testing.T) {
 
 func TestUpdateToRefundPendingSellerCancel(t *testing.T) {
 	var (
-		userId  = 1
-		orderId = 1
+		userId   = 1
+		memberId = 2
+		orderId  = 1
 	)
 	type input struct {
 		userId  int
@@ -1192,11 +1199,12 @@ func TestUpdateToRefundPendingSellerCancel(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			expectedJson, _ := json.Marshal(tc.expected.response)
 			invoicePerShopService := mocks.NewInvoicePerShopService(t)
-			invoicePerShopService.On("UpdateStatusToRefundPendingSellerCancel", tc.input.userId, tc.input.orderId).Return(tc.input.err)
+			invoicePerShopService.On("UpdateStatusToRefundPendingSellerCancel", tc.input.userId, memberId, tc.input.orderId).Return(tc.input.err)
 
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", userId)
+			c.Set("shopOwnerId", userId)
+			c.Set("userId", memberId)
 			c.AddParam("orderId", "1")
 			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/admins/orders/{%d}/cancel-request", tc.orderId), nil)
 			handler := handler.New(&handler.Config{
@@ -14,7 +14,8 @@ import (
 
 func (h *Handler) UpdateRefundStatus(c *gin.Context) {
 	var req dto.RefundRequest
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
+	memberId := c.GetInt("userId")
 	invoiceId, _ := strconv.Atoi(c.Param("orderId"))
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -27,7 +28,7 @@ func (h *Handler) UpdateRefundStatus(c *gin.Context) {
 		return
 	}
 
-	err := h.refundRequestService.UpdateRefundStatus(userId, invoiceId, req.RefundStatus)
+	err := h.refundRequestService.UpdateRefundStatus(userId, memberId, invoiceId, req.RefundStatus)
 
 	if err != nil {
 		if errors.Is(err, commonErr.ErrInvoiceNotFound) {
@@ -76,7 +76,7 @@ func TestUpdateRefundStatus(t *testing.T) {
 					RefundStatus: "SELLER_APPROVED",
 				},
 				beforeTest: func(m *mocks.RefundRequestService) {
-					m.On("UpdateRefundStatus", 1, 1, "SELLER_APPROVED").Return(commonErr.ErrRefundRequestNotFound)
+					m.On("UpdateRefundStatus", 1, 2, 1, "SELLER_APPROVED").Return(commonErr.ErrRefundRequestNotFound)
 				},
 			},
 			expected: expected{
@@ -95,7 +95,7 @@ func TestUpdateRefundStatus(t *testing.T) {
 					RefundStatus: "SELLER_APPROVED",
 				},
 				beforeTest: func(m *mocks.RefundRequestService) {
-					m.On("UpdateRefundStatus", 1, 1, "SELLER_APPROVED").Return(commonErr.ErrInternalServerError)
+					m.On("UpdateRefundStatus", 1, 2, 1, "SELLER_APPROVED").Return(commonErr.ErrInternalServerError)
 				},
 			},
 			expected: expected{
@@ -114,7 +114,7 @@ func TestUpdateRefundStatus(t *testing.T) {
 					RefundStatus: "SELLER_APPROVED",
 				},
 				beforeTest: func(m *mocks.RefundRequestService) {
-					m.On("UpdateRefundStatus", 1, 1, "SELLER_APPROVED").Return(nil)
+					m.On("UpdateRefundStatus", 1, 2, 1, "SELLER_APPROVED").Return(nil)
 				},
 			},
 			expected: expected{
@@ -133,7 +133,7 @@ func TestUpdateRefundStatus(t *testing.T) {
 					RefundStatus: "REJECTED",
 				},
 				beforeTest: func(m *mocks.RefundRequestService) {
-					m.On("UpdateRefundStatus", 1, 1, "REJECTED").Return(nil)
+					m.On("UpdateRefundStatus", 1, 2, 1, "REJECTED").Return(nil)
 				},
 			},
 			expected: expected{
@@ -155,7 +155,8 @@ func TestUpdateRefundStatus(t *testing.T) {
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
 
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
+			c.Set("userId", 2)
 			c.AddParam("orderId", "1")
 			c.Request, _ = http.NewRequest(http.MethodPut, fmt.Sprintf("/sellers/orders/{%d}/refund", tc.input.invoiceId), inputBody)
 			handler := handler.New(&handler.Config{
@@ -37,6 +37,10 @@ func (h *Handler) AddTransactionReview(c *gin.Context) {
 			response.Error(c, http.StatusConflict, code.INVOICE_NOT_COMPLETED, err.Error())
 			return
 		}
+		if errors.Is(err, commonErr.ErrInvalidReviewMedia) {
+			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_REVIEW_MEDIA, err.Error())
+			return
+		}
 
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
 		return
@@ -66,3 +70,72 @@ func (h *Handler) GetReviewByTransactionID(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, code.OK, "success", review)
 }
+
+func (h *Handler) UpdateTransactionReview(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil || reviewID < 1 {
+		response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, commonErr.ErrTransactionReviewNotFound.Error())
+		return
+	}
+
+	var req dto.UpdateTransactionReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	req.ReviewId = reviewID
+	req.UserId = c.GetInt("userId")
+
+	review, err := h.transactionReviewService.Update(req)
+	if err != nil {
+		if errors.Is(err, commonErr.ErrTransactionReviewNotFound) {
+			response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, commonErr.ErrReviewEditWindowClosed) {
+			response.Error(c, http.StatusUnprocessableEntity, code.REVIEW_EDIT_WINDOW_CLOSED, err.Error())
+			return
+		}
+		if errors.Is(err, commonErr.ErrInvalidReviewMedia) {
+			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_REVIEW_MEDIA, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "update successful", review)
+}
+
+func (h *Handler) ReplyTransactionReview(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil || reviewID < 1 {
+		response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, commonErr.ErrTransactionReviewNotFound.Error())
+		return
+	}
+
+	var req dto.ReplyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.transactionReviewService.ReplyReview(c.GetInt("shopOwnerId"), reviewID, &req)
+	if err != nil {
+		if errors.Is(err, commonErr.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+		if errors.Is(err, commonErr.ErrTransactionReviewNotFound) {
+			response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonErr.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "update successful", nil)
+}
@@ -332,3 +332,273 @@ func TestGetReviewByTranscationID(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateTransactionReview(t *testing.T) {
+	var emptyString = ""
+	type input struct {
+		reviewId   string
+		req        dto.UpdateTransactionReviewRequest
+		beforeTest func(mockTransactionReviewService *mocks.TransactionReviewService)
+	}
+
+	type expected struct {
+		response   *response.Response
+		statusCode int
+	}
+
+	cases := []struct {
+		description string
+		input       input
+		expected    expected
+	}{
+		{
+			description: "should return error when review id is invalid",
+			input: input{
+				reviewId:   "abc",
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.TRANSACTION_REVIEW_NOT_FOUND,
+					Message: commonErr.ErrTransactionReviewNotFound.Error(),
+				},
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description: "should return error when request is invalid",
+			input: input{
+				reviewId:   "1",
+				req:        dto.UpdateTransactionReviewRequest{Description: &emptyString},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Rating is required",
+				},
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "should return error when edit window is closed",
+			input: input{
+				reviewId: "1",
+				req:      dto.UpdateTransactionReviewRequest{Rating: 4, Description: &emptyString},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {
+					mockTransactionReviewService.On("Update", dto.UpdateTransactionReviewRequest{
+						Description: &emptyString,
+						Rating:      4,
+						ReviewId:    1,
+						UserId:      1,
+					}).Return(nil, commonErr.ErrReviewEditWindowClosed)
+				},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.REVIEW_EDIT_WINDOW_CLOSED,
+					Message: commonErr.ErrReviewEditWindowClosed.Error(),
+				},
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			description: "should return error when review media is invalid",
+			input: input{
+				reviewId: "1",
+				req:      dto.UpdateTransactionReviewRequest{Rating: 4, Description: &emptyString},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {
+					mockTransactionReviewService.On("Update", dto.UpdateTransactionReviewRequest{
+						Description: &emptyString,
+						Rating:      4,
+						ReviewId:    1,
+						UserId:      1,
+					}).Return(nil, commonErr.ErrInvalidReviewMedia)
+				},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.INVALID_REVIEW_MEDIA,
+					Message: commonErr.ErrInvalidReviewMedia.Error(),
+				},
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			description: "should return error when internal server error",
+			input: input{
+				reviewId: "1",
+				req:      dto.UpdateTransactionReviewRequest{Rating: 4, Description: &emptyString},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {
+					mockTransactionReviewService.On("Update", dto.UpdateTransactionReviewRequest{
+						Description: &emptyString,
+						Rating:      4,
+						ReviewId:    1,
+						UserId:      1,
+					}).Return(nil, errors.New("error"))
+				},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: commonErr.ErrInternalServerError.Error(),
+				},
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			description: "should return updated review when success",
+			input: input{
+				reviewId: "1",
+				req:      dto.UpdateTransactionReviewRequest{Rating: 4, Description: &emptyString},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {
+					mockTransactionReviewService.On("Update", dto.UpdateTransactionReviewRequest{
+						Description: &emptyString,
+						Rating:      4,
+						ReviewId:    1,
+						UserId:      1,
+					}).Return(&model.TransactionReview{ID: 1, Rating: 4}, nil)
+				},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.UPDATED,
+					Message: "update successful",
+					Data:    &model.TransactionReview{ID: 1, Rating: 4},
+				},
+				statusCode: http.StatusOK,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			c.AddParam("reviewId", tc.input.reviewId)
+
+			payload := test.MakeRequestBody(tc.input.req)
+			c.Request, _ = http.NewRequest(http.MethodPut, fmt.Sprintf("/orders/transactions/reviews/%s", tc.input.reviewId), payload)
+
+			mockTransactionReviewService := mocks.NewTransactionReviewService(t)
+			tc.input.beforeTest(mockTransactionReviewService)
+
+			handler := handler.New(&handler.Config{
+				TransactionReviewService: mockTransactionReviewService,
+			})
+
+			handler.UpdateTransactionReview(c)
+
+			expectedJson, _ := json.Marshal(tc.expected.response)
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedJson), rec.Body.String())
+		})
+	}
+}
+
+func TestReplyTransactionReview(t *testing.T) {
+	type input struct {
+		req        dto.ReplyReviewRequest
+		beforeTest func(mockTransactionReviewService *mocks.TransactionReviewService)
+	}
+
+	type expected struct {
+		response   *response.Response
+		statusCode int
+	}
+
+	cases := []struct {
+		description string
+		input       input
+		expected    expected
+	}{
+		{
+			description: "should return error when request is invalid",
+			input: input{
+				req:        dto.ReplyReviewRequest{},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Reply is required",
+				},
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			description: "should return error when shop not found",
+			input: input{
+				req: dto.ReplyReviewRequest{Reply: "thanks"},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {
+					mockTransactionReviewService.On("ReplyReview", 1, 1, &dto.ReplyReviewRequest{Reply: "thanks"}).Return(commonErr.ErrShopNotFound)
+				},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.SHOP_NOT_REGISTERED,
+					Message: commonErr.ErrShopNotFound.Error(),
+				},
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description: "should return error when review not found",
+			input: input{
+				req: dto.ReplyReviewRequest{Reply: "thanks"},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {
+					mockTransactionReviewService.On("ReplyReview", 1, 1, &dto.ReplyReviewRequest{Reply: "thanks"}).Return(commonErr.ErrTransactionReviewNotFound)
+				},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.TRANSACTION_REVIEW_NOT_FOUND,
+					Message: commonErr.ErrTransactionReviewNotFound.Error(),
+				},
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			description: "should return success when reply saved",
+			input: input{
+				req: dto.ReplyReviewRequest{Reply: "thanks"},
+				beforeTest: func(mockTransactionReviewService *mocks.TransactionReviewService) {
+					mockTransactionReviewService.On("ReplyReview", 1, 1, &dto.ReplyReviewRequest{Reply: "thanks"}).Return(nil)
+				},
+			},
+			expected: expected{
+				response: &response.Response{
+					Code:    code.UPDATED,
+					Message: "update successful",
+				},
+				statusCode: http.StatusOK,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("shopOwnerId", 1)
+			c.AddParam("reviewId", "1")
+
+			payload := test.MakeRequestBody(tc.input.req)
+			c.Request, _ = http.NewRequest(http.MethodPut, "/sellers/reviews/1/reply", payload)
+
+			mockTransactionReviewService := mocks.NewTransactionReviewService(t)
+			tc.input.beforeTest(mockTransactionReviewService)
+
+			handler := handler.New(&handler.Config{
+				TransactionReviewService: mockTransactionReviewService,
+			})
+
+			handler.ReplyTransactionReview(c)
+
+			expectedJson, _ := json.Marshal(tc.expected.response)
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedJson), rec.Body.String())
+		})
+	}
+}
@@ -22,6 +22,7 @@ type InvoicePerShop struct {
 	VoucherType    *string  `json:"voucherType,omitempty"`
 	Status         string   `json:"status"`
 	IsReleased     bool     `json:"isReleased"`
+	ReleasedByID   *int     `json:"releasedById,omitempty"`
 
 	UserID           int  `json:"userId"`
 	VoucherID        *int `json:"voucherId,omitempty"`
@@ -11,7 +11,8 @@ type InvoiceStatus struct {
 	Status     string    `json:"status"`
 	StatusDate time.Time `json:"statusDate" gorm:"default:CURRENT_TIMESTAMP"`
 
-	InvoicePerShopID int `json:"invoicePerShopId"`
+	InvoicePerShopID int  `json:"invoicePerShopId"`
+	ActorID          *int `json:"actorId,omitempty"`
 
 	gorm.Model `json:"-"`
 }
@@ -15,9 +15,10 @@ type Transaction struct {
 	PromotedQuantity int     `json:"promotedQuantity"`
 	Note             *string `json:"note"`
 
-	InvoiceID int `json:"invoiceId"`
-	UserID    int `json:"userId"`
-	SkuID     int `json:"skuId"`
+	InvoiceID          int  `json:"invoiceId"`
+	UserID             int  `json:"userId"`
+	SkuID              int  `json:"skuId"`
+	FlashSaleProductID *int `json:"flashSaleProductId,omitempty"`
 
 	Review   *TransactionReview   `json:"review,omitempty"`
 	User     *userModel.User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
@@ -7,10 +7,15 @@ import (
 )
 
 type TransactionReview struct {
-	ID          int       `json:"id"`
-	Description *string   `json:"description"`
-	Rating      int       `json:"rating"`
-	ReviewDate  time.Time `json:"reviewDate"`
+	ID              int        `json:"id"`
+	Description     *string    `json:"description"`
+	Rating          int        `json:"rating"`
+	ReviewDate      time.Time  `json:"reviewDate"`
+	EditedAt        *time.Time `json:"editedAt"`
+	HelpfulCount    int        `json:"helpfulCount"`
+	IsHidden        bool       `json:"-"`
+	SellerReply     *string    `json:"sellerReply"`
+	SellerReplyDate *time.Time `json:"sellerReplyDate"`
 
 	TransactionId int          `json:"transactionId"`
 	Transaction   *Transaction `json:"transactions,omitempty" gorm:"foreignKey:TransactionId"`
@@ -19,3 +24,20 @@ type TransactionReview struct {
 
 	gorm.Model `json:"-"`
 }
+
+type ReviewVote struct {
+	ID       int `json:"id"`
+	ReviewID int `json:"reviewId"`
+	UserID   int `json:"userId"`
+
+	gorm.Model `json:"-"`
+}
+
+type ReviewReport struct {
+	ID       int    `json:"id"`
+	Reason   string `json:"reason"`
+	ReviewID int    `json:"reviewId"`
+	UserID   int    `json:"userId"`
+
+	gorm.Model `json:"-"`
+}
@@ -8,6 +8,7 @@ import (
 	marketplaceModel "kedai/backend/be-kedai/internal/domain/marketplace/model"
 	"kedai/backend/be-kedai/internal/domain/order/dto"
 	"kedai/backend/be-kedai/internal/domain/order/model"
+	productModel "kedai/backend/be-kedai/internal/domain/product/model"
 	productDomainRepo "kedai/backend/be-kedai/internal/domain/product/repository"
 	userModel "kedai/backend/be-kedai/internal/domain/user/model"
 	userRepo "kedai/backend/be-kedai/internal/domain/user/repository"
@@ -23,9 +24,11 @@ type InvoicePerShopRepository interface {
 	Create(tx *gorm.DB, invoicePerShop *model.InvoicePerShop) error
 	GetByID(id int) (*model.InvoicePerShop, error)
 	GetByUserIDAndCode(userID int, code string) (*dto.InvoicePerShopDetail, error)
+	GetAllByUserID(userID int) ([]*dto.InvoicePerShopDetail, error)
+	HasOpenByUserID(userID int) (bool, error)
 	GetShopFinanceToRelease(shopID int) (float64, error)
 	GetByShopId(shopId int, req *dto.InvoicePerShopFilterRequest) ([]*dto.InvoicePerShopDetail, int64, int, error)
-	WithdrawFromInvoice(invoicePerShopIds []int, shopId int, walletId int) error
+	WithdrawFromInvoice(invoicePerShopIds []int, shopId int, walletId int, actorId int) error
 	GetByShopIdAndId(shopId int, id int) (*dto.InvoicePerShopDetail, error)
 	GetByShopIdAndCode(shopId int, code string) (*dto.InvoicePerShopDetail, error)
 	GetShopOrder(shopId int, req *dto.InvoicePerShopFilterRequest) ([]*dto.InvoicePerShopDetail, int64, int, error)
@@ -38,7 +41,8 @@ type InvoicePerShopRepository interface {
 	UpdateStatusToRefundPending(shopId int, orderId int, invoiceStatuses []*model.InvoiceStatus, refundType string) error
 	UpdateStatusToRefunded(tx *gorm.DB, shopId int, orderId int) error
 	UpdateRefundStatus(tx *gorm.DB, shopId int, orderId int, refundStatus string, invoiceStatuses []*model.InvoiceStatus) error
-	UpdateStatusCRONJob() error
+	UpdateStatusCRONJob() ([]int, error)
+	GetEmailDetails(ids []int) ([]*dto.InvoicePerShopEmailDetail, error)
 	AutoReceivedCRONJob() error
 	AutoCompletedCRONJob() error
 }
@@ -193,6 +197,54 @@ func (r *invoicePerShopRepositoryImpl) GetByUserIDAndCode(userID int, code strin
 	return &invoice, nil
 }
 
+func (r *invoicePerShopRepositoryImpl) GetAllByUserID(userID int) ([]*dto.InvoicePerShopDetail, error) {
+	var invoices []*dto.InvoicePerShopDetail
+
+	query := r.db.
+		Select("invoice_per_shops.*, invoices.voucher_amount AS marketplace_voucher_amount, invoices.voucher_type AS marketplace_voucher_type, invoices.payment_date AS payment_date").
+		Joins("JOIN invoices ON invoices.id = invoice_per_shops.invoice_id").
+		Where("invoice_per_shops.user_id = ?", userID)
+
+	query = query.Preload("TransactionItems", func(query *gorm.DB) *gorm.DB {
+		return query.Select(`
+			transactions.*,
+			(SELECT url FROM product_medias WHERE products.id = product_medias.product_id LIMIT 1) AS image_url,
+			products.name AS product_name
+		`).
+			Joins("JOIN skus ON skus.id = transactions.sku_id").
+			Joins("JOIN products ON skus.product_id = products.id")
+	}).
+		Preload("TransactionItems.Variants")
+
+	query = query.Preload("Address.Province").
+		Preload("Address.City").
+		Preload("Address.District").
+		Preload("Address.Subdistrict")
+
+	err := query.Preload("CourierService.Courier").Preload("StatusList").Preload("Shop").Order("invoices.payment_date DESC").Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// HasOpenByUserID reports whether the user has an order that is neither
+// completed, refunded nor canceled, counting unpaid ones.
+func (r *invoicePerShopRepositoryImpl) HasOpenByUserID(userID int) (bool, error) {
+	var count int64
+
+	err := r.db.Model(&model.InvoicePerShop{}).
+		Where("user_id = ?", userID).
+		Where("status NOT IN ?", []string{constant.TransactionStatusCompleted, constant.TransactionStatusRefunded, constant.TransactionStatusCanceled}).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func (r *invoicePerShopRepositoryImpl) GetShopFinanceToRelease(shopID int) (float64, error) {
 
 	var (
@@ -272,7 +324,7 @@ func (r *invoicePerShopRepositoryImpl) GetByShopId(shopId int, req *dto.InvoiceP
 	return invoices, totalRows, totalPages, nil
 }
 
-func (r *invoicePerShopRepositoryImpl) WithdrawFromInvoice(invoicePerShopIds []int, shopId int, walletId int) error {
+func (r *invoicePerShopRepositoryImpl) WithdrawFromInvoice(invoicePerShopIds []int, shopId int, walletId int, actorId int) error {
 	var invoicePerShops []model.InvoicePerShop
 
 	err := r.db.Transaction(func(trx *gorm.DB) error {
@@ -284,7 +336,7 @@ func (r *invoicePerShopRepositoryImpl) WithdrawFromInvoice(invoicePerShopIds []i
 			Where("shop_id = ?", shopId).
 			Where("status = ?", constant.TransactionStatusCompleted).
 			Where("is_released != ?", true).
-			Update("is_released", true)
+			Updates(map[string]interface{}{"is_released": true, "released_by_id": actorId})
 		if err := res.Error; err != nil {
 			return err
 		}
@@ -631,7 +683,7 @@ func (r *invoicePerShopRepositoryImpl) UpdateStatusToCanceled(orderId int, invoi
 		}
 
 		for _, transaction := range invoicePerShop.Transactions {
-			if err := r.skuRepo.IncreaseStock(tx, transaction.SkuID, transaction.Quantity); err != nil {
+			if err := r.skuRepo.IncreaseStock(tx, transaction.SkuID, transaction.Quantity, productModel.InventoryMovementTypeCancelRestock); err != nil {
 				return err
 			}
 		}
@@ -837,12 +889,15 @@ func (r *invoicePerShopRepositoryImpl) UpdateRefundStatus(tx *gorm.DB, shopId in
 	return nil
 }
 
-func (r *invoicePerShopRepositoryImpl) UpdateStatusCRONJob() error {
-	var invoiceStatuses []*model.InvoiceStatus
+func (r *invoicePerShopRepositoryImpl) UpdateStatusCRONJob() ([]int, error) {
+	var (
+		invoiceStatuses []*model.InvoiceStatus
+		delivered       []*model.InvoicePerShop
+	)
 	now := time.Now()
 
 	if err := r.db.Select("invoice_statuses.invoice_per_shop_id").Joins("JOIN invoice_per_shops ip ON ip.id = invoice_statuses.invoice_per_shop_id AND invoice_statuses.status = ?", constant.TransactionStatusOnDelivery).Where("ip.status = ?", constant.TransactionStatusOnDelivery).Find(&invoiceStatuses).Error; err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, is := range invoiceStatuses {
@@ -850,7 +905,7 @@ func (r *invoicePerShopRepositoryImpl) UpdateStatusCRONJob() error {
 	}
 
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(&model.InvoicePerShop{}).Where("status = ? AND arrival_date < ?", constant.TransactionStatusOnDelivery, now).Update("status", constant.TransactionStatusDelivered); err.Error != nil || err.RowsAffected == 0 {
+		if err := tx.Model(&delivered).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).Where("status = ? AND arrival_date < ?", constant.TransactionStatusOnDelivery, now).Update("status", constant.TransactionStatusDelivered); err.Error != nil || err.RowsAffected == 0 {
 			if errors.Is(err.Error, gorm.ErrRecordNotFound) {
 				return commonErr.ErrInvoiceNotFound
 			}
@@ -868,10 +923,35 @@ func (r *invoicePerShopRepositoryImpl) UpdateStatusCRONJob() error {
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	ids := make([]int, 0, len(delivered))
+	for _, invoice := range delivered {
+		ids = append(ids, invoice.ID)
+	}
+
+	return ids, nil
+}
+
+func (r *invoicePerShopRepositoryImpl) GetEmailDetails(ids []int) ([]*dto.InvoicePerShopEmailDetail, error) {
+	var details []*dto.InvoicePerShopEmailDetail
+
+	err := r.db.Table("invoice_per_shops ips").
+		Select(`ips.id, ips.invoice_id, ips.code, ips.total, ips.tracking_number,
+			buyer.id AS buyer_id, buyer.email AS buyer_email, buyer.username AS buyer_username,
+			s.name AS shop_name, seller.id AS seller_id, seller.email AS seller_email, seller.username AS seller_username`).
+		Joins("JOIN users buyer ON buyer.id = ips.user_id").
+		Joins("JOIN shops s ON s.id = ips.shop_id").
+		Joins("JOIN users seller ON seller.id = s.user_id").
+		Where("ips.id IN ?", ids).
+		Order("ips.id").
+		Scan(&details).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return details, nil
 }
 
 func (r *invoicePerShopRepositoryImpl) AutoReceivedCRONJob() error {
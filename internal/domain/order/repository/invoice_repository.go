@@ -4,15 +4,13 @@ import (
 	"errors"
 	"kedai/backend/be-kedai/internal/common/constant"
 	errs "kedai/backend/be-kedai/internal/common/error"
+	marketplaceModel "kedai/backend/be-kedai/internal/domain/marketplace/model"
 	"kedai/backend/be-kedai/internal/domain/order/model"
 	productModel "kedai/backend/be-kedai/internal/domain/product/model"
 	productRepo "kedai/backend/be-kedai/internal/domain/product/repository"
 	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
-	"kedai/backend/be-kedai/internal/domain/user/cache"
-	userDto "kedai/backend/be-kedai/internal/domain/user/dto"
 	userModel "kedai/backend/be-kedai/internal/domain/user/model"
 	userRepo "kedai/backend/be-kedai/internal/domain/user/repository"
-	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
 	"time"
 
 	"gorm.io/gorm"
@@ -22,7 +20,7 @@ type InvoiceRepository interface {
 	Create(invoice *model.Invoice) (*model.Invoice, error)
 	GetAlreadyCheckoutedWithin15Minute(userID, paymentMethodID int, totalPrice float64) (*int, error)
 	GetByIDAndUserID(id, userID int) (*model.Invoice, error)
-	Pay(invoice *model.Invoice, skuIds []int, invoiceStatuses []*model.InvoiceStatus, txnID, token string) (*userDto.Token, error)
+	Pay(invoice *model.Invoice, skuIds []int, invoiceStatuses []*model.InvoiceStatus, txnID string) error
 	Delete(invoice *model.Invoice) error
 	UpdateInvoice(tx *gorm.DB, invoice *model.Invoice) error
 	ClearUnusedInvoice() error
@@ -34,7 +32,6 @@ type invoiceRepositoryImpl struct {
 	skuRepo           productRepo.SkuRepository
 	userWalletRepo    userRepo.WalletRepository
 	invoiceStatusRepo InvoiceStatusRepository
-	redis             cache.UserCache
 }
 
 type InvoiceRConfig struct {
@@ -43,7 +40,6 @@ type InvoiceRConfig struct {
 	SkuRepo           productRepo.SkuRepository
 	UserWalletRepo    userRepo.WalletRepository
 	InvoiceStatusRepo InvoiceStatusRepository
-	Redis             cache.UserCache
 }
 
 func NewInvoiceRepository(config *InvoiceRConfig) InvoiceRepository {
@@ -53,7 +49,6 @@ func NewInvoiceRepository(config *InvoiceRConfig) InvoiceRepository {
 		skuRepo:           config.SkuRepo,
 		userWalletRepo:    config.UserWalletRepo,
 		invoiceStatusRepo: config.InvoiceStatusRepo,
-		redis:             config.Redis,
 	}
 }
 
@@ -63,14 +58,19 @@ func (r *invoiceRepositoryImpl) Create(invoice *model.Invoice) (*model.Invoice,
 
 	for _, shop := range invoice.InvoicePerShops {
 		for _, transaction := range shop.Transactions {
-			err := r.skuRepo.ReduceStock(tx, transaction.SkuID, transaction.Quantity)
+			err := r.skuRepo.ReduceStock(tx, transaction.SkuID, transaction.Quantity, productModel.InventoryMovementTypeSale)
 			if err != nil {
 				tx.Rollback()
 				return nil, err
 			}
 
 			if transaction.PromotedQuantity > 0 {
-				res := tx.Model(&productModel.ProductPromotion{}).Where("sku_id = ?", transaction.SkuID).Where("stock >= ?", transaction.PromotedQuantity).Update("stock", gorm.Expr("stock - ?", transaction.PromotedQuantity))
+				query := tx.Model(&productModel.ProductPromotion{}).Where("sku_id = ?", transaction.SkuID)
+				if transaction.FlashSaleProductID != nil {
+					query = tx.Model(&marketplaceModel.FlashSaleProduct{}).Where("id = ?", *transaction.FlashSaleProductID)
+				}
+
+				res := query.Where("stock >= ?", transaction.PromotedQuantity).Update("stock", gorm.Expr("stock - ?", transaction.PromotedQuantity))
 				if res.Error != nil {
 					tx.Rollback()
 					return nil, res.Error
@@ -124,7 +124,7 @@ func (r *invoiceRepositoryImpl) GetByIDAndUserID(id, userID int) (*model.Invoice
 	return &invoice, nil
 }
 
-func (r *invoiceRepositoryImpl) Pay(invoice *model.Invoice, skuIds []int, invoiceStatuses []*model.InvoiceStatus, txnID, token string) (*userDto.Token, error) {
+func (r *invoiceRepositoryImpl) Pay(invoice *model.Invoice, skuIds []int, invoiceStatuses []*model.InvoiceStatus, txnID string) error {
 	tx := r.db.Begin()
 	defer tx.Commit()
 
@@ -132,54 +132,29 @@ func (r *invoiceRepositoryImpl) Pay(invoice *model.Invoice, skuIds []int, invoic
 		err := r.userWalletRepo.DeductBalanceByUserID(tx, invoice.UserID, invoice.Total, txnID)
 		if err != nil {
 			tx.Rollback()
-			return nil, err
+			return err
 		}
 	}
 
 	err := tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(invoice).Error
 	if err != nil {
 		tx.Rollback()
-		return nil, err
+		return err
 	}
 
 	err = r.invoiceStatusRepo.Create(tx, invoiceStatuses)
 	if err != nil {
 		tx.Rollback()
-		return nil, err
+		return err
 	}
 
 	err = r.userCartItemRepo.DeleteCartItemBySkuIdsAndUserId(tx, skuIds, invoice.UserID)
 	if err != nil {
 		tx.Rollback()
-		return nil, err
-	}
-
-	err = r.redis.DeleteToken(token)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-
-	var (
-		user = &userModel.User{
-			ID: invoice.UserID,
-		}
-		defaultLevel = 0
-	)
-	accessToken, _ := jwttoken.GenerateAccessToken(user, defaultLevel)
-	refreshToken, _ := jwttoken.GenerateRefreshToken(user, defaultLevel)
-
-	newToken := &userDto.Token{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}
-
-	err = r.redis.StoreToken(invoice.UserID, accessToken, refreshToken)
-	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return newToken, nil
+	return nil
 }
 
 func (r *invoiceRepositoryImpl) Delete(invoice *model.Invoice) error {
@@ -189,13 +164,18 @@ func (r *invoiceRepositoryImpl) Delete(invoice *model.Invoice) error {
 	var shopVouchers []*userModel.UserVoucher
 	for _, invoicePerShop := range invoice.InvoicePerShops {
 		for _, transaction := range invoicePerShop.Transactions {
-			err := r.skuRepo.IncreaseStock(tx, transaction.SkuID, transaction.Quantity)
+			err := r.skuRepo.IncreaseStock(tx, transaction.SkuID, transaction.Quantity, productModel.InventoryMovementTypeCancelRestock)
 			if err != nil {
 				tx.Rollback()
 				return err
 			}
 
-			err = tx.Model(&productModel.ProductPromotion{}).Where("sku_id = ?", transaction.SkuID).Update("stock", gorm.Expr("stock + ?", transaction.PromotedQuantity)).Error
+			query := tx.Model(&productModel.ProductPromotion{}).Where("sku_id = ?", transaction.SkuID)
+			if transaction.FlashSaleProductID != nil {
+				query = tx.Model(&marketplaceModel.FlashSaleProduct{}).Where("id = ?", *transaction.FlashSaleProductID)
+			}
+
+			err = query.Update("stock", gorm.Expr("stock + ?", transaction.PromotedQuantity)).Error
 			if err != nil {
 				tx.Rollback()
 				return err
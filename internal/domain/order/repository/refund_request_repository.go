@@ -1,10 +1,12 @@
 package repository
 
 import (
+	"errors"
 	"kedai/backend/be-kedai/internal/common/constant"
 	commonErr "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/order/dto"
 	"kedai/backend/be-kedai/internal/domain/order/model"
+	productModel "kedai/backend/be-kedai/internal/domain/product/model"
 	productRepo "kedai/backend/be-kedai/internal/domain/product/repository"
 	walletModel "kedai/backend/be-kedai/internal/domain/user/model"
 	userRepo "kedai/backend/be-kedai/internal/domain/user/repository"
@@ -17,9 +19,11 @@ import (
 type RefundRequestRepository interface {
 	UpdateRefundStatus(tx *gorm.DB, invoiceId int, refundStatus string) error
 	PostComplain(tx *gorm.DB, ref *model.RefundRequest) error
-	ApproveRejectRefund(shopId int, invoiceId int, refundStatus string) error
+	ApproveRejectRefund(shopId int, actorId int, invoiceId int, refundStatus string) error
 	RefundAdmin(requestRefundId int) error
+	GetByID(id int) (*model.RefundRequest, error)
 	GetRefund(req *dto.GetRefundReq) ([]*dto.GetRefund, int, int, error)
+	HasOpenByUserID(userID int) (bool, error)
 }
 
 type refundRequestRepositoryImpl struct {
@@ -63,7 +67,7 @@ func (r *refundRequestRepositoryImpl) UpdateRefundStatus(tx *gorm.DB, invoiceId
 	return nil
 }
 
-func (r *refundRequestRepositoryImpl) ApproveRejectRefund(shopId int, invoiceId int, refundStatus string) error {
+func (r *refundRequestRepositoryImpl) ApproveRejectRefund(shopId int, actorId int, invoiceId int, refundStatus string) error {
 
 	tx := r.db.Begin()
 	defer tx.Commit()
@@ -89,6 +93,7 @@ func (r *refundRequestRepositoryImpl) ApproveRejectRefund(shopId int, invoiceId
 		invoiceStatuses = append(invoiceStatuses, &model.InvoiceStatus{
 			InvoicePerShopID: invoiceId,
 			Status:           constant.TransactionStatusRefundPending,
+			ActorID:          &actorId,
 		})
 	}
 
@@ -96,6 +101,7 @@ func (r *refundRequestRepositoryImpl) ApproveRejectRefund(shopId int, invoiceId
 		invoiceStatuses = append(invoiceStatuses, &model.InvoiceStatus{
 			InvoicePerShopID: invoiceId,
 			Status:           constant.TransactionStatusComplaintRejected,
+			ActorID:          &actorId,
 		})
 	}
 
@@ -182,7 +188,7 @@ func (r *refundRequestRepositoryImpl) RefundAdmin(requestRefundId int) error {
 	}
 
 	if refundRequests.RequestRefundType == constant.RefundTypeCancel {
-		err = r.productRepo.IncreaseStock(tx, refundRequests.SkuId, refundRequests.Quantity)
+		err = r.productRepo.IncreaseStock(tx, refundRequests.SkuId, refundRequests.Quantity, productModel.InventoryMovementTypeRefundRestock)
 		if err != nil {
 			tx.Rollback()
 			return err
@@ -244,6 +250,20 @@ func (r *refundRequestRepositoryImpl) RefundAdmin(requestRefundId int) error {
 	return nil
 }
 
+func (r *refundRequestRepositoryImpl) GetByID(id int) (*model.RefundRequest, error) {
+	var refundRequest model.RefundRequest
+
+	err := r.db.Preload("Invoice").First(&refundRequest, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commonErr.ErrRefundRequestNotFound
+		}
+		return nil, err
+	}
+
+	return &refundRequest, nil
+}
+
 func (r *refundRequestRepositoryImpl) GetRefund(req *dto.GetRefundReq) ([]*dto.GetRefund, int, int, error) {
 
 	var totalRows int64
@@ -285,3 +305,20 @@ func (r *refundRequestRepositoryImpl) GetRefund(req *dto.GetRefundReq) ([]*dto.G
 	return refundRequests, int(totalRows), totalPage, nil
 
 }
+
+// HasOpenByUserID reports whether a refund on one of the user's orders is
+// still waiting on the seller or on an admin.
+func (r *refundRequestRepositoryImpl) HasOpenByUserID(userID int) (bool, error) {
+	var count int64
+
+	err := r.db.Model(&model.RefundRequest{}).
+		Joins("JOIN invoice_per_shops ON invoice_per_shops.id = refund_requests.invoice_id").
+		Where("invoice_per_shops.user_id = ?", userID).
+		Where("refund_requests.status IN ?", []string{constant.RefundStatusPending, constant.RequestStatusSellerApproved}).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
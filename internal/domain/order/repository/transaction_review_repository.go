@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionReviewRepository interface {
@@ -16,6 +17,13 @@ type TransactionReviewRepository interface {
 	GetByTransactionID(transactionID int) (*model.TransactionReview, error)
 	GetReviews(req productDto.GetReviewRequest) ([]*model.TransactionReview, int64, int, error)
 	GetReviewStats(productCode string) (*productDto.GetReviewStatsResponse, error)
+	GetByID(reviewID int) (*model.TransactionReview, error)
+	GetByUserID(userID int) ([]*model.TransactionReview, error)
+	Update(review *model.TransactionReview) error
+	Vote(reviewID int, userID int) error
+	Unvote(reviewID int, userID int) error
+	Report(report *model.ReviewReport, hideThreshold int) error
+	Reply(reviewID int, shopID int, reply string) error
 }
 
 type transactionReviewRepositoryImpl struct {
@@ -35,14 +43,24 @@ func NewTransactionReviewRepository(config *TransactionReviewRConfig) Transactio
 func (r *transactionReviewRepositoryImpl) Create(transactionReview *model.TransactionReview) (*model.TransactionReview, error) {
 	transactionReview.ReviewDate = time.Now()
 
-	err := r.db.Create(transactionReview).Error
+	tx := r.db.Begin()
+	defer tx.Commit()
+
+	err := tx.Create(transactionReview).Error
 	if err != nil {
+		tx.Rollback()
 		if commonErr.IsDuplicateKeyError(err) {
 			return nil, commonErr.ErrTransactionReviewAlreadyExist
 		}
 		return nil, err
 	}
 
+	err = r.recomputeRatings(tx, transactionReview.TransactionId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	return transactionReview, nil
 }
 
@@ -59,6 +77,24 @@ func (r *transactionReviewRepositoryImpl) GetByTransactionID(transactionID int)
 	return &transactionReview, nil
 }
 
+// GetByUserID returns every review the user wrote, hidden ones included.
+func (r *transactionReviewRepositoryImpl) GetByUserID(userID int) ([]*model.TransactionReview, error) {
+	var reviews []*model.TransactionReview
+
+	err := r.db.
+		Preload("ReviewMedias").
+		Preload("Transaction.Sku.Product").
+		Joins("join transactions on transactions.id = transaction_reviews.transaction_id").
+		Where("transactions.user_id = ?", userID).
+		Order("transaction_reviews.review_date desc").
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
 func (r *transactionReviewRepositoryImpl) GetReviews(req productDto.GetReviewRequest) ([]*model.TransactionReview, int64, int, error) {
 	var (
 		transactionReviews []*model.TransactionReview
@@ -77,7 +113,8 @@ func (r *transactionReviewRepositoryImpl) GetReviews(req productDto.GetReviewReq
 		Joins("join skus on skus.id = transactions.sku_id").
 		Joins("join products on products.id = skus.product_id").
 		Where("products.is_active = ?", isActive).
-		Where("products.code = ?", req.ProductCode)
+		Where("products.code = ?", req.ProductCode).
+		Where("transaction_reviews.is_hidden = ?", false)
 
 	switch req.Filter {
 	case constant.FilterByOneStar:
@@ -103,6 +140,13 @@ func (r *transactionReviewRepositoryImpl) GetReviews(req productDto.GetReviewReq
 
 	totalPages = int(math.Ceil(float64(totalRows) / float64(req.Limit)))
 
+	switch req.Sort {
+	case constant.SortByMostHelpful:
+		query = query.Order("transaction_reviews.helpful_count desc, transaction_reviews.review_date desc")
+	default:
+		query = query.Order("transaction_reviews.review_date desc")
+	}
+
 	err = query.Limit(req.Limit).
 		Offset(req.Offset()).
 		Find(&transactionReviews).Error
@@ -143,3 +187,200 @@ func (r *transactionReviewRepositoryImpl) GetReviewStats(productCode string) (*p
 
 	return &reviewStats, nil
 }
+
+func (r *transactionReviewRepositoryImpl) GetByID(reviewID int) (*model.TransactionReview, error) {
+	var transactionReview model.TransactionReview
+	err := r.db.Preload("ReviewMedias").Preload("Transaction").First(&transactionReview, reviewID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, commonErr.ErrTransactionReviewNotFound
+		}
+		return nil, err
+	}
+
+	return &transactionReview, nil
+}
+
+func (r *transactionReviewRepositoryImpl) Update(review *model.TransactionReview) error {
+	now := time.Now()
+	review.EditedAt = &now
+
+	tx := r.db.Begin()
+	defer tx.Commit()
+
+	err := tx.Model(review).Select("description", "rating", "edited_at").Updates(review).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Unscoped().Where("review_id = ?", review.ID).Delete(&model.ReviewMedia{}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(review.ReviewMedias) > 0 {
+		for _, media := range review.ReviewMedias {
+			media.ReviewId = review.ID
+		}
+
+		err = tx.Create(review.ReviewMedias).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = r.recomputeRatings(tx, review.TransactionId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (r *transactionReviewRepositoryImpl) Vote(reviewID int, userID int) error {
+	tx := r.db.Begin()
+	defer tx.Commit()
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ReviewVote{ReviewID: reviewID, UserID: userID})
+	if res.Error != nil {
+		tx.Rollback()
+		if commonErr.IsForeignKeyError(res.Error) {
+			return commonErr.ErrTransactionReviewNotFound
+		}
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return commonErr.ErrReviewAlreadyVoted
+	}
+
+	err := tx.Model(&model.TransactionReview{}).Where("id = ?", reviewID).Update("helpful_count", gorm.Expr("helpful_count + 1")).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (r *transactionReviewRepositoryImpl) Unvote(reviewID int, userID int) error {
+	tx := r.db.Begin()
+	defer tx.Commit()
+
+	res := tx.Unscoped().Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&model.ReviewVote{})
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return commonErr.ErrReviewNotVoted
+	}
+
+	err := tx.Model(&model.TransactionReview{}).Where("id = ? AND helpful_count > 0", reviewID).Update("helpful_count", gorm.Expr("helpful_count - 1")).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (r *transactionReviewRepositoryImpl) Report(report *model.ReviewReport, hideThreshold int) error {
+	tx := r.db.Begin()
+	defer tx.Commit()
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if res.Error != nil {
+		tx.Rollback()
+		if commonErr.IsForeignKeyError(res.Error) {
+			return commonErr.ErrTransactionReviewNotFound
+		}
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return commonErr.ErrReviewAlreadyReported
+	}
+
+	var reportCount int64
+	err := tx.Model(&model.ReviewReport{}).Where("review_id = ?", report.ReviewID).Count(&reportCount).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if reportCount >= int64(hideThreshold) {
+		err = tx.Model(&model.TransactionReview{}).Where("id = ?", report.ReviewID).Update("is_hidden", true).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *transactionReviewRepositoryImpl) Reply(reviewID int, shopID int, reply string) error {
+	res := r.db.Model(&model.TransactionReview{}).
+		Where("id = ?", reviewID).
+		Where(`transaction_id IN (SELECT t.id FROM transactions t
+			JOIN skus s ON s.id = t.sku_id
+			JOIN products p ON p.id = s.product_id
+			WHERE p.shop_id = ?)`, shopID).
+		Updates(map[string]interface{}{
+			"seller_reply":      reply,
+			"seller_reply_date": time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return commonErr.ErrTransactionReviewNotFound
+	}
+
+	return nil
+}
+
+func (r *transactionReviewRepositoryImpl) recomputeRatings(tx *gorm.DB, transactionID int) error {
+	var target struct {
+		ProductID int
+		ShopID    int
+	}
+
+	err := tx.Table("transactions t").
+		Select("p.id AS product_id, p.shop_id").
+		Joins("JOIN skus s ON s.id = t.sku_id").
+		Joins("JOIN products p ON p.id = s.product_id").
+		Where("t.id = ?", transactionID).
+		Scan(&target).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Exec(`UPDATE products SET rating = COALESCE((
+		SELECT AVG(tr.rating) FROM transaction_reviews tr
+		JOIN transactions t ON t.id = tr.transaction_id
+		JOIN skus s ON s.id = t.sku_id
+		WHERE s.product_id = ? AND tr.deleted_at IS NULL), 0)
+	WHERE id = ?`, target.ProductID, target.ProductID).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`UPDATE shops SET rating = COALESCE((
+		SELECT AVG(tr.rating) FROM transaction_reviews tr
+		JOIN transactions t ON t.id = tr.transaction_id
+		JOIN skus s ON s.id = t.sku_id
+		JOIN products p ON p.id = s.product_id
+		WHERE p.shop_id = ? AND tr.deleted_at IS NULL), 0)
+	WHERE id = ?`, target.ShopID, target.ShopID).Error
+}
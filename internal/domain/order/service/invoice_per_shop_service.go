@@ -3,11 +3,16 @@ package service
 import (
 	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	commonErr "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
 	"kedai/backend/be-kedai/internal/domain/order/dto"
 	"kedai/backend/be-kedai/internal/domain/order/model"
 	"kedai/backend/be-kedai/internal/domain/order/repository"
 	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
 	userService "kedai/backend/be-kedai/internal/domain/user/service"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"log"
 	"strings"
 )
 
@@ -16,39 +21,47 @@ type InvoicePerShopService interface {
 	GetInvoicesByShopId(userId int, req *dto.InvoicePerShopFilterRequest) (*commonDto.PaginationResponse, error)
 	GetByID(id int) (*model.InvoicePerShop, error)
 	GetInvoicesByUserIDAndCode(userID int, code string) (*dto.InvoicePerShopDetail, error)
-	WithdrawFromInvoice(invoicePerShopIds []int, userId int) error
+	WithdrawFromInvoice(invoicePerShopIds []int, userId int, actorId int) error
 	GetInvoiceByUserIdAndId(userId int, id int) (*dto.InvoicePerShopDetail, error)
 	GetInvoiceByUserIdAndCode(userId int, code string) (*dto.InvoicePerShopDetail, error)
 	GetShopOrder(userId int, req *dto.InvoicePerShopFilterRequest) (*commonDto.PaginationResponse, error)
 	RefundRequest(invoiceCode string, userId int) (*model.RefundRequest, error)
-	UpdateStatusToProcessing(userId int, orderId int) error
-	UpdateStatusToDelivery(userId int, orderId int) error
-	UpdateStatusToRefundPendingSellerCancel(userId int, orderId int) error
+	UpdateStatusToProcessing(userId int, actorId int, orderId int) error
+	UpdateStatusToDelivery(userId int, actorId int, orderId int) error
+	UpdateStatusToRefundPendingSellerCancel(userId int, actorId int, orderId int) error
 	UpdateStatusToCanceled(orderId int) error
 	UpdateStatusToReceived(userId int, orderCode string) error
 	UpdateStatusToCompleted(userId int, orderCode string) error
 	UpdateStatusCRONJob() error
 	AutoReceivedCRONJob() error
 	AutoCompletedCRONJob() error
+	ExportAccountData(userId int) (map[string]interface{}, error)
+	CheckAccountDeletion(userId int) error
 }
 
 type invoicePerShopServiceImpl struct {
-	invoicePerShopRepo repository.InvoicePerShopRepository
-	shopService        shopService.ShopService
-	walletService      userService.WalletService
+	invoicePerShopRepo    repository.InvoicePerShopRepository
+	shopService           shopService.ShopService
+	walletService         userService.WalletService
+	notificationPublisher notificationService.NotificationPublisher
+	emailPublisher        notificationService.EmailPublisher
 }
 
 type InvoicePerShopSConfig struct {
-	InvoicePerShopRepo repository.InvoicePerShopRepository
-	ShopService        shopService.ShopService
-	WalletService      userService.WalletService
+	InvoicePerShopRepo    repository.InvoicePerShopRepository
+	ShopService           shopService.ShopService
+	WalletService         userService.WalletService
+	NotificationPublisher notificationService.NotificationPublisher
+	EmailPublisher        notificationService.EmailPublisher
 }
 
 func NewInvoicePerShopService(cfg *InvoicePerShopSConfig) InvoicePerShopService {
 	return &invoicePerShopServiceImpl{
-		invoicePerShopRepo: cfg.InvoicePerShopRepo,
-		shopService:        cfg.ShopService,
-		walletService:      cfg.WalletService,
+		invoicePerShopRepo:    cfg.InvoicePerShopRepo,
+		shopService:           cfg.ShopService,
+		walletService:         cfg.WalletService,
+		notificationPublisher: cfg.NotificationPublisher,
+		emailPublisher:        cfg.EmailPublisher,
 	}
 }
 
@@ -97,7 +110,9 @@ func (s *invoicePerShopServiceImpl) GetInvoicesByUserIDAndCode(userID int, code
 	return s.invoicePerShopRepo.GetByUserIDAndCode(userID, decoded)
 }
 
-func (s *invoicePerShopServiceImpl) WithdrawFromInvoice(invoicePerShopIds []int, userId int) error {
+// WithdrawFromInvoice records actorId, the shop member who released the
+// income, on the withdrawn invoices.
+func (s *invoicePerShopServiceImpl) WithdrawFromInvoice(invoicePerShopIds []int, userId int, actorId int) error {
 	shop, err := s.shopService.FindShopByUserId(userId)
 	if err != nil {
 		return err
@@ -108,7 +123,7 @@ func (s *invoicePerShopServiceImpl) WithdrawFromInvoice(invoicePerShopIds []int,
 		return err
 	}
 
-	return s.invoicePerShopRepo.WithdrawFromInvoice(invoicePerShopIds, shop.ID, wallet.ID)
+	return s.invoicePerShopRepo.WithdrawFromInvoice(invoicePerShopIds, shop.ID, wallet.ID, actorId)
 }
 
 func (s *invoicePerShopServiceImpl) GetInvoiceByUserIdAndId(userId int, id int) (*dto.InvoicePerShopDetail, error) {
@@ -179,7 +194,9 @@ func (s *invoicePerShopServiceImpl) RefundRequest(invoiceCode string, userId int
 	return result, nil
 }
 
-func (s *invoicePerShopServiceImpl) UpdateStatusToProcessing(userId int, orderId int) error {
+// UpdateStatusToProcessing records actorId, the shop member who accepted the
+// order, on the new status.
+func (s *invoicePerShopServiceImpl) UpdateStatusToProcessing(userId int, actorId int, orderId int) error {
 	shop, err := s.shopService.FindShopByUserId(userId)
 	if err != nil {
 		return err
@@ -191,6 +208,7 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToProcessing(userId int, orderId
 	invoiceStatuses = append(invoiceStatuses, &model.InvoiceStatus{
 		InvoicePerShopID: orderId,
 		Status:           status,
+		ActorID:          &actorId,
 	})
 
 	err = s.invoicePerShopRepo.UpdateStatusToProcessing(shop.ID, orderId, invoiceStatuses)
@@ -198,10 +216,14 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToProcessing(userId int, orderId
 		return err
 	}
 
+	s.notifyBuyer(orderId, status)
+
 	return nil
 }
 
-func (s *invoicePerShopServiceImpl) UpdateStatusToDelivery(userId int, orderId int) error {
+// UpdateStatusToDelivery records actorId, the shop member who shipped the
+// order, on the new status.
+func (s *invoicePerShopServiceImpl) UpdateStatusToDelivery(userId int, actorId int, orderId int) error {
 	shop, err := s.shopService.FindShopByUserId(userId)
 	if err != nil {
 		return err
@@ -213,6 +235,7 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToDelivery(userId int, orderId i
 	invoiceStatuses = append(invoiceStatuses, &model.InvoiceStatus{
 		InvoicePerShopID: orderId,
 		Status:           status,
+		ActorID:          &actorId,
 	})
 
 	err = s.invoicePerShopRepo.UpdateStatusToDelivery(shop.ID, orderId, invoiceStatuses)
@@ -220,6 +243,9 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToDelivery(userId int, orderId i
 		return err
 	}
 
+	s.notifyBuyer(orderId, status)
+	emailBuyers(s.emailPublisher, s.invoicePerShopRepo, []int{orderId}, mail.TemplateOrderShipped)
+
 	return nil
 }
 
@@ -238,6 +264,8 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToCanceled(orderId int) error {
 		return err
 	}
 
+	s.notifyBuyer(orderId, status)
+
 	return nil
 }
 
@@ -287,7 +315,9 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToCompleted(userId int, orderCod
 	return nil
 }
 
-func (s *invoicePerShopServiceImpl) UpdateStatusToRefundPendingSellerCancel(userId int, orderId int) error {
+// UpdateStatusToRefundPendingSellerCancel records actorId, the shop member
+// who cancelled the order, on the new status.
+func (s *invoicePerShopServiceImpl) UpdateStatusToRefundPendingSellerCancel(userId int, actorId int, orderId int) error {
 	shop, err := s.shopService.FindShopByUserId(userId)
 	if err != nil {
 		return err
@@ -298,6 +328,7 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToRefundPendingSellerCancel(user
 	invoiceStatuses = append(invoiceStatuses, &model.InvoiceStatus{
 		InvoicePerShopID: orderId,
 		Status:           constant.TransactionStatusRefundPending,
+		ActorID:          &actorId,
 	})
 
 	err = s.invoicePerShopRepo.UpdateStatusToRefundPending(shop.ID, orderId, invoiceStatuses, constant.RefundTypeCancel)
@@ -305,11 +336,47 @@ func (s *invoicePerShopServiceImpl) UpdateStatusToRefundPendingSellerCancel(user
 		return err
 	}
 
+	s.notifyBuyer(orderId, constant.TransactionStatusRefundPending)
+
 	return nil
 }
 
+// notifyBuyer tells the buyer a seller or admin moved their order to status.
+// Failures are only logged since the status change already went through.
+func (s *invoicePerShopServiceImpl) notifyBuyer(orderId int, status string) {
+	if s.notificationPublisher == nil {
+		return
+	}
+
+	order, err := s.invoicePerShopRepo.GetByID(orderId)
+	if err != nil {
+		log.Println("failed to get order", orderId, "for status notification:", err)
+		return
+	}
+
+	err = s.notificationPublisher.Publish(&notificationDto.NotificationEvent{
+		UserID: order.UserID,
+		Type:   constant.NotificationTypeOrderStatus,
+		Data: map[string]interface{}{
+			"invoiceId": order.ID,
+			"code":      order.Code,
+			"status":    status,
+		},
+	})
+	if err != nil {
+		log.Println("failed to send status notification for order", orderId, ":", err)
+	}
+}
+
 func (s *invoicePerShopServiceImpl) UpdateStatusCRONJob() error {
-	return s.invoicePerShopRepo.UpdateStatusCRONJob()
+	deliveredIds, err := s.invoicePerShopRepo.UpdateStatusCRONJob()
+	if err != nil {
+		return err
+	}
+
+	emailBuyers(s.emailPublisher, s.invoicePerShopRepo, deliveredIds, mail.TemplateOrderDelivered)
+
+	return nil
 }
 
 func (s *invoicePerShopServiceImpl) AutoReceivedCRONJob() error {
@@ -319,3 +386,27 @@ func (s *invoicePerShopServiceImpl) AutoReceivedCRONJob() error {
 func (s *invoicePerShopServiceImpl) AutoCompletedCRONJob() error {
 	return s.invoicePerShopRepo.AutoCompletedCRONJob()
 }
+
+// ExportAccountData adds the user's orders to their data export.
+func (s *invoicePerShopServiceImpl) ExportAccountData(userId int) (map[string]interface{}, error) {
+	orders, err := s.invoicePerShopRepo.GetAllByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"orders": orders}, nil
+}
+
+// CheckAccountDeletion keeps the account open until all its orders are
+// settled.
+func (s *invoicePerShopServiceImpl) CheckAccountDeletion(userId int) error {
+	open, err := s.invoicePerShopRepo.HasOpenByUserID(userId)
+	if err != nil {
+		return err
+	}
+	if open {
+		return commonErr.ErrAccountHasOpenOrders
+	}
+
+	return nil
+}
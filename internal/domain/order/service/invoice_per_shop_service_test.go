@@ -4,6 +4,7 @@ import (
 	"errors"
 	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
 	"kedai/backend/be-kedai/internal/domain/order/dto"
 
 	"kedai/backend/be-kedai/internal/domain/order/model"
@@ -13,10 +14,12 @@ import (
 
 	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
 	walletModel "kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/utils/mail"
 
 	commonErr "kedai/backend/be-kedai/internal/common/error"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_InvoicePerShopGetByID(t *testing.T) {
@@ -443,7 +446,7 @@ func TestWithdrawFromInvoice(t *testing.T) {
 				beforeTest: func(ipsr *mocks.InvoicePerShopRepository, ss *mocks.ShopService, ws *mocks.WalletService) {
 					ss.On("FindShopByUserId", 1).Return(&shopModel.Shop{ID: 1}, nil)
 					ws.On("GetWalletByUserID", 1).Return(&walletModel.Wallet{ID: 1}, nil)
-					ipsr.On("WithdrawFromInvoice", []int{1}, 1, 1).Return(errors.New("failed to get invoice"))
+					ipsr.On("WithdrawFromInvoice", []int{1}, 1, 1, 2).Return(errors.New("failed to get invoice"))
 				},
 			},
 			expected: expected{
@@ -465,7 +468,7 @@ func TestWithdrawFromInvoice(t *testing.T) {
 				WalletService:      walletService,
 			})
 
-			err := invoicePerShopService.WithdrawFromInvoice(tc.input.id, tc.input.userID)
+			err := invoicePerShopService.WithdrawFromInvoice(tc.input.id, tc.input.userID, 2)
 
 			assert.Equal(t, tc.expected.err, err)
 		})
@@ -572,10 +575,12 @@ func TestUpdateStatusToDelivery(t *testing.T) {
 		shop = &shopModel.Shop{
 			ID: 1,
 		}
+		actorId         = 2
 		invoiceStatuses = []*model.InvoiceStatus{
 			{
 				InvoicePerShopID: 1,
 				Status:           "ON_DELIVERY",
+				ActorID:          &actorId,
 			},
 		}
 		userId  = 1
@@ -647,7 +652,7 @@ func TestUpdateStatusToDelivery(t *testing.T) {
 				ShopService:        shopService,
 			})
 
-			err := invoicePerShopService.UpdateStatusToDelivery(tc.userId, tc.orderId)
+			err := invoicePerShopService.UpdateStatusToDelivery(tc.userId, actorId, tc.orderId)
 
 			assert.Equal(t, tc.expected.err, err)
 		})
@@ -659,10 +664,12 @@ func TestUpdateStatusToProcessing(t *testing.T) {
 		shop = &shopModel.Shop{
 			ID: 1,
 		}
+		actorId         = 2
 		invoiceStatuses = []*model.InvoiceStatus{
 			{
 				InvoicePerShopID: 1,
 				Status:           constant.TransactionStatusProcessing,
+				ActorID:          &actorId,
 			},
 		}
 		userId  = 1
@@ -734,7 +741,7 @@ func TestUpdateStatusToProcessing(t *testing.T) {
 				ShopService:        shopService,
 			})
 
-			err := invoicePerShopService.UpdateStatusToProcessing(tc.userId, tc.orderId)
+			err := invoicePerShopService.UpdateStatusToProcessing(tc.userId, actorId, tc.orderId)
 
 			assert.Equal(t, tc.expected.err, err)
 		})
@@ -812,11 +819,13 @@ func TestUpdateStatusToCanceled(t *testing.T) {
 
 func TestUpdateStatusToRefundPendingSellerCancel(t *testing.T) {
 	var (
+		actorId         = 2
 		shop            = &shopModel.Shop{ID: 1}
 		invoiceStatuses = []*model.InvoiceStatus{
 			{
 				InvoicePerShopID: 1,
 				Status:           constant.TransactionStatusRefundPending,
+				ActorID:          &actorId,
 			},
 		}
 		userId  = 1
@@ -888,7 +897,7 @@ func TestUpdateStatusToRefundPendingSellerCancel(t *testing.T) {
 				ShopService:        shopService,
 			})
 
-			err := invoicePerShopService.UpdateStatusToRefundPendingSellerCancel(tc.userId, tc.orderId)
+			err := invoicePerShopService.UpdateStatusToRefundPendingSellerCancel(tc.userId, actorId, tc.orderId)
 
 			assert.Equal(t, tc.expected.err, err)
 		})
@@ -1167,7 +1176,7 @@ func TestRefundRequest(t *testing.T) {
 func TestUpdateStatusCRONJob(t *testing.T) {
 	t.Run("should return number of calls when called", func(t *testing.T) {
 		mockRepo := new(mocks.InvoicePerShopRepository)
-		mockRepo.On("UpdateStatusCRONJob").Return(nil)
+		mockRepo.On("UpdateStatusCRONJob").Return(nil, nil)
 		service := service.NewInvoicePerShopService(&service.InvoicePerShopSConfig{
 			InvoicePerShopRepo: mockRepo,
 		})
@@ -1176,6 +1185,38 @@ func TestUpdateStatusCRONJob(t *testing.T) {
 
 		mockRepo.AssertNumberOfCalls(t, "UpdateStatusCRONJob", 1)
 	})
+
+	t.Run("should email buyers of delivered orders", func(t *testing.T) {
+		mockRepo := mocks.NewInvoicePerShopRepository(t)
+		mockEmailPublisher := mocks.NewEmailPublisher(t)
+		mockRepo.On("UpdateStatusCRONJob").Return([]int{1, 2}, nil)
+		mockRepo.On("GetEmailDetails", []int{1, 2}).Return([]*dto.InvoicePerShopEmailDetail{
+			{ID: 1, Code: "INV/1", BuyerID: 9, BuyerEmail: "buyer@mail.com", BuyerUsername: "buyer", ShopName: "shop"},
+			{ID: 2, Code: "INV/2", BuyerID: 10, BuyerEmail: "other@mail.com", BuyerUsername: "other", ShopName: "shop"},
+		}, nil)
+		mockEmailPublisher.On("Enqueue", &notificationDto.EmailRequest{
+			To:       "buyer@mail.com",
+			UserID:   9,
+			Category: constant.NotificationCategoryOrders,
+			Template: mail.TemplateOrderDelivered,
+			Data:     &mail.OrderEmailData{Username: "buyer", ShopName: "shop", Code: "INV/1"},
+		}).Return(nil)
+		mockEmailPublisher.On("Enqueue", &notificationDto.EmailRequest{
+			To:       "other@mail.com",
+			UserID:   10,
+			Category: constant.NotificationCategoryOrders,
+			Template: mail.TemplateOrderDelivered,
+			Data:     &mail.OrderEmailData{Username: "other", ShopName: "shop", Code: "INV/2"},
+		}).Return(errors.New("error"))
+		service := service.NewInvoicePerShopService(&service.InvoicePerShopSConfig{
+			InvoicePerShopRepo: mockRepo,
+			EmailPublisher:     mockEmailPublisher,
+		})
+
+		err := service.UpdateStatusCRONJob()
+
+		assert.NoError(t, err)
+	})
 }
 
 func TestAutoReceivedCRONJob(t *testing.T) {
@@ -1205,3 +1246,55 @@ func TestAutoCompletedCRONJob(t *testing.T) {
 		mockRepo.AssertNumberOfCalls(t, "AutoCompletedCRONJob", 1)
 	})
 }
+
+func TestUpdateStatusNotifiesBuyer(t *testing.T) {
+	var (
+		shop    = &shopModel.Shop{ID: 1}
+		userId  = 1
+		orderId = 5
+		order   = &model.InvoicePerShop{ID: orderId, Code: "INV/1", UserID: 9}
+	)
+
+	t.Run("should publish order status to the buyer", func(t *testing.T) {
+		invoicePerShopRepo := mocks.NewInvoicePerShopRepository(t)
+		shopService := mocks.NewShopService(t)
+		publisher := mocks.NewNotificationPublisher(t)
+		shopService.On("FindShopByUserId", userId).Return(shop, nil)
+		invoicePerShopRepo.On("UpdateStatusToDelivery", shop.ID, orderId, mock.Anything).Return(nil)
+		invoicePerShopRepo.On("GetByID", orderId).Return(order, nil)
+		publisher.On("Publish", &notificationDto.NotificationEvent{
+			UserID: order.UserID,
+			Type:   constant.NotificationTypeOrderStatus,
+			Data: map[string]interface{}{
+				"invoiceId": order.ID,
+				"code":      order.Code,
+				"status":    constant.TransactionStatusOnDelivery,
+			},
+		}).Return(nil)
+		invoicePerShopService := service.NewInvoicePerShopService(&service.InvoicePerShopSConfig{
+			InvoicePerShopRepo:    invoicePerShopRepo,
+			ShopService:           shopService,
+			NotificationPublisher: publisher,
+		})
+
+		err := invoicePerShopService.UpdateStatusToDelivery(userId, 2, orderId)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not fail the status change when notification fails", func(t *testing.T) {
+		invoicePerShopRepo := mocks.NewInvoicePerShopRepository(t)
+		publisher := mocks.NewNotificationPublisher(t)
+		invoicePerShopRepo.On("UpdateStatusToCanceled", orderId, mock.Anything).Return(nil)
+		invoicePerShopRepo.On("GetByID", orderId).Return(order, nil)
+		publisher.On("Publish", mock.Anything).Return(errors.New("error"))
+		invoicePerShopService := service.NewInvoicePerShopService(&service.InvoicePerShopSConfig{
+			InvoicePerShopRepo:    invoicePerShopRepo,
+			NotificationPublisher: publisher,
+		})
+
+		err := invoicePerShopService.UpdateStatusToCanceled(orderId)
+
+		assert.NoError(t, err)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	locationService "kedai/backend/be-kedai/internal/domain/location/service"
 	marketplaceModel "kedai/backend/be-kedai/internal/domain/marketplace/model"
 	marketplaceService "kedai/backend/be-kedai/internal/domain/marketplace/service"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
 	"kedai/backend/be-kedai/internal/domain/order/dto"
 	"kedai/backend/be-kedai/internal/domain/order/model"
 	"kedai/backend/be-kedai/internal/domain/order/repository"
@@ -15,53 +16,67 @@ import (
 	userDto "kedai/backend/be-kedai/internal/domain/user/dto"
 	userModel "kedai/backend/be-kedai/internal/domain/user/model"
 	userService "kedai/backend/be-kedai/internal/domain/user/service"
+	"kedai/backend/be-kedai/internal/utils/mail"
 	"kedai/backend/be-kedai/internal/utils/random"
+	"log"
 	"strconv"
 	"time"
 )
 
 type InvoiceService interface {
 	Checkout(req dto.CheckoutRequest) (*dto.CheckoutResponse, error)
-	PayInvoice(req dto.PayInvoiceRequest, token string) (*userDto.Token, error)
+	PayInvoice(req dto.PayInvoiceRequest) (*userDto.Token, error)
 	CancelCheckout(req dto.CancelCheckoutRequest) error
 	ClearUnusedInvoice() error
 }
 
 type invoiceServiceImpl struct {
 	invoiceRepo               repository.InvoiceRepository
+	invoicePerShopRepo        repository.InvoicePerShopRepository
 	addressService            locationService.AddressService
 	shopService               shopService.ShopService
 	shopVoucherService        shopService.ShopVoucherService
 	cartItemService           userService.UserCartItemService
 	shopCourierService        shopService.CourierService
 	marketplaceVoucherService marketplaceService.MarketplaceVoucherService
+	flashSaleService          marketplaceService.FlashSaleService
 	sealabsPayService         userService.SealabsPayService
 	walletService             userService.WalletService
+	emailPublisher            notificationService.EmailPublisher
+	userService               userService.UserService
 }
 
 type InvoiceSConfig struct {
 	InvoiceRepo               repository.InvoiceRepository
+	InvoicePerShopRepo        repository.InvoicePerShopRepository
 	AddressService            locationService.AddressService
 	ShopService               shopService.ShopService
 	ShopVoucherService        shopService.ShopVoucherService
 	CartItemService           userService.UserCartItemService
 	ShopCourierService        shopService.CourierService
 	MarketplaceVoucherService marketplaceService.MarketplaceVoucherService
+	FlashSaleService          marketplaceService.FlashSaleService
 	SealabsPayService         userService.SealabsPayService
 	WalletService             userService.WalletService
+	EmailPublisher            notificationService.EmailPublisher
+	UserService               userService.UserService
 }
 
 func NewInvoiceService(cfg *InvoiceSConfig) InvoiceService {
 	return &invoiceServiceImpl{
 		invoiceRepo:               cfg.InvoiceRepo,
+		invoicePerShopRepo:        cfg.InvoicePerShopRepo,
 		addressService:            cfg.AddressService,
 		shopService:               cfg.ShopService,
 		shopVoucherService:        cfg.ShopVoucherService,
 		cartItemService:           cfg.CartItemService,
 		shopCourierService:        cfg.ShopCourierService,
 		marketplaceVoucherService: cfg.MarketplaceVoucherService,
+		flashSaleService:          cfg.FlashSaleService,
 		sealabsPayService:         cfg.SealabsPayService,
 		walletService:             cfg.WalletService,
+		emailPublisher:            cfg.EmailPublisher,
+		userService:               cfg.UserService,
 	}
 }
 
@@ -139,26 +154,38 @@ func (s *invoiceServiceImpl) Checkout(req dto.CheckoutRequest) (*dto.CheckoutRes
 				price = cartItem.Sku.Product.Bulk.Price
 			}
 
+			flashSale, err := s.flashSaleService.GetActiveBySkuID(cartItem.SkuId)
+			if err != nil {
+				return nil, err
+			}
+
 			var (
-				totalPrice    float64
-				totalPromoted int
-				basePrice     = price
+				totalPrice         float64
+				totalPromoted      int
+				basePrice          = price
+				promotion          = cartItem.Sku.Promotion
+				flashSaleProductID *int
 			)
-			if cartItem.Sku.Promotion != nil {
+			if flashSale != nil {
+				promotion = flashSale.ToProductPromotion()
+				flashSaleProductID = &flashSale.ID
+			}
+
+			if promotion != nil {
 				totalPromoted = product.Quantity
-				switch cartItem.Sku.Promotion.Type {
+				switch promotion.Type {
 				case shopModel.PromotionTypePercent:
-					price = price - (price * cartItem.Sku.Promotion.Amount)
+					price = price - (price * promotion.Amount)
 				case shopModel.PromotionTypeNominal:
-					price = price - cartItem.Sku.Promotion.Amount
+					price = price - promotion.Amount
 				}
-				if product.Quantity > cartItem.Sku.Promotion.PurchaseLimit || product.Quantity > cartItem.Sku.Promotion.Stock {
-					if cartItem.Sku.Promotion.PurchaseLimit < cartItem.Sku.Promotion.Stock {
-						totalPromoted = cartItem.Sku.Promotion.PurchaseLimit
-						totalPrice = basePrice*float64(product.Quantity-cartItem.Sku.Promotion.PurchaseLimit) + price*float64(cartItem.Sku.Promotion.PurchaseLimit)
+				if product.Quantity > promotion.PurchaseLimit || product.Quantity > promotion.Stock {
+					if promotion.PurchaseLimit < promotion.Stock {
+						totalPromoted = promotion.PurchaseLimit
+						totalPrice = basePrice*float64(product.Quantity-promotion.PurchaseLimit) + price*float64(promotion.PurchaseLimit)
 					} else {
-						totalPromoted = cartItem.Sku.Promotion.Stock
-						totalPrice = basePrice*float64(product.Quantity-cartItem.Sku.Promotion.Stock) + price*float64(cartItem.Sku.Promotion.Stock)
+						totalPromoted = promotion.Stock
+						totalPrice = basePrice*float64(product.Quantity-promotion.Stock) + price*float64(promotion.Stock)
 					}
 				} else {
 					totalPrice = price * float64(product.Quantity)
@@ -170,16 +197,17 @@ func (s *invoiceServiceImpl) Checkout(req dto.CheckoutRequest) (*dto.CheckoutRes
 			transactions = append(transactions, &model.Transaction{
 				SkuID: cartItem.SkuId,
 				Price: func() float64 {
-					if cartItem.Sku.Promotion != nil && (product.Quantity > cartItem.Sku.Promotion.PurchaseLimit || product.Quantity > cartItem.Sku.Promotion.Stock) {
+					if promotion != nil && (product.Quantity > promotion.PurchaseLimit || product.Quantity > promotion.Stock) {
 						return basePrice
 					}
 					return price
 				}(),
-				Quantity:         product.Quantity,
-				PromotedQuantity: totalPromoted,
-				TotalPrice:       totalPrice,
-				Note:             &cartItem.Notes,
-				UserID:           req.UserID,
+				Quantity:           product.Quantity,
+				PromotedQuantity:   totalPromoted,
+				FlashSaleProductID: flashSaleProductID,
+				TotalPrice:         totalPrice,
+				Note:               &cartItem.Notes,
+				UserID:             req.UserID,
 				Variants: func() []model.TransactionVariant {
 					var variants []model.TransactionVariant
 					for _, variant := range cartItem.Sku.Variants {
@@ -345,7 +373,9 @@ func (s *invoiceServiceImpl) Checkout(req dto.CheckoutRequest) (*dto.CheckoutRes
 	}, nil
 }
 
-func (s *invoiceServiceImpl) PayInvoice(req dto.PayInvoiceRequest, token string) (*userDto.Token, error) {
+// PayInvoice pays the invoice and rotates the refresh token of the session
+// back to the default access level, so a wallet step up only pays once.
+func (s *invoiceServiceImpl) PayInvoice(req dto.PayInvoiceRequest) (*userDto.Token, error) {
 	invoice, err := s.invoiceRepo.GetByIDAndUserID(req.InvoiceID, req.UserID)
 	if err != nil {
 		return nil, err
@@ -384,6 +414,7 @@ func (s *invoiceServiceImpl) PayInvoice(req dto.PayInvoiceRequest, token string)
 
 	var (
 		skuIds          []int
+		orderIds        []int
 		invoiceStatuses []*model.InvoiceStatus
 	)
 	for _, shopInvoice := range invoice.InvoicePerShops {
@@ -404,12 +435,22 @@ func (s *invoiceServiceImpl) PayInvoice(req dto.PayInvoiceRequest, token string)
 		for _, transaction := range shopInvoice.Transactions {
 			skuIds = append(skuIds, transaction.SkuID)
 		}
+
+		orderIds = append(orderIds, shopInvoice.ID)
 	}
 
 	now := time.Now()
 	invoice.PaymentDate = &now
 
-	newToken, err := s.invoiceRepo.Pay(invoice, skuIds, invoiceStatuses, req.TxnID, token)
+	err = s.invoiceRepo.Pay(invoice, skuIds, invoiceStatuses, req.TxnID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.emailPaidInvoice(invoice, orderIds)
+
+	defaultLevel := 0
+	newToken, err := s.userService.RotateToken(req.UserID, req.SessionKey, req.RefreshToken, defaultLevel)
 	if err != nil {
 		return nil, err
 	}
@@ -417,6 +458,35 @@ func (s *invoiceServiceImpl) PayInvoice(req dto.PayInvoiceRequest, token string)
 	return newToken, nil
 }
 
+// emailPaidInvoice sends the buyer an order confirmation covering every shop
+// in invoice and tells each seller about their new order.
+func (s *invoiceServiceImpl) emailPaidInvoice(invoice *model.Invoice, orderIds []int) {
+	if s.emailPublisher == nil || len(orderIds) == 0 {
+		return
+	}
+
+	details, err := s.invoicePerShopRepo.GetEmailDetails(orderIds)
+	if err != nil {
+		log.Println("failed to get orders of invoice", invoice.ID, "for confirmation email:", err)
+		return
+	}
+	if len(details) == 0 {
+		return
+	}
+
+	confirmation := &mail.OrderConfirmationEmailData{
+		Username:    details[0].BuyerUsername,
+		InvoiceCode: invoice.Code,
+		Total:       invoice.Total,
+	}
+	for _, detail := range details {
+		confirmation.Orders = append(confirmation.Orders, detail.BuyerEmailData())
+		enqueueEmail(s.emailPublisher, detail.SellerID, detail.SellerEmail, mail.TemplateSellerNewOrder, detail.SellerEmailData())
+	}
+
+	enqueueEmail(s.emailPublisher, details[0].BuyerID, details[0].BuyerEmail, mail.TemplateOrderConfirmation, confirmation)
+}
+
 func (s *invoiceServiceImpl) CancelCheckout(req dto.CancelCheckoutRequest) error {
 	invoice, err := s.invoiceRepo.GetByIDAndUserID(req.InvoiceID, req.UserID)
 	if err != nil {
@@ -1,10 +1,12 @@
 package service_test
 
 import (
+	"errors"
 	"kedai/backend/be-kedai/internal/common/constant"
 	errs "kedai/backend/be-kedai/internal/common/error"
 	locationModel "kedai/backend/be-kedai/internal/domain/location/model"
 	marketplaceModel "kedai/backend/be-kedai/internal/domain/marketplace/model"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
 	"kedai/backend/be-kedai/internal/domain/order/dto"
 	"kedai/backend/be-kedai/internal/domain/order/model"
 	"kedai/backend/be-kedai/internal/domain/order/service"
@@ -12,6 +14,7 @@ import (
 	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
 	userDto "kedai/backend/be-kedai/internal/domain/user/dto"
 	userModel "kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/utils/mail"
 	"kedai/backend/be-kedai/mocks"
 	"testing"
 
@@ -52,6 +55,7 @@ func TestCheckout(t *testing.T) {
 		req        dto.CheckoutRequest
 		want       *dto.CheckoutResponse
 		wantErr    error
+		flashSale  *marketplaceModel.FlashSaleProduct
 		beforeTest func(*mocks.AddressService, *mocks.MarketplaceVoucherService, *mocks.ShopService, *mocks.ShopVoucherService, *mocks.UserCartItemService, *mocks.CourierService, *mocks.InvoiceRepository)
 	}{
 		{
@@ -458,6 +462,59 @@ func TestCheckout(t *testing.T) {
 				invoiceRepo.On("Create", mock.Anything).Return(nil, errs.ErrInternalServerError)
 			},
 		},
+		{
+			name: "should apply flash sale price when sku is in an active flash sale slot",
+			req: dto.CheckoutRequest{
+				AddressID:       req.AddressID,
+				TotalPrice:      3000,
+				UserID:          req.UserID,
+				PaymentMethodID: req.PaymentMethodID,
+				Items: []dto.CheckoutItem{
+					{
+						ShopID:           1,
+						CourierServiceID: 1,
+						ShippingCost:     1000,
+						Products:         products,
+					},
+				},
+			},
+			want: &dto.CheckoutResponse{
+				ID: 1,
+			},
+			wantErr: nil,
+			flashSale: &marketplaceModel.FlashSaleProduct{
+				ID:            1,
+				Type:          shopModel.PromotionTypePercent,
+				Amount:        0.5,
+				Stock:         10,
+				PurchaseLimit: 1,
+			},
+			beforeTest: func(AddressService *mocks.AddressService, marketplaceVoucherService *mocks.MarketplaceVoucherService, shopService *mocks.ShopService, shopVoucherService *mocks.ShopVoucherService, cartItemService *mocks.UserCartItemService, courierService *mocks.CourierService, invoiceRepo *mocks.InvoiceRepository) {
+				invoiceRepo.On("GetAlreadyCheckoutedWithin15Minute", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				AddressService.On("GetUserAddressByIdAndUserId", req.AddressID, req.UserID).Return(&locationModel.UserAddress{}, nil)
+				shopService.On("FindShopById", mock.Anything).Return(&shopModel.Shop{}, nil).Once()
+				courierService.On("GetCourierByServiceIDAndShopID", mock.Anything, mock.Anything).Return(&shopModel.Courier{}, nil).Once()
+				cartItemService.On("GetCartItemByIdAndUserId", mock.Anything, mock.Anything).Return(&userModel.CartItem{
+					Quantity: 1,
+					Sku: productModel.Sku{
+						Stock: 1,
+						Price: 4000,
+						Product: &productModel.Product{
+							Bulk: &productModel.ProductBulkPrice{
+								MinQuantity: 1,
+								Price:       4000,
+							},
+						},
+					},
+				}, nil).Once()
+				invoiceRepo.On("Create", mock.MatchedBy(func(invoice *model.Invoice) bool {
+					transaction := invoice.InvoicePerShops[0].Transactions[0]
+					return transaction.FlashSaleProductID != nil && *transaction.FlashSaleProductID == 1 && transaction.PromotedQuantity == 1
+				})).Return(&model.Invoice{
+					ID: 1,
+				}, nil)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -469,6 +526,8 @@ func TestCheckout(t *testing.T) {
 			mockCartItemService := new(mocks.UserCartItemService)
 			mockCourierService := new(mocks.CourierService)
 			mockInvoiceRepo := new(mocks.InvoiceRepository)
+			mockFlashSaleService := new(mocks.FlashSaleService)
+			mockFlashSaleService.On("GetActiveBySkuID", mock.Anything).Return(test.flashSale, nil)
 
 			test.beforeTest(mockAddressService, mockMarketplaceVoucherService, mockShopService, mockShopVoucherService, mockCartItemService, mockCourierService, mockInvoiceRepo)
 
@@ -480,6 +539,7 @@ func TestCheckout(t *testing.T) {
 				CartItemService:           mockCartItemService,
 				ShopCourierService:        mockCourierService,
 				MarketplaceVoucherService: mockMarketplaceVoucherService,
+				FlashSaleService:          mockFlashSaleService,
 			})
 
 			got, err := service.Checkout(test.req)
@@ -497,6 +557,8 @@ func TestPayInvoice(t *testing.T) {
 			InvoiceID:       1,
 			UserID:          1,
 			PaymentMethodID: constant.PaymentMethodWallet,
+			RefreshToken:    "refresh",
+			SessionKey:      "session",
 		}
 		res = &userDto.Token{
 			AccessToken:  token,
@@ -509,14 +571,14 @@ func TestPayInvoice(t *testing.T) {
 		req        dto.PayInvoiceRequest
 		want       *userDto.Token
 		wantErr    error
-		beforeTest func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService)
+		beforeTest func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService, userService *mocks.UserService)
 	}{
 		{
 			name:    "should return token when pay invoice success",
 			req:     req,
 			want:    res,
 			wantErr: nil,
-			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService) {
+			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService, userService *mocks.UserService) {
 				invoiceRepo.On("GetByIDAndUserID", req.InvoiceID, req.UserID).Return(&model.Invoice{
 					PaymentMethodID: constant.PaymentMethodWallet,
 					InvoicePerShops: []*model.InvoicePerShop{
@@ -531,7 +593,8 @@ func TestPayInvoice(t *testing.T) {
 					},
 				}, nil)
 				walletService.On("CheckIsWalletBlocked", req.UserID).Return(nil)
-				invoiceRepo.On("Pay", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(res, nil)
+				invoiceRepo.On("Pay", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				userService.On("RotateToken", req.UserID, "session", "refresh", 0).Return(res, nil)
 			},
 		},
 		{
@@ -539,7 +602,7 @@ func TestPayInvoice(t *testing.T) {
 			req:     req,
 			want:    nil,
 			wantErr: errs.ErrInvoiceNotFound,
-			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService) {
+			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService, userService *mocks.UserService) {
 				invoiceRepo.On("GetByIDAndUserID", req.InvoiceID, req.UserID).Return(nil, errs.ErrInvoiceNotFound)
 			},
 		},
@@ -553,7 +616,7 @@ func TestPayInvoice(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: errs.ErrSealabsPayTransactionID,
-			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService) {
+			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService, userService *mocks.UserService) {
 				invoiceRepo.On("GetByIDAndUserID", req.InvoiceID, req.UserID).Return(&model.Invoice{
 					Total:           10000,
 					PaymentMethodID: constant.PaymentMethodSeaLabsPay,
@@ -572,7 +635,7 @@ func TestPayInvoice(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: errs.ErrInvoiceAlreadyPaid,
-			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService) {
+			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService, userService *mocks.UserService) {
 				invoiceRepo.On("GetByIDAndUserID", req.InvoiceID, req.UserID).Return(&model.Invoice{
 					PaymentMethodID: constant.PaymentMethodSeaLabsPay,
 					InvoicePerShops: []*model.InvoicePerShop{
@@ -594,7 +657,30 @@ func TestPayInvoice(t *testing.T) {
 			req:     req,
 			want:    nil,
 			wantErr: errs.ErrInternalServerError,
-			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService) {
+			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService, userService *mocks.UserService) {
+				invoiceRepo.On("GetByIDAndUserID", req.InvoiceID, req.UserID).Return(&model.Invoice{
+					PaymentMethodID: constant.PaymentMethodWallet,
+					InvoicePerShops: []*model.InvoicePerShop{
+						{
+							Status: constant.TransactionStatusWaitingForPayment,
+							Transactions: []*model.Transaction{
+								{
+									ID: 1,
+								},
+							},
+						},
+					},
+				}, nil)
+				walletService.On("CheckIsWalletBlocked", req.UserID).Return(nil)
+				invoiceRepo.On("Pay", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errs.ErrInternalServerError)
+			},
+		},
+		{
+			name:    "should return error when the presented refresh token was already used",
+			req:     req,
+			want:    nil,
+			wantErr: errs.ErrRefreshTokenReused,
+			beforeTest: func(invoiceRepo *mocks.InvoiceRepository, walletService *mocks.WalletService, sealabsPayService *mocks.SealabsPayService, userService *mocks.UserService) {
 				invoiceRepo.On("GetByIDAndUserID", req.InvoiceID, req.UserID).Return(&model.Invoice{
 					PaymentMethodID: constant.PaymentMethodWallet,
 					InvoicePerShops: []*model.InvoicePerShop{
@@ -609,7 +695,8 @@ func TestPayInvoice(t *testing.T) {
 					},
 				}, nil)
 				walletService.On("CheckIsWalletBlocked", req.UserID).Return(nil)
-				invoiceRepo.On("Pay", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errs.ErrInternalServerError)
+				invoiceRepo.On("Pay", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				userService.On("RotateToken", req.UserID, "session", "refresh", 0).Return(nil, errs.ErrRefreshTokenReused)
 			},
 		},
 	}
@@ -619,17 +706,96 @@ func TestPayInvoice(t *testing.T) {
 			mockInvoiceRepo := new(mocks.InvoiceRepository)
 			mockWalletService := new(mocks.WalletService)
 			mockSealabsPayService := new(mocks.SealabsPayService)
-			test.beforeTest(mockInvoiceRepo, mockWalletService, mockSealabsPayService)
+			mockUserService := new(mocks.UserService)
+			test.beforeTest(mockInvoiceRepo, mockWalletService, mockSealabsPayService, mockUserService)
 			service := service.NewInvoiceService(&service.InvoiceSConfig{
 				InvoiceRepo:       mockInvoiceRepo,
 				WalletService:     mockWalletService,
 				SealabsPayService: mockSealabsPayService,
+				UserService:       mockUserService,
 			})
 
-			got, err := service.PayInvoice(test.req, token)
+			got, err := service.PayInvoice(test.req)
 
 			assert.Equal(t, test.want, got)
 			assert.ErrorIs(t, test.wantErr, err)
 		})
 	}
 }
+
+func TestPayInvoiceSendsEmails(t *testing.T) {
+	var (
+		token = "token"
+		req   = dto.PayInvoiceRequest{
+			InvoiceID:       1,
+			UserID:          1,
+			PaymentMethodID: constant.PaymentMethodWallet,
+			Amount:          250000,
+			RefreshToken:    "refresh",
+			SessionKey:      "session",
+		}
+		res     = &userDto.Token{AccessToken: token, RefreshToken: token}
+		details = []*dto.InvoicePerShopEmailDetail{
+			{ID: 3, Code: "INV/3", Total: 100000, BuyerID: 9, BuyerEmail: "buyer@mail.com", BuyerUsername: "buyer", ShopName: "Toko A", SellerID: 11, SellerEmail: "a@mail.com", SellerUsername: "seller_a"},
+			{ID: 4, Code: "INV/4", Total: 150000, BuyerID: 9, BuyerEmail: "buyer@mail.com", BuyerUsername: "buyer", ShopName: "Toko B", SellerID: 12, SellerEmail: "b@mail.com", SellerUsername: "seller_b"},
+		}
+	)
+
+	mockInvoiceRepo := mocks.NewInvoiceRepository(t)
+	mockInvoicePerShopRepo := mocks.NewInvoicePerShopRepository(t)
+	mockWalletService := mocks.NewWalletService(t)
+	mockEmailPublisher := mocks.NewEmailPublisher(t)
+	mockUserService := mocks.NewUserService(t)
+	mockInvoiceRepo.On("GetByIDAndUserID", req.InvoiceID, req.UserID).Return(&model.Invoice{
+		ID:              1,
+		Code:            "INV/1",
+		Total:           250000,
+		PaymentMethodID: constant.PaymentMethodWallet,
+		InvoicePerShops: []*model.InvoicePerShop{
+			{ID: 3, Status: constant.TransactionStatusWaitingForPayment},
+			{ID: 4, Status: constant.TransactionStatusWaitingForPayment},
+		},
+	}, nil)
+	mockWalletService.On("CheckIsWalletBlocked", req.UserID).Return(nil)
+	mockInvoiceRepo.On("Pay", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockUserService.On("RotateToken", req.UserID, "session", "refresh", 0).Return(res, nil)
+	mockInvoicePerShopRepo.On("GetEmailDetails", []int{3, 4}).Return(details, nil)
+	mockEmailPublisher.On("Enqueue", &notificationDto.EmailRequest{
+		To:       "a@mail.com",
+		UserID:   11,
+		Category: constant.NotificationCategoryOrders,
+		Template: mail.TemplateSellerNewOrder,
+		Data:     details[0].SellerEmailData(),
+	}).Return(nil)
+	mockEmailPublisher.On("Enqueue", &notificationDto.EmailRequest{
+		To:       "b@mail.com",
+		UserID:   12,
+		Category: constant.NotificationCategoryOrders,
+		Template: mail.TemplateSellerNewOrder,
+		Data:     details[1].SellerEmailData(),
+	}).Return(nil)
+	mockEmailPublisher.On("Enqueue", &notificationDto.EmailRequest{
+		To:       "buyer@mail.com",
+		UserID:   9,
+		Category: constant.NotificationCategoryOrders,
+		Template: mail.TemplateOrderConfirmation,
+		Data: &mail.OrderConfirmationEmailData{
+			Username:    "buyer",
+			InvoiceCode: "INV/1",
+			Total:       250000,
+			Orders:      []*mail.OrderEmailData{details[0].BuyerEmailData(), details[1].BuyerEmailData()},
+		},
+	}).Return(errors.New("error"))
+	service := service.NewInvoiceService(&service.InvoiceSConfig{
+		InvoiceRepo:        mockInvoiceRepo,
+		InvoicePerShopRepo: mockInvoicePerShopRepo,
+		WalletService:      mockWalletService,
+		EmailPublisher:     mockEmailPublisher,
+		UserService:        mockUserService,
+	})
+
+	got, err := service.PayInvoice(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, res, got)
+}
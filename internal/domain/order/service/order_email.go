@@ -0,0 +1,41 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/domain/order/repository"
+	"log"
+)
+
+// enqueueEmail hands an order email for userId to the outbox. Failures are
+// only logged since the change that triggered the email already went through.
+func enqueueEmail(publisher notificationService.EmailPublisher, userId int, to string, template string, data interface{}) {
+	err := publisher.Enqueue(&notificationDto.EmailRequest{
+		To:       to,
+		UserID:   userId,
+		Category: constant.NotificationCategoryOrders,
+		Template: template,
+		Data:     data,
+	})
+	if err != nil {
+		log.Println("failed to enqueue", template, "email to", to, ":", err)
+	}
+}
+
+// emailBuyers sends template to the buyer of each order in orderIds.
+func emailBuyers(publisher notificationService.EmailPublisher, invoicePerShopRepo repository.InvoicePerShopRepository, orderIds []int, template string) {
+	if publisher == nil || len(orderIds) == 0 {
+		return
+	}
+
+	details, err := invoicePerShopRepo.GetEmailDetails(orderIds)
+	if err != nil {
+		log.Println("failed to get orders", orderIds, "for", template, "email:", err)
+		return
+	}
+
+	for _, detail := range details {
+		enqueueEmail(publisher, detail.BuyerID, detail.BuyerEmail, template, detail.BuyerEmailData())
+	}
+}
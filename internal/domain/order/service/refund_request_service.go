@@ -1,55 +1,120 @@
 package service
 
 import (
+	"kedai/backend/be-kedai/internal/common/constant"
 	"kedai/backend/be-kedai/internal/common/dto"
+	commonErr "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
 	orderDto "kedai/backend/be-kedai/internal/domain/order/dto"
+	"kedai/backend/be-kedai/internal/domain/order/model"
 	"kedai/backend/be-kedai/internal/domain/order/repository"
 	"kedai/backend/be-kedai/internal/domain/shop/service"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"log"
 )
 
 type RefundRequestService interface {
-	UpdateRefundStatus(userId int, invoiceId int, refundStatus string) error
+	UpdateRefundStatus(userId int, actorId int, invoiceId int, refundStatus string) error
 	RefundAdmin(requestRefundId int) error
 	GetRefund(req *orderDto.GetRefundReq) (*dto.PaginationResponse, error)
+	CheckAccountDeletion(userId int) error
 }
 
 type refundRequestServiceImpl struct {
-	refundRequestRepo repository.RefundRequestRepository
-	shopService       service.ShopService
+	refundRequestRepo     repository.RefundRequestRepository
+	invoicePerShopRepo    repository.InvoicePerShopRepository
+	shopService           service.ShopService
+	notificationPublisher notificationService.NotificationPublisher
+	emailPublisher        notificationService.EmailPublisher
 }
 
 type RefundRequestSConfig struct {
-	RefundRequestRepo repository.RefundRequestRepository
-	ShopService       service.ShopService
+	RefundRequestRepo     repository.RefundRequestRepository
+	InvoicePerShopRepo    repository.InvoicePerShopRepository
+	ShopService           service.ShopService
+	NotificationPublisher notificationService.NotificationPublisher
+	EmailPublisher        notificationService.EmailPublisher
 }
 
 func NewRefundRequestService(cfg *RefundRequestSConfig) RefundRequestService {
 	return &refundRequestServiceImpl{
-		refundRequestRepo: cfg.RefundRequestRepo,
-		shopService:       cfg.ShopService,
+		refundRequestRepo:     cfg.RefundRequestRepo,
+		invoicePerShopRepo:    cfg.InvoicePerShopRepo,
+		shopService:           cfg.ShopService,
+		notificationPublisher: cfg.NotificationPublisher,
+		emailPublisher:        cfg.EmailPublisher,
 	}
 }
 
-func (s *refundRequestServiceImpl) UpdateRefundStatus(userId int, invoiceId int, refundStatus string) error {
+// UpdateRefundStatus records actorId, the shop member who approved or
+// rejected the refund, on the resulting order status.
+func (s *refundRequestServiceImpl) UpdateRefundStatus(userId int, actorId int, invoiceId int, refundStatus string) error {
 
 	shop, errShop := s.shopService.FindShopByUserId(userId)
 	if errShop != nil {
 		return errShop
 	}
 
-	err := s.refundRequestRepo.ApproveRejectRefund(shop.ID, invoiceId, refundStatus)
+	err := s.refundRequestRepo.ApproveRejectRefund(shop.ID, actorId, invoiceId, refundStatus)
 
 	if err != nil {
 		return err
 	}
 
+	if s.notificationPublisher != nil {
+		invoice, err := s.invoicePerShopRepo.GetByID(invoiceId)
+		if err != nil {
+			log.Println("failed to get order", invoiceId, "for refund notification:", err)
+		} else {
+			s.notifyRefund(invoice, refundStatus)
+		}
+	}
+
+	switch refundStatus {
+	case constant.RequestStatusSellerApproved:
+		emailBuyers(s.emailPublisher, s.invoicePerShopRepo, []int{invoiceId}, mail.TemplateRefundApproved)
+	case constant.RefundStatusRejected:
+		emailBuyers(s.emailPublisher, s.invoicePerShopRepo, []int{invoiceId}, mail.TemplateRefundRejected)
+	}
+
 	return nil
 }
 
 func (s *refundRequestServiceImpl) RefundAdmin(requestRefundId int) error {
 
-	return s.refundRequestRepo.RefundAdmin(requestRefundId)
+	err := s.refundRequestRepo.RefundAdmin(requestRefundId)
+	if err != nil {
+		return err
+	}
+
+	if s.notificationPublisher != nil {
+		refundRequest, err := s.refundRequestRepo.GetByID(requestRefundId)
+		if err != nil {
+			log.Println("failed to get refund request", requestRefundId, "for refund notification:", err)
+		} else if refundRequest.Invoice != nil {
+			s.notifyRefund(refundRequest.Invoice, constant.RefundStatusRefunded)
+		}
+	}
+
+	return nil
+}
 
+// notifyRefund tells the buyer of invoice how their refund request was
+// decided. Failures are only logged since the decision already went through.
+func (s *refundRequestServiceImpl) notifyRefund(invoice *model.InvoicePerShop, refundStatus string) {
+	err := s.notificationPublisher.Publish(&notificationDto.NotificationEvent{
+		UserID: invoice.UserID,
+		Type:   constant.NotificationTypeRefundDecision,
+		Data: map[string]interface{}{
+			"invoiceId": invoice.ID,
+			"code":      invoice.Code,
+			"status":    refundStatus,
+		},
+	})
+	if err != nil {
+		log.Println("failed to send refund notification for order", invoice.ID, ":", err)
+	}
 }
 
 func (s *refundRequestServiceImpl) GetRefund(req *orderDto.GetRefundReq) (*dto.PaginationResponse, error) {
@@ -67,3 +132,17 @@ func (s *refundRequestServiceImpl) GetRefund(req *orderDto.GetRefundReq) (*dto.P
 	return response, err
 
 }
+
+// CheckAccountDeletion keeps the account open while a refund to it is still
+// being decided.
+func (s *refundRequestServiceImpl) CheckAccountDeletion(userId int) error {
+	open, err := s.refundRequestRepo.HasOpenByUserID(userId)
+	if err != nil {
+		return err
+	}
+	if open {
+		return commonErr.ErrAccountHasOpenRefunds
+	}
+
+	return nil
+}
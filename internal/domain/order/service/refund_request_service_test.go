@@ -2,21 +2,27 @@ package service_test
 
 import (
 	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonErr "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
 	"kedai/backend/be-kedai/internal/domain/order/dto"
+	orderModel "kedai/backend/be-kedai/internal/domain/order/model"
 	"kedai/backend/be-kedai/internal/domain/order/service"
 	"kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/internal/utils/mail"
 	"kedai/backend/be-kedai/mocks"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestApproveRejectRefund(t *testing.T) {
 	var emptyString = ""
 	var (
-		userId = 1
-		shop   = &model.Shop{
+		userId  = 1
+		actorId = 2
+		shop    = &model.Shop{
 			ID: 1,
 		}
 	)
@@ -61,7 +67,7 @@ func TestApproveRejectRefund(t *testing.T) {
 				},
 				beforeTest: func(m *mocks.RefundRequestRepository, s *mocks.ShopService) {
 					s.On("FindShopByUserId", 1).Return(shop, nil)
-					m.On("ApproveRejectRefund", shop.ID, 1, "SELLER_APPROVED").Return(commonErr.ErrRefundRequestNotFound)
+					m.On("ApproveRejectRefund", shop.ID, actorId, 1, "SELLER_APPROVED").Return(commonErr.ErrRefundRequestNotFound)
 				},
 			},
 			expected: expected{
@@ -78,7 +84,7 @@ func TestApproveRejectRefund(t *testing.T) {
 				},
 				beforeTest: func(m *mocks.RefundRequestRepository, s *mocks.ShopService) {
 					s.On("FindShopByUserId", 1).Return(shop, nil)
-					m.On("ApproveRejectRefund", 1, 1, "SELLER_APPROVED").Return(nil)
+					m.On("ApproveRejectRefund", 1, actorId, 1, "SELLER_APPROVED").Return(nil)
 				},
 			},
 			expected: expected{
@@ -99,7 +105,7 @@ func TestApproveRejectRefund(t *testing.T) {
 				ShopService:       mockShopService,
 			})
 
-			err := refundRequestService.UpdateRefundStatus(userId, c.input.invoiceId, c.input.req.RefundStatus)
+			err := refundRequestService.UpdateRefundStatus(userId, actorId, c.input.invoiceId, c.input.req.RefundStatus)
 
 			assert.Equal(t, c.expected.err, err)
 		})
@@ -213,3 +219,112 @@ func TestGetRefund(t *testing.T) {
 		})
 	}
 }
+
+func TestRefundDecisionNotifiesBuyer(t *testing.T) {
+	var (
+		userId  = 1
+		shop    = &model.Shop{ID: 1}
+		invoice = &orderModel.InvoicePerShop{ID: 5, Code: "INV/1", UserID: 9}
+	)
+
+	t.Run("should publish seller decision to the buyer", func(t *testing.T) {
+		mockRefundRequestRepo := mocks.NewRefundRequestRepository(t)
+		mockInvoicePerShopRepo := mocks.NewInvoicePerShopRepository(t)
+		mockShopService := mocks.NewShopService(t)
+		mockPublisher := mocks.NewNotificationPublisher(t)
+		mockShopService.On("FindShopByUserId", userId).Return(shop, nil)
+		mockRefundRequestRepo.On("ApproveRejectRefund", shop.ID, 2, invoice.ID, constant.RefundStatusRejected).Return(nil)
+		mockInvoicePerShopRepo.On("GetByID", invoice.ID).Return(invoice, nil)
+		mockPublisher.On("Publish", &notificationDto.NotificationEvent{
+			UserID: invoice.UserID,
+			Type:   constant.NotificationTypeRefundDecision,
+			Data: map[string]interface{}{
+				"invoiceId": invoice.ID,
+				"code":      invoice.Code,
+				"status":    constant.RefundStatusRejected,
+			},
+		}).Return(nil)
+		refundRequestService := service.NewRefundRequestService(&service.RefundRequestSConfig{
+			RefundRequestRepo:     mockRefundRequestRepo,
+			InvoicePerShopRepo:    mockInvoicePerShopRepo,
+			ShopService:           mockShopService,
+			NotificationPublisher: mockPublisher,
+		})
+
+		err := refundRequestService.UpdateRefundStatus(userId, 2, invoice.ID, constant.RefundStatusRejected)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should publish refunded status once admin refunds", func(t *testing.T) {
+		mockRefundRequestRepo := mocks.NewRefundRequestRepository(t)
+		mockPublisher := mocks.NewNotificationPublisher(t)
+		mockRefundRequestRepo.On("RefundAdmin", 3).Return(nil)
+		mockRefundRequestRepo.On("GetByID", 3).Return(&orderModel.RefundRequest{ID: 3, InvoiceID: invoice.ID, Invoice: invoice}, nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(event *notificationDto.NotificationEvent) bool {
+			return event.UserID == invoice.UserID && event.Data["status"] == constant.RefundStatusRefunded
+		})).Return(nil)
+		refundRequestService := service.NewRefundRequestService(&service.RefundRequestSConfig{
+			RefundRequestRepo:     mockRefundRequestRepo,
+			NotificationPublisher: mockPublisher,
+		})
+
+		err := refundRequestService.RefundAdmin(3)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestRefundDecisionEmailsBuyer(t *testing.T) {
+	var (
+		userId = 1
+		shop   = &model.Shop{ID: 1}
+		detail = &dto.InvoicePerShopEmailDetail{ID: 5, Code: "INV/1", Total: 50000, BuyerID: 9, BuyerEmail: "buyer@mail.com", BuyerUsername: "buyer", ShopName: "Toko"}
+	)
+
+	tests := []struct {
+		description  string
+		refundStatus string
+		template     string
+	}{
+		{
+			description:  "should email approval when seller approves refund",
+			refundStatus: constant.RequestStatusSellerApproved,
+			template:     mail.TemplateRefundApproved,
+		},
+		{
+			description:  "should email rejection when seller rejects refund",
+			refundStatus: constant.RefundStatusRejected,
+			template:     mail.TemplateRefundRejected,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockRefundRequestRepo := mocks.NewRefundRequestRepository(t)
+			mockInvoicePerShopRepo := mocks.NewInvoicePerShopRepository(t)
+			mockShopService := mocks.NewShopService(t)
+			mockEmailPublisher := mocks.NewEmailPublisher(t)
+			mockShopService.On("FindShopByUserId", userId).Return(shop, nil)
+			mockRefundRequestRepo.On("ApproveRejectRefund", shop.ID, 2, detail.ID, tc.refundStatus).Return(nil)
+			mockInvoicePerShopRepo.On("GetEmailDetails", []int{detail.ID}).Return([]*dto.InvoicePerShopEmailDetail{detail}, nil)
+			mockEmailPublisher.On("Enqueue", &notificationDto.EmailRequest{
+				To:       detail.BuyerEmail,
+				UserID:   detail.BuyerID,
+				Category: constant.NotificationCategoryOrders,
+				Template: tc.template,
+				Data:     detail.BuyerEmailData(),
+			}).Return(nil)
+			refundRequestService := service.NewRefundRequestService(&service.RefundRequestSConfig{
+				RefundRequestRepo:  mockRefundRequestRepo,
+				InvoicePerShopRepo: mockInvoicePerShopRepo,
+				ShopService:        mockShopService,
+				EmailPublisher:     mockEmailPublisher,
+			})
+
+			err := refundRequestService.UpdateRefundStatus(userId, 2, detail.ID, tc.refundStatus)
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"kedai/backend/be-kedai/internal/domain/order/repository"
 	productDto "kedai/backend/be-kedai/internal/domain/product/dto"
 	productService "kedai/backend/be-kedai/internal/domain/product/service"
+	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
+	"time"
 )
 
 type TransactionReviewService interface {
@@ -16,6 +18,12 @@ type TransactionReviewService interface {
 	GetReviewByTransactionID(transactionID int) (*model.TransactionReview, error)
 	GetReviews(req productDto.GetReviewRequest) (*commonDto.PaginationResponse, error)
 	GetReviewStats(productCode string) (*productDto.GetReviewStatsResponse, error)
+	Update(req dto.UpdateTransactionReviewRequest) (*model.TransactionReview, error)
+	VoteReview(userID int, reviewID int) error
+	UnvoteReview(userID int, reviewID int) error
+	ReportReview(userID int, reviewID int, req *dto.ReportReviewRequest) error
+	ReplyReview(userID int, reviewID int, req *dto.ReplyReviewRequest) error
+	ExportAccountData(userId int) (map[string]interface{}, error)
 }
 
 type transactionReviewServiceImpl struct {
@@ -23,6 +31,7 @@ type transactionReviewServiceImpl struct {
 	transactionService    TransactionService
 	invoicePerShopService InvoicePerShopService
 	productService        productService.ProductService
+	shopService           shopService.ShopService
 }
 
 type TransactionReviewSConfig struct {
@@ -30,6 +39,7 @@ type TransactionReviewSConfig struct {
 	TransactionService    TransactionService
 	InvoicePerShopService InvoicePerShopService
 	ProductService        productService.ProductService
+	ShopService           shopService.ShopService
 }
 
 func NewTransactionReviewService(config *TransactionReviewSConfig) TransactionReviewService {
@@ -38,10 +48,15 @@ func NewTransactionReviewService(config *TransactionReviewSConfig) TransactionRe
 		transactionService:    config.TransactionService,
 		invoicePerShopService: config.InvoicePerShopService,
 		productService:        config.ProductService,
+		shopService:           config.ShopService,
 	}
 }
 
 func (s *transactionReviewServiceImpl) Create(req dto.TransactionReviewRequest) (*model.TransactionReview, error) {
+	if !req.ValidateMedias() {
+		return nil, commonErr.ErrInvalidReviewMedia
+	}
+
 	transaction, err := s.transactionService.GetByID(req.TransactionId)
 	if err != nil {
 		return nil, err
@@ -108,3 +123,90 @@ func (s *transactionReviewServiceImpl) GetReviewStats(productCode string) (*prod
 
 	return reviewStats, nil
 }
+
+func (s *transactionReviewServiceImpl) Update(req dto.UpdateTransactionReviewRequest) (*model.TransactionReview, error) {
+	if !req.ValidateMedias() {
+		return nil, commonErr.ErrInvalidReviewMedia
+	}
+
+	review, err := s.transactionReviewRepo.GetByID(req.ReviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Transaction == nil || review.Transaction.UserID != req.UserId {
+		return nil, commonErr.ErrTransactionReviewNotFound
+	}
+
+	if time.Since(review.ReviewDate) > constant.ReviewEditWindowDays*24*time.Hour {
+		return nil, commonErr.ErrReviewEditWindowClosed
+	}
+
+	req.ApplyTo(review)
+	err = s.transactionReviewRepo.Update(review)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (s *transactionReviewServiceImpl) VoteReview(userID int, reviewID int) error {
+	err := s.checkReactable(userID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactionReviewRepo.Vote(reviewID, userID)
+}
+
+func (s *transactionReviewServiceImpl) UnvoteReview(userID int, reviewID int) error {
+	return s.transactionReviewRepo.Unvote(reviewID, userID)
+}
+
+func (s *transactionReviewServiceImpl) ReportReview(userID int, reviewID int, req *dto.ReportReviewRequest) error {
+	err := s.checkReactable(userID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactionReviewRepo.Report(req.ToModel(reviewID, userID), constant.ReviewReportHideThreshold)
+}
+
+// checkReactable lets userID vote on or report a review only when it is
+// still shown and they did not write it themselves.
+func (s *transactionReviewServiceImpl) checkReactable(userID int, reviewID int) error {
+	review, err := s.transactionReviewRepo.GetByID(reviewID)
+	if err != nil {
+		return err
+	}
+
+	if review.IsHidden {
+		return commonErr.ErrTransactionReviewNotFound
+	}
+
+	if review.Transaction != nil && review.Transaction.UserID == userID {
+		return commonErr.ErrCannotReactToOwnReview
+	}
+
+	return nil
+}
+
+func (s *transactionReviewServiceImpl) ReplyReview(userID int, reviewID int, req *dto.ReplyReviewRequest) error {
+	shop, err := s.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactionReviewRepo.Reply(reviewID, shop.ID, req.Reply)
+}
+
+// ExportAccountData adds the reviews the user wrote to their data export.
+func (s *transactionReviewServiceImpl) ExportAccountData(userId int) (map[string]interface{}, error) {
+	reviews, err := s.transactionReviewRepo.GetByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"reviews": reviews}, nil
+}
@@ -9,8 +9,10 @@ import (
 	"kedai/backend/be-kedai/internal/domain/order/model"
 	"kedai/backend/be-kedai/internal/domain/order/service"
 	productDto "kedai/backend/be-kedai/internal/domain/product/dto"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
 	"kedai/backend/be-kedai/mocks"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,6 +50,22 @@ func TestCreate(t *testing.T) {
 				err:  commonErr.ErrTransactionNotFound,
 			},
 		},
+		{
+			description: "should return error when review media is not an allowed file",
+			input: input{
+				req: dto.TransactionReviewRequest{
+					UserId:        1,
+					TransactionId: 1,
+					ReviewMedias:  []dto.ReviewMediaRequest{{Url: "https://cdn.kedai.id/review.exe"}},
+				},
+				beforeTest: func(mockTransactionService *mocks.TransactionService, mockInvoicePerShopService *mocks.InvoicePerShopService, mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  commonErr.ErrInvalidReviewMedia,
+			},
+		},
 		{
 			description: "should return error when transaction user id not match with request user id",
 			input: input{
@@ -422,3 +440,317 @@ func TestGetReviewByTransactionID(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateTransactionReview(t *testing.T) {
+	var (
+		description = "updated"
+		recentDate  = time.Now().Add(-24 * time.Hour)
+		staleDate   = time.Now().AddDate(0, 0, -(constant.ReviewEditWindowDays + 1))
+	)
+	type input struct {
+		req        dto.UpdateTransactionReviewRequest
+		beforeTest func(mockTransactionReviewRepo *mocks.TransactionReviewRepository)
+	}
+	type expected struct {
+		data *model.TransactionReview
+		err  error
+	}
+
+	cases := []struct {
+		description string
+		input       input
+		expected    expected
+	}{
+		{
+			description: "should return error when review media is not an allowed file",
+			input: input{
+				req: dto.UpdateTransactionReviewRequest{
+					ReviewId:     1,
+					UserId:       1,
+					Rating:       4,
+					ReviewMedias: []dto.ReviewMediaRequest{{Url: "ftp://cdn.kedai.id/review.png"}},
+				},
+				beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {},
+			},
+			expected: expected{
+				err: commonErr.ErrInvalidReviewMedia,
+			},
+		},
+		{
+			description: "should return error when review not found",
+			input: input{
+				req: dto.UpdateTransactionReviewRequest{ReviewId: 1, UserId: 1, Rating: 4},
+				beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+					mockTransactionReviewRepo.On("GetByID", 1).Return(nil, commonErr.ErrTransactionReviewNotFound)
+				},
+			},
+			expected: expected{
+				err: commonErr.ErrTransactionReviewNotFound,
+			},
+		},
+		{
+			description: "should return error when review belongs to another user",
+			input: input{
+				req: dto.UpdateTransactionReviewRequest{ReviewId: 1, UserId: 1, Rating: 4},
+				beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+					mockTransactionReviewRepo.On("GetByID", 1).Return(&model.TransactionReview{
+						ID:          1,
+						ReviewDate:  recentDate,
+						Transaction: &model.Transaction{UserID: 2},
+					}, nil)
+				},
+			},
+			expected: expected{
+				err: commonErr.ErrTransactionReviewNotFound,
+			},
+		},
+		{
+			description: "should return error when edit window is closed",
+			input: input{
+				req: dto.UpdateTransactionReviewRequest{ReviewId: 1, UserId: 1, Rating: 4},
+				beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+					mockTransactionReviewRepo.On("GetByID", 1).Return(&model.TransactionReview{
+						ID:          1,
+						ReviewDate:  staleDate,
+						Transaction: &model.Transaction{UserID: 1},
+					}, nil)
+				},
+			},
+			expected: expected{
+				err: commonErr.ErrReviewEditWindowClosed,
+			},
+		},
+		{
+			description: "should return updated review when success",
+			input: input{
+				req: dto.UpdateTransactionReviewRequest{
+					ReviewId:     1,
+					UserId:       1,
+					Rating:       4,
+					Description:  &description,
+					ReviewMedias: []dto.ReviewMediaRequest{{Url: "https://cdn.kedai.id/review.png"}},
+				},
+				beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+					mockTransactionReviewRepo.On("GetByID", 1).Return(&model.TransactionReview{
+						ID:          1,
+						Rating:      5,
+						ReviewDate:  recentDate,
+						Transaction: &model.Transaction{UserID: 1},
+					}, nil)
+					mockTransactionReviewRepo.On("Update", &model.TransactionReview{
+						ID:           1,
+						Rating:       4,
+						Description:  &description,
+						ReviewDate:   recentDate,
+						ReviewMedias: []*model.ReviewMedia{{Url: "https://cdn.kedai.id/review.png"}},
+						Transaction:  &model.Transaction{UserID: 1},
+					}).Return(nil)
+				},
+			},
+			expected: expected{
+				data: &model.TransactionReview{
+					ID:           1,
+					Rating:       4,
+					Description:  &description,
+					ReviewDate:   recentDate,
+					ReviewMedias: []*model.ReviewMedia{{Url: "https://cdn.kedai.id/review.png"}},
+					Transaction:  &model.Transaction{UserID: 1},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockTransactionReviewRepo := mocks.NewTransactionReviewRepository(t)
+			c.input.beforeTest(mockTransactionReviewRepo)
+
+			transactionReviewService := service.NewTransactionReviewService(&service.TransactionReviewSConfig{
+				TransactionReviewRepo: mockTransactionReviewRepo,
+			})
+
+			data, err := transactionReviewService.Update(c.input.req)
+
+			assert.Equal(t, c.expected.err, err)
+			assert.Equal(t, c.expected.data, data)
+		})
+	}
+}
+
+func TestVoteReview(t *testing.T) {
+	review := &model.TransactionReview{ID: 1, Transaction: &model.Transaction{UserID: 3}}
+	cases := []struct {
+		description string
+		beforeTest  func(mockTransactionReviewRepo *mocks.TransactionReviewRepository)
+		err         error
+	}{
+		{
+			description: "should return error when review not found",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(nil, commonErr.ErrTransactionReviewNotFound)
+			},
+			err: commonErr.ErrTransactionReviewNotFound,
+		},
+		{
+			description: "should return not found when review is hidden",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(&model.TransactionReview{ID: 1, IsHidden: true, Transaction: &model.Transaction{UserID: 3}}, nil)
+			},
+			err: commonErr.ErrTransactionReviewNotFound,
+		},
+		{
+			description: "should return error when user votes on their own review",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(&model.TransactionReview{ID: 1, Transaction: &model.Transaction{UserID: 2}}, nil)
+			},
+			err: commonErr.ErrCannotReactToOwnReview,
+		},
+		{
+			description: "should return error when review already voted",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(review, nil)
+				mockTransactionReviewRepo.On("Vote", 1, 2).Return(commonErr.ErrReviewAlreadyVoted)
+			},
+			err: commonErr.ErrReviewAlreadyVoted,
+		},
+		{
+			description: "should return nil when success",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(review, nil)
+				mockTransactionReviewRepo.On("Vote", 1, 2).Return(nil)
+			},
+			err: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockTransactionReviewRepo := mocks.NewTransactionReviewRepository(t)
+			c.beforeTest(mockTransactionReviewRepo)
+
+			transactionReviewService := service.NewTransactionReviewService(&service.TransactionReviewSConfig{
+				TransactionReviewRepo: mockTransactionReviewRepo,
+			})
+
+			err := transactionReviewService.VoteReview(2, 1)
+
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestReportReview(t *testing.T) {
+	req := &dto.ReportReviewRequest{Reason: "spam"}
+	review := &model.TransactionReview{ID: 1, Transaction: &model.Transaction{UserID: 3}}
+	cases := []struct {
+		description string
+		beforeTest  func(mockTransactionReviewRepo *mocks.TransactionReviewRepository)
+		err         error
+	}{
+		{
+			description: "should return not found when review is hidden",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(&model.TransactionReview{ID: 1, IsHidden: true, Transaction: &model.Transaction{UserID: 3}}, nil)
+			},
+			err: commonErr.ErrTransactionReviewNotFound,
+		},
+		{
+			description: "should return error when user reports their own review",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(&model.TransactionReview{ID: 1, Transaction: &model.Transaction{UserID: 2}}, nil)
+			},
+			err: commonErr.ErrCannotReactToOwnReview,
+		},
+		{
+			description: "should return error when review already reported",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(review, nil)
+				mockTransactionReviewRepo.On("Report", req.ToModel(1, 2), constant.ReviewReportHideThreshold).Return(commonErr.ErrReviewAlreadyReported)
+			},
+			err: commonErr.ErrReviewAlreadyReported,
+		},
+		{
+			description: "should return nil when success",
+			beforeTest: func(mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+				mockTransactionReviewRepo.On("GetByID", 1).Return(review, nil)
+				mockTransactionReviewRepo.On("Report", req.ToModel(1, 2), constant.ReviewReportHideThreshold).Return(nil)
+			},
+			err: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockTransactionReviewRepo := mocks.NewTransactionReviewRepository(t)
+			c.beforeTest(mockTransactionReviewRepo)
+
+			transactionReviewService := service.NewTransactionReviewService(&service.TransactionReviewSConfig{
+				TransactionReviewRepo: mockTransactionReviewRepo,
+			})
+
+			err := transactionReviewService.ReportReview(2, 1, req)
+
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestReplyReview(t *testing.T) {
+	req := &dto.ReplyReviewRequest{Reply: "thank you"}
+	type input struct {
+		beforeTest func(mockShopService *mocks.ShopService, mockTransactionReviewRepo *mocks.TransactionReviewRepository)
+	}
+
+	cases := []struct {
+		description string
+		input       input
+		expected    error
+	}{
+		{
+			description: "should return error when shop not found",
+			input: input{
+				beforeTest: func(mockShopService *mocks.ShopService, mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+					mockShopService.On("FindShopByUserId", 2).Return(nil, commonErr.ErrShopNotFound)
+				},
+			},
+			expected: commonErr.ErrShopNotFound,
+		},
+		{
+			description: "should return error when review does not belong to shop",
+			input: input{
+				beforeTest: func(mockShopService *mocks.ShopService, mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+					mockShopService.On("FindShopByUserId", 2).Return(&shopModel.Shop{ID: 3}, nil)
+					mockTransactionReviewRepo.On("Reply", 1, 3, req.Reply).Return(commonErr.ErrTransactionReviewNotFound)
+				},
+			},
+			expected: commonErr.ErrTransactionReviewNotFound,
+		},
+		{
+			description: "should return nil when success",
+			input: input{
+				beforeTest: func(mockShopService *mocks.ShopService, mockTransactionReviewRepo *mocks.TransactionReviewRepository) {
+					mockShopService.On("FindShopByUserId", 2).Return(&shopModel.Shop{ID: 3}, nil)
+					mockTransactionReviewRepo.On("Reply", 1, 3, req.Reply).Return(nil)
+				},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockShopService := mocks.NewShopService(t)
+			mockTransactionReviewRepo := mocks.NewTransactionReviewRepository(t)
+			c.input.beforeTest(mockShopService, mockTransactionReviewRepo)
+
+			transactionReviewService := service.NewTransactionReviewService(&service.TransactionReviewSConfig{
+				TransactionReviewRepo: mockTransactionReviewRepo,
+				ShopService:           mockShopService,
+			})
+
+			err := transactionReviewService.ReplyReview(2, 1, req)
+
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
@@ -26,16 +26,18 @@ func (r *GetCategoriesRequest) Offset() int {
 }
 
 type CategoryDTO struct {
-	Name     string         `json:"name" binding:"required"`
-	ImageURL string         `json:"image_url" binding:"required"`
-	ParentID *int           `json:"parent_id,omitempty"`
-	Children []*CategoryDTO `json:"children,omitempty"`
+	Name        string         `json:"name" binding:"required"`
+	ImageURL    string         `json:"image_url" binding:"required"`
+	IsHazardous bool           `json:"is_hazardous"`
+	ParentID    *int           `json:"parent_id,omitempty"`
+	Children    []*CategoryDTO `json:"children,omitempty"`
 }
 
 func (cdto CategoryDTO) ToModel() *model.Category {
 	categoryModel := &model.Category{
-		Name:     cdto.Name,
-		ImageURL: cdto.ImageURL,
+		Name:        cdto.Name,
+		ImageURL:    cdto.ImageURL,
+		IsHazardous: cdto.IsHazardous,
 	}
 	for _, childDTO := range cdto.Children {
 		childModel := childDTO.ToModel()
@@ -12,39 +12,47 @@ import (
 )
 
 type Discussion struct {
-	ID         int                   `json:"id"`
-	UserID     int                   `json:"-"`
-	Username   string                `json:"username" gorm:"-"`
-	UserUrl    string                `json:"userUrl" gorm:"-"`
-	User       *userModel.User       `json:"-"`
-	ShopId     int                   `json:"-"`
-	Shop       *shopModel.Shop       `json:"-" gorm:"foreignKey:ShopId"`
-	ShopName   string                `json:"shopName,omitempty" gorm:"-"`
-	ShopUrl    string                `json:"shopUrl,omitempty" gorm:"-"`
-	ProductID  int                   `json:"productId"`
-	Product    *productModel.Product `json:"product,omitempty"`
-	Message    string                `json:"message"`
-	Date       time.Time             `json:"date"`
-	Reply      *DiscussionReply      `json:"reply" gorm:"foreignKey:ID"`
-	ReplyCount int                   `json:"replyCount" gorm:"-"`
+	ID           int                   `json:"id"`
+	UserID       int                   `json:"-"`
+	Username     string                `json:"username" gorm:"-"`
+	UserUrl      string                `json:"userUrl" gorm:"-"`
+	User         *userModel.User       `json:"-"`
+	ShopId       int                   `json:"-"`
+	Shop         *shopModel.Shop       `json:"-" gorm:"foreignKey:ShopId"`
+	ShopName     string                `json:"shopName,omitempty" gorm:"-"`
+	ShopUrl      string                `json:"shopUrl,omitempty" gorm:"-"`
+	ProductID    int                   `json:"productId"`
+	Product      *productModel.Product `json:"product,omitempty"`
+	ParentID     *int                  `json:"parentId,omitempty"`
+	Message      string                `json:"message"`
+	Date         time.Time             `json:"date"`
+	HelpfulCount int                   `json:"helpfulCount"`
+	IsOfficial   bool                  `json:"isOfficial"`
+	IsHidden     bool                  `json:"-"`
+	Reply        *DiscussionReply      `json:"reply" gorm:"foreignKey:ID"`
+	ReplyCount   int                   `json:"replyCount" gorm:"-"`
 
 	gorm.Model `json:"-"`
 }
 
 type DiscussionReply struct {
-	ID        int             `json:"id"`
-	UserID    int             `json:"-"`
-	Username  string          `json:"username"`
-	UserUrl   string          `json:"userUrl"`
-	User      *userModel.User `json:"-"`
-	ShopId    int             `json:"-"`
-	Shop      *shopModel.Shop `json:"-" gorm:"foreignKey:ShopId"`
-	ShopName  string          `json:"shopName,omitempty"`
-	ShopUrl   string          `json:"shopUrl,omitempty"`
-	ProductID int             `json:"productId"`
-	ParentID  int             `json:"parentId"`
-	Message   string          `json:"message"`
-	Date      string          `json:"date"`
+	ID           int             `json:"id"`
+	UserID       int             `json:"-"`
+	Username     string          `json:"username"`
+	UserUrl      string          `json:"userUrl"`
+	User         *userModel.User `json:"-"`
+	ShopId       int             `json:"-"`
+	Shop         *shopModel.Shop `json:"-" gorm:"foreignKey:ShopId"`
+	ShopName     string          `json:"shopName,omitempty"`
+	ShopUrl      string          `json:"shopUrl,omitempty"`
+	ProductID    int             `json:"productId"`
+	ParentID     int             `json:"parentId"`
+	Message      string          `json:"message"`
+	Date         string          `json:"date"`
+	HelpfulCount int             `json:"helpfulCount"`
+	IsOfficial   bool            `json:"isOfficial"`
+	IsHidden     bool            `json:"-"`
+	ReplyCount   int             `json:"replyCount" gorm:"->"`
 
 	gorm.Model `json:"-"`
 }
@@ -82,3 +90,29 @@ func (req *GetDiscussionReq) Validate() {
 func (req *GetDiscussionReq) Offset() int {
 	return (req.Page - 1) * req.Limit
 }
+
+type ReportDiscussionReq struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+func (req *ReportDiscussionReq) ToDiscussionReport(discussionID, userID int) *productModel.DiscussionReport {
+	return &productModel.DiscussionReport{
+		Reason:       req.Reason,
+		DiscussionID: discussionID,
+		UserID:       userID,
+	}
+}
+
+type UpdateDiscussionVisibilityReq struct {
+	IsHidden *bool `json:"isHidden" binding:"required"`
+}
+
+type DiscussionSLAStats struct {
+	SLAHours               int     `json:"slaHours"`
+	UnansweredCount        int64   `json:"unansweredCount"`
+	OverdueCount           int64   `json:"overdueCount"`
+	AnsweredCount          int64   `json:"answeredCount"`
+	AnsweredWithinSLACount int64   `json:"answeredWithinSlaCount"`
+	AverageResponseMinutes float64 `json:"averageResponseMinutes"`
+	SLAComplianceRate      float64 `json:"slaComplianceRate"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/product/model"
+)
+
+type StockHistoryRequest struct {
+	Limit int    `form:"limit"`
+	Page  int    `form:"page"`
+	Type  string `form:"type"`
+	SkuID int    `form:"skuId"`
+}
+
+func (req *StockHistoryRequest) Validate() {
+	if req.Limit < 1 {
+		req.Limit = constant.DefaultStockHistoryLimit
+	}
+
+	if req.Limit > constant.MaxStockHistoryLimit {
+		req.Limit = constant.MaxStockHistoryLimit
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+
+	if req.Type != model.InventoryMovementTypeSale &&
+		req.Type != model.InventoryMovementTypeCancelRestock &&
+		req.Type != model.InventoryMovementTypeRefundRestock &&
+		req.Type != model.InventoryMovementTypeManualAdjust &&
+		req.Type != model.InventoryMovementTypeImport {
+		req.Type = ""
+	}
+}
+
+func (req *StockHistoryRequest) Offset() int {
+	return (req.Page - 1) * req.Limit
+}
+
+type UpdateLowStockThresholdRequest struct {
+	Skus []*SkuLowStockThreshold `json:"skus" binding:"required,min=1,dive"`
+}
+
+type SkuLowStockThreshold struct {
+	SkuID     int  `json:"skuId" binding:"required,gte=1"`
+	Threshold *int `json:"threshold" binding:"omitempty,gte=0"`
+}
+
+type LowStockSku struct {
+	ID                int    `json:"id"`
+	Sku               string `json:"sku"`
+	Stock             int    `json:"stock"`
+	LowStockThreshold int    `json:"lowStockThreshold"`
+	ProductID         int    `json:"productId"`
+	ProductCode       string `json:"productCode"`
+	ProductName       string `json:"productName"`
+	ImageUrl          string `json:"imageUrl"`
+}
+
+type LowStockAlert struct {
+	ID                int
+	Sku               string
+	Stock             int
+	LowStockThreshold int
+	ProductName       string
+	Email             string
+}
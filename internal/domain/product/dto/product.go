@@ -81,6 +81,8 @@ type SellerProductFilterRequest struct {
 	IsPromoted  *bool     `form:"isPromoted"`
 	StartPeriod time.Time `form:"startPeriod"`
 	EndPeriod   time.Time `form:"endPeriod"`
+
+	ModerationStatus string `form:"moderationStatus"`
 }
 
 func (r *SellerProductFilterRequest) Validate() {
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"kedai/backend/be-kedai/internal/common/constant"
+	"kedai/backend/be-kedai/internal/domain/product/model"
+)
+
+type ModerationQueueRequest struct {
+	Limit  int    `form:"limit"`
+	Page   int    `form:"page"`
+	Status string `form:"status"`
+	Name   string `form:"name"`
+}
+
+func (req *ModerationQueueRequest) Validate() {
+	if req.Limit < 1 {
+		req.Limit = constant.DefaultModerationQueueLimit
+	}
+
+	if req.Limit > constant.MaxModerationQueueLimit {
+		req.Limit = constant.MaxModerationQueueLimit
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+
+	if req.Status != constant.ModerationStatusApproved &&
+		req.Status != constant.ModerationStatusRejected &&
+		req.Status != constant.ModerationStatusBanned {
+		req.Status = constant.ModerationStatusPendingReview
+	}
+}
+
+func (req *ModerationQueueRequest) Offset() int {
+	return (req.Page - 1) * req.Limit
+}
+
+type ModerateProductRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected banned"`
+	Reason string `json:"reason" binding:"required_unless=Status approved,max=255"`
+}
+
+func (req *ModerateProductRequest) GenerateModeration(productID int) *model.ProductModeration {
+	moderation := &model.ProductModeration{
+		Status:    req.Status,
+		ProductID: productID,
+	}
+
+	if req.Reason != "" {
+		moderation.Reason = &req.Reason
+	}
+
+	return moderation
+}
+
+type ModerationQueueProduct struct {
+	model.Product
+	MinPrice float64 `json:"minPrice"`
+	MaxPrice float64 `json:"maxPrice"`
+	ImageURL string  `json:"imageUrl,omitempty"`
+}
+
+func (ModerationQueueProduct) TableName() string {
+	return "products"
+}
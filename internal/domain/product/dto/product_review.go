@@ -8,6 +8,7 @@ type GetReviewRequest struct {
 	Limit       int    `form:"limit"`
 	Page        int    `form:"page"`
 	Filter      string `form:"filter"`
+	Sort        string `form:"sort"`
 	ProductCode string
 }
 
@@ -46,6 +47,10 @@ func (req *GetReviewRequest) Validate() {
 		req.Filter = ""
 	}
 
+	if req.Sort != constant.SortByLatest && req.Sort != constant.SortByMostHelpful {
+		req.Sort = constant.SortByLatest
+	}
+
 }
 
 func (req *GetReviewRequest) Offset() int {
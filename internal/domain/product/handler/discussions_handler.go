@@ -32,7 +32,7 @@ func (h *Handler) GetDiscussionByProductID(c *gin.Context) {
 }
 
 func (h *Handler) GetUnrepliedDiscussionByShopID(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	var request dto.GetDiscussionReq
 	request.Limit, _ = strconv.Atoi(c.Query("limit"))
@@ -56,7 +56,13 @@ func (h *Handler) GetUnrepliedDiscussionByShopID(c *gin.Context) {
 func (h *Handler) GetDiscussionByParentID(c *gin.Context) {
 	parentId, _ := strconv.Atoi(c.Param("parentId"))
 
-	result, err := h.discussionService.GetChildDiscussionByParentID(parentId)
+	var request dto.GetDiscussionReq
+	request.Limit, _ = strconv.Atoi(c.Query("limit"))
+	request.Page, _ = strconv.Atoi(c.Query("page"))
+
+	request.Validate()
+
+	result, err := h.discussionService.GetChildDiscussionByParentID(parentId, request)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
 		return
@@ -78,9 +84,161 @@ func (h *Handler) PostDiscussion(c *gin.Context) {
 
 	err := h.discussionService.PostDiscussion(&discussionDto)
 	if err != nil {
+		if errors.Is(err, commonError.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+		if errors.Is(err, commonError.ErrDiscussionNotFound) {
+			response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", nil)
+}
+
+func (h *Handler) VoteDiscussion(c *gin.Context) {
+	userId := c.GetInt("userId")
+	discussionId, err := strconv.Atoi(c.Param("discussionId"))
+	if discussionId < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, commonError.ErrDiscussionNotFound.Error())
+		return
+	}
+
+	err = h.discussionService.VoteDiscussion(userId, discussionId)
+	if err != nil {
+		if errors.Is(err, commonError.ErrDiscussionNotFound) {
+			response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, commonError.ErrDiscussionAlreadyVoted) {
+			response.Error(c, http.StatusConflict, code.DISCUSSION_ALREADY_VOTED, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", nil)
+}
+
+func (h *Handler) UnvoteDiscussion(c *gin.Context) {
+	userId := c.GetInt("userId")
+	discussionId, err := strconv.Atoi(c.Param("discussionId"))
+	if discussionId < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, commonError.ErrDiscussionNotFound.Error())
+		return
+	}
+
+	err = h.discussionService.UnvoteDiscussion(userId, discussionId)
+	if err != nil {
+		if errors.Is(err, commonError.ErrDiscussionNotVoted) {
+			response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_VOTED, err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
 		return
 	}
 
 	response.Success(c, http.StatusOK, code.OK, "ok", nil)
 }
+
+func (h *Handler) ReportDiscussion(c *gin.Context) {
+	userId := c.GetInt("userId")
+	discussionId, err := strconv.Atoi(c.Param("discussionId"))
+	if discussionId < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, commonError.ErrDiscussionNotFound.Error())
+		return
+	}
+
+	var request dto.ReportDiscussionReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.discussionService.ReportDiscussion(userId, discussionId, &request)
+	if err != nil {
+		if errors.Is(err, commonError.ErrDiscussionNotFound) {
+			response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, commonError.ErrDiscussionAlreadyReported) {
+			response.Error(c, http.StatusConflict, code.DISCUSSION_ALREADY_REPORTED, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "created", nil)
+}
+
+func (h *Handler) UpdateDiscussionVisibility(c *gin.Context) {
+	discussionId, err := strconv.Atoi(c.Param("discussionId"))
+	if discussionId < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, commonError.ErrDiscussionNotFound.Error())
+		return
+	}
+
+	var request dto.UpdateDiscussionVisibilityReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.discussionService.UpdateDiscussionVisibility(discussionId, &request)
+	if err != nil {
+		if errors.Is(err, commonError.ErrDiscussionNotFound) {
+			response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "update successful", nil)
+}
+
+func (h *Handler) MarkOfficialAnswer(c *gin.Context) {
+	userId := c.GetInt("shopOwnerId")
+	discussionId, err := strconv.Atoi(c.Param("discussionId"))
+	if discussionId < 1 || err != nil {
+		response.Error(c, http.StatusNotFound, code.DISCUSSION_NOT_FOUND, commonError.ErrDiscussionNotFound.Error())
+		return
+	}
+
+	err = h.discussionService.MarkOfficialAnswer(userId, discussionId)
+	if err != nil {
+		if errors.Is(err, commonError.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+		if errors.Is(err, commonError.ErrInvalidOfficialAnswer) {
+			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_OFFICIAL_ANSWER, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "update successful", nil)
+}
+
+func (h *Handler) GetDiscussionSLAStats(c *gin.Context) {
+	userId := c.GetInt("shopOwnerId")
+
+	result, err := h.discussionService.GetDiscussionSLAStats(userId)
+	if err != nil {
+		if errors.Is(err, commonError.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, commonError.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", result)
+}
@@ -102,7 +102,11 @@ func TestGetDiscussionByProductID(t *testing.T) {
 func TestGetDiscussionByParentID(t *testing.T) {
 	var (
 		parentID   = 1
-		discussion = []*dto.DiscussionReply{}
+		discussion = &commonDto.PaginationResponse{}
+		filter     = dto.GetDiscussionReq{
+			Page:  1,
+			Limit: 5,
+		}
 	)
 
 	type input struct {
@@ -155,7 +159,7 @@ func TestGetDiscussionByParentID(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			expectedBody, _ := json.Marshal(tc.expected.response)
 			mockService := new(mocks.DiscussionService)
-			mockService.On("GetChildDiscussionByParentID", tc.input.parentID).Return(tc.expected.response.Data, tc.input.err)
+			mockService.On("GetChildDiscussionByParentID", tc.input.parentID, filter).Return(tc.expected.response.Data, tc.input.err)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
 			h := handler.New(&handler.Config{
@@ -266,3 +270,362 @@ func TestPostDiscussion(t *testing.T) {
 	}
 
 }
+
+func TestVoteDiscussion(t *testing.T) {
+	var (
+		userId       = 1
+		discussionId = 1
+	)
+
+	type input struct {
+		discussionId string
+		beforeTest   func(*mocks.DiscussionService)
+	}
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return 404 when discussion id is invalid",
+			input: input{
+				discussionId: "abc",
+				beforeTest:   func(ds *mocks.DiscussionService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.DISCUSSION_NOT_FOUND,
+					Message: errorResponse.ErrDiscussionNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 409 when discussion already voted",
+			input: input{
+				discussionId: "1",
+				beforeTest: func(ds *mocks.DiscussionService) {
+					ds.On("VoteDiscussion", userId, discussionId).Return(errorResponse.ErrDiscussionAlreadyVoted)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.DISCUSSION_ALREADY_VOTED,
+					Message: errorResponse.ErrDiscussionAlreadyVoted.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 500 when internal server error",
+			input: input{
+				discussionId: "1",
+				beforeTest: func(ds *mocks.DiscussionService) {
+					ds.On("VoteDiscussion", userId, discussionId).Return(errorResponse.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errorResponse.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 200 when vote recorded",
+			input: input{
+				discussionId: "1",
+				beforeTest: func(ds *mocks.DiscussionService) {
+					ds.On("VoteDiscussion", userId, discussionId).Return(nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			mockService := mocks.NewDiscussionService(t)
+			tc.beforeTest(mockService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h := handler.New(&handler.Config{
+				DiscussionService: mockService,
+			})
+			c.Set("userId", userId)
+			c.AddParam("discussionId", tc.input.discussionId)
+			c.Request, _ = http.NewRequest("POST", fmt.Sprintf("/products/discussions/%s/votes", tc.input.discussionId), nil)
+			h.VoteDiscussion(c)
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, expectedBody, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestReportDiscussion(t *testing.T) {
+	var (
+		userId       = 1
+		discussionId = 1
+		request      = &dto.ReportDiscussionReq{Reason: "spam"}
+	)
+
+	type input struct {
+		request    *dto.ReportDiscussionReq
+		beforeTest func(*mocks.DiscussionService)
+	}
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return 400 when reason is missing",
+			input: input{
+				request:    &dto.ReportDiscussionReq{},
+				beforeTest: func(ds *mocks.DiscussionService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Reason is required",
+				},
+			},
+		},
+		{
+			description: "should return 404 when discussion does not exist",
+			input: input{
+				request: request,
+				beforeTest: func(ds *mocks.DiscussionService) {
+					ds.On("ReportDiscussion", userId, discussionId, request).Return(errorResponse.ErrDiscussionNotFound)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.DISCUSSION_NOT_FOUND,
+					Message: errorResponse.ErrDiscussionNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 409 when discussion already reported",
+			input: input{
+				request: request,
+				beforeTest: func(ds *mocks.DiscussionService) {
+					ds.On("ReportDiscussion", userId, discussionId, request).Return(errorResponse.ErrDiscussionAlreadyReported)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.DISCUSSION_ALREADY_REPORTED,
+					Message: errorResponse.ErrDiscussionAlreadyReported.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 201 when report recorded",
+			input: input{
+				request: request,
+				beforeTest: func(ds *mocks.DiscussionService) {
+					ds.On("ReportDiscussion", userId, discussionId, request).Return(nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "created",
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			mockService := mocks.NewDiscussionService(t)
+			tc.beforeTest(mockService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h := handler.New(&handler.Config{
+				DiscussionService: mockService,
+			})
+			c.Set("userId", userId)
+			c.AddParam("discussionId", "1")
+			c.Request, _ = http.NewRequest("POST", "/products/discussions/1/reports", test.MakeRequestBody(tc.input.request))
+			h.ReportDiscussion(c)
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, expectedBody, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestMarkOfficialAnswer(t *testing.T) {
+	var (
+		userId       = 1
+		discussionId = 2
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		err         error
+		expected
+	}{
+		{
+			description: "should return 404 when user has no shop",
+			err:         errorResponse.ErrShopNotFound,
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.SHOP_NOT_REGISTERED,
+					Message: errorResponse.ErrShopNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 422 when discussion is not the shop's reply",
+			err:         errorResponse.ErrInvalidOfficialAnswer,
+			expected: expected{
+				statusCode: http.StatusUnprocessableEntity,
+				response: response.Response{
+					Code:    code.INVALID_OFFICIAL_ANSWER,
+					Message: errorResponse.ErrInvalidOfficialAnswer.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 200 when answer marked as official",
+			err:         nil,
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.UPDATED,
+					Message: "update successful",
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			mockService := mocks.NewDiscussionService(t)
+			mockService.On("MarkOfficialAnswer", userId, discussionId).Return(tc.err)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h := handler.New(&handler.Config{
+				DiscussionService: mockService,
+			})
+			c.Set("shopOwnerId", userId)
+			c.AddParam("discussionId", fmt.Sprintf("%d", discussionId))
+			c.Request, _ = http.NewRequest("PUT", fmt.Sprintf("/sellers/discussions/%d/official", discussionId), nil)
+			h.MarkOfficialAnswer(c)
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, expectedBody, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestGetDiscussionSLAStats(t *testing.T) {
+	var (
+		userId = 1
+		stats  = &dto.DiscussionSLAStats{SLAHours: 24, UnansweredCount: 1}
+	)
+
+	type input struct {
+		stats *dto.DiscussionSLAStats
+		err   error
+	}
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return 404 when user has no shop",
+			input: input{
+				stats: nil,
+				err:   errorResponse.ErrShopNotFound,
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.SHOP_NOT_REGISTERED,
+					Message: errorResponse.ErrShopNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return 500 when internal server error",
+			input: input{
+				stats: nil,
+				err:   errorResponse.ErrInternalServerError,
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errorResponse.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return stats when success",
+			input: input{
+				stats: stats,
+				err:   nil,
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+					Data:    stats,
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			mockService := mocks.NewDiscussionService(t)
+			mockService.On("GetDiscussionSLAStats", userId).Return(tc.input.stats, tc.input.err)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h := handler.New(&handler.Config{
+				DiscussionService: mockService,
+			})
+			c.Set("shopOwnerId", userId)
+			c.Request, _ = http.NewRequest("GET", "/sellers/discussions/stats", nil)
+			h.GetDiscussionSLAStats(c)
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, expectedBody, rec.Body.Bytes())
+		})
+	}
+}
@@ -11,6 +11,7 @@ type Handler struct {
 	skuService               service.SkuService
 	transactionReviewService orderService.TransactionReviewService
 	discussionService        service.DiscussionService
+	productModerationService service.ProductModerationService
 }
 
 type Config struct {
@@ -19,6 +20,7 @@ type Config struct {
 	SkuService               service.SkuService
 	TransactionReviewService orderService.TransactionReviewService
 	DiscussionService        service.DiscussionService
+	ProductModerationService service.ProductModerationService
 }
 
 func New(cfg *Config) *Handler {
@@ -28,5 +30,6 @@ func New(cfg *Config) *Handler {
 		skuService:               cfg.SkuService,
 		transactionReviewService: cfg.TransactionReviewService,
 		discussionService:        cfg.DiscussionService,
+		productModerationService: cfg.ProductModerationService,
 	}
 }
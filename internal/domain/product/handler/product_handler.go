@@ -97,7 +97,7 @@ func (h *Handler) GetSellerProducts(c *gin.Context) {
 
 	request.Validate()
 
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	res, err := h.productService.GetSellerProducts(userID, &request)
 	if err != nil {
@@ -128,7 +128,7 @@ func (h *Handler) SearchAutocomplete(c *gin.Context) {
 }
 
 func (h *Handler) GetSellerProductDetailByCode(c *gin.Context) {
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 	productCode := c.Param("code")
 
 	product, err := h.productService.GetSellerProductByCode(userID, productCode)
@@ -179,7 +179,7 @@ func (h *Handler) UpdateProductActivation(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	productCode := c.Param("code")
 
@@ -195,6 +195,11 @@ func (h *Handler) UpdateProductActivation(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, errs.ErrProductBanned) {
+			response.Error(c, http.StatusForbidden, code.PRODUCT_BANNED, err.Error())
+			return
+		}
+
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
 		return
 	}
@@ -271,7 +276,7 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 	productCode := c.Param("code")
 
 	product, err := h.productService.UpdateProduct(userID, productCode, &request)
@@ -290,6 +295,11 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, errs.ErrProductBanned) {
+			response.Error(c, http.StatusForbidden, code.PRODUCT_BANNED, err.Error())
+			return
+		}
+
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
 		return
 	}
@@ -592,7 +592,7 @@ func TestGetSellerProduct(t *testing.T) {
 		productService.On("GetSellerProducts", tc.input.userID, tc.input.request).Return(tc.input.mockData, tc.input.mockErr)
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
-		c.Set("userId", tc.input.userID)
+		c.Set("shopOwnerId", tc.input.userID)
 		h := handler.New(&handler.Config{
 			ProductService: productService,
 		})
@@ -701,7 +701,7 @@ func TestGetSellerProductDetailByCode(t *testing.T) {
 			productService.On("GetSellerProductByCode", tc.input.userID, tc.input.productCode).Return(tc.input.mockData, tc.input.mockErr)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			c.AddParam("code", tc.input.productCode)
 			h := handler.New(&handler.Config{
 				ProductService: productService,
@@ -951,7 +951,7 @@ func TestXxx(t *testing.T) {
 			tc.beforeTest(productService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			c.AddParam("code", productCode)
 			h := handler.New(&handler.Config{
 				ProductService: productService,
@@ -1527,7 +1527,7 @@ func TestUpdateProduct(t *testing.T) {
 
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			c.AddParam("code", tc.input.code)
 			c.Request = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/v1/products/%s", tc.input.code), payload)
 			h := handler.New(&handler.Config{
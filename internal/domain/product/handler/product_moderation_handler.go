@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/product/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetModerationQueue(c *gin.Context) {
+	var request dto.ModerationQueueRequest
+	_ = c.ShouldBindQuery(&request)
+
+	request.Validate()
+
+	res, err := h.productModerationService.GetModerationQueue(&request)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", res)
+}
+
+func (h *Handler) GetProductForModeration(c *gin.Context) {
+	productCode := c.Param("code")
+
+	product, err := h.productModerationService.GetProductForModeration(productCode)
+	if err != nil {
+		if errors.Is(err, errs.ErrProductDoesNotExist) {
+			response.Error(c, http.StatusNotFound, code.PRODUCT_NOT_EXISTS, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", product)
+}
+
+func (h *Handler) ModerateProduct(c *gin.Context) {
+	var request dto.ModerateProductRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	productCode := c.Param("code")
+
+	err = h.productModerationService.ModerateProduct(productCode, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrProductDoesNotExist) {
+			response.Error(c, http.StatusNotFound, code.PRODUCT_NOT_EXISTS, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "update successful", nil)
+}
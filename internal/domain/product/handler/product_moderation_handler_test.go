@@ -0,0 +1,306 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/product/dto"
+	"kedai/backend/be-kedai/internal/domain/product/handler"
+	"kedai/backend/be-kedai/internal/domain/product/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetModerationQueue(t *testing.T) {
+	type input struct {
+		mockData *commonDto.PaginationResponse
+		mockErr  error
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		request = &dto.ModerationQueueRequest{
+			Limit:  constant.DefaultModerationQueueLimit,
+			Page:   1,
+			Status: constant.ModerationStatusPendingReview,
+		}
+		queue = &commonDto.PaginationResponse{
+			TotalRows:  1,
+			TotalPages: 1,
+			Page:       1,
+			Limit:      constant.DefaultModerationQueueLimit,
+			Data:       []*dto.ModerationQueueProduct{{Product: model.Product{ID: 1}}},
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 500 when failed to get moderation queue",
+			input: input{
+				mockData: nil,
+				mockErr:  errs.ErrInternalServerError,
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return moderation queue with status code 200 when succeed",
+			input: input{
+				mockData: queue,
+				mockErr:  nil,
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    queue,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			moderationService := mocks.NewProductModerationService(t)
+			moderationService.On("GetModerationQueue", request).Return(tc.input.mockData, tc.input.mockErr)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h := handler.New(&handler.Config{
+				ProductModerationService: moderationService,
+			})
+			c.Request = httptest.NewRequest("GET", "/v1/admins/products/moderations", nil)
+
+			h.GetModerationQueue(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestGetProductForModeration(t *testing.T) {
+	type input struct {
+		productCode string
+		mockData    *model.Product
+		mockErr     error
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		productCode = "product-code"
+		product     = &model.Product{ID: 1, Code: productCode, ModerationStatus: constant.ModerationStatusPendingReview}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when product does not exist",
+			input: input{
+				productCode: productCode,
+				mockData:    nil,
+				mockErr:     errs.ErrProductDoesNotExist,
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.PRODUCT_NOT_EXISTS,
+					Message: errs.ErrProductDoesNotExist.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to get product",
+			input: input{
+				productCode: productCode,
+				mockData:    nil,
+				mockErr:     errs.ErrInternalServerError,
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return product with status code 200 when succeed",
+			input: input{
+				productCode: productCode,
+				mockData:    product,
+				mockErr:     nil,
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    product,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			moderationService := mocks.NewProductModerationService(t)
+			moderationService.On("GetProductForModeration", tc.input.productCode).Return(tc.input.mockData, tc.input.mockErr)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.AddParam("code", tc.input.productCode)
+			h := handler.New(&handler.Config{
+				ProductModerationService: moderationService,
+			})
+			c.Request = httptest.NewRequest("GET", fmt.Sprintf("/v1/admins/products/%s", tc.input.productCode), nil)
+
+			h.GetProductForModeration(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestModerateProduct(t *testing.T) {
+	type input struct {
+		productCode string
+		request     *dto.ModerateProductRequest
+		beforeTest  func(*mocks.ProductModerationService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		productCode = "product-code"
+		request     = &dto.ModerateProductRequest{
+			Status: constant.ModerationStatusRejected,
+			Reason: "misleading product photos",
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 400 when reason is missing for rejection",
+			input: input{
+				productCode: productCode,
+				request:     &dto.ModerateProductRequest{Status: constant.ModerationStatusRejected},
+				beforeTest:  func(pms *mocks.ProductModerationService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Reason is required",
+				},
+			},
+		},
+		{
+			description: "should return error with status code 404 when product does not exist",
+			input: input{
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(pms *mocks.ProductModerationService) {
+					pms.On("ModerateProduct", productCode, request).Return(errs.ErrProductDoesNotExist)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.PRODUCT_NOT_EXISTS,
+					Message: errs.ErrProductDoesNotExist.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to moderate product",
+			input: input{
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(pms *mocks.ProductModerationService) {
+					pms.On("ModerateProduct", productCode, request).Return(errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return status code 200 when product moderated",
+			input: input{
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(pms *mocks.ProductModerationService) {
+					pms.On("ModerateProduct", productCode, request).Return(nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.UPDATED,
+					Message: "update successful",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			moderationService := mocks.NewProductModerationService(t)
+			tc.beforeTest(moderationService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.AddParam("code", tc.input.productCode)
+			h := handler.New(&handler.Config{
+				ProductModerationService: moderationService,
+			})
+			c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/v1/admins/products/%s/moderations", tc.input.productCode), test.MakeRequestBody(tc.input.request))
+
+			h.ModerateProduct(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
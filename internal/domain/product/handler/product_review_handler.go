@@ -8,6 +8,9 @@ import (
 	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/utils/response"
 	"net/http"
+	"strconv"
+
+	orderDto "kedai/backend/be-kedai/internal/domain/order/dto"
 
 	"github.com/gin-gonic/gin"
 )
@@ -46,3 +49,85 @@ func (h *Handler) GetProductReviewStats(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, code.OK, "ok", result)
 }
+
+func (h *Handler) VoteProductReview(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil || reviewID < 1 {
+		response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, errs.ErrTransactionReviewNotFound.Error())
+		return
+	}
+
+	err = h.transactionReviewService.VoteReview(c.GetInt("userId"), reviewID)
+	if err != nil {
+		if errors.Is(err, errs.ErrTransactionReviewNotFound) {
+			response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrReviewAlreadyVoted) {
+			response.Error(c, http.StatusConflict, code.REVIEW_ALREADY_VOTED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrCannotReactToOwnReview) {
+			response.Error(c, http.StatusBadRequest, code.CANNOT_REACT_TO_OWN_REVIEW, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", nil)
+}
+
+func (h *Handler) UnvoteProductReview(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil || reviewID < 1 {
+		response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, errs.ErrTransactionReviewNotFound.Error())
+		return
+	}
+
+	err = h.transactionReviewService.UnvoteReview(c.GetInt("userId"), reviewID)
+	if err != nil {
+		if errors.Is(err, errs.ErrReviewNotVoted) {
+			response.Error(c, http.StatusNotFound, code.REVIEW_NOT_VOTED, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", nil)
+}
+
+func (h *Handler) ReportProductReview(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil || reviewID < 1 {
+		response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, errs.ErrTransactionReviewNotFound.Error())
+		return
+	}
+
+	var req orderDto.ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.transactionReviewService.ReportReview(c.GetInt("userId"), reviewID, &req)
+	if err != nil {
+		if errors.Is(err, errs.ErrTransactionReviewNotFound) {
+			response.Error(c, http.StatusNotFound, code.TRANSACTION_REVIEW_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrReviewAlreadyReported) {
+			response.Error(c, http.StatusConflict, code.REVIEW_ALREADY_REPORTED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrCannotReactToOwnReview) {
+			response.Error(c, http.StatusBadRequest, code.CANNOT_REACT_TO_OWN_REVIEW, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "created", nil)
+}
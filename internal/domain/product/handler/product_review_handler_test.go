@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	commonErr "kedai/backend/be-kedai/internal/common/error"
+	orderDto "kedai/backend/be-kedai/internal/domain/order/dto"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/domain/product/handler"
 	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
 	"kedai/backend/be-kedai/mocks"
 	"net/http"
 	"net/http/httptest"
@@ -41,6 +44,7 @@ func TestGetProductReviews(t *testing.T) {
 				req: dto.GetReviewRequest{
 					Limit: 6,
 					Page:  1,
+					Sort:  constant.SortByLatest,
 				},
 				res: commonDto.PaginationResponse{},
 				err: commonErr.ErrProductDoesNotExist,
@@ -59,6 +63,7 @@ func TestGetProductReviews(t *testing.T) {
 				req: dto.GetReviewRequest{
 					Limit: 6,
 					Page:  1,
+					Sort:  constant.SortByLatest,
 				},
 				res: commonDto.PaginationResponse{},
 				err: commonErr.ErrInternalServerError,
@@ -77,6 +82,7 @@ func TestGetProductReviews(t *testing.T) {
 				req: dto.GetReviewRequest{
 					Limit: 6,
 					Page:  1,
+					Sort:  constant.SortByLatest,
 				},
 				res: commonDto.PaginationResponse{
 					Data: []interface{}{},
@@ -208,3 +214,189 @@ func TestGetProductReviewStats(t *testing.T) {
 	}
 
 }
+
+func TestVoteProductReview(t *testing.T) {
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	tests := []struct {
+		description string
+		reviewId    string
+		err         error
+		expected    expected
+	}{
+		{
+			description: "should return not found when review id is invalid",
+			reviewId:    "abc",
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.TRANSACTION_REVIEW_NOT_FOUND,
+					Message: commonErr.ErrTransactionReviewNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return conflict when review already voted",
+			reviewId:    "1",
+			err:         commonErr.ErrReviewAlreadyVoted,
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.REVIEW_ALREADY_VOTED,
+					Message: commonErr.ErrReviewAlreadyVoted.Error(),
+				},
+			},
+		},
+		{
+			description: "should return bad request when user votes on their own review",
+			reviewId:    "1",
+			err:         commonErr.ErrCannotReactToOwnReview,
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.CANNOT_REACT_TO_OWN_REVIEW,
+					Message: commonErr.ErrCannotReactToOwnReview.Error(),
+				},
+			},
+		},
+		{
+			description: "should return not found when review does not exist",
+			reviewId:    "1",
+			err:         commonErr.ErrTransactionReviewNotFound,
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.TRANSACTION_REVIEW_NOT_FOUND,
+					Message: commonErr.ErrTransactionReviewNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return ok when vote saved",
+			reviewId:    "1",
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			mockService := mocks.NewTransactionReviewService(t)
+			if tc.reviewId == "1" {
+				mockService.On("VoteReview", 2, 1).Return(tc.err)
+			}
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 2)
+			c.AddParam("reviewId", tc.reviewId)
+			h := handler.New(&handler.Config{
+				TransactionReviewService: mockService,
+			})
+			c.Request = httptest.NewRequest("POST", fmt.Sprintf("/products/reviews/%s/votes", tc.reviewId), nil)
+
+			h.VoteProductReview(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestReportProductReview(t *testing.T) {
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	tests := []struct {
+		description string
+		req         orderDto.ReportReviewRequest
+		beforeTest  func(mockService *mocks.TransactionReviewService)
+		expected    expected
+	}{
+		{
+			description: "should return bad request when reason is empty",
+			req:         orderDto.ReportReviewRequest{},
+			beforeTest:  func(mockService *mocks.TransactionReviewService) {},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Reason is required",
+				},
+			},
+		},
+		{
+			description: "should return conflict when review already reported",
+			req:         orderDto.ReportReviewRequest{Reason: "spam"},
+			beforeTest: func(mockService *mocks.TransactionReviewService) {
+				mockService.On("ReportReview", 2, 1, &orderDto.ReportReviewRequest{Reason: "spam"}).Return(commonErr.ErrReviewAlreadyReported)
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.REVIEW_ALREADY_REPORTED,
+					Message: commonErr.ErrReviewAlreadyReported.Error(),
+				},
+			},
+		},
+		{
+			description: "should return bad request when user reports their own review",
+			req:         orderDto.ReportReviewRequest{Reason: "spam"},
+			beforeTest: func(mockService *mocks.TransactionReviewService) {
+				mockService.On("ReportReview", 2, 1, &orderDto.ReportReviewRequest{Reason: "spam"}).Return(commonErr.ErrCannotReactToOwnReview)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.CANNOT_REACT_TO_OWN_REVIEW,
+					Message: commonErr.ErrCannotReactToOwnReview.Error(),
+				},
+			},
+		},
+		{
+			description: "should return created when report saved",
+			req:         orderDto.ReportReviewRequest{Reason: "spam"},
+			beforeTest: func(mockService *mocks.TransactionReviewService) {
+				mockService.On("ReportReview", 2, 1, &orderDto.ReportReviewRequest{Reason: "spam"}).Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "created",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			mockService := mocks.NewTransactionReviewService(t)
+			tc.beforeTest(mockService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 2)
+			c.AddParam("reviewId", "1")
+			h := handler.New(&handler.Config{
+				TransactionReviewService: mockService,
+			})
+			c.Request = httptest.NewRequest("POST", "/products/reviews/1/reports", test.MakeRequestBody(tc.req))
+
+			h.ReportProductReview(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
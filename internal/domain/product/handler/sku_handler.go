@@ -6,6 +6,7 @@ import (
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/utils/response"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -37,3 +38,88 @@ func (h *Handler) GetSKUByVariantIDs(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, code.OK, "success", sku)
 }
+
+func (h *Handler) GetStockHistory(c *gin.Context) {
+	var request dto.StockHistoryRequest
+	_ = c.ShouldBindQuery(&request)
+
+	request.Validate()
+
+	userID := c.GetInt("shopOwnerId")
+	productCode := c.Param("code")
+
+	res, err := h.skuService.GetStockHistory(userID, productCode, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+
+		if errors.Is(err, errs.ErrProductDoesNotExist) {
+			response.Error(c, http.StatusNotFound, code.PRODUCT_NOT_EXISTS, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", res)
+}
+
+func (h *Handler) UpdateLowStockThresholds(c *gin.Context) {
+	var request dto.UpdateLowStockThresholdRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID := c.GetInt("shopOwnerId")
+	productCode := c.Param("code")
+
+	err = h.skuService.UpdateLowStockThresholds(userID, productCode, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+
+		if errors.Is(err, errs.ErrProductDoesNotExist) {
+			response.Error(c, http.StatusNotFound, code.PRODUCT_NOT_EXISTS, err.Error())
+			return
+		}
+
+		if errors.Is(err, errs.ErrSKUDoesNotExist) {
+			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "update successful", nil)
+}
+
+func (h *Handler) GetLowStockSkus(c *gin.Context) {
+	userID := c.GetInt("shopOwnerId")
+
+	skus, err := h.skuService.GetLowStockSkus(userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrShopNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_NOT_REGISTERED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "success", skus)
+}
+
+func (h *Handler) LowStockCronJob(c *gin.Context) {
+	_ = h.skuService.NotifyLowStock()
+	log.Println("LOW STOCK CRON JOB")
+}
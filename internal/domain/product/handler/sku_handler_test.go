@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"kedai/backend/be-kedai/internal/common/code"
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/domain/product/handler"
 	"kedai/backend/be-kedai/internal/domain/product/model"
 	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
 	"kedai/backend/be-kedai/mocks"
 	"net/http"
 	"net/http/httptest"
@@ -134,3 +136,337 @@ func TestGetSKUByVariantIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStockHistory(t *testing.T) {
+	type input struct {
+		userID      int
+		productCode string
+		request     *dto.StockHistoryRequest
+		mockData    *commonDto.PaginationResponse
+		mockErr     error
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		userID      = 1
+		productCode = "product-code"
+		request     = &dto.StockHistoryRequest{Limit: 10, Page: 1}
+		history     = &commonDto.PaginationResponse{
+			Data:       []*model.InventoryMovement{},
+			Limit:      10,
+			Page:       1,
+			TotalRows:  0,
+			TotalPages: 0,
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when shop not found",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				mockData:    nil,
+				mockErr:     errs.ErrShopNotFound,
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.SHOP_NOT_REGISTERED,
+					Message: errs.ErrShopNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 404 when product not found",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				mockData:    nil,
+				mockErr:     errs.ErrProductDoesNotExist,
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.PRODUCT_NOT_EXISTS,
+					Message: errs.ErrProductDoesNotExist.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to get stock history",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				mockData:    nil,
+				mockErr:     errors.New("failed to get stock history"),
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return stock history with status code 200 when succeed",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				mockData:    history,
+				mockErr:     nil,
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    history,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			skuService := mocks.NewSkuService(t)
+			skuService.On("GetStockHistory", tc.input.userID, tc.input.productCode, tc.input.request).Return(tc.input.mockData, tc.input.mockErr)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("shopOwnerId", tc.input.userID)
+			c.AddParam("code", tc.input.productCode)
+			h := handler.New(&handler.Config{
+				SkuService: skuService,
+			})
+			c.Request = httptest.NewRequest("GET", fmt.Sprintf("/v1/sellers/products/%s/stock-history?page=%d&limit=%d", tc.input.productCode, tc.input.request.Page, tc.input.request.Limit), nil)
+
+			h.GetStockHistory(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestUpdateLowStockThresholds(t *testing.T) {
+	type input struct {
+		userID      int
+		productCode string
+		request     *dto.UpdateLowStockThresholdRequest
+		beforeTest  func(*mocks.SkuService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		userID      = 1
+		productCode = "product-code"
+		threshold   = 5
+		request     = &dto.UpdateLowStockThresholdRequest{
+			Skus: []*dto.SkuLowStockThreshold{{SkuID: 1, Threshold: &threshold}},
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 400 when request is invalid",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     &dto.UpdateLowStockThresholdRequest{},
+				beforeTest:  func(ss *mocks.SkuService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Skus is required",
+				},
+			},
+		},
+		{
+			description: "should return error with status code 404 when sku not found",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.SkuService) {
+					ss.On("UpdateLowStockThresholds", userID, productCode, request).Return(errs.ErrSKUDoesNotExist)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.NOT_FOUND,
+					Message: errs.ErrSKUDoesNotExist.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to update threshold",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.SkuService) {
+					ss.On("UpdateLowStockThresholds", userID, productCode, request).Return(errors.New("failed to update"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return status code 200 when threshold updated",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.SkuService) {
+					ss.On("UpdateLowStockThresholds", userID, productCode, request).Return(nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.UPDATED,
+					Message: "update successful",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			skuService := mocks.NewSkuService(t)
+			tc.beforeTest(skuService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("shopOwnerId", tc.input.userID)
+			c.AddParam("code", tc.input.productCode)
+			h := handler.New(&handler.Config{
+				SkuService: skuService,
+			})
+			c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/v1/sellers/products/%s/low-stock-thresholds", tc.input.productCode), test.MakeRequestBody(tc.input.request))
+
+			h.UpdateLowStockThresholds(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestGetLowStockSkus(t *testing.T) {
+	type input struct {
+		userID   int
+		mockData []*dto.LowStockSku
+		mockErr  error
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		userID = 1
+		skus   = []*dto.LowStockSku{{ID: 1, Stock: 2, LowStockThreshold: 5}}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when shop not found",
+			input: input{
+				userID:   userID,
+				mockData: nil,
+				mockErr:  errs.ErrShopNotFound,
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.SHOP_NOT_REGISTERED,
+					Message: errs.ErrShopNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to get low stock skus",
+			input: input{
+				userID:   userID,
+				mockData: nil,
+				mockErr:  errors.New("failed to get low stock skus"),
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return low stock skus with status code 200 when succeed",
+			input: input{
+				userID:   userID,
+				mockData: skus,
+				mockErr:  nil,
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "success",
+					Data:    skus,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			skuService := mocks.NewSkuService(t)
+			skuService.On("GetLowStockSkus", tc.input.userID).Return(tc.input.mockData, tc.input.mockErr)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("shopOwnerId", tc.input.userID)
+			h := handler.New(&handler.Config{
+				SkuService: skuService,
+			})
+			c.Request = httptest.NewRequest("GET", "/v1/sellers/products/low-stocks", nil)
+
+			h.GetLowStockSkus(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import "gorm.io/gorm"
+
+type DiscussionVote struct {
+	ID           int `json:"id"`
+	DiscussionID int `json:"discussionId"`
+	UserID       int `json:"userId"`
+
+	gorm.Model `json:"-"`
+}
+
+type DiscussionReport struct {
+	ID           int    `json:"id"`
+	Reason       string `json:"reason"`
+	DiscussionID int    `json:"discussionId"`
+	UserID       int    `json:"userId"`
+
+	gorm.Model `json:"-"`
+}
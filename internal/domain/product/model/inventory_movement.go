@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InventoryMovement struct {
+	ID         int       `json:"id"`
+	Type       string    `json:"type"`
+	Quantity   int       `json:"quantity"`
+	StockAfter int       `json:"stockAfter"`
+	Date       time.Time `json:"date" gorm:"default:CURRENT_TIMESTAMP"`
+	SkuId      int       `json:"skuId"`
+
+	Sku *Sku `json:"sku,omitempty" gorm:"foreignKey:SkuId"`
+
+	gorm.Model `json:"-"`
+}
+
+const (
+	InventoryMovementTypeSale          = "sale"
+	InventoryMovementTypeCancelRestock = "cancel_restock"
+	InventoryMovementTypeRefundRestock = "refund_restock"
+	InventoryMovementTypeManualAdjust  = "manual_adjust"
+	InventoryMovementTypeImport        = "import"
+)
+
+func (im *InventoryMovement) BeforeCreate(tx *gorm.DB) (err error) {
+	im.Date = time.Now()
+	return
+}
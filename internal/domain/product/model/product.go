@@ -22,6 +22,10 @@ type Product struct {
 	Rating      float64 `json:"rating"`
 	Sold        int     `json:"sold"`
 
+	ModerationStatus string   `json:"moderationStatus"`
+	ModerationReason *string  `json:"moderationReason,omitempty"`
+	ModerationFlags  []string `json:"moderationFlags,omitempty" gorm:"serializer:json"`
+
 	ShopID         int                         `json:"shopId"`
 	Shop           *shopModel.Shop             `json:"shop,omitempty"`
 	CategoryID     int                         `json:"categoryId"`
@@ -31,6 +35,7 @@ type Product struct {
 	SKU            *Sku                        `json:"sku,omitempty"`
 	SKUs           []*Sku                      `json:"skus,omitempty"`
 	CourierService []*shopModel.CourierService `json:"courierServices,omitempty" gorm:"many2many:product_couriers"`
+	Moderations    []*ProductModeration        `json:"moderations,omitempty"`
 
 	gorm.Model `json:"-"`
 }
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProductModeration struct {
+	ID        int       `json:"id"`
+	Status    string    `json:"status"`
+	Reason    *string   `json:"reason,omitempty"`
+	Flags     []string  `json:"flags,omitempty" gorm:"serializer:json"`
+	Date      time.Time `json:"date" gorm:"default:CURRENT_TIMESTAMP"`
+	ProductID int       `json:"productId"`
+
+	gorm.Model `json:"-"`
+}
+
+func (pm *ProductModeration) BeforeCreate(tx *gorm.DB) (err error) {
+	pm.Date = time.Now()
+	return
+}
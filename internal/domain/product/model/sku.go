@@ -1,13 +1,19 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Sku struct {
-	ID        int     `json:"id"`
-	Sku       string  `json:"sku,omitempty"`
-	Price     float64 `json:"price"`
-	Stock     int     `json:"stock"`
-	ProductId int     `json:"productId"`
+	ID                 int        `json:"id"`
+	Sku                string     `json:"sku,omitempty"`
+	Price              float64    `json:"price"`
+	Stock              int        `json:"stock"`
+	LowStockThreshold  *int       `json:"lowStockThreshold,omitempty"`
+	LowStockNotifiedAt *time.Time `json:"-"`
+	ProductId          int        `json:"productId"`
 
 	Product   *Product          `json:"product,omitempty" gorm:"foreignKey:ProductId"`
 	Variants  []Variant         `json:"variants,omitempty" gorm:"many2many:product_variants;"`
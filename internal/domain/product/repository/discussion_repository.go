@@ -1,18 +1,29 @@
 package repository
 
 import (
+	stdErrors "errors"
 	errors "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
+	"kedai/backend/be-kedai/internal/domain/product/model"
 	"math"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DiscussionRepository interface {
 	GetDiscussionByProductID(productID int, req dto.GetDiscussionReq) (data []*dto.Discussion, limit int, page int, totalRows int, totalPages int, err error)
 	GetUnrepliedDiscussionByShopID(shopID int, req dto.GetDiscussionReq) (data []*dto.Discussion, limit int, page int, totalRows int, totalPages int, err error)
-	GetChildDiscussionByParentID(parentID int) ([]*dto.DiscussionReply, error)
+	GetChildDiscussionByParentID(parentID int, req dto.GetDiscussionReq) (data []*dto.DiscussionReply, limit int, page int, totalRows int, totalPages int, err error)
+	GetByID(discussionID int) (*dto.Discussion, error)
 	PostDiscussion(discussion *dto.DiscussionReq) error
+	Vote(discussionID int, userID int) error
+	Unvote(discussionID int, userID int) error
+	Report(report *model.DiscussionReport, hideThreshold int) error
+	UpdateVisibility(discussionID int, isHidden bool) error
+	MarkOfficial(discussionID int, shopID int) error
+	GetSLAStats(shopID int, slaHours int, since time.Time) (*dto.DiscussionSLAStats, error)
 }
 
 type discussionRepositoryImpl struct {
@@ -33,12 +44,12 @@ func (d *discussionRepositoryImpl) GetDiscussionByProductID(productID int, req d
 
 	var discussions []*dto.Discussion
 	var count int64
-	err = d.db.Model(&dto.Discussion{}).Where("product_id = ? AND parent_id IS NULL", productID).Count(&count).Error
+	err = d.db.Model(&dto.Discussion{}).Where("product_id = ? AND parent_id IS NULL AND is_hidden = false", productID).Count(&count).Error
 	if err != nil {
 		return []*dto.Discussion{}, 0, 0, 0, 0, err
 	}
 
-	err = d.db.Where("product_id = ? AND parent_id IS NULL", productID).Preload("User").Preload("User.Profile").
+	err = d.db.Where("product_id = ? AND parent_id IS NULL AND is_hidden = false", productID).Preload("User").Preload("User.Profile").
 		Preload("Shop").Limit(req.Limit).Offset(req.Offset()).Order("date desc").Find(&discussions).Error
 	if err != nil {
 		return []*dto.Discussion{}, 0, 0, 0, 0, err
@@ -51,7 +62,7 @@ func (d *discussionRepositoryImpl) GetDiscussionByProductID(productID int, req d
 	for i, discussion := range discussions {
 		var replies []*dto.DiscussionReply
 		var repliesCount int
-		err := d.db.Table("discussions").Where("parent_id = ?", discussion.ID).Preload("User").Preload("User.Profile").Preload("Shop").Find(&replies).Error
+		err := d.db.Table("discussions").Where("parent_id = ? AND is_hidden = false", discussion.ID).Preload("User").Preload("User.Profile").Preload("Shop").Order("is_official desc, date asc").Find(&replies).Error
 		if err != nil {
 			return []*dto.Discussion{}, 0, 0, 0, 0, err
 		}
@@ -92,7 +103,7 @@ func (d *discussionRepositoryImpl) GetUnrepliedDiscussionByShopID(shopID int, re
 	var count int64
 	err = d.db.Model(&dto.Discussion{}).
 		Joins("JOIN products ON discussions.product_id = products.id").
-		Where("products.shop_id = ? AND discussions.parent_id IS NULL", shopID).
+		Where("products.shop_id = ? AND discussions.parent_id IS NULL AND discussions.is_hidden = false", shopID).
 		Where("(select d2.shop_id from discussions d2 where d2.parent_id = discussions.id order by d2.date desc limit 1) is null").
 		Group("discussions.id").
 		Count(&count).Error
@@ -102,7 +113,7 @@ func (d *discussionRepositoryImpl) GetUnrepliedDiscussionByShopID(shopID int, re
 
 	err = d.db.
 		Joins("JOIN products ON discussions.product_id = products.id").
-		Where("products.shop_id = ? AND discussions.parent_id IS NULL", shopID).
+		Where("products.shop_id = ? AND discussions.parent_id IS NULL AND discussions.is_hidden = false", shopID).
 		Where("(select d2.shop_id from discussions d2 where d2.parent_id = discussions.id order by date desc limit 1) is null").
 		Group("discussions.id").
 		Preload("User").Preload("User.Profile").Preload("Product.Media").
@@ -118,26 +129,34 @@ func (d *discussionRepositoryImpl) GetUnrepliedDiscussionByShopID(shopID int, re
 	for i, discussion := range discussions {
 		var replies []*dto.DiscussionReply
 		var repliesCount int
-		err := d.db.Table("discussions").Where("parent_id = ?", discussion.ID).Preload("User").Preload("User.Profile").Preload("Shop").Find(&replies).Error
+		err := d.db.Table("discussions").Where("parent_id = ? AND is_hidden = false", discussion.ID).Preload("User").Preload("User.Profile").Preload("Shop").Order("is_official desc, date asc").Find(&replies).Error
 		if err != nil {
 			return []*dto.Discussion{}, 0, 0, 0, 0, err
 		}
 
 		discussions[i].Username = discussion.User.Username
-		discussions[i].UserUrl = *discussion.User.Profile.PhotoUrl
+		if discussion.User.Profile.PhotoUrl != nil {
+			discussions[i].UserUrl = *discussion.User.Profile.PhotoUrl
+		}
 		if discussions[i].ShopId != 0 {
 			discussions[i].ShopName = discussion.Shop.Name
-			discussions[i].ShopUrl = *discussion.Shop.PhotoUrl
+			if discussion.Shop.PhotoUrl != nil {
+				discussions[i].ShopUrl = *discussion.Shop.PhotoUrl
+			}
 		}
 		repliesCount = len(replies)
 
 		if repliesCount >= 1 {
 			discussions[i].Reply = replies[0]
 			discussions[i].Reply.Username = replies[0].User.Username
-			discussions[i].Reply.UserUrl = *replies[0].User.Profile.PhotoUrl
+			if replies[0].User.Profile.PhotoUrl != nil {
+				discussions[i].Reply.UserUrl = *replies[0].User.Profile.PhotoUrl
+			}
 			if discussions[i].Reply.ShopId != 0 {
 				discussions[i].Reply.ShopName = replies[0].Shop.Name
-				discussions[i].Reply.ShopUrl = *replies[0].Shop.PhotoUrl
+				if replies[0].Shop.PhotoUrl != nil {
+					discussions[i].Reply.ShopUrl = *replies[0].Shop.PhotoUrl
+				}
 			}
 		}
 		discussions[i].ReplyCount = repliesCount
@@ -145,23 +164,52 @@ func (d *discussionRepositoryImpl) GetUnrepliedDiscussionByShopID(shopID int, re
 	return discussions, req.Limit, req.Page, int(count), int(math.Ceil(float64(count) / float64(req.Limit))), nil
 }
 
-func (d *discussionRepositoryImpl) GetChildDiscussionByParentID(parentID int) ([]*dto.DiscussionReply, error) {
+func (d *discussionRepositoryImpl) GetChildDiscussionByParentID(parentID int, req dto.GetDiscussionReq) (data []*dto.DiscussionReply, limit int, page int, totalRows int, totalPages int, err error) {
 	var replies []*dto.DiscussionReply
-	err := d.db.Table("discussions").Where("parent_id = ?", parentID).Preload("User").Preload("User.Profile").Preload("Shop").Find(&replies).Error
+	var count int64
+	err = d.db.Table("discussions").Where("parent_id = ? AND is_hidden = false AND deleted_at IS NULL", parentID).Count(&count).Error
 	if err != nil {
-		return nil, err
+		return []*dto.DiscussionReply{}, 0, 0, 0, 0, err
+	}
+
+	err = d.db.Table("discussions").Where("parent_id = ? AND is_hidden = false", parentID).Preload("User").Preload("User.Profile").Preload("Shop").
+		Select("discussions.*, (SELECT COUNT(*) FROM discussions d2 WHERE d2.parent_id = discussions.id AND d2.is_hidden = false AND d2.deleted_at IS NULL) AS reply_count").
+		Limit(req.Limit).Offset(req.Offset()).Order("is_official desc, date asc").Find(&replies).Error
+	if err != nil {
+		return []*dto.DiscussionReply{}, 0, 0, 0, 0, err
 	}
 
 	for i, reply := range replies {
 		replies[i].Username = reply.User.Username
-		replies[i].UserUrl = *reply.User.Profile.PhotoUrl
+		if reply.User.Profile.PhotoUrl != nil {
+			replies[i].UserUrl = *reply.User.Profile.PhotoUrl
+		}
 		if replies[i].ShopId != 0 {
 			replies[i].ShopName = reply.Shop.Name
-			replies[i].ShopUrl = *reply.Shop.PhotoUrl
+			if reply.Shop.PhotoUrl != nil {
+				replies[i].ShopUrl = *reply.Shop.PhotoUrl
+			}
+		}
+	}
+
+	if replies == nil {
+		replies = []*dto.DiscussionReply{}
+	}
+
+	return replies, req.Limit, req.Page, int(count), int(math.Ceil(float64(count) / float64(req.Limit))), nil
+}
+
+func (d *discussionRepositoryImpl) GetByID(discussionID int) (*dto.Discussion, error) {
+	var discussion dto.Discussion
+	err := d.db.Where("is_hidden = false").Preload("User").Preload("Product").First(&discussion, discussionID).Error
+	if err != nil {
+		if stdErrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrDiscussionNotFound
 		}
+		return nil, err
 	}
 
-	return replies, nil
+	return &discussion, nil
 }
 
 func (d *discussionRepositoryImpl) PostDiscussion(discussion *dto.DiscussionReq) error {
@@ -176,3 +224,167 @@ func (d *discussionRepositoryImpl) PostDiscussion(discussion *dto.DiscussionReq)
 
 	return nil
 }
+
+func (d *discussionRepositoryImpl) Vote(discussionID int, userID int) error {
+	tx := d.db.Begin()
+	defer tx.Commit()
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.DiscussionVote{DiscussionID: discussionID, UserID: userID})
+	if res.Error != nil {
+		tx.Rollback()
+		if errors.IsForeignKeyError(res.Error) {
+			return errors.ErrDiscussionNotFound
+		}
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.ErrDiscussionAlreadyVoted
+	}
+
+	err := tx.Model(&dto.Discussion{}).Where("id = ?", discussionID).Update("helpful_count", gorm.Expr("helpful_count + 1")).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (d *discussionRepositoryImpl) Unvote(discussionID int, userID int) error {
+	tx := d.db.Begin()
+	defer tx.Commit()
+
+	res := tx.Unscoped().Where("discussion_id = ? AND user_id = ?", discussionID, userID).Delete(&model.DiscussionVote{})
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.ErrDiscussionNotVoted
+	}
+
+	err := tx.Model(&dto.Discussion{}).Where("id = ? AND helpful_count > 0", discussionID).Update("helpful_count", gorm.Expr("helpful_count - 1")).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (d *discussionRepositoryImpl) Report(report *model.DiscussionReport, hideThreshold int) error {
+	tx := d.db.Begin()
+	defer tx.Commit()
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if res.Error != nil {
+		tx.Rollback()
+		if errors.IsForeignKeyError(res.Error) {
+			return errors.ErrDiscussionNotFound
+		}
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.ErrDiscussionAlreadyReported
+	}
+
+	var reportCount int64
+	err := tx.Model(&model.DiscussionReport{}).Where("discussion_id = ?", report.DiscussionID).Count(&reportCount).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if reportCount >= int64(hideThreshold) {
+		err = tx.Model(&dto.Discussion{}).Where("id = ?", report.DiscussionID).Update("is_hidden", true).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *discussionRepositoryImpl) UpdateVisibility(discussionID int, isHidden bool) error {
+	res := d.db.Model(&dto.Discussion{}).Where("id = ?", discussionID).Update("is_hidden", isHidden)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.ErrDiscussionNotFound
+	}
+
+	return nil
+}
+
+func (d *discussionRepositoryImpl) MarkOfficial(discussionID int, shopID int) error {
+	tx := d.db.Begin()
+	defer tx.Commit()
+
+	var reply dto.DiscussionReply
+	err := tx.Table("discussions").
+		Joins("JOIN products ON products.id = discussions.product_id").
+		Where("discussions.id = ? AND discussions.shop_id = ? AND products.shop_id = ?", discussionID, shopID, shopID).
+		Where("discussions.parent_id IS NOT NULL AND discussions.is_hidden = false").
+		Select("discussions.*").
+		First(&reply).Error
+	if err != nil {
+		tx.Rollback()
+		if stdErrors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.ErrInvalidOfficialAnswer
+		}
+		return err
+	}
+
+	err = tx.Model(&dto.Discussion{}).Where("parent_id = ? AND id <> ?", reply.ParentID, reply.ID).Update("is_official", false).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Model(&dto.Discussion{}).Where("id = ?", reply.ID).Update("is_official", true).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// GetSLAStats counts every open question of the shop, however old, and
+// measures answered questions and response times over those asked since
+// since.
+func (d *discussionRepositoryImpl) GetSLAStats(shopID int, slaHours int, since time.Time) (*dto.DiscussionSLAStats, error) {
+	var stats dto.DiscussionSLAStats
+
+	slaSeconds := slaHours * 60 * 60
+	err := d.db.Raw(`WITH questions AS (
+		SELECT d.id, d.date,
+			(SELECT MIN(r.date) FROM discussions r WHERE r.parent_id = d.id AND r.shop_id = ? AND r.deleted_at IS NULL) AS answered_at
+		FROM discussions d
+		JOIN products p ON p.id = d.product_id
+		WHERE p.shop_id = ? AND d.parent_id IS NULL AND d.shop_id IS NULL AND d.is_hidden = false AND d.deleted_at IS NULL
+	)
+	SELECT
+		COUNT(*) FILTER (WHERE answered_at IS NULL) AS unanswered_count,
+		COUNT(*) FILTER (WHERE answered_at IS NULL AND date < ?) AS overdue_count,
+		COUNT(*) FILTER (WHERE answered_at IS NOT NULL AND date >= ?) AS answered_count,
+		COUNT(*) FILTER (WHERE answered_at IS NOT NULL AND date >= ? AND EXTRACT(EPOCH FROM (answered_at - date)) <= ?) AS answered_within_sla_count,
+		COALESCE(AVG(EXTRACT(EPOCH FROM (answered_at - date)) / 60) FILTER (WHERE answered_at IS NOT NULL AND date >= ?), 0) AS average_response_minutes
+	FROM questions`,
+		shopID, shopID, time.Now().Add(-time.Duration(slaHours)*time.Hour), since, since, slaSeconds, since).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
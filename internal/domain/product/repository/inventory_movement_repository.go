@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"kedai/backend/be-kedai/internal/domain/product/dto"
+	"kedai/backend/be-kedai/internal/domain/product/model"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+type InventoryMovementRepository interface {
+	Create(tx *gorm.DB, movements []*model.InventoryMovement) error
+	GetByProductID(productID int, req *dto.StockHistoryRequest) ([]*model.InventoryMovement, int64, int, error)
+}
+
+type inventoryMovementRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type InventoryMovementRConfig struct {
+	DB *gorm.DB
+}
+
+func NewInventoryMovementRepository(cfg *InventoryMovementRConfig) InventoryMovementRepository {
+	return &inventoryMovementRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *inventoryMovementRepositoryImpl) Create(tx *gorm.DB, movements []*model.InventoryMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	err := tx.Omit("Sku").Create(&movements).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (r *inventoryMovementRepositoryImpl) GetByProductID(productID int, req *dto.StockHistoryRequest) ([]*model.InventoryMovement, int64, int, error) {
+	var (
+		movements  []*model.InventoryMovement
+		totalRows  int64
+		totalPages int
+	)
+
+	query := r.db.Model(&model.InventoryMovement{}).
+		Joins("JOIN skus ON skus.id = inventory_movements.sku_id").
+		Where("skus.product_id = ?", productID)
+
+	if req.SkuID > 0 {
+		query = query.Where("inventory_movements.sku_id = ?", req.SkuID)
+	}
+
+	if req.Type != "" {
+		query = query.Where("inventory_movements.type = ?", req.Type)
+	}
+
+	query = query.Session(&gorm.Session{})
+
+	err := query.Count(&totalRows).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages = int(math.Ceil(float64(totalRows) / float64(req.Limit)))
+
+	err = query.
+		Preload("Sku", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Select("id, sku, product_id")
+		}).
+		Preload("Sku.Variants").
+		Order("inventory_movements.date DESC, inventory_movements.id DESC").
+		Limit(req.Limit).
+		Offset(req.Offset()).
+		Find(&movements).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return movements, totalRows, totalPages, nil
+}
@@ -28,10 +28,15 @@ type ProductRepository interface {
 	GetSellerProductByCode(shopID int, productCode string) (*model.Product, error)
 	AddViewCount(productID int) error
 	UpdateActivation(shopID int, code string, isActive bool) error
-	Create(shopID int, request *dto.CreateProductRequest, courierServices []*shopModel.CourierService) (*model.Product, error)
+	Create(shopID int, request *dto.CreateProductRequest, courierServices []*shopModel.CourierService, moderation *model.ProductModeration) (*model.Product, error)
 	GetRecommended(req *dto.GetRecommendedProductRequest) ([]*dto.ProductResponse, int64, int, error)
-	Update(shopID int, code string, payload *dto.CreateProductRequest, courierServices []*shopModel.CourierService) (*model.Product, error)
+	Update(shopID int, code string, payload *dto.CreateProductRequest, courierServices []*shopModel.CourierService, moderation *model.ProductModeration) (*model.Product, error)
 	AddSoldCount(tx *gorm.DB, items []*orderDto.TransactionItem) error
+	GetCategoryAveragePrice(categoryID int) (float64, error)
+	GetModerationQueue(req *dto.ModerationQueueRequest) ([]*dto.ModerationQueueProduct, int64, int, error)
+	GetForModeration(code string) (*model.Product, error)
+	Moderate(moderation *model.ProductModeration) error
+	GetSellerEmail(shopID int) (string, error)
 }
 
 type productRepositoryImpl struct {
@@ -83,7 +88,7 @@ func (r *productRepositoryImpl) GetActiveByID(ID int) (*model.Product, error) {
 		product model.Product
 		active  = true
 	)
-	err := r.db.Where("is_active = ?", active).First(&product, ID).Error
+	err := r.db.Where("is_active = ?", active).Where("moderation_status = ?", constant.ModerationStatusApproved).First(&product, ID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errs.ErrProductDoesNotExist
@@ -105,7 +110,7 @@ func (r *productRepositoryImpl) GetByCode(code string) (*dto.ProductDetail, erro
 		Joins("left join product_promotions pp on pp.sku_id = s.id and (select count(id) from shop_promotions sp where pp.promotion_id = sp.id and now() between sp.start_period and sp.end_period) > 0").
 		Group("products.id")
 
-	err := query.Where("code = ?", code).Preload("SKU", func(query *gorm.DB) *gorm.DB {
+	err := query.Where("code = ?", code).Where("products.moderation_status = ?", constant.ModerationStatusApproved).Preload("SKU", func(query *gorm.DB) *gorm.DB {
 		return query.Select("skus.id, skus.price, skus.stock, skus.product_id")
 	}).Preload("VariantGroup.Variant").Preload("Media").Preload("Bulk").Preload("Shop.Address.Subdistrict").First(&product).Error
 	if err != nil {
@@ -148,7 +153,7 @@ func (r *productRepositoryImpl) GetRecommendationByCategory(productId int, categ
 		Joins("left join product_promotions pp on pp.sku_id = s.id and (select count(id) from shop_promotions sp where pp.promotion_id = sp.id and now() between sp.start_period and sp.end_period) > 0").
 		Group("products.id")
 
-	err := db.Where("products.category_id = ? and products.is_active = ? and products.id != ?", categoryId, isActive, productId).Limit(limit).Order("products.sold desc, products.rating desc").Find(&products).Error
+	err := db.Where("products.category_id = ? and products.is_active = ? and products.id != ?", categoryId, isActive, productId).Where("products.moderation_status = ?", constant.ModerationStatusApproved).Limit(limit).Order("products.sold desc, products.rating desc").Find(&products).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errs.ErrCategoryDoesNotExist
@@ -180,7 +185,7 @@ func (r *productRepositoryImpl) GetByShopID(shopID int, request *dto.ShopProduct
 			Where("sc.id = ?", request.ShopProductCategoryID).Where("sc.is_active = true")
 	}
 
-	query = query.Where("products.is_active = ?", active)
+	query = query.Where("products.is_active = ?", active).Where("products.moderation_status = ?", constant.ModerationStatusApproved)
 	query = query.Group("products.id")
 
 	query = query.Where("products.shop_id = ?", shopID)
@@ -245,7 +250,7 @@ func (r *productRepositoryImpl) ProductSearchFiltering(req dto.ProductSearchFilt
 		Joins("left join product_promotions pp ON pp.sku_id = s.id and (select count(id) from shop_promotions sp where pp.promotion_id = sp.id and now() between sp.start_period and sp.end_period) > 0").
 		Group("products.id, c.name, p.name")
 
-	db = db.Where("products.is_active = ?", active).Where("products.name ILIKE ?", "%"+req.Keyword+"%")
+	db = db.Where("products.is_active = ?", active).Where("products.moderation_status = ?", constant.ModerationStatusApproved).Where("products.name ILIKE ?", "%"+req.Keyword+"%")
 
 	if req.CategoryId > 0 {
 		db = db.Where("products.category_id IN ?", categoryIDs)
@@ -329,6 +334,9 @@ func (r *productRepositoryImpl) GetBySellerID(shopID int, request *dto.SellerPro
 	if request.Stock > 0 {
 		query = query.Having("SUM(skus.stock) >= ?", request.Stock)
 	}
+	if request.ModerationStatus != "" {
+		query = query.Where("products.moderation_status = ?", request.ModerationStatus)
+	}
 
 	switch request.Status {
 	case constant.ProductStatusLive:
@@ -431,7 +439,7 @@ func (r *productRepositoryImpl) SearchAutocomplete(req dto.ProductSearchAutocomp
 
 	db := r.db.Select(`products.*, (select url from product_medias pm where products.id = pm.product_id limit 1) as image_url`)
 
-	db = db.Where("products.is_active = ?", active).Where("products.name ILIKE ?", "%"+req.Keyword+"%").Order("products.rating desc")
+	db = db.Where("products.is_active = ?", active).Where("products.moderation_status = ?", constant.ModerationStatusApproved).Where("products.name ILIKE ?", "%"+req.Keyword+"%").Order("products.rating desc")
 
 	err := db.Limit(req.Limit).Find(&products).Error
 	if err != nil {
@@ -467,6 +475,7 @@ func (r *productRepositoryImpl) AddViewCount(productID int) error {
 		Model(&model.Product{}).
 		Where("id = ?", productID).
 		Where("is_active = ?", true).
+		Where("moderation_status = ?", constant.ModerationStatusApproved).
 		Update("view", gorm.Expr("view + ?", 1))
 
 	if res.Error != nil {
@@ -481,7 +490,21 @@ func (r *productRepositoryImpl) AddViewCount(productID int) error {
 }
 
 func (r *productRepositoryImpl) UpdateActivation(shopID int, code string, isActive bool) error {
-	res := r.db.Model(&model.Product{}).Where("code = ?", code).Where("shop_id = ?", shopID).Update("is_active", isActive)
+	var product model.Product
+	err := r.db.Select("id", "moderation_status").Where("code = ?", code).Where("shop_id = ?", shopID).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrProductDoesNotExist
+		}
+
+		return err
+	}
+
+	if isActive && product.ModerationStatus == constant.ModerationStatusBanned {
+		return errs.ErrProductBanned
+	}
+
+	res := r.db.Model(&product).Update("is_active", isActive)
 
 	if res.Error != nil {
 		return res.Error
@@ -494,13 +517,16 @@ func (r *productRepositoryImpl) UpdateActivation(shopID int, code string, isActi
 	return nil
 }
 
-func (r *productRepositoryImpl) Create(shopID int, request *dto.CreateProductRequest, courierServices []*shopModel.CourierService) (*model.Product, error) {
+func (r *productRepositoryImpl) Create(shopID int, request *dto.CreateProductRequest, courierServices []*shopModel.CourierService, moderation *model.ProductModeration) (*model.Product, error) {
 	tx := r.db.Begin()
 	defer tx.Commit()
 
 	product := request.GenerateProduct()
 	product.ShopID = shopID
 	product.CourierService = courierServices
+	product.ModerationStatus = moderation.Status
+	product.ModerationReason = moderation.Reason
+	product.ModerationFlags = moderation.Flags
 
 	err := tx.Create(product).Error
 	if err != nil {
@@ -508,6 +534,13 @@ func (r *productRepositoryImpl) Create(shopID int, request *dto.CreateProductReq
 		return nil, err
 	}
 
+	moderation.ProductID = product.ID
+	err = tx.Create(moderation).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	variantGroups := request.GenerateVariantGroups()
 	for _, vg := range variantGroups {
 		vg.ProductID = product.ID
@@ -574,6 +607,7 @@ func (r *productRepositoryImpl) GetRecommended(req *dto.GetRecommendedProductReq
 		Joins("join provinces p ON p.id = c.province_id").
 		Joins("left join product_promotions pp on pp.sku_id = s.id and (select count(id) from shop_promotions sp where pp.promotion_id = sp.id and now() between sp.start_period and sp.end_period) > 0").
 		Where("products.is_active = ?", isActive).
+		Where("products.moderation_status = ?", constant.ModerationStatusApproved).
 		Group("products.id,c.name,p.name")
 
 	errCount := db.Model(&model.Product{}).Count(&totalRows).Error
@@ -596,7 +630,7 @@ func (r *productRepositoryImpl) GetRecommended(req *dto.GetRecommendedProductReq
 	return recommendedProducts, totalRows, totalPages, nil
 }
 
-func (r *productRepositoryImpl) Update(shopID int, code string, payload *dto.CreateProductRequest, courierServices []*shopModel.CourierService) (*model.Product, error) {
+func (r *productRepositoryImpl) Update(shopID int, code string, payload *dto.CreateProductRequest, courierServices []*shopModel.CourierService, moderation *model.ProductModeration) (*model.Product, error) {
 
 	tx := r.db.Begin()
 	defer tx.Commit()
@@ -606,6 +640,10 @@ func (r *productRepositoryImpl) Update(shopID int, code string, payload *dto.Cre
 		return nil, err
 	}
 
+	if product.ModerationStatus == constant.ModerationStatusBanned {
+		return nil, errs.ErrProductBanned
+	}
+
 	updatedProduct := payload.GenerateProduct()
 	updatedProduct.Code = product.Code
 	updatedProduct.ID = product.ID
@@ -615,6 +653,9 @@ func (r *productRepositoryImpl) Update(shopID int, code string, payload *dto.Cre
 	updatedProduct.CreatedAt = product.CreatedAt
 	updatedProduct.Rating = product.Rating
 	updatedProduct.Sold = product.Sold
+	updatedProduct.ModerationStatus = moderation.Status
+	updatedProduct.ModerationReason = moderation.Reason
+	updatedProduct.ModerationFlags = moderation.Flags
 
 	if payload.BulkPrice == nil {
 		errBulk := tx.Where("product_id=?", product.ID).Delete(&model.ProductBulkPrice{}).Error
@@ -710,5 +751,138 @@ func (r *productRepositoryImpl) Update(shopID int, code string, payload *dto.Cre
 		return nil, err
 	}
 
+	moderation.ProductID = product.ID
+	err = tx.Create(moderation).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	return updatedProduct, nil
 }
+
+func (r *productRepositoryImpl) GetCategoryAveragePrice(categoryID int) (float64, error) {
+	var averagePrice float64
+
+	err := r.db.Table("skus").
+		Select("COALESCE(AVG(skus.price), 0)").
+		Joins("JOIN products ON products.id = skus.product_id AND products.deleted_at IS NULL").
+		Where("products.category_id = ?", categoryID).
+		Where("products.moderation_status = ?", constant.ModerationStatusApproved).
+		Where("skus.deleted_at IS NULL").
+		Scan(&averagePrice).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return averagePrice, nil
+}
+
+func (r *productRepositoryImpl) GetModerationQueue(req *dto.ModerationQueueRequest) ([]*dto.ModerationQueueProduct, int64, int, error) {
+	var (
+		products   []*dto.ModerationQueueProduct
+		totalRows  int64
+		totalPages int
+	)
+
+	query := r.db.
+		Select(`products.*,
+		MIN(skus.price) AS min_price,
+		MAX(skus.price) AS max_price,
+		(SELECT url FROM product_medias pm WHERE pm.product_id = products.id LIMIT 1) AS image_url
+	`).
+		Joins("JOIN skus ON skus.product_id = products.id AND skus.deleted_at IS NULL").
+		Where("products.moderation_status = ?", req.Status).
+		Group("products.id")
+
+	if req.Name != "" {
+		query = query.Where("products.name ILIKE ?", fmt.Sprintf("%%%s%%", req.Name))
+	}
+
+	query = query.Session(&gorm.Session{})
+
+	err := query.Model(&model.Product{}).Distinct("products.id").Count(&totalRows).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	totalPages = int(math.Ceil(float64(totalRows) / float64(req.Limit)))
+
+	err = query.Preload("Shop").Order("products.updated_at ASC").Limit(req.Limit).Offset(req.Offset()).Find(&products).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return products, totalRows, totalPages, nil
+}
+
+func (r *productRepositoryImpl) GetForModeration(code string) (*model.Product, error) {
+	var product model.Product
+
+	err := r.db.
+		Where("code = ?", code).
+		Preload("Shop").
+		Preload("Bulk").
+		Preload("Media").
+		Preload("VariantGroup.Variant").
+		Preload("SKUs.Variants").
+		Preload("Moderations", func(query *gorm.DB) *gorm.DB {
+			return query.Order("date DESC")
+		}).
+		First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrProductDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+func (r *productRepositoryImpl) Moderate(moderation *model.ProductModeration) error {
+	tx := r.db.Begin()
+	defer tx.Commit()
+
+	updates := map[string]interface{}{
+		"moderation_status": moderation.Status,
+		"moderation_reason": moderation.Reason,
+	}
+	if moderation.Status == constant.ModerationStatusBanned {
+		updates["is_active"] = false
+	}
+
+	res := tx.Model(&model.Product{}).Where("id = ?", moderation.ProductID).Updates(updates)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return errs.ErrProductDoesNotExist
+	}
+
+	err := tx.Create(moderation).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (r *productRepositoryImpl) GetSellerEmail(shopID int) (string, error) {
+	var email string
+
+	err := r.db.Table("shops").
+		Select("users.email").
+		Joins("JOIN users ON users.id = shops.user_id").
+		Where("shops.id = ?", shopID).
+		Scan(&email).Error
+	if err != nil {
+		return "", err
+	}
+
+	return email, nil
+}
@@ -3,7 +3,9 @@ package repository
 import (
 	"errors"
 	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/domain/product/model"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -13,26 +15,34 @@ type SkuRepository interface {
 	GetByID(ID int) (*model.Sku, error)
 	GetByVariantIDs(variantIDs []int) (*model.Sku, error)
 	GetByProductId(productID int) ([]*model.Sku, error)
-	ReduceStock(tx *gorm.DB, skuID int, quantity int) error
-	IncreaseStock(tx *gorm.DB, skuID int, quantity int) error
+	GetBySellerProductCode(shopID int, productCode string) ([]*model.Sku, error)
+	GetLowStockByShopID(shopID int) ([]*dto.LowStockSku, error)
+	GetUnnotifiedLowStock() ([]*dto.LowStockAlert, error)
+	ReduceStock(tx *gorm.DB, skuID int, quantity int, movementType string) error
+	IncreaseStock(tx *gorm.DB, skuID int, quantity int, movementType string) error
 	Create(tx *gorm.DB, skus []*model.Sku) error
 	Update(tx *gorm.DB, productId int, skus []*model.Sku) error
+	UpdateLowStockThresholds(productID int, thresholds []*dto.SkuLowStockThreshold) error
+	MarkLowStockNotified(skuIDs []int) error
 }
 
 type skuRepositoryImpl struct {
-	db                         *gorm.DB
-	productPromotionRepository ProductPromotionRepository
+	db                          *gorm.DB
+	productPromotionRepository  ProductPromotionRepository
+	inventoryMovementRepository InventoryMovementRepository
 }
 
 type SkuRConfig struct {
-	DB                         *gorm.DB
-	ProductPromotionRepository ProductPromotionRepository
+	DB                          *gorm.DB
+	ProductPromotionRepository  ProductPromotionRepository
+	InventoryMovementRepository InventoryMovementRepository
 }
 
 func NewSkuRepository(cfg *SkuRConfig) SkuRepository {
 	return &skuRepositoryImpl{
-		db:                         cfg.DB,
-		productPromotionRepository: cfg.ProductPromotionRepository,
+		db:                          cfg.DB,
+		productPromotionRepository:  cfg.ProductPromotionRepository,
+		inventoryMovementRepository: cfg.InventoryMovementRepository,
 	}
 }
 
@@ -81,8 +91,66 @@ func (r *skuRepositoryImpl) GetByVariantIDs(variantIDs []int) (*model.Sku, error
 	return &sku, nil
 }
 
-func (r *skuRepositoryImpl) ReduceStock(tx *gorm.DB, skuID int, quantity int) error {
-	err := tx.Model(&model.Sku{}).
+func (r *skuRepositoryImpl) GetBySellerProductCode(shopID int, productCode string) ([]*model.Sku, error) {
+	var skus []*model.Sku
+
+	err := r.db.
+		Joins("JOIN products ON products.id = skus.product_id").
+		Where("products.shop_id = ? AND products.code = ?", shopID, productCode).
+		Find(&skus).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(skus) == 0 {
+		return nil, errs.ErrProductDoesNotExist
+	}
+
+	return skus, nil
+}
+
+func (r *skuRepositoryImpl) GetLowStockByShopID(shopID int) ([]*dto.LowStockSku, error) {
+	var skus []*dto.LowStockSku
+
+	err := r.db.Model(&model.Sku{}).
+		Select(`skus.id, skus.sku, skus.stock, skus.low_stock_threshold,
+		products.id AS product_id, products.code AS product_code, products.name AS product_name,
+		(SELECT url FROM product_medias pm WHERE pm.product_id = products.id LIMIT 1) AS image_url`).
+		Joins("JOIN products ON products.id = skus.product_id AND products.deleted_at IS NULL").
+		Where("products.shop_id = ?", shopID).
+		Where("skus.low_stock_threshold IS NOT NULL AND skus.stock <= skus.low_stock_threshold").
+		Order("skus.stock ASC").
+		Find(&skus).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return skus, nil
+}
+
+func (r *skuRepositoryImpl) GetUnnotifiedLowStock() ([]*dto.LowStockAlert, error) {
+	var alerts []*dto.LowStockAlert
+
+	err := r.db.Model(&model.Sku{}).
+		Select("skus.id, skus.sku, skus.stock, skus.low_stock_threshold, products.name AS product_name, users.email").
+		Joins("JOIN products ON products.id = skus.product_id AND products.deleted_at IS NULL").
+		Joins("JOIN shops ON shops.id = products.shop_id").
+		Joins("JOIN users ON users.id = shops.user_id").
+		Where("skus.low_stock_threshold IS NOT NULL AND skus.stock <= skus.low_stock_threshold").
+		Where("skus.low_stock_notified_at IS NULL").
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (r *skuRepositoryImpl) ReduceStock(tx *gorm.DB, skuID int, quantity int, movementType string) error {
+	var sku model.Sku
+
+	err := tx.Model(&sku).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "stock"}}}).
 		Where("id = ?", skuID).
 		Where("stock >= ?", quantity).
 		Update("stock", gorm.Expr("stock - ?", quantity))
@@ -96,19 +164,39 @@ func (r *skuRepositoryImpl) ReduceStock(tx *gorm.DB, skuID int, quantity int) er
 		return errs.ErrProductQuantityNotEnough
 	}
 
-	return nil
+	return r.inventoryMovementRepository.Create(tx, []*model.InventoryMovement{{
+		SkuId:      skuID,
+		Type:       movementType,
+		Quantity:   -quantity,
+		StockAfter: sku.Stock,
+	}})
 }
 
-func (r *skuRepositoryImpl) IncreaseStock(tx *gorm.DB, skuID int, quantity int) error {
-	err := tx.Model(&model.Sku{}).
+func (r *skuRepositoryImpl) IncreaseStock(tx *gorm.DB, skuID int, quantity int, movementType string) error {
+	var sku model.Sku
+
+	err := tx.Model(&sku).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "stock"}}}).
 		Where("id = ?", skuID).
-		Update("stock", gorm.Expr("stock + ?", quantity))
+		Updates(map[string]interface{}{
+			"stock":                 gorm.Expr("stock + ?", quantity),
+			"low_stock_notified_at": gorm.Expr("CASE WHEN stock + ? > low_stock_threshold THEN NULL ELSE low_stock_notified_at END", quantity),
+		})
 	if err.Error != nil {
 		tx.Rollback()
 		return err.Error
 	}
 
-	return nil
+	if err.RowsAffected == 0 {
+		return nil
+	}
+
+	return r.inventoryMovementRepository.Create(tx, []*model.InventoryMovement{{
+		SkuId:      skuID,
+		Type:       movementType,
+		Quantity:   quantity,
+		StockAfter: sku.Stock,
+	}})
 }
 
 func (r *skuRepositoryImpl) Create(tx *gorm.DB, skus []*model.Sku) error {
@@ -122,7 +210,21 @@ func (r *skuRepositoryImpl) Create(tx *gorm.DB, skus []*model.Sku) error {
 		return errs.ErrSKUUsed
 	}
 
-	return nil
+	var movements []*model.InventoryMovement
+	for _, sku := range skus {
+		if sku.Stock == 0 {
+			continue
+		}
+
+		movements = append(movements, &model.InventoryMovement{
+			SkuId:      sku.ID,
+			Type:       model.InventoryMovementTypeImport,
+			Quantity:   sku.Stock,
+			StockAfter: sku.Stock,
+		})
+	}
+
+	return r.inventoryMovementRepository.Create(tx, movements)
 }
 
 func (r *skuRepositoryImpl) GetByProductId(productID int) ([]*model.Sku, error) {
@@ -138,18 +240,36 @@ func (r *skuRepositoryImpl) GetByProductId(productID int) ([]*model.Sku, error)
 	return skus, nil
 }
 
+// Update locks the product's SKUs within tx before diffing them against
+// skus, so concurrent orders cannot change a stock the delta is based on.
 func (r *skuRepositoryImpl) Update(tx *gorm.DB, productId int, skus []*model.Sku) error {
-	retrievedSkus, err := r.GetByProductId(productId)
+	var retrievedSkus []*model.Sku
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ?", productId).
+		Preload("Variants").
+		Find(&retrievedSkus).Error
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	var union []*model.Sku
+	var (
+		union      []*model.Sku
+		stockDelta = map[*model.Sku]int{}
+	)
 
 	for _, sku := range retrievedSkus {
 		found := false
 		for _, newSku := range skus {
 			if sku.Sku == newSku.Sku {
+				if delta := newSku.Stock - sku.Stock; delta != 0 {
+					stockDelta[sku] = delta
+				}
+
+				if sku.LowStockThreshold != nil && newSku.Stock > *sku.LowStockThreshold {
+					sku.LowStockNotifiedAt = nil
+				}
+
 				sku.Stock = newSku.Stock
 				sku.Price = newSku.Price
 				sku.Variants = newSku.Variants
@@ -196,6 +316,11 @@ func (r *skuRepositoryImpl) Update(tx *gorm.DB, productId int, skus []*model.Sku
 		return nil
 	}
 
+	isNew := map[*model.Sku]bool{}
+	for _, sku := range union {
+		isNew[sku] = sku.ID == 0
+	}
+
 	err = tx.Clauses(clause.OnConflict{
 		OnConstraint: ("skus_sku_key"),
 		UpdateAll:    true,
@@ -207,5 +332,57 @@ func (r *skuRepositoryImpl) Update(tx *gorm.DB, productId int, skus []*model.Sku
 		return err
 	}
 
-	return nil
+	var movements []*model.InventoryMovement
+	for _, sku := range union {
+		if isNew[sku] && sku.Stock > 0 {
+			movements = append(movements, &model.InventoryMovement{
+				SkuId:      sku.ID,
+				Type:       model.InventoryMovementTypeImport,
+				Quantity:   sku.Stock,
+				StockAfter: sku.Stock,
+			})
+			continue
+		}
+
+		if delta, ok := stockDelta[sku]; ok {
+			movements = append(movements, &model.InventoryMovement{
+				SkuId:      sku.ID,
+				Type:       model.InventoryMovementTypeManualAdjust,
+				Quantity:   delta,
+				StockAfter: sku.Stock,
+			})
+		}
+	}
+
+	return r.inventoryMovementRepository.Create(tx, movements)
+}
+
+func (r *skuRepositoryImpl) UpdateLowStockThresholds(productID int, thresholds []*dto.SkuLowStockThreshold) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, threshold := range thresholds {
+			res := tx.Model(&model.Sku{}).
+				Where("id = ? AND product_id = ?", threshold.SkuID, productID).
+				Updates(map[string]interface{}{
+					"low_stock_threshold":   threshold.Threshold,
+					"low_stock_notified_at": nil,
+				})
+			if res.Error != nil {
+				return res.Error
+			}
+
+			if res.RowsAffected == 0 {
+				return errs.ErrSKUDoesNotExist
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *skuRepositoryImpl) MarkLowStockNotified(skuIDs []int) error {
+	if len(skuIDs) == 0 {
+		return nil
+	}
+
+	return r.db.Model(&model.Sku{}).Where("id IN ?", skuIDs).Update("low_stock_notified_at", time.Now()).Error
 }
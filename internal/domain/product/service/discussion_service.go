@@ -1,33 +1,51 @@
 package service
 
 import (
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/domain/product/repository"
 	"kedai/backend/be-kedai/internal/domain/shop/service"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"log"
+	"time"
 )
 
 type DiscussionService interface {
 	GetDiscussionByProductID(productID int, req dto.GetDiscussionReq) (*commonDto.PaginationResponse, error)
 	GetUnrepliedDiscussionByShopID(userID int, req dto.GetDiscussionReq) (*commonDto.PaginationResponse, error)
-	GetChildDiscussionByParentID(parentID int) ([]*dto.DiscussionReply, error)
+	GetChildDiscussionByParentID(parentID int, req dto.GetDiscussionReq) (*commonDto.PaginationResponse, error)
 	PostDiscussion(discussion *dto.DiscussionReq) error
+	VoteDiscussion(userID int, discussionID int) error
+	UnvoteDiscussion(userID int, discussionID int) error
+	ReportDiscussion(userID int, discussionID int, req *dto.ReportDiscussionReq) error
+	UpdateDiscussionVisibility(discussionID int, req *dto.UpdateDiscussionVisibilityReq) error
+	MarkOfficialAnswer(userID int, discussionID int) error
+	GetDiscussionSLAStats(userID int) (*dto.DiscussionSLAStats, error)
 }
 
 type discussionServiceImpl struct {
-	discussionRepository repository.DiscussionRepository
-	shopService          service.ShopService
+	discussionRepository  repository.DiscussionRepository
+	shopService           service.ShopService
+	mailUtils             mail.MailUtils
+	notificationPublisher notificationService.NotificationPublisher
 }
 
 type DiscussionSConfig struct {
-	DiscussionRepository repository.DiscussionRepository
-	ShopService          service.ShopService
+	DiscussionRepository  repository.DiscussionRepository
+	ShopService           service.ShopService
+	MailUtils             mail.MailUtils
+	NotificationPublisher notificationService.NotificationPublisher
 }
 
 func NewDiscussionService(cfg *DiscussionSConfig) DiscussionService {
 	return &discussionServiceImpl{
-		discussionRepository: cfg.DiscussionRepository,
-		shopService:          cfg.ShopService,
+		discussionRepository:  cfg.DiscussionRepository,
+		shopService:           cfg.ShopService,
+		mailUtils:             cfg.MailUtils,
+		notificationPublisher: cfg.NotificationPublisher,
 	}
 }
 
@@ -68,19 +86,122 @@ func (d *discussionServiceImpl) GetUnrepliedDiscussionByShopID(userID int, req d
 	return &res, nil
 }
 
-func (d *discussionServiceImpl) GetChildDiscussionByParentID(parentID int) ([]*dto.DiscussionReply, error) {
-	return d.discussionRepository.GetChildDiscussionByParentID(parentID)
+func (d *discussionServiceImpl) GetChildDiscussionByParentID(parentID int, req dto.GetDiscussionReq) (*commonDto.PaginationResponse, error) {
+	data, limit, page, totalRows, totalPages, err := d.discussionRepository.GetChildDiscussionByParentID(parentID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var res commonDto.PaginationResponse
+	res.Data = data
+	res.Limit = limit
+	res.Page = page
+	res.TotalRows = int64(totalRows)
+	res.TotalPages = totalPages
+
+	return &res, nil
 }
 
 func (d *discussionServiceImpl) PostDiscussion(discussion *dto.DiscussionReq) error {
-
+	var shopName string
 	if discussion.IsSeller {
 		shop, err := d.shopService.FindShopByUserId(discussion.UserID)
 		if err != nil {
 			return err
 		}
 		discussion.ShopID = &shop.ID
+		shopName = shop.Name
+	}
+
+	err := d.discussionRepository.PostDiscussion(discussion)
+	if err != nil {
+		return err
+	}
+
+	if discussion.ShopID != nil && discussion.ParentID != nil {
+		d.notifyAsker(*discussion.ParentID, shopName, discussion)
+	}
+
+	return nil
+}
+
+func (d *discussionServiceImpl) notifyAsker(parentID int, shopName string, reply *dto.DiscussionReq) {
+	parent, err := d.discussionRepository.GetByID(parentID)
+	if err != nil {
+		log.Println("failed to get discussion", parentID, "for reply notification:", err)
+		return
+	}
+
+	if parent.UserID == reply.UserID || parent.User == nil || parent.Product == nil {
+		return
+	}
+
+	err = d.mailUtils.SendDiscussionReplyEmail(parent.User.Email, parent.Product.Name, shopName, reply.Message)
+	if err != nil {
+		log.Println("failed to send reply notification for discussion", parentID, ":", err)
+	}
+
+	if d.notificationPublisher == nil {
+		return
+	}
+
+	err = d.notificationPublisher.Publish(&notificationDto.NotificationEvent{
+		UserID: parent.UserID,
+		Type:   constant.NotificationTypeDiscussionReply,
+		Data: map[string]interface{}{
+			"discussionId": parentID,
+			"productId":    parent.Product.ID,
+			"productName":  parent.Product.Name,
+			"shopName":     shopName,
+			"message":      reply.Message,
+		},
+	})
+	if err != nil {
+		log.Println("failed to publish reply notification for discussion", parentID, ":", err)
+	}
+}
+
+func (d *discussionServiceImpl) VoteDiscussion(userID int, discussionID int) error {
+	return d.discussionRepository.Vote(discussionID, userID)
+}
+
+func (d *discussionServiceImpl) UnvoteDiscussion(userID int, discussionID int) error {
+	return d.discussionRepository.Unvote(discussionID, userID)
+}
+
+func (d *discussionServiceImpl) ReportDiscussion(userID int, discussionID int, req *dto.ReportDiscussionReq) error {
+	return d.discussionRepository.Report(req.ToDiscussionReport(discussionID, userID), constant.DiscussionReportHideThreshold)
+}
+
+func (d *discussionServiceImpl) UpdateDiscussionVisibility(discussionID int, req *dto.UpdateDiscussionVisibilityReq) error {
+	return d.discussionRepository.UpdateVisibility(discussionID, *req.IsHidden)
+}
+
+func (d *discussionServiceImpl) MarkOfficialAnswer(userID int, discussionID int) error {
+	shop, err := d.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return err
+	}
+
+	return d.discussionRepository.MarkOfficial(discussionID, shop.ID)
+}
+
+func (d *discussionServiceImpl) GetDiscussionSLAStats(userID int) (*dto.DiscussionSLAStats, error) {
+	shop, err := d.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -constant.DiscussionSLAWindowDays)
+	stats, err := d.discussionRepository.GetSLAStats(shop.ID, constant.DiscussionAnswerSLAHours, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.SLAHours = constant.DiscussionAnswerSLAHours
+	if measured := stats.AnsweredCount + stats.OverdueCount; measured > 0 {
+		stats.SLAComplianceRate = float64(stats.AnsweredWithinSLACount) / float64(measured)
 	}
 
-	return d.discussionRepository.PostDiscussion(discussion)
+	return stats, nil
 }
@@ -1,15 +1,21 @@
 package service_test
 
 import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errorResponse "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
+	productModel "kedai/backend/be-kedai/internal/domain/product/model"
 	"kedai/backend/be-kedai/internal/domain/product/service"
 	"kedai/backend/be-kedai/internal/domain/shop/model"
+	userModel "kedai/backend/be-kedai/internal/domain/user/model"
 	"kedai/backend/be-kedai/mocks"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetDisscussionByProductID(t *testing.T) {
@@ -111,17 +117,29 @@ func TestGetDisscussionByProductID(t *testing.T) {
 
 func TestGetChildDiscussionByParentID(t *testing.T) {
 	var (
-		parentID   = 1
-		discussion = []*dto.DiscussionReply{}
+		parentID = 1
+		req      = dto.GetDiscussionReq{
+			Limit: 5,
+			Page:  1,
+		}
+		data       = []*dto.DiscussionReply{{ID: 2, ParentID: parentID, IsOfficial: true}}
+		discussion = &commonDto.PaginationResponse{
+			Data:       data,
+			Limit:      5,
+			Page:       1,
+			TotalRows:  1,
+			TotalPages: 1,
+		}
 	)
 
 	type input struct {
 		parentID int
+		data     []*dto.DiscussionReply
 		err      error
 	}
 
 	type expected struct {
-		discussion []*dto.DiscussionReply
+		discussion *commonDto.PaginationResponse
 		err        error
 	}
 
@@ -133,9 +151,10 @@ func TestGetChildDiscussionByParentID(t *testing.T) {
 
 	for _, tc := range []cases{
 		{
-			description: "should return discussion when success",
+			description: "should return paginated replies when success",
 			input: input{
 				parentID: parentID,
+				data:     data,
 				err:      nil,
 			},
 			expected: expected{
@@ -147,6 +166,7 @@ func TestGetChildDiscussionByParentID(t *testing.T) {
 			description: "should return error when failed",
 			input: input{
 				parentID: parentID,
+				data:     nil,
 				err:      errorResponse.ErrInternalServerError,
 			},
 			expected: expected{
@@ -157,13 +177,13 @@ func TestGetChildDiscussionByParentID(t *testing.T) {
 	} {
 		t.Run(tc.description, func(t *testing.T) {
 			mockDiscussionRepository := new(mocks.DiscussionRepository)
-			mockDiscussionRepository.On("GetChildDiscussionByParentID", tc.input.parentID).Return(tc.expected.discussion, tc.input.err)
+			mockDiscussionRepository.On("GetChildDiscussionByParentID", tc.input.parentID, req).Return(tc.input.data, req.Limit, req.Page, len(tc.input.data), len(tc.input.data), tc.input.err)
 
 			discussionService := service.NewDiscussionService(&service.DiscussionSConfig{
 				DiscussionRepository: mockDiscussionRepository,
 			})
 
-			discussion, err := discussionService.GetChildDiscussionByParentID(tc.input.parentID)
+			discussion, err := discussionService.GetChildDiscussionByParentID(tc.input.parentID, req)
 			assert.Equal(t, tc.expected.err, err)
 			assert.Equal(t, tc.expected.discussion, discussion)
 		})
@@ -250,3 +270,313 @@ func TestPostDiscussion(t *testing.T) {
 	}
 
 }
+
+func TestPostDiscussionReplyNotification(t *testing.T) {
+	var (
+		parentID = 1
+		shop     = &model.Shop{ID: 1, Name: "Kedai Shop"}
+		parent   = &dto.Discussion{
+			ID:      parentID,
+			UserID:  2,
+			User:    &userModel.User{ID: 2, Email: "asker@mail.com"},
+			Product: &productModel.Product{ID: 1, Name: "Product"},
+		}
+	)
+
+	type input struct {
+		discussion *dto.DiscussionReq
+		beforeTest func(*mocks.DiscussionRepository, *mocks.MailUtils, *mocks.NotificationPublisher)
+	}
+
+	tests := []struct {
+		description string
+		input
+		expected error
+	}{
+		{
+			description: "should notify asker when shop replies to a question",
+			input: input{
+				discussion: &dto.DiscussionReq{ProductID: 1, UserID: 1, Message: "ready stock", ParentID: &parentID, IsSeller: true},
+				beforeTest: func(dr *mocks.DiscussionRepository, mu *mocks.MailUtils, np *mocks.NotificationPublisher) {
+					dr.On("PostDiscussion", mock.Anything).Return(nil)
+					dr.On("GetByID", parentID).Return(parent, nil)
+					mu.On("SendDiscussionReplyEmail", parent.User.Email, parent.Product.Name, shop.Name, "ready stock").Return(nil)
+					np.On("Publish", &notificationDto.NotificationEvent{
+						UserID: parent.UserID,
+						Type:   constant.NotificationTypeDiscussionReply,
+						Data: map[string]interface{}{
+							"discussionId": parentID,
+							"productId":    parent.Product.ID,
+							"productName":  parent.Product.Name,
+							"shopName":     shop.Name,
+							"message":      "ready stock",
+						},
+					}).Return(nil)
+				},
+			},
+			expected: nil,
+		},
+		{
+			description: "should not fail reply when notification email fails",
+			input: input{
+				discussion: &dto.DiscussionReq{ProductID: 1, UserID: 1, Message: "ready stock", ParentID: &parentID, IsSeller: true},
+				beforeTest: func(dr *mocks.DiscussionRepository, mu *mocks.MailUtils, np *mocks.NotificationPublisher) {
+					dr.On("PostDiscussion", mock.Anything).Return(nil)
+					dr.On("GetByID", parentID).Return(parent, nil)
+					mu.On("SendDiscussionReplyEmail", parent.User.Email, parent.Product.Name, shop.Name, "ready stock").Return(errors.New("failed to send email"))
+					np.On("Publish", mock.Anything).Return(errors.New("error"))
+				},
+			},
+			expected: nil,
+		},
+		{
+			description: "should not notify when reply is not from shop",
+			input: input{
+				discussion: &dto.DiscussionReq{ProductID: 1, UserID: 3, Message: "me too", ParentID: &parentID},
+				beforeTest: func(dr *mocks.DiscussionRepository, mu *mocks.MailUtils, np *mocks.NotificationPublisher) {
+					dr.On("PostDiscussion", mock.Anything).Return(nil)
+				},
+			},
+			expected: nil,
+		},
+		{
+			description: "should return error when parent discussion does not exist",
+			input: input{
+				discussion: &dto.DiscussionReq{ProductID: 1, UserID: 1, Message: "ready stock", ParentID: &parentID, IsSeller: true},
+				beforeTest: func(dr *mocks.DiscussionRepository, mu *mocks.MailUtils, np *mocks.NotificationPublisher) {
+					dr.On("PostDiscussion", mock.Anything).Return(errorResponse.ErrDiscussionNotFound)
+				},
+			},
+			expected: errorResponse.ErrDiscussionNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockDiscussionRepository := mocks.NewDiscussionRepository(t)
+			mockMailUtils := mocks.NewMailUtils(t)
+			mockNotificationPublisher := mocks.NewNotificationPublisher(t)
+			mockShopService := new(mocks.ShopService)
+			mockShopService.On("FindShopByUserId", 1).Return(shop, nil)
+			tc.input.beforeTest(mockDiscussionRepository, mockMailUtils, mockNotificationPublisher)
+
+			discussionService := service.NewDiscussionService(&service.DiscussionSConfig{
+				DiscussionRepository:  mockDiscussionRepository,
+				ShopService:           mockShopService,
+				MailUtils:             mockMailUtils,
+				NotificationPublisher: mockNotificationPublisher,
+			})
+
+			err := discussionService.PostDiscussion(tc.input.discussion)
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestVoteDiscussion(t *testing.T) {
+	var (
+		userID       = 1
+		discussionID = 1
+	)
+
+	tests := []struct {
+		description string
+		err         error
+	}{
+		{
+			description: "should return error when discussion already voted",
+			err:         errorResponse.ErrDiscussionAlreadyVoted,
+		},
+		{
+			description: "should return nil when vote recorded",
+			err:         nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockDiscussionRepository := mocks.NewDiscussionRepository(t)
+			mockDiscussionRepository.On("Vote", discussionID, userID).Return(tc.err)
+
+			discussionService := service.NewDiscussionService(&service.DiscussionSConfig{
+				DiscussionRepository: mockDiscussionRepository,
+			})
+
+			err := discussionService.VoteDiscussion(userID, discussionID)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestReportDiscussion(t *testing.T) {
+	var (
+		userID       = 1
+		discussionID = 1
+		req          = &dto.ReportDiscussionReq{Reason: "spam"}
+		report       = &productModel.DiscussionReport{Reason: req.Reason, DiscussionID: discussionID, UserID: userID}
+	)
+
+	tests := []struct {
+		description string
+		err         error
+	}{
+		{
+			description: "should return error when discussion already reported by user",
+			err:         errorResponse.ErrDiscussionAlreadyReported,
+		},
+		{
+			description: "should return nil when report recorded",
+			err:         nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockDiscussionRepository := mocks.NewDiscussionRepository(t)
+			mockDiscussionRepository.On("Report", report, constant.DiscussionReportHideThreshold).Return(tc.err)
+
+			discussionService := service.NewDiscussionService(&service.DiscussionSConfig{
+				DiscussionRepository: mockDiscussionRepository,
+			})
+
+			err := discussionService.ReportDiscussion(userID, discussionID, req)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestMarkOfficialAnswer(t *testing.T) {
+	var (
+		userID       = 1
+		discussionID = 2
+		shop         = &model.Shop{ID: 1}
+	)
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.ShopService, *mocks.DiscussionRepository)
+		expected    error
+	}{
+		{
+			description: "should return error when shop not found",
+			beforeTest: func(ss *mocks.ShopService, dr *mocks.DiscussionRepository) {
+				ss.On("FindShopByUserId", userID).Return(nil, errorResponse.ErrShopNotFound)
+			},
+			expected: errorResponse.ErrShopNotFound,
+		},
+		{
+			description: "should return error when discussion is not the shop's reply",
+			beforeTest: func(ss *mocks.ShopService, dr *mocks.DiscussionRepository) {
+				ss.On("FindShopByUserId", userID).Return(shop, nil)
+				dr.On("MarkOfficial", discussionID, shop.ID).Return(errorResponse.ErrInvalidOfficialAnswer)
+			},
+			expected: errorResponse.ErrInvalidOfficialAnswer,
+		},
+		{
+			description: "should return nil when answer marked as official",
+			beforeTest: func(ss *mocks.ShopService, dr *mocks.DiscussionRepository) {
+				ss.On("FindShopByUserId", userID).Return(shop, nil)
+				dr.On("MarkOfficial", discussionID, shop.ID).Return(nil)
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopService := mocks.NewShopService(t)
+			mockDiscussionRepository := mocks.NewDiscussionRepository(t)
+			tc.beforeTest(mockShopService, mockDiscussionRepository)
+
+			discussionService := service.NewDiscussionService(&service.DiscussionSConfig{
+				DiscussionRepository: mockDiscussionRepository,
+				ShopService:          mockShopService,
+			})
+
+			err := discussionService.MarkOfficialAnswer(userID, discussionID)
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestGetDiscussionSLAStats(t *testing.T) {
+	var (
+		userID = 1
+		shop   = &model.Shop{ID: 1}
+	)
+
+	type expected struct {
+		stats *dto.DiscussionSLAStats
+		err   error
+	}
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.ShopService, *mocks.DiscussionRepository)
+		expected
+	}{
+		{
+			description: "should return error when shop not found",
+			beforeTest: func(ss *mocks.ShopService, dr *mocks.DiscussionRepository) {
+				ss.On("FindShopByUserId", userID).Return(nil, errorResponse.ErrShopNotFound)
+			},
+			expected: expected{
+				stats: nil,
+				err:   errorResponse.ErrShopNotFound,
+			},
+		},
+		{
+			description: "should return error when failed to get stats",
+			beforeTest: func(ss *mocks.ShopService, dr *mocks.DiscussionRepository) {
+				ss.On("FindShopByUserId", userID).Return(shop, nil)
+				dr.On("GetSLAStats", shop.ID, constant.DiscussionAnswerSLAHours, mock.Anything).Return(nil, errorResponse.ErrInternalServerError)
+			},
+			expected: expected{
+				stats: nil,
+				err:   errorResponse.ErrInternalServerError,
+			},
+		},
+		{
+			description: "should return stats with compliance rate when succeed",
+			beforeTest: func(ss *mocks.ShopService, dr *mocks.DiscussionRepository) {
+				ss.On("FindShopByUserId", userID).Return(shop, nil)
+				dr.On("GetSLAStats", shop.ID, constant.DiscussionAnswerSLAHours, mock.Anything).Return(&dto.DiscussionSLAStats{
+					UnansweredCount:        2,
+					OverdueCount:           1,
+					AnsweredCount:          3,
+					AnsweredWithinSLACount: 3,
+					AverageResponseMinutes: 30,
+				}, nil)
+			},
+			expected: expected{
+				stats: &dto.DiscussionSLAStats{
+					SLAHours:               constant.DiscussionAnswerSLAHours,
+					UnansweredCount:        2,
+					OverdueCount:           1,
+					AnsweredCount:          3,
+					AnsweredWithinSLACount: 3,
+					AverageResponseMinutes: 30,
+					SLAComplianceRate:      0.75,
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopService := mocks.NewShopService(t)
+			mockDiscussionRepository := mocks.NewDiscussionRepository(t)
+			tc.beforeTest(mockShopService, mockDiscussionRepository)
+
+			discussionService := service.NewDiscussionService(&service.DiscussionSConfig{
+				DiscussionRepository: mockDiscussionRepository,
+				ShopService:          mockShopService,
+			})
+
+			stats, err := discussionService.GetDiscussionSLAStats(userID)
+			assert.Equal(t, tc.expected.err, err)
+			assert.Equal(t, tc.expected.stats, stats)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	"kedai/backend/be-kedai/internal/domain/product/dto"
+	"kedai/backend/be-kedai/internal/domain/product/model"
+	"kedai/backend/be-kedai/internal/domain/product/repository"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"log"
+	"strings"
+)
+
+type ProductModerationService interface {
+	PreCheck(request *dto.CreateProductRequest) (*model.ProductModeration, error)
+	GetModerationQueue(request *dto.ModerationQueueRequest) (*commonDto.PaginationResponse, error)
+	GetProductForModeration(code string) (*model.Product, error)
+	ModerateProduct(code string, request *dto.ModerateProductRequest) error
+}
+
+type productModerationServiceImpl struct {
+	productRepository repository.ProductRepository
+	categoryService   CategoryService
+	mailUtils         mail.MailUtils
+	bannedKeywords    []string
+}
+
+type ProductModerationSConfig struct {
+	ProductRepository repository.ProductRepository
+	CategoryService   CategoryService
+	MailUtils         mail.MailUtils
+	BannedKeywords    []string
+}
+
+func NewProductModerationService(cfg *ProductModerationSConfig) ProductModerationService {
+	return &productModerationServiceImpl{
+		productRepository: cfg.ProductRepository,
+		categoryService:   cfg.CategoryService,
+		mailUtils:         cfg.MailUtils,
+		bannedKeywords:    cfg.BannedKeywords,
+	}
+}
+
+func (s *productModerationServiceImpl) PreCheck(request *dto.CreateProductRequest) (*model.ProductModeration, error) {
+	text := strings.ToLower(request.Name + " " + request.Description)
+	for _, keyword := range s.bannedKeywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && strings.Contains(text, keyword) {
+			reason := fmt.Sprintf("listing contains banned keyword \"%s\"", keyword)
+			return &model.ProductModeration{
+				Status: constant.ModerationStatusRejected,
+				Reason: &reason,
+				Flags:  []string{constant.ModerationFlagBannedKeyword},
+			}, nil
+		}
+	}
+
+	moderation := &model.ProductModeration{
+		Status: constant.ModerationStatusPendingReview,
+	}
+
+	isHazardous := request.IsHazardous != nil && *request.IsHazardous
+	if !isHazardous {
+		categories, err := s.categoryService.GetCategoryLineAgesFromBottom(request.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, category := range categories {
+			if category.IsHazardous {
+				isHazardous = true
+				break
+			}
+		}
+	}
+	if isHazardous {
+		moderation.Flags = append(moderation.Flags, constant.ModerationFlagHazardous)
+	}
+
+	averagePrice, err := s.productRepository.GetCategoryAveragePrice(request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if averagePrice > 0 {
+		prices := []float64{request.Price}
+		if len(request.SKU) > 0 {
+			prices = []float64{}
+			for _, sku := range request.SKU {
+				prices = append(prices, sku.Price)
+			}
+		}
+
+		for _, price := range prices {
+			if price > averagePrice*constant.PriceOutlierRatio || price*constant.PriceOutlierRatio < averagePrice {
+				moderation.Flags = append(moderation.Flags, constant.ModerationFlagPriceOutlier)
+				break
+			}
+		}
+	}
+
+	return moderation, nil
+}
+
+func (s *productModerationServiceImpl) GetModerationQueue(request *dto.ModerationQueueRequest) (*commonDto.PaginationResponse, error) {
+	products, totalRows, totalPages, err := s.productRepository.GetModerationQueue(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commonDto.PaginationResponse{
+		TotalRows:  totalRows,
+		TotalPages: totalPages,
+		Page:       request.Page,
+		Limit:      request.Limit,
+		Data:       products,
+	}, nil
+}
+
+func (s *productModerationServiceImpl) GetProductForModeration(code string) (*model.Product, error) {
+	return s.productRepository.GetForModeration(code)
+}
+
+func (s *productModerationServiceImpl) ModerateProduct(code string, request *dto.ModerateProductRequest) error {
+	product, err := s.productRepository.GetForModeration(code)
+	if err != nil {
+		return err
+	}
+
+	moderation := request.GenerateModeration(product.ID)
+	moderation.Flags = product.ModerationFlags
+
+	err = s.productRepository.Moderate(moderation)
+	if err != nil {
+		return err
+	}
+
+	email, err := s.productRepository.GetSellerEmail(product.ShopID)
+	if err != nil {
+		log.Println("failed to get seller email for product", product.ID, ":", err)
+		return nil
+	}
+
+	err = s.mailUtils.SendProductModerationEmail(email, product.Name, moderation.Status, moderation.Reason)
+	if err != nil {
+		log.Println("failed to send moderation email for product", product.ID, ":", err)
+	}
+
+	return nil
+}
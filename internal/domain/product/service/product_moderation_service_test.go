@@ -0,0 +1,364 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/product/dto"
+	"kedai/backend/be-kedai/internal/domain/product/model"
+	"kedai/backend/be-kedai/internal/domain/product/service"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreCheck(t *testing.T) {
+	type input struct {
+		request    *dto.CreateProductRequest
+		beforeTest func(*mocks.ProductRepository, *mocks.CategoryService)
+	}
+	type expected struct {
+		data *model.ProductModeration
+		err  error
+	}
+
+	var (
+		categoryID    = 1
+		isHazardous   = true
+		isNotHazard   = false
+		bannedReason  = "listing contains banned keyword \"replica\""
+		bannedRequest = &dto.CreateProductRequest{
+			Name:        "Replica Watch",
+			Description: "a very good watch",
+			IsHazardous: &isNotHazard,
+			CategoryID:  categoryID,
+			Price:       100000,
+		}
+		request = &dto.CreateProductRequest{
+			Name:        "Original Watch",
+			Description: "a very good watch",
+			IsHazardous: &isNotHazard,
+			CategoryID:  categoryID,
+			Price:       100000,
+		}
+		hazardousRequest = &dto.CreateProductRequest{
+			Name:        "Lighter Fluid",
+			Description: "refill for lighters",
+			IsHazardous: &isHazardous,
+			CategoryID:  categoryID,
+			SKU: []*dto.CreateSKURequest{
+				{Price: 10000},
+				{Price: 5000000},
+			},
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should reject listing when it contains a banned keyword",
+			input: input{
+				request:    bannedRequest,
+				beforeTest: func(pr *mocks.ProductRepository, cs *mocks.CategoryService) {},
+			},
+			expected: expected{
+				data: &model.ProductModeration{
+					Status: constant.ModerationStatusRejected,
+					Reason: &bannedReason,
+					Flags:  []string{constant.ModerationFlagBannedKeyword},
+				},
+				err: nil,
+			},
+		},
+		{
+			description: "should return error when failed to get category lineage",
+			input: input{
+				request: request,
+				beforeTest: func(pr *mocks.ProductRepository, cs *mocks.CategoryService) {
+					cs.On("GetCategoryLineAgesFromBottom", categoryID).Return(nil, errs.ErrCategoryDoesNotExist)
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  errs.ErrCategoryDoesNotExist,
+			},
+		},
+		{
+			description: "should return error when failed to get category average price",
+			input: input{
+				request: request,
+				beforeTest: func(pr *mocks.ProductRepository, cs *mocks.CategoryService) {
+					cs.On("GetCategoryLineAgesFromBottom", categoryID).Return([]*model.Category{{ID: categoryID}}, nil)
+					pr.On("GetCategoryAveragePrice", categoryID).Return(float64(0), errors.New("failed to get average price"))
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  errors.New("failed to get average price"),
+			},
+		},
+		{
+			description: "should flag listing under a hazardous parent category",
+			input: input{
+				request: request,
+				beforeTest: func(pr *mocks.ProductRepository, cs *mocks.CategoryService) {
+					cs.On("GetCategoryLineAgesFromBottom", categoryID).Return([]*model.Category{{ID: categoryID}, {ID: 2, IsHazardous: true}}, nil)
+					pr.On("GetCategoryAveragePrice", categoryID).Return(float64(120000), nil)
+				},
+			},
+			expected: expected{
+				data: &model.ProductModeration{
+					Status: constant.ModerationStatusPendingReview,
+					Flags:  []string{constant.ModerationFlagHazardous},
+				},
+				err: nil,
+			},
+		},
+		{
+			description: "should flag hazardous listing with an outlier sku price",
+			input: input{
+				request: hazardousRequest,
+				beforeTest: func(pr *mocks.ProductRepository, cs *mocks.CategoryService) {
+					pr.On("GetCategoryAveragePrice", categoryID).Return(float64(100000), nil)
+				},
+			},
+			expected: expected{
+				data: &model.ProductModeration{
+					Status: constant.ModerationStatusPendingReview,
+					Flags:  []string{constant.ModerationFlagHazardous, constant.ModerationFlagPriceOutlier},
+				},
+				err: nil,
+			},
+		},
+		{
+			description: "should put clean listing in pending review without flags",
+			input: input{
+				request: request,
+				beforeTest: func(pr *mocks.ProductRepository, cs *mocks.CategoryService) {
+					cs.On("GetCategoryLineAgesFromBottom", categoryID).Return([]*model.Category{{ID: categoryID}}, nil)
+					pr.On("GetCategoryAveragePrice", categoryID).Return(float64(0), nil)
+				},
+			},
+			expected: expected{
+				data: &model.ProductModeration{
+					Status: constant.ModerationStatusPendingReview,
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			productRepo := mocks.NewProductRepository(t)
+			categoryService := mocks.NewCategoryService(t)
+			tc.beforeTest(productRepo, categoryService)
+			moderationService := service.NewProductModerationService(&service.ProductModerationSConfig{
+				ProductRepository: productRepo,
+				CategoryService:   categoryService,
+				BannedKeywords:    []string{" Replica", "counterfeit"},
+			})
+
+			actualResult, actualErr := moderationService.PreCheck(tc.input.request)
+
+			assert.Equal(t, tc.expected.data, actualResult)
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
+
+func TestGetModerationQueue(t *testing.T) {
+	type input struct {
+		request    *dto.ModerationQueueRequest
+		beforeTest func(*mocks.ProductRepository)
+	}
+	type expected struct {
+		data *commonDto.PaginationResponse
+		err  error
+	}
+
+	var (
+		request = &dto.ModerationQueueRequest{
+			Limit:  20,
+			Page:   1,
+			Status: constant.ModerationStatusPendingReview,
+		}
+		products = []*dto.ModerationQueueProduct{
+			{Product: model.Product{ID: 1, ModerationStatus: constant.ModerationStatusPendingReview}},
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when failed to get moderation queue",
+			input: input{
+				request: request,
+				beforeTest: func(pr *mocks.ProductRepository) {
+					pr.On("GetModerationQueue", request).Return(nil, int64(0), 0, errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  errs.ErrInternalServerError,
+			},
+		},
+		{
+			description: "should return paginated moderation queue when succeed",
+			input: input{
+				request: request,
+				beforeTest: func(pr *mocks.ProductRepository) {
+					pr.On("GetModerationQueue", request).Return(products, int64(1), 1, nil)
+				},
+			},
+			expected: expected{
+				data: &commonDto.PaginationResponse{
+					TotalRows:  1,
+					TotalPages: 1,
+					Page:       1,
+					Limit:      20,
+					Data:       products,
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			productRepo := mocks.NewProductRepository(t)
+			tc.beforeTest(productRepo)
+			moderationService := service.NewProductModerationService(&service.ProductModerationSConfig{
+				ProductRepository: productRepo,
+			})
+
+			actualResult, actualErr := moderationService.GetModerationQueue(tc.input.request)
+
+			assert.Equal(t, tc.expected.data, actualResult)
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
+
+func TestModerateProduct(t *testing.T) {
+	type input struct {
+		code       string
+		request    *dto.ModerateProductRequest
+		beforeTest func(*mocks.ProductRepository, *mocks.MailUtils)
+	}
+	type expected struct {
+		err error
+	}
+
+	var (
+		code    = "product-code"
+		email   = "seller@mail.com"
+		reason  = "counterfeit goods"
+		product = &model.Product{
+			ID:              1,
+			Name:            "product",
+			ShopID:          2,
+			ModerationFlags: []string{constant.ModerationFlagPriceOutlier},
+		}
+		banRequest = &dto.ModerateProductRequest{
+			Status: constant.ModerationStatusBanned,
+			Reason: reason,
+		}
+		moderation = &model.ProductModeration{
+			Status:    constant.ModerationStatusBanned,
+			Reason:    &reason,
+			Flags:     product.ModerationFlags,
+			ProductID: product.ID,
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when product does not exist",
+			input: input{
+				code:    code,
+				request: banRequest,
+				beforeTest: func(pr *mocks.ProductRepository, mu *mocks.MailUtils) {
+					pr.On("GetForModeration", code).Return(nil, errs.ErrProductDoesNotExist)
+				},
+			},
+			expected: expected{
+				err: errs.ErrProductDoesNotExist,
+			},
+		},
+		{
+			description: "should return error when failed to moderate product",
+			input: input{
+				code:    code,
+				request: banRequest,
+				beforeTest: func(pr *mocks.ProductRepository, mu *mocks.MailUtils) {
+					pr.On("GetForModeration", code).Return(product, nil)
+					pr.On("Moderate", moderation).Return(errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				err: errs.ErrInternalServerError,
+			},
+		},
+		{
+			description: "should not return error when failed to notify seller",
+			input: input{
+				code:    code,
+				request: banRequest,
+				beforeTest: func(pr *mocks.ProductRepository, mu *mocks.MailUtils) {
+					pr.On("GetForModeration", code).Return(product, nil)
+					pr.On("Moderate", moderation).Return(nil)
+					pr.On("GetSellerEmail", product.ShopID).Return(email, nil)
+					mu.On("SendProductModerationEmail", email, product.Name, constant.ModerationStatusBanned, &reason).Return(errors.New("failed to send email"))
+				},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			description: "should return nil when product moderated and seller notified",
+			input: input{
+				code:    code,
+				request: banRequest,
+				beforeTest: func(pr *mocks.ProductRepository, mu *mocks.MailUtils) {
+					pr.On("GetForModeration", code).Return(product, nil)
+					pr.On("Moderate", moderation).Return(nil)
+					pr.On("GetSellerEmail", product.ShopID).Return(email, nil)
+					mu.On("SendProductModerationEmail", email, product.Name, constant.ModerationStatusBanned, &reason).Return(nil)
+				},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			productRepo := mocks.NewProductRepository(t)
+			mailUtils := mocks.NewMailUtils(t)
+			tc.beforeTest(productRepo, mailUtils)
+			moderationService := service.NewProductModerationService(&service.ProductModerationSConfig{
+				ProductRepository: productRepo,
+				MailUtils:         mailUtils,
+			})
+
+			actualErr := moderationService.ModerateProduct(tc.input.code, tc.input.request)
+
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
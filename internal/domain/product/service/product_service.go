@@ -36,6 +36,7 @@ type productServiceImpl struct {
 	courierServiceService service.CourierServiceService
 	categoryService       CategoryService
 	DiscussionService     DiscussionService
+	moderationService     ProductModerationService
 }
 
 type ProductSConfig struct {
@@ -46,6 +47,7 @@ type ProductSConfig struct {
 	CourierServiceService service.CourierServiceService
 	CategoryService       CategoryService
 	DiscussionService     DiscussionService
+	ModerationService     ProductModerationService
 }
 
 func NewProductService(cfg *ProductSConfig) ProductService {
@@ -57,6 +59,7 @@ func NewProductService(cfg *ProductSConfig) ProductService {
 		categoryService:       cfg.CategoryService,
 		courierServiceService: cfg.CourierServiceService,
 		DiscussionService:     cfg.DiscussionService,
+		moderationService:     cfg.ModerationService,
 	}
 }
 
@@ -244,7 +247,12 @@ func (s *productServiceImpl) CreateProduct(userID int, request *dto.CreateProduc
 		return nil, err
 	}
 
-	product, err := s.productRepository.Create(shop.ID, request, couriers)
+	moderation, err := s.moderationService.PreCheck(request)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepository.Create(shop.ID, request, couriers, moderation)
 	if err != nil {
 		return nil, err
 	}
@@ -287,7 +295,12 @@ func (s *productServiceImpl) UpdateProduct(userID int, code string, request *dto
 		return nil, err
 	}
 
-	product, err := s.productRepository.Update(shop.ID, code, request, couriers)
+	moderation, err := s.moderationService.PreCheck(request)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepository.Update(shop.ID, code, request, couriers, moderation)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errorResponse "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
@@ -978,12 +979,13 @@ func TestCreateProduct(t *testing.T) {
 		courierIDs      = []int{1}
 		productName     = "product name"
 		courierServices = []*shopModel.CourierService{}
+		moderation      = &model.ProductModeration{Status: constant.ModerationStatusPendingReview}
 	)
 
 	tests := []struct {
 		description string
 		input
-		beforeTest func(*mocks.ShopService, *mocks.CourierServiceService, *mocks.ProductRepository)
+		beforeTest func(*mocks.ShopService, *mocks.CourierServiceService, *mocks.ProductModerationService, *mocks.ProductRepository)
 		expected
 	}{
 		{
@@ -995,7 +997,8 @@ func TestCreateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {},
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
+			},
 			expected: expected{
 				data: nil,
 				err:  errorResponse.ErrInvalidProductNamePattern,
@@ -1010,7 +1013,7 @@ func TestCreateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(nil, errors.New("failed to get shop"))
 			},
 			expected: expected{
@@ -1027,7 +1030,7 @@ func TestCreateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: shopID}, nil)
 				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(nil, errors.New("failed to get couriers"))
 			},
@@ -1036,6 +1039,25 @@ func TestCreateProduct(t *testing.T) {
 				err:  errors.New("failed to get couriers"),
 			},
 		},
+		{
+			description: "should return error when failed to run moderation pre-check",
+			input: input{
+				userID: userID,
+				request: &dto.CreateProductRequest{
+					Name:       productName,
+					CourierIDs: courierIDs,
+				},
+			},
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
+				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: shopID}, nil)
+				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(courierServices, nil)
+				pms.On("PreCheck", &dto.CreateProductRequest{Name: productName, CourierIDs: courierIDs}).Return(nil, errors.New("failed to get categories"))
+			},
+			expected: expected{
+				data: nil,
+				err:  errors.New("failed to get categories"),
+			},
+		},
 		{
 			description: "should return error when failed to create product",
 			input: input{
@@ -1045,10 +1067,11 @@ func TestCreateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: shopID}, nil)
 				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(courierServices, nil)
-				pr.On("Create", shopID, &dto.CreateProductRequest{Name: productName, CourierIDs: courierIDs}, courierServices).Return(nil, errors.New("failed to create product"))
+				pms.On("PreCheck", &dto.CreateProductRequest{Name: productName, CourierIDs: courierIDs}).Return(moderation, nil)
+				pr.On("Create", shopID, &dto.CreateProductRequest{Name: productName, CourierIDs: courierIDs}, courierServices, moderation).Return(nil, errors.New("failed to create product"))
 			},
 			expected: expected{
 				data: nil,
@@ -1064,10 +1087,11 @@ func TestCreateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: shopID}, nil)
 				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(courierServices, nil)
-				pr.On("Create", shopID, &dto.CreateProductRequest{Name: productName, CourierIDs: courierIDs}, courierServices).Return(&model.Product{Name: productName}, nil)
+				pms.On("PreCheck", &dto.CreateProductRequest{Name: productName, CourierIDs: courierIDs}).Return(moderation, nil)
+				pr.On("Create", shopID, &dto.CreateProductRequest{Name: productName, CourierIDs: courierIDs}, courierServices, moderation).Return(&model.Product{Name: productName}, nil)
 			},
 			expected: expected{
 				data: &model.Product{Name: productName},
@@ -1080,12 +1104,14 @@ func TestCreateProduct(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			shopService := mocks.NewShopService(t)
 			courierServiceService := mocks.NewCourierServiceService(t)
+			moderationService := mocks.NewProductModerationService(t)
 			productRepo := mocks.NewProductRepository(t)
-			tc.beforeTest(shopService, courierServiceService, productRepo)
+			tc.beforeTest(shopService, courierServiceService, moderationService, productRepo)
 			productService := service.NewProductService(&service.ProductSConfig{
 				ProductRepository:     productRepo,
 				CourierServiceService: courierServiceService,
 				ShopService:           shopService,
+				ModerationService:     moderationService,
 			})
 
 			data, err := productService.CreateProduct(tc.input.userID, tc.input.request)
@@ -1214,12 +1240,13 @@ func TestUpdateProduct(t *testing.T) {
 			Name:       productName,
 			CourierIDs: courierIDs,
 		}
+		moderation = &model.ProductModeration{Status: constant.ModerationStatusPendingReview}
 	)
 
 	tests := []struct {
 		description string
 		input
-		beforeTest func(*mocks.ShopService, *mocks.CourierServiceService, *mocks.ProductRepository)
+		beforeTest func(*mocks.ShopService, *mocks.CourierServiceService, *mocks.ProductModerationService, *mocks.ProductRepository)
 		expected
 	}{
 		{
@@ -1232,7 +1259,7 @@ func TestUpdateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 			},
 			expected: expected{
 				data: nil,
@@ -1249,7 +1276,7 @@ func TestUpdateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(nil, errors.New("failed to get shop"))
 			},
 			expected: expected{
@@ -1267,7 +1294,7 @@ func TestUpdateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: int(shopID)}, nil)
 				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(nil, errors.New("failed to get couriers"))
 			},
@@ -1276,6 +1303,26 @@ func TestUpdateProduct(t *testing.T) {
 				err:  errors.New("failed to get couriers"),
 			},
 		},
+		{
+			description: "should return error when failed to run moderation pre-check",
+			input: input{
+				userID: userID,
+				code:   code,
+				request: &dto.CreateProductRequest{
+					Name:       productName,
+					CourierIDs: courierIDs,
+				},
+			},
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
+				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: shopID}, nil)
+				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(courierServices, nil)
+				pms.On("PreCheck", updateReq).Return(nil, errors.New("failed to get categories"))
+			},
+			expected: expected{
+				data: nil,
+				err:  errors.New("failed to get categories"),
+			},
+		},
 		{
 			description: "should return error when failed to update product",
 			input: input{
@@ -1286,10 +1333,11 @@ func TestUpdateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: shopID}, nil)
 				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(courierServices, nil)
-				pr.On("Update", shopID, code, updateReq, courierServices).Return(nil, errors.New("failed to update product"))
+				pms.On("PreCheck", updateReq).Return(moderation, nil)
+				pr.On("Update", shopID, code, updateReq, courierServices, moderation).Return(nil, errors.New("failed to update product"))
 			},
 			expected: expected{
 				data: nil,
@@ -1306,10 +1354,11 @@ func TestUpdateProduct(t *testing.T) {
 					CourierIDs: courierIDs,
 				},
 			},
-			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pr *mocks.ProductRepository) {
+			beforeTest: func(ss *mocks.ShopService, css *mocks.CourierServiceService, pms *mocks.ProductModerationService, pr *mocks.ProductRepository) {
 				ss.On("FindShopByUserId", userID).Return(&shopModel.Shop{ID: shopID}, nil)
 				css.On("GetCourierServicesByCourierIDs", courierIDs).Return(courierServices, nil)
-				pr.On("Update", shopID, code, updateReq, courierServices).Return(&model.Product{}, nil)
+				pms.On("PreCheck", updateReq).Return(moderation, nil)
+				pr.On("Update", shopID, code, updateReq, courierServices, moderation).Return(&model.Product{}, nil)
 			},
 			expected: expected{
 				data: &model.Product{},
@@ -1321,12 +1370,14 @@ func TestUpdateProduct(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			shopService := mocks.NewShopService(t)
 			courierServiceService := mocks.NewCourierServiceService(t)
+			moderationService := mocks.NewProductModerationService(t)
 			productRepo := mocks.NewProductRepository(t)
-			tc.beforeTest(shopService, courierServiceService, productRepo)
+			tc.beforeTest(shopService, courierServiceService, moderationService, productRepo)
 			productService := service.NewProductService(&service.ProductSConfig{
 				ShopService:           shopService,
 				CourierServiceService: courierServiceService,
 				ProductRepository:     productRepo,
+				ModerationService:     moderationService,
 			})
 
 			data, err := productService.UpdateProduct(tc.input.userID, tc.input.code, tc.input.request)
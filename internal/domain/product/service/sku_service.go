@@ -1,27 +1,44 @@
 package service
 
 import (
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/domain/product/model"
 	"kedai/backend/be-kedai/internal/domain/product/repository"
+	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"log"
 )
 
 type SkuService interface {
 	GetByID(id int) (*model.Sku, error)
 	GetSKUByVariantIDs(request *dto.GetSKURequest) (*model.Sku, error)
+	GetStockHistory(userID int, productCode string, request *dto.StockHistoryRequest) (*commonDto.PaginationResponse, error)
+	UpdateLowStockThresholds(userID int, productCode string, request *dto.UpdateLowStockThresholdRequest) error
+	GetLowStockSkus(userID int) ([]*dto.LowStockSku, error)
+	NotifyLowStock() error
 }
 
 type skuServiceImpl struct {
-	skuRepository repository.SkuRepository
+	skuRepository               repository.SkuRepository
+	inventoryMovementRepository repository.InventoryMovementRepository
+	shopService                 shopService.ShopService
+	mailUtils                   mail.MailUtils
 }
 
 type SkuSConfig struct {
-	SkuRepository repository.SkuRepository
+	SkuRepository               repository.SkuRepository
+	InventoryMovementRepository repository.InventoryMovementRepository
+	ShopService                 shopService.ShopService
+	MailUtils                   mail.MailUtils
 }
 
 func NewSkuService(cfg *SkuSConfig) SkuService {
 	return &skuServiceImpl{
-		skuRepository: cfg.SkuRepository,
+		skuRepository:               cfg.SkuRepository,
+		inventoryMovementRepository: cfg.InventoryMovementRepository,
+		shopService:                 cfg.ShopService,
+		mailUtils:                   cfg.MailUtils,
 	}
 }
 
@@ -37,3 +54,71 @@ func (s *skuServiceImpl) GetSKUByVariantIDs(request *dto.GetSKURequest) (*model.
 
 	return s.skuRepository.GetByVariantIDs(variantIDs)
 }
+
+func (s *skuServiceImpl) GetStockHistory(userID int, productCode string, request *dto.StockHistoryRequest) (*commonDto.PaginationResponse, error) {
+	shop, err := s.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	skus, err := s.skuRepository.GetBySellerProductCode(shop.ID, productCode)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, totalRows, totalPages, err := s.inventoryMovementRepository.GetByProductID(skus[0].ProductId, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commonDto.PaginationResponse{
+		Data:       movements,
+		Limit:      request.Limit,
+		Page:       request.Page,
+		TotalRows:  totalRows,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *skuServiceImpl) UpdateLowStockThresholds(userID int, productCode string, request *dto.UpdateLowStockThresholdRequest) error {
+	shop, err := s.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return err
+	}
+
+	skus, err := s.skuRepository.GetBySellerProductCode(shop.ID, productCode)
+	if err != nil {
+		return err
+	}
+
+	return s.skuRepository.UpdateLowStockThresholds(skus[0].ProductId, request.Skus)
+}
+
+func (s *skuServiceImpl) GetLowStockSkus(userID int) ([]*dto.LowStockSku, error) {
+	shop, err := s.shopService.FindShopByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.skuRepository.GetLowStockByShopID(shop.ID)
+}
+
+func (s *skuServiceImpl) NotifyLowStock() error {
+	alerts, err := s.skuRepository.GetUnnotifiedLowStock()
+	if err != nil {
+		return err
+	}
+
+	var notifiedIDs []int
+	for _, alert := range alerts {
+		err := s.mailUtils.SendLowStockAlertEmail(alert.Email, alert.ProductName, alert.Sku, alert.Stock)
+		if err != nil {
+			log.Println("failed to send low stock alert for sku", alert.ID, ":", err)
+			continue
+		}
+
+		notifiedIDs = append(notifiedIDs, alert.ID)
+	}
+
+	return s.skuRepository.MarkLowStockNotified(notifiedIDs)
+}
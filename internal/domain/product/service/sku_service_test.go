@@ -2,10 +2,12 @@ package service_test
 
 import (
 	"errors"
+	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/product/dto"
 	"kedai/backend/be-kedai/internal/domain/product/model"
 	"kedai/backend/be-kedai/internal/domain/product/service"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
 	"kedai/backend/be-kedai/mocks"
 	"testing"
 
@@ -145,3 +147,361 @@ func TestGetSKUByVariantIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStockHistory(t *testing.T) {
+	type input struct {
+		userID      int
+		productCode string
+		request     *dto.StockHistoryRequest
+		beforeTest  func(*mocks.ShopService, *mocks.SkuRepository, *mocks.InventoryMovementRepository)
+	}
+	type expected struct {
+		data *commonDto.PaginationResponse
+		err  error
+	}
+
+	var (
+		userID      = 1
+		productCode = "product-code"
+		shop        = &shopModel.Shop{ID: 1}
+		skus        = []*model.Sku{{ID: 1, ProductId: 2}}
+		request     = &dto.StockHistoryRequest{Limit: 10, Page: 1}
+		movements   = []*model.InventoryMovement{{ID: 1, SkuId: 1, Type: model.InventoryMovementTypeSale, Quantity: -1, StockAfter: 9}}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when shop not found",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository, imr *mocks.InventoryMovementRepository) {
+					ss.On("FindShopByUserId", userID).Return(nil, errs.ErrShopNotFound)
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  errs.ErrShopNotFound,
+			},
+		},
+		{
+			description: "should return error when product not found",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository, imr *mocks.InventoryMovementRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					sr.On("GetBySellerProductCode", shop.ID, productCode).Return(nil, errs.ErrProductDoesNotExist)
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  errs.ErrProductDoesNotExist,
+			},
+		},
+		{
+			description: "should return error when failed to get inventory movements",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository, imr *mocks.InventoryMovementRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					sr.On("GetBySellerProductCode", shop.ID, productCode).Return(skus, nil)
+					imr.On("GetByProductID", skus[0].ProductId, request).Return(nil, int64(0), 0, errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  errs.ErrInternalServerError,
+			},
+		},
+		{
+			description: "should return stock history when succeed",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository, imr *mocks.InventoryMovementRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					sr.On("GetBySellerProductCode", shop.ID, productCode).Return(skus, nil)
+					imr.On("GetByProductID", skus[0].ProductId, request).Return(movements, int64(1), 1, nil)
+				},
+			},
+			expected: expected{
+				data: &commonDto.PaginationResponse{
+					Data:       movements,
+					Limit:      request.Limit,
+					Page:       request.Page,
+					TotalRows:  1,
+					TotalPages: 1,
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			shopService := mocks.NewShopService(t)
+			skuRepo := mocks.NewSkuRepository(t)
+			inventoryMovementRepo := mocks.NewInventoryMovementRepository(t)
+			tc.beforeTest(shopService, skuRepo, inventoryMovementRepo)
+			skuService := service.NewSkuService(&service.SkuSConfig{
+				SkuRepository:               skuRepo,
+				InventoryMovementRepository: inventoryMovementRepo,
+				ShopService:                 shopService,
+			})
+
+			actualData, actualErr := skuService.GetStockHistory(tc.input.userID, tc.input.productCode, tc.input.request)
+
+			assert.Equal(t, tc.expected.data, actualData)
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
+
+func TestUpdateLowStockThresholds(t *testing.T) {
+	type input struct {
+		userID      int
+		productCode string
+		request     *dto.UpdateLowStockThresholdRequest
+		beforeTest  func(*mocks.ShopService, *mocks.SkuRepository)
+	}
+	type expected struct {
+		err error
+	}
+
+	var (
+		userID      = 1
+		productCode = "product-code"
+		threshold   = 5
+		shop        = &shopModel.Shop{ID: 1}
+		skus        = []*model.Sku{{ID: 1, ProductId: 2}}
+		request     = &dto.UpdateLowStockThresholdRequest{
+			Skus: []*dto.SkuLowStockThreshold{{SkuID: 1, Threshold: &threshold}},
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when shop not found",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository) {
+					ss.On("FindShopByUserId", userID).Return(nil, errs.ErrShopNotFound)
+				},
+			},
+			expected: expected{
+				err: errs.ErrShopNotFound,
+			},
+		},
+		{
+			description: "should return error when product not found",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					sr.On("GetBySellerProductCode", shop.ID, productCode).Return(nil, errs.ErrProductDoesNotExist)
+				},
+			},
+			expected: expected{
+				err: errs.ErrProductDoesNotExist,
+			},
+		},
+		{
+			description: "should return error when sku does not belong to product",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					sr.On("GetBySellerProductCode", shop.ID, productCode).Return(skus, nil)
+					sr.On("UpdateLowStockThresholds", skus[0].ProductId, request.Skus).Return(errs.ErrSKUDoesNotExist)
+				},
+			},
+			expected: expected{
+				err: errs.ErrSKUDoesNotExist,
+			},
+		},
+		{
+			description: "should return nil when update succeed",
+			input: input{
+				userID:      userID,
+				productCode: productCode,
+				request:     request,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					sr.On("GetBySellerProductCode", shop.ID, productCode).Return(skus, nil)
+					sr.On("UpdateLowStockThresholds", skus[0].ProductId, request.Skus).Return(nil)
+				},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			shopService := mocks.NewShopService(t)
+			skuRepo := mocks.NewSkuRepository(t)
+			tc.beforeTest(shopService, skuRepo)
+			skuService := service.NewSkuService(&service.SkuSConfig{
+				SkuRepository: skuRepo,
+				ShopService:   shopService,
+			})
+
+			actualErr := skuService.UpdateLowStockThresholds(tc.input.userID, tc.input.productCode, tc.input.request)
+
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
+
+func TestGetLowStockSkus(t *testing.T) {
+	type input struct {
+		userID     int
+		beforeTest func(*mocks.ShopService, *mocks.SkuRepository)
+	}
+	type expected struct {
+		data []*dto.LowStockSku
+		err  error
+	}
+
+	var (
+		userID = 1
+		shop   = &shopModel.Shop{ID: 1}
+		skus   = []*dto.LowStockSku{{ID: 1, Stock: 2, LowStockThreshold: 5}}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when shop not found",
+			input: input{
+				userID: userID,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository) {
+					ss.On("FindShopByUserId", userID).Return(nil, errs.ErrShopNotFound)
+				},
+			},
+			expected: expected{
+				data: nil,
+				err:  errs.ErrShopNotFound,
+			},
+		},
+		{
+			description: "should return low stock skus when succeed",
+			input: input{
+				userID: userID,
+				beforeTest: func(ss *mocks.ShopService, sr *mocks.SkuRepository) {
+					ss.On("FindShopByUserId", userID).Return(shop, nil)
+					sr.On("GetLowStockByShopID", shop.ID).Return(skus, nil)
+				},
+			},
+			expected: expected{
+				data: skus,
+				err:  nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			shopService := mocks.NewShopService(t)
+			skuRepo := mocks.NewSkuRepository(t)
+			tc.beforeTest(shopService, skuRepo)
+			skuService := service.NewSkuService(&service.SkuSConfig{
+				SkuRepository: skuRepo,
+				ShopService:   shopService,
+			})
+
+			actualData, actualErr := skuService.GetLowStockSkus(tc.input.userID)
+
+			assert.Equal(t, tc.expected.data, actualData)
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
+
+func TestNotifyLowStock(t *testing.T) {
+	type input struct {
+		beforeTest func(*mocks.SkuRepository, *mocks.MailUtils)
+	}
+	type expected struct {
+		err error
+	}
+
+	var (
+		alerts = []*dto.LowStockAlert{
+			{ID: 1, Sku: "SKU1", Stock: 1, LowStockThreshold: 5, ProductName: "product 1", Email: "seller1@mail.com"},
+			{ID: 2, Sku: "SKU2", Stock: 0, LowStockThreshold: 2, ProductName: "product 2", Email: "seller2@mail.com"},
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when failed to get low stock skus",
+			input: input{
+				beforeTest: func(sr *mocks.SkuRepository, mu *mocks.MailUtils) {
+					sr.On("GetUnnotifiedLowStock").Return(nil, errs.ErrInternalServerError)
+				},
+			},
+			expected: expected{
+				err: errs.ErrInternalServerError,
+			},
+		},
+		{
+			description: "should only mark skus whose alert was sent as notified",
+			input: input{
+				beforeTest: func(sr *mocks.SkuRepository, mu *mocks.MailUtils) {
+					sr.On("GetUnnotifiedLowStock").Return(alerts, nil)
+					mu.On("SendLowStockAlertEmail", alerts[0].Email, alerts[0].ProductName, alerts[0].Sku, alerts[0].Stock).Return(errors.New("failed to send email"))
+					mu.On("SendLowStockAlertEmail", alerts[1].Email, alerts[1].ProductName, alerts[1].Sku, alerts[1].Stock).Return(nil)
+					sr.On("MarkLowStockNotified", []int{alerts[1].ID}).Return(nil)
+				},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			skuRepo := mocks.NewSkuRepository(t)
+			mailUtils := mocks.NewMailUtils(t)
+			tc.beforeTest(skuRepo, mailUtils)
+			skuService := service.NewSkuService(&service.SkuSConfig{
+				SkuRepository: skuRepo,
+				MailUtils:     mailUtils,
+			})
+
+			actualErr := skuService.NotifyLowStock()
+
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
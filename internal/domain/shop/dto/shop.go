@@ -3,11 +3,17 @@ package dto
 import (
 	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
+	chatDto "kedai/backend/be-kedai/internal/domain/chat/dto"
 	"kedai/backend/be-kedai/internal/domain/shop/model"
 	stringsUtil "kedai/backend/be-kedai/internal/utils/strings"
 	"strings"
 )
 
+type ShopDetail struct {
+	*model.Shop
+	ChatPerformance *chatDto.ChatPerformance `json:"chatPerformance,omitempty"`
+}
+
 type FindShopRequest struct {
 	Keyword string `form:"keyword"`
 	Page    int    `form:"page"`
@@ -0,0 +1,47 @@
+package dto
+
+import "kedai/backend/be-kedai/internal/common/constant"
+
+// ShopMembership is who is acting for which shop on a seller request.
+// OwnerID is the user the shop is registered to; MemberID is the user
+// actually making the request, which is the owner themself for owners.
+type ShopMembership struct {
+	ShopID      int      `json:"shopId"`
+	OwnerID     int      `json:"ownerId"`
+	MemberID    int      `json:"memberId"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+func NewShopMembership(shopId int, ownerId int, memberId int, role string) *ShopMembership {
+	return &ShopMembership{
+		ShopID:      shopId,
+		OwnerID:     ownerId,
+		MemberID:    memberId,
+		Role:        role,
+		Permissions: constant.ShopRolePermissions[role],
+	}
+}
+
+func (m *ShopMembership) Can(permission string) bool {
+	for _, granted := range m.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+type InviteShopMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=admin order_ops chat_agent finance"`
+}
+
+type UpdateShopMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin order_ops chat_agent finance"`
+}
+
+type AcceptShopInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
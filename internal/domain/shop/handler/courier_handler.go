@@ -15,7 +15,7 @@ func (h *Handler) GetShipmentList(c *gin.Context) {
 	var request dto.ShipmentCourierFilterRequest
 	_ = c.ShouldBindQuery(&request)
 
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	result, err := h.courierService.GetShipmentList(userId, &request)
 	if err != nil {
@@ -84,7 +84,7 @@ func (h *Handler) ToggleShopCourier(c *gin.Context) {
 		return
 	}
 
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	res, err := h.courierService.ToggleShopCourier(userId, req.CourierId)
 	if err != nil {
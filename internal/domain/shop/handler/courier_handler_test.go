@@ -206,7 +206,7 @@ func TestGetShipmentList(t *testing.T) {
 			expectedBody, _ := json.Marshal(tc.expected.response)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			mockService := new(mocks.CourierService)
 			mockService.On("GetShipmentList", shopId, tc.request).Return(tc.input.result, tc.input.err)
 			handler := handler.New(&handler.HandlerConfig{
@@ -547,7 +547,7 @@ func TestToggleShopCourier(t *testing.T) {
 			handler := handler.New(&handler.HandlerConfig{
 				CourierService: mockService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 
 			c.Request, _ = http.NewRequest("POST", "/v1/sellers/couriers", payload)
 			handler.ToggleShopCourier(c)
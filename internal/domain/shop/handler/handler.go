@@ -1,6 +1,9 @@
 package handler
 
-import "kedai/backend/be-kedai/internal/domain/shop/service"
+import (
+	chatService "kedai/backend/be-kedai/internal/domain/chat/service"
+	"kedai/backend/be-kedai/internal/domain/shop/service"
+)
 
 type Handler struct {
 	shopService          service.ShopService
@@ -9,6 +12,8 @@ type Handler struct {
 	courierService       service.CourierService
 	shopGuestService     service.ShopGuestService
 	shopCategoryService  service.ShopCategoryService
+	shopMemberService    service.ShopMemberService
+	chatService          chatService.ChatService
 }
 
 type HandlerConfig struct {
@@ -18,6 +23,8 @@ type HandlerConfig struct {
 	CourierService       service.CourierService
 	ShopGuestService     service.ShopGuestService
 	ShopCategoryService  service.ShopCategoryService
+	ShopMemberService    service.ShopMemberService
+	ChatService          chatService.ChatService
 }
 
 func New(cfg *HandlerConfig) *Handler {
@@ -28,5 +35,7 @@ func New(cfg *HandlerConfig) *Handler {
 		courierService:       cfg.CourierService,
 		shopGuestService:     cfg.ShopGuestService,
 		shopCategoryService:  cfg.ShopCategoryService,
+		shopMemberService:    cfg.ShopMemberService,
+		chatService:          cfg.ChatService,
 	}
 }
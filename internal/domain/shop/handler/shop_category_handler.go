@@ -18,7 +18,7 @@ func (h *Handler) GetSellerCategories(c *gin.Context) {
 	_ = c.ShouldBindQuery(&req)
 
 	req.Validate()
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	shopCategories, err := h.shopCategoryService.GetSellerCategories(userId, req)
 	if err != nil {
@@ -35,7 +35,7 @@ func (h *Handler) GetSellerCategories(c *gin.Context) {
 }
 
 func (h *Handler) GetSellerCategoryDetail(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	categoryId := c.Param("categoryId")
 	intCategoryId, _ := strconv.Atoi(categoryId)
 
@@ -65,7 +65,7 @@ func (h *Handler) CreateSellerCategory(c *gin.Context) {
 		return
 	}
 
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	category, err := h.shopCategoryService.CreateSellerCategory(userId, req)
 	if err != nil {
@@ -95,7 +95,7 @@ func (h *Handler) UpdateSellerCategory(c *gin.Context) {
 	var req dto.UpdateSellerCategoryRequest
 	_ = c.ShouldBindJSON(&req)
 
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	categoryId := c.Param("categoryId")
 	intCategoryId, _ := strconv.Atoi(categoryId)
 
@@ -129,7 +129,7 @@ func (h *Handler) UpdateSellerCategory(c *gin.Context) {
 }
 
 func (h *Handler) DeleteSellerCategory(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	categoryId := c.Param("categoryId")
 	intCategoryId, _ := strconv.Atoi(categoryId)
 
@@ -89,7 +89,7 @@ func TestGetSellerCategories(t *testing.T) {
 			tt.beforeTest(shopCategoryService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			handler := handler.New(&handler.HandlerConfig{
 				ShopCategoryService: shopCategoryService,
 			})
@@ -164,7 +164,7 @@ func TestGetSellerCategoryDetail(t *testing.T) {
 			tt.beforeTest(shopCategoryService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			c.AddParam("categoryId", "1")
 			handler := handler.New(&handler.HandlerConfig{
 				ShopCategoryService: shopCategoryService,
@@ -264,7 +264,7 @@ func TestCreateSellerCategory(t *testing.T) {
 			tt.beforeTest(shopCategoryService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			handler := handler.New(&handler.HandlerConfig{
 				ShopCategoryService: shopCategoryService,
 			})
@@ -369,7 +369,7 @@ func TestUpdateSellerCategory(t *testing.T) {
 			tt.beforeTest(shopCategoryService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			c.AddParam("categoryId", "1")
 			handler := handler.New(&handler.HandlerConfig{
 				ShopCategoryService: shopCategoryService,
@@ -444,7 +444,7 @@ func TestDeleteSellerCategory(t *testing.T) {
 			tt.beforeTest(shopCategoryService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			c.AddParam("categoryId", "1")
 			handler := handler.New(&handler.HandlerConfig{
 				ShopCategoryService: shopCategoryService,
@@ -6,6 +6,7 @@ import (
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/shop/dto"
 	"kedai/backend/be-kedai/internal/utils/response"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -25,7 +26,13 @@ func (h *Handler) FindShopBySlug(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, code.OK, "ok", result)
+	detail := &dto.ShopDetail{Shop: result}
+	detail.ChatPerformance, err = h.chatService.GetShopPerformance(result.ID)
+	if err != nil {
+		log.Println("failed to get shop chat performance:", err)
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", detail)
 }
 
 func (h *Handler) FindShopByKeyword(c *gin.Context) {
@@ -43,7 +50,7 @@ func (h *Handler) FindShopByKeyword(c *gin.Context) {
 }
 
 func (h *Handler) GetShopFinanceOverview(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	result, err := h.shopService.GetShopFinanceOverview(userId)
 	if err != nil {
@@ -59,7 +66,7 @@ func (h *Handler) GetShopFinanceOverview(c *gin.Context) {
 }
 
 func (h *Handler) GetShopStats(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 
 	result, err := h.shopService.GetShopStats(userId)
 	if err != nil {
@@ -100,7 +107,7 @@ func (h *Handler) GetShopInsights(c *gin.Context) {
 	var req dto.GetShopInsightRequest
 	_ = c.ShouldBindQuery(&req)
 	req.Validate()
-	req.UserId = c.GetInt("userId")
+	req.UserId = c.GetInt("shopOwnerId")
 
 	result, err := h.shopService.GetShopInsight(req)
 	if err != nil {
@@ -190,7 +197,7 @@ func (h *Handler) CreateShop(c *gin.Context) {
 }
 
 func (h *Handler) GetShopRating(c *gin.Context) {
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 	var req dto.GetShopRatingFilterRequest
 	_ = c.ShouldBindQuery(&req)
 
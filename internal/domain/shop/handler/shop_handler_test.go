@@ -6,6 +6,7 @@ import (
 	"kedai/backend/be-kedai/internal/common/code"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errs "kedai/backend/be-kedai/internal/common/error"
+	chatDto "kedai/backend/be-kedai/internal/domain/chat/dto"
 	"kedai/backend/be-kedai/internal/domain/shop/dto"
 	"kedai/backend/be-kedai/internal/domain/shop/handler"
 	"kedai/backend/be-kedai/internal/domain/shop/model"
@@ -113,7 +114,7 @@ func TestGetShopRating(t *testing.T) {
 			handler := handler.New(&handler.HandlerConfig{
 				ShopService: mockService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 
 			c.Request, _ = http.NewRequest("GET", "/sellers/ratings", nil)
 
@@ -132,11 +133,17 @@ func TestFindShopBySlug(t *testing.T) {
 		shopResult = &model.Shop{
 			ID: 1,
 		}
+		performance = &chatDto.ChatPerformance{
+			ResponseRate:           87.5,
+			AverageResponseSeconds: 300,
+		}
 	)
 
 	type input struct {
-		shop *model.Shop
-		err  error
+		shop           *model.Shop
+		err            error
+		performance    *chatDto.ChatPerformance
+		performanceErr error
 	}
 
 	type expected struct {
@@ -154,15 +161,32 @@ func TestFindShopBySlug(t *testing.T) {
 		{
 			description: "should return shop information with code 200 when success",
 			input: input{
-				shop: shopResult,
-				err:  nil,
+				shop:        shopResult,
+				err:         nil,
+				performance: performance,
 			},
 			expected: expected{
 				statusCode: http.StatusOK,
 				response: response.Response{
 					Code:    code.OK,
 					Message: "ok",
-					Data:    shopResult,
+					Data:    &dto.ShopDetail{Shop: shopResult, ChatPerformance: performance},
+				},
+			},
+		},
+		{
+			description: "should return shop information without chat performance when it fails",
+			input: input{
+				shop:           shopResult,
+				err:            nil,
+				performanceErr: errors.New("error"),
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+					Data:    &dto.ShopDetail{Shop: shopResult},
 				},
 			},
 		},
@@ -207,8 +231,13 @@ func TestFindShopBySlug(t *testing.T) {
 			}
 			mockService := new(mocks.ShopService)
 			mockService.On("FindShopBySlug", slug).Return(tc.input.shop, tc.input.err)
+			mockChatService := new(mocks.ChatService)
+			if tc.input.shop != nil {
+				mockChatService.On("GetShopPerformance", tc.input.shop.ID).Return(tc.input.performance, tc.input.performanceErr)
+			}
 			handler := handler.New(&handler.HandlerConfig{
 				ShopService: mockService,
+				ChatService: mockChatService,
 			})
 			c.Request, _ = http.NewRequest("GET", "/shops/:slug", nil)
 
@@ -382,7 +411,7 @@ func TestGetShopFinanceOverview(t *testing.T) {
 			handler := handler.New(&handler.HandlerConfig{
 				ShopService: mockService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 
 			c.Request, _ = http.NewRequest("GET", "/shops/1/finance/overview", nil)
 
@@ -474,7 +503,7 @@ func TestGetShopStats(t *testing.T) {
 			handler := handler.New(&handler.HandlerConfig{
 				ShopService: mockService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 
 			c.Request, _ = http.NewRequest("GET", "/v1/sellers/stats", nil)
 			handler.GetShopStats(c)
@@ -686,7 +715,7 @@ func TestGetShopInsight(t *testing.T) {
 			handler := handler.New(&handler.HandlerConfig{
 				ShopService: mockService,
 			})
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 
 			c.Request, _ = http.NewRequest("GET", "/v1/sellers/insights", nil)
 			handler.GetShopInsights(c)
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/shop/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetShopMember runs on seller routes and resolves the shop the user acts
+// for, taken from the X-Shop-Id header when they belong to several. Seller
+// handlers look the shop up by its owner in shopOwnerId, while userId stays
+// the user acting for it.
+func (h *Handler) GetShopMember(c *gin.Context) {
+	userId := c.GetInt("userId")
+	shopId, _ := strconv.Atoi(c.GetHeader("X-Shop-Id"))
+
+	membership, err := h.shopMemberService.GetMembership(userId, shopId)
+	if err != nil {
+		if errors.Is(err, errs.ErrShopNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Response{
+				Code:    code.SHOP_NOT_REGISTERED,
+				Message: err.Error(),
+			})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Response{
+			Code:    code.INTERNAL_SERVER_ERROR,
+			Message: errs.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	c.Set("shopOwnerId", membership.OwnerID)
+	c.Set("shopId", membership.ShopID)
+	c.Set("shopMembership", membership)
+	c.Set("shopPermissions", membership.Permissions)
+}
+
+func (h *Handler) GetShopMembership(c *gin.Context) {
+	membership := c.MustGet("shopMembership").(*dto.ShopMembership)
+
+	response.Success(c, http.StatusOK, code.OK, "ok", membership)
+}
+
+func (h *Handler) GetShopMembers(c *gin.Context) {
+	membership := c.MustGet("shopMembership").(*dto.ShopMembership)
+
+	res, err := h.shopMemberService.GetMembers(membership)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", res)
+}
+
+func (h *Handler) InviteShopMember(c *gin.Context) {
+	membership := c.MustGet("shopMembership").(*dto.ShopMembership)
+
+	var request dto.InviteShopMemberRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.shopMemberService.InviteMember(membership, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrShopPermissionDenied) {
+			response.Error(c, http.StatusForbidden, code.FORBIDDEN, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrShopMemberAlreadyExists) {
+			response.Error(c, http.StatusConflict, code.SHOP_MEMBER_ALREADY_EXISTS, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "invitation sent", res)
+}
+
+func (h *Handler) UpdateShopMember(c *gin.Context) {
+	membership := c.MustGet("shopMembership").(*dto.ShopMembership)
+
+	memberId, err := strconv.Atoi(c.Param("memberId"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, code.SHOP_MEMBER_NOT_FOUND, errs.ErrShopMemberNotFound.Error())
+		return
+	}
+
+	var request dto.UpdateShopMemberRequest
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.shopMemberService.UpdateMemberRole(membership, memberId, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrCannotModifyOwnShopMember) {
+			response.Error(c, http.StatusBadRequest, code.CANNOT_MODIFY_OWN_SHOP_MEMBER, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrShopPermissionDenied) {
+			response.Error(c, http.StatusForbidden, code.FORBIDDEN, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrShopMemberNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_MEMBER_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "updated", res)
+}
+
+func (h *Handler) RevokeShopMember(c *gin.Context) {
+	membership := c.MustGet("shopMembership").(*dto.ShopMembership)
+
+	memberId, err := strconv.Atoi(c.Param("memberId"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, code.SHOP_MEMBER_NOT_FOUND, errs.ErrShopMemberNotFound.Error())
+		return
+	}
+
+	err = h.shopMemberService.RevokeMember(membership, memberId)
+	if err != nil {
+		if errors.Is(err, errs.ErrCannotModifyOwnShopMember) {
+			response.Error(c, http.StatusBadRequest, code.CANNOT_MODIFY_OWN_SHOP_MEMBER, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrShopPermissionDenied) {
+			response.Error(c, http.StatusForbidden, code.FORBIDDEN, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrShopMemberNotFound) {
+			response.Error(c, http.StatusNotFound, code.SHOP_MEMBER_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "revoked", nil)
+}
+
+func (h *Handler) GetUserShopMemberships(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	res, err := h.shopMemberService.GetMemberships(userId)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", res)
+}
+
+func (h *Handler) AcceptShopInvitation(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	var request dto.AcceptShopInvitationRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.shopMemberService.AcceptInvitation(userId, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidShopInvitation) {
+			response.Error(c, http.StatusBadRequest, code.INVALID_SHOP_INVITATION, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrShopMemberAlreadyExists) {
+			response.Error(c, http.StatusConflict, code.SHOP_MEMBER_ALREADY_EXISTS, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrShopInvitationEmailMismatch) {
+			response.Error(c, http.StatusForbidden, code.SHOP_INVITATION_EMAIL_MISMATCH, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "invitation accepted", res)
+}
@@ -0,0 +1,355 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/shop/dto"
+	"kedai/backend/be-kedai/internal/domain/shop/handler"
+	"kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetShopMember(t *testing.T) {
+	t.Run("should abort with code 404 when user has no shop", func(t *testing.T) {
+		expectedBody, _ := json.Marshal(response.Response{
+			Code:    code.SHOP_NOT_REGISTERED,
+			Message: errs.ErrShopNotFound.Error(),
+		})
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		mockShopMemberService := mocks.NewShopMemberService(t)
+		mockShopMemberService.On("GetMembership", 2, 0).Return(nil, errs.ErrShopNotFound)
+		handler := handler.New(&handler.HandlerConfig{
+			ShopMemberService: mockShopMemberService,
+		})
+		c.Set("userId", 2)
+		c.Request, _ = http.NewRequest("GET", "/sellers/stats", nil)
+
+		handler.GetShopMember(c)
+
+		assert.True(t, c.IsAborted())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, string(expectedBody), rec.Body.String())
+	})
+
+	t.Run("should keep the caller and set the owner of the requested shop", func(t *testing.T) {
+		membership := dto.NewShopMembership(3, 1, 2, constant.ShopRoleOrderOps)
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		mockShopMemberService := mocks.NewShopMemberService(t)
+		mockShopMemberService.On("GetMembership", 2, 3).Return(membership, nil)
+		handler := handler.New(&handler.HandlerConfig{
+			ShopMemberService: mockShopMemberService,
+		})
+		c.Set("userId", 2)
+		c.Request, _ = http.NewRequest("GET", "/sellers/orders", nil)
+		c.Request.Header.Set("X-Shop-Id", "3")
+
+		handler.GetShopMember(c)
+
+		assert.False(t, c.IsAborted())
+		assert.Equal(t, 2, c.GetInt("userId"))
+		assert.Equal(t, 1, c.GetInt("shopOwnerId"))
+		assert.Equal(t, 3, c.GetInt("shopId"))
+		assert.Equal(t, []string{constant.ShopPermissionManageOrders}, c.GetStringSlice("shopPermissions"))
+	})
+}
+
+func TestInviteShopMember(t *testing.T) {
+	var (
+		membership = dto.NewShopMembership(3, 1, 1, constant.ShopRoleOwner)
+		req        = &dto.InviteShopMemberRequest{Email: "staff@kedai.com", Role: constant.ShopRoleChatAgent}
+		result     = &model.ShopMember{ID: 4, ShopID: 3, Email: "staff@kedai.com", Role: constant.ShopRoleChatAgent, Status: constant.ShopMemberStatusInvited, InvitedByID: 1}
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		body        interface{}
+		beforeTests func(sms *mocks.ShopMemberService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when role is unknown",
+			body:        &dto.InviteShopMemberRequest{Email: "staff@kedai.com", Role: constant.ShopRoleOwner},
+			beforeTests: func(sms *mocks.ShopMemberService) {},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Role must be either admin, order_ops, chat_agent, or finance",
+				},
+			},
+		},
+		{
+			description: "should return error with code 403 when role is above the inviter",
+			body:        req,
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("InviteMember", membership, req).Return(nil, errs.ErrShopPermissionDenied)
+			},
+			expected: expected{
+				statusCode: http.StatusForbidden,
+				response: response.Response{
+					Code:    code.FORBIDDEN,
+					Message: errs.ErrShopPermissionDenied.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 409 when email already works for the shop",
+			body:        req,
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("InviteMember", membership, req).Return(nil, errs.ErrShopMemberAlreadyExists)
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.SHOP_MEMBER_ALREADY_EXISTS,
+					Message: errs.ErrShopMemberAlreadyExists.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 500 when error",
+			body:        req,
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("InviteMember", membership, req).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return invited member with code 201 when success",
+			body:        req,
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("InviteMember", membership, req).Return(result, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusCreated,
+				response: response.Response{
+					Code:    code.CREATED,
+					Message: "invitation sent",
+					Data:    result,
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockShopMemberService := mocks.NewShopMemberService(t)
+			tc.beforeTests(mockShopMemberService)
+			handler := handler.New(&handler.HandlerConfig{
+				ShopMemberService: mockShopMemberService,
+			})
+			c.Set("shopMembership", membership)
+			c.Request, _ = http.NewRequest("POST", "/sellers/staff", test.MakeRequestBody(tc.body))
+
+			handler.InviteShopMember(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestRevokeShopMember(t *testing.T) {
+	var membership = dto.NewShopMembership(3, 1, 2, constant.ShopRoleAdmin)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		param       string
+		beforeTests func(sms *mocks.ShopMemberService)
+		expected
+	}{
+		{
+			description: "should return error with code 404 when member id is invalid",
+			param:       "abc",
+			beforeTests: func(sms *mocks.ShopMemberService) {},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.SHOP_MEMBER_NOT_FOUND,
+					Message: errs.ErrShopMemberNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 400 when revoking yourself",
+			param:       "4",
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("RevokeMember", membership, 4).Return(errs.ErrCannotModifyOwnShopMember)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.CANNOT_MODIFY_OWN_SHOP_MEMBER,
+					Message: errs.ErrCannotModifyOwnShopMember.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 403 when revoking an admin as admin",
+			param:       "4",
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("RevokeMember", membership, 4).Return(errs.ErrShopPermissionDenied)
+			},
+			expected: expected{
+				statusCode: http.StatusForbidden,
+				response: response.Response{
+					Code:    code.FORBIDDEN,
+					Message: errs.ErrShopPermissionDenied.Error(),
+				},
+			},
+		},
+		{
+			description: "should return code 200 when revoked",
+			param:       "4",
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("RevokeMember", membership, 4).Return(nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "revoked",
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockShopMemberService := mocks.NewShopMemberService(t)
+			tc.beforeTests(mockShopMemberService)
+			handler := handler.New(&handler.HandlerConfig{
+				ShopMemberService: mockShopMemberService,
+			})
+			c.Set("shopMembership", membership)
+			c.AddParam("memberId", tc.param)
+			c.Request, _ = http.NewRequest("DELETE", "/sellers/staff/"+tc.param, nil)
+
+			handler.RevokeShopMember(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
+
+func TestAcceptShopInvitation(t *testing.T) {
+	var (
+		req    = &dto.AcceptShopInvitationRequest{Token: "token"}
+		userId = 2
+	)
+
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	for _, tc := range []struct {
+		description string
+		beforeTests func(sms *mocks.ShopMemberService)
+		expected
+	}{
+		{
+			description: "should return error with code 400 when invitation is invalid",
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("AcceptInvitation", userId, req).Return(nil, errs.ErrInvalidShopInvitation)
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.INVALID_SHOP_INVITATION,
+					Message: errs.ErrInvalidShopInvitation.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 409 when user already works for the shop",
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("AcceptInvitation", userId, req).Return(nil, errs.ErrShopMemberAlreadyExists)
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.SHOP_MEMBER_ALREADY_EXISTS,
+					Message: errs.ErrShopMemberAlreadyExists.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with code 403 when invitation was sent to another email",
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("AcceptInvitation", userId, req).Return(nil, errs.ErrShopInvitationEmailMismatch)
+			},
+			expected: expected{
+				statusCode: http.StatusForbidden,
+				response: response.Response{
+					Code:    code.SHOP_INVITATION_EMAIL_MISMATCH,
+					Message: errs.ErrShopInvitationEmailMismatch.Error(),
+				},
+			},
+		},
+		{
+			description: "should return membership with code 200 when accepted",
+			beforeTests: func(sms *mocks.ShopMemberService) {
+				sms.On("AcceptInvitation", userId, req).Return(&model.ShopMember{ID: 4, ShopID: 3, UserID: &userId, Role: constant.ShopRoleFinance, Status: constant.ShopMemberStatusActive}, nil)
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "invitation accepted",
+					Data:    &model.ShopMember{ID: 4, ShopID: 3, UserID: &userId, Role: constant.ShopRoleFinance, Status: constant.ShopMemberStatusActive},
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedBody, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			mockShopMemberService := mocks.NewShopMemberService(t)
+			tc.beforeTests(mockShopMemberService)
+			handler := handler.New(&handler.HandlerConfig{
+				ShopMemberService: mockShopMemberService,
+			})
+			c.Set("userId", userId)
+			c.Request, _ = http.NewRequest("POST", "/users/shop-memberships/accept", test.MakeRequestBody(req))
+
+			handler.AcceptShopInvitation(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedBody), rec.Body.String())
+		})
+	}
+}
@@ -18,7 +18,7 @@ func (h *Handler) GetSellerPromotions(c *gin.Context) {
 
 	request.Validate()
 
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	res, err := h.shopPromotionService.GetSellerPromotions(userID, &request)
 	if err != nil {
@@ -35,7 +35,7 @@ func (h *Handler) GetSellerPromotions(c *gin.Context) {
 }
 
 func (h *Handler) GetSellerPromotionById(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	promotionId, _ := strconv.Atoi(c.Param("promotionId"))
 
 	res, err := h.shopPromotionService.GetSellerPromotionById(userId, promotionId)
@@ -58,7 +58,7 @@ func (h *Handler) GetSellerPromotionById(c *gin.Context) {
 }
 
 func (h *Handler) UpdatePromotion(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	promotionId, _ := strconv.Atoi(c.Param("promotionId"))
 
 	var req dto.UpdateShopPromotionRequest
@@ -109,7 +109,7 @@ func (h *Handler) CreateShopPromotion(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	promotion, err := h.shopPromotionService.CreateShopPromotion(userID, &request)
 	if err != nil {
@@ -141,7 +141,7 @@ func (h *Handler) CreateShopPromotion(c *gin.Context) {
 }
 
 func (h *Handler) DeletePromotion(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	promotionId, _ := strconv.Atoi(c.Param("promotionId"))
 
 	err := h.shopPromotionService.DeletePromotion(userId, promotionId)
@@ -121,7 +121,7 @@ func TestGetSellerPromotion(t *testing.T) {
 		shopPromotionService.On("GetSellerPromotions", tc.input.userID, tc.input.request).Return(tc.input.mockData, tc.input.mockErr)
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
-		c.Set("userId", tc.input.userID)
+		c.Set("shopOwnerId", tc.input.userID)
 		h := handler.New(&handler.HandlerConfig{
 			ShopPromotionService: shopPromotionService,
 		})
@@ -229,7 +229,7 @@ func TestGetSellerPromotionById(t *testing.T) {
 		shopPromotionService.On("GetSellerPromotionById", tc.input.userID, tc.input.promotionId).Return(tc.input.mockData, tc.input.mockErr)
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
-		c.Set("userId", tc.input.userID)
+		c.Set("shopOwnerId", tc.input.userID)
 		c.AddParam("promotionId", "1")
 		h := handler.New(&handler.HandlerConfig{
 			ShopPromotionService: shopPromotionService,
@@ -432,7 +432,7 @@ func TestUpdatePromotion(t *testing.T) {
 			tc.beforeTest(shopPromotionService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			c.AddParam("promotionId", "1")
 			h := handler.New(&handler.HandlerConfig{
 				ShopPromotionService: shopPromotionService,
@@ -593,7 +593,7 @@ func TestCreatePromotion(t *testing.T) {
 			tc.beforeTest(shopPromotionService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			h := handler.New(&handler.HandlerConfig{
 				ShopPromotionService: shopPromotionService,
 			})
@@ -706,7 +706,7 @@ func TestDeletePromotion(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			c.Params = gin.Params{
 				{
 					Key:   "promotionId",
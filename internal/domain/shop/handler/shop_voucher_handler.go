@@ -33,7 +33,7 @@ func (h *Handler) GetSellerVoucher(c *gin.Context) {
 
 	request.Validate()
 
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	res, err := h.shopVoucherService.GetSellerVoucher(userID, &request)
 	if err != nil {
@@ -50,7 +50,7 @@ func (h *Handler) GetSellerVoucher(c *gin.Context) {
 }
 
 func (h *Handler) GetVoucherByCodeAndShopId(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	voucherCode := c.Param("code")
 
 	res, err := h.shopVoucherService.GetVoucherByCodeAndShopId(voucherCode, userId)
@@ -80,7 +80,7 @@ func (h *Handler) CreateVoucher(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetInt("userId")
+	userID := c.GetInt("shopOwnerId")
 
 	product, err := h.shopVoucherService.CreateVoucher(userID, &request)
 	if err != nil {
@@ -112,7 +112,7 @@ func (h *Handler) CreateVoucher(c *gin.Context) {
 }
 
 func (h *Handler) UpdateVoucher(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	voucherCode := c.Param("code")
 
 	var req dto.UpdateVoucherRequest
@@ -157,7 +157,7 @@ func (h *Handler) UpdateVoucher(c *gin.Context) {
 }
 
 func (h *Handler) DeleteVoucher(c *gin.Context) {
-	userId := c.GetInt("userId")
+	userId := c.GetInt("shopOwnerId")
 	voucherCode := c.Param("code")
 
 	err := h.shopVoucherService.DeleteVoucher(userId, voucherCode)
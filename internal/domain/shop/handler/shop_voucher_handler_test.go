@@ -215,7 +215,7 @@ func TestGetSellerVoucher(t *testing.T) {
 		shopVoucherService.On("GetSellerVoucher", tc.input.userID, tc.input.request).Return(tc.input.mockData, tc.input.mockErr)
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
-		c.Set("userId", tc.input.userID)
+		c.Set("shopOwnerId", tc.input.userID)
 		h := handler.New(&handler.HandlerConfig{
 			ShopVoucherService: shopVoucherService,
 		})
@@ -324,7 +324,7 @@ func TestGetVoucherByCodeAndShopId(t *testing.T) {
 			shopVoucherService.On("GetVoucherByCodeAndShopId", tc.input.voucherCode, tc.input.userID).Return(tc.input.mockData, tc.input.mockErr)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			c.AddParam("code", tc.input.voucherCode)
 			h := handler.New(&handler.HandlerConfig{
 				ShopVoucherService: shopVoucherService,
@@ -520,7 +520,7 @@ func TestCreateVoucher(t *testing.T) {
 			tc.beforeTest(shopVoucherService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			h := handler.New(&handler.HandlerConfig{
 				ShopVoucherService: shopVoucherService,
 			})
@@ -633,7 +633,7 @@ func TestDeleteVoucher(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", 1)
+			c.Set("shopOwnerId", 1)
 			c.Params = gin.Params{
 				{
 					Key:   "code",
@@ -864,7 +864,7 @@ func TestUpdateVoucher(t *testing.T) {
 			tc.beforeTest(shopVoucherService)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			c.Set("userId", tc.input.userID)
+			c.Set("shopOwnerId", tc.input.userID)
 			c.AddParam("code", tc.input.voucherCode)
 			h := handler.New(&handler.HandlerConfig{
 				ShopVoucherService: shopVoucherService,
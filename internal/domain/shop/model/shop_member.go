@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShopMember is a user invited to work for a shop. UserID is set once the
+// invitation is accepted.
+type ShopMember struct {
+	ID              int        `json:"id"`
+	ShopID          int        `json:"shopId"`
+	Shop            *Shop      `json:"shop,omitempty"`
+	UserID          *int       `json:"userId"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	InvitedByID     int        `json:"invitedById"`
+	InviteToken     *string    `json:"-"`
+	InviteExpiresAt *time.Time `json:"-"`
+	JoinedAt        *time.Time `json:"joinedAt"`
+	RevokedAt       *time.Time `json:"revokedAt"`
+	RevokedByID     *int       `json:"revokedById"`
+
+	gorm.Model `json:"-"`
+}
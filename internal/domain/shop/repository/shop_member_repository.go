@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/shop/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShopMemberRepository interface {
+	GetByID(shopId int, id int) (*model.ShopMember, error)
+	GetByShopID(shopId int) ([]*model.ShopMember, error)
+	GetByEmail(shopId int, email string) (*model.ShopMember, error)
+	GetByInviteToken(token string) (*model.ShopMember, error)
+	GetActive(shopId int, userId int) (*model.ShopMember, error)
+	GetActiveByUserID(userId int) ([]*model.ShopMember, error)
+	Create(member *model.ShopMember) error
+	Save(member *model.ShopMember) error
+}
+
+type shopMemberRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type ShopMemberRConfig struct {
+	DB *gorm.DB
+}
+
+func NewShopMemberRepository(cfg *ShopMemberRConfig) ShopMemberRepository {
+	return &shopMemberRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *shopMemberRepositoryImpl) GetByID(shopId int, id int) (*model.ShopMember, error) {
+	var member model.ShopMember
+
+	err := r.db.Where("shop_id = ? AND id = ?", shopId, id).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrShopMemberNotFound
+		}
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *shopMemberRepositoryImpl) GetByShopID(shopId int) ([]*model.ShopMember, error) {
+	var members []*model.ShopMember
+
+	err := r.db.Where("shop_id = ?", shopId).Order("created_at").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *shopMemberRepositoryImpl) GetByEmail(shopId int, email string) (*model.ShopMember, error) {
+	var member model.ShopMember
+
+	err := r.db.Where("shop_id = ? AND lower(email) = lower(?)", shopId, email).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrShopMemberNotFound
+		}
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *shopMemberRepositoryImpl) GetByInviteToken(token string) (*model.ShopMember, error) {
+	var member model.ShopMember
+
+	err := r.db.Preload("Shop").Where("invite_token = ?", token).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrInvalidShopInvitation
+		}
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *shopMemberRepositoryImpl) GetActive(shopId int, userId int) (*model.ShopMember, error) {
+	var member model.ShopMember
+
+	err := r.db.Preload("Shop").
+		Where("shop_id = ? AND user_id = ? AND status = ?", shopId, userId, constant.ShopMemberStatusActive).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrShopNotFound
+		}
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *shopMemberRepositoryImpl) GetActiveByUserID(userId int) ([]*model.ShopMember, error) {
+	var members []*model.ShopMember
+
+	err := r.db.Preload("Shop").
+		Where("user_id = ? AND status = ?", userId, constant.ShopMemberStatusActive).
+		Order("joined_at").
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *shopMemberRepositoryImpl) Create(member *model.ShopMember) error {
+	return r.db.Omit(clause.Associations).Create(member).Error
+}
+
+func (r *shopMemberRepositoryImpl) Save(member *model.ShopMember) error {
+	return r.db.Omit(clause.Associations).Save(member).Error
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/domain/shop/dto"
+	"kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/internal/domain/shop/repository"
+	userRepository "kedai/backend/be-kedai/internal/domain/user/repository"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/internal/utils/random"
+	"log"
+	"strings"
+	"time"
+)
+
+type ShopMemberService interface {
+	GetMembership(userId int, shopId int) (*dto.ShopMembership, error)
+	GetMemberships(userId int) ([]*model.ShopMember, error)
+	GetMembers(membership *dto.ShopMembership) ([]*model.ShopMember, error)
+	InviteMember(membership *dto.ShopMembership, req *dto.InviteShopMemberRequest) (*model.ShopMember, error)
+	AcceptInvitation(userId int, req *dto.AcceptShopInvitationRequest) (*model.ShopMember, error)
+	UpdateMemberRole(membership *dto.ShopMembership, memberId int, req *dto.UpdateShopMemberRequest) (*model.ShopMember, error)
+	RevokeMember(membership *dto.ShopMembership, memberId int) error
+}
+
+type shopMemberServiceImpl struct {
+	shopMemberRepo repository.ShopMemberRepository
+	shopRepo       repository.ShopRepository
+	randomUtils    random.RandomUtils
+	emailPublisher notificationService.EmailPublisher
+	userRepo       userRepository.UserRepository
+}
+
+type ShopMemberSConfig struct {
+	ShopMemberRepo repository.ShopMemberRepository
+	ShopRepo       repository.ShopRepository
+	RandomUtils    random.RandomUtils
+	EmailPublisher notificationService.EmailPublisher
+	UserRepo       userRepository.UserRepository
+}
+
+func NewShopMemberService(cfg *ShopMemberSConfig) ShopMemberService {
+	return &shopMemberServiceImpl{
+		shopMemberRepo: cfg.ShopMemberRepo,
+		shopRepo:       cfg.ShopRepo,
+		randomUtils:    cfg.RandomUtils,
+		emailPublisher: cfg.EmailPublisher,
+		userRepo:       cfg.UserRepo,
+	}
+}
+
+// GetMembership resolves which shop userId is acting for. Without a shopId
+// an owner acts for their own shop and staff for the shop they joined first.
+func (s *shopMemberServiceImpl) GetMembership(userId int, shopId int) (*dto.ShopMembership, error) {
+	if shopId == 0 {
+		shop, err := s.shopRepo.FindShopByUserId(userId)
+		if err == nil {
+			return dto.NewShopMembership(shop.ID, userId, userId, constant.ShopRoleOwner), nil
+		}
+		if err != errs.ErrShopNotFound {
+			return nil, err
+		}
+
+		members, err := s.shopMemberRepo.GetActiveByUserID(userId)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) == 0 {
+			return nil, errs.ErrShopNotFound
+		}
+
+		return dto.NewShopMembership(members[0].ShopID, members[0].Shop.UserID, userId, members[0].Role), nil
+	}
+
+	shop, err := s.shopRepo.FindShopById(shopId)
+	if err != nil {
+		return nil, err
+	}
+	if shop.UserID == userId {
+		return dto.NewShopMembership(shop.ID, userId, userId, constant.ShopRoleOwner), nil
+	}
+
+	member, err := s.shopMemberRepo.GetActive(shopId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewShopMembership(shop.ID, shop.UserID, userId, member.Role), nil
+}
+
+func (s *shopMemberServiceImpl) GetMemberships(userId int) ([]*model.ShopMember, error) {
+	return s.shopMemberRepo.GetActiveByUserID(userId)
+}
+
+func (s *shopMemberServiceImpl) GetMembers(membership *dto.ShopMembership) ([]*model.ShopMember, error) {
+	return s.shopMemberRepo.GetByShopID(membership.ShopID)
+}
+
+// InviteMember emails an invitation to join the shop. Members that were
+// revoked or never accepted are invited again on the same row.
+func (s *shopMemberServiceImpl) InviteMember(membership *dto.ShopMembership, req *dto.InviteShopMemberRequest) (*model.ShopMember, error) {
+	if req.Role == constant.ShopRoleAdmin && membership.Role != constant.ShopRoleOwner {
+		return nil, errs.ErrShopPermissionDenied
+	}
+
+	shop, err := s.shopRepo.FindShopById(membership.ShopID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.shopMemberRepo.GetByEmail(membership.ShopID, req.Email)
+	if err != nil && err != errs.ErrShopMemberNotFound {
+		return nil, err
+	}
+	if member != nil && member.Status == constant.ShopMemberStatusActive {
+		return nil, errs.ErrShopMemberAlreadyExists
+	}
+	if member == nil {
+		member = &model.ShopMember{ShopID: membership.ShopID, Email: req.Email}
+	}
+
+	token := s.randomUtils.GenerateSecureUniqueToken()
+	hashedToken := hash.HashSHA256(token)
+	expiresAt := time.Now().Add(constant.ShopInvitationAge)
+
+	member.Role = req.Role
+	member.Status = constant.ShopMemberStatusInvited
+	member.UserID = nil
+	member.InvitedByID = membership.MemberID
+	member.InviteToken = &hashedToken
+	member.InviteExpiresAt = &expiresAt
+	member.JoinedAt = nil
+	member.RevokedAt = nil
+	member.RevokedByID = nil
+
+	if member.ID == 0 {
+		err = s.shopMemberRepo.Create(member)
+	} else {
+		err = s.shopMemberRepo.Save(member)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.emailPublisher != nil {
+		err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+			To:       member.Email,
+			Category: constant.NotificationCategorySecurity,
+			Template: mail.TemplateShopInvitation,
+			Data: &mail.ShopInvitationEmailData{
+				ShopName:      shop.Name,
+				Role:          member.Role,
+				InvitationURL: config.GetEnv("FRONTEND_URL", "http://localhost:3000") + config.GetEnv("SHOP_INVITATION_URL", "/shop-invitations?token=") + token,
+				ExpiresAt:     expiresAt.Format("2 January 2006 15:04 MST"),
+			},
+		})
+		if err != nil {
+			log.Println("failed to enqueue shop invitation email to", member.Email, ":", err)
+		}
+	}
+
+	return member, nil
+}
+
+// AcceptInvitation joins userId to the shop. The invitation token is the
+// proof the user received the email, so it is single use, and only the
+// account registered with the invited email may redeem it.
+func (s *shopMemberServiceImpl) AcceptInvitation(userId int, req *dto.AcceptShopInvitationRequest) (*model.ShopMember, error) {
+	member, err := s.shopMemberRepo.GetByInviteToken(hash.HashSHA256(req.Token))
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Status != constant.ShopMemberStatusInvited || member.InviteExpiresAt == nil || time.Now().After(*member.InviteExpiresAt) {
+		return nil, errs.ErrInvalidShopInvitation
+	}
+
+	if member.Shop != nil && member.Shop.UserID == userId {
+		return nil, errs.ErrShopMemberAlreadyExists
+	}
+
+	user, err := s.userRepo.GetByID(userId)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, member.Email) {
+		return nil, errs.ErrShopInvitationEmailMismatch
+	}
+
+	_, err = s.shopMemberRepo.GetActive(member.ShopID, userId)
+	if err == nil {
+		return nil, errs.ErrShopMemberAlreadyExists
+	}
+	if err != errs.ErrShopNotFound {
+		return nil, err
+	}
+
+	now := time.Now()
+	member.UserID = &userId
+	member.Status = constant.ShopMemberStatusActive
+	member.JoinedAt = &now
+	member.InviteToken = nil
+	member.InviteExpiresAt = nil
+
+	err = s.shopMemberRepo.Save(member)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (s *shopMemberServiceImpl) UpdateMemberRole(membership *dto.ShopMembership, memberId int, req *dto.UpdateShopMemberRequest) (*model.ShopMember, error) {
+	member, err := s.getManageableMember(membership, memberId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role == constant.ShopRoleAdmin && membership.Role != constant.ShopRoleOwner {
+		return nil, errs.ErrShopPermissionDenied
+	}
+
+	member.Role = req.Role
+	err = s.shopMemberRepo.Save(member)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RevokeMember removes the member's access. The row stays so actions they
+// took remain attributed to them.
+func (s *shopMemberServiceImpl) RevokeMember(membership *dto.ShopMembership, memberId int) error {
+	member, err := s.getManageableMember(membership, memberId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	member.Status = constant.ShopMemberStatusRevoked
+	member.RevokedAt = &now
+	member.RevokedByID = &membership.MemberID
+	member.InviteToken = nil
+	member.InviteExpiresAt = nil
+
+	return s.shopMemberRepo.Save(member)
+}
+
+// getManageableMember loads a member of the acting shop that the acting
+// member may change: not themself, and only the owner may touch admins.
+func (s *shopMemberServiceImpl) getManageableMember(membership *dto.ShopMembership, memberId int) (*model.ShopMember, error) {
+	member, err := s.shopMemberRepo.GetByID(membership.ShopID, memberId)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Status == constant.ShopMemberStatusRevoked {
+		return nil, errs.ErrShopMemberNotFound
+	}
+
+	if member.UserID != nil && *member.UserID == membership.MemberID {
+		return nil, errs.ErrCannotModifyOwnShopMember
+	}
+
+	if member.Role == constant.ShopRoleAdmin && membership.Role != constant.ShopRoleOwner {
+		return nil, errs.ErrShopPermissionDenied
+	}
+
+	return member, nil
+}
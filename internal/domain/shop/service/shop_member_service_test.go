@@ -0,0 +1,416 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/shop/dto"
+	"kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/internal/domain/shop/service"
+	userModel "kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetMembership(t *testing.T) {
+	var (
+		shop     = &model.Shop{ID: 3, UserID: 1}
+		memberId = 2
+	)
+
+	tests := []struct {
+		description string
+		userId      int
+		shopId      int
+		beforeTest  func(*mocks.ShopMemberRepository, *mocks.ShopRepository)
+		expected    *dto.ShopMembership
+		err         error
+	}{
+		{
+			description: "should return owner membership when user owns a shop",
+			userId:      1,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository) {
+				sr.On("FindShopByUserId", 1).Return(shop, nil)
+			},
+			expected: dto.NewShopMembership(3, 1, 1, constant.ShopRoleOwner),
+		},
+		{
+			description: "should return first active membership when user owns no shop",
+			userId:      memberId,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository) {
+				sr.On("FindShopByUserId", memberId).Return(nil, errs.ErrShopNotFound)
+				smr.On("GetActiveByUserID", memberId).Return([]*model.ShopMember{{ShopID: 3, Shop: shop, Role: constant.ShopRoleOrderOps}}, nil)
+			},
+			expected: dto.NewShopMembership(3, 1, memberId, constant.ShopRoleOrderOps),
+		},
+		{
+			description: "should return error when user neither owns nor works for a shop",
+			userId:      memberId,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository) {
+				sr.On("FindShopByUserId", memberId).Return(nil, errs.ErrShopNotFound)
+				smr.On("GetActiveByUserID", memberId).Return([]*model.ShopMember{}, nil)
+			},
+			err: errs.ErrShopNotFound,
+		},
+		{
+			description: "should return error when finding owned shop fails",
+			userId:      memberId,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository) {
+				sr.On("FindShopByUserId", memberId).Return(nil, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return membership of the requested shop",
+			userId:      memberId,
+			shopId:      3,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository) {
+				sr.On("FindShopById", 3).Return(shop, nil)
+				smr.On("GetActive", 3, memberId).Return(&model.ShopMember{ShopID: 3, Role: constant.ShopRoleFinance}, nil)
+			},
+			expected: dto.NewShopMembership(3, 1, memberId, constant.ShopRoleFinance),
+		},
+		{
+			description: "should return error when user does not work for the requested shop",
+			userId:      memberId,
+			shopId:      3,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository) {
+				sr.On("FindShopById", 3).Return(shop, nil)
+				smr.On("GetActive", 3, memberId).Return(nil, errs.ErrShopNotFound)
+			},
+			err: errs.ErrShopNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopMemberRepo := mocks.NewShopMemberRepository(t)
+			mockShopRepo := mocks.NewShopRepository(t)
+			tc.beforeTest(mockShopMemberRepo, mockShopRepo)
+			shopMemberService := service.NewShopMemberService(&service.ShopMemberSConfig{
+				ShopMemberRepo: mockShopMemberRepo,
+				ShopRepo:       mockShopRepo,
+			})
+
+			result, err := shopMemberService.GetMembership(tc.userId, tc.shopId)
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestInviteMember(t *testing.T) {
+	var (
+		shop  = &model.Shop{ID: 3, UserID: 1, Name: "Kedai Jaya"}
+		owner = dto.NewShopMembership(3, 1, 1, constant.ShopRoleOwner)
+		admin = dto.NewShopMembership(3, 1, 2, constant.ShopRoleAdmin)
+		req   = &dto.InviteShopMemberRequest{Email: "staff@kedai.com", Role: constant.ShopRoleOrderOps}
+	)
+
+	tests := []struct {
+		description string
+		membership  *dto.ShopMembership
+		req         *dto.InviteShopMemberRequest
+		beforeTest  func(*mocks.ShopMemberRepository, *mocks.ShopRepository, *mocks.RandomUtils, *mocks.EmailPublisher)
+		err         error
+	}{
+		{
+			description: "should return error when an admin invites another admin",
+			membership:  admin,
+			req:         &dto.InviteShopMemberRequest{Email: req.Email, Role: constant.ShopRoleAdmin},
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+			},
+			err: errs.ErrShopPermissionDenied,
+		},
+		{
+			description: "should return error when email already works for the shop",
+			membership:  owner,
+			req:         req,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+				sr.On("FindShopById", 3).Return(shop, nil)
+				smr.On("GetByEmail", 3, req.Email).Return(&model.ShopMember{ID: 4, Status: constant.ShopMemberStatusActive}, nil)
+			},
+			err: errs.ErrShopMemberAlreadyExists,
+		},
+		{
+			description: "should re-invite a revoked member",
+			membership:  admin,
+			req:         req,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+				userId := 5
+				revokedAt := time.Now()
+				sr.On("FindShopById", 3).Return(shop, nil)
+				smr.On("GetByEmail", 3, req.Email).Return(&model.ShopMember{ID: 4, UserID: &userId, Status: constant.ShopMemberStatusRevoked, RevokedAt: &revokedAt}, nil)
+				ru.On("GenerateSecureUniqueToken").Return("token")
+				smr.On("Save", mock.MatchedBy(func(member *model.ShopMember) bool {
+					return member.ID == 4 && member.Status == constant.ShopMemberStatusInvited && member.UserID == nil &&
+						member.RevokedAt == nil && member.InvitedByID == 2 && *member.InviteToken == hash.HashSHA256("token")
+				})).Return(nil)
+				ep.On("Enqueue", mock.Anything).Return(nil)
+			},
+		},
+		{
+			description: "should create invited member and email the invitation",
+			membership:  owner,
+			req:         req,
+			beforeTest: func(smr *mocks.ShopMemberRepository, sr *mocks.ShopRepository, ru *mocks.RandomUtils, ep *mocks.EmailPublisher) {
+				sr.On("FindShopById", 3).Return(shop, nil)
+				smr.On("GetByEmail", 3, req.Email).Return(nil, errs.ErrShopMemberNotFound)
+				ru.On("GenerateSecureUniqueToken").Return("token")
+				smr.On("Create", mock.MatchedBy(func(member *model.ShopMember) bool {
+					return member.ShopID == 3 && member.Email == req.Email && member.Role == constant.ShopRoleOrderOps &&
+						member.Status == constant.ShopMemberStatusInvited && member.InvitedByID == 1
+				})).Return(nil)
+				ep.On("Enqueue", mock.MatchedBy(func(req *notificationDto.EmailRequest) bool {
+					data := req.Data.(*mail.ShopInvitationEmailData)
+					return req.Template == mail.TemplateShopInvitation && data.ShopName == "Kedai Jaya"
+				})).Return(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopMemberRepo := mocks.NewShopMemberRepository(t)
+			mockShopRepo := mocks.NewShopRepository(t)
+			mockRandomUtils := mocks.NewRandomUtils(t)
+			mockEmailPublisher := mocks.NewEmailPublisher(t)
+			tc.beforeTest(mockShopMemberRepo, mockShopRepo, mockRandomUtils, mockEmailPublisher)
+			shopMemberService := service.NewShopMemberService(&service.ShopMemberSConfig{
+				ShopMemberRepo: mockShopMemberRepo,
+				ShopRepo:       mockShopRepo,
+				RandomUtils:    mockRandomUtils,
+				EmailPublisher: mockEmailPublisher,
+			})
+
+			result, err := shopMemberService.InviteMember(tc.membership, tc.req)
+
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, tc.req.Role, result.Role)
+			}
+		})
+	}
+}
+
+func TestAcceptShopInvitation(t *testing.T) {
+	var (
+		shop    = &model.Shop{ID: 3, UserID: 1}
+		req     = &dto.AcceptShopInvitationRequest{Token: "token"}
+		token   = hash.HashSHA256("token")
+		expired = time.Now().Add(-time.Minute)
+		valid   = time.Now().Add(time.Hour)
+	)
+
+	tests := []struct {
+		description string
+		userId      int
+		beforeTest  func(*mocks.ShopMemberRepository, *mocks.UserRepository)
+		err         error
+	}{
+		{
+			description: "should return error when token is unknown",
+			userId:      2,
+			beforeTest: func(smr *mocks.ShopMemberRepository, ur *mocks.UserRepository) {
+				smr.On("GetByInviteToken", token).Return(nil, errs.ErrInvalidShopInvitation)
+			},
+			err: errs.ErrInvalidShopInvitation,
+		},
+		{
+			description: "should return error when invitation expired",
+			userId:      2,
+			beforeTest: func(smr *mocks.ShopMemberRepository, ur *mocks.UserRepository) {
+				smr.On("GetByInviteToken", token).Return(&model.ShopMember{ShopID: 3, Shop: shop, Status: constant.ShopMemberStatusInvited, InviteExpiresAt: &expired}, nil)
+			},
+			err: errs.ErrInvalidShopInvitation,
+		},
+		{
+			description: "should return error when the owner accepts an invitation to their own shop",
+			userId:      1,
+			beforeTest: func(smr *mocks.ShopMemberRepository, ur *mocks.UserRepository) {
+				smr.On("GetByInviteToken", token).Return(&model.ShopMember{ShopID: 3, Shop: shop, Email: "staff@kedai.com", Status: constant.ShopMemberStatusInvited, InviteExpiresAt: &valid}, nil)
+			},
+			err: errs.ErrShopMemberAlreadyExists,
+		},
+		{
+			description: "should return error when the user's email is not the invited one",
+			userId:      2,
+			beforeTest: func(smr *mocks.ShopMemberRepository, ur *mocks.UserRepository) {
+				smr.On("GetByInviteToken", token).Return(&model.ShopMember{ShopID: 3, Shop: shop, Email: "staff@kedai.com", Status: constant.ShopMemberStatusInvited, InviteExpiresAt: &valid}, nil)
+				ur.On("GetByID", 2).Return(&userModel.User{ID: 2, Email: "someone@kedai.com"}, nil)
+			},
+			err: errs.ErrShopInvitationEmailMismatch,
+		},
+		{
+			description: "should return error when user already works for the shop",
+			userId:      2,
+			beforeTest: func(smr *mocks.ShopMemberRepository, ur *mocks.UserRepository) {
+				smr.On("GetByInviteToken", token).Return(&model.ShopMember{ShopID: 3, Shop: shop, Email: "staff@kedai.com", Status: constant.ShopMemberStatusInvited, InviteExpiresAt: &valid}, nil)
+				ur.On("GetByID", 2).Return(&userModel.User{ID: 2, Email: "Staff@kedai.com"}, nil)
+				smr.On("GetActive", 3, 2).Return(&model.ShopMember{ID: 5}, nil)
+			},
+			err: errs.ErrShopMemberAlreadyExists,
+		},
+		{
+			description: "should activate membership and clear the token",
+			userId:      2,
+			beforeTest: func(smr *mocks.ShopMemberRepository, ur *mocks.UserRepository) {
+				smr.On("GetByInviteToken", token).Return(&model.ShopMember{ShopID: 3, Shop: shop, Email: "staff@kedai.com", Status: constant.ShopMemberStatusInvited, InviteToken: &token, InviteExpiresAt: &valid}, nil)
+				ur.On("GetByID", 2).Return(&userModel.User{ID: 2, Email: "Staff@kedai.com"}, nil)
+				smr.On("GetActive", 3, 2).Return(nil, errs.ErrShopNotFound)
+				smr.On("Save", mock.MatchedBy(func(member *model.ShopMember) bool {
+					return *member.UserID == 2 && member.Status == constant.ShopMemberStatusActive &&
+						member.JoinedAt != nil && member.InviteToken == nil
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopMemberRepo := mocks.NewShopMemberRepository(t)
+			mockUserRepo := mocks.NewUserRepository(t)
+			tc.beforeTest(mockShopMemberRepo, mockUserRepo)
+			shopMemberService := service.NewShopMemberService(&service.ShopMemberSConfig{
+				ShopMemberRepo: mockShopMemberRepo,
+				UserRepo:       mockUserRepo,
+			})
+
+			_, err := shopMemberService.AcceptInvitation(tc.userId, req)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestUpdateMemberRole(t *testing.T) {
+	var (
+		owner = dto.NewShopMembership(3, 1, 1, constant.ShopRoleOwner)
+		admin = dto.NewShopMembership(3, 1, 2, constant.ShopRoleAdmin)
+	)
+
+	tests := []struct {
+		description string
+		membership  *dto.ShopMembership
+		req         *dto.UpdateShopMemberRequest
+		beforeTest  func(*mocks.ShopMemberRepository)
+		err         error
+	}{
+		{
+			description: "should return error when member does not exist",
+			membership:  owner,
+			req:         &dto.UpdateShopMemberRequest{Role: constant.ShopRoleFinance},
+			beforeTest: func(smr *mocks.ShopMemberRepository) {
+				smr.On("GetByID", 3, 4).Return(nil, errs.ErrShopMemberNotFound)
+			},
+			err: errs.ErrShopMemberNotFound,
+		},
+		{
+			description: "should return error when member changes their own role",
+			membership:  admin,
+			req:         &dto.UpdateShopMemberRequest{Role: constant.ShopRoleFinance},
+			beforeTest: func(smr *mocks.ShopMemberRepository) {
+				userId := 2
+				smr.On("GetByID", 3, 4).Return(&model.ShopMember{ID: 4, UserID: &userId, Role: constant.ShopRoleAdmin, Status: constant.ShopMemberStatusActive}, nil)
+			},
+			err: errs.ErrCannotModifyOwnShopMember,
+		},
+		{
+			description: "should return error when an admin promotes a member to admin",
+			membership:  admin,
+			req:         &dto.UpdateShopMemberRequest{Role: constant.ShopRoleAdmin},
+			beforeTest: func(smr *mocks.ShopMemberRepository) {
+				smr.On("GetByID", 3, 4).Return(&model.ShopMember{ID: 4, Role: constant.ShopRoleChatAgent, Status: constant.ShopMemberStatusActive}, nil)
+			},
+			err: errs.ErrShopPermissionDenied,
+		},
+		{
+			description: "should update role when owner changes it",
+			membership:  owner,
+			req:         &dto.UpdateShopMemberRequest{Role: constant.ShopRoleAdmin},
+			beforeTest: func(smr *mocks.ShopMemberRepository) {
+				smr.On("GetByID", 3, 4).Return(&model.ShopMember{ID: 4, Role: constant.ShopRoleChatAgent, Status: constant.ShopMemberStatusActive}, nil)
+				smr.On("Save", mock.MatchedBy(func(member *model.ShopMember) bool {
+					return member.Role == constant.ShopRoleAdmin
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopMemberRepo := mocks.NewShopMemberRepository(t)
+			tc.beforeTest(mockShopMemberRepo)
+			shopMemberService := service.NewShopMemberService(&service.ShopMemberSConfig{
+				ShopMemberRepo: mockShopMemberRepo,
+			})
+
+			_, err := shopMemberService.UpdateMemberRole(tc.membership, 4, tc.req)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestRevokeMember(t *testing.T) {
+	var (
+		owner = dto.NewShopMembership(3, 1, 1, constant.ShopRoleOwner)
+		admin = dto.NewShopMembership(3, 1, 2, constant.ShopRoleAdmin)
+	)
+
+	tests := []struct {
+		description string
+		membership  *dto.ShopMembership
+		beforeTest  func(*mocks.ShopMemberRepository)
+		err         error
+	}{
+		{
+			description: "should return error when member was already revoked",
+			membership:  owner,
+			beforeTest: func(smr *mocks.ShopMemberRepository) {
+				smr.On("GetByID", 3, 4).Return(&model.ShopMember{ID: 4, Status: constant.ShopMemberStatusRevoked}, nil)
+			},
+			err: errs.ErrShopMemberNotFound,
+		},
+		{
+			description: "should return error when an admin revokes another admin",
+			membership:  admin,
+			beforeTest: func(smr *mocks.ShopMemberRepository) {
+				smr.On("GetByID", 3, 4).Return(&model.ShopMember{ID: 4, Role: constant.ShopRoleAdmin, Status: constant.ShopMemberStatusActive}, nil)
+			},
+			err: errs.ErrShopPermissionDenied,
+		},
+		{
+			description: "should revoke member and record who revoked them",
+			membership:  admin,
+			beforeTest: func(smr *mocks.ShopMemberRepository) {
+				smr.On("GetByID", 3, 4).Return(&model.ShopMember{ID: 4, Role: constant.ShopRoleOrderOps, Status: constant.ShopMemberStatusActive}, nil)
+				smr.On("Save", mock.MatchedBy(func(member *model.ShopMember) bool {
+					return member.Status == constant.ShopMemberStatusRevoked && member.RevokedAt != nil && *member.RevokedByID == 2
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopMemberRepo := mocks.NewShopMemberRepository(t)
+			tc.beforeTest(mockShopMemberRepo)
+			shopMemberService := service.NewShopMemberService(&service.ShopMemberSConfig{
+				ShopMemberRepo: mockShopMemberRepo,
+			})
+
+			err := shopMemberService.RevokeMember(tc.membership, 4)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
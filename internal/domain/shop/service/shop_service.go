@@ -16,6 +16,7 @@ type ShopService interface {
 	CreateShop(userID int, request *dto.CreateShopRequest) (*model.Shop, error)
 	FindShopById(id int) (*model.Shop, error)
 	FindShopByUserId(userId int) (*model.Shop, error)
+	FindShopForMember(userId int, permission string) (*model.Shop, error)
 	FindShopBySlug(slug string) (*model.Shop, error)
 	FindShopByKeyword(req dto.FindShopRequest) (*commonDto.PaginationResponse, error)
 	GetShopFinanceOverview(userId int) (*dto.ShopFinanceOverviewResponse, error)
@@ -28,17 +29,20 @@ type ShopService interface {
 
 type shopServiceImpl struct {
 	shopRepository        repository.ShopRepository
+	shopMemberRepository  repository.ShopMemberRepository
 	courierServiceService CourierServiceService
 }
 
 type ShopSConfig struct {
 	ShopRepository        repository.ShopRepository
+	ShopMemberRepository  repository.ShopMemberRepository
 	CourierServiceService CourierServiceService
 }
 
 func NewShopService(cfg *ShopSConfig) ShopService {
 	return &shopServiceImpl{
 		shopRepository:        cfg.ShopRepository,
+		shopMemberRepository:  cfg.ShopMemberRepository,
 		courierServiceService: cfg.CourierServiceService,
 	}
 }
@@ -51,6 +55,32 @@ func (s *shopServiceImpl) FindShopByUserId(userId int) (*model.Shop, error) {
 	return s.shopRepository.FindShopByUserId(userId)
 }
 
+// FindShopForMember returns the shop userId owns or, failing that, the first
+// shop they are active staff of whose role grants permission.
+func (s *shopServiceImpl) FindShopForMember(userId int, permission string) (*model.Shop, error) {
+	shop, err := s.shopRepository.FindShopByUserId(userId)
+	if err != commonError.ErrShopNotFound || s.shopMemberRepository == nil {
+		return shop, err
+	}
+
+	members, err := s.shopMemberRepository.GetActiveByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		if dto.NewShopMembership(member.ShopID, member.Shop.UserID, userId, member.Role).Can(permission) {
+			return member.Shop, nil
+		}
+	}
+
+	if len(members) > 0 {
+		return nil, commonError.ErrShopPermissionDenied
+	}
+
+	return nil, commonError.ErrShopNotFound
+}
+
 func (s *shopServiceImpl) FindShopBySlug(slug string) (*model.Shop, error) {
 	shop, err := s.shopRepository.FindShopBySlug(slug)
 	if err != nil {
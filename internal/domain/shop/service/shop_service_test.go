@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
 	commonDto "kedai/backend/be-kedai/internal/common/dto"
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/shop/dto"
@@ -140,6 +141,74 @@ func TestFindShopByUserId(t *testing.T) {
 	}
 }
 
+func TestFindShopForMember(t *testing.T) {
+	var (
+		shop       = &model.Shop{ID: 3, UserID: 1}
+		permission = constant.ShopPermissionManageChats
+	)
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.ShopRepository, *mocks.ShopMemberRepository)
+		expected    *model.Shop
+		err         error
+	}{
+		{
+			description: "should return owned shop",
+			beforeTest: func(sr *mocks.ShopRepository, smr *mocks.ShopMemberRepository) {
+				sr.On("FindShopByUserId", 2).Return(shop, nil)
+			},
+			expected: shop,
+		},
+		{
+			description: "should return shop of the membership whose role grants permission",
+			beforeTest: func(sr *mocks.ShopRepository, smr *mocks.ShopMemberRepository) {
+				sr.On("FindShopByUserId", 2).Return(nil, errs.ErrShopNotFound)
+				smr.On("GetActiveByUserID", 2).Return([]*model.ShopMember{
+					{ShopID: 4, Shop: &model.Shop{ID: 4, UserID: 5}, Role: constant.ShopRoleFinance},
+					{ShopID: 3, Shop: shop, Role: constant.ShopRoleChatAgent},
+				}, nil)
+			},
+			expected: shop,
+		},
+		{
+			description: "should return error when no membership grants permission",
+			beforeTest: func(sr *mocks.ShopRepository, smr *mocks.ShopMemberRepository) {
+				sr.On("FindShopByUserId", 2).Return(nil, errs.ErrShopNotFound)
+				smr.On("GetActiveByUserID", 2).Return([]*model.ShopMember{
+					{ShopID: 3, Shop: shop, Role: constant.ShopRoleOrderOps},
+				}, nil)
+			},
+			err: errs.ErrShopPermissionDenied,
+		},
+		{
+			description: "should return error when user has no shop",
+			beforeTest: func(sr *mocks.ShopRepository, smr *mocks.ShopMemberRepository) {
+				sr.On("FindShopByUserId", 2).Return(nil, errs.ErrShopNotFound)
+				smr.On("GetActiveByUserID", 2).Return([]*model.ShopMember{}, nil)
+			},
+			err: errs.ErrShopNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			mockShopRepo := mocks.NewShopRepository(t)
+			mockShopMemberRepo := mocks.NewShopMemberRepository(t)
+			tc.beforeTest(mockShopRepo, mockShopMemberRepo)
+			service := service.NewShopService(&service.ShopSConfig{
+				ShopRepository:       mockShopRepo,
+				ShopMemberRepository: mockShopMemberRepo,
+			})
+
+			result, err := service.FindShopForMember(2, permission)
+
+			assert.Equal(t, tc.expected, result)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
 func TestFindShopBySlug(t *testing.T) {
 	var (
 		shopSlug   = "shop"
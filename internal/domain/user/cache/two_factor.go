@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type TwoFactorCache interface {
+	StoreChallenge(token string, userId int) error
+	FindChallenge(token string) (int, error)
+	DeleteChallenge(token string) error
+}
+
+type twoFactorCacheImpl struct {
+	rdc *redis.Client
+}
+
+type TwoFactorCConfig struct {
+	RDC *redis.Client
+}
+
+func NewTwoFactorCache(cfg *TwoFactorCConfig) TwoFactorCache {
+	return &twoFactorCacheImpl{
+		rdc: cfg.RDC,
+	}
+}
+
+func (c *twoFactorCacheImpl) StoreChallenge(token string, userId int) error {
+	key := fmt.Sprintf("twoFactorChallenge:%s", token)
+
+	return c.rdc.Set(context.Background(), key, userId, constant.TwoFactorChallengeAge).Err()
+}
+
+func (c *twoFactorCacheImpl) FindChallenge(token string) (int, error) {
+	key := fmt.Sprintf("twoFactorChallenge:%s", token)
+
+	userId, err := c.rdc.Get(context.Background(), key).Int()
+	if err != nil {
+		if err == redis.Nil {
+			err = errs.ErrInvalidTwoFactorChallenge
+		}
+		return 0, err
+	}
+
+	return userId, nil
+}
+
+func (c *twoFactorCacheImpl) DeleteChallenge(token string) error {
+	key := fmt.Sprintf("twoFactorChallenge:%s", token)
+
+	return c.rdc.Del(context.Background(), key).Err()
+}
@@ -12,10 +12,13 @@ import (
 )
 
 type UserCache interface {
-	StoreToken(userId int, accessToken string, refreshToken string) error
+	StoreToken(userId int, sessionKey string, accessToken string, refreshToken string) error
 	DeleteToken(key string) error
-	FindToken(userId int, token string) error
+	FindToken(userId int, token string) (sessionKey string, err error)
+	ConsumeRefreshToken(userId int, refreshToken string) (sessionKey string, err error)
 	DeleteAllByID(userId int) error
+	DeleteSession(userId int, sessionKey string) error
+	DeleteAllExceptSession(userId int, sessionKey string) error
 	DeleteRefreshTokenAndAccessToken(userId int, refreshToken string, accessToken string) error
 	StoreUserPasswordAndVerificationCode(userId int, newPassword string, verificationCode string) error
 	FindUserPasswordAndVerificationCode(userId int) (newPassword string, verificationCode string, err error)
@@ -39,19 +42,21 @@ func NewUserCache(cfg *UserCConfig) UserCache {
 	}
 }
 
-func (r *userCacheImpl) StoreToken(userId int, accessToken string, refreshToken string) error {
+// StoreToken keeps the tokens valid until they expire, tagged with the
+// session they belong to.
+func (r *userCacheImpl) StoreToken(userId int, sessionKey string, accessToken string, refreshToken string) error {
 	refreshKey := fmt.Sprintf("user_%d:%s", userId, refreshToken)
 	refreshTime := jwttoken.ParseTokenAgeFromENV(config.GetEnv("REFRESH_TOKEN_AGE", ""), "refresh")
 
 	accessKey := fmt.Sprintf("user_%d:%s", userId, accessToken)
 	accessTime := jwttoken.ParseTokenAgeFromENV(config.GetEnv("ACCESS_TOKEN_AGE", ""), "access")
 
-	errRefresh := r.rdc.Set(context.Background(), refreshKey, 0, refreshTime).Err()
+	errRefresh := r.rdc.Set(context.Background(), refreshKey, sessionKey, refreshTime).Err()
 	if errRefresh != nil {
 		return errRefresh
 	}
 
-	errAccess := r.rdc.Set(context.Background(), accessKey, 0, accessTime).Err()
+	errAccess := r.rdc.Set(context.Background(), accessKey, sessionKey, accessTime).Err()
 	if errAccess != nil {
 		return errAccess
 	}
@@ -109,15 +114,61 @@ func (r *userCacheImpl) FindUserPasswordAndVerificationCode(userId int) (string,
 	return newPassword, verificationCode, nil
 }
 
-func (r *userCacheImpl) FindToken(userId int, token string) error {
+func (r *userCacheImpl) FindToken(userId int, token string) (string, error) {
 	key := fmt.Sprintf("user_%d:%s", userId, token)
-	err := r.rdc.Get(context.Background(), key).Err()
+	sessionKey, err := r.rdc.Get(context.Background(), key).Result()
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return sessionKey, nil
+}
+
+// consumeRefreshTokenScript deletes the refresh token at KEYS[1] and records
+// it as used at KEYS[2] for ARGV[1] milliseconds in one step, so of two
+// requests presenting the same token exactly one rotates it and the other
+// sees it as reused. It returns the session key and whether the token had
+// already been used, or nil when it is unknown.
+var consumeRefreshTokenScript = redis.NewScript(`
+local sessionKey = redis.call("GET", KEYS[1])
+if sessionKey then
+	redis.call("DEL", KEYS[1])
+	if tonumber(ARGV[1]) > 0 then
+		redis.call("SET", KEYS[2], sessionKey, "PX", ARGV[1])
+	else
+		redis.call("SET", KEYS[2], sessionKey)
+	end
+	return {sessionKey, 0}
+end
+
+local usedSessionKey = redis.call("GET", KEYS[2])
+if usedSessionKey then
+	return {usedSessionKey, 1}
+end
+
+return false
+`)
+
+// ConsumeRefreshToken invalidates refreshToken and returns its session. The
+// token is remembered as used until it expires, so presenting it again
+// returns ErrRefreshTokenReused instead of redis.Nil.
+func (r *userCacheImpl) ConsumeRefreshToken(userId int, refreshToken string) (string, error) {
+	key := fmt.Sprintf("user_%d:%s", userId, refreshToken)
+	usedKey := fmt.Sprintf("usedRefreshToken_%d:%s", userId, refreshToken)
+	refreshTime := jwttoken.ParseTokenAgeFromENV(config.GetEnv("REFRESH_TOKEN_AGE", ""), "refresh")
+
+	result, err := consumeRefreshTokenScript.Run(context.Background(), r.rdc, []string{key, usedKey}, refreshTime.Milliseconds()).Slice()
+	if err != nil {
+		return "", err
+	}
+
+	sessionKey, _ := result[0].(string)
+	if reused, _ := result[1].(int64); reused == 1 {
+		return sessionKey, errs.ErrRefreshTokenReused
+	}
+
+	return sessionKey, nil
 }
 
 func (r *userCacheImpl) DeleteToken(key string) error {
@@ -137,6 +188,39 @@ func (r *userCacheImpl) DeleteAllByID(userId int) error {
 	return nil
 }
 
+// DeleteSession removes every token issued to the session.
+func (r *userCacheImpl) DeleteSession(userId int, sessionKey string) error {
+	return r.deleteTokensWhere(userId, func(key string) bool { return key == sessionKey })
+}
+
+// DeleteAllExceptSession signs the user out of every other device.
+func (r *userCacheImpl) DeleteAllExceptSession(userId int, sessionKey string) error {
+	return r.deleteTokensWhere(userId, func(key string) bool { return key != sessionKey })
+}
+
+func (r *userCacheImpl) deleteTokensWhere(userId int, match func(sessionKey string) bool) error {
+	ctx := context.Background()
+
+	iter := r.rdc.Scan(ctx, 0, fmt.Sprintf("user_%d:*", userId), 0).Iterator()
+	for iter.Next(ctx) {
+		sessionKey, err := r.rdc.Get(ctx, iter.Val()).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if match(sessionKey) {
+			if err := r.rdc.Unlink(ctx, iter.Val()).Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return iter.Err()
+}
+
 func (r *userCacheImpl) DeleteRefreshTokenAndAccessToken(userId int, refreshToken string, accessToken string) error {
 	ctx := context.Background()
 
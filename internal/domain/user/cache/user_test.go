@@ -0,0 +1,71 @@
+package cache_test
+
+import (
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/cache"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUserCache(t *testing.T) cache.UserCache {
+	redisServer := miniredis.RunT(t)
+	rdc := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	t.Cleanup(func() { rdc.Close() })
+
+	return cache.NewUserCache(&cache.UserCConfig{RDC: rdc})
+}
+
+func TestConsumeRefreshToken(t *testing.T) {
+	t.Run("should return the session and report the token as reused afterwards", func(t *testing.T) {
+		userCache := newUserCache(t)
+		assert.NoError(t, userCache.StoreToken(1, "session", "access", "refresh"))
+
+		sessionKey, err := userCache.ConsumeRefreshToken(1, "refresh")
+		assert.NoError(t, err)
+		assert.Equal(t, "session", sessionKey)
+
+		sessionKey, err = userCache.ConsumeRefreshToken(1, "refresh")
+		assert.Equal(t, errs.ErrRefreshTokenReused, err)
+		assert.Equal(t, "session", sessionKey)
+	})
+
+	t.Run("should return redis.Nil when the token is unknown", func(t *testing.T) {
+		userCache := newUserCache(t)
+
+		_, err := userCache.ConsumeRefreshToken(1, "refresh")
+
+		assert.Equal(t, redis.Nil, err)
+	})
+
+	t.Run("should let only one of concurrent requests rotate the token", func(t *testing.T) {
+		userCache := newUserCache(t)
+		assert.NoError(t, userCache.StoreToken(1, "session", "access", "refresh"))
+
+		var wg sync.WaitGroup
+		errors := make([]error, 10)
+		for i := range errors {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errors[i] = userCache.ConsumeRefreshToken(1, "refresh")
+			}(i)
+		}
+		wg.Wait()
+
+		var rotated, reused int
+		for _, err := range errors {
+			switch err {
+			case nil:
+				rotated++
+			case errs.ErrRefreshTokenReused:
+				reused++
+			}
+		}
+		assert.Equal(t, 1, rotated)
+		assert.Equal(t, 9, reused)
+	})
+}
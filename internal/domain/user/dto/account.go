@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"time"
+)
+
+type DataExportResponse struct {
+	ID          int        `json:"id"`
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
+
+func (d *DataExportResponse) FromUserDataExport(export *model.UserDataExport) {
+	d.ID = export.ID
+	d.Status = export.Status
+	d.RequestedAt = export.CreatedAt
+	d.CompletedAt = export.CompletedAt
+	d.ExpiresAt = export.ExpiresAt
+}
+
+type DeleteAccountRequest struct {
+	UserId    int    `json:"-"`
+	IPAddress string `json:"-"`
+	Password  string `json:"password" binding:"required"`
+}
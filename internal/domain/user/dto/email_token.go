@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"strings"
+	"time"
+)
+
+// EmailToken is carried by the links emailed to verify an address, confirm
+// an email change, revert one and link a provider account. It is signed with
+// the token signing key, and only applies while the account still has
+// CurrentEmail so a link stops working once the address moved on.
+type EmailToken struct {
+	Purpose      string    `json:"p"`
+	UserID       int       `json:"u"`
+	Email        string    `json:"e"`
+	CurrentEmail string    `json:"c"`
+	ExpiresAt    time.Time `json:"x"`
+	// Provider and Subject name the provider account a link token links.
+	Provider string `json:"pv,omitempty"`
+	Subject  string `json:"s,omitempty"`
+}
+
+func (t *EmailToken) Sign() string {
+	payload, _ := json.Marshal(t)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + hash.SignToken(string(payload))
+}
+
+func ParseEmailToken(token string, purpose string) (*EmailToken, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errs.ErrInvalidEmailToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errs.ErrInvalidEmailToken
+	}
+
+	if !hash.VerifyToken(string(payload), signature) {
+		return nil, errs.ErrInvalidEmailToken
+	}
+
+	var t EmailToken
+	if err := json.Unmarshal(payload, &t); err != nil || t.Purpose != purpose {
+		return nil, errs.ErrInvalidEmailToken
+	}
+
+	if time.Now().After(t.ExpiresAt) {
+		return nil, errs.ErrEmailTokenExpired
+	}
+
+	return &t, nil
+}
+
+type EmailTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -1,6 +1,9 @@
 package dto
 
+// Token is returned on sign in. When the user has 2FA enabled only
+// TwoFactorToken is set, to be exchanged with a code for the other two.
 type Token struct {
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
+	AccessToken    string `json:"accessToken,omitempty"`
+	RefreshToken   string `json:"refreshToken,omitempty"`
+	TwoFactorToken string `json:"twoFactorToken,omitempty"`
 }
@@ -0,0 +1,30 @@
+package dto
+
+type TwoFactorStatus struct {
+	IsEnabled             bool `json:"isEnabled"`
+	RecoveryCodeRemaining int  `json:"recoveryCodeRemaining"`
+}
+
+// TwoFactorEnrolment is shown once while setting up an authenticator app;
+// ProvisioningURI is what the client renders as a QR code.
+type TwoFactorEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type TwoFactorRecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// TwoFactorCodeRequest takes either a code from the authenticator app or
+// one of the recovery codes.
+type TwoFactorCodeRequest struct {
+	Code      string `json:"code" binding:"required"`
+	IPAddress string `json:"-"`
+}
+
+type TwoFactorLoginRequest struct {
+	TwoFactorToken string        `json:"twoFactorToken" binding:"required"`
+	Code           string        `json:"code" binding:"required"`
+	Device         SessionDevice `json:"-"`
+}
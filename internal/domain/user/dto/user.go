@@ -13,8 +13,9 @@ type UserRegistrationResponse struct {
 }
 
 type UserLogin struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email    string        `json:"email" binding:"required,email"`
+	Password string        `json:"password" binding:"required"`
+	Device   SessionDevice `json:"-"`
 }
 
 type UpdateEmailRequest struct {
@@ -33,34 +34,39 @@ type UpdateEmailResponse struct {
 	Email string `json:"email"`
 }
 
-type UserLoginWithGoogleRequest struct {
-	Credential string `json:"credential" binding:"required"`
-}
-
-type UserRegistrationWithGoogleRequest struct {
-	Credential string `json:"credential" binding:"required"`
-	Username   string `json:"username" binding:"required,min=5,max=30"`
-	Password   string `json:"password" binding:"required,min=8,max=16"`
+// UserLoginWithProviderRequest carries a credential issued by Provider, such
+// as an ID token.
+type UserLoginWithProviderRequest struct {
+	Provider   string        `json:"-"`
+	Credential string        `json:"credential" binding:"required"`
+	Device     SessionDevice `json:"-"`
 }
 
-type UserLoginWithGoogle struct {
-	Email string
+type UserRegistrationWithProviderRequest struct {
+	Provider   string        `json:"-"`
+	Credential string        `json:"credential" binding:"required"`
+	Username   string        `json:"username" binding:"required,min=5,max=30"`
+	Password   string        `json:"password" binding:"required,min=8,max=16"`
+	Device     SessionDevice `json:"-"`
 }
 
 type UserLogoutRequest struct {
 	RefreshToken string `binding:"required"`
 	AccessToken  string
+	SessionKey   string
 	UserId       int
 }
 
 type RequestPasswordChangeRequest struct {
 	UserId          int
+	IPAddress       string `json:"-"`
 	CurrentPassword string `json:"currentPassword" binding:"required,min=8,max=16"`
 	NewPassword     string `json:"newPassword" binding:"required,min=8,max=16"`
 }
 
 type CompletePasswordChangeRequest struct {
 	UserId           int
+	IPAddress        string `json:"-"`
 	VerificationCode string `json:"verificationCode" binding:"required,alphanum,len=6"`
 }
 
@@ -69,8 +75,10 @@ type RequestPasswordResetRequest struct {
 }
 
 type CompletePasswordResetRequest struct {
+	Email       string `json:"email" binding:"required,email"`
 	Token       string `json:"token" binding:"required,alphanum,len=64"`
 	NewPassword string `json:"newPassword" binding:"required,min=8,max=16"`
+	IPAddress   string `json:"-"`
 }
 
 func (d *UserRegistrationRequest) ToUser() *model.User {
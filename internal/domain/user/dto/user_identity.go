@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"time"
+)
+
+type IdentityProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+type LinkIdentityRequest struct {
+	Provider   string `json:"-"`
+	Credential string `json:"credential" binding:"required"`
+}
+
+type UserIdentityResponse struct {
+	Provider string    `json:"provider"`
+	Email    string    `json:"email"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+func (d *UserIdentityResponse) FromUserIdentity(identity *model.UserIdentity) {
+	d.Provider = identity.Provider
+	d.Email = identity.Email
+	d.LinkedAt = identity.CreatedAt
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"time"
+)
+
+// SessionDevice describes the device a sign in comes from.
+type SessionDevice struct {
+	DeviceID  string
+	UserAgent string
+	IPAddress string
+}
+
+type UserSessionResponse struct {
+	ID         int       `json:"id"`
+	DeviceID   string    `json:"deviceId"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	IsCurrent  bool      `json:"isCurrent"`
+}
+
+func (r *UserSessionResponse) FromUserSession(session *model.UserSession, currentKey string) {
+	r.ID = session.ID
+	r.DeviceID = session.DeviceID
+	r.UserAgent = session.UserAgent
+	r.IPAddress = session.IPAddress
+	r.CreatedAt = session.CreatedAt
+	r.LastSeenAt = session.LastSeenAt
+	r.IsCurrent = session.SessionKey == currentKey
+}
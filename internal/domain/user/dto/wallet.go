@@ -14,17 +14,20 @@ type TopUpRequest struct {
 }
 
 type ChangePinRequest struct {
+	IPAddress  string `json:"-"`
 	CurrentPin string `json:"currentPin" binding:"required,numeric,len=6"`
 	NewPin     string `json:"newPin" binding:"required,numeric,len=6"`
 }
 
 type CompleteChangePinRequest struct {
+	IPAddress        string `json:"-"`
 	VerificationCode string `json:"verificationCode" binding:"required,alphanum,len=6"`
 }
 
 type CompleteResetPinRequest struct {
-	Token  string `json:"token" binding:"required,alphanum,len=6"`
-	NewPin string `json:"newPin" binding:"required,numeric,len=6"`
+	Token     string `json:"token" binding:"required,alphanum,len=6"`
+	NewPin    string `json:"newPin" binding:"required,numeric,len=6"`
+	IPAddress string `json:"-"`
 }
 
 type WalletHistoryRequest struct {
@@ -52,7 +55,9 @@ func (req *WalletHistoryRequest) Offset() int {
 }
 
 type StepUpRequest struct {
-	Pin string `form:"pin" binding:"required,numeric,len=6"`
+	Pin          string `form:"pin" binding:"required,numeric,len=6"`
+	RefreshToken string `json:"refreshToken" form:"refreshToken" binding:"required"`
+	SessionKey   string `json:"-" form:"-"`
 }
 
 type GetWalletResponse struct {
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) RequestDataExport(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	res, err := h.accountService.RequestDataExport(userId)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, code.OK, "data export requested", res)
+}
+
+func (h *Handler) GetDataExport(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	res, err := h.accountService.GetDataExport(userId)
+	if err != nil {
+		if errors.Is(err, errs.ErrDataExportNotFound) {
+			response.Error(c, http.StatusNotFound, code.DATA_EXPORT_NOT_FOUND, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", res)
+}
+
+func (h *Handler) DownloadDataExport(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	archive, err := h.accountService.DownloadDataExport(userId)
+	if err != nil {
+		if errors.Is(err, errs.ErrDataExportNotFound) {
+			response.Error(c, http.StatusNotFound, code.DATA_EXPORT_NOT_FOUND, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrDataExportNotReady) {
+			response.Error(c, http.StatusConflict, code.DATA_EXPORT_NOT_READY, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="kedai-data-export.zip"`)
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	var request dto.DeleteAccountRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+	request.UserId = c.GetInt("userId")
+	request.IPAddress = c.ClientIP()
+
+	err = h.accountService.DeleteAccount(&request)
+	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidCredential) {
+			response.Error(c, http.StatusBadRequest, code.WRONG_PASSWORD, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAccountHasOpenOrders) {
+			response.Error(c, http.StatusConflict, code.ACCOUNT_HAS_OPEN_ORDERS, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAccountHasOpenRefunds) {
+			response.Error(c, http.StatusConflict, code.ACCOUNT_HAS_OPEN_REFUNDS, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAccountHasWalletBalance) {
+			response.Error(c, http.StatusConflict, code.ACCOUNT_HAS_WALLET_BALANCE, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrAccountHasShop) {
+			response.Error(c, http.StatusConflict, code.ACCOUNT_HAS_SHOP, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.DELETED, "deleted", nil)
+}
+
+func (h *Handler) DataExportCronJob(c *gin.Context) {
+	_ = h.accountService.ProcessDataExports()
+	log.Println("DATA EXPORT CRON JOB")
+}
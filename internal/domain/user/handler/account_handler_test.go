@@ -0,0 +1,459 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/handler"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestDataExport(t *testing.T) {
+	type input struct {
+		beforeTest func(*mocks.UserAccountService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	export := &dto.DataExportResponse{ID: 1, Status: "pending"}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 500 when failed to request data export",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("RequestDataExport", 1).Return(nil, errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return the queued export with status code 202",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("RequestDataExport", 1).Return(export, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusAccepted,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "data export requested",
+					Data:    export,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			accountService := mocks.NewUserAccountService(t)
+			tc.input.beforeTest(accountService)
+			h := handler.New(&handler.HandlerConfig{
+				AccountService: accountService,
+			})
+			c.Request, _ = http.NewRequest("POST", "/v1/users/data-export", nil)
+
+			h.RequestDataExport(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestGetDataExport(t *testing.T) {
+	type input struct {
+		beforeTest func(*mocks.UserAccountService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	export := &dto.DataExportResponse{ID: 1, Status: "ready"}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when there is no data export",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("GetDataExport", 1).Return(nil, errs.ErrDataExportNotFound)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.DATA_EXPORT_NOT_FOUND,
+					Message: errs.ErrDataExportNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to get data export",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("GetDataExport", 1).Return(nil, errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return data export with status code 200",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("GetDataExport", 1).Return(export, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+					Data:    export,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			accountService := mocks.NewUserAccountService(t)
+			tc.input.beforeTest(accountService)
+			h := handler.New(&handler.HandlerConfig{
+				AccountService: accountService,
+			})
+			c.Request, _ = http.NewRequest("GET", "/v1/users/data-export", nil)
+
+			h.GetDataExport(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestDownloadDataExport(t *testing.T) {
+	type input struct {
+		beforeTest func(*mocks.UserAccountService)
+	}
+	type expected struct {
+		statusCode int
+		response   *response.Response
+	}
+
+	archive := []byte("archive")
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when there is no data export",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DownloadDataExport", 1).Return(nil, errs.ErrDataExportNotFound)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: &response.Response{
+					Code:    code.DATA_EXPORT_NOT_FOUND,
+					Message: errs.ErrDataExportNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 409 when data export is not ready",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DownloadDataExport", 1).Return(nil, errs.ErrDataExportNotReady)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: &response.Response{
+					Code:    code.DATA_EXPORT_NOT_READY,
+					Message: errs.ErrDataExportNotReady.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to download data export",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DownloadDataExport", 1).Return(nil, errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: &response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return the archive with status code 200",
+			input: input{
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DownloadDataExport", 1).Return(archive, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			accountService := mocks.NewUserAccountService(t)
+			tc.input.beforeTest(accountService)
+			h := handler.New(&handler.HandlerConfig{
+				AccountService: accountService,
+			})
+			c.Request, _ = http.NewRequest("GET", "/v1/users/data-export/download", nil)
+
+			h.DownloadDataExport(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			if tc.expected.response != nil {
+				expectedRes, _ := json.Marshal(tc.expected.response)
+				assert.Equal(t, string(expectedRes), rec.Body.String())
+				return
+			}
+			assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="kedai-data-export.zip"`, rec.Header().Get("Content-Disposition"))
+			assert.Equal(t, archive, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	type input struct {
+		request    *dto.DeleteAccountRequest
+		beforeTest func(*mocks.UserAccountService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	request := &dto.DeleteAccountRequest{Password: "Password123"}
+	serviceRequest := &dto.DeleteAccountRequest{UserId: 1, IPAddress: "192.0.2.1", Password: "Password123"}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 400 when password is missing",
+			input: input{
+				request:    &dto.DeleteAccountRequest{},
+				beforeTest: func(uas *mocks.UserAccountService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Password is required",
+				},
+			},
+		},
+		{
+			description: "should return error with status code 429 when there were too many attempts",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(errs.ErrTooManyAttempts)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusTooManyRequests,
+				response: response.Response{
+					Code:    code.TOO_MANY_ATTEMPTS,
+					Message: errs.ErrTooManyAttempts.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 400 when password is wrong",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(errs.ErrInvalidCredential)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.WRONG_PASSWORD,
+					Message: errs.ErrInvalidCredential.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 409 when user has open orders",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(errs.ErrAccountHasOpenOrders)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.ACCOUNT_HAS_OPEN_ORDERS,
+					Message: errs.ErrAccountHasOpenOrders.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 409 when user has open refunds",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(errs.ErrAccountHasOpenRefunds)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.ACCOUNT_HAS_OPEN_REFUNDS,
+					Message: errs.ErrAccountHasOpenRefunds.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 409 when user has wallet balance",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(errs.ErrAccountHasWalletBalance)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.ACCOUNT_HAS_WALLET_BALANCE,
+					Message: errs.ErrAccountHasWalletBalance.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 409 when user owns a shop",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(errs.ErrAccountHasShop)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.ACCOUNT_HAS_SHOP,
+					Message: errs.ErrAccountHasShop.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to delete account",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return status code 200 when account is deleted",
+			input: input{
+				request: request,
+				beforeTest: func(uas *mocks.UserAccountService) {
+					uas.On("DeleteAccount", serviceRequest).Return(nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.DELETED,
+					Message: "deleted",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			accountService := mocks.NewUserAccountService(t)
+			tc.input.beforeTest(accountService)
+			h := handler.New(&handler.HandlerConfig{
+				AccountService: accountService,
+			})
+			c.Request, _ = http.NewRequest("DELETE", "/v1/users", test.MakeRequestBody(tc.input.request))
+			c.Request.RemoteAddr = "192.0.2.1:1234"
+
+			h.DeleteAccount(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendEmailVerification(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	err := h.userService.SendEmailVerification(userId)
+	if err != nil {
+		if errors.Is(err, errs.ErrEmailAlreadyVerified) {
+			response.Error(c, http.StatusConflict, code.EMAIL_ALREADY_VERIFIED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", nil)
+}
+
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var request dto.EmailTokenRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.userService.VerifyEmail(request.Token)
+	if err != nil {
+		emailTokenError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", nil)
+}
+
+func (h *Handler) ConfirmUserEmail(c *gin.Context) {
+	var request dto.EmailTokenRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.userService.ConfirmEmailChange(request.Token)
+	if err != nil {
+		emailTokenError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "updated", res)
+}
+
+func (h *Handler) RevertUserEmail(c *gin.Context) {
+	var request dto.EmailTokenRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.userService.RevertEmailChange(request.Token)
+	if err != nil {
+		emailTokenError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.UPDATED, "updated", nil)
+}
+
+func emailTokenError(c *gin.Context, err error) {
+	if errors.Is(err, errs.ErrInvalidEmailToken) {
+		response.Error(c, http.StatusBadRequest, code.INVALID_EMAIL_TOKEN, err.Error())
+		return
+	}
+	if errors.Is(err, errs.ErrEmailTokenExpired) {
+		response.Error(c, http.StatusBadRequest, code.EMAIL_TOKEN_EXPIRED, err.Error())
+		return
+	}
+	if errors.Is(err, errs.ErrEmailUsed) {
+		response.Error(c, http.StatusConflict, code.EMAIL_ALREADY_REGISTERED, err.Error())
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+}
+
+// RequireVerifiedEmail guards actions that are only open to users who have
+// confirmed their email address, such as checking out and opening a shop.
+func (h *Handler) RequireVerifiedEmail(c *gin.Context) {
+	user, err := h.userService.GetByID(c.GetInt("userId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Response{
+			Code:    code.INTERNAL_SERVER_ERROR,
+			Message: errs.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	if user.EmailVerifiedAt == nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, response.Response{
+			Code:    code.EMAIL_NOT_VERIFIED,
+			Message: errs.ErrEmailNotVerified.Error(),
+		})
+	}
+}
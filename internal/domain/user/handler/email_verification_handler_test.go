@@ -0,0 +1,327 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/handler"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendEmailVerification(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.UserService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 409 when email is already verified",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("SendEmailVerification", 1).Return(errs.ErrEmailAlreadyVerified)
+			},
+			statusCode: http.StatusConflict,
+			response: response.Response{
+				Code:    code.EMAIL_ALREADY_VERIFIED,
+				Message: errs.ErrEmailAlreadyVerified.Error(),
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to send verification link",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("SendEmailVerification", 1).Return(errors.New("failed to send"))
+			},
+			statusCode: http.StatusInternalServerError,
+			response: response.Response{
+				Code:    code.INTERNAL_SERVER_ERROR,
+				Message: errs.ErrInternalServerError.Error(),
+			},
+		},
+		{
+			description: "should return status code 200 when verification link is sent",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("SendEmailVerification", 1).Return(nil)
+			},
+			statusCode: http.StatusOK,
+			response: response.Response{
+				Code:    code.OK,
+				Message: "ok",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			userService := mocks.NewUserService(t)
+			tc.beforeTest(userService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/users/emails/verification", nil)
+			h := handler.New(&handler.HandlerConfig{UserService: userService})
+
+			h.SendEmailVerification(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	tests := []struct {
+		description string
+		request     *dto.EmailTokenRequest
+		beforeTest  func(*mocks.UserService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 400 when token is missing",
+			request:     &dto.EmailTokenRequest{},
+			beforeTest:  func(us *mocks.UserService) {},
+			statusCode:  http.StatusBadRequest,
+			response: response.Response{
+				Code:    code.BAD_REQUEST,
+				Message: "Token is required",
+			},
+		},
+		{
+			description: "should return error with status code 400 when token is invalid",
+			request:     &dto.EmailTokenRequest{Token: "token"},
+			beforeTest: func(us *mocks.UserService) {
+				us.On("VerifyEmail", "token").Return(errs.ErrInvalidEmailToken)
+			},
+			statusCode: http.StatusBadRequest,
+			response: response.Response{
+				Code:    code.INVALID_EMAIL_TOKEN,
+				Message: errs.ErrInvalidEmailToken.Error(),
+			},
+		},
+		{
+			description: "should return error with status code 400 when token has expired",
+			request:     &dto.EmailTokenRequest{Token: "token"},
+			beforeTest: func(us *mocks.UserService) {
+				us.On("VerifyEmail", "token").Return(errs.ErrEmailTokenExpired)
+			},
+			statusCode: http.StatusBadRequest,
+			response: response.Response{
+				Code:    code.EMAIL_TOKEN_EXPIRED,
+				Message: errs.ErrEmailTokenExpired.Error(),
+			},
+		},
+		{
+			description: "should return status code 200 when email is verified",
+			request:     &dto.EmailTokenRequest{Token: "token"},
+			beforeTest: func(us *mocks.UserService) {
+				us.On("VerifyEmail", "token").Return(nil)
+			},
+			statusCode: http.StatusOK,
+			response: response.Response{
+				Code:    code.OK,
+				Message: "ok",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			userService := mocks.NewUserService(t)
+			tc.beforeTest(userService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/users/emails/verify", test.MakeRequestBody(tc.request))
+			h := handler.New(&handler.HandlerConfig{UserService: userService})
+
+			h.VerifyEmail(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestConfirmUserEmail(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.UserService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 409 when new email was taken",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("ConfirmEmailChange", "token").Return(nil, errs.ErrEmailUsed)
+			},
+			statusCode: http.StatusConflict,
+			response: response.Response{
+				Code:    code.EMAIL_ALREADY_REGISTERED,
+				Message: errs.ErrEmailUsed.Error(),
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to change email",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("ConfirmEmailChange", "token").Return(nil, errors.New("failed to change email"))
+			},
+			statusCode: http.StatusInternalServerError,
+			response: response.Response{
+				Code:    code.INTERNAL_SERVER_ERROR,
+				Message: errs.ErrInternalServerError.Error(),
+			},
+		},
+		{
+			description: "should return new email with status code 200 when email is changed",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("ConfirmEmailChange", "token").Return(&dto.UpdateEmailResponse{Email: "new.email@mail.com"}, nil)
+			},
+			statusCode: http.StatusOK,
+			response: response.Response{
+				Code:    code.UPDATED,
+				Message: "updated",
+				Data:    &dto.UpdateEmailResponse{Email: "new.email@mail.com"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			userService := mocks.NewUserService(t)
+			tc.beforeTest(userService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/users/emails/confirm", test.MakeRequestBody(&dto.EmailTokenRequest{Token: "token"}))
+			h := handler.New(&handler.HandlerConfig{UserService: userService})
+
+			h.ConfirmUserEmail(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestRevertUserEmail(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.UserService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 400 when token has expired",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("RevertEmailChange", "token").Return(errs.ErrEmailTokenExpired)
+			},
+			statusCode: http.StatusBadRequest,
+			response: response.Response{
+				Code:    code.EMAIL_TOKEN_EXPIRED,
+				Message: errs.ErrEmailTokenExpired.Error(),
+			},
+		},
+		{
+			description: "should return status code 200 when email is reverted",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("RevertEmailChange", "token").Return(nil)
+			},
+			statusCode: http.StatusOK,
+			response: response.Response{
+				Code:    code.UPDATED,
+				Message: "updated",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			userService := mocks.NewUserService(t)
+			tc.beforeTest(userService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/users/emails/revert", test.MakeRequestBody(&dto.EmailTokenRequest{Token: "token"}))
+			h := handler.New(&handler.HandlerConfig{UserService: userService})
+
+			h.RevertUserEmail(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.UserService)
+		statusCode  int
+		response    *response.Response
+	}{
+		{
+			description: "should abort with status code 500 when failed to get user",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("GetByID", 1).Return(nil, errors.New("failed to get user"))
+			},
+			statusCode: http.StatusInternalServerError,
+			response: &response.Response{
+				Code:    code.INTERNAL_SERVER_ERROR,
+				Message: errs.ErrInternalServerError.Error(),
+			},
+		},
+		{
+			description: "should abort with status code 403 when email is not verified",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("GetByID", 1).Return(&model.User{ID: 1}, nil)
+			},
+			statusCode: http.StatusForbidden,
+			response: &response.Response{
+				Code:    code.EMAIL_NOT_VERIFIED,
+				Message: errs.ErrEmailNotVerified.Error(),
+			},
+		},
+		{
+			description: "should continue when email is verified",
+			beforeTest: func(us *mocks.UserService) {
+				us.On("GetByID", 1).Return(&model.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			userService := mocks.NewUserService(t)
+			tc.beforeTest(userService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			h := handler.New(&handler.HandlerConfig{UserService: userService})
+
+			h.RequireVerifiedEmail(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, tc.response != nil, c.IsAborted())
+			if tc.response != nil {
+				expectedRes, _ := json.Marshal(tc.response)
+				assert.Equal(t, string(expectedRes), rec.Body.String())
+			}
+		})
+	}
+}
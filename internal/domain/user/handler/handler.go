@@ -14,6 +14,10 @@ type Handler struct {
 	sealabsPayService    service.SealabsPayService
 	addressService       locationService.AddressService
 	userProfileService   service.UserProfileService
+	twoFactorService     service.TwoFactorService
+	sessionService       service.UserSessionService
+	identityService      service.UserIdentityService
+	accountService       service.UserAccountService
 }
 
 type HandlerConfig struct {
@@ -25,6 +29,10 @@ type HandlerConfig struct {
 	SealabsPayService    service.SealabsPayService
 	AddressService       locationService.AddressService
 	UserProfileService   service.UserProfileService
+	TwoFactorService     service.TwoFactorService
+	SessionService       service.UserSessionService
+	IdentityService      service.UserIdentityService
+	AccountService       service.UserAccountService
 }
 
 func New(cfg *HandlerConfig) *Handler {
@@ -37,5 +45,9 @@ func New(cfg *HandlerConfig) *Handler {
 		sealabsPayService:    cfg.SealabsPayService,
 		addressService:       cfg.AddressService,
 		userProfileService:   cfg.UserProfileService,
+		twoFactorService:     cfg.TwoFactorService,
+		sessionService:       cfg.SessionService,
+		identityService:      cfg.IdentityService,
+		accountService:       cfg.AccountService,
 	}
 }
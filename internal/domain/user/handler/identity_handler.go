@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetIdentityProviders(c *gin.Context) {
+	response.Success(c, http.StatusOK, code.OK, "ok", h.identityService.GetProviders())
+}
+
+func (h *Handler) UserRegistrationWithProvider(c *gin.Context) {
+	var newUser dto.UserRegistrationWithProviderRequest
+	errBinding := c.ShouldBindJSON(&newUser)
+	if errBinding != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, errBinding)
+		return
+	}
+
+	newUser.Provider = identityProvider(c)
+	newUser.Device = sessionDevice(c)
+
+	user, err := h.identityService.SignUp(&newUser)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserAlreadyExist) || errors.Is(err, errs.ErrEmailUsed) {
+			response.Error(c, http.StatusConflict, code.EMAIL_ALREADY_REGISTERED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrIdentityLinkPending) {
+			response.Error(c, http.StatusConflict, code.IDENTITY_LINK_PENDING, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrUsernameUsed) {
+			response.Error(c, http.StatusConflict, code.USERNAME_ALREADY_REGISTERED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidUsernamePattern) {
+			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_USERNAME_PATTERN, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidPasswordPattern) {
+			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_PASSWORD_PATTERN, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrContainEmail) {
+			response.Error(c, http.StatusUnprocessableEntity, code.PASSWORD_CONTAIN_EMAIL, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrIdentityEmailMissing) {
+			response.Error(c, http.StatusUnprocessableEntity, code.IDENTITY_EMAIL_MISSING, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrUnknownIdentityProvider) {
+			response.Error(c, http.StatusNotFound, code.UNKNOWN_IDENTITY_PROVIDER, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrUnauthorized) {
+			response.Error(c, http.StatusUnauthorized, code.UNAUTHORIZED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "Sign up with "+newUser.Provider+" successful", user)
+}
+
+func (h *Handler) UserLoginWithProvider(c *gin.Context) {
+	var newLogin dto.UserLoginWithProviderRequest
+	errBinding := c.ShouldBindJSON(&newLogin)
+	if errBinding != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, errBinding)
+		return
+	}
+
+	newLogin.Provider = identityProvider(c)
+	newLogin.Device = sessionDevice(c)
+
+	token, err := h.identityService.SignIn(&newLogin)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCredential) {
+			response.Error(c, http.StatusNotFound, code.USER_NOT_REGISTERED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrIdentityLinkPending) {
+			response.Error(c, http.StatusConflict, code.IDENTITY_LINK_PENDING, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrUnknownIdentityProvider) {
+			response.Error(c, http.StatusNotFound, code.UNKNOWN_IDENTITY_PROVIDER, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrUnauthorized) {
+			response.Error(c, http.StatusUnauthorized, code.UNAUTHORIZED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	if token.TwoFactorToken != "" {
+		response.Success(c, http.StatusOK, code.TWO_FACTOR_REQUIRED, "two-factor authentication required", token)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "Sign in with "+newLogin.Provider+" successful", token)
+}
+
+func (h *Handler) ConfirmIdentityLink(c *gin.Context) {
+	var request dto.EmailTokenRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.identityService.ConfirmLink(request.Token)
+	if err != nil {
+		if errors.Is(err, errs.ErrIdentityAlreadyLinked) {
+			response.Error(c, http.StatusConflict, code.IDENTITY_ALREADY_LINKED, err.Error())
+			return
+		}
+
+		emailTokenError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", nil)
+}
+
+func (h *Handler) GetUserIdentities(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	identities, err := h.identityService.GetIdentities(userId)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", identities)
+}
+
+func (h *Handler) LinkUserIdentity(c *gin.Context) {
+	var request dto.LinkIdentityRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userId := c.GetInt("userId")
+	request.Provider = identityProvider(c)
+
+	res, err := h.identityService.Link(userId, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrIdentityAlreadyLinked) {
+			response.Error(c, http.StatusConflict, code.IDENTITY_ALREADY_LINKED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrUnknownIdentityProvider) {
+			response.Error(c, http.StatusNotFound, code.UNKNOWN_IDENTITY_PROVIDER, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrUnauthorized) {
+			response.Error(c, http.StatusUnauthorized, code.UNAUTHORIZED, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "created", res)
+}
+
+func (h *Handler) UnlinkUserIdentity(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	err := h.identityService.Unlink(userId, identityProvider(c))
+	if err != nil {
+		if errors.Is(err, errs.ErrIdentityNotFound) {
+			response.Error(c, http.StatusNotFound, code.IDENTITY_NOT_FOUND, err.Error())
+			return
+		}
+
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.DELETED, "deleted", nil)
+}
+
+// identityProvider names the provider the request is for. The Google-only
+// routes kept for older clients have no provider param.
+func identityProvider(c *gin.Context) string {
+	if provider := c.Param("provider"); provider != "" {
+		return provider
+	}
+
+	return constant.IdentityProviderGoogle
+}
@@ -0,0 +1,308 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/handler"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIdentityProviders(t *testing.T) {
+	t.Run("should return configured providers with status code 200", func(t *testing.T) {
+		providers := &dto.IdentityProvidersResponse{Providers: []string{"apple", "google"}}
+		expectedRes, _ := json.Marshal(response.Response{
+			Code:    code.OK,
+			Message: "ok",
+			Data:    providers,
+		})
+		identityService := mocks.NewUserIdentityService(t)
+		identityService.On("GetProviders").Return(providers)
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		h := handler.New(&handler.HandlerConfig{IdentityService: identityService})
+
+		h.GetIdentityProviders(c)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, string(expectedRes), rec.Body.String())
+	})
+}
+
+func TestUserLoginWithNamedProvider(t *testing.T) {
+	t.Run("should sign in with the provider named in the path", func(t *testing.T) {
+		expectedRes, _ := json.Marshal(response.Response{
+			Code:    code.OK,
+			Message: "Sign in with apple successful",
+			Data:    &dto.Token{},
+		})
+		identityService := mocks.NewUserIdentityService(t)
+		identityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+			Provider:   "apple",
+			Credential: "token",
+		}).Return(&dto.Token{}, nil)
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Params = gin.Params{{Key: "provider", Value: "apple"}}
+		c.Request, _ = http.NewRequest(http.MethodPost, "/v1/users/identity-providers/apple/login", test.MakeRequestBody(&dto.UserLoginWithProviderRequest{Credential: "token"}))
+		h := handler.New(&handler.HandlerConfig{IdentityService: identityService})
+
+		h.UserLoginWithProvider(c)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, string(expectedRes), rec.Body.String())
+	})
+}
+
+func TestConfirmIdentityLink(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.UserIdentityService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 400 when token is invalid",
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("ConfirmLink", "token").Return(errs.ErrInvalidEmailToken)
+			},
+			statusCode: http.StatusBadRequest,
+			response: response.Response{
+				Code:    code.INVALID_EMAIL_TOKEN,
+				Message: errs.ErrInvalidEmailToken.Error(),
+			},
+		},
+		{
+			description: "should return error with status code 409 when provider account is linked to another user",
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("ConfirmLink", "token").Return(errs.ErrIdentityAlreadyLinked)
+			},
+			statusCode: http.StatusConflict,
+			response: response.Response{
+				Code:    code.IDENTITY_ALREADY_LINKED,
+				Message: errs.ErrIdentityAlreadyLinked.Error(),
+			},
+		},
+		{
+			description: "should return status code 200 when provider account is linked",
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("ConfirmLink", "token").Return(nil)
+			},
+			statusCode: http.StatusOK,
+			response: response.Response{
+				Code:    code.OK,
+				Message: "ok",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			identityService := mocks.NewUserIdentityService(t)
+			tc.beforeTest(identityService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/users/identity-links/confirm", test.MakeRequestBody(&dto.EmailTokenRequest{Token: "token"}))
+			h := handler.New(&handler.HandlerConfig{IdentityService: identityService})
+
+			h.ConfirmIdentityLink(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestGetUserIdentities(t *testing.T) {
+	identities := []*dto.UserIdentityResponse{{Provider: "google", Email: "user@mail.com", LinkedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}}
+
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.UserIdentityService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 500 when failed to get linked accounts",
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("GetIdentities", 1).Return(nil, errors.New("failed to get identities"))
+			},
+			statusCode: http.StatusInternalServerError,
+			response: response.Response{
+				Code:    code.INTERNAL_SERVER_ERROR,
+				Message: errs.ErrInternalServerError.Error(),
+			},
+		},
+		{
+			description: "should return linked accounts with status code 200",
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("GetIdentities", 1).Return(identities, nil)
+			},
+			statusCode: http.StatusOK,
+			response: response.Response{
+				Code:    code.OK,
+				Message: "ok",
+				Data:    identities,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			identityService := mocks.NewUserIdentityService(t)
+			tc.beforeTest(identityService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			h := handler.New(&handler.HandlerConfig{IdentityService: identityService})
+
+			h.GetUserIdentities(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestLinkUserIdentity(t *testing.T) {
+	request := &dto.LinkIdentityRequest{Provider: "apple", Credential: "token"}
+	linked := &dto.UserIdentityResponse{Provider: "apple", Email: "user@mail.com", LinkedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		description string
+		body        *dto.LinkIdentityRequest
+		beforeTest  func(*mocks.UserIdentityService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 400 when credential is missing",
+			body:        &dto.LinkIdentityRequest{},
+			beforeTest:  func(is *mocks.UserIdentityService) {},
+			statusCode:  http.StatusBadRequest,
+			response: response.Response{
+				Code:    code.BAD_REQUEST,
+				Message: "Credential is required",
+			},
+		},
+		{
+			description: "should return error with status code 401 when credential is rejected",
+			body:        request,
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("Link", 1, request).Return(nil, errs.ErrUnauthorized)
+			},
+			statusCode: http.StatusUnauthorized,
+			response: response.Response{
+				Code:    code.UNAUTHORIZED,
+				Message: errs.ErrUnauthorized.Error(),
+			},
+		},
+		{
+			description: "should return error with status code 409 when provider account is already linked",
+			body:        request,
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("Link", 1, request).Return(nil, errs.ErrIdentityAlreadyLinked)
+			},
+			statusCode: http.StatusConflict,
+			response: response.Response{
+				Code:    code.IDENTITY_ALREADY_LINKED,
+				Message: errs.ErrIdentityAlreadyLinked.Error(),
+			},
+		},
+		{
+			description: "should return linked account with status code 201",
+			body:        request,
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("Link", 1, request).Return(linked, nil)
+			},
+			statusCode: http.StatusCreated,
+			response: response.Response{
+				Code:    code.CREATED,
+				Message: "created",
+				Data:    linked,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			identityService := mocks.NewUserIdentityService(t)
+			tc.beforeTest(identityService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			c.Params = gin.Params{{Key: "provider", Value: "apple"}}
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/users/identities/apple", test.MakeRequestBody(tc.body))
+			h := handler.New(&handler.HandlerConfig{IdentityService: identityService})
+
+			h.LinkUserIdentity(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestUnlinkUserIdentity(t *testing.T) {
+	tests := []struct {
+		description string
+		beforeTest  func(*mocks.UserIdentityService)
+		statusCode  int
+		response    response.Response
+	}{
+		{
+			description: "should return error with status code 404 when provider account is not linked",
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("Unlink", 1, "apple").Return(errs.ErrIdentityNotFound)
+			},
+			statusCode: http.StatusNotFound,
+			response: response.Response{
+				Code:    code.IDENTITY_NOT_FOUND,
+				Message: errs.ErrIdentityNotFound.Error(),
+			},
+		},
+		{
+			description: "should return status code 200 when provider account is unlinked",
+			beforeTest: func(is *mocks.UserIdentityService) {
+				is.On("Unlink", 1, "apple").Return(nil)
+			},
+			statusCode: http.StatusOK,
+			response: response.Response{
+				Code:    code.DELETED,
+				Message: "deleted",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.response)
+			identityService := mocks.NewUserIdentityService(t)
+			tc.beforeTest(identityService)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			c.Params = gin.Params{{Key: "provider", Value: "apple"}}
+			h := handler.New(&handler.HandlerConfig{IdentityService: identityService})
+
+			h.UnlinkUserIdentity(c)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge bounds how long verifiers cache the key set, so a newly
+// published key is picked up well before it starts signing.
+const jwksMaxAge = "public, max-age=300"
+
+// GetJWKS serves the token verification keys as a bare JWK set rather than
+// the usual response envelope, since JWT libraries expect that shape.
+func (h *Handler) GetJWKS(c *gin.Context) {
+	keys := jwttoken.CurrentKeySet()
+	if keys == nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	c.Header("Cache-Control", jwksMaxAge)
+	c.JSON(http.StatusOK, keys.JWKS())
+}
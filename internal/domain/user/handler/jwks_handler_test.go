@@ -0,0 +1,48 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/handler"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJWKS(t *testing.T) {
+	t.Run("should return error with status code 500 when key set is not loaded", func(t *testing.T) {
+		expectedRes, _ := json.Marshal(response.Response{
+			Code:    code.INTERNAL_SERVER_ERROR,
+			Message: errs.ErrInternalServerError.Error(),
+		})
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		h := handler.New(&handler.HandlerConfig{})
+
+		h.GetJWKS(c)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, string(expectedRes), rec.Body.String())
+	})
+
+	t.Run("should return verification keys with status code 200", func(t *testing.T) {
+		test.UseJWTKeySet(t)
+		expectedRes, _ := json.Marshal(jwttoken.CurrentKeySet().JWKS())
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		h := handler.New(&handler.HandlerConfig{})
+
+		h.GetJWKS(c)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, string(expectedRes), rec.Body.String())
+		assert.NotEmpty(t, rec.Header().Get("Cache-Control"))
+	})
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) UserLoginTwoFactor(c *gin.Context) {
+	var request dto.TwoFactorLoginRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	request.Device = sessionDevice(c)
+
+	token, err := h.twoFactorService.CompleteChallenge(&request)
+	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidTwoFactorChallenge) || errors.Is(err, errs.ErrTwoFactorNotEnabled) {
+			response.Error(c, http.StatusUnauthorized, code.INVALID_TWO_FACTOR_CHALLENGE, errs.ErrInvalidTwoFactorChallenge.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidTwoFactorCode) {
+			response.Error(c, http.StatusUnauthorized, code.INVALID_TWO_FACTOR_CODE, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", token)
+}
+
+func (h *Handler) GetTwoFactorStatus(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	res, err := h.twoFactorService.GetStatus(userId)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", res)
+}
+
+func (h *Handler) EnrolTwoFactor(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	res, err := h.twoFactorService.Enrol(userId)
+	if err != nil {
+		if errors.Is(err, errs.ErrTwoFactorAlreadyEnabled) {
+			response.Error(c, http.StatusConflict, code.TWO_FACTOR_ALREADY_ENABLED, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, code.CREATED, "created", res)
+}
+
+func (h *Handler) EnableTwoFactor(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	var request dto.TwoFactorCodeRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	request.IPAddress = c.ClientIP()
+
+	res, err := h.twoFactorService.Enable(userId, &request)
+	if err != nil {
+		if errors.Is(err, errs.ErrTwoFactorNotEnrolled) {
+			response.Error(c, http.StatusNotFound, code.TWO_FACTOR_NOT_ENROLLED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrTwoFactorAlreadyEnabled) {
+			response.Error(c, http.StatusConflict, code.TWO_FACTOR_ALREADY_ENABLED, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidTwoFactorCode) {
+			response.Error(c, http.StatusBadRequest, code.INVALID_TWO_FACTOR_CODE, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "two-factor authentication enabled", res)
+}
+
+func (h *Handler) DisableTwoFactor(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	var request dto.TwoFactorCodeRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	request.IPAddress = c.ClientIP()
+
+	err = h.twoFactorService.Disable(userId, &request)
+	if err != nil {
+		h.twoFactorCodeError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "two-factor authentication disabled", nil)
+}
+
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	var request dto.TwoFactorCodeRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	request.IPAddress = c.ClientIP()
+
+	res, err := h.twoFactorService.RegenerateRecoveryCodes(userId, &request)
+	if err != nil {
+		h.twoFactorCodeError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", res)
+}
+
+func (h *Handler) VerifyTwoFactor(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	var request dto.TwoFactorCodeRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.ErrorValidator(c, http.StatusBadRequest, err)
+		return
+	}
+
+	request.IPAddress = c.ClientIP()
+
+	token, err := h.twoFactorService.Verify(userId, c.GetString("sessionKey"), &request)
+	if err != nil {
+		h.twoFactorCodeError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", token)
+}
+
+func (h *Handler) twoFactorCodeError(c *gin.Context, err error) {
+	if errors.Is(err, errs.ErrTooManyAttempts) {
+		response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+		return
+	}
+	if errors.Is(err, errs.ErrTwoFactorNotEnabled) {
+		response.Error(c, http.StatusBadRequest, code.TWO_FACTOR_NOT_ENABLED, err.Error())
+		return
+	}
+	if errors.Is(err, errs.ErrInvalidTwoFactorCode) {
+		response.Error(c, http.StatusBadRequest, code.INVALID_TWO_FACTOR_CODE, err.Error())
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+}
+
+// RequireFreshTwoFactor guards sensitive actions: users with 2FA enabled
+// must present a token that passed 2FA within TwoFactorFreshAge.
+func (h *Handler) RequireFreshTwoFactor(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	enabled, err := h.twoFactorService.IsEnabled(userId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Response{
+			Code:    code.INTERNAL_SERVER_ERROR,
+			Message: errs.ErrInternalServerError.Error(),
+		})
+		return
+	}
+	if !enabled {
+		return
+	}
+
+	verifiedAt := time.Unix(c.GetInt64("twoFactorAt"), 0)
+	if time.Since(verifiedAt) > constant.TwoFactorFreshAge {
+		c.AbortWithStatusJSON(http.StatusForbidden, response.Response{
+			Code:    code.TWO_FACTOR_REQUIRED,
+			Message: errs.ErrTwoFactorVerificationStale.Error(),
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/handler"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/internal/utils/test"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserLoginTwoFactor(t *testing.T) {
+	type input struct {
+		request    *dto.TwoFactorLoginRequest
+		beforeTest func(*mocks.TwoFactorService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		request = dto.TwoFactorLoginRequest{
+			TwoFactorToken: "challenge",
+			Code:           "123456",
+		}
+		token = dto.Token{
+			AccessToken:  "access",
+			RefreshToken: "refresh",
+		}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 400 when code is missing",
+			input: input{
+				request:    &dto.TwoFactorLoginRequest{TwoFactorToken: "challenge"},
+				beforeTest: func(tfs *mocks.TwoFactorService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.BAD_REQUEST,
+					Message: "Code is required",
+				},
+			},
+		},
+		{
+			description: "should return error with status code 401 when challenge is invalid or expired",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("CompleteChallenge", &request).Return(nil, errs.ErrInvalidTwoFactorChallenge)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusUnauthorized,
+				response: response.Response{
+					Code:    code.INVALID_TWO_FACTOR_CHALLENGE,
+					Message: errs.ErrInvalidTwoFactorChallenge.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 401 when code is invalid",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("CompleteChallenge", &request).Return(nil, errs.ErrInvalidTwoFactorCode)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusUnauthorized,
+				response: response.Response{
+					Code:    code.INVALID_TWO_FACTOR_CODE,
+					Message: errs.ErrInvalidTwoFactorCode.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to complete challenge",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("CompleteChallenge", &request).Return(nil, errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return token with status code 200 when challenge is completed",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("CompleteChallenge", &request).Return(&token, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+					Data:    &token,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			twoFactorService := mocks.NewTwoFactorService(t)
+			tc.input.beforeTest(twoFactorService)
+			h := handler.New(&handler.HandlerConfig{
+				TwoFactorService: twoFactorService,
+			})
+			c.Request, _ = http.NewRequest("POST", "/v1/users/login/two-factor", test.MakeRequestBody(tc.input.request))
+
+			h.UserLoginTwoFactor(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestEnableTwoFactor(t *testing.T) {
+	type input struct {
+		request    *dto.TwoFactorCodeRequest
+		beforeTest func(*mocks.TwoFactorService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	var (
+		request       = dto.TwoFactorCodeRequest{Code: "123456"}
+		recoveryCodes = dto.TwoFactorRecoveryCodes{RecoveryCodes: []string{"abcde-fghjk"}}
+	)
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when user has not enrolled",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("Enable", 1, &request).Return(nil, errs.ErrTwoFactorNotEnrolled)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.TWO_FACTOR_NOT_ENROLLED,
+					Message: errs.ErrTwoFactorNotEnrolled.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 409 when two factor is already enabled",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("Enable", 1, &request).Return(nil, errs.ErrTwoFactorAlreadyEnabled)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.TWO_FACTOR_ALREADY_ENABLED,
+					Message: errs.ErrTwoFactorAlreadyEnabled.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 400 when code is invalid",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("Enable", 1, &request).Return(nil, errs.ErrInvalidTwoFactorCode)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+				response: response.Response{
+					Code:    code.INVALID_TWO_FACTOR_CODE,
+					Message: errs.ErrInvalidTwoFactorCode.Error(),
+				},
+			},
+		},
+		{
+			description: "should return recovery codes with status code 200 when two factor is enabled",
+			input: input{
+				request: &request,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("Enable", 1, &request).Return(&recoveryCodes, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "two-factor authentication enabled",
+					Data:    &recoveryCodes,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			twoFactorService := mocks.NewTwoFactorService(t)
+			tc.input.beforeTest(twoFactorService)
+			h := handler.New(&handler.HandlerConfig{
+				TwoFactorService: twoFactorService,
+			})
+			c.Request, _ = http.NewRequest("POST", "/v1/users/two-factor/enable", test.MakeRequestBody(tc.input.request))
+
+			h.EnableTwoFactor(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestRequireFreshTwoFactor(t *testing.T) {
+	type input struct {
+		shopOwnerId int
+		twoFactorAt int64
+		beforeTest  func(*mocks.TwoFactorService)
+	}
+	type expected struct {
+		statusCode int
+		aborted    bool
+		response   *response.Response
+	}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should continue when user has no two factor",
+			input: input{
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("IsEnabled", 1).Return(false, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "should continue when token was verified recently",
+			input: input{
+				twoFactorAt: time.Now().Add(-time.Minute).Unix(),
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("IsEnabled", 1).Return(true, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "should check the acting user instead of the shop owner",
+			input: input{
+				shopOwnerId: 2,
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("IsEnabled", 1).Return(false, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			description: "should abort with status code 403 when token never passed two factor",
+			input: input{
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("IsEnabled", 1).Return(true, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusForbidden,
+				aborted:    true,
+				response: &response.Response{
+					Code:    code.TWO_FACTOR_REQUIRED,
+					Message: errs.ErrTwoFactorVerificationStale.Error(),
+				},
+			},
+		},
+		{
+			description: "should abort with status code 403 when verification is stale",
+			input: input{
+				twoFactorAt: time.Now().Add(-time.Hour).Unix(),
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("IsEnabled", 1).Return(true, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusForbidden,
+				aborted:    true,
+				response: &response.Response{
+					Code:    code.TWO_FACTOR_REQUIRED,
+					Message: errs.ErrTwoFactorVerificationStale.Error(),
+				},
+			},
+		},
+		{
+			description: "should abort with status code 500 when failed to check two factor",
+			input: input{
+				beforeTest: func(tfs *mocks.TwoFactorService) {
+					tfs.On("IsEnabled", 1).Return(false, errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				aborted:    true,
+				response: &response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			if tc.input.shopOwnerId != 0 {
+				c.Set("shopOwnerId", tc.input.shopOwnerId)
+			}
+			c.Set("twoFactorAt", tc.input.twoFactorAt)
+			twoFactorService := mocks.NewTwoFactorService(t)
+			tc.input.beforeTest(twoFactorService)
+			h := handler.New(&handler.HandlerConfig{
+				TwoFactorService: twoFactorService,
+			})
+			c.Request, _ = http.NewRequest("PUT", "/v1/users/emails", nil)
+
+			h.RequireFreshTwoFactor(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, tc.expected.aborted, c.IsAborted())
+			if tc.expected.response != nil {
+				expectedRes, _ := json.Marshal(tc.expected.response)
+				assert.Equal(t, string(expectedRes), rec.Body.String())
+			}
+		})
+	}
+}
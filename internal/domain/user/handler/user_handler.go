@@ -66,51 +66,15 @@ func (h *Handler) UserLogin(c *gin.Context) {
 		return
 	}
 
-	token, err := h.userService.SignIn(&newLogin, newLogin.Password)
-	if err != nil {
-		if errors.Is(err, errs.ErrInvalidCredential) {
-			response.Error(c, http.StatusUnauthorized, code.UNAUTHORIZED, err.Error())
-			return
-		}
-
-		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
-		return
-	}
-
-	response.Success(c, http.StatusOK, code.OK, "ok", token)
-}
+	newLogin.Device = sessionDevice(c)
 
-func (h *Handler) UserRegistrationWithGoogle(c *gin.Context) {
-	var newUser dto.UserRegistrationWithGoogleRequest
-	errBinding := c.ShouldBindJSON(&newUser)
-	if errBinding != nil {
-		response.ErrorValidator(c, http.StatusBadRequest, errBinding)
-		return
-	}
-
-	user, err := h.userService.SignUpWithGoogle(&newUser)
+	token, err := h.userService.SignIn(&newLogin, newLogin.Password)
 	if err != nil {
-		if errors.Is(err, errs.ErrUserAlreadyExist) {
-			response.Error(c, http.StatusConflict, code.EMAIL_ALREADY_REGISTERED, err.Error())
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
 			return
 		}
-		if errors.Is(err, errs.ErrUsernameUsed) {
-			response.Error(c, http.StatusConflict, code.USERNAME_ALREADY_REGISTERED, err.Error())
-			return
-		}
-		if errors.Is(err, errs.ErrInvalidUsernamePattern) {
-			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_USERNAME_PATTERN, err.Error())
-			return
-		}
-		if errors.Is(err, errs.ErrInvalidPasswordPattern) {
-			response.Error(c, http.StatusUnprocessableEntity, code.INVALID_PASSWORD_PATTERN, err.Error())
-			return
-		}
-		if errors.Is(err, errs.ErrContainEmail) {
-			response.Error(c, http.StatusUnprocessableEntity, code.PASSWORD_CONTAIN_EMAIL, err.Error())
-			return
-		}
-		if errors.Is(err, errs.ErrUnauthorized) {
+		if errors.Is(err, errs.ErrInvalidCredential) {
 			response.Error(c, http.StatusUnauthorized, code.UNAUTHORIZED, err.Error())
 			return
 		}
@@ -119,34 +83,12 @@ func (h *Handler) UserRegistrationWithGoogle(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusCreated, code.CREATED, "Sign up with google successful", user)
-}
-
-func (h *Handler) UserLoginWithGoogle(c *gin.Context) {
-	var newLogin dto.UserLoginWithGoogleRequest
-	errBinding := c.ShouldBindJSON(&newLogin)
-	if errBinding != nil {
-		response.ErrorValidator(c, http.StatusBadRequest, errBinding)
-		return
-	}
-
-	token, err := h.userService.SignInWithGoogle(&newLogin)
-	if err != nil {
-		if errors.Is(err, errs.ErrInvalidCredential) {
-			response.Error(c, http.StatusNotFound, code.USER_NOT_REGISTERED, err.Error())
-			return
-		}
-
-		if errors.Is(err, errs.ErrUnauthorized) {
-			response.Error(c, http.StatusUnauthorized, code.UNAUTHORIZED, err.Error())
-			return
-		}
-
-		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+	if token.TwoFactorToken != "" {
+		response.Success(c, http.StatusOK, code.TWO_FACTOR_REQUIRED, "two-factor authentication required", token)
 		return
 	}
 
-	response.Success(c, http.StatusOK, code.OK, "Sign in with google successful", token)
+	response.Success(c, http.StatusOK, code.OK, "ok", token)
 }
 
 func (h *Handler) GetSession(c *gin.Context) {
@@ -154,7 +96,7 @@ func (h *Handler) GetSession(c *gin.Context) {
 	token := c.GetHeader("authorization")
 	parsedToken := strings.Replace(token, "Bearer ", "", -1)
 
-	err := h.userService.GetSession(userId, parsedToken)
+	sessionKey, err := h.userService.GetSession(userId, parsedToken)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{
 			Code:    code.UNAUTHORIZED,
@@ -162,6 +104,8 @@ func (h *Handler) GetSession(c *gin.Context) {
 		})
 		return
 	}
+
+	c.Set("sessionKey", sessionKey)
 }
 
 func (h *Handler) RenewSession(c *gin.Context) {
@@ -169,13 +113,18 @@ func (h *Handler) RenewSession(c *gin.Context) {
 	token := c.GetHeader("authorization")
 	parsedToken := strings.Replace(token, "Bearer ", "", -1)
 
-	newToken, err := h.userService.RenewToken(userId, parsedToken)
+	newToken, err := h.userService.RenewToken(userId, c.GetString("familyId"), parsedToken)
 	if err != nil {
 		if errors.Is(err, errs.ErrExpiredToken) {
 			response.Error(c, http.StatusUnauthorized, code.TOKEN_EXPIRED, err.Error())
 			return
 		}
 
+		if errors.Is(err, errs.ErrRefreshTokenReused) {
+			response.Error(c, http.StatusUnauthorized, code.REFRESH_TOKEN_REUSED, err.Error())
+			return
+		}
+
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
 		return
 	}
@@ -204,7 +153,7 @@ func (h *Handler) UpdateUserEmail(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, code.UPDATED, "updated", res)
+	response.Success(c, http.StatusOK, code.OK, "confirmation link sent", res)
 }
 
 func (h *Handler) UpdateUsername(c *gin.Context) {
@@ -246,6 +195,7 @@ func (h *Handler) SignOut(c *gin.Context) {
 
 	token := c.GetHeader("authorization")
 	request.AccessToken = strings.Replace(token, "Bearer ", "", -1)
+	request.SessionKey = c.GetString("sessionKey")
 	request.UserId = c.GetInt("userId")
 
 	err = h.userService.SignOut(&request)
@@ -266,9 +216,14 @@ func (h *Handler) RequestPasswordChange(c *gin.Context) {
 		return
 	}
 	request.UserId = c.GetInt("userId")
+	request.IPAddress = c.ClientIP()
 
 	err = h.userService.RequestPasswordChange(&request)
 	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
 		if errors.Is(err, errs.ErrUserDoesNotExist) {
 			response.Error(c, http.StatusNotFound, code.USER_NOT_REGISTERED, err.Error())
 			return
@@ -305,9 +260,14 @@ func (h *Handler) CompletePasswordChange(c *gin.Context) {
 		return
 	}
 	request.UserId = c.GetInt("userId")
+	request.IPAddress = c.ClientIP()
 
 	err = h.userService.CompletePasswordChange(&request)
 	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
 		if errors.Is(err, errs.ErrIncorrectVerificationCode) {
 			response.Error(c, http.StatusBadRequest, code.INCORRECT_VERIFICATION_CODE, err.Error())
 			return
@@ -354,8 +314,14 @@ func (h *Handler) CompletePasswordReset(c *gin.Context) {
 		return
 	}
 
+	request.IPAddress = c.ClientIP()
+
 	err = h.userService.CompletePasswordReset(&request)
 	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
 		if errors.Is(err, errs.ErrResetPasswordTokenNotFound) {
 			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
 			return
@@ -379,24 +345,3 @@ func (h *Handler) CompletePasswordReset(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, code.OK, "ok", nil)
 }
-
-func (h *Handler) AdminSignIn(c *gin.Context) {
-	var request dto.UserLogin
-	err := c.ShouldBindJSON(&request)
-	if err != nil {
-		response.ErrorValidator(c, http.StatusBadRequest, err)
-		return
-	}
-
-	res, err := h.userService.AdminSignIn(&request)
-	if err != nil {
-		if errors.Is(err, errs.ErrInvalidCredential) {
-			response.Error(c, http.StatusBadRequest, code.WRONG_PASSWORD, err.Error())
-			return
-		}
-		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
-		return
-	}
-
-	response.Success(c, http.StatusOK, code.OK, "ok", res)
-}
@@ -237,6 +237,23 @@ func TestUserLogin(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "should return error with status code 429 when sign in attempts are throttled",
+			input: input{
+				dto: &dto.UserLogin{
+					Email:    "user@mail.com",
+					Password: "password",
+				},
+				err: errs.ErrTooManyAttempts,
+			},
+			expected: expected{
+				statusCode: http.StatusTooManyRequests,
+				response: response.Response{
+					Code:    code.TOO_MANY_ATTEMPTS,
+					Message: errs.ErrTooManyAttempts.Error(),
+				},
+			},
+		},
 		{
 			description: "should return error when internal server error",
 			input: input{
@@ -260,7 +277,9 @@ func TestUserLogin(t *testing.T) {
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
 			mockService := new(mocks.UserService)
-			mockService.On("SignIn", tc.input.dto, tc.input.dto.Password).Return(tc.expected.response.Data, tc.input.err)
+			login := *tc.input.dto
+			login.Device = dto.SessionDevice{IPAddress: "192.0.2.1"}
+			mockService.On("SignIn", &login, tc.input.dto.Password).Return(tc.expected.response.Data, tc.input.err)
 			h := handler.New(&handler.HandlerConfig{
 				UserService: mockService,
 			})
@@ -315,7 +334,7 @@ func TestGetSession(t *testing.T) {
 			c, _ := gin.CreateTestContext(rec)
 			c.Set("userId", tc.input.userId)
 			mockService := new(mocks.UserService)
-			mockService.On("GetSession", tc.input.userId, tc.input.token).Return(tc.input.err)
+			mockService.On("GetSession", tc.input.userId, tc.input.token).Return("", tc.input.err)
 			h := handler.New(&handler.HandlerConfig{
 				UserService: mockService,
 			})
@@ -362,6 +381,22 @@ func TestRenewSession(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "should return error with status code 401 when refresh token was already used",
+			input: input{
+				userId:     1,
+				token:      "",
+				mockReturn: nil,
+				err:        errs.ErrRefreshTokenReused,
+			},
+			expected: expected{
+				statusCode: http.StatusUnauthorized,
+				response: response.Response{
+					Code:    code.REFRESH_TOKEN_REUSED,
+					Message: errs.ErrRefreshTokenReused.Error(),
+				},
+			},
+		},
 		{
 			description: "should return error with status code 500 when failed to renew session",
 			input: input{
@@ -403,8 +438,9 @@ func TestRenewSession(t *testing.T) {
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
 			c.Set("userId", tc.input.userId)
+			c.Set("familyId", "session")
 			mockService := new(mocks.UserService)
-			mockService.On("RenewToken", tc.input.userId, tc.input.token).Return(tc.input.mockReturn, tc.input.err)
+			mockService.On("RenewToken", tc.input.userId, "session", tc.input.token).Return(tc.input.mockReturn, tc.input.err)
 			h := handler.New(&handler.HandlerConfig{
 				UserService: mockService,
 			})
@@ -522,8 +558,8 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		invalidCredential = ""
 	)
 	type input struct {
-		dto         *dto.UserRegistrationWithGoogleRequest
-		beforeTests func(mockUserService *mocks.UserService)
+		dto         *dto.UserRegistrationWithProviderRequest
+		beforeTests func(mockIdentityService *mocks.UserIdentityService)
 		err         error
 	}
 	type expected struct {
@@ -539,13 +575,13 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return credential required and bad request status code if credential is empty",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: invalidCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: nil,
-				beforeTests: func(mockUserService *mocks.UserService) {
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
 				},
 			},
 			expected: expected{
@@ -559,14 +595,15 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return ErrUserAlreadyExist and conflict status code if user already exist",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: errs.ErrUserAlreadyExist,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -584,14 +621,15 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return error ErrUsernameUsed and conflict status code if username already used",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: errs.ErrUsernameUsed,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -609,14 +647,15 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return error ErrInvalidUsernamePattern and StatusUnprocessableEntity status code if username is invalid",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: errs.ErrInvalidUsernamePattern,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -634,14 +673,15 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return error ErrInvalidPasswordPattern and StatusUnprocessableEntity status code if password is invalid",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: errs.ErrUsernameUsed,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -659,14 +699,15 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return error ErrContainEmail and StatusUnprocessableEntity status code if password is invalid",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: errs.ErrUsernameUsed,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -684,14 +725,15 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return error ErrUnauthorized and StatusUnauthorized status code if credential is invalid",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: errs.ErrUsernameUsed,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -709,14 +751,15 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 		{
 			description: "it should return error ErrInternalServer and StatusInternalServerError status code if error is not handled",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: errs.ErrUsernameUsed,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -731,17 +774,70 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "it should return error ErrIdentityLinkPending and conflict status code if an account already has the email",
+			input: input{
+				dto: &dto.UserRegistrationWithProviderRequest{
+					Credential: validCredential,
+					Username:   "testasd",
+					Password:   "password123",
+				},
+				err: errs.ErrIdentityLinkPending,
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
+						Credential: validCredential,
+						Username:   "testasd",
+						Password:   "password123",
+					}).Return(nil, errs.ErrIdentityLinkPending)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.IDENTITY_LINK_PENDING,
+					Message: errs.ErrIdentityLinkPending.Error(),
+				},
+			},
+		},
+		{
+			description: "it should return error ErrIdentityEmailMissing and StatusUnprocessableEntity status code if provider did not share an email",
+			input: input{
+				dto: &dto.UserRegistrationWithProviderRequest{
+					Credential: validCredential,
+					Username:   "testasd",
+					Password:   "password123",
+				},
+				err: errs.ErrIdentityEmailMissing,
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
+						Credential: validCredential,
+						Username:   "testasd",
+						Password:   "password123",
+					}).Return(nil, errs.ErrIdentityEmailMissing)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusUnprocessableEntity,
+				response: response.Response{
+					Code:    code.IDENTITY_EMAIL_MISSING,
+					Message: errs.ErrIdentityEmailMissing.Error(),
+				},
+			},
+		},
 		{
 			description: "it should return nil error and StatusOK status code if success",
 			input: input{
-				dto: &dto.UserRegistrationWithGoogleRequest{
+				dto: &dto.UserRegistrationWithProviderRequest{
 					Credential: validCredential,
 					Username:   "testasd",
 					Password:   "password123",
 				},
 				err: nil,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignUpWithGoogle", &dto.UserRegistrationWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignUp", &dto.UserRegistrationWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 						Username:   "testasd",
 						Password:   "password123",
@@ -764,10 +860,10 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 			expectedRes, _ := json.Marshal(tc.expected.response)
 			rec := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rec)
-			userServiceMock := mocks.NewUserService(t)
-			tc.beforeTests(userServiceMock)
+			identityServiceMock := mocks.NewUserIdentityService(t)
+			tc.beforeTests(identityServiceMock)
 			cfg := handler.HandlerConfig{
-				UserService: userServiceMock,
+				IdentityService: identityServiceMock,
 			}
 
 			h := handler.New(&cfg)
@@ -776,7 +872,7 @@ func TestUserRegistrationWithGoogle(t *testing.T) {
 			body, _ := json.Marshal(tc.input.dto)
 			c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-			h.UserRegistrationWithGoogle(c)
+			h.UserRegistrationWithProvider(c)
 
 			assert.Equal(t, tc.expected.statusCode, rec.Code)
 			assert.Equal(t, string(expectedRes), rec.Body.String())
@@ -790,8 +886,8 @@ func TestUserLoginWithGoogle(t *testing.T) {
 		invalidCredential = ""
 	)
 	type input struct {
-		dto         *dto.UserLoginWithGoogleRequest
-		beforeTests func(mockUserService *mocks.UserService)
+		dto         *dto.UserLoginWithProviderRequest
+		beforeTests func(mockIdentityService *mocks.UserIdentityService)
 		err         error
 	}
 	type expected struct {
@@ -807,11 +903,11 @@ func TestUserLoginWithGoogle(t *testing.T) {
 		{
 			description: "it should return credential required and bad request status code if credential is empty",
 			input: input{
-				dto: &dto.UserLoginWithGoogleRequest{
+				dto: &dto.UserLoginWithProviderRequest{
 					Credential: invalidCredential,
 				},
 				err: nil,
-				beforeTests: func(mockUserService *mocks.UserService) {
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
 
 				},
 			},
@@ -826,12 +922,13 @@ func TestUserLoginWithGoogle(t *testing.T) {
 		{
 			description: "it should return status code 404 when user does not exist",
 			input: input{
-				dto: &dto.UserLoginWithGoogleRequest{
+				dto: &dto.UserLoginWithProviderRequest{
 					Credential: validCredential,
 				},
 				err: errs.ErrInvalidCredential,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignInWithGoogle", &dto.UserLoginWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 					}).Return(nil, errs.ErrInvalidCredential)
 
@@ -847,12 +944,13 @@ func TestUserLoginWithGoogle(t *testing.T) {
 		{
 			description: "it should return status code 401 when google jwt token is invalid",
 			input: input{
-				dto: &dto.UserLoginWithGoogleRequest{
+				dto: &dto.UserLoginWithProviderRequest{
 					Credential: validCredential,
 				},
 				err: errs.ErrUnauthorized,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignInWithGoogle", &dto.UserLoginWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 					}).Return(nil, errs.ErrUnauthorized)
 				},
@@ -865,15 +963,58 @@ func TestUserLoginWithGoogle(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "it should return status code 409 when an account with the email has to confirm the link",
+			input: input{
+				dto: &dto.UserLoginWithProviderRequest{
+					Credential: validCredential,
+				},
+				err: errs.ErrIdentityLinkPending,
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+						Provider:   "google",
+						Credential: validCredential,
+					}).Return(nil, errs.ErrIdentityLinkPending)
+				}},
+			expected: expected{
+				statusCode: http.StatusConflict,
+				response: response.Response{
+					Code:    code.IDENTITY_LINK_PENDING,
+					Message: errs.ErrIdentityLinkPending.Error(),
+				},
+			},
+		},
+		{
+			description: "it should return status code 404 when provider is not configured",
+			input: input{
+				dto: &dto.UserLoginWithProviderRequest{
+					Credential: validCredential,
+				},
+				err: errs.ErrUnknownIdentityProvider,
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+						Provider:   "google",
+						Credential: validCredential,
+					}).Return(nil, errs.ErrUnknownIdentityProvider)
+				}},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.UNKNOWN_IDENTITY_PROVIDER,
+					Message: errs.ErrUnknownIdentityProvider.Error(),
+				},
+			},
+		},
 		{
 			description: "it should return status code 500 if something went wrong when trying to get user data",
 			input: input{
-				dto: &dto.UserLoginWithGoogleRequest{
+				dto: &dto.UserLoginWithProviderRequest{
 					Credential: validCredential,
 				},
 				err: errs.ErrInternalServerError,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignInWithGoogle", &dto.UserLoginWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 					}).Return(nil, errs.ErrInternalServerError)
 				}},
@@ -886,15 +1027,39 @@ func TestUserLoginWithGoogle(t *testing.T) {
 			},
 		},
 
+		{
+			description: "it should return two factor required code if user has two factor enabled",
+			input: input{
+				dto: &dto.UserLoginWithProviderRequest{
+					Credential: validCredential,
+				},
+				err: nil,
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+						Provider:   "google",
+						Credential: validCredential,
+					}).Return(&dto.Token{TwoFactorToken: "challenge"}, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.TWO_FACTOR_REQUIRED,
+					Message: "two-factor authentication required",
+					Data:    &dto.Token{TwoFactorToken: "challenge"},
+				},
+			},
+		},
 		{
 			description: "it should return status code 200 if succeed login with google",
 			input: input{
-				dto: &dto.UserLoginWithGoogleRequest{
+				dto: &dto.UserLoginWithProviderRequest{
 					Credential: validCredential,
 				},
 				err: nil,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("SignInWithGoogle", &dto.UserLoginWithGoogleRequest{
+				beforeTests: func(mockIdentityService *mocks.UserIdentityService) {
+					mockIdentityService.On("SignIn", &dto.UserLoginWithProviderRequest{
+						Provider:   "google",
 						Credential: validCredential,
 					}).Return(&dto.Token{}, nil)
 				},
@@ -920,14 +1085,14 @@ func TestUserLoginWithGoogle(t *testing.T) {
 			body, _ := json.Marshal(tc.input.dto)
 			c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-			userServiceMock := mocks.NewUserService(t)
-			tc.beforeTests(userServiceMock)
+			identityServiceMock := mocks.NewUserIdentityService(t)
+			tc.beforeTests(identityServiceMock)
 			cfg := handler.HandlerConfig{
-				UserService: userServiceMock,
+				IdentityService: identityServiceMock,
 			}
 			h := handler.New(&cfg)
 
-			h.UserLoginWithGoogle(c)
+			h.UserLoginWithProvider(c)
 
 			assert.Equal(t, tc.expected.statusCode, rec.Code)
 			assert.Equal(t, string(expectedRes), rec.Body.String())
@@ -1011,7 +1176,7 @@ func TestUpdateEmail(t *testing.T) {
 			},
 		},
 		{
-			description: "should return updated user data with status code 200 when update email succeed",
+			description: "should return requested email with status code 200 when confirmation link is sent",
 			input: input{
 				userId: 1,
 				request: &dto.UpdateEmailRequest{
@@ -1026,8 +1191,8 @@ func TestUpdateEmail(t *testing.T) {
 			expected: expected{
 				statusCode: http.StatusOK,
 				response: response.Response{
-					Code:    code.UPDATED,
-					Message: "updated",
+					Code:    code.OK,
+					Message: "confirmation link sent",
 					Data:    &dto.UpdateEmailResponse{Email: "new.email@mail.com"},
 				},
 			},
@@ -1796,6 +1961,7 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error bad request when request is nil",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "user@mail.com",
 					Token:       "token",
 					NewPassword: "",
 				},
@@ -1814,11 +1980,13 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error ErrResetPasswordTokenNotFound and status Not Found when CompletePasswordReset failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "user@mail.com",
 					Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 					NewPassword: "newPassword123",
 				},
 				beforeTests: func(mockUserService *mocks.UserService) {
 					mockUserService.On("CompletePasswordReset", &dto.CompletePasswordResetRequest{
+						Email:       "user@mail.com",
 						Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 						NewPassword: "newPassword123",
 					}).Return(errs.ErrResetPasswordTokenNotFound)
@@ -1836,11 +2004,13 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error ErrSamePassword and status Bad Request when CompletePasswordReset failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "user@mail.com",
 					Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 					NewPassword: "newPassword123",
 				},
 				beforeTests: func(mockUserService *mocks.UserService) {
 					mockUserService.On("CompletePasswordReset", &dto.CompletePasswordResetRequest{
+						Email:       "user@mail.com",
 						Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 						NewPassword: "newPassword123",
 					}).Return(errs.ErrSamePassword)
@@ -1858,11 +2028,13 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error ErrInvalidPasswordPattern and status StatusUnprocessableEntitywhen CompletePasswordReset failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "user@mail.com",
 					Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 					NewPassword: "newPassword123",
 				},
 				beforeTests: func(mockUserService *mocks.UserService) {
 					mockUserService.On("CompletePasswordReset", &dto.CompletePasswordResetRequest{
+						Email:       "user@mail.com",
 						Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 						NewPassword: "newPassword123",
 					}).Return(errs.ErrInvalidPasswordPattern)
@@ -1880,11 +2052,13 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error ErrContainUsername and status StatusUnprocessableEntitywhen CompletePasswordReset failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "user@mail.com",
 					Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 					NewPassword: "newPassword123",
 				},
 				beforeTests: func(mockUserService *mocks.UserService) {
 					mockUserService.On("CompletePasswordReset", &dto.CompletePasswordResetRequest{
+						Email:       "user@mail.com",
 						Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 						NewPassword: "newPassword123",
 					}).Return(errs.ErrContainUsername)
@@ -1902,11 +2076,13 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error ErrInternalServerError and status Internal Server Error CompletePasswordReset failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "user@mail.com",
 					Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 					NewPassword: "newPassword123",
 				},
 				beforeTests: func(mockUserService *mocks.UserService) {
 					mockUserService.On("CompletePasswordReset", &dto.CompletePasswordResetRequest{
+						Email:       "user@mail.com",
 						Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 						NewPassword: "newPassword123",
 					}).Return(errs.ErrInternalServerError)
@@ -1924,11 +2100,13 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return nil and status OK when CompletePasswordReset success",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "user@mail.com",
 					Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 					NewPassword: "newPassword123",
 				},
 				beforeTests: func(mockUserService *mocks.UserService) {
 					mockUserService.On("CompletePasswordReset", &dto.CompletePasswordResetRequest{
+						Email:       "user@mail.com",
 						Token:       "0020cf5fdf24ca583b988973fc985abc8910f049191ddfa7ca77dfd5ac705e66",
 						NewPassword: "newPassword123",
 					}).Return(nil)
@@ -1964,131 +2142,3 @@ func TestCompletePasswordReset(t *testing.T) {
 		})
 	}
 }
-
-func TestAdminSignIn(t *testing.T) {
-
-	var (
-		req = &dto.UserLogin{
-			Email:    "kedai@mail.com",
-			Password: "test",
-		}
-	)
-
-	type input struct {
-		request     *dto.UserLogin
-		beforeTests func(*mocks.UserService)
-	}
-	type expected struct {
-		statusCode int
-		response   response.Response
-	}
-	cases := []struct {
-		description string
-		input       input
-		expected    expected
-	}{
-		{
-			description: "should return error with status code 400 when binding request failed",
-			input: input{
-				request: &dto.UserLogin{
-					Email:    "test",
-					Password: "test",
-				},
-				beforeTests: func(mockUserService *mocks.UserService) {
-				},
-			},
-			expected: expected{
-				statusCode: http.StatusBadRequest,
-				response: response.Response{
-					Code:    code.BAD_REQUEST,
-					Message: "Email must be an email format",
-				},
-			},
-		},
-		{
-			description: "should return error with status code 400 when binding request failed",
-			input: input{
-				request: &dto.UserLogin{
-					Email: "kedai@mail.com",
-				},
-				beforeTests: func(mockUserService *mocks.UserService) {
-				},
-			},
-			expected: expected{
-				statusCode: http.StatusBadRequest,
-				response: response.Response{
-					Code:    code.BAD_REQUEST,
-					Message: "Password is required",
-				},
-			},
-		},
-		{
-			description: "should return error with status code 400 when credentials are invalid",
-			input: input{
-				request: req,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("AdminSignIn", req).Return(nil, errs.ErrInvalidCredential)
-				},
-			},
-			expected: expected{
-				statusCode: http.StatusBadRequest,
-				response: response.Response{
-					Code:    code.WRONG_PASSWORD,
-					Message: errs.ErrInvalidCredential.Error(),
-				},
-			},
-		},
-		{
-			description: "should return error with status code 500 when server errors",
-			input: input{
-				request: req,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("AdminSignIn", req).Return(nil, errors.New("internal server error"))
-				},
-			},
-			expected: expected{
-				statusCode: http.StatusInternalServerError,
-				response: response.Response{
-					Code:    code.INTERNAL_SERVER_ERROR,
-					Message: errs.ErrInternalServerError.Error(),
-				},
-			},
-		},
-		{
-			description: "should return token with status code 200 when succesfull",
-			input: input{
-				request: req,
-				beforeTests: func(mockUserService *mocks.UserService) {
-					mockUserService.On("AdminSignIn", req).Return(&dto.Token{}, nil)
-				},
-			},
-			expected: expected{
-				statusCode: http.StatusOK,
-				response: response.Response{
-					Code:    code.OK,
-					Message: "ok",
-					Data:    &dto.Token{},
-				},
-			},
-		},
-	}
-	for _, tc := range cases {
-		t.Run(tc.description, func(t *testing.T) {
-			expectedRes, _ := json.Marshal(tc.expected.response)
-			rec := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(rec)
-			userService := mocks.NewUserService(t)
-			tc.input.beforeTests(userService)
-			cfg := handler.HandlerConfig{
-				UserService: userService,
-			}
-			h := handler.New(&cfg)
-			c.Request, _ = http.NewRequest("POST", "/v1/admin/login", test.MakeRequestBody(tc.input.request))
-
-			h.AdminSignIn(c)
-
-			assert.Equal(t, tc.expected.statusCode, rec.Code)
-			assert.Equal(t, string(expectedRes), rec.Body.String())
-		})
-	}
-}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetUserSessions(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	sessions, err := h.sessionService.GetSessions(userId, c.GetString("sessionKey"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "ok", sessions)
+}
+
+func (h *Handler) RevokeUserSession(c *gin.Context) {
+	userId := c.GetInt("userId")
+	sessionId, err := strconv.Atoi(c.Param("sessionId"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, code.SESSION_NOT_FOUND, errs.ErrSessionNotFound.Error())
+		return
+	}
+
+	err = h.sessionService.RevokeSession(userId, sessionId)
+	if err != nil {
+		if errors.Is(err, errs.ErrSessionNotFound) {
+			response.Error(c, http.StatusNotFound, code.SESSION_NOT_FOUND, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "session revoked", nil)
+}
+
+func (h *Handler) RevokeOtherUserSessions(c *gin.Context) {
+	userId := c.GetInt("userId")
+
+	err := h.sessionService.RevokeOtherSessions(userId, c.GetString("sessionKey"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, code.OK, "signed out of every other device", nil)
+}
+
+func sessionDevice(c *gin.Context) dto.SessionDevice {
+	return dto.SessionDevice{
+		DeviceID:  c.GetHeader(constant.DeviceIDHeader),
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
@@ -0,0 +1,217 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/internal/common/code"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/handler"
+	"kedai/backend/be-kedai/internal/utils/response"
+	"kedai/backend/be-kedai/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserSessions(t *testing.T) {
+	type input struct {
+		beforeTest func(*mocks.UserSessionService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	sessions := []*dto.UserSessionResponse{
+		{ID: 1, DeviceID: "phone", IsCurrent: true},
+		{ID: 2, DeviceID: "laptop"},
+	}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 500 when failed to get sessions",
+			input: input{
+				beforeTest: func(uss *mocks.UserSessionService) {
+					uss.On("GetSessions", 1, "current").Return(nil, errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return sessions with status code 200",
+			input: input{
+				beforeTest: func(uss *mocks.UserSessionService) {
+					uss.On("GetSessions", 1, "current").Return(sessions, nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "ok",
+					Data:    sessions,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			c.Set("sessionKey", "current")
+			sessionService := mocks.NewUserSessionService(t)
+			tc.input.beforeTest(sessionService)
+			h := handler.New(&handler.HandlerConfig{
+				SessionService: sessionService,
+			})
+			c.Request, _ = http.NewRequest("GET", "/v1/users/sessions", nil)
+
+			h.GetUserSessions(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestRevokeUserSession(t *testing.T) {
+	type input struct {
+		sessionId  string
+		beforeTest func(*mocks.UserSessionService)
+	}
+	type expected struct {
+		statusCode int
+		response   response.Response
+	}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error with status code 404 when session id is invalid",
+			input: input{
+				sessionId:  "abc",
+				beforeTest: func(uss *mocks.UserSessionService) {},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.SESSION_NOT_FOUND,
+					Message: errs.ErrSessionNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 404 when session is not found",
+			input: input{
+				sessionId: "2",
+				beforeTest: func(uss *mocks.UserSessionService) {
+					uss.On("RevokeSession", 1, 2).Return(errs.ErrSessionNotFound)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+				response: response.Response{
+					Code:    code.SESSION_NOT_FOUND,
+					Message: errs.ErrSessionNotFound.Error(),
+				},
+			},
+		},
+		{
+			description: "should return error with status code 500 when failed to revoke session",
+			input: input{
+				sessionId: "2",
+				beforeTest: func(uss *mocks.UserSessionService) {
+					uss.On("RevokeSession", 1, 2).Return(errors.New("failed"))
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+				response: response.Response{
+					Code:    code.INTERNAL_SERVER_ERROR,
+					Message: errs.ErrInternalServerError.Error(),
+				},
+			},
+		},
+		{
+			description: "should return status code 200 when session is revoked",
+			input: input{
+				sessionId: "2",
+				beforeTest: func(uss *mocks.UserSessionService) {
+					uss.On("RevokeSession", 1, 2).Return(nil)
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				response: response.Response{
+					Code:    code.OK,
+					Message: "session revoked",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			expectedRes, _ := json.Marshal(tc.expected.response)
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Set("userId", 1)
+			c.AddParam("sessionId", tc.input.sessionId)
+			sessionService := mocks.NewUserSessionService(t)
+			tc.input.beforeTest(sessionService)
+			h := handler.New(&handler.HandlerConfig{
+				SessionService: sessionService,
+			})
+			c.Request, _ = http.NewRequest("DELETE", "/v1/users/sessions/"+tc.input.sessionId, nil)
+
+			h.RevokeUserSession(c)
+
+			assert.Equal(t, tc.expected.statusCode, rec.Code)
+			assert.Equal(t, string(expectedRes), rec.Body.String())
+		})
+	}
+}
+
+func TestRevokeOtherUserSessions(t *testing.T) {
+	t.Run("should keep the current session and return status code 200", func(t *testing.T) {
+		expectedRes, _ := json.Marshal(response.Response{
+			Code:    code.OK,
+			Message: "signed out of every other device",
+		})
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Set("userId", 1)
+		c.Set("sessionKey", "current")
+		sessionService := mocks.NewUserSessionService(t)
+		sessionService.On("RevokeOtherSessions", 1, "current").Return(nil)
+		h := handler.New(&handler.HandlerConfig{
+			SessionService: sessionService,
+		})
+		c.Request, _ = http.NewRequest("DELETE", "/v1/users/sessions", nil)
+
+		h.RevokeOtherUserSessions(c)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, string(expectedRes), rec.Body.String())
+	})
+}
@@ -85,6 +85,7 @@ func (h *Handler) StepUp(c *gin.Context) {
 	}
 
 	userId := c.GetInt("userId")
+	req.SessionKey = c.GetString("sessionKey")
 
 	wallet, err := h.walletService.StepUp(userId, req)
 	if err != nil {
@@ -103,6 +104,16 @@ func (h *Handler) StepUp(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, errs.ErrExpiredToken) {
+			response.Error(c, http.StatusUnauthorized, code.TOKEN_EXPIRED, err.Error())
+			return
+		}
+
+		if errors.Is(err, errs.ErrRefreshTokenReused) {
+			response.Error(c, http.StatusUnauthorized, code.REFRESH_TOKEN_REUSED, err.Error())
+			return
+		}
+
 		response.Error(c, http.StatusInternalServerError, code.INTERNAL_SERVER_ERROR, errs.ErrInternalServerError.Error())
 		return
 	}
@@ -117,10 +128,16 @@ func (h *Handler) RequestWalletPinChange(c *gin.Context) {
 		response.ErrorValidator(c, http.StatusBadRequest, err)
 		return
 	}
+	request.IPAddress = c.ClientIP()
 
 	userID := c.GetInt("userId")
 	err = h.walletService.RequestPinChange(userID, &request)
 	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
+
 		if errors.Is(err, errs.ErrWalletDoesNotExist) {
 			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
 			return
@@ -145,11 +162,17 @@ func (h *Handler) CompleteChangeWalletPin(c *gin.Context) {
 		response.ErrorValidator(c, http.StatusBadRequest, err)
 		return
 	}
+	request.IPAddress = c.ClientIP()
 
 	userID := c.GetInt("userId")
 
 	err = h.walletService.CompletePinChange(userID, &request)
 	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
+
 		if errors.Is(err, errs.ErrVerificationCodeNotFound) {
 			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
 			return
@@ -186,11 +209,17 @@ func (h *Handler) CompleteResetWalletPin(c *gin.Context) {
 		response.ErrorValidator(c, http.StatusBadRequest, err)
 		return
 	}
+	request.IPAddress = c.ClientIP()
 
 	userID := c.GetInt("userId")
 
 	err = h.walletService.CompletePinReset(userID, &request)
 	if err != nil {
+		if errors.Is(err, errs.ErrTooManyAttempts) {
+			response.Error(c, http.StatusTooManyRequests, code.TOO_MANY_ATTEMPTS, err.Error())
+			return
+		}
+
 		if errors.Is(err, errs.ErrResetPinTokenNotFound) {
 			response.Error(c, http.StatusNotFound, code.NOT_FOUND, err.Error())
 			return
@@ -444,6 +444,29 @@ func TestRequestWalletPinChange(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "should return error with status code 429 when pin attempts are throttled",
+			input: input{
+				userID: userID,
+				request: &dto.ChangePinRequest{
+					CurrentPin: "102938",
+					NewPin:     NewPin,
+				},
+			},
+			beforeTest: func(ws *mocks.WalletService) {
+				ws.On("RequestPinChange", userID, &dto.ChangePinRequest{
+					CurrentPin: "102938",
+					NewPin:     NewPin,
+				}).Return(errRes.ErrTooManyAttempts)
+			},
+			expected: expected{
+				statusCode: http.StatusTooManyRequests,
+				response: response.Response{
+					Code:    code.TOO_MANY_ATTEMPTS,
+					Message: errRes.ErrTooManyAttempts.Error(),
+				},
+			},
+		},
 		{
 			description: "should return error with status code 400 when given wrong pin",
 			input: input{
@@ -597,6 +620,27 @@ func TestCompleteChangeWalletPin(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "should return error with status code 429 when verification attempts are throttled",
+			input: input{
+				userID: userID,
+				request: &dto.CompleteChangePinRequest{
+					VerificationCode: verificationCode,
+				},
+			},
+			beforeTest: func(ws *mocks.WalletService) {
+				ws.On("CompletePinChange", userID, &dto.CompleteChangePinRequest{
+					VerificationCode: verificationCode,
+				}).Return(errRes.ErrTooManyAttempts)
+			},
+			expected: expected{
+				statusCode: http.StatusTooManyRequests,
+				response: response.Response{
+					Code:    code.TOO_MANY_ATTEMPTS,
+					Message: errRes.ErrTooManyAttempts.Error(),
+				},
+			},
+		},
 		{
 			description: "should return error with status code 400 when verification code is incorrect",
 			input: input{
@@ -799,6 +843,29 @@ func TestCompleteResetWalletPin(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "should return error with status code 429 when reset attempts are throttled",
+			input: input{
+				userID: userID,
+				request: &dto.CompleteResetPinRequest{
+					Token:  token,
+					NewPin: newPin,
+				},
+			},
+			beforeTest: func(ws *mocks.WalletService) {
+				ws.On("CompletePinReset", userID, &dto.CompleteResetPinRequest{
+					Token:  token,
+					NewPin: newPin,
+				}).Return(errRes.ErrTooManyAttempts)
+			},
+			expected: expected{
+				statusCode: http.StatusTooManyRequests,
+				response: response.Response{
+					Code:    code.TOO_MANY_ATTEMPTS,
+					Message: errRes.ErrTooManyAttempts.Error(),
+				},
+			},
+		},
 		{
 			description: "should return error with status code 404 when token not found",
 			input: input{
@@ -6,10 +6,11 @@ type Claim struct {
 	UserId    int    `json:"userId"`
 	TokenType string `json:"tokenType"`
 	Level     int    `json:"level"`
-	jwt.RegisteredClaims
-}
-
-type GoogleClaim struct {
-	Email string `json:"email"`
+	Scope     string `json:"scope,omitempty"`
+	// TwoFactorAt is when the session last passed 2FA, as unix seconds.
+	TwoFactorAt int64 `json:"twoFactorAt,omitempty"`
+	// FamilyID groups the refresh tokens rotated from one sign in. It is the
+	// key of the session they belong to.
+	FamilyID string `json:"familyId,omitempty"`
 	jwt.RegisteredClaims
 }
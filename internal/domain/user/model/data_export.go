@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserDataExport is a requested archive of a user's personal data. It is
+// built in the background and kept until ExpiresAt.
+type UserDataExport struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"userId"`
+	Status       string     `json:"status"`
+	Archive      []byte     `json:"-"`
+	ClaimedUntil *time.Time `json:"-"`
+	CompletedAt  *time.Time `json:"completedAt"`
+	ExpiresAt    *time.Time `json:"expiresAt"`
+
+	gorm.Model `json:"-"`
+}
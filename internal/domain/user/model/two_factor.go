@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserTwoFactor holds a user's TOTP secret, sealed with the two-factor
+// keyring. It exists from enrolment on; 2FA is only enforced once EnabledAt
+// is set.
+type UserTwoFactor struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"userId"`
+	Secret       string     `json:"-"`
+	KeyVersion   int        `json:"-"`
+	LastUsedStep int64      `json:"-"`
+	EnabledAt    *time.Time `json:"enabledAt"`
+
+	gorm.Model `json:"-"`
+}
+
+type UserRecoveryCode struct {
+	ID       int        `json:"id"`
+	UserID   int        `json:"userId"`
+	CodeHash string     `json:"-"`
+	UsedAt   *time.Time `json:"usedAt"`
+
+	gorm.Model `json:"-"`
+}
@@ -2,9 +2,12 @@ package model
 
 import "gorm.io/gorm"
 
+// UsedEmail is an address UserID had before changing it, which can't be
+// registered again.
 type UsedEmail struct {
-	ID    uint
-	Email string
+	ID     uint
+	UserID int
+	Email  string
 
 	gorm.Model
 }
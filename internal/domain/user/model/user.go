@@ -1,15 +1,21 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type User struct {
-	ID       int          `json:"id"`
-	Email    string       `json:"email"`
-	Username string       `json:"username"`
-	Password string       `json:"-"`
-	Profile  *UserProfile `json:"profile,omitempty"`
+	ID              int          `json:"id"`
+	Email           string       `json:"email"`
+	Username        string       `json:"username"`
+	Password        string       `json:"-"`
+	EmailVerifiedAt *time.Time   `json:"emailVerifiedAt"`
+	Profile         *UserProfile `json:"profile,omitempty"`
+	// Identities is only set when signing up with a provider, so the link is
+	// created together with the user.
+	Identities []*UserIdentity `json:"-"`
 
 	gorm.Model `json:"-"`
 }
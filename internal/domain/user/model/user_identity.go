@@ -0,0 +1,16 @@
+package model
+
+import "gorm.io/gorm"
+
+// UserIdentity links a user to their account at an identity provider.
+// Subject is the provider's id for that account; Email is what the provider
+// reported when the link was made.
+type UserIdentity struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"userId"`
+	Provider string `json:"provider"`
+	Subject  string `json:"-"`
+	Email    string `json:"email"`
+
+	gorm.Model `json:"-"`
+}
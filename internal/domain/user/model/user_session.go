@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserSession is one signed in device. SessionKey is stored as the value of
+// every token key the session owns in Redis, so its tokens can be revoked
+// together.
+type UserSession struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"userId"`
+	SessionKey string     `json:"-"`
+	DeviceID   string     `json:"deviceId"`
+	UserAgent  string     `json:"userAgent"`
+	IPAddress  string     `json:"ipAddress"`
+	LastSeenAt time.Time  `json:"lastSeenAt"`
+	RevokedAt  *time.Time `json:"-"`
+
+	gorm.Model `json:"-"`
+}
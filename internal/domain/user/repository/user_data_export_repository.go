@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserDataExportRepository interface {
+	Create(export *model.UserDataExport) error
+	GetLatestByUserID(userId int) (*model.UserDataExport, error)
+	GetArchive(id int) ([]byte, error)
+	ClaimPending(now time.Time, lease time.Duration, limit int) ([]*model.UserDataExport, error)
+	MarkReady(id int, archive []byte, completedAt time.Time, expiresAt time.Time) error
+	MarkFailed(id int) error
+	DeleteExpired(now time.Time) error
+}
+
+type userDataExportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type UserDataExportRConfig struct {
+	DB *gorm.DB
+}
+
+func NewUserDataExportRepository(cfg *UserDataExportRConfig) UserDataExportRepository {
+	return &userDataExportRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *userDataExportRepositoryImpl) Create(export *model.UserDataExport) error {
+	return r.db.Create(export).Error
+}
+
+// GetLatestByUserID leaves out the archive itself, which is only loaded by
+// its own query when it is downloaded.
+func (r *userDataExportRepositoryImpl) GetLatestByUserID(userId int) (*model.UserDataExport, error) {
+	var export model.UserDataExport
+
+	err := r.db.Omit("archive").Where("user_id = ?", userId).Order("created_at desc").First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrDataExportNotFound
+		}
+
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+func (r *userDataExportRepositoryImpl) GetArchive(id int) ([]byte, error) {
+	var export model.UserDataExport
+
+	err := r.db.Select("archive").Where("id = ?", id).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrDataExportNotFound
+		}
+
+		return nil, err
+	}
+
+	return export.Archive, nil
+}
+
+// ClaimPending pushes claimed_until of up to limit pending exports past the
+// lease and returns them. An export whose worker died is picked up again
+// once its lease runs out.
+func (r *userDataExportRepositoryImpl) ClaimPending(now time.Time, lease time.Duration, limit int) ([]*model.UserDataExport, error) {
+	var exports []*model.UserDataExport
+
+	err := r.db.Raw(`
+		UPDATE user_data_exports SET claimed_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM user_data_exports
+			WHERE status = ? AND (claimed_until IS NULL OR claimed_until <= ?) AND deleted_at IS NULL
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, status, claimed_until, created_at`,
+		now.Add(lease), now, constant.DataExportStatusPending, now, limit,
+	).Scan(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (r *userDataExportRepositoryImpl) MarkReady(id int, archive []byte, completedAt time.Time, expiresAt time.Time) error {
+	return r.db.Model(&model.UserDataExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       constant.DataExportStatusReady,
+			"archive":      archive,
+			"completed_at": completedAt,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+func (r *userDataExportRepositoryImpl) MarkFailed(id int) error {
+	return r.db.Model(&model.UserDataExport{}).
+		Where("id = ?", id).
+		Update("status", constant.DataExportStatusFailed).Error
+}
+
+// DeleteExpired purges exports that can no longer be downloaded, along with
+// the personal data in their archives.
+func (r *userDataExportRepositoryImpl) DeleteExpired(now time.Time) error {
+	return r.db.Unscoped().Where("expires_at <= ?", now).Delete(&model.UserDataExport{}).Error
+}
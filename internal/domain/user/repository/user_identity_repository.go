@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserIdentityRepository interface {
+	GetByProviderSubject(provider string, subject string) (*model.UserIdentity, error)
+	GetByUserID(userId int) ([]*model.UserIdentity, error)
+	Create(identity *model.UserIdentity) error
+	Delete(userId int, provider string) error
+}
+
+type userIdentityRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type UserIdentityRConfig struct {
+	DB *gorm.DB
+}
+
+func NewUserIdentityRepository(cfg *UserIdentityRConfig) UserIdentityRepository {
+	return &userIdentityRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *userIdentityRepositoryImpl) GetByProviderSubject(provider string, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrIdentityNotFound
+		}
+
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+func (r *userIdentityRepositoryImpl) GetByUserID(userId int) ([]*model.UserIdentity, error) {
+	var identities []*model.UserIdentity
+	err := r.db.Where("user_id = ?", userId).Order("provider").Find(&identities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// Create links identity, unless the provider account is linked already or
+// the user already has an account at that provider.
+func (r *userIdentityRepositoryImpl) Create(identity *model.UserIdentity) error {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(identity)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errs.ErrIdentityAlreadyLinked
+	}
+
+	return nil
+}
+
+// Delete unlinks the user's account at provider for good, so it can be
+// linked again later.
+func (r *userIdentityRepositoryImpl) Delete(userId int, provider string) error {
+	res := r.db.Unscoped().Where("user_id = ? AND provider = ?", userId, provider).Delete(&model.UserIdentity{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errs.ErrIdentityNotFound
+	}
+
+	return nil
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"kedai/backend/be-kedai/config"
 	errs "kedai/backend/be-kedai/internal/common/error"
+	locationModel "kedai/backend/be-kedai/internal/domain/location/model"
+	notificationModel "kedai/backend/be-kedai/internal/domain/notification/model"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
 
-	"kedai/backend/be-kedai/internal/domain/user/cache"
 	"kedai/backend/be-kedai/internal/domain/user/model"
 	"kedai/backend/be-kedai/internal/utils/hash"
 	"math/rand"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -23,26 +26,26 @@ type UserRepository interface {
 	SignUp(user *model.User) (*model.User, error)
 	SignIn(user *model.User) (*model.User, error)
 	UpdateEmail(userId int, email string) (*model.User, error)
+	RevertEmail(userId int, email string) (*model.User, error)
+	MarkEmailVerified(userId int, email string) error
 	UpdateUsername(id int, username string) (*model.User, error)
 	UpdatePassword(id int, password string) (*model.User, error)
+	Anonymise(user *model.User) error
 }
 
 type userRepositoryImpl struct {
 	db              *gorm.DB
-	userCache       cache.UserCache
 	userProfileRepo UserProfileRepository
 }
 
 type UserRConfig struct {
 	DB              *gorm.DB
-	UserCache       cache.UserCache
 	UserProfileRepo UserProfileRepository
 }
 
 func NewUserRepository(cfg *UserRConfig) UserRepository {
 	return &userRepositoryImpl{
 		db:              cfg.DB,
-		userCache:       cfg.UserCache,
 		userProfileRepo: cfg.UserProfileRepo,
 	}
 }
@@ -136,6 +139,8 @@ func (r *userRepositoryImpl) SignIn(user *model.User) (*model.User, error) {
 	return user, nil
 }
 
+// UpdateEmail switches the user to email, which they have just confirmed,
+// and retires the previous address.
 func (r *userRepositoryImpl) UpdateEmail(userId int, email string) (*model.User, error) {
 	err := r.db.Where("email = ?", email).First(&model.UsedEmail{}).Error
 	if err == nil {
@@ -145,29 +150,26 @@ func (r *userRepositoryImpl) UpdateEmail(userId int, email string) (*model.User,
 		return nil, err
 	}
 
-	var user model.User
-	err = r.db.Where("id = ?", userId).First(&user).Error
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		return r.replaceEmail(tx, userId, email)
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(&model.User{}).Where("id = ?", userId).Update("email", email).Error; err != nil {
-			return err
-		}
-
-		if err := tx.Create(
-			&model.UsedEmail{
-				Email: user.Email,
-			}).Error; err != nil {
-			return err
-		}
+	return &model.User{ID: userId, Email: email}, nil
+}
 
-		if err := r.userCache.DeleteAllByID(userId); err != nil {
+// RevertEmail gives the user back email, the address they had before their
+// last email change, and retires the one it was changed to.
+func (r *userRepositoryImpl) RevertEmail(userId int, email string) (*model.User, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("email = ?", email).Delete(&model.UsedEmail{}).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return r.replaceEmail(tx, userId, email)
 	})
 
 	if err != nil {
@@ -177,6 +179,47 @@ func (r *userRepositoryImpl) UpdateEmail(userId int, email string) (*model.User,
 	return &model.User{ID: userId, Email: email}, nil
 }
 
+// replaceEmail sets the verified email of the user, keeps their current one
+// from being registered again and revokes their sessions. Their cached tokens
+// are left for the caller to drop once the transaction commits.
+func (r *userRepositoryImpl) replaceEmail(tx *gorm.DB, userId int, email string) error {
+	var user model.User
+	if err := tx.Where("id = ?", userId).First(&user).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+		"email":             email,
+		"email_verified_at": time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Create(
+		&model.UsedEmail{
+			UserID: userId,
+			Email:  user.Email,
+		}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&model.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userId).Update("revoked_at", time.Now()).Error
+}
+
+// MarkEmailVerified records that the user confirmed email, as long as it is
+// still their address.
+func (r *userRepositoryImpl) MarkEmailVerified(userId int, email string) error {
+	res := r.db.Model(&model.User{}).Where("id = ? AND email = ?", userId, email).Update("email_verified_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errs.ErrUserDoesNotExist
+	}
+
+	return nil
+}
+
 func (r *userRepositoryImpl) UpdateUsername(userId int, username string) (*model.User, error) {
 	res := r.db.Model(&model.User{}).Where("id = ?", userId).Clauses(clause.OnConflict{DoNothing: true}).Update("username", username)
 	if res.Error != nil {
@@ -212,11 +255,7 @@ func (r *userRepositoryImpl) UpdatePassword(userId int, password string) (*model
 			return err
 		}
 
-		if err := r.userCache.DeleteAllByID(userId); err != nil {
-			return err
-		}
-
-		return nil
+		return tx.Model(&model.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userId).Update("revoked_at", time.Now()).Error
 	})
 
 	if err != nil {
@@ -225,3 +264,75 @@ func (r *userRepositoryImpl) UpdatePassword(userId int, password string) (*model
 
 	return &model.User{ID: userId}, nil
 }
+
+// Anonymise replaces the user's credentials with those of user and erases
+// what identifies them: their profile, addresses, payment cards, shop
+// memberships and invitations, the emails they used before and everything
+// they could sign in or be reached with. Orders, reviews and
+// wallet history stay, now pointing at the pseudonymous user. Addresses are
+// scrubbed and soft deleted rather than removed, since orders refer to them.
+func (r *userRepositoryImpl) Anonymise(user *model.User) error {
+	hashedPw, _ := hash.HashAndSalt(user.Password)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.User
+		if err := tx.Where("id = ?", user.ID).First(&current).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("user_id = ? OR lower(email) = lower(?)", user.ID, current.Email).Delete(&shopModel.ShopMember{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"username":          user.Username,
+			"email":             user.Email,
+			"password":          hashedPw,
+			"email_verified_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.UserProfile{}).Where("user_id = ?", user.ID).Updates(map[string]interface{}{
+			"name":               nil,
+			"phone_number":       nil,
+			"dob":                nil,
+			"gender":             nil,
+			"photo_url":          nil,
+			"default_address_id": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&locationModel.UserAddress{}).Where("user_id = ?", user.ID).Updates(map[string]interface{}{
+			"name":         "",
+			"phone_number": "",
+			"street":       "",
+			"details":      "",
+			"deleted_at":   time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		for _, personal := range []interface{}{
+			&model.SealabsPay{},
+			&model.UserSession{},
+			&model.UserIdentity{},
+			&model.UserTwoFactor{},
+			&model.UserRecoveryCode{},
+			&model.CartItem{},
+			&model.UserWishlist{},
+			&model.UserDataExport{},
+			&model.UsedEmail{},
+			&notificationModel.Notification{},
+			&notificationModel.NotificationPreference{},
+			&notificationModel.PushDevice{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(personal).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
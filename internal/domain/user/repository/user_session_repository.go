@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserSessionRepository interface {
+	Create(session *model.UserSession) error
+	GetActiveByUserID(userId int, activeSince time.Time) ([]*model.UserSession, error)
+	GetByIDAndUserID(id int, userId int) (*model.UserSession, error)
+	HasDevice(userId int, deviceId string) (bool, error)
+	HasAny(userId int) (bool, error)
+	Touch(sessionKey string) error
+	Revoke(userId int, sessionKey string) error
+	RevokeAllExcept(userId int, sessionKey string) error
+}
+
+type userSessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type UserSessionRConfig struct {
+	DB *gorm.DB
+}
+
+func NewUserSessionRepository(cfg *UserSessionRConfig) UserSessionRepository {
+	return &userSessionRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *userSessionRepositoryImpl) Create(session *model.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+// GetActiveByUserID lists sessions that are not revoked and were used after
+// activeSince; older ones have no refresh token left.
+func (r *userSessionRepositoryImpl) GetActiveByUserID(userId int, activeSince time.Time) ([]*model.UserSession, error) {
+	var sessions []*model.UserSession
+	err := r.db.
+		Where("user_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userId, activeSince).
+		Order("last_seen_at desc").
+		Find(&sessions).Error
+
+	return sessions, err
+}
+
+func (r *userSessionRepositoryImpl) GetByIDAndUserID(id int, userId int) (*model.UserSession, error) {
+	var session model.UserSession
+	err := r.db.Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userId).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrSessionNotFound
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *userSessionRepositoryImpl) HasDevice(userId int, deviceId string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.UserSession{}).Where("user_id = ? AND device_id = ?", userId, deviceId).Limit(1).Count(&count).Error
+
+	return count > 0, err
+}
+
+func (r *userSessionRepositoryImpl) HasAny(userId int) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.UserSession{}).Where("user_id = ?", userId).Limit(1).Count(&count).Error
+
+	return count > 0, err
+}
+
+// Touch records that the session is in use, at most once per
+// SessionTouchInterval.
+func (r *userSessionRepositoryImpl) Touch(sessionKey string) error {
+	now := time.Now()
+
+	return r.db.Model(&model.UserSession{}).
+		Where("session_key = ? AND last_seen_at < ?", sessionKey, now.Add(-constant.SessionTouchInterval)).
+		Update("last_seen_at", now).Error
+}
+
+func (r *userSessionRepositoryImpl) Revoke(userId int, sessionKey string) error {
+	return r.db.Model(&model.UserSession{}).
+		Where("user_id = ? AND session_key = ? AND revoked_at IS NULL", userId, sessionKey).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *userSessionRepositoryImpl) RevokeAllExcept(userId int, sessionKey string) error {
+	return r.db.Model(&model.UserSession{}).
+		Where("user_id = ? AND session_key <> ? AND revoked_at IS NULL", userId, sessionKey).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserTwoFactorRepository interface {
+	GetByUserID(userId int) (*model.UserTwoFactor, error)
+	Save(twoFactor *model.UserTwoFactor) error
+	Delete(userId int) error
+	CountUnusedRecoveryCodes(userId int) (int, error)
+	ReplaceRecoveryCodes(userId int, codes []*model.UserRecoveryCode) error
+	UseRecoveryCode(userId int, codeHash string) error
+	UseTimeStep(userId int, step int64) error
+}
+
+type userTwoFactorRepositoryImpl struct {
+	db *gorm.DB
+}
+
+type UserTwoFactorRConfig struct {
+	DB *gorm.DB
+}
+
+func NewUserTwoFactorRepository(cfg *UserTwoFactorRConfig) UserTwoFactorRepository {
+	return &userTwoFactorRepositoryImpl{
+		db: cfg.DB,
+	}
+}
+
+func (r *userTwoFactorRepositoryImpl) GetByUserID(userId int) (*model.UserTwoFactor, error) {
+	var twoFactor model.UserTwoFactor
+	err := r.db.Where("user_id = ?", userId).First(&twoFactor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrTwoFactorNotEnrolled
+		}
+
+		return nil, err
+	}
+
+	return &twoFactor, nil
+}
+
+func (r *userTwoFactorRepositoryImpl) Save(twoFactor *model.UserTwoFactor) error {
+	return r.db.Save(twoFactor).Error
+}
+
+// Delete removes the secret and every recovery code, so enrolling again
+// starts from scratch.
+func (r *userTwoFactorRepositoryImpl) Delete(userId int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("user_id = ?", userId).Delete(&model.UserRecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("user_id = ?", userId).Delete(&model.UserTwoFactor{}).Error
+	})
+}
+
+func (r *userTwoFactorRepositoryImpl) CountUnusedRecoveryCodes(userId int) (int, error) {
+	var count int64
+	err := r.db.Model(&model.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userId).Count(&count).Error
+
+	return int(count), err
+}
+
+func (r *userTwoFactorRepositoryImpl) ReplaceRecoveryCodes(userId int, codes []*model.UserRecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("user_id = ?", userId).Delete(&model.UserRecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(codes).Error
+	})
+}
+
+// UseRecoveryCode marks the code used in the same statement that checks it
+// is unused, so two requests cannot both spend it.
+func (r *userTwoFactorRepositoryImpl) UseRecoveryCode(userId int, codeHash string) error {
+	res := r.db.Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return errs.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// UseTimeStep records step as the last one a code was accepted for, only if
+// it is newer than the recorded one, so two requests cannot both spend the
+// same code.
+func (r *userTwoFactorRepositoryImpl) UseTimeStep(userId int, step int64) error {
+	res := r.db.Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userId, step).
+		Update("last_used_step", step)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return errs.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 type UserWishlistRepository interface {
 	GetUserWishlists(req dto.GetUserWishlistsRequest) ([]*dto.GetUserWishlistsResponse, int64, int, error)
 	GetUserWishlist(userWishlist *model.UserWishlist) (*model.UserWishlist, error)
+	GetAllByUserID(userId int) ([]*model.UserWishlist, error)
 	AddUserWishlist(userWishlist *model.UserWishlist) (*model.UserWishlist, error)
 	RemoveUserWishlist(userWishlist *model.UserWishlist) error
 }
@@ -130,6 +131,17 @@ func (r *userWishlistRepositoryImpl) GetUserWishlist(userWishlist *model.UserWis
 	return &res, nil
 }
 
+func (r *userWishlistRepositoryImpl) GetAllByUserID(userId int) ([]*model.UserWishlist, error) {
+	var wishlists []*model.UserWishlist
+
+	err := r.db.Where("user_id = ?", userId).Preload("Product").Order("created_at desc").Find(&wishlists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return wishlists, nil
+}
+
 func (r *userWishlistRepositoryImpl) AddUserWishlist(userWishlist *model.UserWishlist) (*model.UserWishlist, error) {
 	err := r.db.Create(userWishlist).Error
 	if err != nil {
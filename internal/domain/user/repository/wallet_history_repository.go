@@ -16,6 +16,7 @@ type WalletHistoryRepository interface {
 	CreateMultiple(*gorm.DB, []*model.WalletHistory) error
 	GetHistoryDetailById(ref string, wallet *model.Wallet) (*model.WalletHistory, error)
 	GetWalletHistoryById(req dto.WalletHistoryRequest, id int) ([]*model.WalletHistory, int64, int, error)
+	GetAllByWalletID(walletId int) ([]*model.WalletHistory, error)
 	GetShopFinanceReleased(shopId int) (*shopDto.ShopFinanceReleased, error)
 }
 
@@ -71,6 +72,17 @@ func (r *walletHistoryRepoImpl) GetWalletHistoryById(req dto.WalletHistoryReques
 	return histories, totalRows, totalPage, nil
 }
 
+func (r *walletHistoryRepoImpl) GetAllByWalletID(walletId int) ([]*model.WalletHistory, error) {
+	var histories []*model.WalletHistory
+
+	err := r.db.Where("wallet_id = ?", walletId).Order("created_at desc").Find(&histories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
 func (r *walletHistoryRepoImpl) GetShopFinanceReleased(shopId int) (*shopDto.ShopFinanceReleased, error) {
 	var (
 		shopFinanceReleased = &shopDto.ShopFinanceReleased{}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/internal/utils/attempt"
+	"log"
+)
+
+// checkAttempts returns ErrTooManyAttempts while any of keys is throttled,
+// and otherwise counts the attempt against all of them until it is reset or
+// released. Limiting is skipped when no limiter is configured.
+func checkAttempts(limiter attempt.Limiter, keys ...string) error {
+	if limiter == nil {
+		return nil
+	}
+
+	return limiter.Check(keys...)
+}
+
+// recordFailedAttempt counts a failure against accountKey and otherKeys and
+// alerts the user once accountKey gets locked out. userId is 0 when the
+// attempt named no existing account.
+func recordFailedAttempt(limiter attempt.Limiter, sessionService UserSessionService, userId int, ipAddress string, accountKey string, otherKeys ...string) {
+	if limiter == nil {
+		return
+	}
+
+	locked, err := limiter.Fail(accountKey)
+	if err != nil {
+		log.Println("failed to record failed attempt for", accountKey, ":", err)
+	}
+
+	for _, key := range otherKeys {
+		if _, err := limiter.Fail(key); err != nil {
+			log.Println("failed to record failed attempt for", key, ":", err)
+		}
+	}
+
+	if locked && userId != 0 && sessionService != nil {
+		sessionService.NotifyLockout(userId, ipAddress)
+	}
+}
+
+// resetAttempts forgets the attempts against accountKey once the credential
+// was verified, and releases the attempt counted against otherKeys.
+func resetAttempts(limiter attempt.Limiter, accountKey string, otherKeys ...string) {
+	if limiter == nil {
+		return
+	}
+
+	if err := limiter.Reset(accountKey); err != nil {
+		log.Println("failed to reset failed attempts for", accountKey, ":", err)
+	}
+
+	releaseAttempts(limiter, otherKeys...)
+}
+
+// releaseAttempts takes back the attempt counted against keys when it turned
+// out to be no failure.
+func releaseAttempts(limiter attempt.Limiter, keys ...string) {
+	if limiter == nil {
+		return
+	}
+
+	for _, key := range keys {
+		if err := limiter.Release(key); err != nil {
+			log.Println("failed to release attempt for", key, ":", err)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/cache"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/repository"
+	"kedai/backend/be-kedai/internal/utils/attempt"
+	"kedai/backend/be-kedai/internal/utils/encrypt"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/random"
+	"kedai/backend/be-kedai/internal/utils/totp"
+	"time"
+)
+
+type TwoFactorService interface {
+	GetStatus(userId int) (*dto.TwoFactorStatus, error)
+	IsEnabled(userId int) (bool, error)
+	Enrol(userId int) (*dto.TwoFactorEnrolment, error)
+	Enable(userId int, req *dto.TwoFactorCodeRequest) (*dto.TwoFactorRecoveryCodes, error)
+	Disable(userId int, req *dto.TwoFactorCodeRequest) error
+	RegenerateRecoveryCodes(userId int, req *dto.TwoFactorCodeRequest) (*dto.TwoFactorRecoveryCodes, error)
+	ChallengeIfEnabled(userId int) (*dto.Token, error)
+	CompleteChallenge(req *dto.TwoFactorLoginRequest) (*dto.Token, error)
+	Verify(userId int, sessionKey string, req *dto.TwoFactorCodeRequest) (*dto.Token, error)
+}
+
+type twoFactorServiceImpl struct {
+	twoFactorRepo  repository.UserTwoFactorRepository
+	userRepo       repository.UserRepository
+	twoFactorCache cache.TwoFactorCache
+	sessionService UserSessionService
+	randomUtils    random.RandomUtils
+	keyring        *encrypt.Keyring
+	limiter        attempt.Limiter
+}
+
+type TwoFactorSConfig struct {
+	TwoFactorRepo  repository.UserTwoFactorRepository
+	UserRepo       repository.UserRepository
+	TwoFactorCache cache.TwoFactorCache
+	SessionService UserSessionService
+	RandomUtils    random.RandomUtils
+	Keyring        *encrypt.Keyring
+	Limiter        attempt.Limiter
+}
+
+func NewTwoFactorService(cfg *TwoFactorSConfig) TwoFactorService {
+	return &twoFactorServiceImpl{
+		twoFactorRepo:  cfg.TwoFactorRepo,
+		userRepo:       cfg.UserRepo,
+		twoFactorCache: cfg.TwoFactorCache,
+		sessionService: cfg.SessionService,
+		randomUtils:    cfg.RandomUtils,
+		keyring:        cfg.Keyring,
+		limiter:        cfg.Limiter,
+	}
+}
+
+func (s *twoFactorServiceImpl) GetStatus(userId int) (*dto.TwoFactorStatus, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(userId)
+	if err == errs.ErrTwoFactorNotEnrolled {
+		return &dto.TwoFactorStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor.EnabledAt == nil {
+		return &dto.TwoFactorStatus{}, nil
+	}
+
+	remaining, err := s.twoFactorRepo.CountUnusedRecoveryCodes(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorStatus{IsEnabled: true, RecoveryCodeRemaining: remaining}, nil
+}
+
+func (s *twoFactorServiceImpl) IsEnabled(userId int) (bool, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(userId)
+	if err == errs.ErrTwoFactorNotEnrolled {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return twoFactor.EnabledAt != nil, nil
+}
+
+// Enrol starts setting up an authenticator app. A previous unfinished
+// enrolment is replaced; 2FA is not enforced until Enable succeeds.
+func (s *twoFactorServiceImpl) Enrol(userId int) (*dto.TwoFactorEnrolment, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(userId)
+	if err != nil && err != errs.ErrTwoFactorNotEnrolled {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		return nil, errs.ErrTwoFactorAlreadyEnabled
+	}
+	if twoFactor == nil {
+		twoFactor = &model.UserTwoFactor{UserID: userId}
+	}
+
+	user, err := s.userRepo.GetByID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor.Secret, twoFactor.KeyVersion, err = s.keyring.Wrap([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.LastUsedStep = 0
+
+	err = s.twoFactorRepo.Save(twoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorEnrolment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(constant.TwoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// Enable turns 2FA on once the user proves their app produces valid codes,
+// and returns the recovery codes, which are only ever shown here.
+func (s *twoFactorServiceImpl) Enable(userId int, req *dto.TwoFactorCodeRequest) (*dto.TwoFactorRecoveryCodes, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.EnabledAt != nil {
+		return nil, errs.ErrTwoFactorAlreadyEnabled
+	}
+
+	err = s.limitCode(userId, req.IPAddress, func() error {
+		return s.verifyTOTP(twoFactor, req.Code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	twoFactor.EnabledAt = &now
+	err = s.twoFactorRepo.Save(twoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(userId)
+}
+
+func (s *twoFactorServiceImpl) Disable(userId int, req *dto.TwoFactorCodeRequest) error {
+	twoFactor, err := s.getEnabled(userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.limitCode(userId, req.IPAddress, func() error {
+		return s.verify(twoFactor, req.Code)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.twoFactorRepo.Delete(userId)
+}
+
+func (s *twoFactorServiceImpl) RegenerateRecoveryCodes(userId int, req *dto.TwoFactorCodeRequest) (*dto.TwoFactorRecoveryCodes, error) {
+	twoFactor, err := s.getEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.limitCode(userId, req.IPAddress, func() error {
+		return s.verifyTOTP(twoFactor, req.Code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(userId)
+}
+
+// ChallengeIfEnabled is called once the first factor checked out. It returns
+// nil when the user has no 2FA, otherwise a token to complete sign in with.
+func (s *twoFactorServiceImpl) ChallengeIfEnabled(userId int) (*dto.Token, error) {
+	enabled, err := s.IsEnabled(userId)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	challenge := s.randomUtils.GenerateSecureUniqueToken()
+	err = s.twoFactorCache.StoreChallenge(challenge, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Token{TwoFactorToken: challenge}, nil
+}
+
+func (s *twoFactorServiceImpl) CompleteChallenge(req *dto.TwoFactorLoginRequest) (*dto.Token, error) {
+	userId, err := s.twoFactorCache.FindChallenge(req.TwoFactorToken)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.getEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.limitCode(userId, req.Device.IPAddress, func() error {
+		return s.verify(twoFactor, req.Code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.twoFactorCache.DeleteChallenge(req.TwoFactorToken)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedAt := time.Now()
+
+	return s.sessionService.StartSession(userId, &req.Device, &verifiedAt)
+}
+
+// Verify re-checks 2FA for a signed in user and issues a token for the same
+// session that passes RequireFreshTwoFactor.
+func (s *twoFactorServiceImpl) Verify(userId int, sessionKey string, req *dto.TwoFactorCodeRequest) (*dto.Token, error) {
+	twoFactor, err := s.getEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.limitCode(userId, req.IPAddress, func() error {
+		return s.verify(twoFactor, req.Code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedAt := time.Now()
+
+	return s.sessionService.IssueToken(userId, sessionKey, &verifiedAt)
+}
+
+func (s *twoFactorServiceImpl) getEnabled(userId int) (*model.UserTwoFactor, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(userId)
+	if err == errs.ErrTwoFactorNotEnrolled {
+		return nil, errs.ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor.EnabledAt == nil {
+		return nil, errs.ErrTwoFactorNotEnabled
+	}
+
+	return twoFactor, nil
+}
+
+// limitCode runs check under the two-factor attempt limits of the user and
+// the client IP, so codes cannot be guessed however many challenges or
+// requests are made, nor across many accounts from one IP.
+func (s *twoFactorServiceImpl) limitCode(userId int, ipAddress string, check func() error) error {
+	accountKey := attempt.AccountKey(constant.AttemptScopeTwoFactor, userId)
+	ipKey := attempt.IPKey(constant.AttemptScopeTwoFactor, ipAddress)
+	err := checkAttempts(s.limiter, accountKey, ipKey)
+	if err != nil {
+		return err
+	}
+
+	err = check()
+	if errors.Is(err, errs.ErrInvalidTwoFactorCode) {
+		recordFailedAttempt(s.limiter, s.sessionService, userId, ipAddress, accountKey, ipKey)
+		return err
+	}
+	if err != nil {
+		releaseAttempts(s.limiter, accountKey, ipKey)
+		return err
+	}
+	resetAttempts(s.limiter, accountKey, ipKey)
+
+	return nil
+}
+
+// verify accepts a code from the authenticator app or, failing that, an
+// unused recovery code.
+func (s *twoFactorServiceImpl) verify(twoFactor *model.UserTwoFactor, code string) error {
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(twoFactor, code)
+	}
+
+	return s.twoFactorRepo.UseRecoveryCode(twoFactor.UserID, hash.HashSHA256(totp.NormalizeRecoveryCode(code)))
+}
+
+// verifyTOTP checks code against the secret and records its time step so the
+// same code cannot be used twice, even by requests racing each other.
+func (s *twoFactorServiceImpl) verifyTOTP(twoFactor *model.UserTwoFactor, code string) error {
+	secret, err := s.keyring.Unwrap(twoFactor.Secret, twoFactor.KeyVersion)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(string(secret), code, time.Now(), twoFactor.LastUsedStep)
+	if !ok {
+		return errs.ErrInvalidTwoFactorCode
+	}
+
+	err = s.twoFactorRepo.UseTimeStep(twoFactor.UserID, step)
+	if err != nil {
+		return err
+	}
+	twoFactor.LastUsedStep = step
+
+	return nil
+}
+
+func (s *twoFactorServiceImpl) replaceRecoveryCodes(userId int) (*dto.TwoFactorRecoveryCodes, error) {
+	codes, err := totp.GenerateRecoveryCodes(constant.TwoFactorRecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []*model.UserRecoveryCode
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, &model.UserRecoveryCode{
+			UserID:   userId,
+			CodeHash: hash.HashSHA256(totp.NormalizeRecoveryCode(code)),
+		})
+	}
+
+	err = s.twoFactorRepo.ReplaceRecoveryCodes(userId, recoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorRecoveryCodes{RecoveryCodes: codes}, nil
+}
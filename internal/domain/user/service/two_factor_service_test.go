@@ -0,0 +1,462 @@
+package service_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/service"
+	"kedai/backend/be-kedai/internal/utils/encrypt"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/totp"
+	mocks "kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTwoFactorKeyring(t *testing.T) *encrypt.Keyring {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	assert.NoError(t, err)
+
+	keyring, err := encrypt.NewKeyring([]string{"1:" + base64.StdEncoding.EncodeToString(key)}, "")
+	assert.NoError(t, err)
+
+	return keyring
+}
+
+func newUserTwoFactor(t *testing.T, keyring *encrypt.Keyring, enabled bool) (*model.UserTwoFactor, string) {
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+
+	wrapped, version, err := keyring.Wrap([]byte(secret))
+	assert.NoError(t, err)
+
+	twoFactor := &model.UserTwoFactor{UserID: 1, Secret: wrapped, KeyVersion: version}
+	if enabled {
+		enabledAt := time.Now()
+		twoFactor.EnabledAt = &enabledAt
+	}
+
+	return twoFactor, secret
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	code, err := totp.Code(secret, step)
+	assert.NoError(t, err)
+
+	return code
+}
+
+func TestTwoFactorGetStatus(t *testing.T) {
+	enabledAt := time.Now()
+
+	type input struct {
+		twoFactor *model.UserTwoFactor
+		err       error
+		remaining int
+	}
+	type expected struct {
+		data *dto.TwoFactorStatus
+		err  error
+	}
+	type cases struct {
+		description string
+		input
+		expected
+	}
+
+	for _, tc := range []cases{
+		{
+			description: "should return disabled status when user never enrolled",
+			input: input{
+				err: errs.ErrTwoFactorNotEnrolled,
+			},
+			expected: expected{
+				data: &dto.TwoFactorStatus{},
+			},
+		},
+		{
+			description: "should return disabled status when enrolment is not confirmed",
+			input: input{
+				twoFactor: &model.UserTwoFactor{UserID: 1},
+			},
+			expected: expected{
+				data: &dto.TwoFactorStatus{},
+			},
+		},
+		{
+			description: "should return enabled status with remaining recovery codes",
+			input: input{
+				twoFactor: &model.UserTwoFactor{UserID: 1, EnabledAt: &enabledAt},
+				remaining: 8,
+			},
+			expected: expected{
+				data: &dto.TwoFactorStatus{IsEnabled: true, RecoveryCodeRemaining: 8},
+			},
+		},
+		{
+			description: "should return error when failed to get two factor",
+			input: input{
+				err: errs.ErrInternalServerError,
+			},
+			expected: expected{
+				err: errs.ErrInternalServerError,
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			mockRepo := mocks.NewUserTwoFactorRepository(t)
+			mockRepo.On("GetByUserID", 1).Return(tc.input.twoFactor, tc.input.err)
+			if tc.input.twoFactor != nil && tc.input.twoFactor.EnabledAt != nil {
+				mockRepo.On("CountUnusedRecoveryCodes", 1).Return(tc.input.remaining, nil)
+			}
+			twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+				TwoFactorRepo: mockRepo,
+			})
+
+			result, err := twoFactorService.GetStatus(1)
+
+			assert.Equal(t, tc.expected.data, result)
+			assert.Equal(t, tc.expected.err, err)
+		})
+	}
+}
+
+func TestEnrolTwoFactor(t *testing.T) {
+	t.Run("should return provisioning uri when user is not enrolled", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(nil, errs.ErrTwoFactorNotEnrolled)
+		mockUserRepo.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@mail.com"}, nil)
+		mockRepo.On("Save", mock.AnythingOfType("*model.UserTwoFactor")).Return(nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+			UserRepo:      mockUserRepo,
+			Keyring:       keyring,
+		})
+
+		result, err := twoFactorService.Enrol(1)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Secret)
+		assert.Contains(t, result.ProvisioningURI, "secret="+result.Secret)
+		saved := mockRepo.Calls[1].Arguments.Get(0).(*model.UserTwoFactor)
+		unwrapped, err := keyring.Unwrap(saved.Secret, saved.KeyVersion)
+		assert.NoError(t, err)
+		assert.Equal(t, result.Secret, string(unwrapped))
+	})
+
+	t.Run("should return error when two factor is already enabled", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, _ := newUserTwoFactor(t, keyring, true)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+			Keyring:       keyring,
+		})
+
+		result, err := twoFactorService.Enrol(1)
+
+		assert.Nil(t, result)
+		assert.Equal(t, errs.ErrTwoFactorAlreadyEnabled, err)
+	})
+}
+
+func TestEnableTwoFactor(t *testing.T) {
+	t.Run("should enable two factor and return recovery codes when code is valid", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, secret := newUserTwoFactor(t, keyring, false)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		mockRepo.On("UseTimeStep", 1, mock.AnythingOfType("int64")).Return(nil)
+		mockRepo.On("Save", twoFactor).Return(nil)
+		mockRepo.On("ReplaceRecoveryCodes", 1, mock.AnythingOfType("[]*model.UserRecoveryCode")).Return(nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+			Keyring:       keyring,
+		})
+
+		result, err := twoFactorService.Enable(1, &dto.TwoFactorCodeRequest{Code: totpCode(t, secret, totp.Step(time.Now()))})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.RecoveryCodes, 10)
+		assert.NotNil(t, twoFactor.EnabledAt)
+	})
+
+	t.Run("should return error when code is invalid", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, _ := newUserTwoFactor(t, keyring, false)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+			Keyring:       keyring,
+		})
+
+		result, err := twoFactorService.Enable(1, &dto.TwoFactorCodeRequest{Code: "000000x"})
+
+		assert.Nil(t, result)
+		assert.Equal(t, errs.ErrInvalidTwoFactorCode, err)
+		assert.Nil(t, twoFactor.EnabledAt)
+	})
+
+	t.Run("should return error when user is not enrolled", func(t *testing.T) {
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(nil, errs.ErrTwoFactorNotEnrolled)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+		})
+
+		result, err := twoFactorService.Enable(1, &dto.TwoFactorCodeRequest{Code: "123456"})
+
+		assert.Nil(t, result)
+		assert.Equal(t, errs.ErrTwoFactorNotEnrolled, err)
+	})
+}
+
+func TestChallengeIfEnabled(t *testing.T) {
+	t.Run("should return nil when two factor is not enabled", func(t *testing.T) {
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(nil, errs.ErrTwoFactorNotEnrolled)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+		})
+
+		result, err := twoFactorService.ChallengeIfEnabled(1)
+
+		assert.Nil(t, result)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return challenge token when two factor is enabled", func(t *testing.T) {
+		enabledAt := time.Now()
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockCache := mocks.NewTwoFactorCache(t)
+		mockRandom := mocks.NewRandomUtils(t)
+		mockRepo.On("GetByUserID", 1).Return(&model.UserTwoFactor{UserID: 1, EnabledAt: &enabledAt}, nil)
+		mockRandom.On("GenerateSecureUniqueToken").Return("challenge")
+		mockCache.On("StoreChallenge", "challenge", 1).Return(nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo:  mockRepo,
+			TwoFactorCache: mockCache,
+			RandomUtils:    mockRandom,
+		})
+
+		result, err := twoFactorService.ChallengeIfEnabled(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.Token{TwoFactorToken: "challenge"}, result)
+	})
+}
+
+func TestCompleteTwoFactorChallenge(t *testing.T) {
+	t.Run("should return token when totp code is valid", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, secret := newUserTwoFactor(t, keyring, true)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockCache := mocks.NewTwoFactorCache(t)
+		mockSessionService := mocks.NewUserSessionService(t)
+		device := dto.SessionDevice{DeviceID: "device", UserAgent: "Firefox", IPAddress: "127.0.0.1"}
+		token := &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
+		mockCache.On("FindChallenge", "challenge").Return(1, nil)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		mockRepo.On("UseTimeStep", 1, mock.AnythingOfType("int64")).Return(nil)
+		mockCache.On("DeleteChallenge", "challenge").Return(nil)
+		mockSessionService.On("StartSession", 1, &device, mock.AnythingOfType("*time.Time")).Return(token, nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo:  mockRepo,
+			TwoFactorCache: mockCache,
+			SessionService: mockSessionService,
+			Keyring:        keyring,
+		})
+
+		result, err := twoFactorService.CompleteChallenge(&dto.TwoFactorLoginRequest{
+			TwoFactorToken: "challenge",
+			Code:           totpCode(t, secret, totp.Step(time.Now())),
+			Device:         device,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, token, result)
+		assert.Equal(t, totp.Step(time.Now()), twoFactor.LastUsedStep)
+	})
+
+	t.Run("should return error when totp code was already used", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, secret := newUserTwoFactor(t, keyring, true)
+		step := totp.Step(time.Now())
+		twoFactor.LastUsedStep = step
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockCache := mocks.NewTwoFactorCache(t)
+		mockCache.On("FindChallenge", "challenge").Return(1, nil)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo:  mockRepo,
+			TwoFactorCache: mockCache,
+			Keyring:        keyring,
+		})
+
+		result, err := twoFactorService.CompleteChallenge(&dto.TwoFactorLoginRequest{
+			TwoFactorToken: "challenge",
+			Code:           totpCode(t, secret, step),
+		})
+
+		assert.Nil(t, result)
+		assert.Equal(t, errs.ErrInvalidTwoFactorCode, err)
+	})
+
+	t.Run("should return error when a concurrent request used the code first", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, secret := newUserTwoFactor(t, keyring, true)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockCache := mocks.NewTwoFactorCache(t)
+		mockCache.On("FindChallenge", "challenge").Return(1, nil)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		mockRepo.On("UseTimeStep", 1, mock.AnythingOfType("int64")).Return(errs.ErrInvalidTwoFactorCode)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo:  mockRepo,
+			TwoFactorCache: mockCache,
+			Keyring:        keyring,
+		})
+
+		result, err := twoFactorService.CompleteChallenge(&dto.TwoFactorLoginRequest{
+			TwoFactorToken: "challenge",
+			Code:           totpCode(t, secret, totp.Step(time.Now())),
+		})
+
+		assert.Nil(t, result)
+		assert.Equal(t, errs.ErrInvalidTwoFactorCode, err)
+	})
+
+	t.Run("should return error when challenge is invalid", func(t *testing.T) {
+		mockCache := mocks.NewTwoFactorCache(t)
+		mockCache.On("FindChallenge", "challenge").Return(0, errs.ErrInvalidTwoFactorChallenge)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorCache: mockCache,
+		})
+
+		result, err := twoFactorService.CompleteChallenge(&dto.TwoFactorLoginRequest{TwoFactorToken: "challenge", Code: "123456"})
+
+		assert.Nil(t, result)
+		assert.Equal(t, errs.ErrInvalidTwoFactorChallenge, err)
+	})
+
+	t.Run("should return error without checking the code when attempts are throttled", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, secret := newUserTwoFactor(t, keyring, true)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockCache := mocks.NewTwoFactorCache(t)
+		mockLimiter := mocks.NewLimiter(t)
+		mockCache.On("FindChallenge", "challenge").Return(1, nil)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		mockLimiter.On("Check", "two_factor:account:1", "two_factor:ip:").Return(errs.ErrTooManyAttempts)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo:  mockRepo,
+			TwoFactorCache: mockCache,
+			Keyring:        keyring,
+			Limiter:        mockLimiter,
+		})
+
+		result, err := twoFactorService.CompleteChallenge(&dto.TwoFactorLoginRequest{
+			TwoFactorToken: "challenge",
+			Code:           totpCode(t, secret, totp.Step(time.Now())),
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, errs.ErrTooManyAttempts)
+		assert.Zero(t, twoFactor.LastUsedStep)
+	})
+
+	t.Run("should alert user when wrong code locks two-factor verification", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, _ := newUserTwoFactor(t, keyring, true)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockCache := mocks.NewTwoFactorCache(t)
+		mockLimiter := mocks.NewLimiter(t)
+		mockSessionService := mocks.NewUserSessionService(t)
+		mockCache.On("FindChallenge", "challenge").Return(1, nil)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		mockLimiter.On("Check", "two_factor:account:1", "two_factor:ip:127.0.0.1").Return(nil)
+		mockRepo.On("UseRecoveryCode", 1, mock.AnythingOfType("string")).Return(errs.ErrInvalidTwoFactorCode)
+		mockLimiter.On("Fail", "two_factor:account:1").Return(true, nil)
+		mockLimiter.On("Fail", "two_factor:ip:127.0.0.1").Return(false, nil)
+		mockSessionService.On("NotifyLockout", 1, "127.0.0.1").Return()
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo:  mockRepo,
+			TwoFactorCache: mockCache,
+			SessionService: mockSessionService,
+			Keyring:        keyring,
+			Limiter:        mockLimiter,
+		})
+
+		result, err := twoFactorService.CompleteChallenge(&dto.TwoFactorLoginRequest{
+			TwoFactorToken: "challenge",
+			Code:           "wrong-recovery-code",
+			Device:         dto.SessionDevice{IPAddress: "127.0.0.1"},
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, errs.ErrInvalidTwoFactorCode)
+	})
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	t.Run("should return token when recovery code is valid", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, _ := newUserTwoFactor(t, keyring, true)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockSessionService := mocks.NewUserSessionService(t)
+		token := &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		mockRepo.On("UseRecoveryCode", 1, hash.HashSHA256("abcde-fghjk")).Return(nil)
+		mockSessionService.On("IssueToken", 1, "session", mock.AnythingOfType("*time.Time")).Return(token, nil)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo:  mockRepo,
+			SessionService: mockSessionService,
+			Keyring:        keyring,
+		})
+
+		result, err := twoFactorService.Verify(1, "session", &dto.TwoFactorCodeRequest{Code: " ABCDE-FGHJK "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, token, result)
+	})
+
+	t.Run("should return error when recovery code was already used", func(t *testing.T) {
+		keyring := newTwoFactorKeyring(t)
+		twoFactor, _ := newUserTwoFactor(t, keyring, true)
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(twoFactor, nil)
+		mockRepo.On("UseRecoveryCode", 1, hash.HashSHA256("abcde-fghjk")).Return(errs.ErrInvalidTwoFactorCode)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+			Keyring:       keyring,
+		})
+
+		result, err := twoFactorService.Verify(1, "session", &dto.TwoFactorCodeRequest{Code: "abcde-fghjk"})
+
+		assert.Nil(t, result)
+		assert.Equal(t, errs.ErrInvalidTwoFactorCode, err)
+	})
+
+	t.Run("should return error when two factor is not enabled", func(t *testing.T) {
+		mockRepo := mocks.NewUserTwoFactorRepository(t)
+		mockRepo.On("GetByUserID", 1).Return(nil, errs.ErrTwoFactorNotEnrolled)
+		twoFactorService := service.NewTwoFactorService(&service.TwoFactorSConfig{
+			TwoFactorRepo: mockRepo,
+		})
+
+		result, err := twoFactorService.Verify(1, "session", &dto.TwoFactorCodeRequest{Code: "123456"})
+
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, errs.ErrTwoFactorNotEnabled))
+	})
+}
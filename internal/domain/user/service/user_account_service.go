@@ -0,0 +1,375 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
+	shopService "kedai/backend/be-kedai/internal/domain/shop/service"
+	"kedai/backend/be-kedai/internal/domain/user/cache"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/repository"
+	"kedai/backend/be-kedai/internal/utils/attempt"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/internal/utils/random"
+	"log"
+	"sort"
+	"time"
+)
+
+// AccountDataExporter is implemented by the domains that hold data about a
+// user outside of the user domain. Every key of the returned map becomes a
+// file of the user's data export.
+type AccountDataExporter interface {
+	ExportAccountData(userId int) (map[string]interface{}, error)
+}
+
+// AccountDeletionGuard is implemented by the domains that can keep an
+// account from being deleted, such as while an order is still open.
+type AccountDeletionGuard interface {
+	CheckAccountDeletion(userId int) error
+}
+
+type UserAccountService interface {
+	RequestDataExport(userId int) (*dto.DataExportResponse, error)
+	GetDataExport(userId int) (*dto.DataExportResponse, error)
+	DownloadDataExport(userId int) ([]byte, error)
+	ProcessDataExports() error
+	DeleteAccount(request *dto.DeleteAccountRequest) error
+}
+
+type userAccountServiceImpl struct {
+	userRepo          repository.UserRepository
+	walletRepo        repository.WalletRepository
+	walletHistoryRepo repository.WalletHistoryRepository
+	wishlistRepo      repository.UserWishlistRepository
+	dataExportRepo    repository.UserDataExportRepository
+	shopService       shopService.ShopService
+	sessionService    UserSessionService
+	emailPublisher    notificationService.EmailPublisher
+	randomUtils       random.RandomUtils
+	limiter           attempt.Limiter
+	userCache         cache.UserCache
+	exporters         []AccountDataExporter
+	guards            []AccountDeletionGuard
+}
+
+type UserAccountSConfig struct {
+	UserRepo          repository.UserRepository
+	WalletRepo        repository.WalletRepository
+	WalletHistoryRepo repository.WalletHistoryRepository
+	WishlistRepo      repository.UserWishlistRepository
+	DataExportRepo    repository.UserDataExportRepository
+	ShopService       shopService.ShopService
+	SessionService    UserSessionService
+	EmailPublisher    notificationService.EmailPublisher
+	RandomUtils       random.RandomUtils
+	Limiter           attempt.Limiter
+	UserCache         cache.UserCache
+	Exporters         []AccountDataExporter
+	Guards            []AccountDeletionGuard
+}
+
+func NewUserAccountService(cfg *UserAccountSConfig) UserAccountService {
+	return &userAccountServiceImpl{
+		userRepo:          cfg.UserRepo,
+		walletRepo:        cfg.WalletRepo,
+		walletHistoryRepo: cfg.WalletHistoryRepo,
+		wishlistRepo:      cfg.WishlistRepo,
+		dataExportRepo:    cfg.DataExportRepo,
+		shopService:       cfg.ShopService,
+		sessionService:    cfg.SessionService,
+		emailPublisher:    cfg.EmailPublisher,
+		randomUtils:       cfg.RandomUtils,
+		limiter:           cfg.Limiter,
+		userCache:         cfg.UserCache,
+		exporters:         cfg.Exporters,
+		guards:            cfg.Guards,
+	}
+}
+
+// RequestDataExport queues an export of the user's data, or returns the one
+// that is still queued.
+func (s *userAccountServiceImpl) RequestDataExport(userId int) (*dto.DataExportResponse, error) {
+	latest, err := s.dataExportRepo.GetLatestByUserID(userId)
+	if err != nil && !errors.Is(err, errs.ErrDataExportNotFound) {
+		return nil, err
+	}
+
+	export := latest
+	if latest == nil || latest.Status != constant.DataExportStatusPending {
+		export = &model.UserDataExport{
+			UserID: userId,
+			Status: constant.DataExportStatusPending,
+		}
+
+		err = s.dataExportRepo.Create(export)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var res dto.DataExportResponse
+	res.FromUserDataExport(export)
+
+	return &res, nil
+}
+
+func (s *userAccountServiceImpl) GetDataExport(userId int) (*dto.DataExportResponse, error) {
+	export, err := s.getDataExport(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var res dto.DataExportResponse
+	res.FromUserDataExport(export)
+
+	return &res, nil
+}
+
+func (s *userAccountServiceImpl) DownloadDataExport(userId int) ([]byte, error) {
+	export, err := s.getDataExport(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch export.Status {
+	case constant.DataExportStatusReady:
+		return s.dataExportRepo.GetArchive(export.ID)
+	case constant.DataExportStatusPending:
+		return nil, errs.ErrDataExportNotReady
+	default:
+		return nil, errs.ErrDataExportNotFound
+	}
+}
+
+// ProcessDataExports builds one batch of queued exports and emails each user
+// once theirs is ready. It also purges archives that have expired.
+func (s *userAccountServiceImpl) ProcessDataExports() error {
+	now := time.Now()
+
+	if err := s.dataExportRepo.DeleteExpired(now); err != nil {
+		log.Println("failed to delete expired data exports:", err)
+	}
+
+	exports, err := s.dataExportRepo.ClaimPending(now, constant.DataExportClaimLease, constant.DataExportBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		user, archive, err := s.buildArchive(export.UserID)
+		if err != nil {
+			log.Println("failed to build data export", export.ID, ":", err)
+			if err := s.dataExportRepo.MarkFailed(export.ID); err != nil {
+				log.Println("failed to record data export failure", export.ID, ":", err)
+			}
+			continue
+		}
+
+		completedAt := time.Now()
+		expiresAt := completedAt.Add(constant.DataExportAge)
+		if err := s.dataExportRepo.MarkReady(export.ID, archive, completedAt, expiresAt); err != nil {
+			log.Println("failed to store data export", export.ID, ":", err)
+			continue
+		}
+
+		if s.emailPublisher == nil {
+			continue
+		}
+
+		err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+			To:       user.Email,
+			UserID:   user.ID,
+			Category: constant.NotificationCategorySecurity,
+			Template: mail.TemplateDataExportReady,
+			Data: &mail.DataExportReadyEmailData{
+				Username:  user.Username,
+				URL:       config.GetEnv("FRONTEND_URL", "http://localhost:3000") + config.GetEnv("DATA_EXPORT_URL", "/account/data-export"),
+				ExpiresAt: expiresAt.Format("2 January 2006 15:04 MST"),
+			},
+		})
+		if err != nil {
+			log.Println("failed to send data export email to user", user.ID, ":", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteAccount anonymises the user once they confirmed their password and
+// nothing they are part of is still open.
+func (s *userAccountServiceImpl) DeleteAccount(request *dto.DeleteAccountRequest) error {
+	accountKey := attempt.AccountKey(constant.AttemptScopeAccountDelete, request.UserId)
+	err := checkAttempts(s.limiter, accountKey)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(request.UserId)
+	if err != nil {
+		return err
+	}
+
+	if !hash.ComparePassword(user.Password, request.Password) {
+		recordFailedAttempt(s.limiter, s.sessionService, user.ID, request.IPAddress, accountKey)
+		return errs.ErrInvalidCredential
+	}
+	resetAttempts(s.limiter, accountKey)
+
+	err = s.checkAccountDeletion(user.ID)
+	if err != nil {
+		return err
+	}
+
+	pseudonym := "deleted_" + s.randomUtils.GenerateSecureUniqueToken()[:16]
+	err = s.userRepo.Anonymise(&model.User{
+		ID:       user.ID,
+		Username: pseudonym,
+		Email:    pseudonym + "@" + constant.DeletedUserEmailDomain,
+		Password: s.randomUtils.GenerateSecureUniqueToken(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.userCache.DeleteAllByID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	if s.emailPublisher != nil {
+		err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+			To:       user.Email,
+			Category: constant.NotificationCategorySecurity,
+			Template: mail.TemplateAccountDeleted,
+			Data: &mail.AccountDeletedEmailData{
+				Username:  user.Username,
+				DeletedAt: time.Now().Format("2 January 2006 15:04 MST"),
+			},
+		})
+		if err != nil {
+			log.Println("failed to send account deleted email to user", user.ID, ":", err)
+		}
+	}
+
+	return nil
+}
+
+// getDataExport returns the user's latest export while it can still be
+// downloaded.
+func (s *userAccountServiceImpl) getDataExport(userId int) (*model.UserDataExport, error) {
+	export, err := s.dataExportRepo.GetLatestByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.ExpiresAt != nil && !time.Now().Before(*export.ExpiresAt) {
+		return nil, errs.ErrDataExportNotFound
+	}
+
+	return export, nil
+}
+
+// checkAccountDeletion returns why the account cannot be deleted yet, if
+// anything keeps it open.
+func (s *userAccountServiceImpl) checkAccountDeletion(userId int) error {
+	_, err := s.shopService.FindShopByUserId(userId)
+	if err == nil {
+		return errs.ErrAccountHasShop
+	}
+	if !errors.Is(err, errs.ErrShopNotFound) {
+		return err
+	}
+
+	wallet, err := s.walletRepo.GetByUserID(userId)
+	if err != nil && !errors.Is(err, errs.ErrWalletDoesNotExist) {
+		return err
+	}
+	if wallet != nil && wallet.Balance > 0 {
+		return errs.ErrAccountHasWalletBalance
+	}
+
+	for _, guard := range s.guards {
+		if err := guard.CheckAccountDeletion(userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildArchive zips every part of the user's data as its own JSON file.
+func (s *userAccountServiceImpl) buildArchive(userId int) (*model.User, []byte, error) {
+	user, err := s.userRepo.GetByID(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	walletHistories := []*model.WalletHistory{}
+	wallet, err := s.walletRepo.GetByUserID(userId)
+	if err != nil && !errors.Is(err, errs.ErrWalletDoesNotExist) {
+		return nil, nil, err
+	}
+	if wallet != nil {
+		walletHistories, err = s.walletHistoryRepo.GetAllByWalletID(wallet.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	wishlists, err := s.wishlistRepo.GetAllByUserID(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sections := map[string]interface{}{
+		"profile":        user,
+		"wallet_history": walletHistories,
+		"wishlists":      wishlists,
+	}
+	for _, exporter := range s.exporters {
+		exported, err := exporter.ExportAccountData(userId)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for name, data := range exported {
+			sections[name] = data
+		}
+	}
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range names {
+		file, err := archive.Create(name + ".json")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(sections[name]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return user, buf.Bytes(), nil
+}
@@ -0,0 +1,416 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	shopModel "kedai/backend/be-kedai/internal/domain/shop/model"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/service"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	mocks "kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type accountServiceMocks struct {
+	userRepo          *mocks.UserRepository
+	walletRepo        *mocks.WalletRepository
+	walletHistoryRepo *mocks.WalletHistoryRepository
+	wishlistRepo      *mocks.UserWishlistRepository
+	dataExportRepo    *mocks.UserDataExportRepository
+	shopService       *mocks.ShopService
+	emailPublisher    *mocks.EmailPublisher
+	randomUtils       *mocks.RandomUtils
+	limiter           *mocks.Limiter
+	userCache         *mocks.UserCache
+	exporter          *mocks.AccountDataExporter
+	guard             *mocks.AccountDeletionGuard
+}
+
+func newAccountServiceMocks(t *testing.T) (*accountServiceMocks, service.UserAccountService) {
+	m := &accountServiceMocks{
+		userRepo:          mocks.NewUserRepository(t),
+		walletRepo:        mocks.NewWalletRepository(t),
+		walletHistoryRepo: mocks.NewWalletHistoryRepository(t),
+		wishlistRepo:      mocks.NewUserWishlistRepository(t),
+		dataExportRepo:    mocks.NewUserDataExportRepository(t),
+		shopService:       mocks.NewShopService(t),
+		emailPublisher:    mocks.NewEmailPublisher(t),
+		randomUtils:       mocks.NewRandomUtils(t),
+		limiter:           mocks.NewLimiter(t),
+		userCache:         mocks.NewUserCache(t),
+		exporter:          mocks.NewAccountDataExporter(t),
+		guard:             mocks.NewAccountDeletionGuard(t),
+	}
+
+	return m, service.NewUserAccountService(&service.UserAccountSConfig{
+		UserRepo:          m.userRepo,
+		WalletRepo:        m.walletRepo,
+		WalletHistoryRepo: m.walletHistoryRepo,
+		WishlistRepo:      m.wishlistRepo,
+		DataExportRepo:    m.dataExportRepo,
+		ShopService:       m.shopService,
+		EmailPublisher:    m.emailPublisher,
+		RandomUtils:       m.randomUtils,
+		Limiter:           m.limiter,
+		UserCache:         m.userCache,
+		Exporters:         []service.AccountDataExporter{m.exporter},
+		Guards:            []service.AccountDeletionGuard{m.guard},
+	})
+}
+
+func TestRequestDataExport(t *testing.T) {
+	pending := &model.UserDataExport{ID: 1, UserID: 1, Status: constant.DataExportStatusPending}
+	ready := &model.UserDataExport{ID: 1, UserID: 1, Status: constant.DataExportStatusReady}
+
+	cases := []struct {
+		description string
+		beforeTest  func(*accountServiceMocks)
+		expected    *dto.DataExportResponse
+		err         error
+	}{
+		{
+			description: "should return error when failed to get the latest export",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(nil, errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+		{
+			description: "should return the export that is still pending instead of queueing another",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(pending, nil)
+			},
+			expected: &dto.DataExportResponse{ID: 1, Status: constant.DataExportStatusPending},
+		},
+		{
+			description: "should queue an export when the user has none",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(nil, errs.ErrDataExportNotFound)
+				m.dataExportRepo.On("Create", &model.UserDataExport{UserID: 1, Status: constant.DataExportStatusPending}).Return(nil)
+			},
+			expected: &dto.DataExportResponse{Status: constant.DataExportStatusPending},
+		},
+		{
+			description: "should queue a new export when the latest one is ready",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(ready, nil)
+				m.dataExportRepo.On("Create", &model.UserDataExport{UserID: 1, Status: constant.DataExportStatusPending}).Return(nil)
+			},
+			expected: &dto.DataExportResponse{Status: constant.DataExportStatusPending},
+		},
+		{
+			description: "should return error when failed to queue the export",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(nil, errs.ErrDataExportNotFound)
+				m.dataExportRepo.On("Create", mock.Anything).Return(errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, accountService := newAccountServiceMocks(t)
+			tc.beforeTest(m)
+
+			res, err := accountService.RequestDataExport(1)
+
+			assert.Equal(t, tc.expected, res)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestGetDataExport(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		description string
+		export      *model.UserDataExport
+		err         error
+	}{
+		{
+			description: "should return the export while it has not expired",
+			export:      &model.UserDataExport{ID: 1, Status: constant.DataExportStatusReady, ExpiresAt: &future},
+		},
+		{
+			description: "should return not found error once the export expired",
+			export:      &model.UserDataExport{ID: 1, Status: constant.DataExportStatusReady, ExpiresAt: &past},
+			err:         errs.ErrDataExportNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, accountService := newAccountServiceMocks(t)
+			m.dataExportRepo.On("GetLatestByUserID", 1).Return(tc.export, nil)
+
+			res, err := accountService.GetDataExport(1)
+
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, tc.export.ID, res.ID)
+			}
+		})
+	}
+}
+
+func TestDownloadDataExport(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		description string
+		beforeTest  func(*accountServiceMocks)
+		expected    []byte
+		err         error
+	}{
+		{
+			description: "should return not found error when the user has no export",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(nil, errs.ErrDataExportNotFound)
+			},
+			err: errs.ErrDataExportNotFound,
+		},
+		{
+			description: "should return not ready error while the export is pending",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(&model.UserDataExport{ID: 1, Status: constant.DataExportStatusPending}, nil)
+			},
+			err: errs.ErrDataExportNotReady,
+		},
+		{
+			description: "should return not found error when the export failed",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(&model.UserDataExport{ID: 1, Status: constant.DataExportStatusFailed}, nil)
+			},
+			err: errs.ErrDataExportNotFound,
+		},
+		{
+			description: "should return the archive once the export is ready",
+			beforeTest: func(m *accountServiceMocks) {
+				m.dataExportRepo.On("GetLatestByUserID", 1).Return(&model.UserDataExport{ID: 1, Status: constant.DataExportStatusReady, ExpiresAt: &future}, nil)
+				m.dataExportRepo.On("GetArchive", 1).Return([]byte("archive"), nil)
+			},
+			expected: []byte("archive"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, accountService := newAccountServiceMocks(t)
+			tc.beforeTest(m)
+
+			res, err := accountService.DownloadDataExport(1)
+
+			assert.Equal(t, tc.expected, res)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestProcessDataExports(t *testing.T) {
+	user := &model.User{ID: 1, Email: "user@email.com", Username: "user"}
+	exports := []*model.UserDataExport{{ID: 1, UserID: 1, Status: constant.DataExportStatusPending}}
+
+	t.Run("should return error when failed to claim pending exports", func(t *testing.T) {
+		m, accountService := newAccountServiceMocks(t)
+		m.dataExportRepo.On("DeleteExpired", mock.Anything).Return(nil)
+		m.dataExportRepo.On("ClaimPending", mock.Anything, constant.DataExportClaimLease, constant.DataExportBatchSize).Return(nil, errors.New("failed"))
+
+		err := accountService.ProcessDataExports()
+
+		assert.Equal(t, errors.New("failed"), err)
+	})
+
+	t.Run("should mark the export failed when a domain fails to export its data", func(t *testing.T) {
+		m, accountService := newAccountServiceMocks(t)
+		m.dataExportRepo.On("DeleteExpired", mock.Anything).Return(nil)
+		m.dataExportRepo.On("ClaimPending", mock.Anything, constant.DataExportClaimLease, constant.DataExportBatchSize).Return(exports, nil)
+		m.userRepo.On("GetByID", 1).Return(user, nil)
+		m.walletRepo.On("GetByUserID", 1).Return(nil, errs.ErrWalletDoesNotExist)
+		m.wishlistRepo.On("GetAllByUserID", 1).Return([]*model.UserWishlist{}, nil)
+		m.exporter.On("ExportAccountData", 1).Return(nil, errors.New("failed"))
+		m.dataExportRepo.On("MarkFailed", 1).Return(nil)
+
+		err := accountService.ProcessDataExports()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should store the archive and email the user once it is ready", func(t *testing.T) {
+		var archive []byte
+		m, accountService := newAccountServiceMocks(t)
+		m.dataExportRepo.On("DeleteExpired", mock.Anything).Return(nil)
+		m.dataExportRepo.On("ClaimPending", mock.Anything, constant.DataExportClaimLease, constant.DataExportBatchSize).Return(exports, nil)
+		m.userRepo.On("GetByID", 1).Return(user, nil)
+		m.walletRepo.On("GetByUserID", 1).Return(&model.Wallet{ID: 2}, nil)
+		m.walletHistoryRepo.On("GetAllByWalletID", 2).Return([]*model.WalletHistory{}, nil)
+		m.wishlistRepo.On("GetAllByUserID", 1).Return([]*model.UserWishlist{}, nil)
+		m.exporter.On("ExportAccountData", 1).Return(map[string]interface{}{"orders": []string{}}, nil)
+		m.dataExportRepo.On("MarkReady", 1, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { archive = args.Get(1).([]byte) }).
+			Return(nil)
+		m.emailPublisher.On("Enqueue", mock.MatchedBy(func(request *notificationDto.EmailRequest) bool {
+			return request.To == user.Email && request.Template == mail.TemplateDataExportReady
+		})).Return(nil)
+
+		err := accountService.ProcessDataExports()
+
+		assert.Nil(t, err)
+		reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		assert.Nil(t, err)
+		names := []string{}
+		for _, file := range reader.File {
+			names = append(names, file.Name)
+		}
+		assert.Equal(t, []string{"orders.json", "profile.json", "wallet_history.json", "wishlists.json"}, names)
+		profile, _ := reader.File[1].Open()
+		content, _ := io.ReadAll(profile)
+		assert.Contains(t, string(content), user.Email)
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	hashedPw, _ := hash.HashAndSalt("password")
+	user := &model.User{ID: 1, Email: "user@email.com", Username: "user", Password: hashedPw}
+	request := &dto.DeleteAccountRequest{UserId: 1, IPAddress: "127.0.0.1", Password: "password"}
+
+	cases := []struct {
+		description string
+		request     *dto.DeleteAccountRequest
+		beforeTest  func(*accountServiceMocks)
+		err         error
+	}{
+		{
+			description: "should return error when there were too many attempts",
+			request:     request,
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(errs.ErrTooManyAttempts)
+			},
+			err: errs.ErrTooManyAttempts,
+		},
+		{
+			description: "should record a failed attempt when password is wrong",
+			request:     &dto.DeleteAccountRequest{UserId: 1, IPAddress: "127.0.0.1", Password: "wrong"},
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(nil)
+				m.userRepo.On("GetByID", 1).Return(user, nil)
+				m.limiter.On("Fail", "account_delete:account:1").Return(false, nil)
+			},
+			err: errs.ErrInvalidCredential,
+		},
+		{
+			description: "should return error when user owns a shop",
+			request:     request,
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(nil)
+				m.userRepo.On("GetByID", 1).Return(user, nil)
+				m.limiter.On("Reset", "account_delete:account:1").Return(nil)
+				m.shopService.On("FindShopByUserId", 1).Return(&shopModel.Shop{ID: 1}, nil)
+			},
+			err: errs.ErrAccountHasShop,
+		},
+		{
+			description: "should return error when user still has wallet balance",
+			request:     request,
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(nil)
+				m.userRepo.On("GetByID", 1).Return(user, nil)
+				m.limiter.On("Reset", "account_delete:account:1").Return(nil)
+				m.shopService.On("FindShopByUserId", 1).Return(nil, errs.ErrShopNotFound)
+				m.walletRepo.On("GetByUserID", 1).Return(&model.Wallet{ID: 2, Balance: 1000}, nil)
+			},
+			err: errs.ErrAccountHasWalletBalance,
+		},
+		{
+			description: "should return the error of the domain that keeps the account open",
+			request:     request,
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(nil)
+				m.userRepo.On("GetByID", 1).Return(user, nil)
+				m.limiter.On("Reset", "account_delete:account:1").Return(nil)
+				m.shopService.On("FindShopByUserId", 1).Return(nil, errs.ErrShopNotFound)
+				m.walletRepo.On("GetByUserID", 1).Return(&model.Wallet{ID: 2}, nil)
+				m.guard.On("CheckAccountDeletion", 1).Return(errs.ErrAccountHasOpenOrders)
+			},
+			err: errs.ErrAccountHasOpenOrders,
+		},
+		{
+			description: "should return error when failed to anonymise the user",
+			request:     request,
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(nil)
+				m.userRepo.On("GetByID", 1).Return(user, nil)
+				m.limiter.On("Reset", "account_delete:account:1").Return(nil)
+				m.shopService.On("FindShopByUserId", 1).Return(nil, errs.ErrShopNotFound)
+				m.walletRepo.On("GetByUserID", 1).Return(nil, errs.ErrWalletDoesNotExist)
+				m.guard.On("CheckAccountDeletion", 1).Return(nil)
+				m.randomUtils.On("GenerateSecureUniqueToken").Return("0123456789abcdef0123")
+				m.userRepo.On("Anonymise", mock.Anything).Return(errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+		{
+			description: "should return error when failed to sign the user out",
+			request:     request,
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(nil)
+				m.userRepo.On("GetByID", 1).Return(user, nil)
+				m.limiter.On("Reset", "account_delete:account:1").Return(nil)
+				m.shopService.On("FindShopByUserId", 1).Return(nil, errs.ErrShopNotFound)
+				m.walletRepo.On("GetByUserID", 1).Return(nil, errs.ErrWalletDoesNotExist)
+				m.guard.On("CheckAccountDeletion", 1).Return(nil)
+				m.randomUtils.On("GenerateSecureUniqueToken").Return("0123456789abcdef0123")
+				m.userRepo.On("Anonymise", mock.Anything).Return(nil)
+				m.userCache.On("DeleteAllByID", 1).Return(errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+		{
+			description: "should anonymise the user and email their old address",
+			request:     request,
+			beforeTest: func(m *accountServiceMocks) {
+				m.limiter.On("Check", "account_delete:account:1").Return(nil)
+				m.userRepo.On("GetByID", 1).Return(user, nil)
+				m.limiter.On("Reset", "account_delete:account:1").Return(nil)
+				m.shopService.On("FindShopByUserId", 1).Return(nil, errs.ErrShopNotFound)
+				m.walletRepo.On("GetByUserID", 1).Return(&model.Wallet{ID: 2}, nil)
+				m.guard.On("CheckAccountDeletion", 1).Return(nil)
+				m.randomUtils.On("GenerateSecureUniqueToken").Return("0123456789abcdef0123")
+				m.userRepo.On("Anonymise", &model.User{
+					ID:       1,
+					Username: "deleted_0123456789abcdef",
+					Email:    "deleted_0123456789abcdef@" + constant.DeletedUserEmailDomain,
+					Password: "0123456789abcdef0123",
+				}).Return(nil)
+				m.userCache.On("DeleteAllByID", 1).Return(nil)
+				m.emailPublisher.On("Enqueue", mock.MatchedBy(func(request *notificationDto.EmailRequest) bool {
+					return request.To == "user@email.com" && request.Template == mail.TemplateAccountDeleted
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, accountService := newAccountServiceMocks(t)
+			tc.beforeTest(m)
+
+			err := accountService.DeleteAccount(tc.request)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
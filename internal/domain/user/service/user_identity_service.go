@@ -0,0 +1,380 @@
+package service
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/repository"
+	"kedai/backend/be-kedai/internal/utils/credential"
+	"kedai/backend/be-kedai/internal/utils/identity"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"log"
+	"time"
+)
+
+type UserIdentityService interface {
+	GetProviders() *dto.IdentityProvidersResponse
+	SignIn(request *dto.UserLoginWithProviderRequest) (*dto.Token, error)
+	SignUp(request *dto.UserRegistrationWithProviderRequest) (*dto.Token, error)
+	ConfirmLink(token string) error
+	GetIdentities(userId int) ([]*dto.UserIdentityResponse, error)
+	Link(userId int, request *dto.LinkIdentityRequest) (*dto.UserIdentityResponse, error)
+	Unlink(userId int, provider string) error
+}
+
+type userIdentityServiceImpl struct {
+	identityRepo     repository.UserIdentityRepository
+	userRepo         repository.UserRepository
+	userService      UserService
+	sessionService   UserSessionService
+	twoFactorService TwoFactorService
+	emailPublisher   notificationService.EmailPublisher
+	providers        identity.Registry
+}
+
+type UserIdentitySConfig struct {
+	IdentityRepo     repository.UserIdentityRepository
+	UserRepo         repository.UserRepository
+	UserService      UserService
+	SessionService   UserSessionService
+	TwoFactorService TwoFactorService
+	EmailPublisher   notificationService.EmailPublisher
+	Providers        identity.Registry
+}
+
+func NewUserIdentityService(cfg *UserIdentitySConfig) UserIdentityService {
+	return &userIdentityServiceImpl{
+		identityRepo:     cfg.IdentityRepo,
+		userRepo:         cfg.UserRepo,
+		userService:      cfg.UserService,
+		sessionService:   cfg.SessionService,
+		twoFactorService: cfg.TwoFactorService,
+		emailPublisher:   cfg.EmailPublisher,
+		providers:        cfg.Providers,
+	}
+}
+
+func (s *userIdentityServiceImpl) GetProviders() *dto.IdentityProvidersResponse {
+	return &dto.IdentityProvidersResponse{Providers: s.providers.Names()}
+}
+
+// SignIn signs in the user the provider account is linked to. An account
+// that is not linked yet but shares its email with a user is only linked
+// once that user follows the link emailed to them, unless it is the Google
+// account they signed up with before accounts were linked.
+func (s *userIdentityServiceImpl) SignIn(request *dto.UserLoginWithProviderRequest) (*dto.Token, error) {
+	verified, err := s.verify(request.Provider, request.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := s.identityRepo.GetByProviderSubject(verified.Provider, verified.Subject)
+	if errors.Is(err, errs.ErrIdentityNotFound) {
+		linked, err = s.linkGoogleUser(verified)
+	}
+	if errors.Is(err, errs.ErrIdentityNotFound) {
+		pending, err := s.requestLink(verified)
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return nil, errs.ErrIdentityLinkPending
+		}
+
+		return nil, errs.ErrInvalidCredential
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.twoFactorService != nil {
+		challenge, err := s.twoFactorService.ChallengeIfEnabled(linked.UserID)
+		if err != nil || challenge != nil {
+			return challenge, err
+		}
+	}
+
+	return s.sessionService.StartSession(linked.UserID, &request.Device, nil)
+}
+
+// SignUp creates a user linked to the provider account. When a user already
+// has its email, the accounts are merged through an emailed link instead.
+func (s *userIdentityServiceImpl) SignUp(request *dto.UserRegistrationWithProviderRequest) (*dto.Token, error) {
+	isValidUsername := credential.VerifyUsername(request.Username)
+	if !isValidUsername {
+		return nil, errs.ErrInvalidUsernamePattern
+	}
+
+	isValidPassword := credential.VerifyPassword(request.Password)
+	if !isValidPassword {
+		return nil, errs.ErrInvalidPasswordPattern
+	}
+
+	if credential.ContainsUsername(request.Password, request.Username) {
+		return nil, errs.ErrInvalidPasswordPattern
+	}
+
+	verified, err := s.verify(request.Provider, request.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	if verified.Email == "" {
+		return nil, errs.ErrIdentityEmailMissing
+	}
+
+	_, err = s.identityRepo.GetByProviderSubject(verified.Provider, verified.Subject)
+	if err == nil {
+		return nil, errs.ErrUserAlreadyExist
+	}
+	if !errors.Is(err, errs.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	pending, err := s.requestLink(verified)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errs.ErrIdentityLinkPending
+	}
+
+	user := &model.User{
+		Email:    verified.Email,
+		Username: request.Username,
+		Password: request.Password,
+		Identities: []*model.UserIdentity{{
+			Provider: verified.Provider,
+			Subject:  verified.Subject,
+			Email:    verified.Email,
+		}},
+	}
+	if verified.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	result, err := s.userRepo.SignUp(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.EmailVerifiedAt == nil && s.userService != nil {
+		if err := s.userService.SendEmailVerification(result.ID); err != nil {
+			log.Println("failed to send email verification to user", result.ID, ":", err)
+		}
+	}
+
+	return s.sessionService.StartSession(result.ID, &request.Device, nil)
+}
+
+// ConfirmLink links the provider account named by token, which was emailed
+// to the user whose email it shares.
+func (s *userIdentityServiceImpl) ConfirmLink(token string) error {
+	linkToken, err := dto.ParseEmailToken(token, constant.EmailTokenPurposeLink)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(linkToken.UserID)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserDoesNotExist) {
+			return errs.ErrInvalidEmailToken
+		}
+		return err
+	}
+
+	if user.Email != linkToken.CurrentEmail {
+		return errs.ErrInvalidEmailToken
+	}
+
+	linked, err := s.identityRepo.GetByProviderSubject(linkToken.Provider, linkToken.Subject)
+	if err == nil {
+		if linked.UserID == user.ID {
+			return nil
+		}
+		return errs.ErrIdentityAlreadyLinked
+	}
+	if !errors.Is(err, errs.ErrIdentityNotFound) {
+		return err
+	}
+
+	return s.identityRepo.Create(&model.UserIdentity{
+		UserID:   user.ID,
+		Provider: linkToken.Provider,
+		Subject:  linkToken.Subject,
+		Email:    linkToken.Email,
+	})
+}
+
+func (s *userIdentityServiceImpl) GetIdentities(userId int) ([]*dto.UserIdentityResponse, error) {
+	identities, err := s.identityRepo.GetByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*dto.UserIdentityResponse{}
+	for _, linked := range identities {
+		var identityRes dto.UserIdentityResponse
+		identityRes.FromUserIdentity(linked)
+		res = append(res, &identityRes)
+	}
+
+	return res, nil
+}
+
+// Link links a provider account to a signed in user. Its email does not have
+// to match theirs.
+func (s *userIdentityServiceImpl) Link(userId int, request *dto.LinkIdentityRequest) (*dto.UserIdentityResponse, error) {
+	verified, err := s.verify(request.Provider, request.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.identityRepo.GetByProviderSubject(verified.Provider, verified.Subject)
+	if err == nil {
+		return nil, errs.ErrIdentityAlreadyLinked
+	}
+	if !errors.Is(err, errs.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	linked := &model.UserIdentity{
+		UserID:   userId,
+		Provider: verified.Provider,
+		Subject:  verified.Subject,
+		Email:    verified.Email,
+	}
+	err = s.identityRepo.Create(linked)
+	if err != nil {
+		return nil, err
+	}
+
+	var res dto.UserIdentityResponse
+	res.FromUserIdentity(linked)
+
+	return &res, nil
+}
+
+// Unlink removes the user's provider account. Every user has a password, so
+// they can still sign in without it.
+func (s *userIdentityServiceImpl) Unlink(userId int, provider string) error {
+	return s.identityRepo.Delete(userId, provider)
+}
+
+// verify returns the provider account credential belongs to. Why a
+// credential was rejected is only logged.
+func (s *userIdentityServiceImpl) verify(provider string, credential string) (*identity.Identity, error) {
+	identityProvider, err := s.providers.Get(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := identityProvider.Verify(credential)
+	if err != nil {
+		log.Println("failed to verify", provider, "credential:", err)
+		return nil, errs.ErrUnauthorized
+	}
+
+	return verified, nil
+}
+
+// linkGoogleUser links a Google account to the user with its email. Google
+// sign in used to find users by email alone, so users who signed up with
+// Google before accounts were linked have no linked account yet. It is only
+// linked when both Google and the user verified the email and the user has
+// no other Google account linked.
+func (s *userIdentityServiceImpl) linkGoogleUser(verified *identity.Identity) (*model.UserIdentity, error) {
+	if verified.Provider != constant.IdentityProviderGoogle || verified.Email == "" || !verified.EmailVerified {
+		return nil, errs.ErrIdentityNotFound
+	}
+
+	user, err := s.userRepo.GetByEmail(verified.Email)
+	if errors.Is(err, errs.ErrUserDoesNotExist) {
+		return nil, errs.ErrIdentityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return nil, errs.ErrIdentityNotFound
+	}
+
+	identities, err := s.identityRepo.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, linked := range identities {
+		if linked.Provider == constant.IdentityProviderGoogle {
+			return nil, errs.ErrIdentityNotFound
+		}
+	}
+
+	linked := &model.UserIdentity{
+		UserID:   user.ID,
+		Provider: verified.Provider,
+		Subject:  verified.Subject,
+		Email:    verified.Email,
+	}
+	err = s.identityRepo.Create(linked)
+	if err != nil {
+		return nil, err
+	}
+
+	return linked, nil
+}
+
+// requestLink emails a link to link the provider account to the user who has
+// its email, and reports whether there is such a user.
+func (s *userIdentityServiceImpl) requestLink(verified *identity.Identity) (bool, error) {
+	if verified.Email == "" {
+		return false, nil
+	}
+
+	user, err := s.userRepo.GetByEmail(verified.Email)
+	if errors.Is(err, errs.ErrUserDoesNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if s.emailPublisher == nil {
+		return true, nil
+	}
+
+	token := &dto.EmailToken{
+		Purpose:      constant.EmailTokenPurposeLink,
+		UserID:       user.ID,
+		Email:        verified.Email,
+		CurrentEmail: user.Email,
+		ExpiresAt:    time.Now().Add(constant.EmailVerificationAge),
+		Provider:     verified.Provider,
+		Subject:      verified.Subject,
+	}
+
+	err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+		To:       user.Email,
+		UserID:   user.ID,
+		Category: constant.NotificationCategorySecurity,
+		Template: mail.TemplateLinkIdentity,
+		Data: &mail.LinkIdentityEmailData{
+			Username:  user.Username,
+			Provider:  verified.Provider,
+			URL:       config.GetEnv("FRONTEND_URL", "http://localhost:3000") + config.GetEnv("LINK_IDENTITY_URL", "/link-account?token=") + token.Sign(),
+			ExpiresAt: token.ExpiresAt.Format("2 January 2006 15:04 MST"),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,420 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/service"
+	"kedai/backend/be-kedai/internal/utils/identity"
+	"kedai/backend/be-kedai/internal/utils/test"
+	mocks "kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type identityServiceMocks struct {
+	provider       *mocks.Provider
+	identityRepo   *mocks.UserIdentityRepository
+	userRepo       *mocks.UserRepository
+	userService    *mocks.UserService
+	sessionService *mocks.UserSessionService
+	emailPublisher *mocks.EmailPublisher
+}
+
+func newIdentityServiceMocks(t *testing.T) (*identityServiceMocks, service.UserIdentityService) {
+	m := &identityServiceMocks{
+		provider:       mocks.NewProvider(t),
+		identityRepo:   mocks.NewUserIdentityRepository(t),
+		userRepo:       mocks.NewUserRepository(t),
+		userService:    mocks.NewUserService(t),
+		sessionService: mocks.NewUserSessionService(t),
+		emailPublisher: mocks.NewEmailPublisher(t),
+	}
+	m.provider.On("Name").Return(constant.IdentityProviderGoogle)
+
+	return m, service.NewUserIdentityService(&service.UserIdentitySConfig{
+		IdentityRepo:   m.identityRepo,
+		UserRepo:       m.userRepo,
+		UserService:    m.userService,
+		SessionService: m.sessionService,
+		EmailPublisher: m.emailPublisher,
+		Providers:      identity.NewRegistry(m.provider),
+	})
+}
+
+func TestIdentitySignIn(t *testing.T) {
+	verified := &identity.Identity{Provider: "google", Subject: "sub", Email: "user@email.com", EmailVerified: true}
+	token := &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
+
+	cases := []struct {
+		description string
+		request     *dto.UserLoginWithProviderRequest
+		beforeTest  func(*identityServiceMocks)
+		expected    *dto.Token
+		err         error
+	}{
+		{
+			description: "should return error when provider is not configured",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "apple", Credential: "credential"},
+			beforeTest:  func(m *identityServiceMocks) {},
+			err:         errs.ErrUnknownIdentityProvider,
+		},
+		{
+			description: "should return unauthorized error when provider rejects the credential",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "google", Credential: "credential"},
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(nil, identity.ErrInvalidCredential)
+			},
+			err: errs.ErrUnauthorized,
+		},
+		{
+			description: "should return error when provider account is not linked and no user has its email",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "google", Credential: "credential"},
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(nil, errs.ErrUserDoesNotExist)
+			},
+			err: errs.ErrInvalidCredential,
+		},
+		{
+			description: "should email a link and return error when a user has the provider account email",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "google", Credential: "credential"},
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(&model.User{ID: 1, Email: "user@email.com"}, nil)
+				m.emailPublisher.On("Enqueue", mock.Anything).Return(nil)
+			},
+			err: errs.ErrIdentityLinkPending,
+		},
+		{
+			description: "should link and sign in the verified user who signed up with the Google account email",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "google", Credential: "credential"},
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(&model.User{ID: 1, Email: "user@email.com", EmailVerifiedAt: &time.Time{}}, nil)
+				m.identityRepo.On("GetByUserID", 1).Return([]*model.UserIdentity{}, nil)
+				m.identityRepo.On("Create", &model.UserIdentity{UserID: 1, Provider: "google", Subject: "sub", Email: "user@email.com"}).Return(nil)
+				m.sessionService.On("StartSession", 1, mock.Anything, (*time.Time)(nil)).Return(token, nil)
+			},
+			expected: token,
+		},
+		{
+			description: "should email a link when Google did not verify the email",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "google", Credential: "credential"},
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(&identity.Identity{Provider: "google", Subject: "sub", Email: "user@email.com"}, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(&model.User{ID: 1, Email: "user@email.com", EmailVerifiedAt: &time.Time{}}, nil)
+				m.emailPublisher.On("Enqueue", mock.Anything).Return(nil)
+			},
+			err: errs.ErrIdentityLinkPending,
+		},
+		{
+			description: "should email a link when the user already has another Google account linked",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "google", Credential: "credential"},
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(&model.User{ID: 1, Email: "user@email.com", EmailVerifiedAt: &time.Time{}}, nil)
+				m.identityRepo.On("GetByUserID", 1).Return([]*model.UserIdentity{{UserID: 1, Provider: "google", Subject: "other"}}, nil)
+				m.emailPublisher.On("Enqueue", mock.Anything).Return(nil)
+			},
+			err: errs.ErrIdentityLinkPending,
+		},
+		{
+			description: "should start a session for the linked user",
+			request:     &dto.UserLoginWithProviderRequest{Provider: "google", Credential: "credential"},
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(&model.UserIdentity{UserID: 1}, nil)
+				m.sessionService.On("StartSession", 1, mock.Anything, (*time.Time)(nil)).Return(token, nil)
+			},
+			expected: token,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, identityService := newIdentityServiceMocks(t)
+			tc.beforeTest(m)
+
+			res, err := identityService.SignIn(tc.request)
+
+			assert.Equal(t, tc.expected, res)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestIdentitySignUp(t *testing.T) {
+	verified := &identity.Identity{Provider: "google", Subject: "sub", Email: "user@email.com", EmailVerified: true}
+	unverified := &identity.Identity{Provider: "google", Subject: "sub", Email: "user@email.com"}
+	request := &dto.UserRegistrationWithProviderRequest{Provider: "google", Credential: "credential", Username: "user_name", Password: "Password123"}
+	token := &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
+
+	cases := []struct {
+		description string
+		request     *dto.UserRegistrationWithProviderRequest
+		beforeTest  func(*identityServiceMocks)
+		expected    *dto.Token
+		err         error
+	}{
+		{
+			description: "should return error when username is not valid",
+			request:     &dto.UserRegistrationWithProviderRequest{Provider: "google", Credential: "credential", Username: "u$er", Password: "Password123"},
+			beforeTest:  func(m *identityServiceMocks) {},
+			err:         errs.ErrInvalidUsernamePattern,
+		},
+		{
+			description: "should return error when provider did not share an email",
+			request:     request,
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(&identity.Identity{Provider: "google", Subject: "sub"}, nil)
+			},
+			err: errs.ErrIdentityEmailMissing,
+		},
+		{
+			description: "should return error when provider account is already linked",
+			request:     request,
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(&model.UserIdentity{UserID: 1}, nil)
+			},
+			err: errs.ErrUserAlreadyExist,
+		},
+		{
+			description: "should email a link and return error when a user has the provider account email",
+			request:     request,
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(&model.User{ID: 1, Email: "user@email.com"}, nil)
+				m.emailPublisher.On("Enqueue", mock.Anything).Return(nil)
+			},
+			err: errs.ErrIdentityLinkPending,
+		},
+		{
+			description: "should create a verified user linked to the provider account",
+			request:     request,
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(nil, errs.ErrUserDoesNotExist)
+				m.userRepo.On("SignUp", mock.MatchedBy(func(user *model.User) bool {
+					return user.EmailVerifiedAt != nil && len(user.Identities) == 1 && user.Identities[0].Subject == "sub"
+				})).Return(&model.User{ID: 1, EmailVerifiedAt: &time.Time{}}, nil)
+				m.sessionService.On("StartSession", 1, mock.Anything, (*time.Time)(nil)).Return(token, nil)
+			},
+			expected: token,
+		},
+		{
+			description: "should send an email verification when provider did not verify the email",
+			request:     request,
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(unverified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.userRepo.On("GetByEmail", "user@email.com").Return(nil, errs.ErrUserDoesNotExist)
+				m.userRepo.On("SignUp", mock.MatchedBy(func(user *model.User) bool {
+					return user.EmailVerifiedAt == nil
+				})).Return(&model.User{ID: 1}, nil)
+				m.userService.On("SendEmailVerification", 1).Return(nil)
+				m.sessionService.On("StartSession", 1, mock.Anything, (*time.Time)(nil)).Return(token, nil)
+			},
+			expected: token,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, identityService := newIdentityServiceMocks(t)
+			tc.beforeTest(m)
+
+			res, err := identityService.SignUp(tc.request)
+
+			assert.Equal(t, tc.expected, res)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func signLinkToken(currentEmail string) string {
+	token := &dto.EmailToken{
+		Purpose:      constant.EmailTokenPurposeLink,
+		UserID:       1,
+		Email:        "user@email.com",
+		CurrentEmail: currentEmail,
+		ExpiresAt:    time.Now().Add(time.Hour),
+		Provider:     "google",
+		Subject:      "sub",
+	}
+
+	return token.Sign()
+}
+
+func TestConfirmLink(t *testing.T) {
+	test.UseTokenKey(t)
+	token := signLinkToken("user@email.com")
+
+	cases := []struct {
+		description string
+		token       string
+		beforeTest  func(*identityServiceMocks)
+		expected    error
+	}{
+		{
+			description: "should return error when token is signed with the server hash key",
+			token:       forgeEmailToken(token),
+			beforeTest:  func(m *identityServiceMocks) {},
+			expected:    errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when token is for another purpose",
+			token:       signEmailToken(constant.EmailTokenPurposeVerify, "user@email.com", "user@email.com", time.Hour),
+			beforeTest:  func(m *identityServiceMocks) {},
+			expected:    errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when email changed since the token was issued",
+			token:       signLinkToken("old@email.com"),
+			beforeTest: func(m *identityServiceMocks) {
+				m.userRepo.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@email.com"}, nil)
+			},
+			expected: errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when provider account is linked to another user",
+			token:       token,
+			beforeTest: func(m *identityServiceMocks) {
+				m.userRepo.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@email.com"}, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(&model.UserIdentity{UserID: 2}, nil)
+			},
+			expected: errs.ErrIdentityAlreadyLinked,
+		},
+		{
+			description: "should return nil when provider account is already linked to the user",
+			token:       token,
+			beforeTest: func(m *identityServiceMocks) {
+				m.userRepo.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@email.com"}, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(&model.UserIdentity{UserID: 1}, nil)
+			},
+			expected: nil,
+		},
+		{
+			description: "should link provider account to the user",
+			token:       token,
+			beforeTest: func(m *identityServiceMocks) {
+				m.userRepo.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@email.com"}, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.identityRepo.On("Create", &model.UserIdentity{UserID: 1, Provider: "google", Subject: "sub", Email: "user@email.com"}).Return(nil)
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, identityService := newIdentityServiceMocks(t)
+			tc.beforeTest(m)
+
+			err := identityService.ConfirmLink(tc.token)
+
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestGetIdentities(t *testing.T) {
+	linkedAt := time.Now()
+
+	t.Run("should return linked provider accounts", func(t *testing.T) {
+		m, identityService := newIdentityServiceMocks(t)
+		identities := []*model.UserIdentity{{Provider: "google", Email: "user@email.com"}}
+		identities[0].CreatedAt = linkedAt
+		m.identityRepo.On("GetByUserID", 1).Return(identities, nil)
+
+		res, err := identityService.GetIdentities(1)
+
+		assert.Equal(t, []*dto.UserIdentityResponse{{Provider: "google", Email: "user@email.com", LinkedAt: linkedAt}}, res)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should return error when failed to get linked provider accounts", func(t *testing.T) {
+		m, identityService := newIdentityServiceMocks(t)
+		m.identityRepo.On("GetByUserID", 1).Return(nil, errors.New("failed to get identities"))
+
+		res, err := identityService.GetIdentities(1)
+
+		assert.Nil(t, res)
+		assert.Equal(t, errors.New("failed to get identities"), err)
+	})
+}
+
+func TestLink(t *testing.T) {
+	verified := &identity.Identity{Provider: "google", Subject: "sub", Email: "other@email.com"}
+	request := &dto.LinkIdentityRequest{Provider: "google", Credential: "credential"}
+
+	cases := []struct {
+		description string
+		beforeTest  func(*identityServiceMocks)
+		expected    *dto.UserIdentityResponse
+		err         error
+	}{
+		{
+			description: "should return error when provider account is already linked",
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(&model.UserIdentity{UserID: 2}, nil)
+			},
+			err: errs.ErrIdentityAlreadyLinked,
+		},
+		{
+			description: "should return error when user already linked an account of the provider",
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.identityRepo.On("Create", mock.Anything).Return(errs.ErrIdentityAlreadyLinked)
+			},
+			err: errs.ErrIdentityAlreadyLinked,
+		},
+		{
+			description: "should link provider account even when its email differs",
+			beforeTest: func(m *identityServiceMocks) {
+				m.provider.On("Verify", "credential").Return(verified, nil)
+				m.identityRepo.On("GetByProviderSubject", "google", "sub").Return(nil, errs.ErrIdentityNotFound)
+				m.identityRepo.On("Create", &model.UserIdentity{UserID: 1, Provider: "google", Subject: "sub", Email: "other@email.com"}).Return(nil)
+			},
+			expected: &dto.UserIdentityResponse{Provider: "google", Email: "other@email.com"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, identityService := newIdentityServiceMocks(t)
+			tc.beforeTest(m)
+
+			res, err := identityService.Link(1, request)
+
+			assert.Equal(t, tc.expected, res)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestUnlink(t *testing.T) {
+	t.Run("should return error when provider account is not linked", func(t *testing.T) {
+		m, identityService := newIdentityServiceMocks(t)
+		m.identityRepo.On("Delete", 1, "google").Return(errs.ErrIdentityNotFound)
+
+		err := identityService.Unlink(1, "google")
+
+		assert.Equal(t, errs.ErrIdentityNotFound, err)
+	})
+}
@@ -2,20 +2,23 @@ package service
 
 import (
 	"errors"
-	"fmt"
 	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
 	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
 	"kedai/backend/be-kedai/internal/domain/user/cache"
 	"kedai/backend/be-kedai/internal/domain/user/dto"
 	"kedai/backend/be-kedai/internal/domain/user/model"
 	"kedai/backend/be-kedai/internal/domain/user/repository"
+	"kedai/backend/be-kedai/internal/utils/attempt"
 	"kedai/backend/be-kedai/internal/utils/credential"
-	"kedai/backend/be-kedai/internal/utils/google"
 	"kedai/backend/be-kedai/internal/utils/hash"
-	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
 	"kedai/backend/be-kedai/internal/utils/mail"
 	"kedai/backend/be-kedai/internal/utils/random"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -25,11 +28,14 @@ type UserService interface {
 	GetByUsername(username string) (*model.User, error)
 	SignUp(*dto.UserRegistrationRequest) (*dto.UserRegistrationResponse, error)
 	SignIn(*dto.UserLogin, string) (*dto.Token, error)
-	SignInWithGoogle(userLogin *dto.UserLoginWithGoogleRequest) (*dto.Token, error)
-	SignUpWithGoogle(userReg *dto.UserRegistrationWithGoogleRequest) (*dto.Token, error)
-	GetSession(userId int, token string) error
-	RenewToken(userId int, refreshToken string) (*dto.Token, error)
+	GetSession(userId int, token string) (string, error)
+	RenewToken(userId int, familyId string, refreshToken string) (*dto.Token, error)
+	RotateToken(userId int, familyId string, refreshToken string, level int) (*dto.Token, error)
 	UpdateEmail(userId int, request *dto.UpdateEmailRequest) (*dto.UpdateEmailResponse, error)
+	ConfirmEmailChange(token string) (*dto.UpdateEmailResponse, error)
+	RevertEmailChange(token string) error
+	SendEmailVerification(userId int) error
+	VerifyEmail(token string) error
 	UpdateUsername(userId int, requst *dto.UpdateUsernameRequest) (*dto.UpdateUsernameResponse, error)
 	RequestPasswordChange(request *dto.RequestPasswordChangeRequest) error
 	CompletePasswordChange(request *dto.CompletePasswordChangeRequest) error
@@ -37,29 +43,40 @@ type UserService interface {
 	CompletePasswordReset(request *dto.CompletePasswordResetRequest) error
 	ValidatePasswordChange(request *dto.RequestPasswordChangeRequest, user *model.User) error
 	SignOut(*dto.UserLogoutRequest) error
-	AdminSignIn(*dto.UserLogin) (*dto.Token, error)
 }
 
 type userServiceImpl struct {
-	repository  repository.UserRepository
-	redis       cache.UserCache
-	randomUtils random.RandomUtils
-	mailUtils   mail.MailUtils
+	repository       repository.UserRepository
+	redis            cache.UserCache
+	randomUtils      random.RandomUtils
+	mailUtils        mail.MailUtils
+	twoFactorService TwoFactorService
+	sessionService   UserSessionService
+	limiter          attempt.Limiter
+	emailPublisher   notificationService.EmailPublisher
 }
 
 type UserSConfig struct {
-	Repository  repository.UserRepository
-	Redis       cache.UserCache
-	RandomUtils random.RandomUtils
-	MailUtils   mail.MailUtils
+	Repository       repository.UserRepository
+	Redis            cache.UserCache
+	RandomUtils      random.RandomUtils
+	MailUtils        mail.MailUtils
+	TwoFactorService TwoFactorService
+	SessionService   UserSessionService
+	Limiter          attempt.Limiter
+	EmailPublisher   notificationService.EmailPublisher
 }
 
 func NewUserService(cfg *UserSConfig) UserService {
 	return &userServiceImpl{
-		repository:  cfg.Repository,
-		redis:       cfg.Redis,
-		mailUtils:   cfg.MailUtils,
-		randomUtils: cfg.RandomUtils,
+		repository:       cfg.Repository,
+		redis:            cfg.Redis,
+		mailUtils:        cfg.MailUtils,
+		randomUtils:      cfg.RandomUtils,
+		twoFactorService: cfg.TwoFactorService,
+		sessionService:   cfg.SessionService,
+		limiter:          cfg.Limiter,
+		emailPublisher:   cfg.EmailPublisher,
 	}
 }
 
@@ -91,176 +108,325 @@ func (s *userServiceImpl) SignUp(userReg *dto.UserRegistrationRequest) (*dto.Use
 		return nil, err
 	}
 
+	if err := s.sendEmailVerification(result); err != nil {
+		log.Println("failed to send email verification to user", result.ID, ":", err)
+	}
+
 	var response dto.UserRegistrationResponse
 	response.FromUser(result)
 
 	return &response, nil
 }
 
-func (s *userServiceImpl) SignUpWithGoogle(userReg *dto.UserRegistrationWithGoogleRequest) (*dto.Token, error) {
-	isValidUsername := credential.VerifyUsername(userReg.Username)
-	if !isValidUsername {
-		return nil, errs.ErrInvalidUsernamePattern
-	}
-
-	isValidPassword := credential.VerifyPassword(userReg.Password)
-	if !isValidPassword {
-		return nil, errs.ErrInvalidPasswordPattern
-	}
+func (s *userServiceImpl) SignIn(userLogin *dto.UserLogin, inputPw string) (*dto.Token, error) {
+	user := userLogin.ToUser()
+	user.Email = strings.ToLower(user.Email)
 
-	if credential.ContainsUsername(userReg.Password, userReg.Username) {
-		return nil, errs.ErrInvalidPasswordPattern
+	ipAddress := userLogin.Device.IPAddress
+	accountKey := attempt.AccountKey(constant.AttemptScopeUserLogin, user.Email)
+	ipKey := attempt.IPKey(constant.AttemptScopeUserLogin, ipAddress)
+	err := checkAttempts(s.limiter, accountKey, ipKey)
+	if err != nil {
+		return nil, err
 	}
 
-	claim, err := google.ValidateGoogleToken(userReg.Credential)
+	result, err := s.repository.SignIn(user)
 	if err != nil {
-		return nil, errs.ErrUnauthorized
+		if errors.Is(err, errs.ErrInvalidCredential) {
+			recordFailedAttempt(s.limiter, s.sessionService, 0, ipAddress, accountKey, ipKey)
+		}
+		return nil, err
 	}
 
-	result, err := s.repository.SignUp(&model.User{
-		Email:    claim.Email,
-		Username: userReg.Username,
-		Password: userReg.Password,
-	})
+	isValid := hash.ComparePassword(result.Password, inputPw)
+	if isValid {
+		resetAttempts(s.limiter, accountKey, ipKey)
 
-	if err != nil {
-		return nil, err
+		challenge, err := s.challengeTwoFactor(result.ID)
+		if err != nil || challenge != nil {
+			return challenge, err
+		}
+
+		return s.sessionService.StartSession(result.ID, &userLogin.Device, nil)
 	}
 
-	defaultLevel := 0
+	recordFailedAttempt(s.limiter, s.sessionService, result.ID, ipAddress, accountKey, ipKey)
 
-	accessToken, _ := jwttoken.GenerateAccessToken(result, defaultLevel)
-	refreshToken, _ := jwttoken.GenerateRefreshToken(result, defaultLevel)
+	return nil, errs.ErrInvalidCredential
+}
 
-	token := &dto.Token{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+// challengeTwoFactor returns a 2FA challenge in place of session tokens when
+// the user has 2FA enabled.
+func (s *userServiceImpl) challengeTwoFactor(userId int) (*dto.Token, error) {
+	if s.twoFactorService == nil {
+		return nil, nil
 	}
 
-	err = s.redis.StoreToken(result.ID, accessToken, refreshToken)
+	return s.twoFactorService.ChallengeIfEnabled(userId)
+}
+
+// GetSession checks accessToken is still valid and returns the key of the
+// session it belongs to.
+func (s *userServiceImpl) GetSession(userId int, accessToken string) (string, error) {
+	sessionKey, err := s.redis.FindToken(userId, accessToken)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return token, nil
+	if s.sessionService != nil {
+		if err := s.sessionService.Touch(sessionKey); err != nil {
+			log.Println("failed to update last seen of session of user", userId, ":", err)
+		}
+	}
 
+	return sessionKey, nil
 }
 
-func (s *userServiceImpl) SignIn(userLogin *dto.UserLogin, inputPw string) (*dto.Token, error) {
-	user := userLogin.ToUser()
-	user.Email = strings.ToLower(user.Email)
+// RenewToken rotates refreshToken within its token family. A refresh token
+// that was already rotated must have leaked, so presenting it again revokes
+// the whole family and the user has to sign in again.
+func (s *userServiceImpl) RenewToken(userId int, familyId string, refreshToken string) (*dto.Token, error) {
+	sessionKey, err := s.consumeRefreshToken(userId, familyId, refreshToken)
+	if err != nil {
+		return nil, err
+	}
 
-	result, err := s.repository.SignIn(user)
+	return s.sessionService.IssueToken(userId, sessionKey, nil)
+}
+
+// RotateToken rotates refreshToken like RenewToken for flows that change the
+// access level of the session, such as the wallet step up, so the session
+// never holds more than one live refresh token.
+func (s *userServiceImpl) RotateToken(userId int, familyId string, refreshToken string, level int) (*dto.Token, error) {
+	sessionKey, err := s.consumeRefreshToken(userId, familyId, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	isValid := hash.ComparePassword(result.Password, inputPw)
-	if isValid {
-		defaultLevel := 0
-		accessToken, _ := jwttoken.GenerateAccessToken(result, defaultLevel)
-		refreshToken, _ := jwttoken.GenerateRefreshToken(result, defaultLevel)
+	return s.sessionService.IssueTokenAtLevel(userId, sessionKey, level)
+}
 
-		token := &dto.Token{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
+func (s *userServiceImpl) consumeRefreshToken(userId int, familyId string, refreshToken string) (string, error) {
+	sessionKey, err := s.redis.ConsumeRefreshToken(userId, refreshToken)
+	if errors.Is(err, errs.ErrRefreshTokenReused) {
+		if familyId == "" {
+			familyId = sessionKey
 		}
+		log.Println("refresh token reuse detected for user", userId, ", revoking its token family")
 
-		err := s.redis.StoreToken(result.ID, accessToken, refreshToken)
-		if err != nil {
-			return nil, err
+		if familyId != "" {
+			if err := s.sessionService.EndSession(userId, familyId); err != nil {
+				return "", err
+			}
 		}
 
-		return token, nil
+		return "", errs.ErrRefreshTokenReused
+	}
+	if errors.Is(err, redis.Nil) {
+		return "", errs.ErrExpiredToken
+	}
+	if err != nil {
+		return "", err
 	}
 
-	return nil, errs.ErrInvalidCredential
+	return sessionKey, nil
 }
 
-func (s *userServiceImpl) SignInWithGoogle(userLogin *dto.UserLoginWithGoogleRequest) (*dto.Token, error) {
-	claim, err := google.ValidateGoogleToken(userLogin.Credential)
+// UpdateEmail sends a confirmation link to the requested address. The email
+// of the user only changes once that link is followed.
+func (s *userServiceImpl) UpdateEmail(userId int, request *dto.UpdateEmailRequest) (*dto.UpdateEmailResponse, error) {
+	email := strings.ToLower(request.Email)
+
+	_, err := s.repository.GetByEmail(email)
+	if err == nil {
+		return nil, errs.ErrEmailUsed
+	}
+
+	if !errors.Is(err, errs.ErrUserDoesNotExist) {
+		return nil, err
+	}
+
+	user, err := s.repository.GetByID(userId)
 	if err != nil {
-		return nil, errs.ErrUnauthorized
+		return nil, err
 	}
 
-	result, err := s.repository.SignIn(&model.User{Email: claim.Email})
+	err = s.sendEmailLink(user, email, mail.TemplateConfirmEmailChange, &dto.EmailToken{
+		Purpose:      constant.EmailTokenPurposeChange,
+		UserID:       user.ID,
+		Email:        email,
+		CurrentEmail: user.Email,
+		ExpiresAt:    time.Now().Add(constant.EmailVerificationAge),
+	}, config.GetEnv("CONFIRM_EMAIL_URL", "/confirm-email?token="))
 	if err != nil {
 		return nil, err
 	}
 
-	defaultLevel := 0
-	accessToken, _ := jwttoken.GenerateAccessToken(result, defaultLevel)
-	refreshToken, _ := jwttoken.GenerateRefreshToken(result, defaultLevel)
+	return &dto.UpdateEmailResponse{Email: email}, nil
+}
 
-	token := &dto.Token{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+// ConfirmEmailChange applies the email change token was sent for and lets
+// the previous address revert it for EmailRevertAge.
+func (s *userServiceImpl) ConfirmEmailChange(token string) (*dto.UpdateEmailResponse, error) {
+	emailToken, err := dto.ParseEmailToken(token, constant.EmailTokenPurposeChange)
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.redis.StoreToken(result.ID, accessToken, refreshToken)
+	user, err := s.getEmailTokenUser(emailToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return token, nil
-}
-
-func (s *userServiceImpl) GetSession(userId int, accessToken string) error {
-	return s.redis.FindToken(userId, accessToken)
-}
+	_, err = s.repository.GetByEmail(emailToken.Email)
+	if err == nil {
+		return nil, errs.ErrEmailUsed
+	}
 
-func (s *userServiceImpl) RenewToken(userId int, refreshToken string) (*dto.Token, error) {
-	err := s.redis.FindToken(userId, refreshToken)
-	if errors.Is(err, redis.Nil) {
-		return nil, errs.ErrExpiredToken
+	if !errors.Is(err, errs.ErrUserDoesNotExist) {
+		return nil, err
 	}
+
+	res, err := s.repository.UpdateEmail(user.ID, emailToken.Email)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.redis.DeleteToken(fmt.Sprintf("user_%d:%s", userId, refreshToken))
+	err = s.redis.DeleteAllByID(user.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	defaultLevel := 0
-	newAccessToken, _ := jwttoken.GenerateAccessToken(&model.User{ID: userId}, defaultLevel)
-	newRefreshToken, _ := jwttoken.GenerateRefreshToken(&model.User{ID: userId}, defaultLevel)
+	err = s.sendEmailLink(user, user.Email, mail.TemplateEmailChanged, &dto.EmailToken{
+		Purpose:      constant.EmailTokenPurposeRevert,
+		UserID:       user.ID,
+		Email:        user.Email,
+		CurrentEmail: emailToken.Email,
+		ExpiresAt:    time.Now().Add(constant.EmailRevertAge),
+	}, config.GetEnv("REVERT_EMAIL_URL", "/revert-email?token="))
+	if err != nil {
+		log.Println("failed to send email change alert to user", user.ID, ":", err)
+	}
+
+	var response dto.UpdateEmailResponse
+	response.FromUser(res)
+
+	return &response, nil
+}
 
-	err = s.redis.StoreToken(userId, newAccessToken, newRefreshToken)
+// RevertEmailChange restores the address token was sent to. Whoever changed
+// it may also know the password, so the user is signed out everywhere and
+// sent a password reset link.
+func (s *userServiceImpl) RevertEmailChange(token string) error {
+	emailToken, err := dto.ParseEmailToken(token, constant.EmailTokenPurposeRevert)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	user, err := s.getEmailTokenUser(emailToken)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.repository.RevertEmail(user.ID, emailToken.Email)
+	if err != nil {
+		return err
+	}
+
+	err = s.redis.DeleteAllByID(user.ID)
+	if err != nil {
+		return err
 	}
 
-	token := dto.Token{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
+	err = s.RequestPasswordReset(&dto.RequestPasswordResetRequest{Email: res.Email})
+	if err != nil {
+		log.Println("failed to send password reset after email revert to user", user.ID, ":", err)
 	}
 
-	return &token, nil
+	return nil
 }
 
-func (s *userServiceImpl) UpdateEmail(userId int, request *dto.UpdateEmailRequest) (*dto.UpdateEmailResponse, error) {
-	email := strings.ToLower(request.Email)
+// SendEmailVerification sends the user a new verification link.
+func (s *userServiceImpl) SendEmailVerification(userId int) error {
+	user, err := s.repository.GetByID(userId)
+	if err != nil {
+		return err
+	}
 
-	_, err := s.repository.GetByEmail(email)
-	if err == nil {
-		return nil, errs.ErrEmailUsed
+	if user.EmailVerifiedAt != nil {
+		return errs.ErrEmailAlreadyVerified
 	}
 
-	if !errors.Is(err, errs.ErrUserDoesNotExist) {
-		return nil, err
+	return s.sendEmailVerification(user)
+}
+
+func (s *userServiceImpl) VerifyEmail(token string) error {
+	emailToken, err := dto.ParseEmailToken(token, constant.EmailTokenPurposeVerify)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.getEmailTokenUser(emailToken)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
 	}
 
-	res, err := s.repository.UpdateEmail(userId, email)
+	return s.repository.MarkEmailVerified(user.ID, emailToken.Email)
+}
+
+// getEmailTokenUser returns the user token was sent for, as long as their
+// email has not changed since.
+func (s *userServiceImpl) getEmailTokenUser(token *dto.EmailToken) (*model.User, error) {
+	user, err := s.repository.GetByID(token.UserID)
 	if err != nil {
+		if errors.Is(err, errs.ErrUserDoesNotExist) {
+			return nil, errs.ErrInvalidEmailToken
+		}
 		return nil, err
 	}
 
-	var response dto.UpdateEmailResponse
-	response.FromUser(res)
+	if user.Email != token.CurrentEmail {
+		return nil, errs.ErrInvalidEmailToken
+	}
 
-	return &response, nil
+	return user, nil
+}
+
+func (s *userServiceImpl) sendEmailVerification(user *model.User) error {
+	return s.sendEmailLink(user, user.Email, mail.TemplateVerifyEmail, &dto.EmailToken{
+		Purpose:      constant.EmailTokenPurposeVerify,
+		UserID:       user.ID,
+		Email:        user.Email,
+		CurrentEmail: user.Email,
+		ExpiresAt:    time.Now().Add(constant.EmailVerificationAge),
+	}, config.GetEnv("VERIFY_EMAIL_URL", "/verify-email?token="))
+}
+
+func (s *userServiceImpl) sendEmailLink(user *model.User, to string, template string, token *dto.EmailToken, path string) error {
+	if s.emailPublisher == nil {
+		return nil
+	}
+
+	data := &mail.EmailLinkEmailData{
+		Username:  user.Username,
+		URL:       config.GetEnv("FRONTEND_URL", "http://localhost:3000") + path + token.Sign(),
+		ExpiresAt: token.ExpiresAt.Format("2 January 2006 15:04 MST"),
+	}
+	if template == mail.TemplateEmailChanged {
+		data.NewEmail = token.CurrentEmail
+	}
+
+	return s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+		To:       to,
+		UserID:   user.ID,
+		Category: constant.NotificationCategorySecurity,
+		Template: template,
+		Data:     data,
+	})
 }
 
 func (s *userServiceImpl) UpdateUsername(userId int, request *dto.UpdateUsernameRequest) (*dto.UpdateUsernameResponse, error) {
@@ -283,10 +449,25 @@ func (s *userServiceImpl) UpdateUsername(userId int, request *dto.UpdateUsername
 }
 
 func (s *userServiceImpl) SignOut(request *dto.UserLogoutRequest) error {
-	return s.redis.DeleteRefreshTokenAndAccessToken(request.UserId, request.RefreshToken, request.AccessToken)
+	err := s.redis.DeleteRefreshTokenAndAccessToken(request.UserId, request.RefreshToken, request.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	if request.SessionKey == "" {
+		return nil
+	}
+
+	return s.sessionService.EndSession(request.UserId, request.SessionKey)
 }
 
 func (s *userServiceImpl) RequestPasswordChange(request *dto.RequestPasswordChangeRequest) error {
+	accountKey := attempt.AccountKey(constant.AttemptScopePasswordChange, request.UserId)
+	err := checkAttempts(s.limiter, accountKey)
+	if err != nil {
+		return err
+	}
+
 	user, err := s.repository.GetByID(request.UserId)
 	if err != nil {
 		return err
@@ -294,8 +475,10 @@ func (s *userServiceImpl) RequestPasswordChange(request *dto.RequestPasswordChan
 
 	isValidPassword := hash.ComparePassword(user.Password, request.CurrentPassword)
 	if !isValidPassword {
+		recordFailedAttempt(s.limiter, s.sessionService, user.ID, request.IPAddress, accountKey)
 		return errs.ErrInvalidCredential
 	}
+	resetAttempts(s.limiter, accountKey)
 
 	err = s.ValidatePasswordChange(request, user)
 	if err != nil {
@@ -337,20 +520,33 @@ func (s *userServiceImpl) ValidatePasswordChange(request *dto.RequestPasswordCha
 }
 
 func (s *userServiceImpl) CompletePasswordChange(request *dto.CompletePasswordChangeRequest) error {
+	accountKey := attempt.AccountKey(constant.AttemptScopePasswordChange, request.UserId)
+	err := checkAttempts(s.limiter, accountKey)
+	if err != nil {
+		return err
+	}
+
 	newPassword, verifcationCode, err := s.redis.FindUserPasswordAndVerificationCode(request.UserId)
 	if err != nil {
 		return err
 	}
 
 	if verifcationCode != request.VerificationCode {
+		recordFailedAttempt(s.limiter, s.sessionService, request.UserId, request.IPAddress, accountKey)
 		return errs.ErrIncorrectVerificationCode
 	}
+	resetAttempts(s.limiter, accountKey)
 
 	_, err = s.repository.UpdatePassword(request.UserId, newPassword)
 	if err != nil {
 		return err
 	}
 
+	err = s.redis.DeleteAllByID(request.UserId)
+	if err != nil {
+		return err
+	}
+
 	_ = s.redis.DeleteUserPasswordAndVerificationCode(request.UserId)
 
 	return nil
@@ -376,9 +572,22 @@ func (s *userServiceImpl) RequestPasswordReset(request *dto.RequestPasswordReset
 	return nil
 }
 
+// CompletePasswordReset sets the new password of the user the reset token
+// was sent to. The request names their email as well, so that guessing
+// tokens is limited by account and not only by IP.
 func (s *userServiceImpl) CompletePasswordReset(request *dto.CompletePasswordResetRequest) error {
+	accountKey := attempt.AccountKey(constant.AttemptScopePasswordReset, strings.ToLower(request.Email))
+	ipKey := attempt.IPKey(constant.AttemptScopePasswordReset, request.IPAddress)
+	err := checkAttempts(s.limiter, accountKey, ipKey)
+	if err != nil {
+		return err
+	}
+
 	userId, err := s.redis.FindResetPasswordToken(request.Token)
 	if err != nil {
+		if errors.Is(err, errs.ErrResetPasswordTokenNotFound) {
+			recordFailedAttempt(s.limiter, nil, 0, request.IPAddress, accountKey, ipKey)
+		}
 		return err
 	}
 
@@ -387,6 +596,12 @@ func (s *userServiceImpl) CompletePasswordReset(request *dto.CompletePasswordRes
 		return err
 	}
 
+	if !strings.EqualFold(user.Email, request.Email) {
+		recordFailedAttempt(s.limiter, nil, 0, request.IPAddress, accountKey, ipKey)
+		return errs.ErrResetPasswordTokenNotFound
+	}
+	resetAttempts(s.limiter, accountKey, ipKey)
+
 	err = s.ValidatePasswordChange(&dto.RequestPasswordChangeRequest{
 		UserId:      userId,
 		NewPassword: request.NewPassword,
@@ -400,40 +615,12 @@ func (s *userServiceImpl) CompletePasswordReset(request *dto.CompletePasswordRes
 		return err
 	}
 
+	err = s.redis.DeleteAllByID(userId)
+	if err != nil {
+		return err
+	}
+
 	_ = s.redis.DeleteResetPasswordToken(request.Token)
 
 	return nil
 }
-
-func (s *userServiceImpl) AdminSignIn(userLogin *dto.UserLogin) (*dto.Token, error) {
-
-	admin := userLogin.ToUser()
-
-	isValid := hash.ComparePassword(config.AdminPassword, admin.Password)
-
-	if admin.Email == config.AdminEmail && isValid {
-		result := &model.User{
-			ID:       0,
-			Email:    admin.Email,
-			Username: "admin",
-		}
-
-		defaultLevel := 0
-		accessToken, _ := jwttoken.GenerateAccessToken(result, defaultLevel)
-		refreshToken, _ := jwttoken.GenerateRefreshToken(result, defaultLevel)
-
-		token := &dto.Token{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-		}
-
-		errToken := s.redis.StoreToken(result.ID, accessToken, refreshToken)
-		if errToken != nil {
-			return nil, errToken
-		}
-
-		return token, nil
-	}
-
-	return nil, errs.ErrInvalidCredential
-}
@@ -1,14 +1,21 @@
 package service_test
 
 import (
+	"encoding/base64"
 	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
 	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
 	"kedai/backend/be-kedai/internal/domain/user/dto"
 	"kedai/backend/be-kedai/internal/domain/user/model"
 	"kedai/backend/be-kedai/internal/domain/user/service"
 	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/internal/utils/test"
 	mocks "kedai/backend/be-kedai/mocks"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -176,6 +183,147 @@ func TestSignIn(t *testing.T) {
 		assert.Error(t, errs.ErrInvalidCredential, err)
 	})
 
+	t.Run("should return two factor challenge when user has two factor enabled", func(t *testing.T) {
+		hashedPw, _ := hash.HashAndSalt("password")
+		challenge := &dto.Token{TwoFactorToken: "challenge"}
+		dto := &dto.UserLogin{
+			Email:    "user@mail.com",
+			Password: "password",
+		}
+		mockRepo := new(mocks.UserRepository)
+		mockTwoFactorService := new(mocks.TwoFactorService)
+		service := service.NewUserService(&service.UserSConfig{
+			Repository:       mockRepo,
+			TwoFactorService: mockTwoFactorService,
+		})
+		mockRepo.On("SignIn", &model.User{Email: "user@mail.com", Password: "password"}).Return(&model.User{ID: 1, Email: "user@mail.com", Password: hashedPw}, nil)
+		mockTwoFactorService.On("ChallengeIfEnabled", 1).Return(challenge, nil)
+
+		result, err := service.SignIn(dto, dto.Password)
+
+		assert.NoError(t, err)
+		assert.Equal(t, challenge, result)
+	})
+
+	t.Run("should start a session for the device when credential is valid", func(t *testing.T) {
+		hashedPw, _ := hash.HashAndSalt("password")
+		token := &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
+		dto := &dto.UserLogin{
+			Email:    "user@mail.com",
+			Password: "password",
+			Device:   dto.SessionDevice{DeviceID: "device"},
+		}
+		mockRepo := new(mocks.UserRepository)
+		mockSessionService := mocks.NewUserSessionService(t)
+		service := service.NewUserService(&service.UserSConfig{
+			Repository:     mockRepo,
+			SessionService: mockSessionService,
+		})
+		mockRepo.On("SignIn", &model.User{Email: "user@mail.com", Password: "password"}).Return(&model.User{ID: 1, Email: "user@mail.com", Password: hashedPw}, nil)
+		mockSessionService.On("StartSession", 1, &dto.Device, (*time.Time)(nil)).Return(token, nil)
+
+		result, err := service.SignIn(dto, dto.Password)
+
+		assert.NoError(t, err)
+		assert.Equal(t, token, result)
+	})
+
+	t.Run("should return error when sign in attempts are throttled", func(t *testing.T) {
+		dto := &dto.UserLogin{
+			Email:    "User@mail.com",
+			Password: "password",
+			Device:   dto.SessionDevice{IPAddress: "127.0.0.1"},
+		}
+		mockLimiter := mocks.NewLimiter(t)
+		service := service.NewUserService(&service.UserSConfig{
+			Limiter: mockLimiter,
+		})
+		mockLimiter.On("Check", "user_login:account:user@mail.com", "user_login:ip:127.0.0.1").Return(errs.ErrTooManyAttempts)
+
+		result, err := service.SignIn(dto, dto.Password)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, errs.ErrTooManyAttempts)
+	})
+
+	t.Run("should alert user when failed sign in locks the account", func(t *testing.T) {
+		hashedPw, _ := hash.HashAndSalt("password")
+		dto := &dto.UserLogin{
+			Email:    "user@mail.com",
+			Password: "password1",
+			Device:   dto.SessionDevice{IPAddress: "127.0.0.1"},
+		}
+		mockRepo := new(mocks.UserRepository)
+		mockLimiter := mocks.NewLimiter(t)
+		mockSessionService := mocks.NewUserSessionService(t)
+		service := service.NewUserService(&service.UserSConfig{
+			Repository:     mockRepo,
+			SessionService: mockSessionService,
+			Limiter:        mockLimiter,
+		})
+		mockLimiter.On("Check", "user_login:account:user@mail.com", "user_login:ip:127.0.0.1").Return(nil)
+		mockRepo.On("SignIn", &model.User{Email: "user@mail.com", Password: "password1"}).Return(&model.User{ID: 1, Email: "user@mail.com", Password: hashedPw}, nil)
+		mockLimiter.On("Fail", "user_login:account:user@mail.com").Return(true, nil)
+		mockLimiter.On("Fail", "user_login:ip:127.0.0.1").Return(false, nil)
+		mockSessionService.On("NotifyLockout", 1, "127.0.0.1").Return()
+
+		result, err := service.SignIn(dto, dto.Password)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, errs.ErrInvalidCredential)
+	})
+
+	t.Run("should count failed sign in to an unknown email without alerting", func(t *testing.T) {
+		dto := &dto.UserLogin{
+			Email:    "unknown@mail.com",
+			Password: "password",
+			Device:   dto.SessionDevice{IPAddress: "127.0.0.1"},
+		}
+		mockRepo := new(mocks.UserRepository)
+		mockLimiter := mocks.NewLimiter(t)
+		service := service.NewUserService(&service.UserSConfig{
+			Repository: mockRepo,
+			Limiter:    mockLimiter,
+		})
+		mockLimiter.On("Check", "user_login:account:unknown@mail.com", "user_login:ip:127.0.0.1").Return(nil)
+		mockRepo.On("SignIn", &model.User{Email: "unknown@mail.com", Password: "password"}).Return(nil, errs.ErrInvalidCredential)
+		mockLimiter.On("Fail", "user_login:account:unknown@mail.com").Return(true, nil)
+		mockLimiter.On("Fail", "user_login:ip:127.0.0.1").Return(false, nil)
+
+		result, err := service.SignIn(dto, dto.Password)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, errs.ErrInvalidCredential)
+	})
+
+	t.Run("should reset the account attempts and release the IP attempt when credential is valid", func(t *testing.T) {
+		hashedPw, _ := hash.HashAndSalt("password")
+		token := &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
+		dto := &dto.UserLogin{
+			Email:    "user@mail.com",
+			Password: "password",
+			Device:   dto.SessionDevice{IPAddress: "127.0.0.1"},
+		}
+		mockRepo := new(mocks.UserRepository)
+		mockLimiter := mocks.NewLimiter(t)
+		mockSessionService := mocks.NewUserSessionService(t)
+		service := service.NewUserService(&service.UserSConfig{
+			Repository:     mockRepo,
+			SessionService: mockSessionService,
+			Limiter:        mockLimiter,
+		})
+		mockLimiter.On("Check", "user_login:account:user@mail.com", "user_login:ip:127.0.0.1").Return(nil)
+		mockRepo.On("SignIn", &model.User{Email: "user@mail.com", Password: "password"}).Return(&model.User{ID: 1, Email: "user@mail.com", Password: hashedPw}, nil)
+		mockLimiter.On("Reset", "user_login:account:user@mail.com").Return(nil)
+		mockLimiter.On("Release", "user_login:ip:127.0.0.1").Return(nil)
+		mockSessionService.On("StartSession", 1, &dto.Device, (*time.Time)(nil)).Return(token, nil)
+
+		result, err := service.SignIn(dto, dto.Password)
+
+		assert.NoError(t, err)
+		assert.Equal(t, token, result)
+	})
+
 	type input struct {
 		user *model.User
 		dto  *dto.UserLogin
@@ -321,13 +469,15 @@ func TestGetByID(t *testing.T) {
 
 func TestGetSession(t *testing.T) {
 	type input struct {
-		userId int
-		token  string
-		err    error
+		userId     int
+		token      string
+		sessionKey string
+		err        error
 	}
 
 	type expected struct {
-		err error
+		sessionKey string
+		err        error
 	}
 
 	type cases struct {
@@ -338,14 +488,16 @@ func TestGetSession(t *testing.T) {
 
 	for _, tc := range []cases{
 		{
-			description: "should return nil error when session available",
+			description: "should return session key when session available",
 			input: input{
-				userId: 1,
-				token:  "token",
-				err:    nil,
+				userId:     1,
+				token:      "token",
+				sessionKey: "session",
+				err:        nil,
 			},
 			expected: expected{
-				err: nil,
+				sessionKey: "session",
+				err:        nil,
 			},
 		},
 		{
@@ -362,14 +514,20 @@ func TestGetSession(t *testing.T) {
 	} {
 		t.Run(tc.description, func(t *testing.T) {
 			mockRedis := new(mocks.UserCache)
+			mockSessionService := mocks.NewUserSessionService(t)
 			service := service.NewUserService(&service.UserSConfig{
-				Redis: mockRedis,
+				Redis:          mockRedis,
+				SessionService: mockSessionService,
 			})
-			mockRedis.On("FindToken", tc.input.userId, tc.input.token).Return(tc.expected.err)
+			mockRedis.On("FindToken", tc.input.userId, tc.input.token).Return(tc.input.sessionKey, tc.input.err)
+			if tc.input.err == nil {
+				mockSessionService.On("Touch", tc.input.sessionKey).Return(nil)
+			}
 
-			result := service.GetSession(tc.input.userId, tc.input.token)
+			sessionKey, err := service.GetSession(tc.input.userId, tc.input.token)
 
-			assert.Equal(t, tc.expected.err, result)
+			assert.Equal(t, tc.expected.sessionKey, sessionKey)
+			assert.Equal(t, tc.expected.err, err)
 		})
 	}
 }
@@ -377,8 +535,9 @@ func TestGetSession(t *testing.T) {
 func TestRenewToken(t *testing.T) {
 	type input struct {
 		userId       int
+		familyId     string
 		refreshToken string
-		beforeTest   func(*mocks.UserCache)
+		beforeTest   func(*mocks.UserCache, *mocks.UserSessionService)
 	}
 	type expected struct {
 		token *dto.Token
@@ -394,9 +553,10 @@ func TestRenewToken(t *testing.T) {
 			description: "should return error when refresh token is expired or does not exist",
 			input: input{
 				userId:       1,
+				familyId:     "session",
 				refreshToken: "token",
-				beforeTest: func(uc *mocks.UserCache) {
-					uc.On("FindToken", 1, "token").Return(redis.Nil)
+				beforeTest: func(uc *mocks.UserCache, uss *mocks.UserSessionService) {
+					uc.On("ConsumeRefreshToken", 1, "token").Return("", redis.Nil)
 				},
 			},
 			expected: expected{
@@ -408,9 +568,10 @@ func TestRenewToken(t *testing.T) {
 			description: "should return error when failed to fetch token from redis",
 			input: input{
 				userId:       1,
+				familyId:     "session",
 				refreshToken: "token",
-				beforeTest: func(uc *mocks.UserCache) {
-					uc.On("FindToken", 1, "token").Return(errors.New("failed to check token"))
+				beforeTest: func(uc *mocks.UserCache, uss *mocks.UserSessionService) {
+					uc.On("ConsumeRefreshToken", 1, "token").Return("", errors.New("failed to check token"))
 				},
 			},
 			expected: expected{
@@ -419,29 +580,61 @@ func TestRenewToken(t *testing.T) {
 			},
 		},
 		{
-			description: "should return error when failed to store renewed tokens",
+			description: "should revoke the token family when refresh token was already used",
+			input: input{
+				userId:       1,
+				familyId:     "session",
+				refreshToken: "token",
+				beforeTest: func(uc *mocks.UserCache, uss *mocks.UserSessionService) {
+					uc.On("ConsumeRefreshToken", 1, "token").Return("session", errs.ErrRefreshTokenReused)
+					uss.On("EndSession", 1, "session").Return(nil)
+				},
+			},
+			expected: expected{
+				token: nil,
+				err:   errs.ErrRefreshTokenReused,
+			},
+		},
+		{
+			description: "should revoke the session of a reused refresh token without family id",
+			input: input{
+				userId:       1,
+				refreshToken: "token",
+				beforeTest: func(uc *mocks.UserCache, uss *mocks.UserSessionService) {
+					uc.On("ConsumeRefreshToken", 1, "token").Return("session", errs.ErrRefreshTokenReused)
+					uss.On("EndSession", 1, "session").Return(nil)
+				},
+			},
+			expected: expected{
+				token: nil,
+				err:   errs.ErrRefreshTokenReused,
+			},
+		},
+		{
+			description: "should return error when failed to revoke the token family",
 			input: input{
 				userId:       1,
+				familyId:     "session",
 				refreshToken: "token",
-				beforeTest: func(uc *mocks.UserCache) {
-					uc.On("FindToken", 1, "token").Return(nil)
-					uc.On("DeleteToken", "user_1:token").Return(errors.New("failed to delete token"))
+				beforeTest: func(uc *mocks.UserCache, uss *mocks.UserSessionService) {
+					uc.On("ConsumeRefreshToken", 1, "token").Return("session", errs.ErrRefreshTokenReused)
+					uss.On("EndSession", 1, "session").Return(errors.New("failed to revoke"))
 				},
 			},
 			expected: expected{
 				token: nil,
-				err:   errors.New("failed to delete token"),
+				err:   errors.New("failed to revoke"),
 			},
 		},
 		{
 			description: "should return error when failed to store renewed tokens",
 			input: input{
 				userId:       1,
+				familyId:     "session",
 				refreshToken: "token",
-				beforeTest: func(uc *mocks.UserCache) {
-					uc.On("FindToken", 1, "token").Return(nil)
-					uc.On("DeleteToken", "user_1:token").Return(nil)
-					uc.On("StoreToken", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("failed to store token"))
+				beforeTest: func(uc *mocks.UserCache, uss *mocks.UserSessionService) {
+					uc.On("ConsumeRefreshToken", 1, "token").Return("session", nil)
+					uss.On("IssueToken", 1, "session", (*time.Time)(nil)).Return(nil, errors.New("failed to store token"))
 				},
 			},
 			expected: expected{
@@ -449,33 +642,90 @@ func TestRenewToken(t *testing.T) {
 				err:   errors.New("failed to store token"),
 			},
 		},
+		{
+			description: "should return new tokens in the same family when refresh token is valid",
+			input: input{
+				userId:       1,
+				familyId:     "session",
+				refreshToken: "token",
+				beforeTest: func(uc *mocks.UserCache, uss *mocks.UserSessionService) {
+					uc.On("ConsumeRefreshToken", 1, "token").Return("session", nil)
+					uss.On("IssueToken", 1, "session", (*time.Time)(nil)).Return(&dto.Token{AccessToken: "access", RefreshToken: "refresh"}, nil)
+				},
+			},
+			expected: expected{
+				token: &dto.Token{AccessToken: "access", RefreshToken: "refresh"},
+				err:   nil,
+			},
+		},
 	}
 
 	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			userCache := mocks.NewUserCache(t)
+			sessionService := mocks.NewUserSessionService(t)
+			tc.beforeTest(userCache, sessionService)
+			userService := service.NewUserService(&service.UserSConfig{
+				Redis:          userCache,
+				SessionService: sessionService,
+			})
+
+			actualToken, actualErr := userService.RenewToken(tc.input.userId, tc.input.familyId, tc.input.refreshToken)
+
+			assert.Equal(t, tc.expected.token, actualToken)
+			assert.Equal(t, tc.expected.err, actualErr)
+		})
+	}
+}
+
+func TestRotateToken(t *testing.T) {
+	t.Run("should consume the refresh token and issue tokens at the requested level", func(t *testing.T) {
+		token := &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
 		userCache := mocks.NewUserCache(t)
-		tc.beforeTest(userCache)
+		sessionService := mocks.NewUserSessionService(t)
+		userCache.On("ConsumeRefreshToken", 1, "token").Return("session", nil)
+		sessionService.On("IssueTokenAtLevel", 1, "session", 1).Return(token, nil)
 		userService := service.NewUserService(&service.UserSConfig{
-			Redis: userCache,
+			Redis:          userCache,
+			SessionService: sessionService,
 		})
 
-		actualToken, actualErr := userService.RenewToken(tc.input.userId, tc.input.refreshToken)
+		actualToken, actualErr := userService.RotateToken(1, "session", "token", 1)
 
-		assert.Equal(t, tc.expected.token, actualToken)
-		assert.Equal(t, tc.expected.err, actualErr)
-	}
+		assert.Equal(t, token, actualToken)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should revoke the token family when refresh token was already used", func(t *testing.T) {
+		userCache := mocks.NewUserCache(t)
+		sessionService := mocks.NewUserSessionService(t)
+		userCache.On("ConsumeRefreshToken", 1, "token").Return("session", errs.ErrRefreshTokenReused)
+		sessionService.On("EndSession", 1, "session").Return(nil)
+		userService := service.NewUserService(&service.UserSConfig{
+			Redis:          userCache,
+			SessionService: sessionService,
+		})
+
+		actualToken, actualErr := userService.RotateToken(1, "session", "token", 1)
+
+		assert.Nil(t, actualToken)
+		assert.Equal(t, errs.ErrRefreshTokenReused, actualErr)
+	})
 }
 
 func TestUpdateEmail(t *testing.T) {
 	type input struct {
 		userId     int
 		request    *dto.UpdateEmailRequest
-		beforeTest func(*mocks.UserRepository)
+		beforeTest func(*mocks.UserRepository, *mocks.EmailPublisher)
 	}
 	type expected struct {
 		res *dto.UpdateEmailResponse
 		err error
 	}
 
+	user := &model.User{ID: 1, Email: "old.email@email.com", Username: "user"}
+
 	cases := []struct {
 		description string
 		input
@@ -488,7 +738,7 @@ func TestUpdateEmail(t *testing.T) {
 				request: &dto.UpdateEmailRequest{
 					Email: "used.email@email.com",
 				},
-				beforeTest: func(ur *mocks.UserRepository) {
+				beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
 					ur.On("GetByEmail", "used.email@email.com").Return(&model.User{Email: "used.email@email.com"}, nil)
 				},
 			},
@@ -504,7 +754,7 @@ func TestUpdateEmail(t *testing.T) {
 				request: &dto.UpdateEmailRequest{
 					Email: "used.email@email.com",
 				},
-				beforeTest: func(ur *mocks.UserRepository) {
+				beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
 					ur.On("GetByEmail", "used.email@email.com").Return(nil, errors.New("failed to check email"))
 				},
 			},
@@ -514,32 +764,53 @@ func TestUpdateEmail(t *testing.T) {
 			},
 		},
 		{
-			description: "should return error when failed to update email",
+			description: "should return error when failed to get user",
 			input: input{
 				userId: 1,
 				request: &dto.UpdateEmailRequest{
 					Email: "new.email@email.com",
 				},
-				beforeTest: func(ur *mocks.UserRepository) {
+				beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
 					ur.On("GetByEmail", "new.email@email.com").Return(nil, errs.ErrUserDoesNotExist)
-					ur.On("UpdateEmail", 1, "new.email@email.com").Return(nil, errors.New("failed to update email"))
+					ur.On("GetByID", 1).Return(nil, errors.New("failed to get user"))
 				},
 			},
 			expected: expected{
 				res: nil,
-				err: errors.New("failed to update email"),
+				err: errors.New("failed to get user"),
 			},
 		},
 		{
-			description: "should return updated email when update email successed",
+			description: "should return error when failed to send confirmation link",
 			input: input{
 				userId: 1,
 				request: &dto.UpdateEmailRequest{
 					Email: "new.email@email.com",
 				},
-				beforeTest: func(ur *mocks.UserRepository) {
+				beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+					ur.On("GetByEmail", "new.email@email.com").Return(nil, errs.ErrUserDoesNotExist)
+					ur.On("GetByID", 1).Return(user, nil)
+					ep.On("Enqueue", mock.Anything).Return(errors.New("failed to enqueue email"))
+				},
+			},
+			expected: expected{
+				res: nil,
+				err: errors.New("failed to enqueue email"),
+			},
+		},
+		{
+			description: "should send confirmation link to the new email without changing it",
+			input: input{
+				userId: 1,
+				request: &dto.UpdateEmailRequest{
+					Email: "New.Email@email.com",
+				},
+				beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
 					ur.On("GetByEmail", "new.email@email.com").Return(nil, errs.ErrUserDoesNotExist)
-					ur.On("UpdateEmail", 1, "new.email@email.com").Return(&model.User{Email: "new.email@email.com"}, nil)
+					ur.On("GetByID", 1).Return(user, nil)
+					ep.On("Enqueue", mock.MatchedBy(func(req *notificationDto.EmailRequest) bool {
+						return req.To == "new.email@email.com" && req.Template == mail.TemplateConfirmEmailChange
+					})).Return(nil)
 				},
 			},
 			expected: expected{
@@ -554,9 +825,11 @@ func TestUpdateEmail(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.description, func(t *testing.T) {
 			userRepo := mocks.NewUserRepository(t)
-			tc.beforeTest(userRepo)
+			emailPublisher := mocks.NewEmailPublisher(t)
+			tc.beforeTest(userRepo, emailPublisher)
 			userService := service.NewUserService(&service.UserSConfig{
-				Repository: userRepo,
+				Repository:     userRepo,
+				EmailPublisher: emailPublisher,
 			})
 
 			res, err := userService.UpdateEmail(tc.input.userId, tc.input.request)
@@ -567,6 +840,414 @@ func TestUpdateEmail(t *testing.T) {
 	}
 }
 
+func signEmailToken(purpose string, email string, currentEmail string, expiresIn time.Duration) string {
+	token := &dto.EmailToken{
+		Purpose:      purpose,
+		UserID:       1,
+		Email:        email,
+		CurrentEmail: currentEmail,
+		ExpiresAt:    time.Now().Add(expiresIn),
+	}
+
+	return token.Sign()
+}
+
+// forgeEmailToken re-signs token with the public default hash key, the way
+// links were signed before they had a key of their own.
+func forgeEmailToken(token string) string {
+	encoded, _, _ := strings.Cut(token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(encoded)
+
+	return encoded + "." + hash.HashSHA256(string(payload))
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	test.UseTokenKey(t)
+	type input struct {
+		token      string
+		beforeTest func(*mocks.UserRepository, *mocks.UserCache, *mocks.EmailPublisher)
+	}
+	type expected struct {
+		res *dto.UpdateEmailResponse
+		err error
+	}
+
+	oldEmail := "old.email@email.com"
+	newEmail := "new.email@email.com"
+	token := signEmailToken(constant.EmailTokenPurposeChange, newEmail, oldEmail, time.Hour)
+	user := &model.User{ID: 1, Email: oldEmail, Username: "user"}
+
+	cases := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when token is tampered with",
+			input: input{
+				token:      token + "0",
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {},
+			},
+			expected: expected{
+				err: errs.ErrInvalidEmailToken,
+			},
+		},
+		{
+			description: "should return error when token is signed with the server hash key",
+			input: input{
+				token:      forgeEmailToken(token),
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {},
+			},
+			expected: expected{
+				err: errs.ErrInvalidEmailToken,
+			},
+		},
+		{
+			description: "should return error when token was issued for another purpose",
+			input: input{
+				token:      signEmailToken(constant.EmailTokenPurposeRevert, newEmail, oldEmail, time.Hour),
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {},
+			},
+			expected: expected{
+				err: errs.ErrInvalidEmailToken,
+			},
+		},
+		{
+			description: "should return error when token has expired",
+			input: input{
+				token:      signEmailToken(constant.EmailTokenPurposeChange, newEmail, oldEmail, -time.Hour),
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {},
+			},
+			expected: expected{
+				err: errs.ErrEmailTokenExpired,
+			},
+		},
+		{
+			description: "should return error when email changed since the token was issued",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {
+					ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: "other.email@email.com"}, nil)
+				},
+			},
+			expected: expected{
+				err: errs.ErrInvalidEmailToken,
+			},
+		},
+		{
+			description: "should return error when new email was taken in the meantime",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {
+					ur.On("GetByID", 1).Return(user, nil)
+					ur.On("GetByEmail", newEmail).Return(&model.User{ID: 2, Email: newEmail}, nil)
+				},
+			},
+			expected: expected{
+				err: errs.ErrEmailUsed,
+			},
+		},
+		{
+			description: "should return error when failed to update email",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {
+					ur.On("GetByID", 1).Return(user, nil)
+					ur.On("GetByEmail", newEmail).Return(nil, errs.ErrUserDoesNotExist)
+					ur.On("UpdateEmail", 1, newEmail).Return(nil, errors.New("failed to update email"))
+				},
+			},
+			expected: expected{
+				err: errors.New("failed to update email"),
+			},
+		},
+		{
+			description: "should return error when failed to sign the user out",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {
+					ur.On("GetByID", 1).Return(user, nil)
+					ur.On("GetByEmail", newEmail).Return(nil, errs.ErrUserDoesNotExist)
+					ur.On("UpdateEmail", 1, newEmail).Return(&model.User{ID: 1, Email: newEmail}, nil)
+					uc.On("DeleteAllByID", 1).Return(errors.New("failed to delete tokens"))
+				},
+			},
+			expected: expected{
+				err: errors.New("failed to delete tokens"),
+			},
+		},
+		{
+			description: "should update email and send revert link to the old email",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, ep *mocks.EmailPublisher) {
+					ur.On("GetByID", 1).Return(user, nil)
+					ur.On("GetByEmail", newEmail).Return(nil, errs.ErrUserDoesNotExist)
+					ur.On("UpdateEmail", 1, newEmail).Return(&model.User{ID: 1, Email: newEmail}, nil)
+					uc.On("DeleteAllByID", 1).Return(nil)
+					ep.On("Enqueue", mock.MatchedBy(func(req *notificationDto.EmailRequest) bool {
+						data := req.Data.(*mail.EmailLinkEmailData)
+						return req.To == oldEmail && req.Template == mail.TemplateEmailChanged && data.NewEmail == newEmail
+					})).Return(nil)
+				},
+			},
+			expected: expected{
+				res: &dto.UpdateEmailResponse{Email: newEmail},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			userCache := mocks.NewUserCache(t)
+			emailPublisher := mocks.NewEmailPublisher(t)
+			tc.beforeTest(userRepo, userCache, emailPublisher)
+			userService := service.NewUserService(&service.UserSConfig{
+				Repository:     userRepo,
+				Redis:          userCache,
+				EmailPublisher: emailPublisher,
+			})
+
+			res, err := userService.ConfirmEmailChange(tc.input.token)
+
+			assert.Equal(t, tc.expected.res, res)
+			assert.Equal(t, tc.expected.err, err)
+		})
+	}
+}
+
+func TestRevertEmailChange(t *testing.T) {
+	test.UseTokenKey(t)
+	type input struct {
+		token      string
+		beforeTest func(*mocks.UserRepository, *mocks.UserCache, *mocks.MailUtils, *mocks.RandomUtils)
+	}
+
+	oldEmail := "old.email@email.com"
+	newEmail := "new.email@email.com"
+	token := signEmailToken(constant.EmailTokenPurposeRevert, oldEmail, newEmail, time.Hour)
+
+	cases := []struct {
+		description string
+		input
+		expected error
+	}{
+		{
+			description: "should return error when token is signed with the server hash key",
+			input: input{
+				token:      forgeEmailToken(token),
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, mu *mocks.MailUtils, ru *mocks.RandomUtils) {},
+			},
+			expected: errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when token has expired",
+			input: input{
+				token:      signEmailToken(constant.EmailTokenPurposeRevert, oldEmail, newEmail, -time.Hour),
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, mu *mocks.MailUtils, ru *mocks.RandomUtils) {},
+			},
+			expected: errs.ErrEmailTokenExpired,
+		},
+		{
+			description: "should return error when user no longer exists",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, mu *mocks.MailUtils, ru *mocks.RandomUtils) {
+					ur.On("GetByID", 1).Return(nil, errs.ErrUserDoesNotExist)
+				},
+			},
+			expected: errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when email changed again since the token was issued",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, mu *mocks.MailUtils, ru *mocks.RandomUtils) {
+					ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: oldEmail}, nil)
+				},
+			},
+			expected: errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when failed to revert email",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, mu *mocks.MailUtils, ru *mocks.RandomUtils) {
+					ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: newEmail}, nil)
+					ur.On("RevertEmail", 1, oldEmail).Return(nil, errors.New("failed to revert email"))
+				},
+			},
+			expected: errors.New("failed to revert email"),
+		},
+		{
+			description: "should return error when failed to sign the user out",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, mu *mocks.MailUtils, ru *mocks.RandomUtils) {
+					ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: newEmail}, nil)
+					ur.On("RevertEmail", 1, oldEmail).Return(&model.User{ID: 1, Email: oldEmail}, nil)
+					uc.On("DeleteAllByID", 1).Return(errors.New("failed to delete tokens"))
+				},
+			},
+			expected: errors.New("failed to delete tokens"),
+		},
+		{
+			description: "should revert email and send a password reset link",
+			input: input{
+				token: token,
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache, mu *mocks.MailUtils, ru *mocks.RandomUtils) {
+					ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: newEmail}, nil)
+					ur.On("RevertEmail", 1, oldEmail).Return(&model.User{ID: 1, Email: oldEmail}, nil)
+					uc.On("DeleteAllByID", 1).Return(nil)
+					ur.On("GetByEmail", oldEmail).Return(&model.User{ID: 1, Email: oldEmail}, nil)
+					ru.On("GenerateSecureUniqueToken").Return("token")
+					uc.On("StoreResetPasswordToken", 1, "token").Return(nil)
+					mu.On("SendResetPasswordEmail", oldEmail, "token").Return(nil)
+				},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			userCache := mocks.NewUserCache(t)
+			mailUtils := mocks.NewMailUtils(t)
+			randomUtils := mocks.NewRandomUtils(t)
+			tc.beforeTest(userRepo, userCache, mailUtils, randomUtils)
+			userService := service.NewUserService(&service.UserSConfig{
+				Repository:  userRepo,
+				Redis:       userCache,
+				MailUtils:   mailUtils,
+				RandomUtils: randomUtils,
+			})
+
+			err := userService.RevertEmailChange(tc.input.token)
+
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	verifiedAt := time.Now()
+
+	cases := []struct {
+		description string
+		beforeTest  func(*mocks.UserRepository, *mocks.EmailPublisher)
+		expected    error
+	}{
+		{
+			description: "should return error when failed to get user",
+			beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+				ur.On("GetByID", 1).Return(nil, errs.ErrUserDoesNotExist)
+			},
+			expected: errs.ErrUserDoesNotExist,
+		},
+		{
+			description: "should return error when email is already verified",
+			beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+				ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@email.com", EmailVerifiedAt: &verifiedAt}, nil)
+			},
+			expected: errs.ErrEmailAlreadyVerified,
+		},
+		{
+			description: "should send verification link to the user email",
+			beforeTest: func(ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+				ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@email.com"}, nil)
+				ep.On("Enqueue", mock.MatchedBy(func(req *notificationDto.EmailRequest) bool {
+					return req.To == "user@email.com" && req.Template == mail.TemplateVerifyEmail
+				})).Return(nil)
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			emailPublisher := mocks.NewEmailPublisher(t)
+			tc.beforeTest(userRepo, emailPublisher)
+			userService := service.NewUserService(&service.UserSConfig{
+				Repository:     userRepo,
+				EmailPublisher: emailPublisher,
+			})
+
+			err := userService.SendEmailVerification(1)
+
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	test.UseTokenKey(t)
+	email := "user@email.com"
+	token := signEmailToken(constant.EmailTokenPurposeVerify, email, email, time.Hour)
+	verifiedAt := time.Now()
+
+	cases := []struct {
+		description string
+		token       string
+		beforeTest  func(*mocks.UserRepository)
+		expected    error
+	}{
+		{
+			description: "should return error when token is malformed",
+			token:       "token",
+			beforeTest:  func(ur *mocks.UserRepository) {},
+			expected:    errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when token is signed with the server hash key",
+			token:       forgeEmailToken(token),
+			beforeTest:  func(ur *mocks.UserRepository) {},
+			expected:    errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return error when email changed since the token was issued",
+			token:       token,
+			beforeTest: func(ur *mocks.UserRepository) {
+				ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: "other@email.com"}, nil)
+			},
+			expected: errs.ErrInvalidEmailToken,
+		},
+		{
+			description: "should return nil when email is already verified",
+			token:       token,
+			beforeTest: func(ur *mocks.UserRepository) {
+				ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: email, EmailVerifiedAt: &verifiedAt}, nil)
+			},
+			expected: nil,
+		},
+		{
+			description: "should mark email as verified",
+			token:       token,
+			beforeTest: func(ur *mocks.UserRepository) {
+				ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: email}, nil)
+				ur.On("MarkEmailVerified", 1, email).Return(nil)
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			tc.beforeTest(userRepo)
+			userService := service.NewUserService(&service.UserSConfig{
+				Repository: userRepo,
+			})
+
+			err := userService.VerifyEmail(tc.token)
+
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
 func TestUpdateUsername(t *testing.T) {
 	type input struct {
 		userId     int
@@ -650,7 +1331,7 @@ func TestUpdateUsername(t *testing.T) {
 func TestSignOut(t *testing.T) {
 	type input struct {
 		data       dto.UserLogoutRequest
-		beforeTest func(*mocks.UserCache)
+		beforeTest func(*mocks.UserCache, *mocks.UserSessionService)
 	}
 	type expected struct {
 		err error
@@ -669,7 +1350,7 @@ func TestSignOut(t *testing.T) {
 					RefreshToken: "refresh_token",
 					AccessToken:  "access_token",
 				},
-				beforeTest: func(ur *mocks.UserCache) {
+				beforeTest: func(ur *mocks.UserCache, uss *mocks.UserSessionService) {
 					ur.On("DeleteRefreshTokenAndAccessToken", 1, "refresh_token", "access_token").Return(errors.New("failed to sign out"))
 				},
 			},
@@ -677,6 +1358,24 @@ func TestSignOut(t *testing.T) {
 				err: errors.New("failed to sign out"),
 			},
 		},
+		{
+			description: "should end the session when signing out of a known session",
+			input: input{
+				data: dto.UserLogoutRequest{
+					UserId:       1,
+					RefreshToken: "refresh_token",
+					AccessToken:  "access_token",
+					SessionKey:   "session",
+				},
+				beforeTest: func(ur *mocks.UserCache, uss *mocks.UserSessionService) {
+					ur.On("DeleteRefreshTokenAndAccessToken", 1, "refresh_token", "access_token").Return(nil)
+					uss.On("EndSession", 1, "session").Return(nil)
+				},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
 		{
 			description: "should return nil if sign out successed",
 			input: input{
@@ -685,7 +1384,7 @@ func TestSignOut(t *testing.T) {
 					RefreshToken: "refresh_token",
 					AccessToken:  "access_token",
 				},
-				beforeTest: func(ur *mocks.UserCache) {
+				beforeTest: func(ur *mocks.UserCache, uss *mocks.UserSessionService) {
 					ur.On("DeleteRefreshTokenAndAccessToken", 1, "refresh_token", "access_token").Return(nil)
 				},
 			},
@@ -698,9 +1397,11 @@ func TestSignOut(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.description, func(t *testing.T) {
 			userCache := mocks.NewUserCache(t)
-			tc.beforeTest(userCache)
+			sessionService := mocks.NewUserSessionService(t)
+			tc.beforeTest(userCache, sessionService)
 			userService := service.NewUserService(&service.UserSConfig{
-				Redis: userCache,
+				Redis:          userCache,
+				SessionService: sessionService,
 			})
 
 			actualErr := userService.SignOut(&tc.input.data)
@@ -921,6 +1622,23 @@ func TestCompletePasswordChange(t *testing.T) {
 			},
 		},
 
+		{
+			description: "should return error when failed to sign the user out",
+			input: input{
+				request: &dto.CompletePasswordChangeRequest{
+					UserId:           1,
+					VerificationCode: verifcationCode,
+				},
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache) {
+					uc.On("FindUserPasswordAndVerificationCode", 1).Return(newPassword, verifcationCode, nil)
+					ur.On("UpdatePassword", 1, mock.Anything).Return(nil, nil)
+					uc.On("DeleteAllByID", 1).Return(errors.New("failed to delete tokens"))
+				},
+			},
+			expected: expected{
+				err: errors.New("failed to delete tokens"),
+			},
+		},
 		{
 			description: "should return nil when success",
 			input: input{
@@ -931,6 +1649,7 @@ func TestCompletePasswordChange(t *testing.T) {
 				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache) {
 					uc.On("FindUserPasswordAndVerificationCode", 1).Return(newPassword, verifcationCode, nil)
 					ur.On("UpdatePassword", 1, mock.Anything).Return(nil, nil)
+					uc.On("DeleteAllByID", 1).Return(nil)
 					uc.On("DeleteUserPasswordAndVerificationCode", 1).Return(nil)
 				},
 			},
@@ -956,6 +1675,40 @@ func TestCompletePasswordChange(t *testing.T) {
 		})
 	}
 
+	t.Run("should return error when verification attempts are throttled", func(t *testing.T) {
+		limiter := mocks.NewLimiter(t)
+		limiter.On("Check", "password_change:account:1").Return(errs.ErrTooManyAttempts)
+		userService := service.NewUserService(&service.UserSConfig{
+			Limiter: limiter,
+		})
+
+		err := userService.CompletePasswordChange(&dto.CompletePasswordChangeRequest{UserId: 1, VerificationCode: verifcationCode})
+
+		assert.ErrorIs(t, err, errs.ErrTooManyAttempts)
+	})
+
+	t.Run("should alert user when wrong verification code locks the account", func(t *testing.T) {
+		userCache := mocks.NewUserCache(t)
+		limiter := mocks.NewLimiter(t)
+		sessionService := mocks.NewUserSessionService(t)
+		limiter.On("Check", "password_change:account:1").Return(nil)
+		userCache.On("FindUserPasswordAndVerificationCode", 1).Return(newPassword, verifcationCode, nil)
+		limiter.On("Fail", "password_change:account:1").Return(true, nil)
+		sessionService.On("NotifyLockout", 1, "127.0.0.1").Return()
+		userService := service.NewUserService(&service.UserSConfig{
+			Redis:          userCache,
+			SessionService: sessionService,
+			Limiter:        limiter,
+		})
+
+		err := userService.CompletePasswordChange(&dto.CompletePasswordChangeRequest{
+			UserId:           1,
+			IPAddress:        "127.0.0.1",
+			VerificationCode: wrongVerifcationCode,
+		})
+
+		assert.ErrorIs(t, err, errs.ErrIncorrectVerificationCode)
+	})
 }
 
 func TestRequestPasswordReset(t *testing.T) {
@@ -1086,6 +1839,7 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error when FindResetPasswordToken failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "asd@mail.com",
 					Token:       token,
 					NewPassword: "newPassword",
 				},
@@ -1101,6 +1855,7 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error when GetByID failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "asd@mail.com",
 					Token:       token,
 					NewPassword: "newPassword",
 				},
@@ -1113,16 +1868,34 @@ func TestCompletePasswordReset(t *testing.T) {
 				err: errs.ErrUserDoesNotExist,
 			},
 		},
+		{
+			description: "should return error when token was sent to another email",
+			input: input{
+				request: &dto.CompletePasswordResetRequest{
+					Email:       "other@mail.com",
+					Token:       token,
+					NewPassword: "newPassword123",
+				},
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache) {
+					uc.On("FindResetPasswordToken", token).Return(1, nil)
+					ur.On("GetByID", 1).Return(&model.User{Username: "asd", Email: "asd@mail.com", ID: 1}, nil)
+				},
+			},
+			expected: expected{
+				err: errs.ErrResetPasswordTokenNotFound,
+			},
+		},
 		{
 			description: "should return error when new password is invalid",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "asd@mail.com",
 					Token:       token,
 					NewPassword: "asdASDa5d",
 				},
 				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache) {
 					uc.On("FindResetPasswordToken", token).Return(1, nil)
-					ur.On("GetByID", 1).Return(&model.User{Username: "asd", ID: 1, Password: hashedPassword}, nil)
+					ur.On("GetByID", 1).Return(&model.User{Username: "asd", Email: "asd@mail.com", ID: 1, Password: hashedPassword}, nil)
 				},
 			},
 			expected: expected{
@@ -1133,12 +1906,13 @@ func TestCompletePasswordReset(t *testing.T) {
 			description: "should return error when UpdatePassword failed",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "asd@mail.com",
 					Token:       token,
 					NewPassword: "newPassword123",
 				},
 				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache) {
 					uc.On("FindResetPasswordToken", token).Return(1, nil)
-					ur.On("GetByID", 1).Return(&model.User{Username: "asd", ID: 1}, nil)
+					ur.On("GetByID", 1).Return(&model.User{Username: "asd", Email: "asd@mail.com", ID: 1}, nil)
 					ur.On("UpdatePassword", mock.Anything, mock.Anything).Return(nil, errs.ErrUserDoesNotExist)
 				},
 			},
@@ -1147,17 +1921,38 @@ func TestCompletePasswordReset(t *testing.T) {
 			},
 		},
 
+		{
+			description: "should return error when failed to sign the user out",
+			input: input{
+				request: &dto.CompletePasswordResetRequest{
+					Email:       "asd@mail.com",
+					Token:       token,
+					NewPassword: "newPassword123",
+				},
+				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache) {
+					uc.On("FindResetPasswordToken", token).Return(1, nil)
+					ur.On("GetByID", 1).Return(&model.User{Username: "asd", Email: "asd@mail.com", ID: 1}, nil)
+					ur.On("UpdatePassword", 1, "newPassword123").Return(nil, nil)
+					uc.On("DeleteAllByID", 1).Return(errors.New("failed to delete tokens"))
+				},
+			},
+			expected: expected{
+				err: errors.New("failed to delete tokens"),
+			},
+		},
 		{
 			description: "should return nil when success",
 			input: input{
 				request: &dto.CompletePasswordResetRequest{
+					Email:       "asd@mail.com",
 					Token:       token,
 					NewPassword: "newPassword123",
 				},
 				beforeTest: func(ur *mocks.UserRepository, uc *mocks.UserCache) {
 					uc.On("FindResetPasswordToken", token).Return(1, nil)
-					ur.On("GetByID", 1).Return(&model.User{Username: "asd", ID: 1}, nil)
+					ur.On("GetByID", 1).Return(&model.User{Username: "asd", Email: "asd@mail.com", ID: 1}, nil)
 					ur.On("UpdatePassword", 1, "newPassword123").Return(nil, nil)
+					uc.On("DeleteAllByID", 1).Return(nil)
 					uc.On("DeleteResetPasswordToken", token).Return(nil)
 				},
 			},
@@ -1183,58 +1978,25 @@ func TestCompletePasswordReset(t *testing.T) {
 		})
 	}
 
-}
-
-func TestAdminSignIn(t *testing.T) {
-	var (
-		email             = "yangPunya@kedai.com"
-		incorrectPassword = "kedai_Owner89"
-	)
-
-	type input struct {
-		request    *dto.UserLogin
-		beforeTest func(*mocks.UserCache)
-	}
-
-	type expected struct {
-		token *dto.Token
-		err   error
-	}
-
-	cases := []struct {
-		description string
-		input
-		expected
-	}{
-		{
-			description: "should return error when inputted credentials does not match",
-			input: input{
-				request: &dto.UserLogin{
-					Email:    email,
-					Password: incorrectPassword,
-				},
-				beforeTest: func(uc *mocks.UserCache) {
-				},
-			},
-			expected: expected{
-				err:   errors.New("invalid user credential"),
-				token: nil,
-			},
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.description, func(t *testing.T) {
-			userCache := mocks.NewUserCache(t)
-			tc.beforeTest(userCache)
-			userService := service.NewUserService(&service.UserSConfig{
-				Redis: userCache,
-			})
-
-			actualToken, actualErr := userService.AdminSignIn(tc.input.request)
+	t.Run("should count unknown reset token against the account and the client IP", func(t *testing.T) {
+		userCache := mocks.NewUserCache(t)
+		limiter := mocks.NewLimiter(t)
+		limiter.On("Check", "password_reset:account:asd@mail.com", "password_reset:ip:127.0.0.1").Return(nil)
+		userCache.On("FindResetPasswordToken", token).Return(0, errs.ErrResetPasswordTokenNotFound)
+		limiter.On("Fail", "password_reset:account:asd@mail.com").Return(false, nil)
+		limiter.On("Fail", "password_reset:ip:127.0.0.1").Return(false, nil)
+		userService := service.NewUserService(&service.UserSConfig{
+			Redis:   userCache,
+			Limiter: limiter,
+		})
 
-			assert.Equal(t, tc.expected.err, actualErr)
-			assert.Equal(t, tc.expected.token, actualToken)
+		err := userService.CompletePasswordReset(&dto.CompletePasswordResetRequest{
+			Email:       "Asd@mail.com",
+			Token:       token,
+			NewPassword: "newPassword",
+			IPAddress:   "127.0.0.1",
 		})
-	}
+
+		assert.ErrorIs(t, err, errs.ErrResetPasswordTokenNotFound)
+	})
 }
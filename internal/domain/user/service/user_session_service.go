@@ -0,0 +1,272 @@
+package service
+
+import (
+	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	notificationService "kedai/backend/be-kedai/internal/domain/notification/service"
+	"kedai/backend/be-kedai/internal/domain/user/cache"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/repository"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/internal/utils/random"
+	"log"
+	"net"
+	"time"
+)
+
+type UserSessionService interface {
+	StartSession(userId int, device *dto.SessionDevice, twoFactorAt *time.Time) (*dto.Token, error)
+	IssueToken(userId int, sessionKey string, twoFactorAt *time.Time) (*dto.Token, error)
+	IssueTokenAtLevel(userId int, sessionKey string, level int) (*dto.Token, error)
+	Touch(sessionKey string) error
+	GetSessions(userId int, currentKey string) ([]*dto.UserSessionResponse, error)
+	RevokeSession(userId int, sessionId int) error
+	RevokeOtherSessions(userId int, currentKey string) error
+	EndSession(userId int, sessionKey string) error
+	NotifyLockout(userId int, ipAddress string)
+}
+
+type userSessionServiceImpl struct {
+	sessionRepo    repository.UserSessionRepository
+	userRepo       repository.UserRepository
+	userCache      cache.UserCache
+	randomUtils    random.RandomUtils
+	emailPublisher notificationService.EmailPublisher
+}
+
+type UserSessionSConfig struct {
+	SessionRepo    repository.UserSessionRepository
+	UserRepo       repository.UserRepository
+	UserCache      cache.UserCache
+	RandomUtils    random.RandomUtils
+	EmailPublisher notificationService.EmailPublisher
+}
+
+func NewUserSessionService(cfg *UserSessionSConfig) UserSessionService {
+	return &userSessionServiceImpl{
+		sessionRepo:    cfg.SessionRepo,
+		userRepo:       cfg.UserRepo,
+		userCache:      cfg.UserCache,
+		randomUtils:    cfg.RandomUtils,
+		emailPublisher: cfg.EmailPublisher,
+	}
+}
+
+// StartSession records a sign in from device and issues its first tokens.
+// The user is alerted when the device has never signed in to their account
+// before, except on their very first sign in.
+func (s *userSessionServiceImpl) StartSession(userId int, device *dto.SessionDevice, twoFactorAt *time.Time) (*dto.Token, error) {
+	deviceId := device.DeviceID
+	if deviceId == "" {
+		deviceId = fingerprintDevice(device)
+	}
+
+	hasAny, err := s.sessionRepo.HasAny(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	isKnown := false
+	if hasAny {
+		isKnown, err = s.sessionRepo.HasDevice(userId, deviceId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	session := &model.UserSession{
+		UserID:     userId,
+		SessionKey: s.randomUtils.GenerateSecureUniqueToken(),
+		DeviceID:   deviceId,
+		UserAgent:  device.UserAgent,
+		IPAddress:  device.IPAddress,
+		LastSeenAt: time.Now(),
+	}
+	err = s.sessionRepo.Create(session)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.IssueToken(userId, session.SessionKey, twoFactorAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasAny && !isKnown {
+		s.sendNewDeviceAlert(session)
+	}
+
+	return token, nil
+}
+
+// fingerprintDevice identifies a device that sent no device ID by its user
+// agent together with the network it signs in from (the /24 of an IPv4
+// address, the /64 of an IPv6 one), so the same browser elsewhere still
+// counts as a new device.
+func fingerprintDevice(device *dto.SessionDevice) string {
+	network := device.IPAddress
+	if ip := net.ParseIP(device.IPAddress); ip != nil {
+		if ipv4 := ip.To4(); ipv4 != nil {
+			network = ipv4.Mask(net.CIDRMask(24, 32)).String()
+		} else {
+			network = ip.Mask(net.CIDRMask(64, 128)).String()
+		}
+	}
+
+	return hash.HashSHA256(device.UserAgent + "|" + network)
+}
+
+// IssueToken issues a new token pair for an existing session. twoFactorAt
+// is set when the session just passed 2FA.
+func (s *userSessionServiceImpl) IssueToken(userId int, sessionKey string, twoFactorAt *time.Time) (*dto.Token, error) {
+	defaultLevel := 0
+
+	return s.issueToken(userId, sessionKey, defaultLevel, twoFactorAt)
+}
+
+// IssueTokenAtLevel issues a new token pair for an existing session with the
+// access level a step up granted, or back at the default level once used.
+func (s *userSessionServiceImpl) IssueTokenAtLevel(userId int, sessionKey string, level int) (*dto.Token, error) {
+	return s.issueToken(userId, sessionKey, level, nil)
+}
+
+func (s *userSessionServiceImpl) issueToken(userId int, sessionKey string, level int, twoFactorAt *time.Time) (*dto.Token, error) {
+	var (
+		user        = &model.User{ID: userId}
+		accessToken string
+		err         error
+	)
+	if twoFactorAt != nil {
+		accessToken, err = jwttoken.GenerateTwoFactorAccessToken(user, level, *twoFactorAt)
+	} else {
+		accessToken, err = jwttoken.GenerateAccessToken(user, level)
+	}
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := jwttoken.GenerateRefreshToken(user, level, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userCache.StoreToken(userId, sessionKey, accessToken, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Token{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *userSessionServiceImpl) Touch(sessionKey string) error {
+	return s.sessionRepo.Touch(sessionKey)
+}
+
+func (s *userSessionServiceImpl) GetSessions(userId int, currentKey string) ([]*dto.UserSessionResponse, error) {
+	refreshAge := jwttoken.ParseTokenAgeFromENV(config.GetEnv("REFRESH_TOKEN_AGE", ""), "refresh")
+
+	sessions, err := s.sessionRepo.GetActiveByUserID(userId, time.Now().Add(-refreshAge))
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*dto.UserSessionResponse{}
+	for _, session := range sessions {
+		var item dto.UserSessionResponse
+		item.FromUserSession(session, currentKey)
+		res = append(res, &item)
+	}
+
+	return res, nil
+}
+
+func (s *userSessionServiceImpl) RevokeSession(userId int, sessionId int) error {
+	session, err := s.sessionRepo.GetByIDAndUserID(sessionId, userId)
+	if err != nil {
+		return err
+	}
+
+	return s.EndSession(userId, session.SessionKey)
+}
+
+func (s *userSessionServiceImpl) RevokeOtherSessions(userId int, currentKey string) error {
+	err := s.sessionRepo.RevokeAllExcept(userId, currentKey)
+	if err != nil {
+		return err
+	}
+
+	return s.userCache.DeleteAllExceptSession(userId, currentKey)
+}
+
+func (s *userSessionServiceImpl) EndSession(userId int, sessionKey string) error {
+	err := s.sessionRepo.Revoke(userId, sessionKey)
+	if err != nil {
+		return err
+	}
+
+	return s.userCache.DeleteSession(userId, sessionKey)
+}
+
+func (s *userSessionServiceImpl) sendNewDeviceAlert(session *model.UserSession) {
+	if s.emailPublisher == nil {
+		return
+	}
+
+	user, err := s.userRepo.GetByID(session.UserID)
+	if err != nil {
+		log.Println("failed to get user", session.UserID, "for new device alert:", err)
+		return
+	}
+
+	err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+		To:       user.Email,
+		UserID:   user.ID,
+		Category: constant.NotificationCategorySecurity,
+		Template: mail.TemplateNewDeviceLogin,
+		Data: &mail.NewDeviceLoginEmailData{
+			Username:    user.Username,
+			UserAgent:   session.UserAgent,
+			IPAddress:   session.IPAddress,
+			SignedInAt:  session.LastSeenAt.Format("2 January 2006 15:04 MST"),
+			SessionsURL: config.GetEnv("FRONTEND_URL", "http://localhost:3000") + config.GetEnv("SESSIONS_URL", "/account/sessions"),
+		},
+	})
+	if err != nil {
+		log.Println("failed to enqueue new device alert to", user.Email, ":", err)
+	}
+}
+
+// NotifyLockout tells the user their sign in or verification codes were
+// locked after repeated failures from ipAddress.
+func (s *userSessionServiceImpl) NotifyLockout(userId int, ipAddress string) {
+	if s.emailPublisher == nil {
+		return
+	}
+
+	user, err := s.userRepo.GetByID(userId)
+	if err != nil {
+		log.Println("failed to get user", userId, "for lockout alert:", err)
+		return
+	}
+
+	err = s.emailPublisher.Enqueue(&notificationDto.EmailRequest{
+		To:       user.Email,
+		UserID:   user.ID,
+		Category: constant.NotificationCategorySecurity,
+		Template: mail.TemplateAccountLocked,
+		Data: &mail.AccountLockedEmailData{
+			Name:        user.Username,
+			IPAddress:   ipAddress,
+			LockedUntil: time.Now().Add(constant.AttemptLockout).Format("2 January 2006 15:04 MST"),
+		},
+	})
+	if err != nil {
+		log.Println("failed to enqueue lockout alert to", user.Email, ":", err)
+	}
+}
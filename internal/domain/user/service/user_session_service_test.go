@@ -0,0 +1,306 @@
+package service_test
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/internal/common/constant"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	notificationDto "kedai/backend/be-kedai/internal/domain/notification/dto"
+	"kedai/backend/be-kedai/internal/domain/user/dto"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"kedai/backend/be-kedai/internal/domain/user/service"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/mail"
+	"kedai/backend/be-kedai/internal/utils/test"
+	mocks "kedai/backend/be-kedai/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStartSession(t *testing.T) {
+	test.UseJWTKeySet(t)
+
+	device := &dto.SessionDevice{DeviceID: "device", UserAgent: "Firefox", IPAddress: "127.0.0.1"}
+
+	type input struct {
+		device     *dto.SessionDevice
+		beforeTest func(*mocks.UserSessionRepository, *mocks.UserRepository, *mocks.EmailPublisher)
+	}
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should not alert on the first sign in of the user",
+			input: input{
+				device: device,
+				beforeTest: func(usr *mocks.UserSessionRepository, ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+					usr.On("HasAny", 1).Return(false, nil)
+					usr.On("Create", mock.AnythingOfType("*model.UserSession")).Return(nil)
+				},
+			},
+		},
+		{
+			description: "should not alert when the device signed in before",
+			input: input{
+				device: device,
+				beforeTest: func(usr *mocks.UserSessionRepository, ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+					usr.On("HasAny", 1).Return(true, nil)
+					usr.On("HasDevice", 1, "device").Return(true, nil)
+					usr.On("Create", mock.AnythingOfType("*model.UserSession")).Return(nil)
+				},
+			},
+		},
+		{
+			description: "should send a security alert when the device is new",
+			input: input{
+				device: device,
+				beforeTest: func(usr *mocks.UserSessionRepository, ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+					usr.On("HasAny", 1).Return(true, nil)
+					usr.On("HasDevice", 1, "device").Return(false, nil)
+					usr.On("Create", mock.AnythingOfType("*model.UserSession")).Return(nil)
+					ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@mail.com", Username: "user"}, nil)
+					ep.On("Enqueue", mock.MatchedBy(func(req *notificationDto.EmailRequest) bool {
+						data := req.Data.(*mail.NewDeviceLoginEmailData)
+						return req.To == "user@mail.com" &&
+							req.Category == constant.NotificationCategorySecurity &&
+							req.Template == mail.TemplateNewDeviceLogin &&
+							data.UserAgent == "Firefox" &&
+							data.IPAddress == "127.0.0.1"
+					})).Return(nil)
+				},
+			},
+		},
+		{
+			description: "should identify the device by its user agent and network when no device id is given",
+			input: input{
+				device: &dto.SessionDevice{UserAgent: "Firefox", IPAddress: "192.0.2.17"},
+				beforeTest: func(usr *mocks.UserSessionRepository, ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+					usr.On("HasAny", 1).Return(true, nil)
+					usr.On("HasDevice", 1, hash.HashSHA256("Firefox|192.0.2.0")).Return(true, nil)
+					usr.On("Create", mock.AnythingOfType("*model.UserSession")).Return(nil)
+				},
+			},
+		},
+		{
+			description: "should alert when the same user agent without device id signs in from another network",
+			input: input{
+				device: &dto.SessionDevice{UserAgent: "Firefox", IPAddress: "2001:db8:1:2::17"},
+				beforeTest: func(usr *mocks.UserSessionRepository, ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+					usr.On("HasAny", 1).Return(true, nil)
+					usr.On("HasDevice", 1, hash.HashSHA256("Firefox|2001:db8:1:2::")).Return(false, nil)
+					usr.On("Create", mock.AnythingOfType("*model.UserSession")).Return(nil)
+					ur.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@mail.com", Username: "user"}, nil)
+					ep.On("Enqueue", mock.AnythingOfType("*dto.EmailRequest")).Return(nil)
+				},
+			},
+		},
+		{
+			description: "should return error when failed to create session",
+			input: input{
+				device: device,
+				beforeTest: func(usr *mocks.UserSessionRepository, ur *mocks.UserRepository, ep *mocks.EmailPublisher) {
+					usr.On("HasAny", 1).Return(false, nil)
+					usr.On("Create", mock.AnythingOfType("*model.UserSession")).Return(errors.New("failed to create session"))
+				},
+			},
+			expected: expected{
+				err: errors.New("failed to create session"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			sessionRepo := mocks.NewUserSessionRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+			userCache := mocks.NewUserCache(t)
+			randomUtils := mocks.NewRandomUtils(t)
+			emailPublisher := mocks.NewEmailPublisher(t)
+			tc.beforeTest(sessionRepo, userRepo, emailPublisher)
+			randomUtils.On("GenerateSecureUniqueToken").Return("session")
+			if tc.expected.err == nil {
+				userCache.On("StoreToken", 1, "session", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+			}
+			sessionService := service.NewUserSessionService(&service.UserSessionSConfig{
+				SessionRepo:    sessionRepo,
+				UserRepo:       userRepo,
+				UserCache:      userCache,
+				RandomUtils:    randomUtils,
+				EmailPublisher: emailPublisher,
+			})
+
+			token, err := sessionService.StartSession(1, tc.input.device, nil)
+
+			assert.Equal(t, tc.expected.err, err)
+			if tc.expected.err == nil {
+				assert.NotEmpty(t, token.AccessToken)
+				assert.NotEmpty(t, token.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestGetUserSessions(t *testing.T) {
+	now := time.Now()
+	sessions := []*model.UserSession{
+		{ID: 1, SessionKey: "current", DeviceID: "phone", LastSeenAt: now},
+		{ID: 2, SessionKey: "other", DeviceID: "laptop", LastSeenAt: now},
+	}
+
+	sessionRepo := mocks.NewUserSessionRepository(t)
+	sessionRepo.On("GetActiveByUserID", 1, mock.AnythingOfType("time.Time")).Return(sessions, nil)
+	sessionService := service.NewUserSessionService(&service.UserSessionSConfig{
+		SessionRepo: sessionRepo,
+	})
+
+	result, err := sessionService.GetSessions(1, "current")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*dto.UserSessionResponse{
+		{ID: 1, DeviceID: "phone", LastSeenAt: now, IsCurrent: true},
+		{ID: 2, DeviceID: "laptop", LastSeenAt: now},
+	}, result)
+}
+
+func TestRevokeUserSession(t *testing.T) {
+	type input struct {
+		beforeTest func(*mocks.UserSessionRepository, *mocks.UserCache)
+	}
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when session does not belong to user",
+			input: input{
+				beforeTest: func(usr *mocks.UserSessionRepository, uc *mocks.UserCache) {
+					usr.On("GetByIDAndUserID", 2, 1).Return(nil, errs.ErrSessionNotFound)
+				},
+			},
+			expected: expected{
+				err: errs.ErrSessionNotFound,
+			},
+		},
+		{
+			description: "should revoke session and delete its tokens",
+			input: input{
+				beforeTest: func(usr *mocks.UserSessionRepository, uc *mocks.UserCache) {
+					usr.On("GetByIDAndUserID", 2, 1).Return(&model.UserSession{ID: 2, UserID: 1, SessionKey: "other"}, nil)
+					usr.On("Revoke", 1, "other").Return(nil)
+					uc.On("DeleteSession", 1, "other").Return(nil)
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			sessionRepo := mocks.NewUserSessionRepository(t)
+			userCache := mocks.NewUserCache(t)
+			tc.beforeTest(sessionRepo, userCache)
+			sessionService := service.NewUserSessionService(&service.UserSessionSConfig{
+				SessionRepo: sessionRepo,
+				UserCache:   userCache,
+			})
+
+			err := sessionService.RevokeSession(1, 2)
+
+			assert.Equal(t, tc.expected.err, err)
+		})
+	}
+}
+
+func TestRevokeOtherUserSessions(t *testing.T) {
+	type input struct {
+		beforeTest func(*mocks.UserSessionRepository, *mocks.UserCache)
+	}
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		description string
+		input
+		expected
+	}{
+		{
+			description: "should return error when failed to revoke sessions",
+			input: input{
+				beforeTest: func(usr *mocks.UserSessionRepository, uc *mocks.UserCache) {
+					usr.On("RevokeAllExcept", 1, "current").Return(errors.New("failed to revoke"))
+				},
+			},
+			expected: expected{
+				err: errors.New("failed to revoke"),
+			},
+		},
+		{
+			description: "should keep only the current session",
+			input: input{
+				beforeTest: func(usr *mocks.UserSessionRepository, uc *mocks.UserCache) {
+					usr.On("RevokeAllExcept", 1, "current").Return(nil)
+					uc.On("DeleteAllExceptSession", 1, "current").Return(nil)
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			sessionRepo := mocks.NewUserSessionRepository(t)
+			userCache := mocks.NewUserCache(t)
+			tc.beforeTest(sessionRepo, userCache)
+			sessionService := service.NewUserSessionService(&service.UserSessionSConfig{
+				SessionRepo: sessionRepo,
+				UserCache:   userCache,
+			})
+
+			err := sessionService.RevokeOtherSessions(1, "current")
+
+			assert.Equal(t, tc.expected.err, err)
+		})
+	}
+}
+
+func TestNotifyLockout(t *testing.T) {
+	t.Run("should email the user when their account was locked", func(t *testing.T) {
+		userRepo := mocks.NewUserRepository(t)
+		emailPublisher := mocks.NewEmailPublisher(t)
+		userRepo.On("GetByID", 1).Return(&model.User{ID: 1, Email: "user@mail.com", Username: "user"}, nil)
+		emailPublisher.On("Enqueue", mock.MatchedBy(func(email *notificationDto.EmailRequest) bool {
+			data, ok := email.Data.(*mail.AccountLockedEmailData)
+			return email.To == "user@mail.com" && email.Template == mail.TemplateAccountLocked && email.Category == constant.NotificationCategorySecurity &&
+				ok && data.Name == "user" && data.IPAddress == "127.0.0.1"
+		})).Return(nil)
+		sessionService := service.NewUserSessionService(&service.UserSessionSConfig{
+			UserRepo:       userRepo,
+			EmailPublisher: emailPublisher,
+		})
+
+		sessionService.NotifyLockout(1, "127.0.0.1")
+	})
+
+	t.Run("should not email when the user cannot be loaded", func(t *testing.T) {
+		userRepo := mocks.NewUserRepository(t)
+		userRepo.On("GetByID", 1).Return(nil, errs.ErrUserDoesNotExist)
+		sessionService := service.NewUserSessionService(&service.UserSessionSConfig{
+			UserRepo:       userRepo,
+			EmailPublisher: mocks.NewEmailPublisher(t),
+		})
+
+		sessionService.NotifyLockout(1, "127.0.0.1")
+	})
+}
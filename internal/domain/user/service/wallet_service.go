@@ -1,15 +1,17 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
 	errs "kedai/backend/be-kedai/internal/common/error"
 	"kedai/backend/be-kedai/internal/domain/user/cache"
 	"kedai/backend/be-kedai/internal/domain/user/dto"
 	"kedai/backend/be-kedai/internal/domain/user/model"
 	"kedai/backend/be-kedai/internal/domain/user/repository"
+	"kedai/backend/be-kedai/internal/utils/attempt"
 	"kedai/backend/be-kedai/internal/utils/hash"
-	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
 	"kedai/backend/be-kedai/internal/utils/mail"
 	"kedai/backend/be-kedai/internal/utils/random"
 )
@@ -32,31 +34,34 @@ type WalletService interface {
 }
 
 type walletServiceImpl struct {
-	userService UserService
-	walletRepo  repository.WalletRepository
-	userCache   cache.UserCache
-	walletCache cache.WalletCache
-	randomUtils random.RandomUtils
-	mailUtils   mail.MailUtils
+	userService    UserService
+	sessionService UserSessionService
+	walletRepo     repository.WalletRepository
+	walletCache    cache.WalletCache
+	randomUtils    random.RandomUtils
+	mailUtils      mail.MailUtils
+	limiter        attempt.Limiter
 }
 
 type WalletSConfig struct {
-	UserService UserService
-	WalletRepo  repository.WalletRepository
-	UserCache   cache.UserCache
-	WalletCache cache.WalletCache
-	RandomUtils random.RandomUtils
-	MailUtils   mail.MailUtils
+	UserService    UserService
+	SessionService UserSessionService
+	WalletRepo     repository.WalletRepository
+	WalletCache    cache.WalletCache
+	RandomUtils    random.RandomUtils
+	MailUtils      mail.MailUtils
+	Limiter        attempt.Limiter
 }
 
 func NewWalletService(cfg *WalletSConfig) WalletService {
 	return &walletServiceImpl{
-		walletRepo:  cfg.WalletRepo,
-		userCache:   cfg.UserCache,
-		walletCache: cfg.WalletCache,
-		userService: cfg.UserService,
-		randomUtils: cfg.RandomUtils,
-		mailUtils:   cfg.MailUtils,
+		walletRepo:     cfg.WalletRepo,
+		walletCache:    cfg.WalletCache,
+		userService:    cfg.UserService,
+		sessionService: cfg.SessionService,
+		randomUtils:    cfg.RandomUtils,
+		mailUtils:      cfg.MailUtils,
+		limiter:        cfg.Limiter,
 	}
 }
 
@@ -154,26 +159,9 @@ func (s *walletServiceImpl) StepUp(userId int, req dto.StepUpRequest) (*dto.Toke
 		return nil, err
 	}
 
-	var (
-		user = &model.User{
-			ID: userId,
-		}
-		stepUpLevel = 1
-	)
-	accessToken, _ := jwttoken.GenerateAccessToken(user, stepUpLevel)
-	refreshToken, _ := jwttoken.GenerateRefreshToken(user, stepUpLevel)
-
-	token := &dto.Token{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}
-
-	err = s.userCache.StoreToken(userId, accessToken, refreshToken)
-	if err != nil {
-		return nil, err
-	}
+	stepUpLevel := 1
 
-	return token, nil
+	return s.userService.RotateToken(userId, req.SessionKey, req.RefreshToken, stepUpLevel)
 }
 
 func (s *walletServiceImpl) CheckIsWalletBlocked(userID int) error {
@@ -185,15 +173,28 @@ func (s *walletServiceImpl) CheckIsWalletBlocked(userID int) error {
 	return s.walletCache.CheckIsWalletBlocked(wallet.ID)
 }
 
+// RequestPinChange mails a verification code for the new PIN once the
+// current one was confirmed. Wrong PINs count against the account and the
+// client IP.
 func (s *walletServiceImpl) RequestPinChange(userID int, request *dto.ChangePinRequest) error {
+	accountKey := attempt.AccountKey(constant.AttemptScopePinChange, userID)
+	ipKey := attempt.IPKey(constant.AttemptScopePinChange, request.IPAddress)
+	err := checkAttempts(s.limiter, accountKey, ipKey)
+	if err != nil {
+		return err
+	}
+
 	wallet, err := s.walletRepo.GetByUserID(userID)
 	if err != nil {
+		releaseAttempts(s.limiter, accountKey, ipKey)
 		return err
 	}
 
 	if isPinValid := hash.ComparePassword(wallet.Pin, request.CurrentPin); !isPinValid {
+		recordFailedAttempt(s.limiter, s.sessionService, userID, request.IPAddress, accountKey, ipKey)
 		return errs.ErrPinMismatch
 	}
+	resetAttempts(s.limiter, accountKey, ipKey)
 
 	codeLength := 6
 	verificationCode := s.randomUtils.GenerateAlphanumericString(codeLength)
@@ -212,15 +213,27 @@ func (s *walletServiceImpl) RequestPinChange(userID int, request *dto.ChangePinR
 	return s.mailUtils.SendUpdatePinEmail(user.Email, verificationCode)
 }
 
+// CompletePinChange applies the new PIN once the mailed verification code
+// matches. Wrong codes count against the account and the client IP.
 func (s *walletServiceImpl) CompletePinChange(userID int, request *dto.CompleteChangePinRequest) error {
+	accountKey := attempt.AccountKey(constant.AttemptScopePinChange, userID)
+	ipKey := attempt.IPKey(constant.AttemptScopePinChange, request.IPAddress)
+	err := checkAttempts(s.limiter, accountKey, ipKey)
+	if err != nil {
+		return err
+	}
+
 	newPin, verificationCode, err := s.walletCache.FindPinAndVerificationCode(userID)
 	if err != nil {
+		releaseAttempts(s.limiter, accountKey, ipKey)
 		return err
 	}
 
 	if verificationCode != request.VerificationCode {
+		recordFailedAttempt(s.limiter, s.sessionService, userID, request.IPAddress, accountKey, ipKey)
 		return errs.ErrIncorrectVerificationCode
 	}
+	resetAttempts(s.limiter, accountKey, ipKey)
 
 	err = s.walletRepo.ChangePin(userID, newPin)
 	if err != nil {
@@ -248,12 +261,27 @@ func (s *walletServiceImpl) RequestPinReset(userID int) error {
 	return s.mailUtils.SendResetPinEmail(user.Email, verificationCode)
 }
 
+// CompletePinReset sets the new PIN once the mailed reset token is found.
+// Unknown tokens count against the account and the client IP.
 func (s *walletServiceImpl) CompletePinReset(userID int, request *dto.CompleteResetPinRequest) error {
-	err := s.walletCache.FindResetPinToken(request.Token)
+	accountKey := attempt.AccountKey(constant.AttemptScopePinReset, userID)
+	ipKey := attempt.IPKey(constant.AttemptScopePinReset, request.IPAddress)
+	err := checkAttempts(s.limiter, accountKey, ipKey)
 	if err != nil {
 		return err
 	}
 
+	err = s.walletCache.FindResetPinToken(request.Token)
+	if err != nil {
+		if errors.Is(err, errs.ErrResetPinTokenNotFound) {
+			recordFailedAttempt(s.limiter, s.sessionService, userID, request.IPAddress, accountKey, ipKey)
+		} else {
+			releaseAttempts(s.limiter, accountKey, ipKey)
+		}
+		return err
+	}
+	resetAttempts(s.limiter, accountKey, ipKey)
+
 	newPin, _ := hash.HashAndSalt(request.NewPin)
 
 	err = s.walletRepo.ChangePin(userID, newPin)
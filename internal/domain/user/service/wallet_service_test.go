@@ -439,6 +439,67 @@ func TestRequestPinChange(t *testing.T) {
 			assert.Equal(t, tc.expected.err, err)
 		})
 	}
+
+	t.Run("should refuse pin change while attempts are throttled", func(t *testing.T) {
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			Limiter: limiter,
+		})
+		limiter.On("Check", "pin_change:account:1", "pin_change:ip:127.0.0.1").Return(errRes.ErrTooManyAttempts)
+
+		err := walletService.RequestPinChange(userID, &dto.ChangePinRequest{CurrentPin: oldPin, IPAddress: "127.0.0.1"})
+
+		assert.ErrorIs(t, err, errRes.ErrTooManyAttempts)
+	})
+
+	t.Run("should alert user when wrong pin locks the account", func(t *testing.T) {
+		walletRepo := mocks.NewWalletRepository(t)
+		sessionService := mocks.NewUserSessionService(t)
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			WalletRepo:     walletRepo,
+			SessionService: sessionService,
+			Limiter:        limiter,
+		})
+		limiter.On("Check", "pin_change:account:1", "pin_change:ip:127.0.0.1").Return(nil)
+		walletRepo.On("GetByUserID", userID).Return(&model.Wallet{Pin: hashedOldPin}, nil)
+		limiter.On("Fail", "pin_change:account:1").Return(true, nil)
+		limiter.On("Fail", "pin_change:ip:127.0.0.1").Return(false, nil)
+		sessionService.On("NotifyLockout", userID, "127.0.0.1").Return()
+
+		err := walletService.RequestPinChange(userID, &dto.ChangePinRequest{CurrentPin: "789012", IPAddress: "127.0.0.1"})
+
+		assert.ErrorIs(t, err, errRes.ErrPinMismatch)
+	})
+
+	t.Run("should reset attempts when current pin is valid", func(t *testing.T) {
+		walletRepo := mocks.NewWalletRepository(t)
+		randomUtils := mocks.NewRandomUtils(t)
+		mailUtils := mocks.NewMailUtils(t)
+		userService := mocks.NewUserService(t)
+		walletCache := mocks.NewWalletCache(t)
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			WalletRepo:  walletRepo,
+			UserService: userService,
+			WalletCache: walletCache,
+			RandomUtils: randomUtils,
+			MailUtils:   mailUtils,
+			Limiter:     limiter,
+		})
+		limiter.On("Check", "pin_change:account:1", "pin_change:ip:127.0.0.1").Return(nil)
+		walletRepo.On("GetByUserID", userID).Return(&model.Wallet{Pin: hashedOldPin}, nil)
+		limiter.On("Reset", "pin_change:account:1").Return(nil)
+		limiter.On("Release", "pin_change:ip:127.0.0.1").Return(nil)
+		randomUtils.On("GenerateAlphanumericString", codeLength).Return(verificationCode)
+		walletCache.On("StorePinAndVerificationCode", userID, mock.Anything, verificationCode).Return(nil)
+		userService.On("GetByID", userID).Return(&model.User{Email: email}, nil)
+		mailUtils.On("SendUpdatePinEmail", email, verificationCode).Return(nil)
+
+		err := walletService.RequestPinChange(userID, &dto.ChangePinRequest{CurrentPin: oldPin, NewPin: "098765", IPAddress: "127.0.0.1"})
+
+		assert.NoError(t, err)
+	})
 }
 
 func TestCompletePinChange(t *testing.T) {
@@ -541,6 +602,40 @@ func TestCompletePinChange(t *testing.T) {
 			assert.Equal(t, tc.expected.err, err)
 		})
 	}
+
+	t.Run("should count wrong verification code against account and ip", func(t *testing.T) {
+		walletCache := mocks.NewWalletCache(t)
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			WalletCache: walletCache,
+			Limiter:     limiter,
+		})
+		limiter.On("Check", "pin_change:account:1", "pin_change:ip:127.0.0.1").Return(nil)
+		walletCache.On("FindPinAndVerificationCode", userID).Return(hashedPin, verificationCode, nil)
+		limiter.On("Fail", "pin_change:account:1").Return(false, nil)
+		limiter.On("Fail", "pin_change:ip:127.0.0.1").Return(false, nil)
+
+		err := walletService.CompletePinChange(userID, &dto.CompleteChangePinRequest{VerificationCode: "d4e5f6", IPAddress: "127.0.0.1"})
+
+		assert.ErrorIs(t, err, errRes.ErrIncorrectVerificationCode)
+	})
+
+	t.Run("should release attempt when there is no pending pin change", func(t *testing.T) {
+		walletCache := mocks.NewWalletCache(t)
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			WalletCache: walletCache,
+			Limiter:     limiter,
+		})
+		limiter.On("Check", "pin_change:account:1", "pin_change:ip:127.0.0.1").Return(nil)
+		walletCache.On("FindPinAndVerificationCode", userID).Return("", "", errRes.ErrVerificationCodeNotFound)
+		limiter.On("Release", "pin_change:account:1").Return(nil)
+		limiter.On("Release", "pin_change:ip:127.0.0.1").Return(nil)
+
+		err := walletService.CompletePinChange(userID, &dto.CompleteChangePinRequest{VerificationCode: verificationCode, IPAddress: "127.0.0.1"})
+
+		assert.ErrorIs(t, err, errRes.ErrVerificationCodeNotFound)
+	})
 }
 
 func TestRequestPinReset(t *testing.T) {
@@ -731,6 +826,56 @@ func TestCompletePinReset(t *testing.T) {
 			assert.Equal(t, tc.expected.err, err)
 		})
 	}
+
+	t.Run("should refuse pin reset while attempts are throttled", func(t *testing.T) {
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			Limiter: limiter,
+		})
+		limiter.On("Check", "pin_reset:account:1", "pin_reset:ip:127.0.0.1").Return(errRes.ErrTooManyAttempts)
+
+		err := walletService.CompletePinReset(userID, &dto.CompleteResetPinRequest{Token: token, NewPin: newPin, IPAddress: "127.0.0.1"})
+
+		assert.ErrorIs(t, err, errRes.ErrTooManyAttempts)
+	})
+
+	t.Run("should count unknown reset token against account and ip", func(t *testing.T) {
+		walletCache := mocks.NewWalletCache(t)
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			WalletCache: walletCache,
+			Limiter:     limiter,
+		})
+		limiter.On("Check", "pin_reset:account:1", "pin_reset:ip:127.0.0.1").Return(nil)
+		walletCache.On("FindResetPinToken", token).Return(errRes.ErrResetPinTokenNotFound)
+		limiter.On("Fail", "pin_reset:account:1").Return(false, nil)
+		limiter.On("Fail", "pin_reset:ip:127.0.0.1").Return(false, nil)
+
+		err := walletService.CompletePinReset(userID, &dto.CompleteResetPinRequest{Token: token, NewPin: newPin, IPAddress: "127.0.0.1"})
+
+		assert.ErrorIs(t, err, errRes.ErrResetPinTokenNotFound)
+	})
+
+	t.Run("should reset attempts when reset token is found", func(t *testing.T) {
+		walletRepo := mocks.NewWalletRepository(t)
+		walletCache := mocks.NewWalletCache(t)
+		limiter := mocks.NewLimiter(t)
+		walletService := service.NewWalletService(&service.WalletSConfig{
+			WalletRepo:  walletRepo,
+			WalletCache: walletCache,
+			Limiter:     limiter,
+		})
+		limiter.On("Check", "pin_reset:account:1", "pin_reset:ip:127.0.0.1").Return(nil)
+		walletCache.On("FindResetPinToken", token).Return(nil)
+		limiter.On("Reset", "pin_reset:account:1").Return(nil)
+		limiter.On("Release", "pin_reset:ip:127.0.0.1").Return(nil)
+		walletRepo.On("ChangePin", userID, mock.Anything).Return(nil)
+		walletCache.On("DeleteResetPinToken", token).Return(nil)
+
+		err := walletService.CompletePinReset(userID, &dto.CompleteResetPinRequest{Token: token, NewPin: newPin, IPAddress: "127.0.0.1"})
+
+		assert.NoError(t, err)
+	})
 }
 
 func TestStepUp(t *testing.T) {
@@ -744,8 +889,10 @@ func TestStepUp(t *testing.T) {
 	}
 
 	var (
-		userID = 1
-		PIN    = "123456"
+		userID       = 1
+		PIN          = "123456"
+		hashedPIN, _ = hash.HashAndSalt(PIN)
+		token        = &dto.Token{AccessToken: "access", RefreshToken: "refresh"}
 	)
 
 	tests := []struct {
@@ -785,6 +932,46 @@ func TestStepUp(t *testing.T) {
 				err: errors.New("failed to check wallet blocked"),
 			},
 		},
+		{
+			description: "should rotate the presented refresh token into a stepped up token",
+			input: input{
+				userID: userID,
+				req: &dto.StepUpRequest{
+					Pin:          PIN,
+					RefreshToken: "old-refresh",
+					SessionKey:   "session",
+				},
+			},
+			beforeTest: func(us *mocks.UserService, ru *mocks.RandomUtils, wr *mocks.WalletRepository, wc *mocks.WalletCache) {
+				wr.On("GetByUserID", userID).Return(&model.Wallet{ID: 1, Pin: hashedPIN}, nil)
+				wc.On("CheckIsWalletBlocked", 1).Return(nil)
+				wc.On("DeleteErrorCount", 1).Return(nil)
+				us.On("RotateToken", userID, "session", "old-refresh", 1).Return(token, nil)
+			},
+			expected: expected{
+				token: token,
+			},
+		},
+		{
+			description: "should return error when the presented refresh token was already used",
+			input: input{
+				userID: userID,
+				req: &dto.StepUpRequest{
+					Pin:          PIN,
+					RefreshToken: "old-refresh",
+					SessionKey:   "session",
+				},
+			},
+			beforeTest: func(us *mocks.UserService, ru *mocks.RandomUtils, wr *mocks.WalletRepository, wc *mocks.WalletCache) {
+				wr.On("GetByUserID", userID).Return(&model.Wallet{ID: 1, Pin: hashedPIN}, nil)
+				wc.On("CheckIsWalletBlocked", 1).Return(nil)
+				wc.On("DeleteErrorCount", 1).Return(nil)
+				us.On("RotateToken", userID, "session", "old-refresh", 1).Return(nil, errRes.ErrRefreshTokenReused)
+			},
+			expected: expected{
+				err: errRes.ErrRefreshTokenReused,
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -2,7 +2,6 @@ package middleware
 
 import (
 	"errors"
-	"kedai/backend/be-kedai/config"
 	"kedai/backend/be-kedai/internal/common/code"
 	"kedai/backend/be-kedai/internal/common/constant"
 	errs "kedai/backend/be-kedai/internal/common/error"
@@ -30,7 +29,7 @@ func JWTAuthorization(c *gin.Context) {
 
 	auth = strings.Replace(auth, "Bearer ", "", -1)
 
-	parsedToken, err := jwttoken.ValidateToken(auth, config.SecretKey)
+	parsedToken, err := jwttoken.ValidateToken(auth)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{
@@ -47,8 +46,17 @@ func JWTAuthorization(c *gin.Context) {
 		return
 	}
 
+	if parsedToken.Scope == constant.AdminTokenScope {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{
+			Code:    code.UNAUTHORIZED,
+			Message: errs.ErrUnauthorized.Error(),
+		})
+		return
+	}
+
 	c.Set("userId", parsedToken.UserId)
 	c.Set("level", parsedToken.Level)
+	c.Set("twoFactorAt", parsedToken.TwoFactorAt)
 }
 
 func JWTValidateRefreshToken(c *gin.Context) {
@@ -64,7 +72,7 @@ func JWTValidateRefreshToken(c *gin.Context) {
 
 	auth = strings.Replace(auth, "Bearer ", "", -1)
 
-	parsedToken, err := jwttoken.ValidateRefreshToken(auth, config.SecretKey)
+	parsedToken, err := jwttoken.ValidateRefreshToken(auth)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{
@@ -82,6 +90,7 @@ func JWTValidateRefreshToken(c *gin.Context) {
 	}
 
 	c.Set("userId", parsedToken.UserId)
+	c.Set("familyId", parsedToken.FamilyID)
 }
 
 func AdminJWTAuthorization(c *gin.Context) {
@@ -97,7 +106,7 @@ func AdminJWTAuthorization(c *gin.Context) {
 
 	auth = strings.Replace(auth, "Bearer ", "", -1)
 
-	parsedToken, err := jwttoken.ValidateToken(auth, config.SecretKey)
+	parsedToken, err := jwttoken.ValidateToken(auth)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{
@@ -114,7 +123,7 @@ func AdminJWTAuthorization(c *gin.Context) {
 		return
 	}
 
-	if parsedToken.UserId != constant.AdminUserId {
+	if parsedToken.Scope != constant.AdminTokenScope {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{
 			Code:    code.UNAUTHORIZED,
 			Message: errs.ErrUnauthorized.Error(),
@@ -122,5 +131,39 @@ func AdminJWTAuthorization(c *gin.Context) {
 		return
 	}
 
-	c.Set("userId", parsedToken.UserId)
+	c.Set("adminId", parsedToken.UserId)
+}
+
+// RequireAdminPermission lets the request through only when the admin
+// session loaded before it granted permission.
+func RequireAdminPermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, granted := range c.GetStringSlice("adminPermissions") {
+			if granted == permission {
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, response.Response{
+			Code:    code.FORBIDDEN,
+			Message: errs.ErrAdminPermissionDenied.Error(),
+		})
+	}
+}
+
+// RequireShopPermission lets the request through only when the shop
+// membership resolved before it grants permission.
+func RequireShopPermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, granted := range c.GetStringSlice("shopPermissions") {
+			if granted == permission {
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, response.Response{
+			Code:    code.FORBIDDEN,
+			Message: errs.ErrShopPermissionDenied.Error(),
+		})
+	}
 }
@@ -2,14 +2,16 @@ package server
 
 import (
 	"kedai/backend/be-kedai/config"
-	"kedai/backend/be-kedai/connection"
+	"kedai/backend/be-kedai/internal/common/constant"
 	"kedai/backend/be-kedai/internal/server/middleware"
 
 	"github.com/gin-contrib/pprof"
 
+	adminHandler "kedai/backend/be-kedai/internal/domain/admin/handler"
 	chatHandler "kedai/backend/be-kedai/internal/domain/chat/handler"
 	locationHandler "kedai/backend/be-kedai/internal/domain/location/handler"
 	marketplaceHandler "kedai/backend/be-kedai/internal/domain/marketplace/handler"
+	notificationHandler "kedai/backend/be-kedai/internal/domain/notification/handler"
 	orderHandler "kedai/backend/be-kedai/internal/domain/order/handler"
 	productHandler "kedai/backend/be-kedai/internal/domain/product/handler"
 	shopHandler "kedai/backend/be-kedai/internal/domain/shop/handler"
@@ -17,16 +19,20 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	socketio "github.com/googollee/go-socket.io"
 )
 
 type RouterConfig struct {
-	UserHandler        *userHandler.Handler
-	LocationHandler    *locationHandler.Handler
-	ProductHandler     *productHandler.Handler
-	ShopHandler        *shopHandler.Handler
-	OrderHandler       *orderHandler.Handler
-	MarketplaceHandler *marketplaceHandler.Handler
-	ChatHandler        *chatHandler.Handler
+	UserHandler         *userHandler.Handler
+	LocationHandler     *locationHandler.Handler
+	ProductHandler      *productHandler.Handler
+	ShopHandler         *shopHandler.Handler
+	OrderHandler        *orderHandler.Handler
+	MarketplaceHandler  *marketplaceHandler.Handler
+	ChatHandler         *chatHandler.Handler
+	NotificationHandler *notificationHandler.Handler
+	AdminHandler        *adminHandler.Handler
+	SocketServer        *socketio.Server
 }
 
 func NewRouter(cfg *RouterConfig) *gin.Engine {
@@ -37,16 +43,17 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 	corsCfg := cors.DefaultConfig()
 	corsCfg.AllowOrigins = config.Origin
 	corsCfg.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsCfg.AllowHeaders = []string{"Content-Type", "Authorization"}
+	corsCfg.AllowHeaders = []string{"Content-Type", "Authorization", "X-Shop-Id", "X-Device-Id"}
 	corsCfg.ExposeHeaders = []string{"Content-Length"}
 	corsCfg.AllowCredentials = true
 	r.Use(cors.New(corsCfg))
 
-	socketServer := connection.SocketIO()
+	r.GET("/.well-known/jwks.json", cfg.UserHandler.GetJWKS)
+
 	socket := r.Group("/socket.io")
 	{
-		socket.GET("/*any", gin.WrapH(socketServer))
-		socket.POST("/*any", gin.WrapH(socketServer))
+		socket.GET("/*any", gin.WrapH(cfg.SocketServer))
+		socket.POST("/*any", gin.WrapH(cfg.SocketServer))
 	}
 
 	v1 := r.Group("/v1")
@@ -57,26 +64,67 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 		{
 			user.POST("/register", cfg.UserHandler.UserRegistration)
 			user.POST("/login", cfg.UserHandler.UserLogin)
-			user.POST("/google-register", cfg.UserHandler.UserRegistrationWithGoogle)
-			user.POST("/google-login", cfg.UserHandler.UserLoginWithGoogle)
+			user.POST("/login/two-factor", cfg.UserHandler.UserLoginTwoFactor)
+			user.POST("/google-register", cfg.UserHandler.UserRegistrationWithProvider)
+			user.POST("/google-login", cfg.UserHandler.UserLoginWithProvider)
+			user.GET("/identity-providers", cfg.UserHandler.GetIdentityProviders)
+			user.POST("/identity-providers/:provider/register", cfg.UserHandler.UserRegistrationWithProvider)
+			user.POST("/identity-providers/:provider/login", cfg.UserHandler.UserLoginWithProvider)
+			user.POST("/identity-links/confirm", cfg.UserHandler.ConfirmIdentityLink)
 			user.POST("/tokens/refresh", middleware.JWTValidateRefreshToken, cfg.UserHandler.RenewSession)
 
 			user.POST("/passwords/reset-request", cfg.UserHandler.RequestPasswordReset)
 			user.POST("/passwords/reset-confirmation", cfg.UserHandler.CompletePasswordReset)
+			user.POST("/emails/verify", cfg.UserHandler.VerifyEmail)
+			user.POST("/emails/confirm", cfg.UserHandler.ConfirmUserEmail)
+			user.POST("/emails/revert", cfg.UserHandler.RevertUserEmail)
 			userAuthenticated := user.Group("", middleware.JWTAuthorization, cfg.UserHandler.GetSession)
 			{
 				userAuthenticated.GET("", cfg.UserHandler.GetUserByID)
+				userAuthenticated.DELETE("", cfg.UserHandler.RequireFreshTwoFactor, cfg.UserHandler.DeleteAccount)
 				userAuthenticated.POST("/logout", cfg.UserHandler.SignOut)
 
-				userAuthenticated.PUT("/emails", cfg.UserHandler.UpdateUserEmail)
+				userAuthenticated.PUT("/emails", cfg.UserHandler.RequireFreshTwoFactor, cfg.UserHandler.UpdateUserEmail)
+				userAuthenticated.POST("/emails/verification", cfg.UserHandler.SendEmailVerification)
 				userAuthenticated.PUT("/usernames", cfg.UserHandler.UpdateUsername)
 
 				passwords := userAuthenticated.Group("/passwords")
 				{
-					passwords.POST("/change-request", cfg.UserHandler.RequestPasswordChange)
+					passwords.POST("/change-request", cfg.UserHandler.RequireFreshTwoFactor, cfg.UserHandler.RequestPasswordChange)
 					passwords.POST("/change-confirmation", cfg.UserHandler.CompletePasswordChange)
 				}
 
+				sessions := userAuthenticated.Group("/sessions")
+				{
+					sessions.GET("", cfg.UserHandler.GetUserSessions)
+					sessions.DELETE("", cfg.UserHandler.RevokeOtherUserSessions)
+					sessions.DELETE("/:sessionId", cfg.UserHandler.RevokeUserSession)
+				}
+
+				dataExport := userAuthenticated.Group("/data-export")
+				{
+					dataExport.POST("", cfg.UserHandler.RequestDataExport)
+					dataExport.GET("", cfg.UserHandler.GetDataExport)
+					dataExport.GET("/download", cfg.UserHandler.DownloadDataExport)
+				}
+
+				identities := userAuthenticated.Group("/identities")
+				{
+					identities.GET("", cfg.UserHandler.GetUserIdentities)
+					identities.POST("/:provider", cfg.UserHandler.RequireFreshTwoFactor, cfg.UserHandler.LinkUserIdentity)
+					identities.DELETE("/:provider", cfg.UserHandler.RequireFreshTwoFactor, cfg.UserHandler.UnlinkUserIdentity)
+				}
+
+				twoFactor := userAuthenticated.Group("/two-factor")
+				{
+					twoFactor.GET("", cfg.UserHandler.GetTwoFactorStatus)
+					twoFactor.POST("/enrolment", cfg.UserHandler.EnrolTwoFactor)
+					twoFactor.POST("/enable", cfg.UserHandler.EnableTwoFactor)
+					twoFactor.POST("/disable", cfg.UserHandler.DisableTwoFactor)
+					twoFactor.POST("/recovery-codes", cfg.UserHandler.RegenerateRecoveryCodes)
+					twoFactor.POST("/verify", cfg.UserHandler.VerifyTwoFactor)
+				}
+
 				profile := userAuthenticated.Group("/profiles")
 				{
 					profile.PUT("", cfg.UserHandler.UpdateProfile)
@@ -123,12 +171,41 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 				chat := userAuthenticated.Group("/chats")
 				{
 					chat.GET("", cfg.ChatHandler.UserGetListOfChats)
+					chat.GET("/unread-count", cfg.ChatHandler.GetUnreadChatCount)
 					chat.GET("/:shopSlug", cfg.ChatHandler.UserGetChat)
 					chat.POST("/:shopSlug", cfg.ChatHandler.UserAddChat)
+					chat.PUT("/:shopSlug/read", cfg.ChatHandler.UserReadChat)
+				}
+				notifications := userAuthenticated.Group("/notifications")
+				{
+					notifications.GET("", cfg.NotificationHandler.GetNotifications)
+					notifications.GET("/unread-count", cfg.NotificationHandler.GetUnreadNotificationCount)
+					notifications.PUT("/read", cfg.NotificationHandler.MarkAllNotificationsRead)
+					notifications.PUT("/:notificationId/read", cfg.NotificationHandler.MarkNotificationRead)
+				}
+				userAuthenticated.GET("/notification-preferences", cfg.NotificationHandler.GetNotificationPreferences)
+				userAuthenticated.PUT("/notification-preferences", cfg.NotificationHandler.UpdateNotificationPreferences)
+				shopMemberships := userAuthenticated.Group("/shop-memberships")
+				{
+					shopMemberships.GET("", cfg.ShopHandler.GetUserShopMemberships)
+					shopMemberships.POST("/accept", cfg.ShopHandler.AcceptShopInvitation)
+				}
+				devices := userAuthenticated.Group("/devices")
+				{
+					devices.GET("", cfg.NotificationHandler.GetPushDevices)
+					devices.POST("", cfg.NotificationHandler.RegisterPushDevice)
+					devices.GET("/vapid-public-key", cfg.NotificationHandler.GetVAPIDPublicKey)
+					devices.DELETE("/:deviceId", cfg.NotificationHandler.DeletePushDevice)
 				}
 			}
 		}
 
+		notification := v1.Group("/notifications")
+		{
+			notification.GET("/unsubscribe", cfg.NotificationHandler.Unsubscribe)
+			notification.POST("/unsubscribe", cfg.NotificationHandler.Unsubscribe)
+		}
+
 		location := v1.Group("/locations")
 		{
 			location.GET("/cities", cfg.LocationHandler.GetCities)
@@ -152,6 +229,12 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 			product.GET("/discussions/:productId", cfg.ProductHandler.GetDiscussionByProductID)
 			product.GET("/discussions/replies/:parentId", cfg.ProductHandler.GetDiscussionByParentID)
 			product.POST("/discussions", middleware.JWTAuthorization, cfg.ProductHandler.PostDiscussion)
+			product.POST("/discussions/:discussionId/votes", middleware.JWTAuthorization, cfg.ProductHandler.VoteDiscussion)
+			product.DELETE("/discussions/:discussionId/votes", middleware.JWTAuthorization, cfg.ProductHandler.UnvoteDiscussion)
+			product.POST("/discussions/:discussionId/reports", middleware.JWTAuthorization, cfg.ProductHandler.ReportDiscussion)
+			product.POST("/reviews/:reviewId/votes", middleware.JWTAuthorization, cfg.ProductHandler.VoteProductReview)
+			product.DELETE("/reviews/:reviewId/votes", middleware.JWTAuthorization, cfg.ProductHandler.UnvoteProductReview)
+			product.POST("/reviews/:reviewId/reports", middleware.JWTAuthorization, cfg.ProductHandler.ReportProductReview)
 			category := product.Group("/categories")
 			{
 				category.GET("", cfg.ProductHandler.GetCategories)
@@ -191,6 +274,7 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 			marketplace.GET("/vouchers", cfg.MarketplaceHandler.GetMarketplaceVoucher)
 			marketplace.POST("/couriers", cfg.ShopHandler.AddCourier)
 			marketplace.GET("/banners", cfg.MarketplaceHandler.GetMarketplaceBanner)
+			marketplace.GET("/flash-sales", cfg.MarketplaceHandler.GetFlashSales)
 			authenticated := marketplace.Group("", middleware.JWTAuthorization, cfg.UserHandler.GetSession)
 			{
 				authenticated.GET("/vouchers/valid", cfg.MarketplaceHandler.GetValidMarketplaceVoucher)
@@ -202,7 +286,7 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 		{
 			authenticated := order.Group("", middleware.JWTAuthorization, cfg.UserHandler.GetSession)
 			{
-				authenticated.POST("", cfg.OrderHandler.Checkout)
+				authenticated.POST("", cfg.UserHandler.RequireVerifiedEmail, cfg.OrderHandler.Checkout)
 				invoice := authenticated.Group("/invoices")
 				{
 					invoice.POST("", cfg.OrderHandler.PayInvoice)
@@ -220,29 +304,53 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 					review := transaction.Group("/reviews")
 					{
 						review.POST("", cfg.OrderHandler.AddTransactionReview)
+						review.PUT("/:reviewId", cfg.OrderHandler.UpdateTransactionReview)
 					}
 				}
 			}
 		}
 
-		// TODO ADD MIDLEWARE FOR AUTH ADMIN
 		admin := v1.Group("/admins")
 		{
-			admin.POST("/login", cfg.UserHandler.AdminSignIn)
-			authenticated := admin.Group("", middleware.AdminJWTAuthorization, cfg.UserHandler.GetSession)
+			admin.POST("/login", cfg.AdminHandler.SignIn)
+			admin.POST("/invitations/accept", cfg.AdminHandler.AcceptInvitation)
+			authenticated := admin.Group("", middleware.AdminJWTAuthorization, cfg.AdminHandler.GetSession, cfg.AdminHandler.AuditAction)
 			{
-				category := authenticated.Group("/categories")
+				authenticated.GET("/me", cfg.AdminHandler.GetProfile)
+
+				staff := authenticated.Group("/staff", middleware.RequireAdminPermission(constant.AdminPermissionManageAdmins))
+				{
+					staff.GET("", cfg.AdminHandler.GetAdmins)
+					staff.POST("", cfg.AdminHandler.InviteAdmin)
+					staff.PUT("/:adminId/role", cfg.AdminHandler.UpdateAdminRole)
+					staff.DELETE("/:adminId", cfg.AdminHandler.RevokeAdmin)
+				}
+				authenticated.GET("/roles", middleware.RequireAdminPermission(constant.AdminPermissionManageAdmins), cfg.AdminHandler.GetRoles)
+				authenticated.GET("/audit-logs", middleware.RequireAdminPermission(constant.AdminPermissionReadAuditLogs), cfg.AdminHandler.GetAuditLogs)
+
+				category := authenticated.Group("/categories", middleware.RequireAdminPermission(constant.AdminPermissionManageCategories))
 				{
 					category.POST("", cfg.ProductHandler.AddCategory)
 				}
 
+				product := authenticated.Group("/products", middleware.RequireAdminPermission(constant.AdminPermissionModerateProducts))
+				{
+					product.GET("/moderations", cfg.ProductHandler.GetModerationQueue)
+					product.GET("/:code", cfg.ProductHandler.GetProductForModeration)
+					product.PUT("/:code/moderations", cfg.ProductHandler.ModerateProduct)
+					product.PUT("/discussions/:discussionId/visibility", cfg.ProductHandler.UpdateDiscussionVisibility)
+				}
+
 				order := authenticated.Group("/orders")
 				{
-					order.GET("/refund", cfg.OrderHandler.GetRefund)
-					order.POST("/:orderId/cancel-commit", cfg.OrderHandler.UpdateToCanceled)
-					order.POST("/refund/:refundId", cfg.OrderHandler.RefundAdmin)
+					order.POST("/:orderId/cancel-commit", middleware.RequireAdminPermission(constant.AdminPermissionManageOrders), cfg.OrderHandler.UpdateToCanceled)
+					refund := order.Group("/refund", middleware.RequireAdminPermission(constant.AdminPermissionManageRefunds))
+					{
+						refund.GET("", cfg.OrderHandler.GetRefund)
+						refund.POST("/:refundId", cfg.OrderHandler.RefundAdmin)
+					}
 				}
-				marketplace := authenticated.Group("/marketplaces")
+				marketplace := authenticated.Group("/marketplaces", middleware.RequireAdminPermission(constant.AdminPermissionManageMarketplace))
 				{
 					marketplace.POST("/banners", cfg.MarketplaceHandler.AddMarketplaceBanner)
 					voucher := marketplace.Group("/vouchers")
@@ -252,6 +360,12 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 						voucher.GET("/:code", cfg.MarketplaceHandler.GetMarketplaceVoucherAdminByCode)
 						voucher.PUT("/:code", cfg.MarketplaceHandler.UpdateVoucher)
 					}
+					flashSale := marketplace.Group("/flash-sales")
+					{
+						flashSale.POST("", cfg.MarketplaceHandler.CreateFlashSaleCampaign)
+						flashSale.GET("/:campaignId/submissions", cfg.MarketplaceHandler.GetFlashSaleSubmissions)
+						flashSale.PUT("/submissions/:submissionId", cfg.MarketplaceHandler.UpdateFlashSaleSubmissionStatus)
+					}
 				}
 			}
 		}
@@ -260,75 +374,112 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 		{
 			authenticated := seller.Group("", middleware.JWTAuthorization, cfg.UserHandler.GetSession)
 			{
-				authenticated.POST("/register", cfg.ShopHandler.CreateShop)
-				authenticated.GET("/stats", cfg.ShopHandler.GetShopStats)
-				authenticated.GET("/insights", cfg.ShopHandler.GetShopInsights)
-				authenticated.GET("/ratings", cfg.ShopHandler.GetShopRating)
-				authenticated.GET("/discussions", cfg.ProductHandler.GetUnrepliedDiscussionByShopID)
-				finance := authenticated.Group("/finances")
+				authenticated.POST("/register", cfg.UserHandler.RequireVerifiedEmail, cfg.ShopHandler.CreateShop)
+				member := authenticated.Group("", cfg.ShopHandler.GetShopMember)
 				{
-					income := finance.Group("/incomes")
+					member.GET("/memberships", cfg.ShopHandler.GetShopMembership)
+					insight := member.Group("", middleware.RequireShopPermission(constant.ShopPermissionViewInsights))
 					{
-						income.GET("", cfg.OrderHandler.GetInvoicePerShopsByShopId)
-						income.GET("/overviews", cfg.ShopHandler.GetShopFinanceOverview)
-						income.POST("/withdrawals", cfg.OrderHandler.WithdrawFromInvoice)
-						income.GET("/:orderId", cfg.OrderHandler.GetInvoiceByShopIdAndOrderId)
+						insight.GET("/stats", cfg.ShopHandler.GetShopStats)
+						insight.GET("/insights", cfg.ShopHandler.GetShopInsights)
+						insight.GET("/ratings", cfg.ShopHandler.GetShopRating)
+					}
+					engagement := member.Group("", middleware.RequireShopPermission(constant.ShopPermissionManageChats))
+					{
+						engagement.GET("/discussions", cfg.ProductHandler.GetUnrepliedDiscussionByShopID)
+						engagement.GET("/discussions/stats", cfg.ProductHandler.GetDiscussionSLAStats)
+						engagement.PUT("/discussions/:discussionId/official", cfg.ProductHandler.MarkOfficialAnswer)
+						engagement.PUT("/reviews/:reviewId/reply", cfg.OrderHandler.ReplyTransactionReview)
+					}
+					finance := member.Group("/finances", middleware.RequireShopPermission(constant.ShopPermissionManageFinance))
+					{
+						income := finance.Group("/incomes")
+						{
+							income.GET("", cfg.OrderHandler.GetInvoicePerShopsByShopId)
+							income.GET("/overviews", cfg.ShopHandler.GetShopFinanceOverview)
+							income.POST("/withdrawals", cfg.UserHandler.RequireFreshTwoFactor, cfg.OrderHandler.WithdrawFromInvoice)
+							income.GET("/:orderId", cfg.OrderHandler.GetInvoiceByShopIdAndOrderId)
+						}
+					}
+					courier := member.Group("/couriers", middleware.RequireShopPermission(constant.ShopPermissionManageShop))
+					{
+						courier.GET("", cfg.ShopHandler.GetShipmentList)
+						courier.POST("", cfg.ShopHandler.ToggleShopCourier)
 					}
-				}
-				courier := authenticated.Group("/couriers")
-				{
-					courier.GET("", cfg.ShopHandler.GetShipmentList)
-					courier.POST("", cfg.ShopHandler.ToggleShopCourier)
-				}
 
-				product := authenticated.Group("/products")
-				{
-					product.GET("", cfg.ProductHandler.GetSellerProducts)
-					product.GET("/:code", cfg.ProductHandler.GetSellerProductDetailByCode)
-					product.PUT("/:code", cfg.ProductHandler.UpdateProduct)
-					product.PUT("/:code/activations", cfg.ProductHandler.UpdateProductActivation)
-				}
+					product := member.Group("/products", middleware.RequireShopPermission(constant.ShopPermissionManageProducts))
+					{
+						product.GET("", cfg.ProductHandler.GetSellerProducts)
+						product.GET("/low-stocks", cfg.ProductHandler.GetLowStockSkus)
+						product.GET("/:code", cfg.ProductHandler.GetSellerProductDetailByCode)
+						product.PUT("/:code", cfg.ProductHandler.UpdateProduct)
+						product.PUT("/:code/activations", cfg.ProductHandler.UpdateProductActivation)
+						product.GET("/:code/stock-history", cfg.ProductHandler.GetStockHistory)
+						product.PUT("/:code/low-stock-thresholds", cfg.ProductHandler.UpdateLowStockThresholds)
+					}
 
-				voucher := authenticated.Group("/vouchers")
-				{
-					voucher.GET("", cfg.ShopHandler.GetSellerVoucher)
-					voucher.GET("/:code", cfg.ShopHandler.GetVoucherByCodeAndShopId)
-					voucher.POST("", cfg.ShopHandler.CreateVoucher)
-					voucher.PUT("/:code", cfg.ShopHandler.UpdateVoucher)
-					voucher.DELETE("/:code", cfg.ShopHandler.DeleteVoucher)
-				}
+					voucher := member.Group("/vouchers", middleware.RequireShopPermission(constant.ShopPermissionManagePromotions))
+					{
+						voucher.GET("", cfg.ShopHandler.GetSellerVoucher)
+						voucher.GET("/:code", cfg.ShopHandler.GetVoucherByCodeAndShopId)
+						voucher.POST("", cfg.ShopHandler.CreateVoucher)
+						voucher.PUT("/:code", cfg.ShopHandler.UpdateVoucher)
+						voucher.DELETE("/:code", cfg.ShopHandler.DeleteVoucher)
+					}
 
-				promotion := authenticated.Group("/promotions")
-				{
-					promotion.GET("", cfg.ShopHandler.GetSellerPromotions)
-					promotion.GET("/:promotionId", cfg.ShopHandler.GetSellerPromotionById)
-					promotion.PUT("/:promotionId", cfg.ShopHandler.UpdatePromotion)
-					promotion.POST("", cfg.ShopHandler.CreateShopPromotion)
-					promotion.DELETE("/:promotionId", cfg.ShopHandler.DeletePromotion)
-				}
+					flashSale := member.Group("/flash-sales", middleware.RequireShopPermission(constant.ShopPermissionManagePromotions))
+					{
+						flashSale.GET("", cfg.MarketplaceHandler.GetOpenFlashSaleSlots)
+						flashSale.GET("/submissions", cfg.MarketplaceHandler.GetSellerFlashSaleSubmissions)
+						flashSale.POST("/slots/:slotId/submissions", cfg.MarketplaceHandler.SubmitFlashSaleSkus)
+					}
 
-				order := authenticated.Group("/orders")
-				{
-					order.GET("", cfg.OrderHandler.GetShopOrder)
-					order.GET("/:orderId", cfg.OrderHandler.GetInvoiceByShopIdAndOrderId)
-					order.PUT("/:orderId/process", cfg.OrderHandler.UpdateToProcessing)
-					order.PUT("/:orderId/delivery", cfg.OrderHandler.UpdateToDelivery)
-					order.POST("/:orderId/cancel-request", cfg.OrderHandler.UpdateToRefundPendingSellerCancel)
-					order.PUT("/:orderId/refund", cfg.OrderHandler.UpdateRefundStatus)
-				}
-				chat := authenticated.Group("/chats")
-				{
-					chat.GET("", cfg.ChatHandler.SellerGetListOfChats)
-					chat.GET("/:username", cfg.ChatHandler.SellerGetChat)
-					chat.POST("/:username", cfg.ChatHandler.SellerAddChat)
-				}
-				category := authenticated.Group("/categories")
-				{
-					category.GET("", cfg.ShopHandler.GetSellerCategories)
-					category.POST("", cfg.ShopHandler.CreateSellerCategory)
-					category.GET(":categoryId", cfg.ShopHandler.GetSellerCategoryDetail)
-					category.PUT(":categoryId", cfg.ShopHandler.UpdateSellerCategory)
-					category.DELETE(":categoryId", cfg.ShopHandler.DeleteSellerCategory)
+					promotion := member.Group("/promotions", middleware.RequireShopPermission(constant.ShopPermissionManagePromotions))
+					{
+						promotion.GET("", cfg.ShopHandler.GetSellerPromotions)
+						promotion.GET("/:promotionId", cfg.ShopHandler.GetSellerPromotionById)
+						promotion.PUT("/:promotionId", cfg.ShopHandler.UpdatePromotion)
+						promotion.POST("", cfg.ShopHandler.CreateShopPromotion)
+						promotion.DELETE("/:promotionId", cfg.ShopHandler.DeletePromotion)
+					}
+
+					order := member.Group("/orders", middleware.RequireShopPermission(constant.ShopPermissionManageOrders))
+					{
+						order.GET("", cfg.OrderHandler.GetShopOrder)
+						order.GET("/:orderId", cfg.OrderHandler.GetInvoiceByShopIdAndOrderId)
+						order.PUT("/:orderId/process", cfg.OrderHandler.UpdateToProcessing)
+						order.PUT("/:orderId/delivery", cfg.OrderHandler.UpdateToDelivery)
+						order.POST("/:orderId/cancel-request", cfg.OrderHandler.UpdateToRefundPendingSellerCancel)
+						order.PUT("/:orderId/refund", cfg.OrderHandler.UpdateRefundStatus)
+					}
+					chat := member.Group("/chats", middleware.RequireShopPermission(constant.ShopPermissionManageChats))
+					{
+						chat.GET("", cfg.ChatHandler.SellerGetListOfChats)
+						chat.GET("/settings", cfg.ChatHandler.GetChatSetting)
+						chat.PUT("/settings", cfg.ChatHandler.UpdateChatSetting)
+						chat.GET("/quick-replies", cfg.ChatHandler.GetQuickReplies)
+						chat.POST("/quick-replies", cfg.ChatHandler.CreateQuickReply)
+						chat.PUT("/quick-replies/:quickReplyId", cfg.ChatHandler.UpdateQuickReply)
+						chat.DELETE("/quick-replies/:quickReplyId", cfg.ChatHandler.DeleteQuickReply)
+						chat.GET("/:username", cfg.ChatHandler.SellerGetChat)
+						chat.POST("/:username", cfg.ChatHandler.SellerAddChat)
+						chat.PUT("/:username/read", cfg.ChatHandler.SellerReadChat)
+					}
+					category := member.Group("/categories", middleware.RequireShopPermission(constant.ShopPermissionManageProducts))
+					{
+						category.GET("", cfg.ShopHandler.GetSellerCategories)
+						category.POST("", cfg.ShopHandler.CreateSellerCategory)
+						category.GET(":categoryId", cfg.ShopHandler.GetSellerCategoryDetail)
+						category.PUT(":categoryId", cfg.ShopHandler.UpdateSellerCategory)
+						category.DELETE(":categoryId", cfg.ShopHandler.DeleteSellerCategory)
+					}
+					staff := member.Group("/staff", middleware.RequireShopPermission(constant.ShopPermissionManageStaff))
+					{
+						staff.GET("", cfg.ShopHandler.GetShopMembers)
+						staff.POST("", cfg.ShopHandler.InviteShopMember)
+						staff.PUT("/:memberId", cfg.ShopHandler.UpdateShopMember)
+						staff.DELETE("/:memberId", cfg.ShopHandler.RevokeShopMember)
+					}
 				}
 			}
 		}
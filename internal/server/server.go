@@ -1,10 +1,13 @@
 package server
 
 import (
+	"context"
 	"log"
 	"time"
 
+	"kedai/backend/be-kedai/config"
 	"kedai/backend/be-kedai/connection"
+	"kedai/backend/be-kedai/internal/common/constant"
 	locationRedisCache "kedai/backend/be-kedai/internal/domain/location/cache"
 	locationHandlerPackage "kedai/backend/be-kedai/internal/domain/location/handler"
 	locationRepoPackage "kedai/backend/be-kedai/internal/domain/location/repository"
@@ -28,15 +31,31 @@ import (
 	shopRepoPackage "kedai/backend/be-kedai/internal/domain/shop/repository"
 	shopServicePackage "kedai/backend/be-kedai/internal/domain/shop/service"
 	mail "kedai/backend/be-kedai/internal/utils/mail"
+	push "kedai/backend/be-kedai/internal/utils/push"
 	random "kedai/backend/be-kedai/internal/utils/random"
 
 	marketplaceHandlerPackage "kedai/backend/be-kedai/internal/domain/marketplace/handler"
 	marketplaceRepoPackage "kedai/backend/be-kedai/internal/domain/marketplace/repository"
 	marketplaceServicePackage "kedai/backend/be-kedai/internal/domain/marketplace/service"
 
+	chatRedisCache "kedai/backend/be-kedai/internal/domain/chat/cache"
 	chatHandlerPackage "kedai/backend/be-kedai/internal/domain/chat/handler"
 	chatRepoPackage "kedai/backend/be-kedai/internal/domain/chat/repository"
 	chatServicePackage "kedai/backend/be-kedai/internal/domain/chat/service"
+	"kedai/backend/be-kedai/internal/utils/attempt"
+	"kedai/backend/be-kedai/internal/utils/encrypt"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	"kedai/backend/be-kedai/internal/utils/identity"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
+
+	notificationHandlerPackage "kedai/backend/be-kedai/internal/domain/notification/handler"
+	notificationRepoPackage "kedai/backend/be-kedai/internal/domain/notification/repository"
+	notificationServicePackage "kedai/backend/be-kedai/internal/domain/notification/service"
+
+	adminRedisCache "kedai/backend/be-kedai/internal/domain/admin/cache"
+	adminHandlerPackage "kedai/backend/be-kedai/internal/domain/admin/handler"
+	adminRepoPackage "kedai/backend/be-kedai/internal/domain/admin/repository"
+	adminServicePackage "kedai/backend/be-kedai/internal/domain/admin/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-co-op/gocron"
@@ -46,6 +65,16 @@ func createRouter() *gin.Engine {
 	db := connection.GetDB()
 	redis := connection.GetCache()
 
+	jwtKeySet, err := jwttoken.NewKeySet(config.JWTSigningKeys, config.JWTVerificationKeys, config.JWTActiveKeyID, config.SecretKey)
+	if err != nil {
+		log.Fatal("couldn't load JWT signing keys:", err)
+	}
+	jwttoken.UseKeySet(jwtKeySet)
+
+	if err := hash.UseTokenKey(config.TokenSigningKey); err != nil {
+		log.Fatal("couldn't load token signing key:", err)
+	}
+
 	userCache := userRedisCache.NewUserCache(&userRedisCache.UserCConfig{
 		RDC: redis,
 	})
@@ -72,6 +101,10 @@ func createRouter() *gin.Engine {
 		DB: db,
 	})
 
+	flashSaleRepo := marketplaceRepoPackage.NewFlashSaleRepository(&marketplaceRepoPackage.FlashSaleRConfig{
+		DB: db,
+	})
+
 	marketplaceVoucherService := marketplaceServicePackage.NewMarketplaceVoucherService(&marketplaceServicePackage.MarketplaceVoucherSConfig{
 		MarketplaceVoucherRepository: marketplaceVoucherRepo,
 	})
@@ -82,6 +115,10 @@ func createRouter() *gin.Engine {
 
 	mailer := connection.GetMailer()
 	mailUtils := mail.NewMailUtils(&mail.MailUtilsConfig{Mailer: mailer})
+	mailSink, err := mail.NewSink(&config.Mail, mailer)
+	if err != nil {
+		log.Fatal(err)
+	}
 	randomUtils := random.NewRandomUtils(&random.RandomUtilsConfig{})
 	maps := connection.GetGoogleMaps()
 
@@ -131,9 +168,14 @@ func createRouter() *gin.Engine {
 		DB: db,
 	})
 
+	inventoryMovementRepo := productRepoPackage.NewInventoryMovementRepository(&productRepoPackage.InventoryMovementRConfig{
+		DB: db,
+	})
+
 	skuRepo := productRepoPackage.NewSkuRepository(&productRepoPackage.SkuRConfig{
-		DB:                         db,
-		ProductPromotionRepository: productPromotionRepo,
+		DB:                          db,
+		ProductPromotionRepository:  productPromotionRepo,
+		InventoryMovementRepository: inventoryMovementRepo,
 	})
 
 	userCartItemRepo := userRepoPackage.NewUserCartItemRepository(&userRepoPackage.UserCartItemRConfig{
@@ -146,7 +188,6 @@ func createRouter() *gin.Engine {
 		SkuRepo:           skuRepo,
 		UserWalletRepo:    walletRepo,
 		InvoiceStatusRepo: invoiceStatusRepo,
-		Redis:             userCache,
 	})
 
 	refundRequestRepo := orderRepoPackage.NewRefundRequestRepository(&orderRepoPackage.RefundRequestRConfig{
@@ -177,10 +218,6 @@ func createRouter() *gin.Engine {
 		UserVoucherRepository: userVoucherRepo,
 	})
 
-	skuService := productServicePackage.NewSkuService(&productServicePackage.SkuSConfig{
-		SkuRepository: skuRepo,
-	})
-
 	categoryRepo := productRepoPackage.NewCategoryRepository(&productRepoPackage.CategoryRConfig{
 		DB: db,
 	})
@@ -232,11 +269,23 @@ func createRouter() *gin.Engine {
 		InvoicePerShopRepo: invoicePerShopRepo,
 	})
 
+	shopMemberRepo := shopRepoPackage.NewShopMemberRepository(&shopRepoPackage.ShopMemberRConfig{
+		DB: db,
+	})
+
 	shopService := shopServicePackage.NewShopService(&shopServicePackage.ShopSConfig{
 		ShopRepository:        shopRepo,
+		ShopMemberRepository:  shopMemberRepo,
 		CourierServiceService: courierServiceService,
 	})
 
+	skuService := productServicePackage.NewSkuService(&productServicePackage.SkuSConfig{
+		SkuRepository:               skuRepo,
+		InventoryMovementRepository: inventoryMovementRepo,
+		ShopService:                 shopService,
+		MailUtils:                   mailUtils,
+	})
+
 	shopVoucherService := shopServicePackage.NewShopVoucherService(&shopServicePackage.ShopVoucherSConfig{
 		ShopVoucherRepository: shopVoucherRepo,
 		ShopService:           shopService,
@@ -247,9 +296,67 @@ func createRouter() *gin.Engine {
 		ShopService:       shopService,
 	})
 
+	socketServer := connection.SocketIO()
+	var chatBroadcaster chatServicePackage.ChatBroadcaster = socketServer
+	socketAdapter := connection.NewSocketIORedisAdapter(socketServer, redis)
+	if err := socketAdapter.Start(context.Background()); err != nil {
+		log.Println("socketio redis adapter disabled, broadcasting locally:", err)
+	} else {
+		chatBroadcaster = socketAdapter
+	}
+
+	notificationPreferenceService := notificationServicePackage.NewNotificationPreferenceService(&notificationServicePackage.NotificationPreferenceSConfig{
+		PreferenceRepo: notificationRepoPackage.NewNotificationPreferenceRepository(&notificationRepoPackage.NotificationPreferenceRConfig{
+			DB: db,
+		}),
+	})
+
+	pushSender, err := push.NewSender(&config.Push)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pushService := notificationServicePackage.NewPushService(&notificationServicePackage.PushSConfig{
+		PushDeviceRepo: notificationRepoPackage.NewPushDeviceRepository(&notificationRepoPackage.PushDeviceRConfig{
+			DB: db,
+		}),
+		Sender: pushSender,
+	})
+
+	presenceCache := chatRedisCache.NewPresenceCache(&chatRedisCache.PresenceCConfig{
+		RDC: redis,
+	})
+
+	notificationService := notificationServicePackage.NewNotificationService(&notificationServicePackage.NotificationSConfig{
+		NotificationRepo: notificationRepoPackage.NewNotificationRepository(&notificationRepoPackage.NotificationRConfig{
+			DB: db,
+		}),
+		Broadcaster: chatBroadcaster,
+		Preferences: notificationPreferenceService,
+		Pusher:      pushService,
+		Presence:    presenceCache,
+	})
+
+	emailService := notificationServicePackage.NewEmailService(&notificationServicePackage.EmailSConfig{
+		EmailOutboxRepo: notificationRepoPackage.NewEmailOutboxRepository(&notificationRepoPackage.EmailOutboxRConfig{
+			DB: db,
+		}),
+		Sink:        mailSink,
+		Preferences: notificationPreferenceService,
+	})
+
 	discussionService := productServicePackage.NewDiscussionService(&productServicePackage.DiscussionSConfig{
-		DiscussionRepository: discussionRepo,
-		ShopService:          shopService,
+		DiscussionRepository:  discussionRepo,
+		ShopService:           shopService,
+		MailUtils:             mailUtils,
+		NotificationPublisher: notificationService,
+	})
+
+	productModerationService := productServicePackage.NewProductModerationService(&productServicePackage.ProductModerationSConfig{
+		ProductRepository: productRepo,
+		CategoryService:   categoryService,
+		MailUtils:         mailUtils,
+		BannedKeywords:    config.BannedKeywords,
 	})
 
 	productService := productServicePackage.NewProductService(&productServicePackage.ProductSConfig{
@@ -260,6 +367,7 @@ func createRouter() *gin.Engine {
 		CategoryService:       categoryService,
 		CourierServiceService: courierServiceService,
 		DiscussionService:     discussionService,
+		ModerationService:     productModerationService,
 	})
 
 	shopPromotionRepo := shopRepoPackage.NewShopPromotionRepository(&shopRepoPackage.ShopPromotionRConfig{
@@ -282,22 +390,12 @@ func createRouter() *gin.Engine {
 		ShopService:      shopService,
 	})
 
-	shopHandler := shopHandlerPackage.New(&shopHandlerPackage.HandlerConfig{
-		ShopService:          shopService,
-		ShopVoucherService:   shopVoucherService,
-		ShopPromotionService: shopPromotionService,
-		CourierService:       courierService,
-		ShopGuestService:     shopGuestService,
-		ShopCategoryService:  shopCategoryService,
-	})
-
 	userProfileRepo := userRepoPackage.NewUserProfileRepository(&userRepoPackage.UserProfileRConfig{
 		DB: db,
 	})
 
 	userRepo := userRepoPackage.NewUserRepository(&userRepoPackage.UserRConfig{
 		DB:              db,
-		UserCache:       userCache,
 		UserProfileRepo: userProfileRepo,
 	})
 
@@ -310,14 +408,92 @@ func createRouter() *gin.Engine {
 	})
 
 	refundRequestService := orderServicePackage.NewRefundRequestService(&orderServicePackage.RefundRequestSConfig{
-		RefundRequestRepo: refundRequestRepo,
-		ShopService:       shopService,
+		RefundRequestRepo:     refundRequestRepo,
+		InvoicePerShopRepo:    invoicePerShopRepo,
+		ShopService:           shopService,
+		NotificationPublisher: notificationService,
+		EmailPublisher:        emailService,
+	})
+
+	attemptLimiter := attempt.NewLimiter(&attempt.LimiterConfig{
+		RDC: redis,
+		Policy: attempt.Policy{
+			FreeFailures: constant.AttemptFreeFailures,
+			BaseDelay:    constant.AttemptBaseDelay,
+			MaxDelay:     constant.AttemptMaxDelay,
+			MaxFailures:  constant.AttemptMaxFailures,
+			Window:       constant.AttemptWindow,
+			Lockout:      constant.AttemptLockout,
+		},
+	})
+
+	twoFactorKeyring, err := encrypt.NewKeyring(config.TwoFactorMasterKeys, config.TwoFactorMasterKeyVersion)
+	if err != nil {
+		log.Fatal("couldn't load two-factor master keys:", err)
+	}
+
+	sessionService := userServicePackage.NewUserSessionService(&userServicePackage.UserSessionSConfig{
+		SessionRepo: userRepoPackage.NewUserSessionRepository(&userRepoPackage.UserSessionRConfig{
+			DB: db,
+		}),
+		UserRepo:       userRepo,
+		UserCache:      userCache,
+		RandomUtils:    randomUtils,
+		EmailPublisher: emailService,
+	})
+
+	twoFactorService := userServicePackage.NewTwoFactorService(&userServicePackage.TwoFactorSConfig{
+		TwoFactorRepo: userRepoPackage.NewUserTwoFactorRepository(&userRepoPackage.UserTwoFactorRConfig{
+			DB: db,
+		}),
+		UserRepo: userRepo,
+		TwoFactorCache: userRedisCache.NewTwoFactorCache(&userRedisCache.TwoFactorCConfig{
+			RDC: redis,
+		}),
+		SessionService: sessionService,
+		RandomUtils:    randomUtils,
+		Keyring:        twoFactorKeyring,
+		Limiter:        attemptLimiter,
 	})
+
 	userService := userServicePackage.NewUserService(&userServicePackage.UserSConfig{
-		Repository:  userRepo,
-		Redis:       userCache,
-		MailUtils:   mailUtils,
-		RandomUtils: randomUtils,
+		Repository:       userRepo,
+		Redis:            userCache,
+		MailUtils:        mailUtils,
+		RandomUtils:      randomUtils,
+		TwoFactorService: twoFactorService,
+		SessionService:   sessionService,
+		Limiter:          attemptLimiter,
+		EmailPublisher:   emailService,
+	})
+
+	identityProviders, err := identity.ParseOIDCProviders(config.OIDCProviders)
+	if err != nil {
+		log.Fatal("couldn't load identity providers:", err)
+	}
+	if config.GoogleClientID != "" {
+		identityProviders = append(identityProviders, identity.NewGoogleProvider(config.GoogleClientID))
+	}
+	if config.AppleClientID != "" {
+		identityProviders = append(identityProviders, identity.NewAppleProvider(config.AppleClientID))
+	}
+	if config.FacebookAppID != "" {
+		identityProviders = append(identityProviders, identity.NewFacebookProvider(&identity.FacebookConfig{
+			AppID:     config.FacebookAppID,
+			AppSecret: config.FacebookAppSecret,
+		}))
+	}
+
+	userIdentityService := userServicePackage.NewUserIdentityService(&userServicePackage.UserIdentitySConfig{
+		IdentityRepo: userRepoPackage.NewUserIdentityRepository(&userRepoPackage.UserIdentityRConfig{
+			DB: db,
+		}),
+		UserRepo:         userRepo,
+		UserService:      userService,
+		SessionService:   sessionService,
+		TwoFactorService: twoFactorService,
+		EmailPublisher:   emailService,
+		Providers:        identity.NewRegistry(identityProviders...),
 	})
 
 	userProfileService := userServicePackage.NewUserProfileService(&userServicePackage.UserProfileSConfig{
@@ -325,12 +501,13 @@ func createRouter() *gin.Engine {
 	})
 
 	walletService := userServicePackage.NewWalletService(&userServicePackage.WalletSConfig{
-		WalletRepo:  walletRepo,
-		UserCache:   userCache,
-		WalletCache: walletCache,
-		UserService: userService,
-		MailUtils:   mailUtils,
-		RandomUtils: randomUtils,
+		WalletRepo:     walletRepo,
+		WalletCache:    walletCache,
+		UserService:    userService,
+		SessionService: sessionService,
+		MailUtils:      mailUtils,
+		RandomUtils:    randomUtils,
+		Limiter:        attemptLimiter,
 	})
 
 	walletHistoryService := userServicePackage.NewWalletHistoryService(&userServicePackage.WalletHistorySConfig{
@@ -393,31 +570,30 @@ func createRouter() *gin.Engine {
 		SealabsPayRepo: sealabsPayRepo,
 	})
 
-	userHandler := userHandlerPackage.New(&userHandlerPackage.HandlerConfig{
-		UserService:          userService,
-		WalletService:        walletService,
-		WalletHistoryService: walletHistoryService,
-		UserWishlistService:  userWishlistService,
-		UserCartItemService:  userCartItemService,
-		SealabsPayService:    sealabsPayService,
-		AddressService:       addressService,
-		UserProfileService:   userProfileService,
+	flashSaleService := marketplaceServicePackage.NewFlashSaleService(&marketplaceServicePackage.FlashSaleSConfig{
+		FlashSaleRepository: flashSaleRepo,
+		ShopService:         shopService,
 	})
+
 	marketplaceHandler := marketplaceHandlerPackage.New(&marketplaceHandlerPackage.HandlerConfig{
 		MarketplaceVoucherService: marketplaceVoucherService,
 		MarketplaceBannerService:  marketplaceBannerService,
+		FlashSaleService:          flashSaleService,
 	})
 
 	invoicePerShopService := orderServicePackage.NewInvoicePerShopService(&orderServicePackage.InvoicePerShopSConfig{
-		InvoicePerShopRepo: invoicePerShopRepo,
-		ShopService:        shopService,
-		WalletService:      walletService,
+		InvoicePerShopRepo:    invoicePerShopRepo,
+		ShopService:           shopService,
+		WalletService:         walletService,
+		NotificationPublisher: notificationService,
+		EmailPublisher:        emailService,
 	})
 	transactionReviewService := orderServicePackage.NewTransactionReviewService(&orderServicePackage.TransactionReviewSConfig{
 		TransactionReviewRepo: transactionReviewRepo,
 		TransactionService:    transactionService,
 		InvoicePerShopService: invoicePerShopService,
 		ProductService:        productService,
+		ShopService:           shopService,
 	})
 
 	productHandler := productHandlerPackage.New(&productHandlerPackage.Config{
@@ -426,18 +602,23 @@ func createRouter() *gin.Engine {
 		SkuService:               skuService,
 		TransactionReviewService: transactionReviewService,
 		DiscussionService:        discussionService,
+		ProductModerationService: productModerationService,
 	})
 
 	invoiceService := orderServicePackage.NewInvoiceService(&orderServicePackage.InvoiceSConfig{
 		InvoiceRepo:               invoiceRepo,
+		InvoicePerShopRepo:        invoicePerShopRepo,
 		AddressService:            addressService,
 		ShopService:               shopService,
 		ShopVoucherService:        shopVoucherService,
 		CartItemService:           userCartItemService,
 		ShopCourierService:        courierService,
 		MarketplaceVoucherService: marketplaceVoucherService,
+		FlashSaleService:          flashSaleService,
 		SealabsPayService:         sealabsPayService,
 		WalletService:             walletService,
+		EmailPublisher:            emailService,
+		UserService:               userService,
 	})
 
 	orderHandler := orderHandlerPackage.New(&orderHandlerPackage.Config{
@@ -455,28 +636,164 @@ func createRouter() *gin.Engine {
 		AddressService:     addressService,
 	})
 
+	chatSettingService := chatServicePackage.NewChatSettingService(&chatServicePackage.ChatSettingSConfig{
+		ChatSettingRepo: chatRepoPackage.NewChatSettingRepository(&chatRepoPackage.ChatSettingRConfig{
+			DB: db,
+		}),
+		ShopService: shopService,
+	})
+
+	chatKeyring, err := encrypt.NewKeyring(config.ChatMasterKeys, config.ChatMasterKeyVersion)
+	if err != nil {
+		log.Fatal("couldn't load chat master keys:", err)
+	}
+
+	chatRepo := chatRepoPackage.NewChatRepository(&chatRepoPackage.ChatRConfig{
+		DB: db,
+		ChatKeyRepo: chatRepoPackage.NewChatKeyRepository(&chatRepoPackage.ChatKeyRConfig{
+			DB:      db,
+			Keyring: chatKeyring,
+		}),
+	})
+
+	if len(config.AES16SecretKey) == 0 {
+		legacyChats, err := chatRepo.CountLegacy()
+		if err != nil {
+			log.Fatal("couldn't count legacy chats:", err)
+		}
+		if legacyChats > 0 {
+			log.Fatalf("%d chats were stored before envelope encryption; set AES_16_SECRET_KEY or migrate them with cmd/chat-key-rotation", legacyChats)
+		}
+	}
+
+	chatService := chatServicePackage.NewChatService(&chatServicePackage.ChatConfig{
+		ChatRepo:              chatRepo,
+		ShopService:           shopService,
+		UserService:           userService,
+		ProductService:        productService,
+		InvoiceService:        invoicePerShopService,
+		VoucherService:        shopVoucherService,
+		SettingService:        chatSettingService,
+		Broadcaster:           chatBroadcaster,
+		PresenceCache:         presenceCache,
+		NotificationPublisher: notificationService,
+	})
+
+	shopMemberService := shopServicePackage.NewShopMemberService(&shopServicePackage.ShopMemberSConfig{
+		ShopMemberRepo: shopMemberRepo,
+		ShopRepo:       shopRepo,
+		RandomUtils:    randomUtils,
+		EmailPublisher: emailService,
+		UserRepo:       userRepo,
+	})
+
 	chatHandler := chatHandlerPackage.New(&chatHandlerPackage.Config{
-		ChatService: chatServicePackage.NewChatService(&chatServicePackage.ChatConfig{
-			ChatRepo: chatRepoPackage.NewChatRepository(&chatRepoPackage.ChatRConfig{
+		ChatService:        chatService,
+		ChatSettingService: chatSettingService,
+		UserService:        userService,
+		ShopMemberService:  shopMemberService,
+	})
+	chatHandler.RegisterSocketEvents(socketServer)
+
+	userAccountService := userServicePackage.NewUserAccountService(&userServicePackage.UserAccountSConfig{
+		UserRepo:          userRepo,
+		WalletRepo:        walletRepo,
+		WalletHistoryRepo: walletHistoryRepo,
+		WishlistRepo:      userWishlistRepo,
+		DataExportRepo: userRepoPackage.NewUserDataExportRepository(&userRepoPackage.UserDataExportRConfig{
+			DB: db,
+		}),
+		ShopService:    shopService,
+		SessionService: sessionService,
+		EmailPublisher: emailService,
+		RandomUtils:    randomUtils,
+		Limiter:        attemptLimiter,
+		UserCache:      userCache,
+		Exporters: []userServicePackage.AccountDataExporter{
+			addressService,
+			invoicePerShopService,
+			transactionReviewService,
+			chatService,
+		},
+		Guards: []userServicePackage.AccountDeletionGuard{
+			refundRequestService,
+			invoicePerShopService,
+		},
+	})
+
+	userHandler := userHandlerPackage.New(&userHandlerPackage.HandlerConfig{
+		UserService:          userService,
+		WalletService:        walletService,
+		WalletHistoryService: walletHistoryService,
+		UserWishlistService:  userWishlistService,
+		UserCartItemService:  userCartItemService,
+		SealabsPayService:    sealabsPayService,
+		AddressService:       addressService,
+		UserProfileService:   userProfileService,
+		TwoFactorService:     twoFactorService,
+		SessionService:       sessionService,
+		IdentityService:      userIdentityService,
+		AccountService:       userAccountService,
+	})
+
+	shopHandler := shopHandlerPackage.New(&shopHandlerPackage.HandlerConfig{
+		ShopService:          shopService,
+		ShopVoucherService:   shopVoucherService,
+		ShopPromotionService: shopPromotionService,
+		CourierService:       courierService,
+		ShopGuestService:     shopGuestService,
+		ShopCategoryService:  shopCategoryService,
+		ShopMemberService:    shopMemberService,
+		ChatService:          chatService,
+	})
+
+	notificationHandler := notificationHandlerPackage.New(&notificationHandlerPackage.Config{
+		NotificationService:           notificationService,
+		EmailService:                  emailService,
+		NotificationPreferenceService: notificationPreferenceService,
+		PushService:                   pushService,
+	})
+
+	adminService := adminServicePackage.NewAdminService(&adminServicePackage.AdminSConfig{
+		AdminRepo: adminRepoPackage.NewAdminRepository(&adminRepoPackage.AdminRConfig{
+			DB: db,
+		}),
+		AdminRoleRepo: adminRepoPackage.NewAdminRoleRepository(&adminRepoPackage.AdminRoleRConfig{
+			DB: db,
+		}),
+		Cache: adminRedisCache.NewAdminCache(&adminRedisCache.AdminCConfig{
+			RDC: redis,
+		}),
+		RandomUtils:    randomUtils,
+		EmailPublisher: emailService,
+		Limiter:        attemptLimiter,
+	})
+	if err := adminService.Bootstrap(config.AdminEmail, config.AdminPassword); err != nil {
+		log.Println("failed to bootstrap super admin:", err)
+	}
+
+	adminHandler := adminHandlerPackage.New(&adminHandlerPackage.Config{
+		AdminService: adminService,
+		AdminAuditService: adminServicePackage.NewAdminAuditService(&adminServicePackage.AdminAuditSConfig{
+			AuditLogRepo: adminRepoPackage.NewAdminAuditLogRepository(&adminRepoPackage.AdminAuditLogRConfig{
 				DB: db,
 			}),
-			ShopService:    shopService,
-			UserService:    userService,
-			ProductService: productService,
-			InvoiceService: invoicePerShopService,
 		}),
 	})
 
-	startCron(orderHandler)
+	startCron(orderHandler, productHandler, notificationHandler, userHandler)
 
 	return NewRouter(&RouterConfig{
-		UserHandler:        userHandler,
-		LocationHandler:    locHandler,
-		ProductHandler:     productHandler,
-		ShopHandler:        shopHandler,
-		MarketplaceHandler: marketplaceHandler,
-		OrderHandler:       orderHandler,
-		ChatHandler:        chatHandler,
+		UserHandler:         userHandler,
+		LocationHandler:     locHandler,
+		ProductHandler:      productHandler,
+		ShopHandler:         shopHandler,
+		MarketplaceHandler:  marketplaceHandler,
+		OrderHandler:        orderHandler,
+		ChatHandler:         chatHandler,
+		NotificationHandler: notificationHandler,
+		AdminHandler:        adminHandler,
+		SocketServer:        socketServer,
 	})
 }
 
@@ -490,7 +807,7 @@ func Init() {
 	}
 }
 
-func startCron(handler *orderHandlerPackage.Handler) {
+func startCron(handler *orderHandlerPackage.Handler, productHandler *productHandlerPackage.Handler, notificationHandler *notificationHandlerPackage.Handler, userHandler *userHandlerPackage.Handler) {
 
 	scheduler := gocron.NewScheduler(time.UTC)
 
@@ -514,6 +831,46 @@ func startCron(handler *orderHandlerPackage.Handler) {
 		log.Println(err)
 	}
 
+	_, err = scheduler.Every(10).Minutes().Do(func() {
+		c := gin.Context{}
+
+		productHandler.LowStockCronJob(&c)
+	})
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = scheduler.Every(1).Hours().Do(func() {
+		c := gin.Context{}
+
+		notificationHandler.VoucherExpiryCronJob(&c)
+	})
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = scheduler.Every(1).Minutes().Do(func() {
+		c := gin.Context{}
+
+		notificationHandler.EmailOutboxCronJob(&c)
+	})
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = scheduler.Every(1).Minutes().Do(func() {
+		c := gin.Context{}
+
+		userHandler.DataExportCronJob(&c)
+	})
+
+	if err != nil {
+		log.Println(err)
+	}
+
 	scheduler.StartAsync()
 
 }
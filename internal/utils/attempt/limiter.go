@@ -0,0 +1,214 @@
+package attempt
+
+import (
+	"context"
+	"fmt"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Policy describes how failures against one key are throttled.
+type Policy struct {
+	FreeFailures int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	MaxFailures  int
+	Window       time.Duration
+	Lockout      time.Duration
+}
+
+// Delay returns how long the next attempt has to wait after the given number
+// of consecutive failures.
+func (p Policy) Delay(failures int) time.Duration {
+	if failures <= p.FreeFailures {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeFailures + 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay
+}
+
+// Limiter counts credential and code checks per key. Callers check every key
+// that applies (usually the account and the client IP) before verifying,
+// which counts the attempt against each of them up front. When verification
+// fails they record the failure against each key, and once it succeeds they
+// reset the account key and release the others.
+type Limiter interface {
+	Check(keys ...string) error
+	Fail(key string) (bool, error)
+	Reset(key string) error
+	Release(key string) error
+}
+
+type limiterImpl struct {
+	rdc    *redis.Client
+	policy Policy
+}
+
+type LimiterConfig struct {
+	RDC    *redis.Client
+	Policy Policy
+}
+
+func NewLimiter(cfg *LimiterConfig) Limiter {
+	return &limiterImpl{
+		rdc:    cfg.RDC,
+		policy: cfg.Policy,
+	}
+}
+
+// AccountKey names the counter for one account within scope.
+func AccountKey(scope string, account interface{}) string {
+	return fmt.Sprintf("%s:account:%v", scope, account)
+}
+
+// IPKey names the counter for one client IP within scope.
+func IPKey(scope string, ip string) string {
+	return fmt.Sprintf("%s:ip:%s", scope, ip)
+}
+
+// checkScript refuses the attempt while any key is locked out, waiting out a
+// delay or already has MaxFailures attempts counted. Otherwise it counts the
+// attempt against every key, starting the window with the first one, and
+// makes the next attempt wait as the policy says. Counting before the
+// credential is verified keeps concurrent attempts from all getting through
+// before the first of them fails. KEYS holds the failures, delayed and
+// locked key of every key in turn.
+var checkScript = redis.NewScript(`
+local maxFailures = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local freeFailures = tonumber(ARGV[3])
+local baseDelay = tonumber(ARGV[4])
+local maxDelay = tonumber(ARGV[5])
+
+for i = 1, #KEYS, 3 do
+	if redis.call("EXISTS", KEYS[i + 1], KEYS[i + 2]) > 0 then
+		return 0
+	end
+	if tonumber(redis.call("GET", KEYS[i]) or "0") >= maxFailures then
+		return 0
+	end
+end
+
+for i = 1, #KEYS, 3 do
+	local failures = redis.call("INCR", KEYS[i])
+	if failures == 1 and window > 0 then
+		redis.call("PEXPIRE", KEYS[i], window)
+	end
+
+	if failures > freeFailures then
+		local delay = baseDelay
+		local counted = freeFailures + 1
+		while counted < failures and delay < maxDelay do
+			delay = delay * 2
+			counted = counted + 1
+		end
+		if delay > maxDelay then
+			delay = maxDelay
+		end
+		if delay > 0 then
+			redis.call("SET", KEYS[i + 1], failures, "PX", delay)
+		end
+	end
+end
+
+return 1
+`)
+
+// failScript locks the key out once the failed attempt was its MaxFailures
+// one, and reports whether it did.
+var failScript = redis.NewScript(`
+local failures = tonumber(redis.call("GET", KEYS[1]) or "0")
+if failures < tonumber(ARGV[1]) then
+	return 0
+end
+
+redis.call("SET", KEYS[3], failures, "PX", ARGV[2])
+redis.call("DEL", KEYS[1], KEYS[2])
+return 1
+`)
+
+// releaseScript takes back one attempt counted against the key.
+var releaseScript = redis.NewScript(`
+if tonumber(redis.call("GET", KEYS[1]) or "0") > 0 then
+	redis.call("DECR", KEYS[1])
+end
+return 1
+`)
+
+// Check returns ErrTooManyAttempts while any key is throttled, and otherwise
+// counts the attempt against all of them.
+func (l *limiterImpl) Check(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	scriptKeys := make([]string, 0, len(keys)*3)
+	for _, key := range keys {
+		scriptKeys = append(scriptKeys, failuresKey(key), delayedKey(key), lockedKey(key))
+	}
+
+	allowed, err := checkScript.Run(context.Background(), l.rdc, scriptKeys,
+		l.policy.MaxFailures,
+		l.policy.Window.Milliseconds(),
+		l.policy.FreeFailures,
+		l.policy.BaseDelay.Milliseconds(),
+		l.policy.MaxDelay.Milliseconds(),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if allowed == 0 {
+		return errs.ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+// Fail records that the attempt Check counted against key failed, and
+// reports whether it locked key out.
+func (l *limiterImpl) Fail(key string) (bool, error) {
+	locked, err := failScript.Run(context.Background(), l.rdc,
+		[]string{failuresKey(key), delayedKey(key), lockedKey(key)},
+		l.policy.MaxFailures,
+		l.policy.Lockout.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return locked == 1, nil
+}
+
+// Reset forgets the attempts counted against key. A lockout already in place
+// still runs its course.
+func (l *limiterImpl) Reset(key string) error {
+	return l.rdc.Del(context.Background(), failuresKey(key), delayedKey(key)).Err()
+}
+
+// Release takes back the attempt Check counted against key, for keys shared
+// by many accounts such as an IP, which a success must not reset.
+func (l *limiterImpl) Release(key string) error {
+	return releaseScript.Run(context.Background(), l.rdc, []string{failuresKey(key)}).Err()
+}
+
+func failuresKey(key string) string {
+	return fmt.Sprintf("attempt:%s:failures", key)
+}
+
+func delayedKey(key string) string {
+	return fmt.Sprintf("attempt:%s:delayed", key)
+}
+
+func lockedKey(key string) string {
+	return fmt.Sprintf("attempt:%s:locked", key)
+}
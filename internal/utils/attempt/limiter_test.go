@@ -0,0 +1,158 @@
+package attempt_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	errs "kedai/backend/be-kedai/internal/common/error"
+	. "kedai/backend/be-kedai/internal/utils/attempt"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLimiter(t *testing.T, policy Policy) (*miniredis.Miniredis, Limiter) {
+	redisServer := miniredis.RunT(t)
+	rdc := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	t.Cleanup(func() { rdc.Close() })
+
+	return redisServer, NewLimiter(&LimiterConfig{RDC: rdc, Policy: policy})
+}
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{
+		FreeFailures: 3,
+		BaseDelay:    time.Second,
+		MaxDelay:     5 * time.Second,
+	}
+
+	tests := []struct {
+		description string
+		failures    int
+		expected    time.Duration
+	}{
+		{
+			description: "should not delay while failures are free",
+			failures:    3,
+			expected:    0,
+		},
+		{
+			description: "should wait the base delay after the first counted failure",
+			failures:    4,
+			expected:    time.Second,
+		},
+		{
+			description: "should double the delay on every further failure",
+			failures:    6,
+			expected:    4 * time.Second,
+		},
+		{
+			description: "should cap the delay",
+			failures:    9,
+			expected:    5 * time.Second,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, policy.Delay(tc.failures))
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	assert.Equal(t, "user_login:account:a@mail.com", AccountKey("user_login", "a@mail.com"))
+	assert.Equal(t, "two_factor:account:1", AccountKey("two_factor", 1))
+	assert.Equal(t, "user_login:ip:127.0.0.1", IPKey("user_login", "127.0.0.1"))
+}
+
+func TestLimiter(t *testing.T) {
+	policy := Policy{
+		FreeFailures: 2,
+		BaseDelay:    time.Second,
+		MaxDelay:     4 * time.Second,
+		MaxFailures:  5,
+		Window:       time.Minute,
+		Lockout:      time.Hour,
+	}
+
+	t.Run("should count the attempt within the window", func(t *testing.T) {
+		redisServer, limiter := newLimiter(t, policy)
+
+		assert.NoError(t, limiter.Check("key"))
+
+		assert.Equal(t, time.Minute, redisServer.TTL("attempt:key:failures"))
+	})
+
+	t.Run("should delay the attempt after the free failures", func(t *testing.T) {
+		redisServer, limiter := newLimiter(t, policy)
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, limiter.Check("key"))
+			_, _ = limiter.Fail("key")
+		}
+
+		assert.Equal(t, errs.ErrTooManyAttempts, limiter.Check("key"))
+
+		redisServer.FastForward(time.Second)
+		assert.NoError(t, limiter.Check("key"))
+	})
+
+	t.Run("should lock the key out on the last failure of the window", func(t *testing.T) {
+		redisServer, limiter := newLimiter(t, policy)
+		var locked bool
+		for i := 0; i < 5; i++ {
+			redisServer.FastForward(policy.MaxDelay)
+			assert.NoError(t, limiter.Check("key"))
+			locked, _ = limiter.Fail("key")
+		}
+
+		assert.True(t, locked)
+		redisServer.FastForward(policy.Window)
+		assert.Equal(t, errs.ErrTooManyAttempts, limiter.Check("key"))
+	})
+
+	t.Run("should refuse every key when one of them is throttled", func(t *testing.T) {
+		_, limiter := newLimiter(t, policy)
+		for i := 0; i < 3; i++ {
+			_ = limiter.Check("ip")
+		}
+
+		assert.Equal(t, errs.ErrTooManyAttempts, limiter.Check("account", "ip"))
+		assert.NoError(t, limiter.Check("account"))
+	})
+
+	t.Run("should only let MaxFailures concurrent attempts through", func(t *testing.T) {
+		_, limiter := newLimiter(t, Policy{MaxFailures: 5, Window: time.Minute, Lockout: time.Hour})
+
+		var wg sync.WaitGroup
+		results := make([]error, 20)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i] = limiter.Check("key")
+			}(i)
+		}
+		wg.Wait()
+
+		var allowed int
+		for _, err := range results {
+			if err == nil {
+				allowed++
+			}
+		}
+		assert.Equal(t, 5, allowed)
+	})
+
+	t.Run("should take back released and reset attempts", func(t *testing.T) {
+		_, limiter := newLimiter(t, Policy{MaxFailures: 1, Window: time.Minute, Lockout: time.Hour})
+
+		assert.NoError(t, limiter.Check("account", "ip"))
+		assert.NoError(t, limiter.Reset("account"))
+		assert.NoError(t, limiter.Release("ip"))
+
+		assert.NoError(t, limiter.Check("account", "ip"))
+	})
+}
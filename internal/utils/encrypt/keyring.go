@@ -0,0 +1,154 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const DataKeySize = 32
+
+var (
+	ErrNoMasterKey         = errors.New("no master key configured")
+	ErrInvalidMasterKey    = errors.New("master key must be version:base64 of 16, 24 or 32 bytes")
+	ErrUnknownMasterKey    = errors.New("master key version not configured")
+	ErrInvalidSealedString = errors.New("invalid sealed string")
+)
+
+// Keyring holds versioned master keys used to wrap data keys. New data keys
+// are always wrapped with the active version; older versions stay loaded so
+// keys wrapped before a rotation can still be unwrapped.
+type Keyring struct {
+	active int
+	keys   map[int][]byte
+}
+
+// NewKeyring parses entries of the form "version:base64key". The active
+// version defaults to the highest one when empty.
+func NewKeyring(entries []string, active string) (*Keyring, error) {
+	keyring := &Keyring{keys: map[int][]byte{}}
+
+	for _, entry := range entries {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 {
+			return nil, ErrInvalidMasterKey
+		}
+
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || version < 1 {
+			return nil, ErrInvalidMasterKey
+		}
+
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, ErrInvalidMasterKey
+		}
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, ErrInvalidMasterKey
+		}
+
+		keyring.keys[version] = key
+		if version > keyring.active {
+			keyring.active = version
+		}
+	}
+
+	if len(keyring.keys) == 0 {
+		return nil, ErrNoMasterKey
+	}
+
+	if active != "" {
+		version, err := strconv.Atoi(active)
+		if err != nil {
+			return nil, ErrUnknownMasterKey
+		}
+		if _, ok := keyring.keys[version]; !ok {
+			return nil, ErrUnknownMasterKey
+		}
+		keyring.active = version
+	}
+
+	return keyring, nil
+}
+
+func (k *Keyring) ActiveVersion() int {
+	return k.active
+}
+
+// Wrap seals dataKey with the active master key and returns the version used.
+func (k *Keyring) Wrap(dataKey []byte) (string, int, error) {
+	wrapped, err := Seal(dataKey, k.keys[k.active])
+	if err != nil {
+		return "", 0, err
+	}
+
+	return wrapped, k.active, nil
+}
+
+func (k *Keyring) Unwrap(wrapped string, version int) ([]byte, error) {
+	key, ok := k.keys[version]
+	if !ok {
+		return nil, ErrUnknownMasterKey
+	}
+
+	return Open(wrapped, key)
+}
+
+// GenerateDataKey returns a random AES-256 key.
+func GenerateDataKey() ([]byte, error) {
+	key := make([]byte, DataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Seal encrypts plaintext with AES-GCM and returns base64 of nonce and
+// ciphertext, so tampering is detected when opening.
+func Seal(plaintext []byte, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func Open(sealed string, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(message) < gcm.NonceSize() {
+		return nil, ErrInvalidSealedString
+	}
+
+	nonce, ciphertext := message[:gcm.NonceSize()], message[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,130 @@
+package encrypt_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	. "kedai/backend/be-kedai/internal/utils/encrypt"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMasterKey(t *testing.T) string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	assert.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestNewKeyring(t *testing.T) {
+	first, second := newMasterKey(t), newMasterKey(t)
+
+	tests := []struct {
+		description string
+		entries     []string
+		active      string
+		version     int
+		err         error
+	}{
+		{
+			description: "should return error when no key configured",
+			entries:     []string{},
+			err:         ErrNoMasterKey,
+		},
+		{
+			description: "should return error when entry has no version",
+			entries:     []string{first},
+			err:         ErrInvalidMasterKey,
+		},
+		{
+			description: "should return error when key has invalid length",
+			entries:     []string{"1:" + base64.StdEncoding.EncodeToString([]byte("short"))},
+			err:         ErrInvalidMasterKey,
+		},
+		{
+			description: "should return error when active version is not configured",
+			entries:     []string{"1:" + first},
+			active:      "2",
+			err:         ErrUnknownMasterKey,
+		},
+		{
+			description: "should default active version to the highest",
+			entries:     []string{"2:" + second, "1:" + first},
+			version:     2,
+		},
+		{
+			description: "should use the configured active version",
+			entries:     []string{"1:" + first, "2:" + second},
+			active:      "1",
+			version:     1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			keyring, err := NewKeyring(tc.entries, tc.active)
+
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, tc.version, keyring.ActiveVersion())
+			}
+		})
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	first, second := newMasterKey(t), newMasterKey(t)
+	dataKey, err := GenerateDataKey()
+	assert.NoError(t, err)
+
+	oldKeyring, err := NewKeyring([]string{"1:" + first}, "")
+	assert.NoError(t, err)
+	wrapped, version, err := oldKeyring.Wrap(dataKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	rotated, err := NewKeyring([]string{"1:" + first, "2:" + second}, "")
+	assert.NoError(t, err)
+
+	unwrapped, err := rotated.Unwrap(wrapped, version)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	rewrapped, version, err := rotated.Wrap(unwrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	unwrapped, err = rotated.Unwrap(rewrapped, version)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	_, err = oldKeyring.Unwrap(rewrapped, version)
+	assert.Equal(t, ErrUnknownMasterKey, err)
+}
+
+func TestSealAndOpen(t *testing.T) {
+	key, err := GenerateDataKey()
+	assert.NoError(t, err)
+
+	sealed, err := Seal([]byte("Hello, world!"), key)
+	assert.NoError(t, err)
+
+	plaintext, err := Open(sealed, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello, world!", string(plaintext))
+
+	message, _ := base64.StdEncoding.DecodeString(sealed)
+	message[len(message)-1] ^= 0xff
+	_, err = Open(base64.StdEncoding.EncodeToString(message), key)
+	assert.Error(t, err)
+
+	otherKey, err := GenerateDataKey()
+	assert.NoError(t, err)
+	_, err = Open(sealed, otherKey)
+	assert.Error(t, err)
+
+	_, err = Open(base64.StdEncoding.EncodeToString([]byte("x")), key)
+	assert.Equal(t, ErrInvalidSealedString, err)
+}
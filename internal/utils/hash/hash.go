@@ -4,11 +4,25 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"kedai/backend/be-kedai/config"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// insecureTokenKey is the hash key the service used to fall back to. It is
+// public, so it must never sign tokens.
+const insecureTokenKey = "secret_key"
+
+var (
+	ErrNoTokenKey       = errors.New("TOKEN_SIGNING_KEY is not configured")
+	ErrInsecureTokenKey = errors.New("TOKEN_SIGNING_KEY is set to the public default")
+)
+
+// tokenKey signs the tokens carried by emailed links. Unlike HashKey it has
+// no default, so links can't be forged on a deployment that never set it.
+var tokenKey []byte
+
 func HashAndSalt(pwd string) (string, error) {
 	hash, _ := bcrypt.GenerateFromPassword([]byte(pwd), bcrypt.MinCost)
 
@@ -30,3 +44,33 @@ func HashSHA256(word string) string {
 func CompareSignature(apiSignature string, hashedSign string) bool {
 	return hmac.Equal([]byte(apiSignature), []byte(hashedSign))
 }
+
+// UseTokenKey sets the key SignToken signs with.
+func UseTokenKey(key string) error {
+	if key == "" {
+		return ErrNoTokenKey
+	}
+	if key == insecureTokenKey {
+		return ErrInsecureTokenKey
+	}
+
+	tokenKey = []byte(key)
+	return nil
+}
+
+// SignToken returns the signature of a token payload.
+func SignToken(payload string) string {
+	h := hmac.New(sha256.New, tokenKey)
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyToken reports whether signature was made by SignToken for payload. It
+// rejects every token until a key is set.
+func VerifyToken(payload string, signature string) bool {
+	if len(tokenKey) == 0 {
+		return false
+	}
+
+	return CompareSignature(signature, SignToken(payload))
+}
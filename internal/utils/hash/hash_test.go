@@ -0,0 +1,49 @@
+package hash_test
+
+import (
+	"testing"
+
+	. "kedai/backend/be-kedai/internal/utils/hash"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseTokenKey(t *testing.T) {
+	tests := []struct {
+		description string
+		key         string
+		err         error
+	}{
+		{
+			description: "should reject an empty key",
+			key:         "",
+			err:         ErrNoTokenKey,
+		},
+		{
+			description: "should reject the public default key",
+			key:         "secret_key",
+			err:         ErrInsecureTokenKey,
+		},
+		{
+			description: "should accept any other key",
+			key:         "a-long-random-token-signing-key",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := UseTokenKey(tc.key)
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	_ = UseTokenKey("a-long-random-token-signing-key")
+	signature := SignToken("payload")
+
+	assert.True(t, VerifyToken("payload", signature))
+	assert.False(t, VerifyToken("other payload", signature))
+	assert.False(t, VerifyToken("payload", HashSHA256("payload")))
+}
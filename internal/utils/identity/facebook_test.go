@@ -0,0 +1,75 @@
+package identity_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "kedai/backend/be-kedai/internal/utils/identity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacebookProviderVerify(t *testing.T) {
+	graph := http.NewServeMux()
+	graph.HandleFunc("/debug_token", func(w http.ResponseWriter, r *http.Request) {
+		data := map[string]interface{}{"is_valid": false}
+		if r.URL.Query().Get("access_token") == "app|secret" {
+			switch r.URL.Query().Get("input_token") {
+			case "valid":
+				data = map[string]interface{}{"is_valid": true, "app_id": "app", "user_id": "42"}
+			case "other-app":
+				data = map[string]interface{}{"is_valid": true, "app_id": "other", "user_id": "42"}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	})
+	graph.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": "42", "email": "User@Mail.com"})
+	})
+	server := httptest.NewServer(graph)
+	defer server.Close()
+
+	provider := NewFacebookProvider(&FacebookConfig{
+		AppID:     "app",
+		AppSecret: "secret",
+		GraphURL:  server.URL,
+	})
+
+	tests := []struct {
+		description string
+		credential  string
+		expected    *Identity
+		err         error
+	}{
+		{
+			description: "should return identity with unverified email when token is valid",
+			credential:  "valid",
+			expected: &Identity{
+				Provider: "facebook",
+				Subject:  "42",
+				Email:    "user@mail.com",
+			},
+		},
+		{
+			description: "should return error when token was issued to another app",
+			credential:  "other-app",
+			err:         ErrInvalidCredential,
+		},
+		{
+			description: "should return error when token is invalid",
+			credential:  "invalid",
+			err:         ErrInvalidCredential,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			identity, err := provider.Verify(tc.credential)
+
+			assert.Equal(t, tc.expected, identity)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package identity
+
+import (
+	"errors"
+	errs "kedai/backend/be-kedai/internal/common/error"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidProviderConfig = errors.New("invalid identity provider config")
+	ErrInvalidCredential     = errors.New("invalid identity provider credential")
+	ErrUnknownKey            = errors.New("identity provider signing key not found")
+)
+
+// Identity is the account a provider vouches a credential belongs to.
+// Subject is stable for the account, while Email can change or be missing.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// Provider verifies credentials issued to this app by one identity provider,
+// such as an OIDC ID token or an OAuth access token.
+type Provider interface {
+	Name() string
+	Verify(credential string) (*Identity, error)
+}
+
+// Registry holds the providers users can sign in with, by name.
+type Registry map[string]Provider
+
+func NewRegistry(providers ...Provider) Registry {
+	registry := Registry{}
+	for _, provider := range providers {
+		registry[provider.Name()] = provider
+	}
+
+	return registry
+}
+
+func (r Registry) Get(name string) (Provider, error) {
+	provider, ok := r[name]
+	if !ok {
+		return nil, errs.ErrUnknownIdentityProvider
+	}
+
+	return provider, nil
+}
+
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseOIDCProviders reads generic OIDC providers from "name|issuer|clientId"
+// entries.
+func ParseOIDCProviders(entries []string) ([]Provider, error) {
+	var providers []Provider
+	for _, entry := range entries {
+		parts := strings.Split(strings.TrimSpace(entry), "|")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, ErrInvalidProviderConfig
+		}
+
+		providers = append(providers, NewOIDCProvider(&OIDCConfig{
+			Name:     parts[0],
+			Issuer:   parts[1],
+			ClientID: parts[2],
+		}))
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,40 @@
+package identity_test
+
+import (
+	"testing"
+
+	errs "kedai/backend/be-kedai/internal/common/error"
+	. "kedai/backend/be-kedai/internal/utils/identity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(NewAppleProvider("app"), NewGoogleProvider("app"))
+
+	t.Run("should list provider names in order", func(t *testing.T) {
+		assert.Equal(t, []string{"apple", "google"}, registry.Names())
+	})
+
+	t.Run("should return error when provider is not configured", func(t *testing.T) {
+		_, err := registry.Get("facebook")
+
+		assert.ErrorIs(t, err, errs.ErrUnknownIdentityProvider)
+	})
+}
+
+func TestParseOIDCProviders(t *testing.T) {
+	t.Run("should read name, issuer and client id", func(t *testing.T) {
+		providers, err := ParseOIDCProviders([]string{"okta|https://kedai.okta.com|client"})
+
+		assert.NoError(t, err)
+		assert.Len(t, providers, 1)
+		assert.Equal(t, "okta", providers[0].Name())
+	})
+
+	t.Run("should return error when entry is incomplete", func(t *testing.T) {
+		_, err := ParseOIDCProviders([]string{"okta|https://kedai.okta.com"})
+
+		assert.ErrorIs(t, err, ErrInvalidProviderConfig)
+	})
+}
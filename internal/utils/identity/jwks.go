@@ -0,0 +1,77 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// publicKeys returns the RSA and EC signing keys of the set by kid. Keys of
+// other types or for encryption are skipped.
+func (s *jsonWebKeySet) publicKeys() map[string]interface{} {
+	keys := map[string]interface{}{}
+	for _, jwk := range s.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := decodeBigInt(jwk.N)
+			e, errE := decodeBigInt(jwk.E)
+			if errN != nil || errE != nil || !e.IsInt64() {
+				continue
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			curve := curveOf(jwk.Crv)
+			x, errX := decodeBigInt(jwk.X)
+			y, errY := decodeBigInt(jwk.Y)
+			if curve == nil || errX != nil || errY != nil || !curve.IsOnCurve(x, y) {
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		}
+	}
+
+	return keys
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+func curveOf(crv string) elliptic.Curve {
+	switch crv {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+	"kedai/backend/be-kedai/internal/common/constant"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// defaultKeyRefreshInterval limits how often an unknown kid makes a provider
+// fetch its keys again.
+const defaultKeyRefreshInterval = time.Minute
+
+type OIDCConfig struct {
+	Name     string
+	Issuer   string
+	ClientID string
+	// AltIssuers are also accepted in the iss claim, for providers that
+	// spell their issuer more than one way.
+	AltIssuers []string
+	// KeyRefreshInterval defaults to a minute.
+	KeyRefreshInterval time.Duration
+	HTTPClient         *http.Client
+}
+
+// oidcProvider verifies ID tokens against the keys published through the
+// issuer's discovery document.
+type oidcProvider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	jwksURI   string
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func NewOIDCProvider(cfg *OIDCConfig) Provider {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	provider := &oidcProvider{
+		cfg:    *cfg,
+		client: client,
+	}
+	if provider.cfg.KeyRefreshInterval == 0 {
+		provider.cfg.KeyRefreshInterval = defaultKeyRefreshInterval
+	}
+
+	return provider
+}
+
+func NewGoogleProvider(clientId string) Provider {
+	return NewOIDCProvider(&OIDCConfig{
+		Name:       constant.IdentityProviderGoogle,
+		Issuer:     "https://accounts.google.com",
+		AltIssuers: []string{"accounts.google.com"},
+		ClientID:   clientId,
+	})
+}
+
+func NewAppleProvider(clientId string) Provider {
+	return NewOIDCProvider(&OIDCConfig{
+		Name:     constant.IdentityProviderApple,
+		Issuer:   "https://appleid.apple.com",
+		ClientID: clientId,
+	})
+}
+
+func (p *oidcProvider) Name() string {
+	return p.cfg.Name
+}
+
+type oidcClaims struct {
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	jwt.RegisteredClaims
+}
+
+func (p *oidcProvider) Verify(credential string) (*Identity, error) {
+	var claims oidcClaims
+	_, err := jwt.ParseWithClaims(credential, &claims, p.keyfunc, jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" || claims.ExpiresAt == nil {
+		return nil, ErrInvalidCredential
+	}
+	if !claims.VerifyAudience(p.cfg.ClientID, true) {
+		return nil, ErrInvalidCredential
+	}
+	if !p.isIssuer(claims.Issuer) {
+		return nil, ErrInvalidCredential
+	}
+
+	return &Identity{
+		Provider:      p.cfg.Name,
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: bool(claims.EmailVerified),
+	}, nil
+}
+
+func (p *oidcProvider) isIssuer(issuer string) bool {
+	if issuer == p.cfg.Issuer {
+		return true
+	}
+	for _, alt := range p.cfg.AltIssuers {
+		if issuer == alt {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *oidcProvider) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.keys[kid]
+	if !ok && time.Since(p.fetchedAt) > p.cfg.KeyRefreshInterval {
+		if err := p.refreshKeys(); err != nil {
+			return nil, err
+		}
+		key, ok = p.keys[kid]
+	}
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// refreshKeys fetches the provider's signing keys, discovering where they
+// are published on first use.
+func (p *oidcProvider) refreshKeys() error {
+	p.fetchedAt = time.Now()
+
+	if p.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		url := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+		if err := getJSON(p.client, url, &discovery); err != nil {
+			return err
+		}
+		if discovery.Issuer != p.cfg.Issuer || discovery.JWKSURI == "" {
+			return ErrInvalidProviderConfig
+		}
+		p.jwksURI = discovery.JWKSURI
+	}
+
+	var jwks jsonWebKeySet
+	if err := getJSON(p.client, p.jwksURI, &jwks); err != nil {
+		return err
+	}
+
+	p.keys = jwks.publicKeys()
+
+	return nil
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	res, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// flexibleBool reads claims that some providers, Apple among them, send as
+// the string "true" or "false" instead of a boolean.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*b = flexibleBool(v)
+	case string:
+		*b = flexibleBool(v == "true")
+	}
+
+	return nil
+}
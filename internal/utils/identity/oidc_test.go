@@ -0,0 +1,144 @@
+package identity_test
+
+import (
+	"testing"
+	"time"
+
+	. "kedai/backend/be-kedai/internal/utils/identity"
+	"kedai/backend/be-kedai/internal/utils/test"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCProviderVerify(t *testing.T) {
+	fake := test.NewFakeOIDCProvider(t)
+	provider := NewOIDCProvider(&OIDCConfig{
+		Name:     "fake",
+		Issuer:   fake.Issuer,
+		ClientID: fake.ClientID,
+	})
+
+	claims := func(override jwt.MapClaims) jwt.MapClaims {
+		base := jwt.MapClaims{
+			"iss":   fake.Issuer,
+			"aud":   fake.ClientID,
+			"sub":   "123",
+			"email": "User@Mail.com",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+		for key, value := range override {
+			base[key] = value
+		}
+		return base
+	}
+
+	tests := []struct {
+		description string
+		credential  string
+		expected    *Identity
+		hasErr      bool
+	}{
+		{
+			description: "should return identity when id token is valid",
+			credential:  fake.IDToken("123", "User@Mail.com", true),
+			expected: &Identity{
+				Provider:      "fake",
+				Subject:       "123",
+				Email:         "user@mail.com",
+				EmailVerified: true,
+			},
+		},
+		{
+			description: "should read email_verified sent as a string",
+			credential:  fake.Sign(claims(jwt.MapClaims{"email_verified": "true"})),
+			expected: &Identity{
+				Provider:      "fake",
+				Subject:       "123",
+				Email:         "user@mail.com",
+				EmailVerified: true,
+			},
+		},
+		{
+			description: "should return error when token was issued to another client",
+			credential:  fake.Sign(claims(jwt.MapClaims{"aud": "other"})),
+			hasErr:      true,
+		},
+		{
+			description: "should return error when token was issued by another issuer",
+			credential:  fake.Sign(claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+			hasErr:      true,
+		},
+		{
+			description: "should return error when token has expired",
+			credential:  fake.Sign(claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})),
+			hasErr:      true,
+		},
+		{
+			description: "should return error when token does not expire",
+			credential:  fake.Sign(claims(jwt.MapClaims{"exp": nil})),
+			hasErr:      true,
+		},
+		{
+			description: "should return error when token has no subject",
+			credential:  fake.Sign(claims(jwt.MapClaims{"sub": ""})),
+			hasErr:      true,
+		},
+		{
+			description: "should return error when token is not signed with the provider key",
+			credential:  fake.IDToken("123", "user@mail.com", true) + "x",
+			hasErr:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			identity, err := provider.Verify(tc.credential)
+
+			assert.Equal(t, tc.expected, identity)
+			assert.Equal(t, tc.hasErr, err != nil)
+		})
+	}
+}
+
+func TestOIDCProviderKeyRotation(t *testing.T) {
+	t.Run("should fetch keys again when token is signed with an unknown key", func(t *testing.T) {
+		fake := test.NewFakeOIDCProvider(t)
+		provider := NewOIDCProvider(&OIDCConfig{
+			Name:               "fake",
+			Issuer:             fake.Issuer,
+			ClientID:           fake.ClientID,
+			KeyRefreshInterval: time.Nanosecond,
+		})
+
+		_, err := provider.Verify(fake.IDToken("123", "user@mail.com", true))
+		assert.NoError(t, err)
+		_, err = provider.Verify(fake.IDToken("123", "user@mail.com", true))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, fake.KeyRequests())
+
+		fake.RotateKey()
+
+		_, err = provider.Verify(fake.IDToken("123", "user@mail.com", true))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, fake.KeyRequests())
+	})
+
+	t.Run("should not fetch keys again before the refresh interval", func(t *testing.T) {
+		fake := test.NewFakeOIDCProvider(t)
+		provider := NewOIDCProvider(&OIDCConfig{
+			Name:     "fake",
+			Issuer:   fake.Issuer,
+			ClientID: fake.ClientID,
+		})
+
+		_, err := provider.Verify(fake.IDToken("123", "user@mail.com", true))
+		assert.NoError(t, err)
+
+		fake.RotateKey()
+
+		_, err = provider.Verify(fake.IDToken("123", "user@mail.com", true))
+		assert.ErrorIs(t, err, ErrUnknownKey)
+		assert.Equal(t, 1, fake.KeyRequests())
+	})
+}
@@ -2,11 +2,13 @@ package jwttoken
 
 import (
 	"kedai/backend/be-kedai/config"
+	"kedai/backend/be-kedai/internal/common/constant"
 	"kedai/backend/be-kedai/internal/domain/user/model"
 	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 func GenerateAccessToken(user *model.User, level int) (string, error) {
@@ -23,60 +25,92 @@ func GenerateAccessToken(user *model.User, level int) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte(config.SecretKey))
+	return Sign(claims)
+}
+
+// GenerateTwoFactorAccessToken issues an access token recording that the
+// session passed 2FA at verifiedAt.
+func GenerateTwoFactorAccessToken(user *model.User, level int, verifiedAt time.Time) (string, error) {
+	accessTime := ParseTokenAgeFromENV(config.GetEnv("ACCESS_TOKEN_AGE", ""), "access")
+	claims := &model.Claim{
+		UserId:      user.ID,
+		TokenType:   "access",
+		Level:       level,
+		TwoFactorAt: verifiedAt.Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "Kedai",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTime)),
+		},
+	}
 
-	return tokenString, nil
+	return Sign(claims)
 }
 
-func GenerateRefreshToken(user *model.User, level int) (string, error) {
+// GenerateRefreshToken issues a refresh token in the token family familyId.
+// Every refresh token gets a unique ID so a rotated token never equals the
+// one it replaces.
+func GenerateRefreshToken(user *model.User, level int, familyId string) (string, error) {
 	refreshTime := ParseTokenAgeFromENV(config.GetEnv("REFRESH_TOKEN_AGE", ""), "refresh")
 	claims := &model.Claim{
 		UserId:    user.ID,
 		TokenType: "refresh",
 		Level:     level,
+		FamilyID:  familyId,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "Kedai",
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTime)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte(config.SecretKey))
-
-	return tokenString, nil
+	return Sign(claims)
 }
 
-func ValidateToken(token string, secretKey string) (*model.Claim, error) {
-	parsedToken, err := jwt.ParseWithClaims(token, &model.Claim{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
-
-	if err != nil {
-		if ve, ok := err.(*jwt.ValidationError); ok {
-			if ve.Errors&jwt.ValidationErrorMalformed != 0 {
-				return nil, jwt.ErrTokenMalformed
-			}
-			if ve.Errors&(jwt.ValidationErrorExpired|jwt.ValidationErrorNotValidYet) != 0 {
-				return nil, jwt.ErrTokenExpired
-			}
-		}
+// GenerateAdminAccessToken issues an access token scoped to the admin API.
+// UserId carries the admin's id, which is unrelated to user ids.
+func GenerateAdminAccessToken(adminId int) (string, error) {
+	accessTime := ParseTokenAgeFromENV(config.GetEnv("ACCESS_TOKEN_AGE", ""), "access")
+	claims := &model.Claim{
+		UserId:    adminId,
+		TokenType: "access",
+		Scope:     constant.AdminTokenScope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "Kedai",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTime)),
+		},
 	}
 
-	parsedClaim := parsedToken.Claims.(*model.Claim)
-	if parsedClaim.TokenType != "access" {
-		return nil, jwt.ErrTokenInvalidClaims
+	return Sign(claims)
+}
+
+// Sign signs claims with the active key of the installed key set.
+func Sign(claims jwt.Claims) (string, error) {
+	keys := CurrentKeySet()
+	if keys == nil {
+		return "", ErrKeySetNotLoaded
 	}
 
-	return parsedClaim, nil
+	return keys.Sign(claims)
+}
+
+func ValidateToken(token string) (*model.Claim, error) {
+	return validate(token, "access")
 }
 
-func ValidateRefreshToken(token string, secretKey string) (*model.Claim, error) {
-	parsedToken, err := jwt.ParseWithClaims(token, &model.Claim{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
+func ValidateRefreshToken(token string) (*model.Claim, error) {
+	return validate(token, "refresh")
+}
 
+func validate(token string, tokenType string) (*model.Claim, error) {
+	keys := CurrentKeySet()
+	if keys == nil {
+		return nil, ErrKeySetNotLoaded
+	}
+
+	parsedToken, err := jwt.ParseWithClaims(token, &model.Claim{}, keys.Keyfunc)
 	if err != nil {
 		if ve, ok := err.(*jwt.ValidationError); ok {
 			if ve.Errors&jwt.ValidationErrorMalformed != 0 {
@@ -86,10 +120,12 @@ func ValidateRefreshToken(token string, secretKey string) (*model.Claim, error)
 				return nil, jwt.ErrTokenExpired
 			}
 		}
+
+		return nil, jwt.ErrTokenSignatureInvalid
 	}
 
 	parsedClaim := parsedToken.Claims.(*model.Claim)
-	if parsedClaim.TokenType != "refresh" {
+	if parsedClaim.TokenType != tokenType {
 		return nil, jwt.ErrTokenInvalidClaims
 	}
 
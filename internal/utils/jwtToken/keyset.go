@@ -0,0 +1,295 @@
+package jwttoken
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// LegacySecretDefault is the secret the service used to fall back to. It is
+// public, so tokens signed with it must never be accepted.
+const LegacySecretDefault = "secret_key"
+
+var (
+	ErrNoSigningKey       = errors.New("no JWT signing key configured")
+	ErrInvalidSigningKey  = errors.New("JWT key must be kid:base64 of a PEM encoded RSA or Ed25519 key")
+	ErrUnknownSigningKey  = errors.New("JWT signing key id not configured")
+	ErrDuplicateKeyID     = errors.New("JWT key id configured more than once")
+	ErrInsecureSecret     = errors.New("SECRET_KEY is set to the public default")
+	ErrKeySetNotLoaded    = errors.New("JWT key set not loaded")
+	ErrUnknownTokenKey    = errors.New("token signed with an unknown key")
+	ErrUnexpectedTokenAlg = errors.New("token signed with an unexpected algorithm")
+)
+
+// verificationKey is a public key tokens may be verified with, together with
+// the only algorithm accepted for it.
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    crypto.PublicKey
+}
+
+// KeySet signs tokens with one active private key and verifies them against
+// every configured public key, so a key can be rotated out without logging
+// everyone out: publish the new key, switch the active id, then drop the old
+// key once the longest-lived token it signed has expired.
+type KeySet struct {
+	activeKid    string
+	signingKey   crypto.PrivateKey
+	method       jwt.SigningMethod
+	keys         map[string]verificationKey
+	order        []string
+	legacySecret []byte
+}
+
+// NewKeySet parses signing entries of the form "kid:base64(PKCS#8 PEM)" and
+// verification-only entries of the form "kid:base64(PKIX PEM)". The active
+// kid defaults to the first signing entry. legacySecret, when set, keeps
+// accepting HS256 tokens issued before asymmetric signing; it is never used
+// to sign.
+func NewKeySet(signing []string, verification []string, activeKid string, legacySecret string) (*KeySet, error) {
+	if legacySecret == LegacySecretDefault {
+		return nil, ErrInsecureSecret
+	}
+
+	keySet := &KeySet{keys: map[string]verificationKey{}}
+	if legacySecret != "" {
+		keySet.legacySecret = []byte(legacySecret)
+	}
+
+	signingKeys := map[string]crypto.PrivateKey{}
+	for _, entry := range signing {
+		kid, block, err := parseKeyEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		private, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, err
+		}
+
+		public, method := publicKeyOf(private)
+		if err := keySet.add(kid, public, method); err != nil {
+			return nil, err
+		}
+
+		signingKeys[kid] = private
+		if activeKid == "" {
+			activeKid = kid
+		}
+	}
+
+	for _, entry := range verification {
+		kid, block, err := parseKeyEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		public, err := x509.ParsePKIXPublicKey(block)
+		if err != nil {
+			return nil, ErrInvalidSigningKey
+		}
+
+		method := methodOf(public)
+		if method == nil {
+			return nil, ErrInvalidSigningKey
+		}
+
+		if err := keySet.add(kid, public, method); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(signingKeys) == 0 {
+		return nil, ErrNoSigningKey
+	}
+
+	private, ok := signingKeys[activeKid]
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+
+	keySet.activeKid = activeKid
+	keySet.signingKey = private
+	keySet.method = keySet.keys[activeKid].method
+
+	return keySet, nil
+}
+
+func (k *KeySet) ActiveKeyID() string {
+	return k.activeKid
+}
+
+// Sign signs claims with the active key and records its id in the kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.activeKid
+
+	return token.SignedString(k.signingKey)
+}
+
+// Keyfunc resolves the verification key named by the token's kid header and
+// rejects any algorithm other than the one bound to that key.
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if k.legacySecret != nil && token.Method == jwt.SigningMethodHS256 {
+			return k.legacySecret, nil
+		}
+
+		return nil, ErrUnknownTokenKey
+	}
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrUnknownTokenKey
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrUnexpectedTokenAlg
+	}
+
+	return key.key, nil
+}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists every verification key, so other services can validate tokens
+// without sharing a secret.
+func (k *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	for _, kid := range k.order {
+		key := k.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.method.Alg()}
+
+		switch public := key.key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func (k *KeySet) add(kid string, public crypto.PublicKey, method jwt.SigningMethod) error {
+	if _, ok := k.keys[kid]; ok {
+		return ErrDuplicateKeyID
+	}
+
+	k.keys[kid] = verificationKey{method: method, key: public}
+	k.order = append(k.order, kid)
+
+	return nil
+}
+
+func parseKeyEntry(entry string) (string, []byte, error) {
+	parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", nil, ErrInvalidSigningKey
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, ErrInvalidSigningKey
+	}
+
+	block, _ := pem.Decode(decoded)
+	if block == nil {
+		return "", nil, ErrInvalidSigningKey
+	}
+
+	return parts[0], block.Bytes, nil
+}
+
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrInvalidSigningKey
+	}
+
+	switch key.(type) {
+	case *rsa.PrivateKey, ed25519.PrivateKey:
+		return key, nil
+	}
+
+	return nil, ErrInvalidSigningKey
+}
+
+func publicKeyOf(private crypto.PrivateKey) (crypto.PublicKey, jwt.SigningMethod) {
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		return &key.PublicKey, jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		return key.Public(), jwt.SigningMethodEdDSA
+	}
+
+	return nil, nil
+}
+
+func methodOf(public crypto.PublicKey) jwt.SigningMethod {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA
+	}
+
+	return nil
+}
+
+var (
+	keySetMu sync.RWMutex
+	keySet   *KeySet
+)
+
+// UseKeySet installs the key set used by the token helpers in this package.
+func UseKeySet(k *KeySet) {
+	keySetMu.Lock()
+	defer keySetMu.Unlock()
+
+	keySet = k
+}
+
+// CurrentKeySet returns the installed key set, or nil before startup loads
+// one.
+func CurrentKeySet() *KeySet {
+	keySetMu.RLock()
+	defer keySetMu.RUnlock()
+
+	return keySet
+}
@@ -0,0 +1,258 @@
+package jwttoken_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"kedai/backend/be-kedai/internal/domain/user/model"
+	"testing"
+	"time"
+
+	. "kedai/backend/be-kedai/internal/utils/jwtToken"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	return key
+}
+
+func privateKeyEntry(t *testing.T, kid string, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	return kid + ":" + base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func publicKeyEntry(t *testing.T, kid string, key interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	assert.NoError(t, err)
+
+	return kid + ":" + base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func newClaim(tokenType string, expiresAt time.Time) *model.Claim {
+	return &model.Claim{
+		UserId:    1,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+}
+
+func TestNewKeySet(t *testing.T) {
+	first, second := newEd25519Key(t), newEd25519Key(t)
+
+	tests := []struct {
+		description  string
+		signing      []string
+		verification []string
+		active       string
+		legacy       string
+		activeKid    string
+		err          error
+	}{
+		{
+			description: "should return error when no signing key configured",
+			verification: []string{
+				publicKeyEntry(t, "old", first.Public()),
+			},
+			err: ErrNoSigningKey,
+		},
+		{
+			description: "should return error when entry has no key id",
+			signing:     []string{base64.StdEncoding.EncodeToString([]byte("key"))},
+			err:         ErrInvalidSigningKey,
+		},
+		{
+			description: "should return error when entry is not a PEM key",
+			signing:     []string{"a:" + base64.StdEncoding.EncodeToString([]byte("key"))},
+			err:         ErrInvalidSigningKey,
+		},
+		{
+			description: "should return error when key id is configured twice",
+			signing:     []string{privateKeyEntry(t, "a", first)},
+			verification: []string{
+				publicKeyEntry(t, "a", second.Public()),
+			},
+			err: ErrDuplicateKeyID,
+		},
+		{
+			description: "should return error when active key id is not a signing key",
+			signing:     []string{privateKeyEntry(t, "a", first)},
+			verification: []string{
+				publicKeyEntry(t, "b", second.Public()),
+			},
+			active: "b",
+			err:    ErrUnknownSigningKey,
+		},
+		{
+			description: "should refuse the public default secret",
+			signing:     []string{privateKeyEntry(t, "a", first)},
+			legacy:      LegacySecretDefault,
+			err:         ErrInsecureSecret,
+		},
+		{
+			description: "should default active key id to the first signing key",
+			signing:     []string{privateKeyEntry(t, "b", second), privateKeyEntry(t, "a", first)},
+			activeKid:   "b",
+		},
+		{
+			description: "should use the configured active key id",
+			signing:     []string{privateKeyEntry(t, "b", second), privateKeyEntry(t, "a", first)},
+			active:      "a",
+			activeKid:   "a",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			keySet, err := NewKeySet(tc.signing, tc.verification, tc.active, tc.legacy)
+
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, tc.activeKid, keySet.ActiveKeyID())
+			}
+		})
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edKey, retiredKey, otherKey := newEd25519Key(t), newEd25519Key(t), newEd25519Key(t)
+
+	retired, err := NewKeySet([]string{privateKeyEntry(t, "retired", retiredKey)}, nil, "", "")
+	assert.NoError(t, err)
+	retiredToken, _ := retired.Sign(newClaim("access", time.Now().Add(time.Hour)))
+
+	other, err := NewKeySet([]string{privateKeyEntry(t, "ed", otherKey)}, nil, "", "")
+	assert.NoError(t, err)
+	forgedToken, _ := other.Sign(newClaim("access", time.Now().Add(time.Hour)))
+
+	keySet, err := NewKeySet(
+		[]string{privateKeyEntry(t, "rsa", rsaKey), privateKeyEntry(t, "ed", edKey)},
+		[]string{publicKeyEntry(t, "retired", retiredKey.Public())},
+		"ed",
+		"legacy_secret",
+	)
+	assert.NoError(t, err)
+	UseKeySet(keySet)
+	defer UseKeySet(nil)
+
+	activeToken, _ := Sign(newClaim("access", time.Now().Add(time.Hour)))
+	expiredToken, _ := Sign(newClaim("access", time.Now().Add(-time.Minute)))
+	refreshToken, _ := Sign(newClaim("refresh", time.Now().Add(time.Hour)))
+
+	legacyToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaim("access", time.Now().Add(time.Hour))).
+		SignedString([]byte("legacy_secret"))
+
+	// An HS256 token whose secret is the RSA public key must not pass as RS256.
+	rsaPublic, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaim("access", time.Now().Add(time.Hour)))
+	confused.Header["kid"] = "rsa"
+	confusedToken, _ := confused.SignedString(rsaPublic)
+
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, newClaim("access", time.Now().Add(time.Hour)))
+	unsigned.Header["kid"] = "ed"
+	unsignedToken, _ := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	tests := []struct {
+		description string
+		token       string
+		err         error
+	}{
+		{
+			description: "should accept token signed with the active key",
+			token:       activeToken,
+		},
+		{
+			description: "should accept token signed with a retired key",
+			token:       retiredToken,
+		},
+		{
+			description: "should accept legacy HS256 token while the legacy secret is set",
+			token:       legacyToken,
+		},
+		{
+			description: "should return error when token is malformed",
+			token:       "token",
+			err:         jwt.ErrTokenMalformed,
+		},
+		{
+			description: "should return error when token is expired",
+			token:       expiredToken,
+			err:         jwt.ErrTokenExpired,
+		},
+		{
+			description: "should return error when token is a refresh token",
+			token:       refreshToken,
+			err:         jwt.ErrTokenInvalidClaims,
+		},
+		{
+			description: "should return error when signature does not match the key id",
+			token:       forgedToken,
+			err:         jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			description: "should return error when algorithm does not match the key",
+			token:       confusedToken,
+			err:         jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			description: "should return error when token is unsigned",
+			token:       unsignedToken,
+			err:         jwt.ErrTokenSignatureInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			claim, err := ValidateToken(tc.token)
+
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, 1, claim.UserId)
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edKey := newEd25519Key(t)
+
+	keySet, err := NewKeySet([]string{privateKeyEntry(t, "rsa", rsaKey)}, []string{publicKeyEntry(t, "ed", edKey.Public())}, "", "")
+	assert.NoError(t, err)
+
+	set := keySet.JWKS()
+
+	assert.Equal(t, []JWK{
+		{
+			Kty: "RSA",
+			Kid: "rsa",
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+			E:   "AQAB",
+		},
+		{
+			Kty: "OKP",
+			Kid: "ed",
+			Use: "sig",
+			Alg: "EdDSA",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey)),
+		},
+	}, set.Keys)
+}
@@ -2,7 +2,10 @@ package mail
 
 import (
 	"context"
+	"fmt"
 	"kedai/backend/be-kedai/config"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/mailgun/mailgun-go/v4"
@@ -13,6 +16,9 @@ type MailUtils interface {
 	SendUpdatePinEmail(receiverEmail string, verificationCode string) error
 	SendResetPasswordEmail(receiverEmail string, token string) error
 	SendResetPinEmail(receiverEmail string, token string) error
+	SendLowStockAlertEmail(receiverEmail string, productName string, sku string, stock int) error
+	SendProductModerationEmail(receiverEmail string, productName string, status string, reason *string) error
+	SendDiscussionReplyEmail(receiverEmail string, productName string, shopName string, message string) error
 }
 
 type mailUtilsImpl struct {
@@ -71,7 +77,7 @@ func (u *mailUtilsImpl) SendResetPasswordEmail(receiverEmail string, token strin
 	body := "Please click this link to reset your password: " +
 		config.GetEnv("FRONTEND_URL", "http://localhost:3000") +
 		config.GetEnv("RESET_PASSWORD_URL", "/reset-password?token=") +
-		token
+		token + "&email=" + url.QueryEscape(receiverEmail)
 
 	msg := u.mailer.NewMessage(sender, subject, body, receiverEmail)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
@@ -106,3 +112,60 @@ func (u *mailUtilsImpl) SendResetPinEmail(receiverEmail string, token string) er
 
 	return nil
 }
+
+func (u *mailUtilsImpl) SendLowStockAlertEmail(receiverEmail string, productName string, sku string, stock int) error {
+	sender := config.GetEnv("MAILGUN_SENDER", "Support@kedai.com")
+	subject := "Low Stock Alert"
+	body := fmt.Sprintf("Your product %s (SKU %s) is running low on stock. Remaining stock: %d", productName, sku, stock)
+
+	msg := u.mailer.NewMessage(sender, subject, body, receiverEmail)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	_, _, err := u.mailer.Send(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *mailUtilsImpl) SendProductModerationEmail(receiverEmail string, productName string, status string, reason *string) error {
+	sender := config.GetEnv("MAILGUN_SENDER", "Support@kedai.com")
+	subject := "Product Moderation Result"
+	body := fmt.Sprintf("Your product %s has been reviewed. Status: %s", productName, strings.ReplaceAll(status, "_", " "))
+	if reason != nil {
+		body += fmt.Sprintf("\nReason: %s", *reason)
+	}
+
+	msg := u.mailer.NewMessage(sender, subject, body, receiverEmail)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	_, _, err := u.mailer.Send(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *mailUtilsImpl) SendDiscussionReplyEmail(receiverEmail string, productName string, shopName string, message string) error {
+	sender := config.GetEnv("MAILGUN_SENDER", "Support@kedai.com")
+	subject := "The seller answered your question"
+	body := fmt.Sprintf("%s replied to your question about %s:\n\n%s", shopName, productName, message)
+
+	msg := u.mailer.NewMessage(sender, subject, body, receiverEmail)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	_, _, err := u.mailer.Send(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"kedai/backend/be-kedai/config"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	netMail "net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/mailgun/mailgun-go/v4"
+)
+
+const (
+	DriverMailgun = "mailgun"
+	DriverSMTP    = "smtp"
+	DriverFile    = "file"
+)
+
+var ErrUnknownMailDriver = errors.New("mail driver must be mailgun, smtp or file")
+
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// UnsubscribeURL, when set, is sent as a List-Unsubscribe header so mail
+	// clients can offer one-click unsubscribe.
+	UnsubscribeURL string
+}
+
+// Bytes encodes m as a multipart/alternative MIME message so clients pick
+// the HTML part and fall back to the text one.
+func (m *Message) Bytes() ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", m.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	if m.UnsubscribeURL != "" {
+		fmt.Fprintf(&msg, "List-Unsubscribe: <%s>\r\n", m.UnsubscribeURL)
+		fmt.Fprintf(&msg, "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// Sink delivers a rendered message.
+type Sink interface {
+	Send(msg *Message) error
+}
+
+func NewSink(cfg *config.MailConfig, mailer *mailgun.MailgunImpl) (Sink, error) {
+	switch cfg.Driver {
+	case DriverMailgun:
+		return &mailgunSink{mailer: mailer}, nil
+	case DriverSMTP:
+		return &smtpSink{addr: cfg.SMTPAddr, username: cfg.SMTPUsername, password: cfg.SMTPPassword}, nil
+	case DriverFile:
+		return &fileSink{dir: cfg.FileDir}, nil
+	}
+
+	return nil, ErrUnknownMailDriver
+}
+
+type mailgunSink struct {
+	mailer *mailgun.MailgunImpl
+}
+
+func (s *mailgunSink) Send(msg *Message) error {
+	message := s.mailer.NewMessage(msg.From, msg.Subject, msg.Text, msg.To)
+	if msg.HTML != "" {
+		message.SetHtml(msg.HTML)
+	}
+	if msg.UnsubscribeURL != "" {
+		message.AddHeader("List-Unsubscribe", "<"+msg.UnsubscribeURL+">")
+		message.AddHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	_, _, err := s.mailer.Send(ctx, message)
+
+	return err
+}
+
+type smtpSink struct {
+	addr     string
+	username string
+	password string
+}
+
+func (s *smtpSink) Send(msg *Message) error {
+	from, err := netMail.ParseAddress(msg.From)
+	if err != nil {
+		return err
+	}
+
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		host, _, err := net.SplitHostPort(s.addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.username, s.password, host)
+	}
+
+	return smtp.SendMail(s.addr, auth, from.Address, []string{msg.To}, body)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+// fileSink writes each message as an .eml file, which most mail clients can
+// open, so templates can be checked without sending anything.
+type fileSink struct {
+	dir string
+}
+
+func (s *fileSink) Send(msg *Message) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+
+	return os.WriteFile(filepath.Join(s.dir, name), body, 0o644)
+}
@@ -0,0 +1,43 @@
+package mail_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"kedai/backend/be-kedai/config"
+	. "kedai/backend/be-kedai/internal/utils/mail"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSink(t *testing.T) {
+	t.Run("should return error when driver is unknown", func(t *testing.T) {
+		sink, err := NewSink(&config.MailConfig{Driver: "pigeon"}, nil)
+
+		assert.Nil(t, sink)
+		assert.Equal(t, ErrUnknownMailDriver, err)
+	})
+
+	t.Run("should write message as eml file when driver is file", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "mail")
+		sink, err := NewSink(&config.MailConfig{Driver: DriverFile, FileDir: dir}, nil)
+		assert.NoError(t, err)
+
+		err = sink.Send(&Message{
+			From:    "Support@kedai.com",
+			To:      "budi@mail.com",
+			Subject: "Pesanan INV/1 sedang dikirim",
+			Text:    "Halo budi",
+			HTML:    "<p>Halo budi</p>",
+		})
+		assert.NoError(t, err)
+
+		files, _ := filepath.Glob(filepath.Join(dir, "*budi@mail.com.eml"))
+		assert.Len(t, files, 1)
+		content, _ := os.ReadFile(files[0])
+		assert.Contains(t, string(content), "To: budi@mail.com")
+		assert.Contains(t, string(content), "Content-Type: text/plain; charset=UTF-8")
+		assert.Contains(t, string(content), "<p>Halo budi</p>")
+	})
+}
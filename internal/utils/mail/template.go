@@ -0,0 +1,232 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmlTemplate "html/template"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	textTemplate "text/template"
+)
+
+const (
+	TemplateOrderConfirmation  = "order_confirmation"
+	TemplateSellerNewOrder     = "seller_new_order"
+	TemplateOrderShipped       = "order_shipped"
+	TemplateOrderDelivered     = "order_delivered"
+	TemplateRefundApproved     = "refund_approved"
+	TemplateRefundRejected     = "refund_rejected"
+	TemplateAdminInvitation    = "admin_invitation"
+	TemplateShopInvitation     = "shop_invitation"
+	TemplateNewDeviceLogin     = "new_device_login"
+	TemplateAccountLocked      = "account_locked"
+	TemplateVerifyEmail        = "verify_email"
+	TemplateConfirmEmailChange = "confirm_email_change"
+	TemplateEmailChanged       = "email_changed"
+	TemplateLinkIdentity       = "link_identity"
+	TemplateDataExportReady    = "data_export_ready"
+	TemplateAccountDeleted     = "account_deleted"
+
+	// FallbackLocale is used when a template has no translation for the
+	// requested locale.
+	FallbackLocale = "en"
+)
+
+var ErrUnknownEmailTemplate = errors.New("unknown email template")
+
+// OrderEmailData fills order_shipped, order_delivered, refund_approved,
+// refund_rejected and seller_new_order.
+type OrderEmailData struct {
+	Username       string
+	ShopName       string
+	Code           string
+	TrackingNumber string
+	Total          float64
+}
+
+type OrderConfirmationEmailData struct {
+	Username    string
+	InvoiceCode string
+	Total       float64
+	Orders      []*OrderEmailData
+}
+
+type AdminInvitationEmailData struct {
+	Name          string
+	InviterName   string
+	RoleName      string
+	InvitationURL string
+	ExpiresAt     string
+}
+
+type ShopInvitationEmailData struct {
+	ShopName      string
+	Role          string
+	InvitationURL string
+	ExpiresAt     string
+}
+
+type NewDeviceLoginEmailData struct {
+	Username    string
+	UserAgent   string
+	IPAddress   string
+	SignedInAt  string
+	SessionsURL string
+}
+
+// AccountLockedEmailData fills account_locked, sent to users and admins
+// whose sign in was locked after repeated failures.
+type AccountLockedEmailData struct {
+	Name        string
+	IPAddress   string
+	LockedUntil string
+}
+
+// EmailLinkEmailData fills verify_email, confirm_email_change and
+// email_changed. NewEmail is only set on email_changed, which is sent to the
+// previous address.
+type EmailLinkEmailData struct {
+	Username  string
+	NewEmail  string
+	URL       string
+	ExpiresAt string
+}
+
+type LinkIdentityEmailData struct {
+	Username  string
+	Provider  string
+	URL       string
+	ExpiresAt string
+}
+
+type DataExportReadyEmailData struct {
+	Username  string
+	URL       string
+	ExpiresAt string
+}
+
+type AccountDeletedEmailData struct {
+	Username  string
+	DeletedAt string
+}
+
+type Email struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+var templateFuncs = map[string]interface{}{
+	"rupiah": formatRupiah,
+}
+
+type emailTemplate struct {
+	text *textTemplate.Template
+	html *htmlTemplate.Template
+}
+
+// emailTemplates is keyed by "locale/name". Every template file defines a
+// subject, text and html block; the html block is parsed with html/template
+// so data is escaped.
+var emailTemplates = loadTemplates("templates/*/*.tmpl")
+
+// footerTemplates only define text and html blocks and are appended to
+// rendered emails.
+var footerTemplates = loadTemplates("templates/*/footer/*.tmpl")
+
+func loadTemplates(pattern string) map[string]*emailTemplate {
+	paths, err := fs.Glob(templateFS, pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	templates := map[string]*emailTemplate{}
+	for _, p := range paths {
+		locale := strings.Split(p, "/")[1]
+		name := strings.TrimSuffix(path.Base(p), ".tmpl")
+
+		templates[locale+"/"+name] = &emailTemplate{
+			text: textTemplate.Must(textTemplate.New(path.Base(p)).Funcs(templateFuncs).ParseFS(templateFS, p)),
+			html: htmlTemplate.Must(htmlTemplate.New(path.Base(p)).Funcs(templateFuncs).ParseFS(templateFS, p)),
+		}
+	}
+
+	return templates
+}
+
+func RenderTemplate(name string, locale string, data interface{}) (*Email, error) {
+	tmpl, ok := emailTemplates[locale+"/"+name]
+	if !ok {
+		tmpl, ok = emailTemplates[FallbackLocale+"/"+name]
+	}
+	if !ok {
+		return nil, ErrUnknownEmailTemplate
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, err
+	}
+
+	return &Email{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    strings.TrimSpace(html.String()),
+	}, nil
+}
+
+// AppendUnsubscribeFooter adds a localised one-click unsubscribe link to the
+// end of email.
+func AppendUnsubscribeFooter(email *Email, locale string, url string) error {
+	tmpl, ok := footerTemplates[locale+"/unsubscribe"]
+	if !ok {
+		tmpl = footerTemplates[FallbackLocale+"/unsubscribe"]
+	}
+
+	var text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&text, "text", url); err != nil {
+		return err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", url); err != nil {
+		return err
+	}
+
+	email.Text += "\n\n" + strings.TrimSpace(text.String())
+	email.HTML += "\n" + strings.TrimSpace(html.String())
+
+	return nil
+}
+
+// formatRupiah formats amount the way prices are shown in the app, e.g.
+// 150000 as "Rp150.000".
+func formatRupiah(amount float64) string {
+	digits := strconv.FormatInt(int64(amount), 10)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if negative {
+		return "-Rp" + grouped.String()
+	}
+
+	return "Rp" + grouped.String()
+}
@@ -0,0 +1,80 @@
+package mail_test
+
+import (
+	"testing"
+
+	. "kedai/backend/be-kedai/internal/utils/mail"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	shipped := &OrderEmailData{
+		Username:       "budi",
+		ShopName:       "Toko <Budi>",
+		Code:           "INV/1",
+		TrackingNumber: "123",
+	}
+
+	tests := []struct {
+		description string
+		name        string
+		locale      string
+		data        interface{}
+		expected    *Email
+		err         error
+	}{
+		{
+			description: "should return error when template does not exist",
+			name:        "unknown",
+			locale:      "id",
+			err:         ErrUnknownEmailTemplate,
+		},
+		{
+			description: "should render template in requested locale and escape html",
+			name:        TemplateOrderShipped,
+			locale:      "id",
+			data:        shipped,
+			expected: &Email{
+				Subject: "Pesanan INV/1 sedang dikirim",
+				Text:    "Halo budi,\n\nToko <Budi> sudah mengirim pesananmu INV/1. Nomor resi: 123.",
+				HTML:    "<p>Halo budi,</p>\n<p>Toko &lt;Budi&gt; sudah mengirim pesananmu <strong>INV/1</strong>.</p>\n<p>Nomor resi: 123</p>",
+			},
+		},
+		{
+			description: "should fall back to english when locale has no translation",
+			name:        TemplateOrderShipped,
+			locale:      "fr",
+			data:        shipped,
+			expected: &Email{
+				Subject: "Order INV/1 is on its way",
+				Text:    "Hi budi,\n\nToko <Budi> has shipped your order INV/1. Tracking number: 123.",
+				HTML:    "<p>Hi budi,</p>\n<p>Toko &lt;Budi&gt; has shipped your order <strong>INV/1</strong>.</p>\n<p>Tracking number: 123</p>",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			email, err := RenderTemplate(tc.name, tc.locale, tc.data)
+
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, email)
+		})
+	}
+}
+
+func TestRenderTemplateFormatsRupiah(t *testing.T) {
+	email, err := RenderTemplate(TemplateOrderConfirmation, "en", &OrderConfirmationEmailData{
+		Username:    "budi",
+		InvoiceCode: "INV/1",
+		Total:       1250000,
+		Orders: []*OrderEmailData{
+			{ShopName: "Toko A", Code: "INV/2", Total: 1250000},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, email.Text, "Rp1.250.000")
+	assert.Contains(t, email.HTML, "INV/2")
+}
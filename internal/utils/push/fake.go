@@ -0,0 +1,37 @@
+package push
+
+import (
+	"log"
+	"sync"
+)
+
+type FakeDelivery struct {
+	Subscription *Subscription
+	Message      *Message
+}
+
+// FakeSender records pushes instead of sending them. Tokens listed in Gone
+// are answered with ErrSubscriptionGone to exercise pruning.
+type FakeSender struct {
+	mu   sync.Mutex
+	Sent []*FakeDelivery
+	Gone map[string]bool
+}
+
+func NewFakeSender() *FakeSender {
+	return &FakeSender{Gone: map[string]bool{}}
+}
+
+func (s *FakeSender) Send(sub *Subscription, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Gone[sub.Token] {
+		return ErrSubscriptionGone
+	}
+
+	s.Sent = append(s.Sent, &FakeDelivery{Subscription: sub, Message: msg})
+	log.Println("push to", sub.Platform, "device:", msg.Title, "-", msg.Body)
+
+	return nil
+}
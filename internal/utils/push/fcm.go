@@ -0,0 +1,172 @@
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+var ErrInvalidFCMCredentials = errors.New("invalid FCM service account credentials")
+
+type fcmCredentials struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// fcmSender delivers to Android and iOS devices through the FCM HTTP v1 API,
+// exchanging a service account assertion for a short-lived access token.
+type fcmSender struct {
+	credentials *fcmCredentials
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func newFCMSender(credentialsFile string) (*fcmSender, error) {
+	content, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials fcmCredentials
+	if err := json.Unmarshal(content, &credentials); err != nil {
+		return nil, ErrInvalidFCMCredentials
+	}
+	if credentials.ProjectID == "" || credentials.ClientEmail == "" || credentials.PrivateKey == "" || credentials.TokenURI == "" {
+		return nil, ErrInvalidFCMCredentials
+	}
+
+	return &fcmSender{credentials: &credentials}, nil
+}
+
+type fcmError struct {
+	Error struct {
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (s *fcmSender) Send(sub *Subscription, msg *Message) error {
+	token, err := s.token()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": sub.Token,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(fcmSendURL, s.credentials.ProjectID), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 300 {
+		return nil
+	}
+
+	var body fcmError
+	_ = json.NewDecoder(res.Body).Decode(&body)
+	if res.StatusCode == http.StatusNotFound || body.Error.Status == "NOT_FOUND" {
+		return ErrSubscriptionGone
+	}
+	for _, detail := range body.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ErrSubscriptionGone
+		}
+	}
+
+	return fmt.Errorf("fcm push failed with status %d %s", res.StatusCode, body.Error.Status)
+}
+
+// token returns a cached access token, refreshing it a minute before it
+// expires.
+func (s *fcmSender) token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Add(time.Minute).Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(s.credentials.PrivateKey))
+	if err != nil {
+		return "", ErrInvalidFCMCredentials
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.credentials.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	res, err := httpClient.Post(s.credentials.TokenURI, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return "", fmt.Errorf("fcm token exchange failed with status %d", res.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	s.accessToken = body.AccessToken
+	s.expiresAt = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+
+	return s.accessToken, nil
+}
@@ -0,0 +1,97 @@
+package push
+
+import (
+	"errors"
+	"kedai/backend/be-kedai/config"
+	"net/http"
+	"time"
+)
+
+const (
+	DriverLive = "live"
+	DriverFake = "fake"
+
+	PlatformWeb     = "web"
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+)
+
+var (
+	ErrUnknownPushDriver = errors.New("push driver must be live or fake")
+	// ErrSubscriptionGone means the push service no longer knows the device,
+	// so its registration should be deleted.
+	ErrSubscriptionGone     = errors.New("push subscription is gone")
+	ErrPlatformNotSupported = errors.New("push platform is not configured")
+)
+
+type Message struct {
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Data  map[string]string `json:"data,omitempty"`
+}
+
+// Subscription is where a device receives pushes. For web it is the push
+// service endpoint with the browser's keys, for mobile the FCM token.
+type Subscription struct {
+	Platform string
+	Token    string
+	P256dh   string
+	Auth     string
+}
+
+type Sender interface {
+	Send(sub *Subscription, msg *Message) error
+}
+
+var httpClient = &http.Client{Timeout: 5 * time.Second}
+
+func NewSender(cfg *config.PushConfig) (Sender, error) {
+	switch cfg.Driver {
+	case DriverFake:
+		return NewFakeSender(), nil
+	case DriverLive:
+		sender := &platformSender{}
+
+		if cfg.VAPIDPrivateKey != "" {
+			web, err := newWebPushSender(cfg)
+			if err != nil {
+				return nil, err
+			}
+			sender.web = web
+		}
+
+		if cfg.FCMCredentialsFile != "" {
+			fcm, err := newFCMSender(cfg.FCMCredentialsFile)
+			if err != nil {
+				return nil, err
+			}
+			sender.fcm = fcm
+		}
+
+		return sender, nil
+	}
+
+	return nil, ErrUnknownPushDriver
+}
+
+// platformSender routes each subscription to the service its platform uses.
+type platformSender struct {
+	web Sender
+	fcm Sender
+}
+
+func (s *platformSender) Send(sub *Subscription, msg *Message) error {
+	var sender Sender
+	switch sub.Platform {
+	case PlatformWeb:
+		sender = s.web
+	case PlatformAndroid, PlatformIOS:
+		sender = s.fcm
+	}
+
+	if sender == nil {
+		return ErrPlatformNotSupported
+	}
+
+	return sender.Send(sub, msg)
+}
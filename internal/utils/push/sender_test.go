@@ -0,0 +1,167 @@
+package push_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kedai/backend/be-kedai/config"
+	. "kedai/backend/be-kedai/internal/utils/push"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/hkdf"
+)
+
+func TestNewSender(t *testing.T) {
+	t.Run("should return error when driver is unknown", func(t *testing.T) {
+		sender, err := NewSender(&config.PushConfig{Driver: "carrier-pigeon"})
+
+		assert.Nil(t, sender)
+		assert.Equal(t, ErrUnknownPushDriver, err)
+	})
+
+	t.Run("should return error when VAPID key is invalid", func(t *testing.T) {
+		sender, err := NewSender(&config.PushConfig{Driver: DriverLive, VAPIDPrivateKey: "abc"})
+
+		assert.Nil(t, sender)
+		assert.Equal(t, ErrInvalidVAPIDKey, err)
+	})
+
+	t.Run("should return error when platform is not configured", func(t *testing.T) {
+		sender, _ := NewSender(&config.PushConfig{Driver: DriverLive})
+
+		err := sender.Send(&Subscription{Platform: PlatformAndroid, Token: "token"}, &Message{Title: "Hi"})
+
+		assert.Equal(t, ErrPlatformNotSupported, err)
+	})
+}
+
+func TestFakeSender(t *testing.T) {
+	sender := NewFakeSender()
+	sender.Gone["dead"] = true
+
+	assert.NoError(t, sender.Send(&Subscription{Platform: PlatformIOS, Token: "alive"}, &Message{Title: "Hi"}))
+	assert.Equal(t, ErrSubscriptionGone, sender.Send(&Subscription{Platform: PlatformIOS, Token: "dead"}, &Message{Title: "Hi"}))
+	assert.Len(t, sender.Sent, 1)
+	assert.Equal(t, "alive", sender.Sent[0].Subscription.Token)
+}
+
+// browser plays the user agent side of RFC 8291 so tests can check what the
+// push service would hand to the browser.
+type browser struct {
+	private []byte
+	public  []byte
+	auth    []byte
+}
+
+func newBrowser(t *testing.T) *browser {
+	private, x, y, err := elliptic.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	auth := make([]byte, 16)
+	_, _ = rand.Read(auth)
+
+	return &browser{private: private, public: elliptic.Marshal(elliptic.P256(), x, y), auth: auth}
+}
+
+func (b *browser) subscription(endpoint string) *Subscription {
+	return &Subscription{
+		Platform: PlatformWeb,
+		Token:    endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(b.public),
+		Auth:     base64.RawURLEncoding.EncodeToString(b.auth),
+	}
+}
+
+func (b *browser) decrypt(t *testing.T, body []byte) []byte {
+	salt, idLen := body[:16], int(body[20])
+	asPublic, record := body[21:21+idLen], body[21+idLen:]
+
+	curve := elliptic.P256()
+	asX, asY := elliptic.Unmarshal(curve, asPublic)
+	sharedX, _ := curve.ScalarMult(asX, asY, b.private)
+	secret := make([]byte, 32)
+	sharedX.FillBytes(secret)
+
+	read := func(secret, salt, info []byte, n int) []byte {
+		out := make([]byte, n)
+		_, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out)
+		assert.NoError(t, err)
+		return out
+	}
+	ikm := read(secret, b.auth, append(append([]byte("WebPush: info\x00"), b.public...), asPublic...), 32)
+	cek := read(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := read(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, record, nil)
+	assert.NoError(t, err)
+
+	return plain[:len(plain)-1]
+}
+
+func newVAPIDConfig(t *testing.T) *config.PushConfig {
+	private, _, _, err := elliptic.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	return &config.PushConfig{
+		Driver:          DriverLive,
+		VAPIDPrivateKey: base64.RawURLEncoding.EncodeToString(private),
+		VAPIDSubject:    "mailto:Support@kedai.com",
+	}
+}
+
+func TestWebPush(t *testing.T) {
+	t.Run("should deliver payload the browser can decrypt", func(t *testing.T) {
+		b := newBrowser(t)
+		msg := &Message{Title: "Order INV/1 updated", Body: "Your order is on delivery.", Data: map[string]string{"code": "INV/1"}}
+		var received *Message
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+			assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "vapid t="))
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b.decrypt(t, body), &received)
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		sender, err := NewSender(newVAPIDConfig(t))
+		assert.NoError(t, err)
+
+		err = sender.Send(b.subscription(server.URL+"/push/abc"), msg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, msg, received)
+	})
+
+	t.Run("should report gone subscriptions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer server.Close()
+
+		sender, _ := NewSender(newVAPIDConfig(t))
+
+		err := sender.Send(newBrowser(t).subscription(server.URL), &Message{Title: "Hi"})
+
+		assert.Equal(t, ErrSubscriptionGone, err)
+	})
+
+	t.Run("should return error when subscription keys are invalid", func(t *testing.T) {
+		sender, _ := NewSender(newVAPIDConfig(t))
+
+		err := sender.Send(&Subscription{Platform: PlatformWeb, Token: "https://push.example.com", P256dh: "abc", Auth: "abc"}, &Message{Title: "Hi"})
+
+		assert.Equal(t, ErrInvalidSubscriptionKey, err)
+	})
+}
@@ -0,0 +1,206 @@
+package push
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kedai/backend/be-kedai/config"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	webPushTTL        = 24 * time.Hour
+	webPushRecordSize = 4096
+	vapidTokenAge     = 12 * time.Hour
+)
+
+var (
+	ErrInvalidVAPIDKey        = errors.New("invalid VAPID key")
+	ErrInvalidSubscriptionKey = errors.New("invalid web push subscription key")
+)
+
+// webPushSender delivers to browser push services, encrypting the payload
+// as RFC 8291 aes128gcm and authenticating with RFC 8292 VAPID.
+type webPushSender struct {
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+}
+
+func newWebPushSender(cfg *config.PushConfig) (*webPushSender, error) {
+	d, err := base64.RawURLEncoding.DecodeString(cfg.VAPIDPrivateKey)
+	if err != nil || len(d) != 32 {
+		return nil, ErrInvalidVAPIDKey
+	}
+
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d)
+
+	publicKey := base64.RawURLEncoding.EncodeToString(elliptic.Marshal(curve, key.X, key.Y))
+	if cfg.VAPIDPublicKey != "" && cfg.VAPIDPublicKey != publicKey {
+		return nil, ErrInvalidVAPIDKey
+	}
+
+	return &webPushSender{
+		privateKey: key,
+		publicKey:  publicKey,
+		subject:    cfg.VAPIDSubject,
+	}, nil
+}
+
+func (s *webPushSender) Send(sub *Subscription, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	body, err := encryptWebPush(sub, payload)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := s.vapidAuthorization(sub.Token)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sub.Token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", fmt.Sprint(int(webPushTTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case res.StatusCode >= 300:
+		return fmt.Errorf("web push failed with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *webPushSender) vapidAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{u.Scheme + "://" + u.Host},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(vapidTokenAge)),
+		Subject:   s.subject,
+	}).SignedString(s.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("vapid t=%s, k=%s", token, s.publicKey), nil
+}
+
+// encryptWebPush encrypts payload for the browser owning sub as a single
+// aes128gcm record.
+func encryptWebPush(sub *Subscription, payload []byte) ([]byte, error) {
+	curve := elliptic.P256()
+
+	uaPublic, err := base64.RawURLEncoding.DecodeString(sub.P256dh)
+	if err != nil {
+		return nil, ErrInvalidSubscriptionKey
+	}
+	uaX, uaY := elliptic.Unmarshal(curve, uaPublic)
+	if uaX == nil {
+		return nil, ErrInvalidSubscriptionKey
+	}
+
+	authSecret, err := base64.RawURLEncoding.DecodeString(sub.Auth)
+	if err != nil || len(authSecret) == 0 {
+		return nil, ErrInvalidSubscriptionKey
+	}
+
+	asPrivate, asX, asY, err := elliptic.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := elliptic.Marshal(curve, asX, asY)
+
+	sharedX, _ := curve.ScalarMult(uaX, uaY, asPrivate)
+	ecdhSecret := make([]byte, 32)
+	sharedX.FillBytes(ecdhSecret)
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaPublic...), asPublic...)
+	ikm, err := readHKDF(ecdhSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := readHKDF(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := readHKDF(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 marks the last (and only) record.
+	record := gcm.Seal(nil, nonce, append(payload, 0x02), nil)
+
+	var body bytes.Buffer
+	body.Write(salt)
+	_ = binary.Write(&body, binary.BigEndian, uint32(webPushRecordSize))
+	body.WriteByte(byte(len(asPublic)))
+	body.Write(asPublic)
+	body.Write(record)
+
+	return body.Bytes(), nil
+}
+
+func readHKDF(secret []byte, salt []byte, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -1,11 +1,19 @@
 package test
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"kedai/backend/be-kedai/internal/server"
+	"kedai/backend/be-kedai/internal/utils/hash"
+	jwttoken "kedai/backend/be-kedai/internal/utils/jwtToken"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"testing"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,3 +29,38 @@ func MakeRequestBody(dto interface{}) *strings.Reader {
 	payload, _ := json.Marshal(dto)
 	return strings.NewReader(string(payload))
 }
+
+// UseJWTKeySet installs a throwaway Ed25519 signing key for the duration of
+// the test, so code under test can issue and validate real tokens.
+func UseJWTKeySet(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := "test:" + base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	keySet, err := jwttoken.NewKeySet([]string{entry}, nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwttoken.UseKeySet(keySet)
+	t.Cleanup(func() { jwttoken.UseKeySet(nil) })
+}
+
+// UseTokenKey installs a random key to sign emailed link tokens with, so code
+// under test can issue and verify real tokens.
+func UseTokenKey(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hash.UseTokenKey(base64.StdEncoding.EncodeToString(key)); err != nil {
+		t.Fatal(err)
+	}
+}
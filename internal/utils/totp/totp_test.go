@@ -0,0 +1,94 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "kedai/backend/be-kedai/internal/utils/totp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the RFC 6238 SHA1 test key "12345678901234567890" in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		description string
+		unix        int64
+		expected    string
+	}{
+		{description: "should match RFC 6238 vector at 59", unix: 59, expected: "287082"},
+		{description: "should match RFC 6238 vector at 1111111109", unix: 1111111109, expected: "081804"},
+		{description: "should match RFC 6238 vector at 1234567890", unix: 1234567890, expected: "005924"},
+		{description: "should match RFC 6238 vector at 2000000000", unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(time.Unix(tc.unix, 0)))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, code)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current, _ := Code(rfcSecret, Step(now))
+	previous, _ := Code(rfcSecret, Step(now)-1)
+	stale, _ := Code(rfcSecret, Step(now)-2)
+
+	tests := []struct {
+		description string
+		code        string
+		lastStep    int64
+		step        int64
+		valid       bool
+	}{
+		{description: "should accept the current code", code: current, step: Step(now), valid: true},
+		{description: "should accept the previous code for clock drift", code: previous, step: Step(now) - 1, valid: true},
+		{description: "should reject a code outside the skew", code: stale},
+		{description: "should reject a code already used", code: current, lastStep: Step(now)},
+		{description: "should reject a code of the wrong length", code: "12345"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			step, valid := Validate(rfcSecret, tc.code, now, tc.lastStep)
+
+			assert.Equal(t, tc.valid, valid)
+			assert.Equal(t, tc.step, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Kedai", "user@kedai.com", rfcSecret)
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Kedai:user@kedai.com?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=Kedai")
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	for _, code := range codes {
+		assert.Regexp(t, "^[a-z0-9]{5}-[a-z0-9]{5}$", code)
+	}
+	assert.Equal(t, codes[0], NormalizeRecoveryCode(" "+strings.ToUpper(codes[0])+" "))
+}
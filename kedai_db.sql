@@ -3,6 +3,7 @@ CREATE TABLE "users" (
   "username" varchar UNIQUE NOT NULL,
   "email" varchar UNIQUE NOT NULL,
   "password" varchar NOT NULL,
+  "email_verified_at" timestamp,
   "created_at" timestamp NOT NULL DEFAULT (now()),
   "updated_at" timestamp NOT NULL DEFAULT (now()),
   "deleted_at" timestamp
@@ -187,6 +188,9 @@ CREATE TABLE "products" (
   "is_new" boolean NOT NULL,
   "is_active" boolean NOT NULL,
   "rating" float NOT NULL,
+  "moderation_status" varchar NOT NULL DEFAULT 'approved',
+  "moderation_reason" varchar,
+  "moderation_flags" varchar,
   "shop_id" bigint NOT NULL,
   "category_id" bigint NOT NULL,
   "created_at" timestamp NOT NULL DEFAULT (now()),
@@ -256,6 +260,8 @@ CREATE TABLE "skus" (
   "sku" varchar UNIQUE NOT NULL,
   "price" float NOT NULL,
   "stock" int NOT NULL,
+  "low_stock_threshold" int,
+  "low_stock_notified_at" timestamp,
   "product_id" bigint NOT NULL,
   "created_at" timestamp NOT NULL DEFAULT (now()),
   "updated_at" timestamp NOT NULL DEFAULT (now()),
@@ -272,6 +278,7 @@ CREATE TABLE "transactions" (
   "address_id" bigint NOT NULL,
   "user_id" bigint NOT NULL,
   "sku_id" bigint NOT NULL,
+  "flash_sale_product_id" bigint,
   "created_at" timestamp NOT NULL DEFAULT (now()),
   "updated_at" timestamp NOT NULL DEFAULT (now()),
   "deleted_at" timestamp
@@ -282,6 +289,11 @@ CREATE TABLE "transaction_reviews" (
   "description" varchar NOT NULL,
   "rating" int NOT NULL,
   "review_date" timestamp NOT NULL,
+  "edited_at" timestamp,
+  "helpful_count" int NOT NULL DEFAULT 0,
+  "is_hidden" boolean NOT NULL DEFAULT false,
+  "seller_reply" varchar,
+  "seller_reply_date" timestamp,
   "transactions_id" bigint NOT NULL,
   "created_at" timestamp NOT NULL DEFAULT (now()),
   "updated_at" timestamp NOT NULL DEFAULT (now()),
@@ -314,6 +326,7 @@ CREATE TABLE "invoice_per_shops" (
   "user_id" bigint NOT NULL,
   "voucher_id" bigint,
   "invoice_id" bigint NOT NULL,
+  "released_by_id" bigint,
   "created_at" timestamp NOT NULL DEFAULT (now()),
   "updated_at" timestamp NOT NULL DEFAULT (now()),
   "deleted_at" timestamp
@@ -339,6 +352,7 @@ CREATE TABLE "invoice_status" (
   "status" varchar NOT NULL,
   "status_date" timestamp NOT NULL,
   "invoice_per_shop_id" bigint NOT NULL,
+  "actor_id" bigint,
   "created_at" timestamp NOT NULL DEFAULT (now()),
   "updated_at" timestamp NOT NULL DEFAULT (now()),
   "deleted_at" timestamp
@@ -400,6 +414,7 @@ CREATE TABLE "categories" (
   "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
   "name" varchar NOT NULL,
   "image_url" varchar NOT NULL,
+  "is_hazardous" boolean NOT NULL DEFAULT false,
   "parent_id" bigint,
   "created_at" timestamp NOT NULL DEFAULT (now()),
   "updated_at" timestamp NOT NULL DEFAULT (now()),
@@ -480,10 +495,437 @@ CREATE TABLE "marketplace_banner" (
   "deleted_at" timestamp
 );
 
+CREATE TABLE "inventory_movements" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "type" varchar NOT NULL,
+  "quantity" int NOT NULL,
+  "stock_after" int NOT NULL,
+  "date" timestamp NOT NULL,
+  "sku_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "product_moderations" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "status" varchar NOT NULL,
+  "reason" varchar,
+  "flags" varchar,
+  "date" timestamp NOT NULL,
+  "product_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "discussions" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "message" varchar NOT NULL,
+  "date" timestamp NOT NULL DEFAULT (now()),
+  "is_seller" boolean NOT NULL DEFAULT false,
+  "helpful_count" int NOT NULL DEFAULT 0,
+  "is_official" boolean NOT NULL DEFAULT false,
+  "is_hidden" boolean NOT NULL DEFAULT false,
+  "user_id" bigint NOT NULL,
+  "product_id" bigint NOT NULL,
+  "parent_id" bigint,
+  "shop_id" bigint,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "discussion_votes" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "discussion_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "discussion_reports" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "reason" varchar NOT NULL,
+  "discussion_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "chats" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "message" varchar NOT NULL,
+  "type" varchar NOT NULL DEFAULT 'text',
+  "shop_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "issuer" varchar NOT NULL,
+  "is_read_by_opponent" boolean NOT NULL DEFAULT false,
+  "delivered_at" timestamp,
+  "read_at" timestamp,
+  "payload" jsonb,
+  "is_auto_reply" boolean NOT NULL DEFAULT false,
+  "sent_by_id" bigint,
+  "key_id" bigint,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "chat_keys" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "shop_id" bigint NOT NULL,
+  "wrapped_key" varchar NOT NULL,
+  "master_key_version" int NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "chat_settings" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "shop_id" bigint NOT NULL,
+  "is_auto_reply_enabled" boolean NOT NULL DEFAULT false,
+  "auto_reply_message" varchar NOT NULL DEFAULT '',
+  "is_away_enabled" boolean NOT NULL DEFAULT false,
+  "away_message" varchar NOT NULL DEFAULT '',
+  "away_from" timestamp,
+  "away_until" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "chat_quick_replies" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "shop_id" bigint NOT NULL,
+  "shortcut" varchar NOT NULL,
+  "message" varchar NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "notifications" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "type" varchar NOT NULL,
+  "title" varchar NOT NULL,
+  "body" varchar NOT NULL,
+  "data" jsonb,
+  "read_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "notification_preferences" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "category" varchar NOT NULL,
+  "channel" varchar NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "push_devices" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "platform" varchar NOT NULL,
+  "token" varchar NOT NULL,
+  "p256dh" varchar NOT NULL DEFAULT '',
+  "auth" varchar NOT NULL DEFAULT '',
+  "user_agent" varchar NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "admin_roles" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT ''
+);
+
+CREATE TABLE "admin_permissions" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "code" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT ''
+);
+
+CREATE TABLE "admin_role_permissions" (
+  "role_id" bigint NOT NULL,
+  "permission_id" bigint NOT NULL,
+  PRIMARY KEY ("role_id", "permission_id")
+);
+
+CREATE TABLE "admins" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "email" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "password" varchar,
+  "status" varchar NOT NULL DEFAULT 'invited',
+  "role_id" bigint NOT NULL,
+  "invited_by_id" bigint,
+  "invite_token" varchar,
+  "invite_expires_at" timestamp,
+  "last_login_at" timestamp,
+  "revoked_at" timestamp,
+  "revoked_by_id" bigint,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "user_two_factors" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "secret" varchar NOT NULL,
+  "key_version" int NOT NULL,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "enabled_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "user_sessions" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "session_key" varchar NOT NULL,
+  "device_id" varchar NOT NULL,
+  "user_agent" varchar NOT NULL DEFAULT '',
+  "ip_address" varchar NOT NULL DEFAULT '',
+  "last_seen_at" timestamp NOT NULL DEFAULT (now()),
+  "revoked_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "user_identities" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "provider" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "email" varchar NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "user_data_exports" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "status" varchar NOT NULL,
+  "archive" bytea,
+  "claimed_until" timestamp,
+  "completed_at" timestamp,
+  "expires_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "used_emails" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint,
+  "email" varchar NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "user_recovery_codes" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "admin_audit_logs" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "admin_id" bigint NOT NULL,
+  "action" varchar NOT NULL,
+  "path" varchar NOT NULL,
+  "status_code" int NOT NULL,
+  "ip_address" varchar NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "email_outbox" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "recipient" varchar NOT NULL,
+  "template" varchar NOT NULL,
+  "locale" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "text_body" text NOT NULL,
+  "html_body" text NOT NULL,
+  "unsubscribe_url" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp NOT NULL DEFAULT (now()),
+  "last_error" varchar,
+  "sent_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "review_votes" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "review_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "review_reports" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "reason" varchar NOT NULL,
+  "review_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "flash_sale_campaigns" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "description" varchar,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "flash_sale_slots" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "start_time" timestamp NOT NULL,
+  "end_time" timestamp NOT NULL,
+  "campaign_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "flash_sale_products" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "type" varchar NOT NULL,
+  "amount" float NOT NULL,
+  "quota" int NOT NULL,
+  "stock" int NOT NULL,
+  "purchase_limit" int NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "slot_id" bigint NOT NULL,
+  "sku_id" bigint NOT NULL,
+  "shop_id" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
+CREATE TABLE "shop_members" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "shop_id" bigint NOT NULL,
+  "user_id" bigint,
+  "email" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'invited',
+  "invited_by_id" bigint NOT NULL,
+  "invite_token" varchar,
+  "invite_expires_at" timestamp,
+  "joined_at" timestamp,
+  "revoked_at" timestamp,
+  "revoked_by_id" bigint,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  "deleted_at" timestamp
+);
+
 CREATE UNIQUE INDEX ON "variants" ("value", "group_id");
 
+CREATE UNIQUE INDEX ON "flash_sale_products" ("slot_id", "sku_id");
+
+CREATE UNIQUE INDEX ON "discussion_votes" ("discussion_id", "user_id");
+
+CREATE UNIQUE INDEX ON "discussion_reports" ("discussion_id", "user_id");
+
+CREATE INDEX ON "chats" ("user_id", "shop_id", "created_at");
+
+CREATE INDEX ON "chats" ("shop_id", "created_at");
+
+CREATE UNIQUE INDEX ON "chat_keys" ("user_id", "shop_id");
+
+CREATE INDEX ON "chat_keys" ("master_key_version");
+
+CREATE UNIQUE INDEX ON "chat_settings" ("shop_id");
+
+CREATE UNIQUE INDEX ON "chat_quick_replies" ("shop_id", "shortcut") WHERE "deleted_at" IS NULL;
+
+CREATE INDEX ON "notifications" ("user_id", "created_at");
+
+CREATE INDEX ON "notifications" ("user_id") WHERE "read_at" IS NULL;
+
+CREATE UNIQUE INDEX ON "notification_preferences" ("user_id", "category", "channel");
+
+CREATE UNIQUE INDEX ON "push_devices" ("token");
+
+CREATE INDEX ON "push_devices" ("user_id");
+
+CREATE UNIQUE INDEX ON "admin_roles" ("name");
+
+CREATE UNIQUE INDEX ON "admin_permissions" ("code");
+
+CREATE UNIQUE INDEX ON "admins" (lower("email"));
+
+CREATE UNIQUE INDEX ON "admins" ("invite_token");
+
+CREATE INDEX ON "admin_audit_logs" ("admin_id", "created_at");
+
+CREATE INDEX ON "admin_audit_logs" ("created_at");
+
+CREATE INDEX ON "email_outbox" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE UNIQUE INDEX ON "review_votes" ("review_id", "user_id");
+
+CREATE UNIQUE INDEX ON "review_reports" ("review_id", "user_id");
+
 CREATE UNIQUE INDEX ON "wishlist_items" ("user_id", "product_id");
 
+CREATE UNIQUE INDEX ON "shop_members" ("shop_id", lower("email"));
+
+CREATE UNIQUE INDEX ON "shop_members" ("invite_token");
+
+CREATE INDEX ON "shop_members" ("user_id", "status");
+
+CREATE UNIQUE INDEX ON "user_two_factors" ("user_id");
+
+CREATE INDEX ON "user_recovery_codes" ("user_id", "code_hash");
+
+CREATE UNIQUE INDEX ON "user_sessions" ("session_key");
+
+CREATE INDEX ON "user_sessions" ("user_id", "device_id");
+
+CREATE UNIQUE INDEX ON "user_identities" ("provider", "subject");
+
+CREATE UNIQUE INDEX ON "user_identities" ("user_id", "provider");
+
+CREATE INDEX ON "user_data_exports" ("user_id");
+
+CREATE INDEX ON "used_emails" ("email");
+
+CREATE INDEX ON "used_emails" ("user_id");
+
 ALTER TABLE "user_profiles" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
 
 ALTER TABLE "user_profiles" ADD FOREIGN KEY ("default_address_id") REFERENCES "user_addresses" ("id");
@@ -550,6 +992,36 @@ ALTER TABLE "product_variants" ADD FOREIGN KEY ("variant") REFERENCES "variants"
 
 ALTER TABLE "skus" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
 
+ALTER TABLE "inventory_movements" ADD FOREIGN KEY ("sku_id") REFERENCES "skus" ("id");
+
+ALTER TABLE "product_moderations" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
+
+ALTER TABLE "discussions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "discussions" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
+
+ALTER TABLE "discussions" ADD FOREIGN KEY ("parent_id") REFERENCES "discussions" ("id");
+
+ALTER TABLE "discussions" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
+
+ALTER TABLE "discussion_votes" ADD FOREIGN KEY ("discussion_id") REFERENCES "discussions" ("id");
+
+ALTER TABLE "discussion_votes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "discussion_reports" ADD FOREIGN KEY ("discussion_id") REFERENCES "discussions" ("id");
+
+ALTER TABLE "discussion_reports" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "flash_sale_slots" ADD FOREIGN KEY ("campaign_id") REFERENCES "flash_sale_campaigns" ("id");
+
+ALTER TABLE "flash_sale_products" ADD FOREIGN KEY ("slot_id") REFERENCES "flash_sale_slots" ("id");
+
+ALTER TABLE "flash_sale_products" ADD FOREIGN KEY ("sku_id") REFERENCES "skus" ("id");
+
+ALTER TABLE "flash_sale_products" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
+
+ALTER TABLE "transactions" ADD FOREIGN KEY ("flash_sale_product_id") REFERENCES "flash_sale_products" ("id");
+
 ALTER TABLE "transactions" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoice_per_shops" ("id");
 
 ALTER TABLE "transactions" ADD FOREIGN KEY ("address_id") REFERENCES "user_addresses" ("id");
@@ -562,6 +1034,46 @@ ALTER TABLE "transaction_reviews" ADD FOREIGN KEY ("transactions_id") REFERENCES
 
 ALTER TABLE "review_medias" ADD FOREIGN KEY ("review_id") REFERENCES "transaction_reviews" ("id");
 
+ALTER TABLE "chats" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
+
+ALTER TABLE "chats" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "chats" ADD FOREIGN KEY ("key_id") REFERENCES "chat_keys" ("id");
+
+ALTER TABLE "chat_keys" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "chat_keys" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
+
+ALTER TABLE "chat_settings" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
+
+ALTER TABLE "chat_quick_replies" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "push_devices" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "admin_role_permissions" ADD FOREIGN KEY ("role_id") REFERENCES "admin_roles" ("id");
+
+ALTER TABLE "admin_role_permissions" ADD FOREIGN KEY ("permission_id") REFERENCES "admin_permissions" ("id");
+
+ALTER TABLE "admins" ADD FOREIGN KEY ("role_id") REFERENCES "admin_roles" ("id");
+
+ALTER TABLE "admins" ADD FOREIGN KEY ("invited_by_id") REFERENCES "admins" ("id");
+
+ALTER TABLE "admins" ADD FOREIGN KEY ("revoked_by_id") REFERENCES "admins" ("id");
+
+ALTER TABLE "admin_audit_logs" ADD FOREIGN KEY ("admin_id") REFERENCES "admins" ("id");
+
+ALTER TABLE "review_votes" ADD FOREIGN KEY ("review_id") REFERENCES "transaction_reviews" ("id");
+
+ALTER TABLE "review_votes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "review_reports" ADD FOREIGN KEY ("review_id") REFERENCES "transaction_reviews" ("id");
+
+ALTER TABLE "review_reports" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
 ALTER TABLE "invoice_per_shops" ADD FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id");
 
 ALTER TABLE "invoice_per_shops" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
@@ -610,4 +1122,52 @@ ALTER TABLE "refund_requests" ADD FOREIGN KEY ("transaction_id") REFERENCES "tra
 
 ALTER TABLE "refund_chats" ADD FOREIGN KEY ("sender_id") REFERENCES "users" ("id");
 
-ALTER TABLE "refund_chats" ADD FOREIGN KEY ("request_id") REFERENCES "refund_requests" ("id");
\ No newline at end of file
+ALTER TABLE "refund_chats" ADD FOREIGN KEY ("request_id") REFERENCES "refund_requests" ("id");
+
+ALTER TABLE "shop_members" ADD FOREIGN KEY ("shop_id") REFERENCES "shops" ("id");
+
+ALTER TABLE "shop_members" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "shop_members" ADD FOREIGN KEY ("invited_by_id") REFERENCES "users" ("id");
+
+ALTER TABLE "shop_members" ADD FOREIGN KEY ("revoked_by_id") REFERENCES "users" ("id");
+
+ALTER TABLE "chats" ADD FOREIGN KEY ("sent_by_id") REFERENCES "users" ("id");
+
+ALTER TABLE "invoice_status" ADD FOREIGN KEY ("actor_id") REFERENCES "users" ("id");
+
+ALTER TABLE "invoice_per_shops" ADD FOREIGN KEY ("released_by_id") REFERENCES "users" ("id");
+
+ALTER TABLE "user_two_factors" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "user_recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "user_sessions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "user_identities" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "user_data_exports" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "used_emails" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+INSERT INTO "admin_roles" ("name", "description") VALUES
+  ('super_admin', 'Super Admin'),
+  ('finance', 'Finance'),
+  ('moderator', 'Moderator'),
+  ('support', 'Support');
+
+INSERT INTO "admin_permissions" ("code", "description") VALUES
+  ('categories.manage', 'Create product categories'),
+  ('products.moderate', 'Review flagged products and hide discussions'),
+  ('orders.manage', 'Cancel orders'),
+  ('refunds.manage', 'Review and pay out refunds'),
+  ('marketplace.manage', 'Manage banners, marketplace vouchers and flash sales'),
+  ('admins.manage', 'Invite, change the role of and revoke admins'),
+  ('audit_logs.read', 'Read the admin audit log');
+
+INSERT INTO "admin_role_permissions" ("role_id", "permission_id")
+SELECT r."id", p."id" FROM "admin_roles" r, "admin_permissions" p WHERE
+  r."name" = 'super_admin' OR
+  (r."name" = 'finance' AND p."code" IN ('refunds.manage', 'marketplace.manage')) OR
+  (r."name" = 'moderator' AND p."code" IN ('categories.manage', 'products.moderate')) OR
+  (r."name" = 'support' AND p."code" IN ('orders.manage'));